@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/backfill"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/monitor"
+)
+
+// runBackfillAlerts implements `go-monitoring backfill-alerts [-dry-run]
+// [-format mbox|json] [-gap 6h] [-any-name] FILE...`: read archived alert
+// emails and write their alerts to the result store (DATABASE_URL or
+// STORE_PATH) as down checks and incidents. Names that match no configured
+// row (BaseEndpoints plus ENDPOINTS_FILE) are skipped unless -any-name is
+// set. Reruns add nothing new. With STORE_PATH, stop the server first: it
+// rewrites the file from memory on its next flush. Returns the process exit
+// code.
+func runBackfillAlerts(args []string) int {
+	fs := flag.NewFlagSet("backfill-alerts", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "parse and summarize only")
+	format := fs.String("format", "", "mbox or json (default: from the file extension)")
+	gap := fs.Duration("gap", 6*time.Hour, "alerts further apart than this start a new incident")
+	anyName := fs.Bool("any-name", false, "keep alerts for names that match no configured endpoint")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(logs.Stderr, "usage: go-monitoring backfill-alerts [-dry-run] [-format mbox|json] [-gap 6h] [-any-name] FILE...")
+		return 2
+	}
+
+	var emails []backfill.Email
+	for _, name := range fs.Args() {
+		f := *format
+		if f == "" {
+			f = backfill.FormatFor(name)
+		}
+		file, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintln(logs.Stderr, err)
+			return 1
+		}
+		batch, err := backfill.Parse(file, f)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(logs.Stderr, "%s: %v\n", name, err)
+			return 1
+		}
+		emails = append(emails, batch...)
+	}
+
+	var known func(string) bool
+	if !*anyName {
+		loadImportedEndpoints()
+		names := map[string]bool{}
+		for _, e := range monitor.ExpandForSolvers(monitor.BaseInputs(config.BaseEndpoints)) {
+			names[e.Name] = true
+		}
+		known = func(name string) bool { return names[name] }
+	}
+	alerts, skipped := backfill.Alerts(emails, known)
+	logs.Printf("%d emails, %d alerts, %d incidents\n", len(emails), len(alerts), len(backfill.Incidents(alerts, *gap)))
+	if len(skipped) > 0 {
+		var names []string
+		for n := range skipped {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Fprintf(logs.Stderr, "skipped %d alerts for unknown endpoint %q\n", skipped[n], n)
+		}
+	}
+	if *dryRun {
+		return 0
+	}
+
+	st := openStore()
+	if st == nil {
+		fmt.Fprintln(logs.Stderr, "no store to backfill: set DATABASE_URL or STORE_PATH")
+		return 2
+	}
+	sum, err := backfill.Apply(st, alerts, *gap)
+	if err != nil {
+		fmt.Fprintln(logs.Stderr, err)
+		return 1
+	}
+	logs.Printf("backfilled %d checks and %d incidents for %d endpoints\n", sum.Checks, sum.Incidents, sum.Endpoints)
+	return 0
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/discovery"
+	"go-monitoring/internal/importer"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/swapsize"
+)
+
+// runImport implements `go-monitoring import [-dry-run] [-format csv|json]
+// [-into PATH] FILE...`: validate every file as one batch, then upsert it
+// into the endpoints file (ENDPOINTS_FILE by default). A running instance
+// picks the file up on restart; POST /api/v1/endpoints/import applies a batch
+// live. "-" reads stdin. Returns the process exit code.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "validate only")
+	format := fs.String("format", "", "csv or json (default: from the file extension)")
+	into := fs.String("into", config.GetEndpointsFile(), "endpoints file to upsert into")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(logs.Stderr, "usage: go-monitoring import [-dry-run] [-format csv|json] [-into PATH] FILE...")
+		return 2
+	}
+
+	var rows []importer.Row
+	for _, name := range fs.Args() {
+		f := *format
+		if f == "" {
+			f = importer.FormatFor(name)
+		}
+		batch, err := parseImportFile(name, f)
+		if err != nil {
+			fmt.Fprintf(logs.Stderr, "%s: %v\n", name, err)
+			return 1
+		}
+		rows = append(rows, batch...)
+	}
+
+	swapsize.SetPoolInfo(poolSizingInfo)
+	suggested, err := importer.Prepare(rows)
+	if err != nil {
+		var verr *importer.ValidationError
+		if errors.As(err, &verr) {
+			for _, e := range verr.Rows {
+				fmt.Fprintf(logs.Stderr, "row %d (%s): %s\n", e.Row, e.Name, e.Message)
+			}
+			fmt.Fprintf(logs.Stderr, "%d invalid rows; nothing imported\n", len(verr.Rows))
+		} else {
+			fmt.Fprintln(logs.Stderr, err)
+		}
+		return 1
+	}
+	if suggested > 0 {
+		logs.Printf("%d swap amounts suggested from pool prices\n", suggested)
+	}
+	if *dryRun {
+		logs.Printf("%d endpoints valid\n", len(rows))
+		return 0
+	}
+	if *into == "" {
+		fmt.Fprintln(logs.Stderr, "no endpoints file: set ENDPOINTS_FILE or pass -into")
+		return 2
+	}
+
+	added, updated, err := importer.UpsertFile(*into, rows)
+	if err != nil {
+		fmt.Fprintln(logs.Stderr, err)
+		return 1
+	}
+	logs.Printf("%s: %d endpoints added, %d updated\n", *into, added, updated)
+	return 0
+}
+
+// poolSizingInfo feeds swapsize from the Balancer API pool metadata cache.
+func poolSizingInfo(network, pool string) (swapsize.PoolInfo, bool) {
+	meta, ok := discovery.PoolMetadataFor(network, pool)
+	return swapsize.PoolInfo{Type: meta.Type, TVLUSD: meta.TVLUSD, PricesUSD: meta.PricesUSD}, ok
+}
+
+func parseImportFile(name, format string) ([]importer.Row, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return importer.Parse(r, format)
+}
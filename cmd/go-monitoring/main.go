@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/handlers"
+	"go-monitoring/internal/apitoken"
+	"go-monitoring/internal/boosted"
+	"go-monitoring/internal/chaos"
+	"go-monitoring/internal/discovery"
+	"go-monitoring/internal/importer"
+	"go-monitoring/internal/leader"
+	"go-monitoring/internal/localdev"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/internal/poolwatch"
+	"go-monitoring/internal/shared"
+	"go-monitoring/internal/store"
+	"go-monitoring/internal/swapsize"
+	"go-monitoring/internal/worker"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/providers"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	// leaderElectionInterval is how often a follower retries the scheduler
+	// lock, bounding failover time.
+	leaderElectionInterval = 30 * time.Second
+	// followerSyncInterval is how often a follower reloads results from the
+	// shared store.
+	followerSyncInterval = time.Minute
+	// compactionInterval is how often the store archives and prunes history
+	// (HISTORY_RETENTION_DAYS, HISTORY_HOURLY_RETENTION_DAYS).
+	compactionInterval = 6 * time.Hour
+	// sourceScanInterval is how often provider source catalogs are scanned
+	// for new Balancer V3 source IDs.
+	sourceScanInterval = 24 * time.Hour
+	// exclusionCheckInterval is how often each provider is quoted with
+	// Balancer V3 excluded to check it honors its source filters.
+	exclusionCheckInterval = 24 * time.Hour
+	// poolWatchInterval is how often the factories in POOL_WATCH_FILE are
+	// scanned for new pools.
+	poolWatchInterval = 5 * time.Minute
+	// deliveryRetryInterval is how often notifications a channel failed to
+	// take are retried (each on its own backoff).
+	deliveryRetryInterval = 30 * time.Second
+)
+
+func main() {
+	// Scaffolding a provider edits source, not a running setup: it needs
+	// no settings.
+	if len(os.Args) > 1 && os.Args[1] == "new-provider" {
+		os.Exit(runNewProvider(os.Args[2:]))
+	}
+
+	// Load .env file if it exists
+	if err := godotenv.Load(); err != nil {
+		// It's okay if .env doesn't exist, just log it
+		logs.Println("No .env file found, using system environment variables")
+	}
+
+	// Parse and validate every setting once; the process reads this
+	// snapshot from here on (see config.Env and /api/v1/config).
+	if err := config.Load(); err != nil {
+		for _, e := range err.(config.EnvErrors) {
+			logs.Printf("%s[ERROR]%s invalid setting %v\n", config.ColorRed, config.ColorReset, e)
+		}
+		os.Exit(1)
+	}
+
+	// Keys, tokens and RPC URLs stay out of logs, pages and alerts, the
+	// subcommands' included.
+	registerSecrets()
+
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "soak" {
+		os.Exit(runSoak(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "token" {
+		os.Exit(runToken(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backfill-alerts" {
+		os.Exit(runBackfillAlerts(os.Args[2:]))
+	}
+
+	if config.GetDryRunEnabled() {
+		logs.Printf("%s[DRY RUN]%s Provider, RPC and email requests are disabled\n", config.ColorYellow, config.ColorReset)
+	}
+
+	// Fault injection (CHAOS_FAULTS) for staging and resilience tests.
+	injector, err := chaos.FromConfig()
+	if err != nil {
+		logs.Printf("%s[ERROR]%s invalid setting %v\n", config.ColorRed, config.ColorReset, err)
+		os.Exit(1)
+	}
+	if injector != nil {
+		chaos.Use(injector)
+		logs.Printf("%s[CHAOS]%s Injecting %v into %.0f%% of provider requests and checks\n",
+			config.ColorOrange, config.ColorReset, injector.Faults, injector.Rate*100)
+	}
+
+	// Endpoints imported from a spreadsheet or automation extend (or
+	// override, by name) the compiled-in BaseEndpoints.
+	loadImportedEndpoints()
+	// A profile (APP_ENV) may check only a subset of them.
+	applyProfileEndpoints()
+
+	// Local dev swaps the route solvers for fake in-process providers; parse
+	// again so per-solver settings (DELAY_FAKE_STEADY, ...) cover the fakes.
+	localDev := config.GetLocalDevEnabled()
+	if localDev {
+		localdev.UseFakeSolvers()
+		if err := config.Load(); err != nil {
+			logs.Printf("%s[ERROR]%s invalid setting %v\n", config.ColorRed, config.ColorReset, err)
+			os.Exit(1)
+		}
+	}
+
+	// Expand BaseEndpoints across every enabled route solver that supports
+	// the endpoint's network. Shared with the discovered test set builder so
+	// the network-support filter cannot drift between the two paths.
+	collector.SetEndpoints(monitor.ExpandForSolvers(monitor.BaseInputs(config.BaseEndpoints)))
+	if len(collector.GetEndpointsCopy()) == 0 {
+		logs.Printf("%s[WARN]%s no endpoints configured: %d BaseEndpoints, %d enabled route solvers; the monitor will idle\n",
+			config.ColorYellow, config.ColorReset, len(config.BaseEndpoints), len(config.GetEnabledRouteSolvers()))
+	}
+
+	// Initialize the provider registry
+	monitor.InitializeRegistry()
+
+	// Get check interval from environment variable in main thread
+	checkInterval := config.GetCheckInterval()
+	discoveryIntervalHours := config.GetDiscoveryIntervalHours()
+
+	// Register the discovered test set runner before starting discovery so the
+	// first refresh's results are exercised against the providers.
+	discovery.SetTestSetRunner(monitor.RunDiscoveredOnce)
+
+	// Fetch Balancer API pool names for the dashboard's group rows.
+	expectedPools := make([][2]string, 0, len(config.BaseEndpoints))
+	for _, base := range config.BaseEndpoints {
+		expectedPools = append(expectedPools, [2]string{base.Network, base.ExpectedPool})
+	}
+	if !localDev {
+		go discovery.WarmPoolMetadata(expectedPools)
+	}
+
+	// Routes to boosted pools are checked against the pools' wrapped tokens.
+	boosted.SetWrappedTokens(discovery.WrappedTokensFor)
+	// Imported endpoints are sized from the same pool metadata.
+	swapsize.SetPoolInfo(poolSizingInfo)
+
+	// Share the provider metadata cache and rate limiter between instances.
+	if url := config.GetRedisURL(); url != "" {
+		if r, err := shared.NewRedis(url); err != nil {
+			logs.Printf("%s[STARTUP]%s Redis unavailable, using in-memory cache and rate limiter: %v\n", config.ColorRed, config.ColorReset, err)
+		} else {
+			shared.Use(r)
+			logs.Printf("%s[STARTUP]%s sharing provider cache and rate limits through Redis\n", config.ColorGreen, config.ColorReset)
+		}
+	}
+
+	// Restore statuses and history from the last run when configured, and
+	// persist every result. The store is set first so rows renamed since
+	// then move their stored rows too.
+	if st := openStore(); st != nil {
+		monitor.SetStore(st)
+		if config.GetWarmStartEnabled() {
+			if snap, err := store.LoadSnapshot(st, time.Now()); err != nil {
+				logs.Printf("%s[STARTUP]%s warm start failed: %v\n", config.ColorRed, config.ColorReset, err)
+			} else {
+				n := store.Restore(snap)
+				logs.Printf("%s[STARTUP]%s warm-started %d endpoints (last saved result %s)\n", config.ColorGreen, config.ColorReset, n, snap.SavedAt.Format(time.RFC3339))
+			}
+		}
+		apitoken.Use(st.LoadAPITokens)
+		startLeaderElection(st)
+		if c, ok := st.(store.Compactor); ok {
+			retention := store.Retention{
+				Raw:    time.Duration(config.GetHistoryRetentionDays()) * 24 * time.Hour,
+				Hourly: time.Duration(config.GetHistoryHourlyRetentionDays()) * 24 * time.Hour,
+			}
+			go store.RunCompaction(c, retention, compactionInterval, monitor.IsLeader)
+		}
+	}
+	monitor.RecordDeploy(config.BuildVersion(), time.Now())
+	if localDev {
+		startLocalDev()
+	}
+	firstCycleDelay := config.GetFirstCycleDelay()
+
+	// A regional worker checks BaseEndpoints and reports them to the central
+	// instance; discovery, its test set and every alert stay with the central
+	// instance, which alerts when a region disagrees with it.
+	collectorURL := config.GetWorkerCollectorURL()
+	if collectorURL != "" {
+		region := config.GetWorkerRegion()
+		logs.Printf("%s[WORKER]%s running as region %s, reporting to %s; notifications off\n", config.ColorBlue, config.ColorReset, region, collectorURL)
+		notify.SetMuted(true)
+		monitor.SetCycleReporter(worker.NewClient(collectorURL, config.GetWorkerToken(), region).ReportCycle)
+	}
+
+	go monitor.MonitorAPIs(checkInterval, firstCycleDelay) // Start monitoring in the background
+	go monitor.RunScheduled(firstCycleDelay)               // Check groups with their own Schedule
+	go monitor.RunBlockRefresh()                           // Refresh on-chain prices on new blocks (<NETWORK>_WS_RPC_URL)
+	if collectorURL == "" && !localDev {
+		go discovery.Run(discoveryIntervalHours, firstCycleDelay) // Start Balancer V3 pool discovery
+		go monitor.RunSourceScan(sourceScanInterval)              // Watch provider catalogs for new Balancer sources
+		go monitor.RunExclusionCheck(exclusionCheckInterval)      // Check providers honor Balancer V3 exclude filters
+		go poolwatch.Run(poolWatchInterval)                       // Watch pool factories for new pools (POOL_WATCH_FILE)
+	}
+	go notify.RunDeliveryRetries(deliveryRetryInterval) // Retry notifications a channel failed to take
+	starting := "Service starting"
+	if p, ok := config.GetProfile(); ok {
+		starting += " (profile " + p.Name + ")"
+	}
+	notify.Send(notify.SeverityInfo, starting)
+
+	// Register HTTP handlers, each behind method, content type and path
+	// parameter validation
+	handlers.Register(http.DefaultServeMux)
+
+	closeOnShutdown()
+
+	srv := newHTTPServer(http.DefaultServeMux)
+	certFile, keyFile := config.GetTLSFiles()
+	if certFile != "" {
+		logs.Printf("Server running on https://localhost%s\n", srv.Addr)
+		err = srv.ListenAndServeTLS(certFile, keyFile)
+	} else {
+		logs.Printf("Server running on http://localhost%s\n", srv.Addr)
+		err = srv.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		logs.Printf("%s[ERROR]%s HTTP server: %v\n", config.ColorRed, config.ColorReset, err)
+		os.Exit(1)
+	}
+}
+
+// newHTTPServer returns the dashboard's server: PORT, the HTTP_* timeouts
+// so slow or idle clients can't hold connections open, and
+// HTTP_MAX_HEADER_BYTES.
+func newHTTPServer(handler http.Handler) *http.Server {
+	// gRPC clients of MonitoringService need HTTP/2; the server doesn't
+	// terminate TLS, so it has to accept HTTP/2 in cleartext (h2c).
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Server{
+		Protocols:         &protocols,
+		Addr:              config.GetHTTPAddr(),
+		Handler:           handler,
+		ReadHeaderTimeout: config.GetHTTPReadHeaderTimeout(),
+		ReadTimeout:       config.GetHTTPReadTimeout(),
+		WriteTimeout:      config.GetHTTPWriteTimeout(),
+		IdleTimeout:       config.GetHTTPIdleTimeout(),
+		MaxHeaderBytes:    config.GetHTTPMaxHeaderBytes(),
+	}
+}
+
+// closeOnShutdown closes the on-chain RPC clients and spools the
+// notifications awaiting a retry when the process is asked to stop (SIGINT,
+// SIGTERM), then exits.
+func closeOnShutdown() {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-stop
+		logs.Printf("%s[SHUTDOWN]%s %s: closing RPC clients\n", config.ColorYellow, config.ColorReset, sig)
+		providers.CloseClients()
+		notify.SpillDeliveries()
+		os.Exit(0)
+	}()
+}
+
+// loadImportedEndpoints merges ENDPOINTS_FILE into config.BaseEndpoints. A
+// file that fails to load or validate is skipped as a whole.
+func loadImportedEndpoints() {
+	path := config.GetEndpointsFile()
+	if path == "" {
+		return
+	}
+	rows, err := importer.LoadFile(path)
+	if err != nil {
+		logs.Printf("%s[STARTUP]%s ENDPOINTS_FILE ignored: %v\n", config.ColorRed, config.ColorReset, err)
+		return
+	}
+	var added, updated int
+	config.BaseEndpoints, added, updated = importer.Merge(config.BaseEndpoints, rows)
+	logs.Printf("%s[STARTUP]%s %s: %d imported endpoints added, %d overriding BaseEndpoints\n", config.ColorGreen, config.ColorReset, path, added, updated)
+}
+
+// startLocalDev serves the fake providers, registers them in place of the
+// real ones and seeds a week of history for rows without any (LOCAL_DEV).
+// Discovery, source scans, exclusion checks and pool watching, which call
+// real APIs, don't run.
+func startLocalDev() {
+	baseURL, err := localdev.Serve()
+	if err != nil {
+		logs.Printf("%s[LOCAL DEV]%s fake providers unavailable: %v\n", config.ColorRed, config.ColorReset, err)
+		return
+	}
+	localdev.Register(monitor.GlobalRegistry, baseURL)
+	n := localdev.Seed(collector.GetEndpointsCopy(), time.Now())
+	logs.Printf("%s[LOCAL DEV]%s fake providers at %s; seeded a week of history for %d endpoints\n", config.ColorYellow, config.ColorReset, baseURL, n)
+}
+
+// applyProfileEndpoints narrows config.BaseEndpoints to the active profile's
+// endpoint selector.
+func applyProfileEndpoints() {
+	p, ok := config.GetProfile()
+	if !ok {
+		return
+	}
+	total := len(config.BaseEndpoints)
+	config.BaseEndpoints = p.Endpoints.Select(config.BaseEndpoints)
+	logs.Printf("%s[STARTUP]%s profile %s: checking %d of %d endpoints with %d route solvers\n", config.ColorGreen, config.ColorReset,
+		p.Name, len(config.BaseEndpoints), total, len(config.GetEnabledRouteSolvers()))
+}
+
+// openStore returns the configured result store: SQLite when DATABASE_URL
+// is a sqlite: URL, Postgres when it is set otherwise, else the STORE_PATH
+// JSON file, else nil (no persistence).
+func openStore() store.Store {
+	if path, ok := config.GetSQLitePath(); ok {
+		st, err := store.NewSQLiteStore(path)
+		if err != nil {
+			logs.Printf("%s[STARTUP]%s SQLite store unavailable, results will not be persisted: %v\n", config.ColorRed, config.ColorReset, err)
+			return nil
+		}
+		logs.Printf("%s[STARTUP]%s persisting results to SQLite %s\n", config.ColorGreen, config.ColorReset, path)
+		return st
+	}
+	if url := config.GetDatabaseURL(); url != "" {
+		st, err := store.NewPostgresStore(url)
+		if err != nil {
+			logs.Printf("%s[STARTUP]%s Postgres store unavailable, results will not be persisted: %v\n", config.ColorRed, config.ColorReset, err)
+			return nil
+		}
+		logs.Printf("%s[STARTUP]%s persisting results to Postgres\n", config.ColorGreen, config.ColorReset)
+		return st
+	}
+	if path := config.GetStorePath(); path != "" {
+		logs.Printf("%s[STARTUP]%s persisting results to %s\n", config.ColorGreen, config.ColorReset, path)
+		return store.NewFileStore(path)
+	}
+	return nil
+}
+
+// startLeaderElection makes this instance a candidate for the scheduler lock
+// when LEADER_ELECTION is set. The first attempt runs before the loops start
+// so a lone instance checks immediately; followers sync results from the
+// shared store for the dashboard.
+func startLeaderElection(st store.Store) {
+	if !config.GetLeaderElectionEnabled() {
+		return
+	}
+	pg, ok := st.(*store.PostgresStore)
+	if !ok {
+		logs.Printf("%s[STARTUP]%s LEADER_ELECTION needs a Postgres DATABASE_URL; running as the only scheduler\n", config.ColorYellow, config.ColorReset)
+		return
+	}
+	e := leader.NewPostgresElector(pg.DB())
+	e.Step(context.Background())
+	monitor.SetElector(e)
+	go e.Run(context.Background(), leaderElectionInterval)
+	go monitor.SyncFromStore(followerSyncInterval)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/providers/skeleton"
+)
+
+// runNewProvider implements `go-monitoring new-provider -base-url URL
+// -networks 1,8453 [-display NAME] [-api-key-env VAR] [-post] [-source-id ID]
+// TYPE`: scaffold a route solver's handler, tests and registrations (see
+// package skeleton). Run it from the repository root. Returns the process
+// exit code.
+func runNewProvider(args []string) int {
+	usage := "usage: go-monitoring new-provider -base-url URL -networks 1,8453 [-display NAME] [-api-key-env VAR] [-post] [-source-id ID] TYPE"
+	fs := flag.NewFlagSet("new-provider", flag.ContinueOnError)
+	display := fs.String("display", "", "name on the dashboard (default: TYPE capitalized)")
+	baseURL := fs.String("base-url", "", "the provider's quote endpoint, https")
+	apiKeyEnv := fs.String("api-key-env", "", "env var holding the provider's API key, if it needs one")
+	post := fs.Bool("post", false, "send the quote request as a JSON body instead of query parameters")
+	networks := fs.String("networks", "", "comma-separated chain IDs the provider serves")
+	sourceID := fs.String("source-id", "", "the provider's name for Balancer V3 liquidity (default BalancerV3)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(logs.Stderr, usage)
+		return 2
+	}
+
+	opts := skeleton.Options{
+		Type:         fs.Arg(0),
+		Display:      *display,
+		BaseURL:      *baseURL,
+		APIKeyEnvVar: *apiKeyEnv,
+		POST:         *post,
+		SourceID:     *sourceID,
+	}
+	for _, n := range strings.Split(*networks, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			opts.Networks = append(opts.Networks, n)
+		}
+	}
+	written, err := skeleton.Write(".", opts)
+	if err != nil {
+		fmt.Fprintf(logs.Stderr, "new-provider: %v\n", err)
+		return 1
+	}
+	for _, path := range written {
+		logs.Println("wrote", path)
+	}
+	logs.Printf("Next: fill in the TODO(new-provider) comments in monitoring/providers/%s_handler.go, then run go test ./monitoring/providers/...\n", opts.Type)
+	return 0
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/redact"
+)
+
+// registerSecrets registers the configured keys, tokens and URLs as redact
+// secrets, so the logs (logs.Stdout, logs.Stderr), pages and alerts mask
+// them as well as credential-shaped values. `token create` prints its new
+// secret to the raw stdout on purpose.
+func registerSecrets() {
+	redact.SetSecrets(config.Current().SecretValues(os.Environ())...)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/boosted"
+	"go-monitoring/internal/discovery"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/internal/soak"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/providers"
+)
+
+// runSoak implements `go-monitoring soak -solver TYPE [-endpoint NAME]
+// [-duration 1h] [-interval 5s] [-market]`: check one route solver's row of
+// a BaseEndpoint over and over, then print its latency distribution, failure
+// classes and rate limiting with a suggested DELAY_<SOLVER>. The solver need
+// not be enabled; alerts are not sent. Interrupt to stop early and still get
+// the report. Returns the process exit code.
+func runSoak(args []string) int {
+	fs := flag.NewFlagSet("soak", flag.ContinueOnError)
+	solverType := fs.String("solver", "", "route solver type, e.g. kyberswap")
+	name := fs.String("endpoint", "", "BaseEndpoint name (default: the first on a network the solver supports)")
+	duration := fs.Duration("duration", time.Hour, "how long to soak")
+	interval := fs.Duration("interval", 5*time.Second, "wait after each check")
+	market := fs.Bool("market", false, "soak the market price call instead of the Balancer-only one")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *solverType == "" || *duration <= 0 || *interval < 0 {
+		fmt.Fprintln(logs.Stderr, "usage: go-monitoring soak -solver TYPE [-endpoint NAME] [-duration 1h] [-interval 5s] [-market]")
+		return 2
+	}
+
+	loadImportedEndpoints()
+	endpoint, err := soakEndpoint(*solverType, *name)
+	if err != nil {
+		fmt.Fprintln(logs.Stderr, err)
+		return 2
+	}
+	monitor.InitializeRegistry()
+	// Routes to boosted pools are checked against the pools' wrapped tokens,
+	// as in the monitor.
+	boosted.SetWrappedTokens(discovery.WrappedTokensFor)
+	balancerOnly := !*market
+	options := &providers.CheckOptions{IsBalancerSourceOnly: &balancerOnly}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	logs.Printf("%s[SOAK]%s %s every %s for %s\n", config.ColorBlue, config.ColorReset, endpoint.Name, *interval, *duration)
+	samples := soak.Run(ctx, *duration, *interval, func() soak.Sample {
+		e := endpoint
+		notify.HoldGroup(e.BaseName)
+		defer notify.DropGroup(e.BaseName)
+		started := time.Now()
+		res := monitor.GlobalRegistry.Check(&e, options)
+		s := soak.Sample{At: started, Latency: time.Since(started), Status: res.Status, Message: res.Message}
+		if s.Status != "up" {
+			s.Class = res.ErrorClass
+			if s.Class == "" {
+				s.Class = "other"
+			}
+		}
+		return s
+	})
+	logs.Println()
+	soak.Summarize(*solverType, endpoint.Name, *interval, samples).Write(logs.Stdout)
+	return 0
+}
+
+// soakEndpoint builds the solver's row of the named BaseEndpoint, or of the
+// first one on a network the solver supports.
+func soakEndpoint(solverType, name string) (collector.Endpoint, error) {
+	var solver *config.RouteSolver
+	for i := range config.RouteSolvers {
+		if config.RouteSolvers[i].Type == solverType {
+			solver = &config.RouteSolvers[i]
+		}
+	}
+	if solver == nil {
+		return collector.Endpoint{}, fmt.Errorf("unknown route solver %q", solverType)
+	}
+	for _, in := range monitor.BaseInputs(config.BaseEndpoints) {
+		if name != "" && in.BaseName != name {
+			continue
+		}
+		if e, ok := monitor.ExpandForSolver(in, *solver); ok {
+			return e, nil
+		}
+		if name != "" {
+			return collector.Endpoint{}, fmt.Errorf("%s does not support network %s of %s", solver.Name, in.Network, name)
+		}
+	}
+	if name != "" {
+		return collector.Endpoint{}, fmt.Errorf("no BaseEndpoint named %q", name)
+	}
+	return collector.Endpoint{}, fmt.Errorf("no BaseEndpoint on a network %s supports", solver.Name)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-monitoring/internal/apitoken"
+	"go-monitoring/internal/logs"
+)
+
+// runToken implements `go-monitoring token create -name NAME -scopes
+// read,trigger-check`, `token list` and `token revoke ID`: API tokens in the
+// result store (DATABASE_URL or STORE_PATH), which a running server picks up
+// within a minute. create prints the secret once; only its hash is stored.
+// Returns the process exit code.
+func runToken(args []string) int {
+	usage := "usage: go-monitoring token create -name NAME -scopes read[,trigger-check][,admin] | list | revoke ID"
+	if len(args) == 0 {
+		fmt.Fprintln(logs.Stderr, usage)
+		return 2
+	}
+	st := openStore()
+	if st == nil {
+		fmt.Fprintln(logs.Stderr, "no store for tokens: set DATABASE_URL or STORE_PATH")
+		return 2
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("token create", flag.ContinueOnError)
+		name := fs.String("name", "", "what the token is for, e.g. nightly-ci")
+		scopeList := fs.String("scopes", string(apitoken.Read), "comma-separated: read, trigger-check, admin")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if *name == "" {
+			fmt.Fprintln(logs.Stderr, "token create: -name is required")
+			return 2
+		}
+		scopes, err := apitoken.ParseScopes(*scopeList)
+		if err != nil {
+			fmt.Fprintf(logs.Stderr, "token create: %v\n", err)
+			return 2
+		}
+		t, secret, err := apitoken.New(*name, scopes, time.Now())
+		if err == nil {
+			err = st.SaveAPIToken(t)
+		}
+		if err != nil {
+			fmt.Fprintf(logs.Stderr, "token create: %v\n", err)
+			return 1
+		}
+		fmt.Printf("token %s (%s, %s); send it as \"Authorization: Bearer <token>\". It is not shown again:\n%s\n",
+			t.ID, t.Name, strings.Join(t.Scopes, ","), secret)
+		return 0
+	case "list":
+		tokens, err := st.LoadAPITokens()
+		if err != nil {
+			fmt.Fprintf(logs.Stderr, "token list: %v\n", err)
+			return 1
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tSCOPES\tCREATED")
+		for _, t := range tokens {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.ID, t.Name, strings.Join(t.Scopes, ","), t.CreatedAt.Format(time.RFC3339))
+		}
+		tw.Flush()
+		return 0
+	case "revoke":
+		if len(args) != 2 {
+			fmt.Fprintln(logs.Stderr, usage)
+			return 2
+		}
+		ok, err := st.DeleteAPIToken(args[1])
+		if err != nil {
+			fmt.Fprintf(logs.Stderr, "token revoke: %v\n", err)
+			return 1
+		}
+		if !ok {
+			fmt.Fprintf(logs.Stderr, "token revoke: no token %s\n", args[1])
+			return 1
+		}
+		fmt.Printf("token %s revoked; a running server stops accepting it within a minute\n", args[1])
+		return 0
+	}
+	fmt.Fprintln(logs.Stderr, usage)
+	return 2
+}
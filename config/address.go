@@ -0,0 +1,34 @@
+package config
+
+import "strings"
+
+// NormalizeAddress returns an EVM address's comparison form: trimmed and
+// lowercase, with the 0x prefix added to a bare 40-digit hex address.
+// Config, the Balancer API and the providers mix lowercase and EIP-55
+// checksummed forms; pool and token comparisons and keys go through
+// NormalizeAddress or SameAddress so a case difference never reads as a pool
+// missing from a route.
+func NormalizeAddress(a string) string {
+	a = strings.ToLower(strings.TrimSpace(a))
+	if len(a) == 40 && isLowerHex(a) {
+		return "0x" + a
+	}
+	return a
+}
+
+// SameAddress reports whether a and b are the same address, ignoring case.
+// An empty address is the same as nothing.
+func SameAddress(a, b string) bool {
+	a = NormalizeAddress(a)
+	return a != "" && a == NormalizeAddress(b)
+}
+
+// isLowerHex reports whether s is all lowercase hex digits.
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,25 @@
+package config
+
+import "testing"
+
+func TestNormalizeAddress(t *testing.T) {
+	for in, want := range map[string]string{
+		"0x85B2b559bC2D21104C4DEFdd6EFcA8A20343361D":   "0x85b2b559bc2d21104c4defdd6efca8a20343361d",
+		" 0x85b2b559bc2d21104c4defdd6efca8a20343361d ": "0x85b2b559bc2d21104c4defdd6efca8a20343361d",
+		"0X85B2B559BC2D21104C4DEFDD6EFCA8A20343361D":   "0x85b2b559bc2d21104c4defdd6efca8a20343361d",
+		"85B2b559bC2D21104C4DEFdd6EFcA8A20343361D":     "0x85b2b559bc2d21104c4defdd6efca8a20343361d",
+		"0xPool": "0xpool",
+		"":       "",
+	} {
+		if got := NormalizeAddress(in); got != want {
+			t.Errorf("NormalizeAddress(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if !SameAddress("0x85B2b559bC2D21104C4DEFdd6EFcA8A20343361D", "0x85b2b559bc2d21104c4defdd6efca8a20343361d") {
+		t.Error("checksummed and lowercase forms differ")
+	}
+	if SameAddress("", "") || SameAddress("0xabc", "0xabd") {
+		t.Error("different addresses are the same")
+	}
+}
@@ -1,7 +1,7 @@
 package config
 
 import (
-	"os"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -77,42 +77,71 @@ func BalancerAPIChain(network string) string {
 	}
 }
 
+// GetCheckInterval returns the BaseEndpoints check interval from
+// CHECK_INTERVAL_HOURS: whole hours, or a Go duration such as "15m" for a
+// sub-hour cadence. Defaults to 1h. Groups with a Schedule keep their own.
+func GetCheckInterval() time.Duration {
+	return Current().CheckInterval
+}
+
+// GetDownRecheckInterval returns how long after a failing check a down
+// BaseEndpoints row is checked again, from DOWN_RECHECK_INTERVAL (a Go
+// duration). The wait doubles after each failed recheck. Defaults to 5m; 0
+// disables rechecks.
+func GetDownRecheckInterval() time.Duration {
+	return Current().DownRecheckInterval
+}
+
+// GetManualCheckInterval returns the shortest time between two manual checks
+// of one endpoint (Check Now, TriggerCheck), from MANUAL_CHECK_INTERVAL (a
+// Go duration). Defaults to 30s; 0 disables the throttle.
+func GetManualCheckInterval() time.Duration {
+	return Current().ManualCheckInterval
+}
+
+// GetDownRecheckMaxInterval returns the longest wait between rechecks of a
+// down row, from DOWN_RECHECK_MAX_INTERVAL. Defaults to 30m.
+func GetDownRecheckMaxInterval() time.Duration {
+	return Current().DownRecheckMaxInterval
+}
+
+// GetDownRecheckMaxRows returns how many down rows are rechecked at most per
+// pass, from DOWN_RECHECK_MAX_ROWS. Defaults to 10.
+func GetDownRecheckMaxRows() int {
+	return Current().DownRecheckMaxRows
+}
+
 // GetDiscoveryIntervalHours returns the discovery interval in hours from the
 // DISCOVERY_INTERVAL_HOURS environment variable. Defaults to 24 if unset or invalid.
 func GetDiscoveryIntervalHours() int {
-	envValue := os.Getenv("DISCOVERY_INTERVAL_HOURS")
-	if envValue == "" {
-		return 24
-	}
-
-	interval, err := strconv.Atoi(envValue)
-	if err != nil || interval <= 0 {
-		return 24
-	}
-
-	return interval
+	return Current().DiscoveryIntervalHours
 }
 
 // GetDiscoveryTestPoolsPerGroup returns the maximum number of pools to select
 // per (PoolType, HookType) group when building the daily test set, from the
 // DISCOVERY_TEST_POOLS_PER_GROUP environment variable. Defaults to 1.
 func GetDiscoveryTestPoolsPerGroup() int {
-	envValue := os.Getenv("DISCOVERY_TEST_POOLS_PER_GROUP")
-	if envValue == "" {
-		return 1
-	}
+	return Current().DiscoveryTestPoolsPerGroup
+}
 
-	n, err := strconv.Atoi(envValue)
-	if err != nil || n <= 0 {
-		return 1
-	}
+// GetHookTriggerEnabled reports whether HOOK_TRIGGER is set: the daily test
+// set adds a row per StableSurge pool sized to trigger the surge fee, and its
+// Balancer-only quotes are checked against the on-chain Router query.
+func GetHookTriggerEnabled() bool {
+	return Current().HookTrigger
+}
 
-	return n
+// GetHookQuoteToleranceBps returns how far, in basis points, a provider's
+// quote on a hook-triggering row may differ from the on-chain Router query,
+// from HOOK_QUOTE_TOLERANCE_BPS. Defaults to 10.
+func GetHookQuoteToleranceBps() float64 {
+	return Current().HookQuoteToleranceBps
 }
 
 // BaseEndpoint represents the common configuration for an endpoint
 type BaseEndpoint struct {
 	Name             string
+	ID               string // optional stable identity of the row, kept across renames; see ValidEndpointID
 	Network          string
 	TokenIn          string
 	TokenOut         string
@@ -121,6 +150,23 @@ type BaseEndpoint struct {
 	ExpectedPool     string
 	SwapAmount       string
 	ExpectedNoHops   int
+	Rules            []EndpointRule // optional assertions, see EndpointRule
+	Labels           Labels         // optional tags, see Labels
+	Schedule         string         // optional own check cadence, see ParseSchedule
+}
+
+// ValidEndpointID reports whether id can be a BaseEndpoint.ID: 1 to 64
+// lowercase letters, digits, '-' and '_', so it needs no escaping in a URL.
+func ValidEndpointID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
 }
 
 // RouteSolver represents a specific route solver configuration
@@ -133,42 +179,449 @@ type RouteSolver struct {
 // GetEmailNotificationsEnabled checks if email notifications should be enabled
 // based on environment variables at runtime
 func GetEmailNotificationsEnabled() bool {
-	envValue := os.Getenv("EMAIL_NOTIFICATIONS")
-	if envValue == "" {
-		return false // Default to false if not set
+	return Current().EmailNotifications
+}
+
+// GetEmailMinSeverity returns the lowest alert severity emailed, from
+// EMAIL_MIN_SEVERITY ("info", "warning" or "critical"). Empty sends every
+// alert.
+func GetEmailMinSeverity() string {
+	return Current().EmailMinSeverity
+}
+
+// GetResendAPIKey returns the Resend API key emails are sent with, from
+// RESEND_API_KEY.
+func GetResendAPIKey() string {
+	return Current().ResendAPIKey
+}
+
+// GetSlackWebhookURL returns the Slack incoming webhook for alerts from
+// SLACK_WEBHOOK_URL. Empty disables Slack.
+func GetSlackWebhookURL() string {
+	return Current().SlackWebhookURL
+}
+
+// GetSlackMinSeverity returns the lowest alert severity posted to Slack, from
+// SLACK_MIN_SEVERITY. Empty sends every alert.
+func GetSlackMinSeverity() string {
+	return Current().SlackMinSeverity
+}
+
+// GetQuietHours returns the daily quiet window from QUIET_HOURS, e.g.
+// "22:00-07:00". Non-critical alerts raised inside it are sent as one digest
+// when it ends. Empty disables quiet hours.
+func GetQuietHours() string {
+	return Current().QuietHours
+}
+
+// GetQuietHoursTimezone returns the IANA time zone QUIET_HOURS is in, from
+// QUIET_HOURS_TZ (e.g. "Europe/London"). Empty means UTC.
+func GetQuietHoursTimezone() string {
+	return Current().QuietHoursTZ
+}
+
+// GetAlertStorm returns the notification cap from ALERT_STORM_MAX and the
+// window it counts over from ALERT_STORM_WINDOW (default 20 per 10m).
+// Notifications beyond the cap are rolled up into one summary; a max of 0
+// disables the cap.
+func GetAlertStorm() (max int, window time.Duration) {
+	e := Current()
+	return e.AlertStormMax, e.AlertStormWindow
+}
+
+// GetPublicURL returns the externally reachable base URL of the dashboard from
+// PUBLIC_URL (e.g. https://go-monitoring.fly.dev), without a trailing slash.
+// Empty when unset; alerts then omit dashboard links.
+func GetPublicURL() string {
+	return strings.TrimRight(Current().PublicURL, "/")
+}
+
+// GetAlertHintsFile returns the path of an optional JSON hints table from
+// ALERT_HINTS_FILE. Empty when unset; the built-in table is used.
+func GetAlertHintsFile() string {
+	return Current().AlertHintsFile
+}
+
+// GetNotifySpoolFile returns the path notifications awaiting a retry spill
+// to, from NOTIFY_SPOOL_FILE. Empty when unset; the retry queue is then in
+// memory only and drops its oldest entries when full.
+func GetNotifySpoolFile() string {
+	return Current().NotifySpoolFile
+}
+
+// GetMessagesFile returns the path of an optional JSON message catalog from
+// MESSAGES_FILE. Empty when unset; the built-in catalog is used.
+func GetMessagesFile() string {
+	return Current().MessagesFile
+}
+
+// GetSourceIDsFile returns the path of an optional JSON table of Balancer
+// source identifiers from SOURCE_IDS_FILE, for when a provider renames a
+// source. Empty when unset; the built-in table is used.
+func GetSourceIDsFile() string {
+	return Current().SourceIDsFile
+}
+
+// GetRoutersFile returns the path of an optional JSON table of Balancer V3
+// Router and BatchRouter deployments from ROUTERS_FILE, for new router
+// versions. Empty when unset; the built-in table is used.
+func GetRoutersFile() string {
+	return Current().RoutersFile
+}
+
+// GetSpendersFile returns the path of an optional JSON table of known-good
+// provider spender addresses from SPENDERS_FILE, added to the built-in
+// table. Empty when unset.
+func GetSpendersFile() string {
+	return Current().SpendersFile
+}
+
+// GetPoolWatchFile returns the path of the JSON pool creation watch settings
+// (factories to watch and filters for new pools) from POOL_WATCH_FILE. Empty
+// when unset; no factories are watched.
+func GetPoolWatchFile() string {
+	return Current().PoolWatchFile
+}
+
+// GetAlertRoutesFile returns the path of an optional JSON table of alert
+// routes (endpoint label selectors and extra destinations) from
+// ALERT_ROUTES_FILE. Empty when unset; alerts go to the default channels only.
+func GetAlertRoutesFile() string {
+	return Current().AlertRoutesFile
+}
+
+// GetJiraBaseURL returns the Jira site escalation tickets are created on,
+// from JIRA_BASE_URL, without a trailing slash. Empty disables tickets.
+func GetJiraBaseURL() string {
+	return strings.TrimRight(Current().JiraBaseURL, "/")
+}
+
+// GetJiraCredentials returns the account (JIRA_EMAIL) and API token
+// (JIRA_API_TOKEN) escalation tickets are created with.
+func GetJiraCredentials() (email, token string) {
+	return Current().JiraEmail, Current().JiraAPIToken
+}
+
+// GetJiraProject returns the key of the project escalation tickets are
+// created in, from JIRA_PROJECT. Empty disables tickets.
+func GetJiraProject() string {
+	return Current().JiraProject
+}
+
+// GetJiraIssueType returns the issue type of escalation tickets, from
+// JIRA_ISSUE_TYPE. Defaults to Task.
+func GetJiraIssueType() string {
+	return Current().JiraIssueType
+}
+
+// GetJiraRulesFile returns the path of an optional JSON table of rules that
+// escalate failures to Jira automatically, from JIRA_RULES_FILE. Empty when
+// unset; tickets are only created by hand.
+func GetJiraRulesFile() string {
+	return Current().JiraRulesFile
+}
+
+// GetMetricLabelKeys returns the endpoint label keys exported as metric
+// labels, from METRIC_LABEL_KEYS (comma-separated, e.g. "team,priority").
+// Invalid keys are skipped. Empty when unset.
+func GetMetricLabelKeys() []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, k := range strings.Split(Current().MetricLabelKeys, ",") {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if ValidLabelKey(k) && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
 	}
+	return keys
+}
 
-	// Convert to lowercase for case-insensitive comparison
-	envValue = strings.ToLower(envValue)
+// ChaosSettings configure fault injection (see package chaos).
+type ChaosSettings struct {
+	Faults     []string
+	Rate       float64
+	MaxLatency time.Duration
+	Solvers    []string
+}
 
-	// Check for various "true" values
-	switch envValue {
-	case "true", "1", "yes", "on":
-		return true
-	default:
-		return false
+// GetChaos returns the fault injection settings: CHAOS_FAULTS and
+// CHAOS_SOLVERS split on commas, CHAOS_RATE and CHAOS_LATENCY. Faults is
+// empty when injection is off.
+func GetChaos() ChaosSettings {
+	e := Current()
+	return ChaosSettings{
+		Faults:     splitList(e.ChaosFaults),
+		Rate:       e.ChaosRate,
+		MaxLatency: e.ChaosLatency,
+		Solvers:    splitList(e.ChaosSolvers),
 	}
 }
 
-// getRouteSolverEnabled checks if a specific route solver should be enabled
-// based on environment variables. Returns true by default if no env var is found.
-func getRouteSolverEnabled(solverType string) bool {
-	envVarName := "DISABLE_" + strings.ToUpper(solverType)
-	envValue := os.Getenv(envVarName)
-	if envValue == "" {
-		return true // Default to enabled if no env var is found
+// GetBalancerAPIURL returns BALANCER_API_URL, the Balancer API endpoint
+// the balancer_sor provider queries.
+func GetBalancerAPIURL() string {
+	return Current().BalancerAPIURL
+}
+
+// GetBalancerAPIFallbackURLs returns BALANCER_API_FALLBACK_URLS split on
+// commas: the endpoints a failed Balancer API request is resent to, in
+// order.
+func GetBalancerAPIFallbackURLs() []string {
+	return splitList(Current().BalancerAPIFallbackURLs)
+}
+
+// GetSORBatchSize returns SOR_BATCH_SIZE, the most Balancer SOR queries
+// batched into one request; below 2 disables batching.
+func GetSORBatchSize() int {
+	return Current().SORBatchSize
+}
+
+// splitList splits a comma-separated setting, dropping blank entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
 	}
+	return out
+}
+
+// GetPoolMigrationAutoApply reports whether POOL_MIGRATION_AUTO_APPLY is set.
+// When on, a detected pool migration rewrites the running BaseEndpoints'
+// ExpectedPool; otherwise it is only suggested on the dashboard and by email.
+func GetPoolMigrationAutoApply() bool {
+	return Current().PoolMigrationAutoApply
+}
 
-	// Convert to lowercase for case-insensitive comparison
-	envValue = strings.ToLower(envValue)
+// GetPriceImpactAlertBps returns the provider-reported price impact, in basis
+// points, above which an endpoint alert is sent, from PRICE_IMPACT_ALERT_BPS.
+// Defaults to 100 (1%) for the configured swap size; 0 disables the alert.
+func GetPriceImpactAlertBps() float64 {
+	return Current().PriceImpactAlertBps
+}
 
-	// Check for various "true" values that would disable the solver
-	switch envValue {
-	case "true", "1", "yes", "on", "disable":
-		return false
-	default:
-		return true
+// GetBalancerRankAlertTopN returns how high Balancer V3 must rank among the
+// per-source quotes of a market price response (Paraswap, OpenOcean) before
+// an endpoint alert is sent, from BALANCER_RANK_ALERT_TOP_N. Defaults to 3;
+// 0 disables the alert.
+func GetBalancerRankAlertTopN() int {
+	return Current().BalancerRankAlertTopN
+}
+
+// GetQuoteSpreadAlertBps returns how far, in basis points, the quote spread
+// (market price over the Balancer-only quote) must widen beyond its earlier
+// level before an endpoint alert is sent, from QUOTE_SPREAD_ALERT_BPS.
+// Defaults to 25; 0 disables the alert.
+func GetQuoteSpreadAlertBps() float64 {
+	return Current().QuoteSpreadAlertBps
+}
+
+// GetQuoteSpreadAlertChecks returns over how many consecutive checks the
+// quote spread must stay widened before an endpoint alert is sent, from
+// QUOTE_SPREAD_ALERT_CHECKS. Defaults to 6.
+func GetQuoteSpreadAlertChecks() int {
+	return Current().QuoteSpreadAlertChecks
+}
+
+// GetStorePath returns the JSON state file from STORE_PATH (e.g. a file on a
+// Fly volume). Empty disables persistence.
+func GetStorePath() string {
+	return Current().StorePath
+}
+
+// GetHistoryRetentionDays returns how many days of raw check results the
+// store keeps from HISTORY_RETENTION_DAYS before rolling them up into hourly
+// aggregates. Defaults to 30; values below 7 (the dashboard's history
+// window) are raised to 7.
+func GetHistoryRetentionDays() int {
+	if days := Current().HistoryRetentionDays; days > 7 {
+		return days
 	}
+	return 7
+}
+
+// GetHistoryHourlyRetentionDays returns how many days of hourly aggregates
+// and resolved incidents the store keeps, from HISTORY_HOURLY_RETENTION_DAYS.
+// Defaults to 365.
+func GetHistoryHourlyRetentionDays() int {
+	return Current().HistoryHourlyRetentionDays
+}
+
+// GetDatabaseURL returns the Postgres URL from DATABASE_URL (set by
+// `fly postgres attach`). When set it takes precedence over STORE_PATH.
+func GetDatabaseURL() string {
+	return Current().DatabaseURL
+}
+
+// GetSQLitePath returns the database file of a sqlite: DATABASE_URL
+// (sqlite:/data/monitor.db or sqlite:///data/monitor.db), and false for any
+// other URL.
+func GetSQLitePath() (string, bool) {
+	rest, ok := strings.CutPrefix(Current().DatabaseURL, "sqlite:")
+	if !ok {
+		return "", false
+	}
+	if path, ok := strings.CutPrefix(rest, "//"); ok {
+		rest = path
+	}
+	return rest, rest != ""
+}
+
+// GetWarmStartEnabled reports whether WARM_START is set: restore endpoint
+// statuses and history from the store at startup.
+func GetWarmStartEnabled() bool {
+	return Current().WarmStart
+}
+
+// GetRedisURL returns the Redis URL from REDIS_URL. When set, the provider
+// metadata cache and rate limiter are shared through Redis.
+func GetRedisURL() string {
+	return Current().RedisURL
+}
+
+// GetLeaderElectionEnabled reports whether LEADER_ELECTION is set: with
+// several replicas sharing DATABASE_URL, only the holder of a Postgres
+// advisory lock runs the check loops.
+func GetLeaderElectionEnabled() bool {
+	return Current().LeaderElection
+}
+
+// GetWorkerCollectorURL returns the central instance to report results to
+// from WORKER_COLLECTOR_URL (e.g. http://go-monitoring.internal:8080). When
+// set this instance runs as a regional check worker.
+func GetWorkerCollectorURL() string {
+	return strings.TrimRight(Current().WorkerCollectorURL, "/")
+}
+
+// GetWorkerToken returns the shared secret workers send and the central
+// instance requires, from WORKER_TOKEN. Empty on the central instance
+// disables worker reports.
+func GetWorkerToken() string {
+	return Current().WorkerToken
+}
+
+// GetWorkerRegion returns the region a worker reports as: WORKER_REGION, else
+// FLY_REGION (set by Fly), else "local".
+func GetWorkerRegion() string {
+	e := Current()
+	if e.WorkerRegion != "" {
+		return e.WorkerRegion
+	}
+	if e.FlyRegion != "" {
+		return e.FlyRegion
+	}
+	return "local"
+}
+
+// GetEndpointsFile returns the path of the imported endpoints file from
+// ENDPOINTS_FILE: a JSON batch merged over BaseEndpoints at startup and
+// updated by imports. Empty disables it.
+func GetEndpointsFile() string {
+	return Current().EndpointsFile
+}
+
+// GetAdminToken returns the bearer token required by write APIs such as the
+// endpoint import, from ADMIN_TOKEN. Empty disables those APIs.
+func GetAdminToken() string {
+	return Current().AdminToken
+}
+
+// GetAPIRequireToken reports whether API_REQUIRE_TOKEN is set: the JSON
+// and RPC APIs and manual checks need a scoped API token or ADMIN_TOKEN.
+func GetAPIRequireToken() bool {
+	return Current().APIRequireToken
+}
+
+// GetAccessLogEnabled reports whether ACCESS_LOG is on (the default): each
+// HTTP request is logged with its status, duration and client IP.
+func GetAccessLogEnabled() bool {
+	return Current().AccessLog
+}
+
+// GetTrustProxyHeaders reports whether TRUST_PROXY_HEADERS is set: the
+// client IP of a request is read from the proxy's headers rather than the
+// connection.
+func GetTrustProxyHeaders() bool {
+	return Current().TrustProxyHeaders
+}
+
+// GetHTTPAddr returns the address the HTTP server listens on, ":" and PORT
+// (default 8080).
+func GetHTTPAddr() string {
+	return ":" + strconv.Itoa(Current().Port)
+}
+
+// GetHTTPReadHeaderTimeout returns HTTP_READ_HEADER_TIMEOUT, how long the
+// server waits for a request's headers. Defaults to 10s.
+func GetHTTPReadHeaderTimeout() time.Duration {
+	return Current().HTTPReadHeaderTimeout
+}
+
+// GetHTTPReadTimeout returns HTTP_READ_TIMEOUT, how long the server waits
+// for a whole request. Defaults to 30s.
+func GetHTTPReadTimeout() time.Duration {
+	return Current().HTTPReadTimeout
+}
+
+// GetHTTPWriteTimeout returns HTTP_WRITE_TIMEOUT, how long a handler has to
+// respond. Defaults to 2m, which covers a manual check.
+func GetHTTPWriteTimeout() time.Duration {
+	return Current().HTTPWriteTimeout
+}
+
+// GetHTTPIdleTimeout returns HTTP_IDLE_TIMEOUT, how long an idle keep-alive
+// connection is kept open. Defaults to 2m.
+func GetHTTPIdleTimeout() time.Duration {
+	return Current().HTTPIdleTimeout
+}
+
+// GetHTTPMaxHeaderBytes returns HTTP_MAX_HEADER_BYTES, the largest request
+// header block the server reads. Defaults to 64 KiB.
+func GetHTTPMaxHeaderBytes() int {
+	return Current().HTTPMaxHeaderBytes
+}
+
+// GetTLSFiles returns TLS_CERT_FILE and TLS_KEY_FILE; both empty (the
+// default) serves plain HTTP.
+func GetTLSFiles() (certFile, keyFile string) {
+	e := Current()
+	return e.TLSCertFile, e.TLSKeyFile
+}
+
+// GetDashboardCacheTTL returns DASHBOARD_CACHE_TTL, how long a rendered
+// dashboard view is reused while the collector reports no change. Defaults
+// to 5s; 0 disables the cache.
+func GetDashboardCacheTTL() time.Duration {
+	return Current().DashboardCacheTTL
+}
+
+// GetFirstCycleDelay returns how long to wait after startup before the first
+// BaseEndpoints cycle and discovery run, from FIRST_CYCLE_DELAY (a Go
+// duration such as "10m"). Defaults to 0: check immediately.
+func GetFirstCycleDelay() time.Duration {
+	return Current().FirstCycleDelay
+}
+
+// GetDryRunEnabled reports whether DRY_RUN is set. In dry-run mode provider
+// URLs, request bodies and on-chain calldata are built and validated but no
+// provider, RPC or email requests are sent.
+func GetDryRunEnabled() bool {
+	return Current().DryRun
+}
+
+// GetLocalDevEnabled reports whether LOCAL_DEV is set: the route solvers are
+// replaced by fake in-process providers (see package localdev), so the
+// service runs without API keys or requests to real aggregators.
+func GetLocalDevEnabled() bool {
+	return Current().LocalDev
+}
+
+// getRouteSolverEnabled checks if a specific route solver should be enabled
+// based on environment variables. Returns true by default if no env var is found.
+func getRouteSolverEnabled(solverType string) bool {
+	return !Current().SolverDisabled[solverType]
 }
 
 // BaseEndpoints contains all base endpoint configurations
@@ -327,46 +780,136 @@ func GetEnabledRouteSolvers() []RouteSolver {
 // Environment variable format: DELAY_<ROUTESOLVER> (e.g., DELAY_KYBERSWAP, DELAY_HYPERBLOOM)
 // Defaults to 2 seconds if no environment variable is found
 func GetRouteSolverDelay(routeSolver string) time.Duration {
-	envVarName := "DELAY_" + strings.ToUpper(routeSolver)
-	envValue := os.Getenv(envVarName)
-
-	if envValue == "" {
-		return 2 * time.Second // Default to 2 seconds
+	if d, ok := Current().SolverDelays[routeSolver]; ok {
+		return d
 	}
+	return 2 * time.Second
+}
 
-	// Try to parse as seconds (integer)
-	if seconds, err := strconv.Atoi(envValue); err == nil && seconds >= 0 {
-		return time.Duration(seconds) * time.Second
-	}
+// GetRouteSolverRateLimit returns the minimum spacing between requests to a
+// route solver from RATE_LIMIT_<ROUTESOLVER>, a Go duration (e.g.
+// RATE_LIMIT_KYBERSWAP=2s). With REDIS_URL set the spacing holds across all
+// instances. Zero (the default) disables it.
+func GetRouteSolverRateLimit(routeSolver string) time.Duration {
+	return Current().SolverRateLimits[routeSolver]
+}
 
-	// If parsing fails, return default
-	return 2 * time.Second
+// GetSLOWindow returns the window provider SLO attainment and error budgets
+// are measured over, from SLO_WINDOW_DAYS. Defaults to 30 days.
+func GetSLOWindow() time.Duration {
+	return time.Duration(Current().SLOWindowDays) * 24 * time.Hour
+}
+
+// GetSLOAvailability returns the percentage of a route solver's checks that
+// must be up, from SLO_AVAILABILITY_<ROUTESOLVER>. Defaults to 99.5; 0 (or
+// 100 and above, which leaves no error budget) disables the SLO.
+func GetSLOAvailability(routeSolver string) float64 {
+	return Current().SLOAvailability[routeSolver]
+}
+
+// GetSLOLatency returns a route solver's response time SLO: the threshold
+// its Balancer-only quotes must beat (SLO_LATENCY_<ROUTESOLVER>, default 3s)
+// and the percentage of quotes that must beat it
+// (SLO_LATENCY_TARGET_<ROUTESOLVER>, default 99). A zero threshold or target
+// disables the SLO.
+func GetSLOLatency(routeSolver string) (threshold time.Duration, target float64) {
+	e := Current()
+	return e.SLOLatency[routeSolver], e.SLOLatencyTarget[routeSolver]
+}
+
+// DialOptions is how requests to a route solver connect, for providers that
+// misbehave over one IP family or behind some resolvers.
+type DialOptions struct {
+	// Network is the dial network: "tcp4" or "tcp6" from IP_FAMILY_<ROUTESOLVER>
+	// (ipv4, ipv6), "tcp" for either.
+	Network string
+	// Resolver is the DNS server (host:port) from DNS_RESOLVER_<ROUTESOLVER>,
+	// empty for the system resolver.
+	Resolver string
+	// Hosts maps hostnames to the IP addresses dialled instead of resolving
+	// them, from HOST_OVERRIDES_<ROUTESOLVER> (e.g.
+	// HOST_OVERRIDES_ODOS=api.odos.xyz=104.18.20.1). Malformed pairs are
+	// skipped.
+	Hosts map[string]string
+}
+
+// IsDefault reports whether the options dial like the standard transport.
+func (o DialOptions) IsDefault() bool {
+	return o.Network == "tcp" && o.Resolver == "" && len(o.Hosts) == 0
+}
+
+// GetDialOptions returns how requests to a route solver connect.
+func GetDialOptions(routeSolver string) DialOptions {
+	e := Current()
+	o := DialOptions{Network: "tcp", Resolver: e.DNSResolver[routeSolver]}
+	switch strings.ToLower(e.IPFamily[routeSolver]) {
+	case "ipv4":
+		o.Network = "tcp4"
+	case "ipv6":
+		o.Network = "tcp6"
+	}
+	for _, pair := range strings.Split(e.HostOverrides[routeSolver], ",") {
+		host, ip, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || host == "" || net.ParseIP(strings.TrimSpace(ip)) == nil {
+			continue
+		}
+		if o.Hosts == nil {
+			o.Hosts = map[string]string{}
+		}
+		o.Hosts[strings.ToLower(strings.TrimSpace(host))] = strings.TrimSpace(ip)
+	}
+	return o
 }
 
 // GetRPCURL returns the RPC URL for a given network chain ID.
 func GetRPCURL(network string) string {
-	var envVarName string
+	return Current().RPCURLs[network]
+}
+
+// GetWSRPCURL returns the WebSocket RPC URL for a given network chain ID from
+// <NETWORK>_WS_RPC_URL (e.g. ETHEREUM_WS_RPC_URL). When set, the on-chain
+// price of the network's endpoints is refreshed on new blocks.
+func GetWSRPCURL(network string) string {
+	return Current().WSRPCURLs[network]
+}
+
+// rpcNetworks lists the networks rpcEnvPrefix knows an env var prefix for.
+var rpcNetworks = []string{"1", "42161", "10", "8453", "43114", "100", "999", "9745", "143"}
+
+// RPCNetworks returns the networks GetRPCURL knows an env var for.
+func RPCNetworks() []string {
+	return append([]string(nil), rpcNetworks...)
+}
+
+// rpcEnvPrefix returns the env var prefix of a network's RPC URLs.
+func rpcEnvPrefix(network string) string {
 	switch network {
 	case "1":
-		envVarName = "ETHEREUM_RPC_URL"
+		return "ETHEREUM"
 	case "42161":
-		envVarName = "ARBITRUM_RPC_URL"
+		return "ARBITRUM"
 	case "10":
-		envVarName = "OPTIMISM_RPC_URL"
+		return "OPTIMISM"
 	case "8453":
-		envVarName = "BASE_RPC_URL"
+		return "BASE"
 	case "43114":
-		envVarName = "AVALANCHE_RPC_URL"
+		return "AVALANCHE"
 	case "100":
-		envVarName = "GNOSIS_RPC_URL"
+		return "GNOSIS"
 	case "999":
-		envVarName = "HYPEREVM_RPC_URL"
+		return "HYPEREVM"
 	case "9745":
-		envVarName = "PLASMA_RPC_URL"
+		return "PLASMA"
 	case "143":
-		envVarName = "MONAD_RPC_URL"
+		return "MONAD"
 	default:
 		return ""
 	}
-	return os.Getenv(envVarName)
+}
+
+// GetBlockRefreshInterval returns the time between on-chain price refreshes
+// on networks with a WebSocket RPC URL, from BLOCK_REFRESH_INTERVAL.
+// Defaults to 1m.
+func GetBlockRefreshInterval() time.Duration {
+	return Current().BlockRefreshInterval
 }
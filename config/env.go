@@ -0,0 +1,465 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Env is the service configuration read from environment variables. Each
+// field is one variable, described by its tags:
+//
+//	env      variable name; {SOLVER} and {NETWORK} make a map field with one
+//	         variable per route solver type (DELAY_KYBERSWAP) or RPC network
+//	         (BASE_RPC_URL), keyed by solver type or chain ID
+//	default  value when unset or empty
+//	min      lowest accepted number
+//	oneof    accepted values, case-insensitive
+//	unit     "seconds" / "hours": a duration given as a whole number of that
+//	         unit ("hours" also takes a Go duration such as 15m)
+//	truthy   extra value read as true for a bool
+//	secret   redacted by Dump
+//	doc      description served by /api/v1/config
+//
+// Load parses and validates it once at startup; the Get* functions read it.
+type Env struct {
+	CheckInterval              time.Duration `env:"CHECK_INTERVAL_HOURS" default:"1" min:"30s" unit:"hours" doc:"BaseEndpoints monitoring cadence: whole hours or a Go duration such as 15m"`
+	DiscoveryIntervalHours     int           `env:"DISCOVERY_INTERVAL_HOURS" default:"24" min:"1" doc:"Discovery and test set cadence in hours"`
+	DiscoveryTestPoolsPerGroup int           `env:"DISCOVERY_TEST_POOLS_PER_GROUP" default:"1" min:"1" doc:"Max test set pools per (PoolType, HookType) group"`
+	HookTrigger                bool          `env:"HOOK_TRIGGER" doc:"Add a -surge test row per StableSurge pool sized to trigger the surge fee"`
+	HookQuoteToleranceBps      float64       `env:"HOOK_QUOTE_TOLERANCE_BPS" default:"10" min:"0" doc:"Max difference between a provider's quote on a -surge row and the Router query"`
+	DownRecheckInterval        time.Duration `env:"DOWN_RECHECK_INTERVAL" default:"5m" min:"0" doc:"Recheck a down BaseEndpoints row this long after its failing check, doubling per failed recheck; 0 disables"`
+	DownRecheckMaxInterval     time.Duration `env:"DOWN_RECHECK_MAX_INTERVAL" default:"30m" min:"1m" doc:"Longest wait between rechecks of a down row"`
+	DownRecheckMaxRows         int           `env:"DOWN_RECHECK_MAX_ROWS" default:"10" min:"1" doc:"Down rows rechecked per pass, oldest check first, to stay within provider rate limits"`
+	FirstCycleDelay            time.Duration `env:"FIRST_CYCLE_DELAY" default:"0s" min:"0" doc:"Wait before the first BaseEndpoints cycle and discovery run"`
+	ManualCheckInterval        time.Duration `env:"MANUAL_CHECK_INTERVAL" default:"30s" min:"0" doc:"Shortest time between manual checks (Check Now, TriggerCheck) of one endpoint; 0 disables the throttle"`
+	DryRun                     bool          `env:"DRY_RUN" doc:"Build and validate provider requests and on-chain calldata without sending them"`
+	LocalDev                   bool          `env:"LOCAL_DEV" doc:"Check fake in-process providers (steady, flaky, down) instead of the real route solvers and seed a week of history"`
+	UserAgent                  string        `env:"USER_AGENT" doc:"User-Agent of provider requests; empty sends go-monitoring/<version> (+<USER_AGENT_CONTACT>)"`
+	UserAgentContact           string        `env:"USER_AGENT_CONTACT" default:"https://github.com/johngrantuk/go-monitoring" doc:"Contact URL or email in the default User-Agent"`
+
+	EmailNotifications bool   `env:"EMAIL_NOTIFICATIONS" doc:"Email alerts via Resend"`
+	ResendAPIKey       string `env:"RESEND_API_KEY" secret:"true" doc:"Resend API key for email alerts"`
+	EmailMinSeverity   string `env:"EMAIL_MIN_SEVERITY" oneof:"info,warning,warn,critical" doc:"Lowest alert severity emailed; empty sends every alert"`
+	SlackWebhookURL    string `env:"SLACK_WEBHOOK_URL" secret:"true" doc:"Slack incoming webhook for alerts"`
+	SlackMinSeverity   string `env:"SLACK_MIN_SEVERITY" oneof:"info,warning,warn,critical" doc:"Lowest alert severity posted to Slack; empty sends every alert"`
+	QuietHours         string `env:"QUIET_HOURS" doc:"Daily window (e.g. 22:00-07:00) during which non-critical alerts are held for a digest"`
+	QuietHoursTZ       string `env:"QUIET_HOURS_TZ" doc:"IANA time zone of QUIET_HOURS; empty means UTC"`
+	PublicURL          string `env:"PUBLIC_URL" doc:"Dashboard base URL for alert deep links"`
+	AlertHintsFile     string `env:"ALERT_HINTS_FILE" doc:"JSON hints table overriding the built-in alert hints by class"`
+	AlertRoutesFile    string `env:"ALERT_ROUTES_FILE" doc:"JSON alert routes sending labelled endpoints' alerts to extra destinations"`
+	NotifySpoolFile    string `env:"NOTIFY_SPOOL_FILE" doc:"JSON lines file notifications awaiting a retry spill to beyond the in-memory queue and at shutdown; empty keeps them in memory only"`
+	MetricLabelKeys    string `env:"METRIC_LABEL_KEYS" doc:"Comma-separated endpoint label keys exported as metric labels"`
+
+	AlertStormMax    int           `env:"ALERT_STORM_MAX" default:"20" min:"0" doc:"Most notifications sent per ALERT_STORM_WINDOW; later ones are rolled up into one summary; 0 disables the cap"`
+	AlertStormWindow time.Duration `env:"ALERT_STORM_WINDOW" default:"10m" min:"1m" doc:"Window ALERT_STORM_MAX counts notifications over"`
+
+	ChaosFaults  string        `env:"CHAOS_FAULTS" doc:"Comma-separated faults injected into provider requests and checks: latency, drop, malformed, 500; empty disables (testing and staging only)"`
+	ChaosRate    float64       `env:"CHAOS_RATE" default:"0.1" min:"0" doc:"Fraction of provider requests and checks CHAOS_FAULTS faults"`
+	ChaosLatency time.Duration `env:"CHAOS_LATENCY" default:"5s" min:"0" doc:"Longest delay of the latency fault"`
+	ChaosSolvers string        `env:"CHAOS_SOLVERS" doc:"Comma-separated route solver types CHAOS_FAULTS applies to; empty applies to all"`
+
+	BalancerAPIURL          string `env:"BALANCER_API_URL" default:"https://api-v3.balancer.fi/" doc:"Balancer API GraphQL endpoint the balancer_sor provider queries"`
+	BalancerAPIFallbackURLs string `env:"BALANCER_API_FALLBACK_URLS" doc:"Comma-separated alternate Balancer API endpoints (e.g. canary, backup) tried in order when BALANCER_API_URL can't be reached or answers 429 or 5xx"`
+	SORBatchSize            int    `env:"SOR_BATCH_SIZE" default:"10" min:"0" doc:"Most sorGetSwapPaths queries sent to the Balancer API in one GraphQL request: a cycle fetches a balancer_sor row's queries together with the next rows'; 0 or 1 sends each alone"`
+
+	JiraBaseURL   string `env:"JIRA_BASE_URL" doc:"Jira site escalation tickets are created on, e.g. https://example.atlassian.net; empty disables them"`
+	JiraEmail     string `env:"JIRA_EMAIL" doc:"Jira account escalation tickets are created as"`
+	JiraAPIToken  string `env:"JIRA_API_TOKEN" secret:"true" doc:"API token of JIRA_EMAIL"`
+	JiraProject   string `env:"JIRA_PROJECT" doc:"Key of the partnerships project escalation tickets are created in"`
+	JiraIssueType string `env:"JIRA_ISSUE_TYPE" default:"Task" doc:"Issue type of escalation tickets"`
+	JiraRulesFile string `env:"JIRA_RULES_FILE" doc:"JSON rules creating escalation tickets for failures automatically"`
+
+	SourceIDsFile string `env:"SOURCE_IDS_FILE" doc:"JSON source ID table checked before the built-in one"`
+	RoutersFile   string `env:"ROUTERS_FILE" doc:"JSON Balancer V3 router table merged with the built-in one"`
+	SpendersFile  string `env:"SPENDERS_FILE" doc:"JSON known-good spender table added to the built-in one"`
+	MessagesFile  string `env:"MESSAGES_FILE" doc:"JSON message catalog adding or overriding translations of the partner-facing pages"`
+	PoolWatchFile string `env:"POOL_WATCH_FILE" doc:"JSON pool factories to watch for new pools, and filters"`
+	EndpointsFile string `env:"ENDPOINTS_FILE" doc:"JSON endpoints file merged over BaseEndpoints at startup; imports upsert into it"`
+	ProfilesFile  string `env:"PROFILES_FILE" doc:"JSON deployment profiles: settings, solvers and the endpoint subset per environment"`
+	AppEnv        string `env:"APP_ENV" doc:"Profile from PROFILES_FILE applied at startup, e.g. staging; empty applies none"`
+	AdminToken    string `env:"ADMIN_TOKEN" secret:"true" doc:"Bearer token for the import APIs and note edits; empty refuses them"`
+
+	APIRequireToken bool `env:"API_REQUIRE_TOKEN" doc:"Require an API token (or ADMIN_TOKEN) with the read scope for the JSON and RPC APIs and trigger-check for manual checks"`
+
+	AccessLog         bool `env:"ACCESS_LOG" default:"true" doc:"Log each dashboard and API request: method, path, status, duration and client IP"`
+	TrustProxyHeaders bool `env:"TRUST_PROXY_HEADERS" doc:"Take the client IP from Fly-Client-IP or X-Forwarded-For; set only behind a proxy that sets them"`
+
+	Port                  int           `env:"PORT" default:"8080" min:"1" doc:"Port the dashboard and APIs listen on"`
+	HTTPReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" default:"10s" min:"0" doc:"Longest time to read a request's headers; 0 uses HTTP_READ_TIMEOUT"`
+	HTTPReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" default:"30s" min:"0" doc:"Longest time to read a whole request, body included; 0 disables"`
+	HTTPWriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" default:"2m" min:"0" doc:"Longest time to write a response after reading the headers; Check Now answers after its check, so keep it above a check's duration; 0 disables"`
+	HTTPIdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" default:"2m" min:"0" doc:"How long a keep-alive connection waits for the next request; 0 uses HTTP_READ_TIMEOUT"`
+	HTTPMaxHeaderBytes    int           `env:"HTTP_MAX_HEADER_BYTES" default:"65536" min:"1024" doc:"Largest request header block accepted, in bytes"`
+	TLSCertFile           string        `env:"TLS_CERT_FILE" doc:"PEM certificate (chain) to serve HTTPS with, together with TLS_KEY_FILE; empty serves plain HTTP"`
+	TLSKeyFile            string        `env:"TLS_KEY_FILE" doc:"PEM private key of TLS_CERT_FILE"`
+	DashboardCacheTTL     time.Duration `env:"DASHBOARD_CACHE_TTL" default:"5s" min:"0" doc:"How long a rendered dashboard view is served again while no endpoint changes; 0 renders every request"`
+
+	PoolMigrationAutoApply bool    `env:"POOL_MIGRATION_AUTO_APPLY" doc:"Write a detected replacement pool into the running BaseEndpoints"`
+	PriceImpactAlertBps    float64 `env:"PRICE_IMPACT_ALERT_BPS" default:"100" min:"0" doc:"Alert above this provider-reported price impact; 0 disables"`
+	BalancerRankAlertTopN  int     `env:"BALANCER_RANK_ALERT_TOP_N" default:"3" min:"0" doc:"Alert when Balancer V3 ranks below this among per-source quotes; 0 disables"`
+	QuoteSpreadAlertBps    float64 `env:"QUOTE_SPREAD_ALERT_BPS" default:"25" min:"0" doc:"Alert when the quote spread widens by more than this; 0 disables"`
+	QuoteSpreadAlertChecks int     `env:"QUOTE_SPREAD_ALERT_CHECKS" default:"6" min:"1" doc:"Checks the quote spread must stay widened before alerting"`
+	SLOWindowDays          int     `env:"SLO_WINDOW_DAYS" default:"30" min:"1" doc:"Days over which provider SLO attainment and error budgets are measured"`
+
+	StorePath                  string `env:"STORE_PATH" doc:"JSON file for persisted results and history; empty disables persistence"`
+	DatabaseURL                string `env:"DATABASE_URL" secret:"true" doc:"Postgres URL, or sqlite:<path> for a SQLite file, for persisted results and history; takes precedence over STORE_PATH"`
+	HistoryRetentionDays       int    `env:"HISTORY_RETENTION_DAYS" default:"30" min:"1" doc:"Days of raw check results kept before hourly roll-up (raised to 7)"`
+	HistoryHourlyRetentionDays int    `env:"HISTORY_HOURLY_RETENTION_DAYS" default:"365" min:"1" doc:"Days of hourly aggregates and resolved incidents kept"`
+	WarmStart                  bool   `env:"WARM_START" doc:"Restore statuses and history from the store at startup"`
+	RedisURL                   string `env:"REDIS_URL" secret:"true" doc:"Redis URL sharing caches and rate limits across instances"`
+	LeaderElection             bool   `env:"LEADER_ELECTION" doc:"Only the Postgres advisory lock holder runs checks"`
+
+	WorkerCollectorURL string `env:"WORKER_COLLECTOR_URL" doc:"Run as a regional worker reporting to this central instance"`
+	WorkerToken        string `env:"WORKER_TOKEN" secret:"true" doc:"Shared secret for worker reports"`
+	WorkerRegion       string `env:"WORKER_REGION" doc:"Region a worker reports as; defaults to FLY_REGION"`
+	FlyRegion          string `env:"FLY_REGION" doc:"Region set by Fly"`
+
+	BlockRefreshInterval time.Duration `env:"BLOCK_REFRESH_INTERVAL" default:"1m" min:"1s" doc:"Time between on-chain price refreshes on networks with a WebSocket RPC URL; each runs on the first new block after it"`
+
+	SolverDisabled   map[string]bool          `env:"DISABLE_{SOLVER}" truthy:"disable" doc:"Disable the route solver"`
+	SolverDelays     map[string]time.Duration `env:"DELAY_{SOLVER}" default:"2" min:"0" unit:"seconds" doc:"Seconds to wait after each check of the route solver"`
+	SolverRateLimits map[string]time.Duration `env:"RATE_LIMIT_{SOLVER}" default:"0s" min:"0" doc:"Minimum spacing between requests to the route solver"`
+	SLOAvailability  map[string]float64       `env:"SLO_AVAILABILITY_{SOLVER}" default:"99.5" min:"0" doc:"Percent of the route solver's checks that must be up; 0 disables"`
+	SLOLatency       map[string]time.Duration `env:"SLO_LATENCY_{SOLVER}" default:"3s" min:"0" doc:"Response time the route solver's quotes must beat; 0 disables"`
+	SLOLatencyTarget map[string]float64       `env:"SLO_LATENCY_TARGET_{SOLVER}" default:"99" min:"0" doc:"Percent of the route solver's quotes that must beat SLO_LATENCY_<SOLVER>"`
+	IPFamily         map[string]string        `env:"IP_FAMILY_{SOLVER}" oneof:"ipv4,ipv6" doc:"Only connect to the route solver over this IP family; empty uses either"`
+	DNSResolver      map[string]string        `env:"DNS_RESOLVER_{SOLVER}" doc:"DNS server (host:port) resolving the route solver's hosts; empty uses the system resolver"`
+	HostOverrides    map[string]string        `env:"HOST_OVERRIDES_{SOLVER}" doc:"Comma-separated host=IP pairs connecting to a fixed address instead of resolving the host"`
+	RPCURLs          map[string]string        `env:"{NETWORK}_RPC_URL" secret:"true" doc:"HTTP RPC URL of the network"`
+	WSRPCURLs        map[string]string        `env:"{NETWORK}_WS_RPC_URL" secret:"true" doc:"WebSocket RPC URL of the network; refreshes on-chain prices on new blocks"`
+}
+
+// loadedEnv is the configuration pinned by Load.
+var loadedEnv atomic.Pointer[Env]
+
+// Load parses the environment, under the profile APP_ENV names (see
+// Profile), into the Env the Get* functions read for the rest of the
+// process. It returns every invalid value; each falls back to its default. A
+// profile that fails to load is an error too.
+func Load() error {
+	p, err := LoadProfile(os.LookupEnv)
+	if err != nil {
+		return EnvErrors{err}
+	}
+	activeProfile.Store(p)
+	e, errs := ParseEnv(Lookup)
+	loadedEnv.Store(e)
+	return joinErrors(errs)
+}
+
+// Current returns the Env pinned by Load. Before Load (tests, library use)
+// it parses the environment on every call, so changes are seen immediately.
+func Current() *Env {
+	if e := loadedEnv.Load(); e != nil {
+		return e
+	}
+	e, _ := ParseEnv(os.LookupEnv)
+	return e
+}
+
+// EnvErrors lists invalid environment values.
+type EnvErrors []error
+
+func (e EnvErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return EnvErrors(errs)
+}
+
+// envVar is one variable of an Env field: a map field has one per solver or
+// network, identified by key.
+type envVar struct {
+	name string
+	key  string
+}
+
+// fieldVars expands a field's env tag.
+func fieldVars(pattern string) []envVar {
+	switch {
+	case strings.Contains(pattern, "{SOLVER}"):
+		out := make([]envVar, 0, len(RouteSolvers))
+		for _, s := range RouteSolvers {
+			out = append(out, envVar{name: strings.Replace(pattern, "{SOLVER}", strings.ToUpper(s.Type), 1), key: s.Type})
+		}
+		return out
+	case strings.Contains(pattern, "{NETWORK}"):
+		out := make([]envVar, 0, len(rpcNetworks))
+		for _, n := range rpcNetworks {
+			out = append(out, envVar{name: strings.Replace(pattern, "{NETWORK}", rpcEnvPrefix(n), 1), key: n})
+		}
+		return out
+	default:
+		return []envVar{{name: pattern}}
+	}
+}
+
+// ParseEnv builds an Env from lookup (os.LookupEnv outside tests), returning
+// an error per invalid value alongside the Env with defaults in their place.
+func ParseEnv(lookup func(string) (string, bool)) (*Env, []error) {
+	e := &Env{}
+	v := reflect.ValueOf(e).Elem()
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		pattern := f.Tag.Get("env")
+		if f.Type.Kind() != reflect.Map {
+			val, err := parseVar(f, pattern, lookup)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			v.Field(i).Set(val)
+			continue
+		}
+		m := reflect.MakeMap(f.Type)
+		for _, ev := range fieldVars(pattern) {
+			val, err := parseVar(f, ev.name, lookup)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(ev.key), val)
+		}
+		v.Field(i).Set(m)
+	}
+	if (e.TLSCertFile == "") != (e.TLSKeyFile == "") {
+		errs = append(errs, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+		e.TLSCertFile, e.TLSKeyFile = "", ""
+	}
+	return e, errs
+}
+
+// parseVar reads one variable of field f. An invalid value returns the
+// default and an error naming the variable.
+func parseVar(f reflect.StructField, name string, lookup func(string) (string, bool)) (reflect.Value, error) {
+	typ := f.Type
+	if typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	def, err := parseValue(f.Tag, typ, f.Tag.Get("default"))
+	if err != nil {
+		panic(fmt.Sprintf("config: bad default for %s: %v", name, err))
+	}
+	raw, _ := lookup(name)
+	if raw = strings.TrimSpace(raw); raw == "" {
+		return def, nil
+	}
+	val, err := parseValue(f.Tag, typ, raw)
+	if err != nil {
+		return def, fmt.Errorf("%s=%q: %v", name, raw, err)
+	}
+	return val, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseValue converts raw to typ under the field's tags. An empty raw is the
+// zero value.
+func parseValue(tag reflect.StructTag, typ reflect.Type, raw string) (reflect.Value, error) {
+	out := reflect.New(typ).Elem()
+	if raw == "" {
+		return out, nil
+	}
+	var num float64
+	switch {
+	case typ == durationType:
+		var d time.Duration
+		if unit := tag.Get("unit"); unit == "seconds" {
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return out, fmt.Errorf("want whole seconds")
+			}
+			d = time.Duration(n) * time.Second
+		} else if n, err := strconv.Atoi(raw); err == nil && unit == "hours" {
+			d = time.Duration(n) * time.Hour
+		} else {
+			var err error
+			if d, err = time.ParseDuration(raw); err != nil {
+				return out, fmt.Errorf("want a Go duration such as 10m")
+			}
+		}
+		out.SetInt(int64(d))
+		num = float64(d)
+	case typ.Kind() == reflect.Bool:
+		b, ok := parseBool(raw, tag.Get("truthy"))
+		if !ok {
+			return out, fmt.Errorf("want true or false")
+		}
+		out.SetBool(b)
+		return out, nil
+	case typ.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return out, fmt.Errorf("want an integer")
+		}
+		out.SetInt(int64(n))
+		num = float64(n)
+	case typ.Kind() == reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return out, fmt.Errorf("want a non-negative integer")
+		}
+		out.SetUint(n)
+		num = float64(n)
+	case typ.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return out, fmt.Errorf("want a number")
+		}
+		out.SetFloat(n)
+		num = n
+	case typ.Kind() == reflect.String:
+		if oneof := tag.Get("oneof"); oneof != "" && !containsFold(strings.Split(oneof, ","), raw) {
+			return out, fmt.Errorf("want one of %s", oneof)
+		}
+		out.SetString(raw)
+		return out, nil
+	default:
+		panic("config: unsupported Env field type " + typ.String())
+	}
+	if m := tag.Get("min"); m != "" {
+		floor, _ := strconv.ParseFloat(m, 64)
+		if typ == durationType {
+			d, _ := time.ParseDuration(m)
+			floor = float64(d)
+		}
+		if num < floor {
+			return out, fmt.Errorf("below the minimum %s", m)
+		}
+	}
+	return out, nil
+}
+
+// parseBool reads the boolean spellings the service has always accepted.
+func parseBool(raw, truthy string) (value, ok bool) {
+	switch s := strings.ToLower(raw); {
+	case s == "true" || s == "1" || s == "yes" || s == "on" || (truthy != "" && s == truthy):
+		return true, true
+	case s == "false" || s == "0" || s == "no" || s == "off":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Setting is one variable as served by /api/v1/config.
+type Setting struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Default string `json:"default,omitempty"`
+	Set     bool   `json:"set"`
+	Secret  bool   `json:"secret,omitempty"`
+	Doc     string `json:"doc"`
+}
+
+// Redacted replaces a set secret's value in Dump.
+const Redacted = "[redacted]"
+
+// Dump lists every variable of e in declaration order (map fields sorted by
+// variable name), with the effective value. Secrets show Redacted when set.
+// lookup tells which variables are set.
+func (e *Env) Dump(lookup func(string) (string, bool)) []Setting {
+	var out []Setting
+	v := reflect.ValueOf(e).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		vars := fieldVars(f.Tag.Get("env"))
+		if f.Type.Kind() == reflect.Map {
+			sort.Slice(vars, func(a, b int) bool { return vars[a].name < vars[b].name })
+		}
+		for _, ev := range vars {
+			val := v.Field(i)
+			if f.Type.Kind() == reflect.Map {
+				val = val.MapIndex(reflect.ValueOf(ev.key))
+			}
+			raw, _ := lookup(ev.name)
+			s := Setting{
+				Name:    ev.name,
+				Value:   formatValue(val),
+				Default: f.Tag.Get("default"),
+				Set:     strings.TrimSpace(raw) != "",
+				Secret:  f.Tag.Get("secret") == "true",
+				Doc:     f.Tag.Get("doc"),
+			}
+			if s.Secret && s.Set {
+				s.Value = Redacted
+			}
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// credentialSuffixes mark variables read outside Env that hold credentials,
+// such as the provider keys (ZEROX_API_KEY) the providers read directly.
+var credentialSuffixes = []string{"_API_KEY", "_TOKEN", "_SECRET", "_PASSWORD"}
+
+// SecretValues returns the set values of e's secret variables and of the
+// environ entries ("NAME=value") whose names end in _API_KEY, _TOKEN,
+// _SECRET or _PASSWORD, for redaction from logs, pages and alerts.
+func (e *Env) SecretValues(environ []string) []string {
+	var out []string
+	v := reflect.ValueOf(e).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.Tag.Get("secret") != "true" {
+			continue
+		}
+		switch val := v.Field(i); val.Kind() {
+		case reflect.String:
+			out = append(out, val.String())
+		case reflect.Map:
+			for _, k := range val.MapKeys() {
+				out = append(out, val.MapIndex(k).String())
+			}
+		}
+	}
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		for _, suffix := range credentialSuffixes {
+			if strings.HasSuffix(name, suffix) {
+				out = append(out, value)
+				break
+			}
+		}
+	}
+	var set []string
+	for _, s := range out {
+		if s = strings.TrimSpace(s); s != "" {
+			set = append(set, s)
+		}
+	}
+	return set
+}
+
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func lookupFrom(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestParseEnvDefaults(t *testing.T) {
+	e, errs := ParseEnv(lookupFrom(nil))
+	if len(errs) != 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if e.CheckInterval != time.Hour || e.DiscoveryIntervalHours != 24 || e.BlockRefreshInterval != time.Minute || e.PriceImpactAlertBps != 100 {
+		t.Errorf("defaults = %+v", e)
+	}
+	if d := e.SolverDelays["kyberswap"]; d != 2*time.Second {
+		t.Errorf("kyberswap delay = %v, want 2s", d)
+	}
+	if e.SolverDisabled["kyberswap"] {
+		t.Error("kyberswap disabled by default")
+	}
+}
+
+func TestParseEnvValues(t *testing.T) {
+	e, errs := ParseEnv(lookupFrom(map[string]string{
+		"CHECK_INTERVAL_HOURS": " 3 ",
+		"DELAY_KYBERSWAP":      "5",
+		"RATE_LIMIT_KYBERSWAP": "1500ms",
+		"DISABLE_KYBERSWAP":    "disable",
+		"EMAIL_NOTIFICATIONS":  "Yes",
+		"SLACK_MIN_SEVERITY":   "WARN",
+		"BASE_RPC_URL":         "https://base.example",
+		"FIRST_CYCLE_DELAY":    "10m",
+	}))
+	if len(errs) != 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if e.CheckInterval != 3*time.Hour || !e.EmailNotifications || e.SlackMinSeverity != "WARN" || e.FirstCycleDelay != 10*time.Minute {
+		t.Errorf("env = %+v", e)
+	}
+	if e.SolverDelays["kyberswap"] != 5*time.Second || e.SolverRateLimits["kyberswap"] != 1500*time.Millisecond || !e.SolverDisabled["kyberswap"] {
+		t.Errorf("kyberswap = %v %v %v", e.SolverDelays["kyberswap"], e.SolverRateLimits["kyberswap"], e.SolverDisabled["kyberswap"])
+	}
+	if e.RPCURLs["8453"] != "https://base.example" {
+		t.Errorf("RPCURLs[8453] = %q", e.RPCURLs["8453"])
+	}
+}
+
+func TestParseEnvCheckIntervalDuration(t *testing.T) {
+	e, errs := ParseEnv(lookupFrom(map[string]string{"CHECK_INTERVAL_HOURS": "15m"}))
+	if len(errs) != 0 || e.CheckInterval != 15*time.Minute {
+		t.Errorf("15m = %v, %v", e.CheckInterval, errs)
+	}
+	if _, errs := ParseEnv(lookupFrom(map[string]string{"CHECK_INTERVAL_HOURS": "10s"})); len(errs) != 1 {
+		t.Errorf("10s accepted below the minimum: %v", errs)
+	}
+}
+
+func TestParseEnvInvalidFallsBackAndReports(t *testing.T) {
+	e, errs := ParseEnv(lookupFrom(map[string]string{
+		"CHECK_INTERVAL_HOURS":   "0",
+		"DELAY_KYBERSWAP":        "2s",
+		"DRY_RUN":                "maybe",
+		"EMAIL_MIN_SEVERITY":     "loud",
+		"PRICE_IMPACT_ALERT_BPS": "x",
+	}))
+	if len(errs) != 5 {
+		t.Fatalf("errors = %v, want 5", errs)
+	}
+	for _, name := range []string{"CHECK_INTERVAL_HOURS", "DELAY_KYBERSWAP", "DRY_RUN", "EMAIL_MIN_SEVERITY", "PRICE_IMPACT_ALERT_BPS"} {
+		if !strings.Contains(EnvErrors(errs).Error(), name) {
+			t.Errorf("errors do not name %s: %v", name, errs)
+		}
+	}
+	if e.CheckInterval != time.Hour || e.SolverDelays["kyberswap"] != 2*time.Second || e.DryRun || e.EmailMinSeverity != "" || e.PriceImpactAlertBps != 100 {
+		t.Errorf("invalid values did not fall back to defaults: %+v", e)
+	}
+}
+
+func TestDumpRedactsSecrets(t *testing.T) {
+	vars := map[string]string{
+		"SLACK_WEBHOOK_URL":    "https://hooks.slack.com/services/secret",
+		"ETHEREUM_RPC_URL":     "https://mainnet.example/key",
+		"CHECK_INTERVAL_HOURS": "2",
+	}
+	e, _ := ParseEnv(lookupFrom(vars))
+	got := map[string]Setting{}
+	for _, s := range e.Dump(lookupFrom(vars)) {
+		got[s.Name] = s
+	}
+	for _, name := range []string{"SLACK_WEBHOOK_URL", "ETHEREUM_RPC_URL"} {
+		if s := got[name]; s.Value != Redacted || !s.Set || !s.Secret {
+			t.Errorf("%s = %+v, want redacted", name, s)
+		}
+	}
+	if s := got["CHECK_INTERVAL_HOURS"]; s.Value != "2h0m0s" || !s.Set || s.Default != "1" || s.Doc == "" {
+		t.Errorf("CHECK_INTERVAL_HOURS = %+v", s)
+	}
+	if s := got["ADMIN_TOKEN"]; s.Value != "" || s.Set {
+		t.Errorf("unset ADMIN_TOKEN = %+v", s)
+	}
+	if s := got["DELAY_KYBERSWAP"]; s.Value != "2s" {
+		t.Errorf("DELAY_KYBERSWAP = %+v", s)
+	}
+}
+
+func TestSecretValues(t *testing.T) {
+	vars := map[string]string{
+		"RESEND_API_KEY":       "re_123",
+		"ETHEREUM_RPC_URL":     "https://mainnet.example/key",
+		"CHECK_INTERVAL_HOURS": "2",
+	}
+	e, _ := ParseEnv(lookupFrom(vars))
+	got := e.SecretValues([]string{"ZEROX_API_KEY=zx-key", "GITHUB_TOKEN=", "PATH=/usr/bin"})
+	want := map[string]bool{"re_123": true, "https://mainnet.example/key": true, "zx-key": true}
+	if len(got) != len(want) {
+		t.Fatalf("SecretValues = %q, want %v", got, want)
+	}
+	for _, v := range got {
+		if !want[v] {
+			t.Errorf("SecretValues has %q", v)
+		}
+	}
+}
+
+func TestGetUserAgent(t *testing.T) {
+	defer loadedEnv.Store(loadedEnv.Load())
+
+	e, _ := ParseEnv(lookupFrom(nil))
+	loadedEnv.Store(e)
+	if got, want := GetUserAgent(), "go-monitoring/"+BuildVersion()+" (+https://github.com/johngrantuk/go-monitoring)"; got != want {
+		t.Errorf("default = %q, want %q", got, want)
+	}
+	e, _ = ParseEnv(lookupFrom(map[string]string{"USER_AGENT": "acme-monitor/1"}))
+	loadedEnv.Store(e)
+	if got := GetUserAgent(); got != "acme-monitor/1" {
+		t.Errorf("USER_AGENT = %q", got)
+	}
+}
+
+func TestGetDialOptions(t *testing.T) {
+	if o := GetDialOptions("kyberswap"); !o.IsDefault() {
+		t.Fatalf("default = %+v", o)
+	}
+	t.Setenv("IP_FAMILY_KYBERSWAP", "IPv4")
+	t.Setenv("DNS_RESOLVER_KYBERSWAP", "1.1.1.1:53")
+	t.Setenv("HOST_OVERRIDES_KYBERSWAP", "API.kyberswap.com=104.18.1.1, aggregator.example=2606:4700::1,bad,nohost=x")
+	o := GetDialOptions("kyberswap")
+	if o.Network != "tcp4" || o.Resolver != "1.1.1.1:53" || len(o.Hosts) != 2 ||
+		o.Hosts["api.kyberswap.com"] != "104.18.1.1" || o.Hosts["aggregator.example"] != "2606:4700::1" {
+		t.Fatalf("options = %+v", o)
+	}
+}
+
+func TestGetSQLitePath(t *testing.T) {
+	for url, want := range map[string]string{
+		"sqlite:/data/monitor.db":            "/data/monitor.db",
+		"sqlite:///data/monitor.db":          "/data/monitor.db",
+		"sqlite:monitor.db":                  "monitor.db",
+		"sqlite:":                            "",
+		"postgres://user:pw@db.internal/mon": "",
+	} {
+		t.Setenv("DATABASE_URL", url)
+		if got, ok := GetSQLitePath(); got != want || ok != (want != "") {
+			t.Errorf("GetSQLitePath(%q) = %q, %v, want %q", url, got, ok, want)
+		}
+	}
+}
+
+func TestParseEnvTLSFilesTogether(t *testing.T) {
+	e, errs := ParseEnv(lookupFrom(map[string]string{"TLS_CERT_FILE": "/etc/tls/cert.pem"}))
+	if len(errs) != 1 || e.TLSCertFile != "" {
+		t.Errorf("certificate without key = %q, %v", e.TLSCertFile, errs)
+	}
+	e, errs = ParseEnv(lookupFrom(map[string]string{"TLS_CERT_FILE": "/etc/tls/cert.pem", "TLS_KEY_FILE": "/etc/tls/key.pem"}))
+	if len(errs) != 0 || e.TLSKeyFile != "/etc/tls/key.pem" {
+		t.Errorf("certificate and key = %q, %v", e.TLSKeyFile, errs)
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Labels are free-form tags on a BaseEndpoint, e.g.
+//
+//	Labels: Labels{"team": "integrations", "priority": "p1", "partner": "aave"},
+//
+// They filter the dashboard (?labels=team=integrations), become metric labels
+// for the keys in METRIC_LABEL_KEYS, and select alert routes
+// (ALERT_ROUTES_FILE). Keys are lower-case identifiers so they are valid
+// Prometheus label names.
+type Labels map[string]string
+
+var labelKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ValidLabelKey reports whether key is usable as a label key.
+func ValidLabelKey(key string) bool {
+	return labelKeyPattern.MatchString(key)
+}
+
+// ParseLabels reads "key=value" pairs separated by commas or semicolons,
+// e.g. "team=integrations;priority=p1". Keys are lower-cased; values are kept
+// as written.
+func ParseLabels(s string) (Labels, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' })
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	out := make(Labels, len(fields))
+	for _, f := range fields {
+		k, v, ok := strings.Cut(f, "=")
+		k, v = strings.ToLower(strings.TrimSpace(k)), strings.TrimSpace(v)
+		if !ok || v == "" {
+			return nil, fmt.Errorf("label %q: want key=value", strings.TrimSpace(f))
+		}
+		if !ValidLabelKey(k) {
+			return nil, fmt.Errorf("label key %q: want lower-case letters, digits and underscores", k)
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// Validate checks every key and that no value is empty.
+func (l Labels) Validate() error {
+	for _, k := range l.Keys() {
+		if !ValidLabelKey(k) {
+			return fmt.Errorf("label key %q: want lower-case letters, digits and underscores", k)
+		}
+		if l[k] == "" {
+			return fmt.Errorf("label %q has an empty value", k)
+		}
+	}
+	return nil
+}
+
+// Keys returns the label keys, sorted.
+func (l Labels) Keys() []string {
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String renders the labels as sorted "key=value" pairs joined by commas, the
+// form ParseLabels reads.
+func (l Labels) String() string {
+	pairs := make([]string, 0, len(l))
+	for _, k := range l.Keys() {
+		pairs = append(pairs, k+"="+l[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Matches reports whether l has every pair in selector (values compared
+// case-insensitively). An empty selector matches everything.
+func (l Labels) Matches(selector Labels) bool {
+	for k, v := range selector {
+		if !strings.EqualFold(l[k], v) {
+			return false
+		}
+	}
+	return true
+}
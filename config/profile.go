@@ -0,0 +1,175 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Profile is a named deployment environment (dev, staging, prod) from
+// PROFILES_FILE, applied by Load when APP_ENV names it, e.g.
+//
+//	{"staging": {
+//	  "settings": {"CHECK_INTERVAL_HOURS": "15m", "SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/staging"},
+//	  "solvers": ["kyberswap", "odos"],
+//	  "endpoints": {"networks": ["8453"], "labels": {"priority": "p1"}}
+//	}}
+//
+// so a staging deployment sharing production's environment checks a small
+// endpoint set with its own notification targets.
+type Profile struct {
+	Name string `json:"-"`
+	// Settings are environment variables taking precedence over the
+	// environment. APP_ENV and PROFILES_FILE can't be set here.
+	Settings map[string]string `json:"settings,omitempty"`
+	// Solvers are the route solver types to run, the others disabled as by
+	// DISABLE_<SOLVER>; empty leaves them as configured.
+	Solvers []string `json:"solvers,omitempty"`
+	// Endpoints selects the BaseEndpoints to check; empty checks them all.
+	Endpoints EndpointSelector `json:"endpoints"`
+}
+
+// EndpointSelector picks BaseEndpoints by name, network and labels. Each
+// non-empty criterion must match.
+type EndpointSelector struct {
+	Names    []string `json:"names,omitempty"`
+	Networks []string `json:"networks,omitempty"` // chain IDs
+	Labels   Labels   `json:"labels,omitempty"`
+}
+
+// IsEmpty reports whether the selector selects every endpoint.
+func (s EndpointSelector) IsEmpty() bool {
+	return len(s.Names) == 0 && len(s.Networks) == 0 && len(s.Labels) == 0
+}
+
+// Matches reports whether the selector selects b.
+func (s EndpointSelector) Matches(b BaseEndpoint) bool {
+	if len(s.Names) > 0 && !slices.Contains(s.Names, b.Name) {
+		return false
+	}
+	if len(s.Networks) > 0 && !slices.Contains(s.Networks, b.Network) {
+		return false
+	}
+	return b.Labels.Matches(s.Labels)
+}
+
+// Select returns the endpoints the selector matches, in order.
+func (s EndpointSelector) Select(endpoints []BaseEndpoint) []BaseEndpoint {
+	if s.IsEmpty() {
+		return endpoints
+	}
+	var out []BaseEndpoint
+	for _, b := range endpoints {
+		if s.Matches(b) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// activeProfile is the profile applied by Load, nil when APP_ENV is unset.
+var activeProfile atomic.Pointer[Profile]
+
+// GetProfile returns the profile applied by Load (APP_ENV), false when none
+// is.
+func GetProfile() (Profile, bool) {
+	if p := activeProfile.Load(); p != nil {
+		return *p, true
+	}
+	return Profile{}, false
+}
+
+// Lookup reads an environment variable as Load saw it: the active profile's
+// settings first, then the environment.
+func Lookup(name string) (string, bool) {
+	if p := activeProfile.Load(); p != nil {
+		return p.lookup(os.LookupEnv)(name)
+	}
+	return os.LookupEnv(name)
+}
+
+// LoadProfile reads the profile APP_ENV names from PROFILES_FILE, both read
+// through lookup. It returns nil when APP_ENV is unset, and an error when the
+// file can't be read, APP_ENV isn't in it or the profile is invalid.
+func LoadProfile(lookup func(string) (string, bool)) (*Profile, error) {
+	name, _ := lookup("APP_ENV")
+	if name = strings.TrimSpace(name); name == "" {
+		return nil, nil
+	}
+	path, _ := lookup("PROFILES_FILE")
+	if path = strings.TrimSpace(path); path == "" {
+		return nil, fmt.Errorf("APP_ENV=%q: PROFILES_FILE is not set", name)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("PROFILES_FILE: %w", err)
+	}
+	var profiles map[string]Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("PROFILES_FILE %s: %w", path, err)
+	}
+	p, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("APP_ENV=%q: no such profile in %s (have %s)", name, path, strings.Join(names, ", "))
+	}
+	p.Name = name
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return &p, nil
+}
+
+// validate checks that every setting is a known variable other than the
+// profile selectors, every solver exists and the labels are well formed.
+func (p Profile) validate() error {
+	known := map[string]bool{}
+	t := reflect.TypeOf(Env{})
+	for i := 0; i < t.NumField(); i++ {
+		for _, ev := range fieldVars(t.Field(i).Tag.Get("env")) {
+			known[ev.name] = true
+		}
+	}
+	for name := range p.Settings {
+		if name == "APP_ENV" || name == "PROFILES_FILE" {
+			return fmt.Errorf("setting %s: can't be set by a profile", name)
+		}
+		if !known[name] {
+			return fmt.Errorf("setting %s: unknown variable", name)
+		}
+	}
+	for _, s := range p.Solvers {
+		if !slices.ContainsFunc(RouteSolvers, func(r RouteSolver) bool { return r.Type == s }) {
+			return fmt.Errorf("solver %q: unknown route solver type", s)
+		}
+	}
+	return p.Endpoints.Labels.Validate()
+}
+
+// lookup wraps the environment's lookup with the profile: its settings win,
+// and DISABLE_<SOLVER> is set for each solver not in Solvers unless the
+// settings say otherwise.
+func (p *Profile) lookup(env func(string) (string, bool)) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		if v, ok := p.Settings[name]; ok {
+			return v, true
+		}
+		if len(p.Solvers) > 0 && strings.HasPrefix(name, "DISABLE_") {
+			for _, s := range RouteSolvers {
+				if name == "DISABLE_"+strings.ToUpper(s.Type) && !slices.Contains(p.Solvers, s.Type) {
+					return "true", true
+				}
+			}
+		}
+		return env(name)
+	}
+}
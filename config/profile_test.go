@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeProfiles(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := writeProfiles(t, `{
+		"staging": {
+			"settings": {"CHECK_INTERVAL_HOURS": "15m", "SLACK_WEBHOOK_URL": "https://hooks.example/staging"},
+			"solvers": ["kyberswap"],
+			"endpoints": {"networks": ["8453"], "labels": {"priority": "p1"}}
+		},
+		"prod": {}
+	}`)
+	env := lookupFrom(map[string]string{
+		"APP_ENV":           "staging",
+		"PROFILES_FILE":     path,
+		"SLACK_WEBHOOK_URL": "https://hooks.example/prod",
+		"DELAY_KYBERSWAP":   "7",
+	})
+
+	p, err := LoadProfile(env)
+	if err != nil || p == nil || p.Name != "staging" {
+		t.Fatalf("profile = %+v, %v", p, err)
+	}
+	e, errs := ParseEnv(p.lookup(env))
+	if len(errs) != 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	// Profile settings win, the rest of the environment still applies.
+	if e.CheckInterval != 15*time.Minute || e.SlackWebhookURL != "https://hooks.example/staging" || e.SolverDelays["kyberswap"] != 7*time.Second {
+		t.Fatalf("env = %+v", e)
+	}
+	if e.SolverDisabled["kyberswap"] || !e.SolverDisabled["odos"] {
+		t.Fatalf("disabled = %v", e.SolverDisabled)
+	}
+
+	if p, err := LoadProfile(lookupFrom(nil)); p != nil || err != nil {
+		t.Fatalf("no APP_ENV = %+v, %v", p, err)
+	}
+	_, err = LoadProfile(lookupFrom(map[string]string{"APP_ENV": "dev", "PROFILES_FILE": path}))
+	if err == nil || !strings.Contains(err.Error(), "have prod, staging") {
+		t.Fatalf("unknown profile error = %v", err)
+	}
+}
+
+func TestLoadProfileRejectsInvalid(t *testing.T) {
+	for body, want := range map[string]string{
+		`{"dev": {"settings": {"CHECK_INTERVAL": "1h"}}}`:   "unknown variable",
+		`{"dev": {"settings": {"APP_ENV": "prod"}}}`:        "can't be set",
+		`{"dev": {"solvers": ["nope"]}}`:                    "unknown route solver",
+		`{"dev": {"endpoints": {"labels": {"Team": "x"}}}}`: "label key",
+	} {
+		_, err := LoadProfile(lookupFrom(map[string]string{"APP_ENV": "dev", "PROFILES_FILE": writeProfiles(t, body)}))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error = %v, want %q", body, err, want)
+		}
+	}
+}
+
+func TestEndpointSelector(t *testing.T) {
+	endpoints := []BaseEndpoint{
+		{Name: "a", Network: "1", Labels: Labels{"priority": "p1"}},
+		{Name: "b", Network: "8453", Labels: Labels{"priority": "P1"}},
+		{Name: "c", Network: "8453"},
+	}
+	if got := (EndpointSelector{}).Select(endpoints); len(got) != 3 {
+		t.Fatalf("empty selector = %+v", got)
+	}
+	got := EndpointSelector{Networks: []string{"8453"}, Labels: Labels{"priority": "p1"}}.Select(endpoints)
+	if len(got) != 1 || got[0].Name != "b" {
+		t.Fatalf("selected = %+v", got)
+	}
+	if got := (EndpointSelector{Names: []string{"a", "c"}}).Select(endpoints); len(got) != 2 {
+		t.Fatalf("by name = %+v", got)
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import "strings"
+
+// EndpointRule is a declarative assertion on a BaseEndpoint, evaluated by
+// internal/rules after a provider handler has parsed a successful response.
+// Zero-valued fields are not checked, so a rule only needs the assertions it
+// cares about, e.g.
+//
+//	Rules: []EndpointRule{{MinReturnAmount: "99000000000000000000000"}},
+//	Rules: []EndpointRule{{Solvers: []string{"kyberswap"}, ForbiddenSources: []string{"uniswap"}}},
+type EndpointRule struct {
+	// Solvers limits the rule to these route solver types; empty means all.
+	Solvers []string
+	// MinReturnAmount is the minimum return amount in raw TokenOut units.
+	MinReturnAmount string
+	// MaxPriceImpactBps is the maximum provider-reported price impact in
+	// basis points. Skipped for providers that don't report price impact.
+	MaxPriceImpactBps float64
+	// RequiredSources must each match at least one route source;
+	// ForbiddenSources must match none. Matching is a case-insensitive
+	// substring test against the provider's own source names (e.g.
+	// "BalancerV3", "Balancer_V3", "balancer-v3-stable"). Skipped for
+	// providers whose responses don't list sources.
+	RequiredSources  []string
+	ForbiddenSources []string
+	// MarketLeadCycles alerts when the market price (all sources) has beaten
+	// the Balancer-only quote on this many consecutive checks, i.e. the pool
+	// is no longer competitive for the pair. Unlike the assertions above it
+	// spans checks, so it alerts without failing the check. Skipped for
+	// providers without a market price call (balancer_sor).
+	MarketLeadCycles int
+}
+
+// AppliesTo reports whether the rule applies to the given route solver type.
+func (r EndpointRule) AppliesTo(solverType string) bool {
+	if len(r.Solvers) == 0 {
+		return true
+	}
+	for _, s := range r.Solvers {
+		if strings.EqualFold(s, solverType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule says when an endpoint group is next checked.
+type Schedule interface {
+	// Next returns the first check time strictly after t.
+	Next(t time.Time) time.Time
+}
+
+// minScheduleInterval bounds how often a schedule may fire, so a typo such
+// as "1s" can't turn a group into a provider load test.
+const minScheduleInterval = 30 * time.Second
+
+// ParseSchedule reads a BaseEndpoint schedule:
+//
+//	15m, 90s          a Go duration: every interval
+//	@every 15m        the same
+//	@hourly, @daily   top of every hour, midnight UTC
+//	*/5 * * * *       a cron expression: minute hour day-of-month month
+//	                  day-of-week, in UTC; fields take *, lists (1,15),
+//	                  ranges (9-17) and steps (*/10, 0-30/5)
+//
+// An empty string is a nil Schedule: the group is checked every
+// CHECK_INTERVAL_HOURS with the rest.
+func ParseSchedule(s string) (Schedule, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return nil, nil
+	case s == "@hourly":
+		s = "0 * * * *"
+	case s == "@daily" || s == "@midnight":
+		s = "0 0 * * *"
+	case strings.HasPrefix(s, "@every "):
+		return parseEvery(strings.TrimSpace(strings.TrimPrefix(s, "@every ")))
+	case !strings.Contains(s, " "):
+		return parseEvery(s)
+	}
+	return parseCron(s)
+}
+
+// everySchedule fires at a fixed interval.
+type everySchedule time.Duration
+
+func parseEvery(s string) (Schedule, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("schedule %q: want a duration (15m), @every, @hourly, @daily or a cron expression", s)
+	}
+	if d < minScheduleInterval {
+		return nil, fmt.Errorf("schedule %q: interval below %s", s, minScheduleInterval)
+	}
+	return everySchedule(d), nil
+}
+
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// cronSchedule is a parsed five-field cron expression; each field is the set
+// of values it allows.
+type cronSchedule struct {
+	minute, hour, dom, month, dow map[int]bool
+	// domAny and dowAny record a "*" day field: as in cron, when both day
+	// fields are restricted a day matching either one fires.
+	domAny, dowAny bool
+}
+
+// cronFields are the fields of a cron expression in order, with their ranges.
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day-of-month", 1, 31},
+	{"month", 1, 12},
+	{"day-of-week", 0, 6},
+}
+
+func parseCron(s string) (Schedule, error) {
+	fields := strings.Fields(s)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("schedule %q: cron expression needs %d fields, got %d", s, len(cronFields), len(fields))
+	}
+	sets := make([]map[int]bool, len(fields))
+	for i, f := range fields {
+		set, err := parseCronField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %s: %v", s, cronFields[i].name, err)
+		}
+		sets[i] = set
+	}
+	c := &cronSchedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+	}
+	if c.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("schedule %q: never fires", s)
+	}
+	return c, nil
+}
+
+// parseCronField expands one field, e.g. "*/15" or "1-5,0".
+func parseCronField(f string, first, last int) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(f, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("bad step in %q", part)
+			}
+			rng, step = part[:i], n
+		}
+		lo, hi := first, last
+		if rng != "*" {
+			var err error
+			if i := strings.Index(rng, "-"); i >= 0 {
+				if lo, err = strconv.Atoi(rng[:i]); err == nil {
+					hi, err = strconv.Atoi(rng[i+1:])
+				}
+			} else if lo, err = strconv.Atoi(rng); err == nil {
+				hi = lo
+				if step > 1 {
+					hi = last
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("bad value %q", part)
+			}
+		}
+		if lo < first || hi > last || lo > hi {
+			return nil, fmt.Errorf("%q outside %d-%d", part, first, last)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// cronHorizon bounds Next's search; an expression with no match within it
+// (e.g. 30 February) never fires.
+const cronHorizon = 5 * 366 * 24 * time.Hour
+
+func (c *cronSchedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	end := t.Add(cronHorizon)
+	for t.Before(end) {
+		switch {
+		case !c.month[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !c.hour[t.Hour()]:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !c.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	at := time.Date(2025, 3, 14, 10, 7, 30, 0, time.UTC) // a Friday
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"15m", at.Add(15 * time.Minute)},
+		{"@every 90s", at.Add(90 * time.Second)},
+		{"@hourly", time.Date(2025, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"*/5 * * * *", time.Date(2025, 3, 14, 10, 10, 0, 0, time.UTC)},
+		{"0,30 9-17 * * *", time.Date(2025, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 6", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)}, // day-of-month or day-of-week
+		{"5/20 * * * *", time.Date(2025, 3, 14, 10, 25, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		s, err := ParseSchedule(c.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", c.spec, err)
+			continue
+		}
+		if got := s.Next(at); !got.Equal(c.want) {
+			t.Errorf("%q: Next = %s, want %s", c.spec, got, c.want)
+		}
+	}
+}
+
+func TestParseScheduleEmpty(t *testing.T) {
+	if s, err := ParseSchedule(" "); s != nil || err != nil {
+		t.Errorf("ParseSchedule(empty) = %v, %v", s, err)
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, spec := range []string{"10s", "soon", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "0 0 30 2 *"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) accepted", spec)
+		}
+	}
+}
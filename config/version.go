@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Version is the release the binary was built from, set at build time with
+// -ldflags "-X go-monitoring/config.Version=v1.2.3". Empty falls back to the
+// module version or VCS revision Go stamps into the binary.
+var Version = ""
+
+// BuildVersion returns Version, else the stamped module version or short VCS
+// revision (with "-dirty" for a modified tree), else "dev".
+func BuildVersion() string {
+	if Version != "" {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	var revision, dirty string
+	for _, s := range info.Settings {
+		switch {
+		case s.Key == "vcs.revision":
+			revision = s.Value
+		case s.Key == "vcs.modified" && s.Value == "true":
+			dirty = "-dirty"
+		}
+	}
+	if revision == "" {
+		return "dev"
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	return revision + dirty
+}
+
+// GetUserAgent returns the User-Agent of outbound provider requests: USER_AGENT
+// when set, otherwise "go-monitoring/<version> (+<USER_AGENT_CONTACT>)", so
+// provider ops teams can identify and allowlist the monitor.
+func GetUserAgent() string {
+	e := Current()
+	if e.UserAgent != "" {
+		return e.UserAgent
+	}
+	if e.UserAgentContact == "" {
+		return "go-monitoring/" + BuildVersion()
+	}
+	return fmt.Sprintf("go-monitoring/%s (+%s)", BuildVersion(), e.UserAgentContact)
+}
@@ -3,9 +3,14 @@ module go-monitoring
 go 1.24.0
 
 require (
+	connectrpc.com/connect v1.19.1
 	github.com/ethereum/go-ethereum v1.17.0
 	github.com/joho/godotenv v1.5.1
+	github.com/lib/pq v1.9.0
+	github.com/mattn/go-sqlite3 v1.14.33
 	github.com/resend/resend-go/v2 v2.16.0
+	golang.org/x/sync v0.18.0
+	google.golang.org/protobuf v1.36.11
 )
 
 require (
@@ -32,6 +37,5 @@ require (
 	go.opentelemetry.io/otel v1.39.0 // indirect
 	go.opentelemetry.io/otel/metric v1.39.0 // indirect
 	go.opentelemetry.io/otel/trace v1.39.0 // indirect
-	golang.org/x/sync v0.18.0 // indirect
 	golang.org/x/sys v0.39.0 // indirect
 )
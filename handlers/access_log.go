@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/metrics"
+)
+
+var (
+	httpRequests = metrics.NewCounter("http_requests_total",
+		"Dashboard and API requests by route pattern, method and status.", "route", "method", "status")
+	httpRequestSeconds = metrics.NewCounter("http_request_duration_seconds_total",
+		"Seconds spent serving requests by route pattern and method; divided by http_requests_total, the mean.", "route", "method")
+)
+
+// logRequests wraps a route's handler, counting each request and its
+// duration in the http_request metrics and, with ACCESS_LOG, logging its
+// method, path, status, duration and client IP. /metrics scrapes are counted
+// but not logged.
+func logRequests(rt Route, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		started := clock.Now()
+		sw := &statusWriter{ResponseWriter: w}
+		h(sw, r)
+		took := clock.Since(started)
+
+		status := sw.Status()
+		method := r.Method
+		if !slices.Contains(rt.Methods, method) {
+			method = "other" // keep arbitrary methods out of the labels
+		}
+		httpRequests.Inc(rt.Pattern, method, strconv.Itoa(status))
+		httpRequestSeconds.Add(took.Seconds(), rt.Pattern, method)
+
+		if !config.GetAccessLogEnabled() || rt.Pattern == "/metrics" {
+			return
+		}
+		color := config.ColorBlue
+		switch {
+		case status >= 500:
+			color = config.ColorRed
+		case status >= 400:
+			color = config.ColorYellow
+		}
+		logs.Printf("%s[HTTP]%s method=%s path=%q route=%q status=%d duration=%s client=%s\n", color, config.ColorReset,
+			r.Method, r.URL.Path, rt.Pattern, status, took.Round(time.Microsecond), clientIP(r))
+	}
+}
+
+// clientIP returns the address a request came from: with
+// TRUST_PROXY_HEADERS, Fly-Client-IP or the first X-Forwarded-For address,
+// else the connection's.
+func clientIP(r *http.Request) string {
+	if config.GetTrustProxyHeaders() {
+		if ip := strings.TrimSpace(r.Header.Get("Fly-Client-IP")); ip != "" {
+			return ip
+		}
+		if first, _, _ := strings.Cut(r.Header.Get("X-Forwarded-For"), ","); strings.TrimSpace(first) != "" {
+			return strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// statusWriter records the status a handler responds with.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusWriter) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusWriter) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (s *statusWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// Status returns the response status; 200 when the handler wrote nothing.
+func (s *statusWriter) Status() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
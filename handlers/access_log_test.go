@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/internal/metrics"
+)
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:4321"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	if got := clientIP(req); got != "10.0.0.1" {
+		t.Errorf("untrusted client IP = %s", got)
+	}
+	t.Setenv("TRUST_PROXY_HEADERS", "true")
+	if got := clientIP(req); got != "203.0.113.7" {
+		t.Errorf("forwarded client IP = %s", got)
+	}
+	req.Header.Set("Fly-Client-IP", "198.51.100.2")
+	if got := clientIP(req); got != "198.51.100.2" {
+		t.Errorf("Fly client IP = %s", got)
+	}
+}
+
+func TestLogRequestsCountsRequests(t *testing.T) {
+	t.Setenv("ACCESS_LOG", "false")
+	serve(httptest.NewRequest(http.MethodGet, "/check/x", nil))
+	serve(httptest.NewRequest("BREW", "/check/x", nil))
+
+	var out bytes.Buffer
+	metrics.WriteText(&out)
+	for _, want := range []string{
+		`http_requests_total{route="/check/{endpoint...}",method="other",status="405"}`,
+		`http_request_duration_seconds_total{route="/check/{endpoint...}",method="other"}`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics lack %s", want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/apitoken"
+)
+
+// tokenGrant is what a request's bearer token allows for a scope.
+type tokenGrant int
+
+const (
+	tokenUnknown tokenGrant = iota // no token, or not ADMIN_TOKEN or an API token
+	tokenDenied                    // an API token without the scope
+	tokenGranted
+)
+
+// tokenGrants checks the bearer token in request headers h for scope:
+// ADMIN_TOKEN grants every scope, an API token (see package apitoken) its
+// own.
+func tokenGrants(h http.Header, scope apitoken.Scope) tokenGrant {
+	if hasBearerToken(h, config.GetAdminToken()) {
+		return tokenGranted
+	}
+	secret, ok := strings.CutPrefix(h.Get("Authorization"), "Bearer ")
+	if !ok {
+		return tokenUnknown
+	}
+	t, ok := apitoken.Lookup(secret)
+	switch {
+	case !ok:
+		return tokenUnknown
+	case !apitoken.Allows(t, scope):
+		return tokenDenied
+	}
+	return tokenGranted
+}
+
+// hasScope reports whether r's bearer token grants scope.
+func hasScope(r *http.Request, scope apitoken.Scope) bool {
+	return tokenGrants(r.Header, scope) == tokenGranted
+}
+
+// requireScope is Validate's check of a route's Scope with API_REQUIRE_TOKEN
+// set: 401 without a known token, 403 when the token lacks the scope.
+func requireScope(w http.ResponseWriter, r *http.Request, scope apitoken.Scope) bool {
+	if !config.GetAPIRequireToken() {
+		return true
+	}
+	switch tokenGrants(r.Header, scope) {
+	case tokenUnknown:
+		w.Header().Set("WWW-Authenticate", `Bearer realm="go-monitoring"`)
+		writeError(w, http.StatusUnauthorized, "an API token is required")
+		return false
+	case tokenDenied:
+		writeError(w, http.StatusForbidden, "token lacks the "+string(scope)+" scope")
+		return false
+	}
+	return true
+}
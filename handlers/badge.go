@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/collector"
+)
+
+// badgeWindow is the uptime a badge shows.
+const badgeWindow = 7 * 24 * time.Hour
+
+// BadgeHandler serves /badge/{solver}.svg: a shields.io-style badge with
+// the route solver's uptime over the last 7 days, across its base and
+// discovered rows, for READMEs and partner docs. The checks come from the
+// store, archived hours included, when there is one (endpointSeries), so
+// the week survives restarts. {solver} matches like /solver/{name}; "no
+// data" shows before the solver has counted checks.
+func BadgeHandler(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("badge"), ".svg")
+	if !ok {
+		http.Error(w, "Badge not found", http.StatusNotFound)
+		return
+	}
+	solver, ok := findRouteSolver(name)
+	if !ok {
+		http.Error(w, "Solver not found", http.StatusNotFound)
+		return
+	}
+
+	var total collector.HistorySummary
+	since := clock.Now().Add(-badgeWindow)
+	for _, e := range solverEndpoints(solver.Type) {
+		points, err := endpointSeries(e.Name, since, time.Hour)
+		if err != nil {
+			http.Error(w, "query series: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, p := range points {
+			total.Add(collector.HistorySummary{Checks: p.Checks, Up: p.Up, Down: p.Down})
+		}
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	value, color := badgeValue(total)
+	fmt.Fprint(w, badgeSVG(solver.Name+" uptime 7d", value, color))
+}
+
+// badgeValue returns a badge's uptime text, e.g. "99.2%", and its color.
+func badgeValue(s collector.HistorySummary) (text, color string) {
+	u := s.Uptime()
+	if u < 0 {
+		return "no data", "#9f9f9f"
+	}
+	text = strings.TrimSuffix(fmt.Sprintf("%.1f", u*100), ".0") + "%"
+	switch {
+	case u >= 0.99:
+		return text, "#4c1"
+	case u >= 0.95:
+		return text, "#97ca00"
+	case u >= 0.90:
+		return text, "#dfb317"
+	case u >= 0.80:
+		return text, "#fe7d37"
+	default:
+		return text, "#e05d44"
+	}
+}
+
+// badgeTextWidth estimates the width of s in 11px Verdana, the badge font.
+func badgeTextWidth(s string) int {
+	return len([]rune(s))*7 + 10
+}
+
+// badgeSVG renders a flat two-part badge: label on grey, value on color.
+func badgeSVG(label, value, color string) string {
+	lw, vw := badgeTextWidth(label), badgeTextWidth(value)
+	label, value = html.EscapeString(label), html.EscapeString(value)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[7]d" y="14">%[4]s</text>`+
+		`<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[8]d" y="14">%[5]s</text></g></svg>`,
+		lw+vw, lw, vw, label, value, color, lw/2, lw+vw/2)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/monitor"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestBadgeHandler(t *testing.T) {
+	now := time.Now()
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-GHO/USDC", BaseName: "GHO/USDC", Network: "42161", RouteSolver: "odos"}})
+	collector.SetHistory("Odos-GHO/USDC", []collector.CheckRecord{
+		{At: now.Add(-8 * 24 * time.Hour), Status: "down"}, // outside the window
+		{At: now.Add(-3 * time.Hour), Status: "down"},
+		{At: now.Add(-2 * time.Hour), Status: "up"},
+		{At: now.Add(-time.Hour), Status: "up"},
+		{At: now, Status: "up"},
+	})
+	t.Cleanup(func() {
+		collector.SetEndpoints(nil)
+		collector.SetHistory("Odos-GHO/USDC", nil)
+	})
+
+	rec := serve(httptest.NewRequest(http.MethodGet, "/badge/odos.svg", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/svg+xml" {
+		t.Fatalf("status %d, Content-Type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"<svg", "Odos uptime 7d: 75%", `fill="#e05d44"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("badge lacks %s:\n%s", want, body)
+		}
+	}
+
+	if body := serve(httptest.NewRequest(http.MethodGet, "/badge/kyberswap.svg", nil)).Body.String(); !strings.Contains(body, "no data") {
+		t.Errorf("a solver without history should read no data:\n%s", body)
+	}
+	for _, path := range []string{"/badge/odos", "/badge/nope.svg"} {
+		if rec := serve(httptest.NewRequest(http.MethodGet, path, nil)); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status %d, want 404", path, rec.Code)
+		}
+	}
+}
+
+func TestBadgeHandlerReadsTheStore(t *testing.T) {
+	st := store.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	monitor.SetStore(st)
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-GHO/USDC", BaseName: "GHO/USDC", Network: "42161", RouteSolver: "odos"}})
+	t.Cleanup(func() {
+		monitor.SetStore(nil)
+		collector.SetEndpoints(nil)
+	})
+	// Checks from before a restart: in the store, not in memory.
+	now := time.Now()
+	for i, status := range []string{"up", "up", "up", "down"} {
+		at := now.Add(-time.Duration(i+1) * 24 * time.Hour)
+		if err := st.SaveResult(store.EndpointState{Name: "Odos-GHO/USDC", LastStatus: status, LastChecked: at}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	body := serve(httptest.NewRequest(http.MethodGet, "/badge/odos.svg", nil)).Body.String()
+	if !strings.Contains(body, "Odos uptime 7d: 75%") {
+		t.Errorf("badge should count the stored week:\n%s", body)
+	}
+}
+
+func TestBadgeValue(t *testing.T) {
+	for _, tc := range []struct {
+		s           collector.HistorySummary
+		text, color string
+	}{
+		{collector.HistorySummary{Checks: 100, Up: 100}, "100%", "#4c1"},
+		{collector.HistorySummary{Checks: 1000, Up: 962}, "96.2%", "#97ca00"},
+		{collector.HistorySummary{Checks: 10, Up: 9}, "90%", "#dfb317"},
+		{collector.HistorySummary{}, "no data", "#9f9f9f"},
+	} {
+		if text, color := badgeValue(tc.s); text != tc.text || color != tc.color {
+			t.Errorf("%+v: %q %q, want %q %q", tc.s, text, color, tc.text, tc.color)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go-monitoring/config"
+)
+
+// configResponse is the JSON response of ConfigHandler.
+type configResponse struct {
+	Settings []config.Setting `json:"settings"`
+}
+
+// ConfigHandler serves /api/v1/config: every environment setting with its
+// effective value, default and description (see config.Env). Secrets such as
+// API keys, tokens and RPC URLs are redacted when set.
+func ConfigHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(configResponse{Settings: config.Current().Dump(config.Lookup)})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/collector"
+)
+
+// ConfigExportHandler serves the effective runtime configuration at
+// /api/v1/config/export as YAML: intervals, solver settings (enabled flag,
+// delay, networks), discovery settings and every generated endpoint row. The
+// output is meant to reproduce a production setup locally, so it carries no
+// secrets — API keys and RPC URLs are reported as set / unset only.
+func ConfigExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="go-monitoring-config.yaml"`)
+
+	var b strings.Builder
+	writeConfigYAML(&b, clock.Now(), collector.GetEndpointsCopy(), collector.GetDiscoveredEndpointsCopy())
+	fmt.Fprint(w, b.String())
+}
+
+// writeConfigYAML emits the export document. Strings are always double-quoted
+// so addresses, amounts and names like "0x" round-trip as strings.
+func writeConfigYAML(b *strings.Builder, now time.Time, base, discovered []collector.Endpoint) {
+	fmt.Fprintf(b, "generated_at: %s\n", yamlString(now.UTC().Format(time.RFC3339)))
+	fmt.Fprintf(b, "check_interval: %s\n", yamlString(config.GetCheckInterval().String()))
+	fmt.Fprintf(b, "discovery_interval_hours: %d\n", config.GetDiscoveryIntervalHours())
+	fmt.Fprintf(b, "discovery_test_pools_per_group: %d\n", config.GetDiscoveryTestPoolsPerGroup())
+	fmt.Fprintf(b, "email_notifications: %t\n", config.GetEmailNotificationsEnabled())
+
+	enabled := make(map[string]bool)
+	for _, s := range config.GetEnabledRouteSolvers() {
+		enabled[s.Type] = true
+	}
+	b.WriteString("route_solvers:\n")
+	for _, s := range config.RouteSolvers {
+		fmt.Fprintf(b, "  - name: %s\n", yamlString(s.Name))
+		fmt.Fprintf(b, "    type: %s\n", yamlString(s.Type))
+		fmt.Fprintf(b, "    enabled: %t\n", enabled[s.Type])
+		fmt.Fprintf(b, "    delay_seconds: %s\n", strconv.FormatFloat(config.GetRouteSolverDelay(s.Type).Seconds(), 'f', -1, 64))
+		fmt.Fprintf(b, "    supported_networks: %s\n", yamlStringList(s.SupportedNetworks))
+	}
+
+	b.WriteString("discovery:\n")
+	for _, d := range config.DiscoveryConfigs {
+		fmt.Fprintf(b, "  - network: %s\n", yamlString(d.Network))
+		fmt.Fprintf(b, "    enabled: %t\n", d.Enabled)
+		fmt.Fprintf(b, "    tvl_threshold_usd: %s\n", strconv.FormatFloat(d.TVLThresholdUSD, 'f', -1, 64))
+		fmt.Fprintf(b, "    trade_percent: %s\n", strconv.FormatFloat(d.TradePercent, 'f', -1, 64))
+	}
+
+	b.WriteString("rpc_urls_configured:\n")
+	for _, network := range config.RPCNetworks() {
+		fmt.Fprintf(b, "  %s: %t\n", yamlString(network), config.GetRPCURL(network) != "")
+	}
+
+	b.WriteString("endpoints:\n")
+	writeEndpointsYAML(b, base)
+	b.WriteString("discovered_endpoints:\n")
+	writeEndpointsYAML(b, discovered)
+}
+
+// writeEndpointsYAML emits the input side of each endpoint row. Result fields
+// (status, prices, paths) are left out — they are not configuration.
+func writeEndpointsYAML(b *strings.Builder, eps []collector.Endpoint) {
+	for _, e := range eps {
+		fmt.Fprintf(b, "  - name: %s\n", yamlString(e.Name))
+		if e.ID != "" {
+			fmt.Fprintf(b, "    id: %s\n", yamlString(e.ID))
+		}
+		fmt.Fprintf(b, "    base_name: %s\n", yamlString(e.BaseName))
+		fmt.Fprintf(b, "    route_solver: %s\n", yamlString(e.RouteSolver))
+		fmt.Fprintf(b, "    network: %s\n", yamlString(e.Network))
+		fmt.Fprintf(b, "    token_in: %s\n", yamlString(e.TokenIn))
+		fmt.Fprintf(b, "    token_out: %s\n", yamlString(e.TokenOut))
+		fmt.Fprintf(b, "    token_in_decimals: %d\n", e.TokenInDecimals)
+		fmt.Fprintf(b, "    token_out_decimals: %d\n", e.TokenOutDecimals)
+		fmt.Fprintf(b, "    swap_amount: %s\n", yamlString(e.SwapAmount))
+		fmt.Fprintf(b, "    expected_pool: %s\n", yamlString(e.ExpectedPool))
+		fmt.Fprintf(b, "    expected_no_hops: %d\n", e.ExpectedNoHops)
+		if e.PoolType != "" {
+			fmt.Fprintf(b, "    pool_type: %s\n", yamlString(e.PoolType))
+		}
+		if e.HookType != "" {
+			fmt.Fprintf(b, "    hook_type: %s\n", yamlString(e.HookType))
+		}
+		if e.Variant != "" {
+			fmt.Fprintf(b, "    variant: %s\n", yamlString(e.Variant))
+		}
+		if len(e.Labels) > 0 {
+			fmt.Fprintf(b, "    labels: %s\n", yamlStringMap(e.Labels))
+		}
+		if e.Schedule != "" {
+			fmt.Fprintf(b, "    schedule: %s\n", yamlString(e.Schedule))
+		}
+	}
+}
+
+// yamlString renders s as a double-quoted YAML scalar. Go's quoted form is a
+// subset of YAML's double-quoted escapes.
+func yamlString(s string) string {
+	return strconv.Quote(s)
+}
+
+// yamlStringMap renders a flow mapping of quoted strings, sorted by key.
+func yamlStringMap(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for _, k := range config.Labels(m).Keys() {
+		pairs = append(pairs, yamlString(k)+": "+yamlString(m[k]))
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// yamlStringList renders a flow sequence of quoted strings.
+func yamlStringList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = yamlString(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestWriteConfigYAMLQuotesEndpointFields(t *testing.T) {
+	eps := []collector.Endpoint{{
+		Name:        "0x-Base-Boosted-StableSurge(GHO/USDC)",
+		BaseName:    "Base-Boosted-StableSurge(GHO/USDC)",
+		RouteSolver: "0x",
+		Network:     "8453",
+		TokenIn:     "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913",
+		SwapAmount:  "100000000000",
+		LastStatus:  "down",
+		Message:     "should not be exported",
+	}}
+
+	var b strings.Builder
+	writeConfigYAML(&b, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), eps, nil)
+	out := b.String()
+
+	for _, want := range []string{
+		`generated_at: "2025-01-01T00:00:00Z"`,
+		`  - name: "0x-Base-Boosted-StableSurge(GHO/USDC)"`,
+		`    route_solver: "0x"`,
+		`    network: "8453"`,
+		`    swap_amount: "100000000000"`,
+		"discovered_endpoints:\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("export missing %q", want)
+		}
+	}
+	if strings.Contains(out, "should not be exported") {
+		t.Error("export leaked result fields")
+	}
+}
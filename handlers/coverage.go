@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/monitoring/collector"
+)
+
+// CoverageHandler renders /coverage: which pool kinds each enabled provider
+// quotes Balancer V3 on per network, since when, when it was last confirmed
+// by an up check, and what the checks report now (WIP integrations show as
+// info with their message).
+func CoverageHandler(w http.ResponseWriter, r *http.Request) {
+	endpoints := append(collector.GetEndpointsCopy(), collector.GetDiscoveredEndpointsCopy()...)
+	cells := monitor.Coverage(config.GetEnabledRouteSolvers(), endpoints, monitor.SupportMatrix())
+
+	fmt.Fprint(w, "<html><head>\n<title>Coverage &middot; API Monitor</title>\n")
+	fmt.Fprint(w, solverStyle)
+	fmt.Fprint(w, `<h1>Balancer V3 coverage</h1>`)
+	fmt.Fprintf(w, `<div class="subhead"><a href="/">&larr; Back to monitor</a> &middot; Generated %s</div>`,
+		clock.Now().UTC().Format("2006-01-02 15:04 MST"))
+	if len(cells) == 0 {
+		fmt.Fprint(w, `<div class="placeholder">No provider has been checked yet.</div></body></html>`)
+		return
+	}
+	fmt.Fprint(w, coverageTable(cells))
+	fmt.Fprint(w, `</body></html>`)
+}
+
+// coverageSummary is the /api/v1/coverage response. Networks names every
+// chain ID in the providers' lists.
+type coverageSummary struct {
+	GeneratedAt time.Time                `json:"generatedAt"`
+	PoolTypes   []string                 `json:"poolTypes"`
+	Networks    map[string]string        `json:"networks"`
+	Providers   []monitor.SolverCoverage `json:"providers"`
+}
+
+// CoverageSummaryHandler serves /api/v1/coverage: per enabled provider,
+// which Balancer V3 pool types (monitor.PoolTypes: Stable, StableSurge,
+// GyroE, ..., Boosted) pass their latest checks on which networks, and where
+// they are checked but fail. ?solver= limits it to one provider (type or
+// name).
+func CoverageSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	solvers := config.GetEnabledRouteSolvers()
+	if name := r.URL.Query().Get("solver"); name != "" {
+		solver, ok := findRouteSolver(name)
+		if !ok {
+			http.Error(w, "Solver not found", http.StatusNotFound)
+			return
+		}
+		solvers = []config.RouteSolver{solver}
+	}
+	endpoints := append(collector.GetEndpointsCopy(), collector.GetDiscoveredEndpointsCopy()...)
+	resp := coverageSummary{
+		GeneratedAt: clock.Now().UTC(),
+		PoolTypes:   monitor.PoolTypes,
+		Networks:    map[string]string{},
+		Providers:   monitor.CoverageSummary(solvers, endpoints),
+	}
+	for _, p := range resp.Providers {
+		for _, c := range p.PoolTypes {
+			for _, n := range append(c.Passing, c.Failing...) {
+				resp.Networks[n] = config.NetworkName(n)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// coverageTable renders cells ordered as monitor.Coverage orders them as
+// one row per provider and pool kind and one column per network, networks
+// by name.
+func coverageTable(cells []monitor.CoverageCell) string {
+	var networks []string
+	seen := map[string]bool{}
+	for _, c := range cells {
+		if !seen[c.Network] {
+			seen[c.Network] = true
+			networks = append(networks, c.Network)
+		}
+	}
+	sort.Slice(networks, func(i, j int) bool { return config.NetworkName(networks[i]) < config.NetworkName(networks[j]) })
+
+	var b strings.Builder
+	b.WriteString(`<table><thead><tr><th>Provider</th><th>Pool kind</th>`)
+	for _, n := range networks {
+		fmt.Fprintf(&b, `<th>%s</th>`, html.EscapeString(config.NetworkName(n)))
+	}
+	b.WriteString(`</tr></thead><tbody>`)
+	for i := 0; i < len(cells); {
+		row := map[string]monitor.CoverageCell{}
+		j := i
+		for ; j < len(cells) && cells[j].Solver == cells[i].Solver && cells[j].PoolKind == cells[i].PoolKind; j++ {
+			row[cells[j].Network] = cells[j]
+		}
+		fmt.Fprintf(&b, `<tr><td><a href="/solver/%s">%s</a></td><td>%s</td>`,
+			html.EscapeString(cells[i].Solver), html.EscapeString(cells[i].Solver), html.EscapeString(poolKindLabel(cells[i].PoolKind)))
+		for _, n := range networks {
+			b.WriteString(coverageCell(row[n]))
+		}
+		b.WriteString(`</tr>`)
+		i = j
+	}
+	b.WriteString(`</tbody></table>`)
+	return b.String()
+}
+
+// coverageCell renders one network's cell: whether the provider supports
+// the pool kind there now, with the first-seen date and when support was
+// last confirmed underneath; the row's message (e.g. a WIP integration) is
+// the tooltip. Empty when no row checks it and support was never seen.
+func coverageCell(c monitor.CoverageCell) string {
+	if c.Rows == 0 && c.FirstSeen.IsZero() {
+		return `<td></td>`
+	}
+	label, class := "supported", "status-up"
+	switch {
+	case c.Rows == 0:
+		label, class = "not checked", "status-disabled"
+	case c.Status == "up":
+	case collector.IsDownStatus(c.Status):
+		label, class = "down", "status-down"
+		if c.FirstSeen.IsZero() {
+			label = "never supported"
+		}
+	case c.Status == "info":
+		label, class = "WIP", "status-unknown"
+	case c.Status == collector.StatusNotApplicable:
+		label, class = "n/a", "status-disabled"
+	default:
+		label, class = "unknown", "status-disabled"
+	}
+	detail := "never confirmed"
+	if !c.FirstSeen.IsZero() {
+		detail = fmt.Sprintf("since %s &middot; confirmed %s", c.FirstSeen.UTC().Format("2006-01-02"), html.EscapeString(formatTimeAgo(c.LastConfirmed)))
+	}
+	return fmt.Sprintf(`<td class="%s" title="%s">%s<br><span class="checks">%s</span></td>`,
+		class, html.EscapeString(c.Message), label, detail)
+}
+
+// poolKindLabel names a sources.Kind* pool kind, "other" for unclassified
+// pools.
+func poolKindLabel(kind string) string {
+	if kind == "" {
+		return "other"
+	}
+	return kind
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/monitor"
+)
+
+func TestCoverageTable(t *testing.T) {
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	got := coverageTable([]monitor.CoverageCell{
+		{Solver: "kyberswap", Network: "1", PoolKind: "gyro", Rows: 1, Status: "info", Message: "Kyber <WIP>"},
+		{Solver: "kyberswap", Network: "1", PoolKind: "stable", Rows: 1, Status: "up", FirstSeen: at, LastConfirmed: at},
+		{Solver: "kyberswap", Network: "8453", PoolKind: "stable", Rows: 1, Status: "down"},
+	})
+	for _, want := range []string{
+		`<th>base</th><th>ethereum</th>`,
+		`<td>gyro</td><td></td><td class="status-unknown" title="Kyber &lt;WIP&gt;">WIP<br><span class="checks">never confirmed</span></td></tr>`,
+		`<td class="status-up" title="">supported<br><span class="checks">since 2026-03-01`,
+		`<td class="status-down" title="">never supported<br>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("table lacks %s:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "<tr><td>"); n != 2 {
+		t.Errorf("%d rows, want 2", n)
+	}
+}
+
+func TestCoverageSummaryHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	CoverageSummaryHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/coverage?solver=nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown solver status = %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	CoverageSummaryHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/coverage?solver=kyberswap", nil))
+	var got coverageSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.PoolTypes) != len(monitor.PoolTypes) || len(got.Providers) != 1 || got.Providers[0].Solver != "kyberswap" {
+		t.Fatalf("summary = %+v", got)
+	}
+}
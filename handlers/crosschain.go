@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/crosschain"
+	"go-monitoring/monitoring/collector"
+)
+
+// CrossChainHandler renders /crosschain: for each pair quoted on more than
+// one network (GHO/USDC on Mainnet, Arbitrum and Base), every network's best
+// Balancer quote with the gas its execution would cost, ranked by the rate
+// left after gas (crosschain.Compare).
+func CrossChainHandler(w http.ResponseWriter, r *http.Request) {
+	pairs := crosschain.Compare(collector.GetEndpointsCopy())
+
+	fmt.Fprint(w, "<html><head>\n<title>Cross-chain &middot; API Monitor</title>\n")
+	fmt.Fprint(w, solverStyle)
+	fmt.Fprint(w, `<h1>Cross-chain competitiveness</h1>`)
+	fmt.Fprintf(w, `<div class="subhead"><a href="/">&larr; Back to monitor</a> &middot; Generated %s</div>`,
+		clock.Now().UTC().Format("2006-01-02 15:04 MST"))
+	if len(pairs) == 0 {
+		fmt.Fprint(w, `<div class="placeholder">No pair has up quotes on more than one network yet.</div></body></html>`)
+		return
+	}
+	fmt.Fprint(w, `<p>Rates are USD out per USD in at Balancer API token prices. Net rate takes the estimated gas of executing the route off the output.</p>`)
+	for _, p := range pairs {
+		fmt.Fprint(w, crossChainTable(p))
+	}
+	fmt.Fprint(w, `</body></html>`)
+}
+
+// crossChainTable renders one pair's networks, best net rate first.
+func crossChainTable(p crosschain.Pair) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<h2>%s</h2>`, html.EscapeString(p.Name))
+	b.WriteString(`<table><thead><tr><th>Network</th><th>Solver</th><th>Hops</th><th>Rate</th><th>Est. gas</th><th>Net rate</th><th>Behind best</th></tr></thead><tbody>`)
+	for _, q := range p.Quotes {
+		fmt.Fprintf(&b, `<tr><td title="%s">%s</td><td><a href="/solver/%s">%s</a></td><td>%d</td><td>%.5f</td><td>%s</td><td>%.5f</td><td>%s</td></tr>`,
+			html.EscapeString(q.BaseName), html.EscapeString(config.NetworkName(q.Network)),
+			html.EscapeString(q.Solver), html.EscapeString(q.Solver), q.Hops, q.Rate,
+			gasCell(q), q.NetRate, behindCell(q.BehindBps))
+	}
+	b.WriteString(`</tbody></table>`)
+	return b.String()
+}
+
+// gasCell shows a quote's estimated gas, its price and USD cost, e.g.
+// "150k @ 20 gwei = $6.00"; the cost reads "unpriced" without a native
+// token price.
+func gasCell(q crosschain.Quote) string {
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(q.GasPrice.Wei), big.NewFloat(1e9)).Float64()
+	cost := "unpriced"
+	if q.GasKnown {
+		cost = formatGasUSD(q.GasUSD)
+	}
+	return fmt.Sprintf(`%dk @ %s gwei = %s`, q.GasUnits/1000, strconv.FormatFloat(gwei, 'g', 3, 64), cost)
+}
+
+// formatGasUSD formats a gas cost to the cent, or to 1/100 cent below a
+// cent: formatUSD rounds it to whole dollars.
+func formatGasUSD(v float64) string {
+	if v < 0.01 {
+		return fmt.Sprintf("$%.4f", v)
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
+
+// behindCell shows how far a quote trails the pair's best net rate.
+func behindCell(bps float64) string {
+	if bps <= 0 {
+		return `<span class="status-up">best</span>`
+	}
+	return fmt.Sprintf("%.1f bps", bps)
+}
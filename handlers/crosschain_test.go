@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/internal/crosschain"
+	"go-monitoring/monitoring/providers"
+)
+
+func TestCrossChainTable(t *testing.T) {
+	got := crossChainTable(crosschain.Pair{Name: "GHO/USDC", Quotes: []crosschain.Quote{
+		{Network: "8453", Solver: "odos", Hops: 1, GasUnits: 150_000, GasPrice: providers.GasPrice{Wei: big.NewInt(1e6)},
+			GasUSD: 0.0003, GasKnown: true, Rate: 0.9999, NetRate: 0.9999},
+		{Network: "1", Solver: "paraswap", Hops: 2, GasUnits: 240_000, GasPrice: providers.GasPrice{Wei: big.NewInt(20e9)},
+			Rate: 1.0005, NetRate: 0.9945, BehindBps: 54.005},
+	}})
+	for _, want := range []string{
+		`<h2>GHO/USDC</h2>`,
+		`<a href="/solver/odos">odos</a></td><td>1</td><td>0.99990</td><td>150k @ 0.001 gwei = $0.0003</td><td>0.99990</td><td><span class="status-up">best</span>`,
+		`<td>240k @ 20 gwei = unpriced</td><td>0.99450</td><td>54.0 bps</td>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("table lacks %s:\n%s", want, got)
+		}
+	}
+}
+
+func TestCrossChainHandlerWithoutPairs(t *testing.T) {
+	rec := serve(httptest.NewRequest(http.MethodGet, "/crosschain", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "No pair has up quotes") {
+		t.Errorf("status %d, body %s", rec.Code, rec.Body.String())
+	}
+}
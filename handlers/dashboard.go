@@ -1,36 +1,51 @@
 package handlers
 
 import (
+	"bytes"
 	"fmt"
-	"math/big"
+	"html"
+	"io"
 	"net/http"
+	"net/url"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	"go-monitoring/internal/collector"
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
 	"go-monitoring/internal/discovery"
+	"go-monitoring/internal/i18n"
 	"go-monitoring/internal/monitor"
+	"go-monitoring/internal/worker"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/providers"
 )
 
-// CheckEndpointHandler triggers a check for a specific endpoint. Tries the
+// CheckEndpointHandler triggers a check for the endpoint whose ID or name is
+// the {endpoint} of /check/{endpoint} (see collector.ResolveEndpoint). Tries the
 // BaseEndpoints store first, falling back to the discovered-endpoints store
-// so the "Check Now" button works for both sections of the dashboard.
+// so the "Check Now" button works for both sections of the dashboard. An
+// endpoint checked by hand within MANUAL_CHECK_INTERVAL gets 429 with
+// Retry-After, which the button counts down.
 func CheckEndpointHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	name := r.URL.Path[len("/check/"):]
-
-	runCheck := func(endpoint *collector.Endpoint) {
-		monitor.CheckAPI(endpoint, nil) // nil options will trigger both calls
+	name, ok := collector.ResolveEndpoint(r.PathValue("endpoint"))
+	if !ok {
+		http.Error(w, "Endpoint not found", http.StatusNotFound)
+		return
 	}
-
-	if collector.UpdateEndpointByName(name, runCheck) {
-		http.Redirect(w, r, "/", http.StatusSeeOther)
+	if wait := reserveManualCheck(name, clock.Now()); wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(wait)))
+		http.Error(w, throttledMessage(name, wait), http.StatusTooManyRequests)
 		return
 	}
-	if collector.UpdateDiscoveredEndpointByName(name, runCheck) {
+	if _, _, _, ok := checkEndpointNow(name); ok {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
@@ -38,22 +53,76 @@ func CheckEndpointHandler(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "Endpoint not found", http.StatusNotFound)
 }
 
+// checkEndpointNow runs both provider calls for the named row, base store
+// first, and returns the row afterwards, the check's result and whether the
+// row is discovered.
+func checkEndpointNow(name string) (collector.Endpoint, providers.CheckResult, bool, bool) {
+	var (
+		checked collector.Endpoint
+		res     providers.CheckResult
+	)
+	runCheck := func(endpoint *collector.Endpoint) {
+		res = monitor.CheckAPI(endpoint, nil) // nil options will trigger both calls
+		checked = *endpoint
+	}
+
+	if collector.UpdateEndpointByName(name, runCheck) {
+		return checked, res, false, true
+	}
+	if collector.UpdateDiscoveredEndpointByName(name, runCheck) {
+		return checked, res, true, true
+	}
+	return collector.Endpoint{}, providers.CheckResult{}, false, false
+}
+
 // DashboardHandler handles the main dashboard page. Renders two tables with
 // identical layout: the BaseEndpoints results (driven by the hourly loop) and
 // the discovered test set results (driven by the daily discovery loop).
+//
+// Filter, sort and uptime-range state come from the query string (see
+// dashboardFilter) so any view can be shared as a link. Rendered views are
+// reused for DASHBOARD_CACHE_TTL while the collector reports no change (see
+// dashboardCache), so a refreshing wallboard costs one render per change.
 func DashboardHandler(w http.ResponseWriter, r *http.Request) {
+	gen := collector.Generation()
+	if page, ok := dashboardPages.get(r.URL.RawQuery, gen); ok {
+		w.Write(page)
+		return
+	}
+	var buf bytes.Buffer
+	renderDashboard(&buf, parseDashboardFilter(r.URL.Query()))
+	dashboardPages.put(r.URL.RawQuery, gen, buf.Bytes())
+	w.Write(buf.Bytes())
+}
+
+// renderDashboard writes the whole dashboard page for filter.
+func renderDashboard(w io.Writer, filter dashboardFilter) {
+	base := collector.GetEndpointsCopy()
+	discovered := collector.GetDiscoveredEndpointsCopy()
+
 	fmt.Fprint(w, dashboardHeader)
-	fmt.Fprintf(w, `<div style="margin-bottom:12px;font-size:0.95em;"><a href="/pools" style="color:#1565c0;text-decoration:none;">Discovered pools &rarr;</a> <span style="color:#666;">(last refresh: %s)</span></div>`,
+	fmt.Fprintf(w, "<script>const initialSort = { column: %d, direction: '%s' };</script>", filter.sortColumn(), filter.Dir)
+	fmt.Fprintf(w, `<div style="margin-bottom:12px;font-size:0.95em;"><a href="/pools" style="color:#1565c0;text-decoration:none;">Discovered pools &rarr;</a> <span style="color:#666;">(last refresh: %s)</span> &middot; <a href="/coverage" style="color:#1565c0;text-decoration:none;">Coverage &rarr;</a> &middot; <a href="/crosschain" style="color:#1565c0;text-decoration:none;">Cross-chain &rarr;</a></div>`,
 		formatTimeAgo(discovery.LastSuccessAt()))
-
-	renderEndpointsTable(w, "endpoints-table", collector.GetEndpointsCopy())
+	renderDeliveryProblems(w, notify.DeliveryProblems(clock.Now()))
+	renderPoolMigrations(w, discovery.GetPoolMigrations())
+	renderFilterForm(w, filter, append(append([]collector.Endpoint{}, base...), discovered...))
+
+	window := filter.RangeDuration()
+	switch filtered := filter.apply(base); {
+	case len(base) == 0:
+		fmt.Fprint(w, `<div style="padding:16px;background:#fff8e1;border:1px solid #ffe082;border-radius:4px;color:#5d4037;margin-bottom:12px;">No endpoints configured. Check that at least one route solver is enabled (<code>DISABLE_*</code> env vars) and supports a <code>BaseEndpoints</code> network.</div>`)
+	case len(filtered) == 0:
+		fmt.Fprint(w, `<div style="padding:16px;background:#fff8e1;border:1px solid #ffe082;border-radius:4px;color:#5d4037;margin-bottom:12px;">No endpoints match the current filters. <a href="/">Clear filters</a></div>`)
+	default:
+		renderEndpointsTable(w, "endpoints-table", filtered, filter.Range, window)
+	}
 
 	fmt.Fprintf(w, `<h2 style="margin-top:32px;">Discovered test set (daily)</h2>`)
-	discovered := collector.GetDiscoveredEndpointsCopy()
 	if len(discovered) == 0 {
 		fmt.Fprint(w, `<div style="padding:16px;background:#fff8e1;border:1px solid #ffe082;border-radius:4px;color:#5d4037;margin-bottom:12px;">No discovered test rows yet; first daily run pending.</div>`)
 	} else {
-		renderEndpointsTable(w, "discovered-table", discovered)
+		renderEndpointsTable(w, "discovered-table", filter.apply(discovered), filter.Range, window)
 	}
 
 	fmt.Fprintln(w, "</body></html>")
@@ -63,7 +132,9 @@ func DashboardHandler(w http.ResponseWriter, r *http.Request) {
 // endpoints grouped by BaseName. Both the BaseEndpoints and discovered
 // sections share this implementation so the layout, sorting, and per-row
 // highlighting logic can't drift.
-func renderEndpointsTable(w http.ResponseWriter, tableID string, endpoints []collector.Endpoint) {
+//
+// rangeLabel / window select the uptime column's history window.
+func renderEndpointsTable(w io.Writer, tableID string, endpoints []collector.Endpoint, rangeLabel string, window time.Duration) {
 	groups := make(map[string][]collector.Endpoint)
 	for _, e := range endpoints {
 		groups[e.BaseName] = append(groups[e.BaseName], e)
@@ -76,16 +147,19 @@ func renderEndpointsTable(w http.ResponseWriter, tableID string, endpoints []col
 
 	fmt.Fprintf(w, `<table id="%s" border="1"><thead><tr>`, tableID)
 	fmt.Fprint(w, `<th class='name-column'>Name</th><th>Status</th><th>Message</th>`)
-	fmt.Fprintf(w, `<th class='sortable-header' onclick="sortTable('%s', 3)">Balancer Price<span class='sort-arrow' id='%s-arrow-3'>&#8597;</span></th>`, tableID, tableID)
-	fmt.Fprintf(w, `<th class='sortable-header' onclick="sortTable('%s', 4)">Market Price<span class='sort-arrow' id='%s-arrow-4'>&#8597;</span></th>`, tableID, tableID)
-	fmt.Fprint(w, `<th>Last Checked</th><th>Actions</th></tr></thead><tbody>`)
+	fmt.Fprintf(w, `<th class='sortable-header' onclick="sortTable('%s', 3, true)">Balancer Price<span class='sort-arrow' id='%s-arrow-3'>&#8597;</span></th>`, tableID, tableID)
+	fmt.Fprintf(w, `<th class='sortable-header' onclick="sortTable('%s', 4, true)">Market Price<span class='sort-arrow' id='%s-arrow-4'>&#8597;</span></th>`, tableID, tableID)
+	fmt.Fprintf(w, `<th title='Expected hops / hops in the last returned route'>Hops</th><th>Last Checked</th><th>Uptime (%s)</th><th>Actions</th></tr></thead><tbody>`, html.EscapeString(rangeLabel))
 
 	for _, baseName := range baseNames {
 		groupEndpoints := groups[baseName]
 		networkName := getNetworkName(groupEndpoints[0].Network)
 		poolLink := fmt.Sprintf("https://balancer.fi/pools/%s/v3/%s", networkName, groupEndpoints[0].ExpectedPool)
-		fmt.Fprintf(w, "<tr class='base-name-row'><td colspan='7'>%s<br><span style='font-weight: normal; font-size: 0.9em; margin-top: 10px; display: inline-block;'>In: %s<br>Out: %s<br>Pool: <a href='%s' target='_blank'>%s</a><br>Amount: %s</span></td></tr>",
+		fmt.Fprintf(w, "<tr class='base-name-row'><td colspan='9'>%s%s%s%s<br><span style='font-weight: normal; font-size: 0.9em; margin-top: 10px; display: inline-block;'>In: %s<br>Out: %s<br>Pool: <a href='%s' target='_blank'>%s</a><br>Amount: %s</span></td></tr>",
 			baseName,
+			poolMetadataDisplay(groupEndpoints[0]),
+			labelsDisplay(groupEndpoints[0].Labels),
+			groupStatusDisplay(baseName),
 			groupEndpoints[0].TokenIn,
 			groupEndpoints[0].TokenOut,
 			poolLink,
@@ -94,30 +168,167 @@ func renderEndpointsTable(w http.ResponseWriter, tableID string, endpoints []col
 
 		sorted := make([]collector.Endpoint, len(groupEndpoints))
 		copy(sorted, groupEndpoints)
-		sort.Slice(sorted, func(i, j int) bool {
-			return parseBigInt(sorted[i].ReturnAmount).Cmp(parseBigInt(sorted[j].ReturnAmount)) > 0
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Amounts().Return.Cmp(sorted[j].Amounts().Return) > 0
 		})
 
+		since := clock.Now().Add(-window)
 		for _, endpoint := range sorted {
-			renderSolverRow(w, endpoint)
+			renderSolverRow(w, endpoint, formatUptime(collector.SummarizeHistory(collector.GetHistory(endpoint.Name), since)))
 		}
 	}
 
 	fmt.Fprint(w, `</tbody></table>`)
 }
 
-// renderSolverRow writes one solver-level <tr> with status, return amount,
-// market/on-chain price, deviation highlighting, and the Check Now button.
-func renderSolverRow(w http.ResponseWriter, endpoint collector.Endpoint) {
-	statusClass := "status-unknown"
-	switch endpoint.LastStatus {
-	case "up":
-		statusClass = "status-up"
-	case "down":
-		statusClass = "status-down"
-	case "disabled":
-		statusClass = "status-disabled"
+// renderPoolMigrations lists detected ExpectedPool migrations above the
+// tables; nothing is written when there are none.
+func renderPoolMigrations(w io.Writer, migrations []discovery.PoolMigration) {
+	if len(migrations) == 0 {
+		return
+	}
+	fmt.Fprint(w, `<div style="padding:12px 16px;background:#fff8e1;border:1px solid #ffe082;border-radius:4px;color:#5d4037;margin-bottom:12px;"><strong>Pool migrations detected</strong><ul style="margin:6px 0 0 0;">`)
+	for _, m := range migrations {
+		action := "suggested: update <code>ExpectedPool</code> in <code>config.BaseEndpoints</code>"
+		if m.Applied {
+			action = "applied to the running monitor; update <code>config.BaseEndpoints</code> to keep it after a restart"
+		}
+		networkName := getNetworkName(m.Network)
+		fmt.Fprintf(w, `<li>%s (%s): <a href="https://balancer.fi/pools/%s/v3/%s" target="_blank">%s</a> &rarr; <a href="https://balancer.fi/pools/%s/v3/%s" target="_blank">%s</a> %s &middot; %s &middot; %s</li>`,
+			html.EscapeString(m.BaseName),
+			html.EscapeString(networkName),
+			networkName, html.EscapeString(m.OldPool), html.EscapeString(m.OldPool),
+			networkName, html.EscapeString(m.NewPool), html.EscapeString(m.NewPool),
+			html.EscapeString(m.NewPoolName),
+			html.EscapeString(m.Reason),
+			action)
+	}
+	fmt.Fprint(w, `</ul></div>`)
+}
+
+// priceImpactDisplay renders the provider-reported price impact under the
+// return amount, highlighted when above the alert threshold; "" when the
+// provider doesn't report it.
+func priceImpactDisplay(e collector.Endpoint, thresholdBps float64) string {
+	if !e.HasPriceImpact {
+		return ""
+	}
+	class := "price-impact"
+	if thresholdBps > 0 && e.PriceImpactBps > thresholdBps {
+		class += " high"
+	}
+	return fmt.Sprintf("<span class='%s'>impact %.1f bps</span>", class, e.PriceImpactBps)
+}
+
+// surgeDisplay renders a StableSurge pool's fee at the latest check under
+// the return amount, highlighted while the pool is surging, with the full
+// state as the tooltip; "" for other pools.
+func surgeDisplay(e collector.Endpoint) string {
+	if e.Surge.IsZero() {
+		return ""
+	}
+	class := "price-impact"
+	if e.Surge.Surging() {
+		class += " high"
+	}
+	return fmt.Sprintf("<span class='%s' title='%s'>surge fee %.2f%%</span>", class, html.EscapeString(e.Surge.String()), e.Surge.Fee*100)
+}
+
+// balancerRankDisplay renders Balancer V3's rank among the market price
+// response's per-source quotes, highlighted outside the alert's top N; ""
+// when the provider doesn't quote sources separately.
+func balancerRankDisplay(e collector.Endpoint, topN int) string {
+	if e.RankedSources == 0 {
+		return ""
+	}
+	class := "price-impact"
+	if topN > 0 && (e.BalancerRank == 0 || e.BalancerRank > topN) {
+		class += " high"
+	}
+	if e.BalancerRank == 0 {
+		return fmt.Sprintf("<span class='%s'>Balancer unranked of %d</span>", class, e.RankedSources)
+	}
+	return fmt.Sprintf("<span class='%s'>Balancer #%d of %d</span>", class, e.BalancerRank, e.RankedSources)
+}
+
+// poolMetadataDisplay renders the pool's Balancer API name, type, version
+// and tokens for a group row, or "" until the metadata is cached.
+func poolMetadataDisplay(e collector.Endpoint) string {
+	meta, ok := discovery.CachedPoolMetadata(e.Network, e.ExpectedPool)
+	if !ok || meta.Name == "" {
+		return ""
+	}
+	return fmt.Sprintf(" &middot; %s <span style='font-weight: normal; font-size: 0.85em; color: #555;'>(%s &middot; v%d &middot; %s)</span>",
+		html.EscapeString(meta.Name),
+		html.EscapeString(meta.Type),
+		meta.Version,
+		html.EscapeString(meta.TokenSymbols()))
+}
+
+// labelsDisplay renders an endpoint's labels as links that filter the
+// dashboard to that label.
+func labelsDisplay(labels config.Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, k := range labels.Keys() {
+		pair := k + "=" + labels[k]
+		fmt.Fprintf(&b, " <a class='label' href='/?labels=%s'>%s</a>", url.QueryEscape(pair), html.EscapeString(pair))
+	}
+	return b.String()
+}
+
+// groupStatusDisplay renders the BaseName's composite status from its last
+// check cycle, e.g. "3 of 6 failing".
+func groupStatusDisplay(baseName string) string {
+	g, ok := collector.GetGroupStatus(baseName)
+	if !ok || g.Status == collector.GroupUnknown {
+		return ""
+	}
+	text := "all up"
+	if g.Failing > 0 {
+		text = fmt.Sprintf("%d of %d failing", g.Failing, g.Total)
+	}
+	return fmt.Sprintf(" <span class='group-status %s'>%s</span>", g.Status, text)
+}
+
+// notSupported fills a cell for a feature the provider lacks (see
+// providers.Capabilities), so it doesn't read as a missing result.
+const notSupported = "<span class='unsupported'>not supported</span>"
+
+// hopsCell renders the expected hop count over the hop count of the last
+// returned route (its longest path), e.g. "1 / 2", highlighted when they
+// differ: a multi-hop route can still pass checks that only look for the
+// pool. "—" stands for a count that isn't known, "not supported" for a
+// provider that reports no route.
+func hopsCell(e collector.Endpoint) string {
+	expected, actual := "&mdash;", "&mdash;"
+	if e.ExpectedNoHops > 0 {
+		expected = fmt.Sprint(e.ExpectedNoHops)
+	}
+	if caps, ok := monitor.SolverCapabilities(e.RouteSolver); ok && !caps.Route {
+		actual = notSupported
+	}
+	if e.Route.IsEmpty() {
+		return fmt.Sprintf("<td class='hops'>%s / %s</td>", expected, actual)
 	}
+	hops := e.Route.HopCount()
+	actual = fmt.Sprint(hops)
+	if len(e.Route.Paths) > 1 {
+		actual += fmt.Sprintf(" <span class='checks'>(%d paths)</span>", len(e.Route.Paths))
+	}
+	if e.ExpectedNoHops > 0 && hops != e.ExpectedNoHops {
+		return fmt.Sprintf("<td class='hops hop-mismatch' title='Expected %d hops, last route had %d'>%s / %s</td>", e.ExpectedNoHops, hops, expected, actual)
+	}
+	return fmt.Sprintf("<td class='hops'>%s / %s</td>", expected, actual)
+}
+
+// renderSolverRow writes one solver-level <tr> with status, return amount,
+// market/on-chain price, deviation highlighting, expected vs actual hops,
+// uptime, and the Check Now button.
+func renderSolverRow(w io.Writer, endpoint collector.Endpoint, uptime string) {
+	statusClass := statusClassFor(endpoint.LastStatus)
 
 	returnAmountDisplay := "N/A"
 	if endpoint.ReturnAmount != "" {
@@ -144,68 +355,107 @@ func renderSolverRow(w http.ResponseWriter, endpoint collector.Endpoint) {
 		}
 	} else if endpoint.MarketPrice != "" {
 		marketPriceDisplay = endpoint.MarketPrice
+	} else if caps, ok := monitor.SolverCapabilities(endpoint.RouteSolver); ok && !caps.MarketPrice {
+		marketPriceDisplay = notSupported
 	}
 
-	returnAmountBig := parseBigInt(endpoint.ReturnAmount)
-	var priceBig *big.Int
-	if endpoint.RouteSolver == "balancer_sor" && endpoint.OnChainPrice != "" && endpoint.OnChainQueryError == "" {
-		priceBig = parseBigInt(endpoint.OnChainPrice)
-	} else {
-		priceBig = parseBigInt(endpoint.MarketPrice)
-	}
-
+	amounts := endpoint.Amounts()
 	if endpoint.RouteSolver == "balancer_sor" && endpoint.OnChainPrice != "" {
-		if returnAmountBig.Sign() > 0 && priceBig.Sign() > 0 {
-			diff := new(big.Int).Abs(new(big.Int).Sub(returnAmountBig, priceBig))
-			diffFloat := new(big.Float).SetInt(diff)
-			priceFloat := new(big.Float).SetInt(priceBig)
-			if priceFloat.Sign() > 0 {
-				percent := new(big.Float).Quo(diffFloat, priceFloat)
-				percent.Mul(percent, big.NewFloat(100))
-				pctVal, _ := percent.Float64()
-				if pctVal > 0.5 {
-					returnAmountClass = " class='price-warning'"
-					marketPriceClass = " class='price-warning'"
-				} else if returnAmountBig.Cmp(priceBig) > 0 {
-					returnAmountClass = " class='highest-value'"
-				} else if priceBig.Cmp(returnAmountBig) > 0 {
-					marketPriceClass = " class='highest-value'"
-				}
+		if pct, ok := amounts.Return.DeviationPct(amounts.Price); ok {
+			switch {
+			case pct > 0.5:
+				returnAmountClass = " class='price-warning'"
+				marketPriceClass = " class='price-warning'"
+			case amounts.Return.Cmp(amounts.Price) > 0:
+				returnAmountClass = " class='highest-value'"
+			case amounts.Price.Cmp(amounts.Return) > 0:
+				marketPriceClass = " class='highest-value'"
 			}
 		}
-	} else if returnAmountBig.Sign() > 0 || priceBig.Sign() > 0 {
-		if returnAmountBig.Cmp(priceBig) > 0 {
+	} else {
+		switch amounts.Return.Cmp(amounts.Price) {
+		case 1:
 			returnAmountClass = " class='highest-value'"
-		} else if priceBig.Cmp(returnAmountBig) > 0 {
+		case -1:
 			marketPriceClass = " class='highest-value'"
 		}
 	}
+	returnAmountClass += amountTitle(amounts.Return, endpoint.TokenOutDecimals)
+	if marketPriceDisplay == amounts.Price.Raw {
+		marketPriceClass += amountTitle(amounts.Price, endpoint.TokenOutDecimals)
+	}
 
-	fmt.Fprintf(w, "<tr class='solver-row'><td class='name-column'>%s</td><td class='%s'>%s</td><td>%s</td><td%s>%s</td><td%s>%s%s</td><td>%s</td><td><button class='check-button' onclick='checkEndpoint(\"%s\")'>Check Now</button></td></tr>",
+	fmt.Fprintf(w, "<tr class='solver-row'><td class='name-column'><a href='/solver/%s'>%s</a>%s</td><td class='%s'%s>%s%s%s</td><td>%s</td><td%s>%s%s</td><td%s>%s%s</td>%s<td>%s</td><td class='uptime'>%s</td><td>%s</td></tr>",
+		endpoint.RouteSolver,
 		endpoint.SolverName,
+		noteMarker(endpoint),
 		statusClass,
+		stateChangeTitle(i18n.For(i18n.DefaultLang), endpoint),
 		endpoint.LastStatus,
+		downForDisplay(i18n.For(i18n.DefaultLang), endpoint),
+		regionsDisplay(endpoint),
 		endpoint.Message,
 		returnAmountClass,
 		returnAmountDisplay,
+		priceImpactDisplay(endpoint, config.GetPriceImpactAlertBps())+surgeDisplay(endpoint),
 		marketPriceClass,
 		marketPriceDisplay,
-		priceLabel,
+		priceLabel+balancerRankDisplay(endpoint, config.GetBalancerRankAlertTopN()),
+		hopsCell(endpoint),
 		formatTimeAgo(endpoint.LastChecked),
-		endpoint.Name)
+		uptime,
+		checkButton(endpoint))
+}
+
+// amountTitle returns a title attribute with a base-unit amount in whole
+// tokens, e.g. " title='≈ 1234.5'"; "" when the decimals are unknown or the
+// amount isn't a number.
+func amountTitle(a collector.Amount, decimals int) string {
+	if decimals <= 0 || !a.Valid() {
+		return ""
+	}
+	return fmt.Sprintf(" title='≈ %s'", a.Format(decimals))
 }
 
-// parseBigInt parses a decimal string into a *big.Int. Empty or "N/A" map to
-// zero so sorting / comparison stay well-defined.
-func parseBigInt(s string) *big.Int {
-	v := new(big.Int)
-	if s == "" || s == "N/A" {
-		return v
+// downForDisplay renders the "down for 3h 12m" annotation shown under the
+// status while an endpoint is in a down streak, in l's language.
+func downForDisplay(l i18n.Localizer, endpoint collector.Endpoint) string {
+	d := endpoint.DownFor(clock.Now())
+	if d <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("<br><span class='down-for'>%s</span>", html.EscapeString(l.T("status.down_for", "duration", collector.FormatDuration(d))))
+}
+
+// regionsDisplay renders regional worker results under the status, e.g.
+// "sin down · iad up", highlighted when a region disagrees with the local
+// result; "" when no worker has reported the endpoint.
+func regionsDisplay(endpoint collector.Endpoint) string {
+	results := collector.GetRegionResults(endpoint.Name)
+	if len(results) == 0 {
+		return ""
+	}
+	parts := make([]string, len(results))
+	class := "regions"
+	for i, r := range results {
+		parts[i] = fmt.Sprintf("<span title='%s'>%s %s</span>",
+			html.EscapeString(r.Message+" ("+formatTimeAgo(r.CheckedAt)+")"),
+			html.EscapeString(r.Region), html.EscapeString(r.Status))
+		if worker.Diverges(endpoint.LastStatus, r.Status) {
+			class = "regions diverge"
+		}
 	}
-	if _, ok := v.SetString(s, 10); !ok {
-		return new(big.Int)
+	return fmt.Sprintf("<br><span class='%s'>%s</span>", class, strings.Join(parts, " &middot; "))
+}
+
+// stateChangeTitle returns a title attribute with the time of the last status
+// transition, so hovering the status cell shows how long it has held.
+func stateChangeTitle(l i18n.Localizer, endpoint collector.Endpoint) string {
+	if endpoint.LastStateChange.IsZero() {
+		return ""
 	}
-	return v
+	return fmt.Sprintf(" title='%s'", html.EscapeString(l.T("status.since",
+		"status", l.Status(endpoint.LastStatus), "time", endpoint.LastStateChange.UTC().Format("2006-01-02 15:04 MST"))))
 }
 
 // dashboardHeader is the static <html><head>...<body><h1> prefix. Extracted
@@ -216,9 +466,22 @@ const dashboardHeader = `<html><head>
 			.status-down { background-color: #FFB6C1; }
 			.status-unknown { background-color: #FFA500; }
 			.status-disabled { background-color: #D3D3D3; }
+			.down-for { font-size: 0.85em; color: #8b0000; white-space: nowrap; }
+			.uptime { white-space: nowrap; }
+			.hops { white-space: nowrap; }
+			.hop-mismatch { background-color: #FFB347; font-weight: bold; }
+			.checks { color: #888; font-size: 0.85em; }
+			.unsupported { color: #888; font-style: italic; }
+			.filters { margin: 0 0 16px 0; display: flex; gap: 12px; flex-wrap: wrap; align-items: flex-end; }
+			.filters label { font-size: 0.9em; color: #333; display: flex; flex-direction: column; gap: 2px; }
+			.filters select, .filters input { padding: 4px 6px; font-size: 0.95em; }
 			.highest-value { background-color: #90EE90; font-weight: bold; }
 			.price-warning { background-color: #FFB347; font-weight: bold; }
 			.price-error { background-color: #FF6B6B; color: white; font-weight: bold; }
+			.price-impact { display: block; font-size: 0.85em; color: #666; font-weight: normal; }
+			.price-impact.high { color: #b71c1c; font-weight: bold; }
+			.regions { font-size: 0.8em; color: #555; font-weight: normal; white-space: nowrap; }
+			.regions.diverge { color: #b71c1c; font-weight: bold; }
 			table { border-collapse: collapse; width: 100%; margin-bottom: 24px; }
 			th, td { padding: 8px; text-align: left; }
 			.name-column { white-space: nowrap; }
@@ -238,6 +501,11 @@ const dashboardHeader = `<html><head>
 			}
 			.check-button:hover { background-color: #45a049; }
 			.base-name-row { background-color: #e6f3ff; font-weight: bold; }
+			.group-status { font-weight: normal; font-size: 0.8em; border-radius: 3px; padding: 0 4px; color: #fff; }
+			.group-status.all-up { background: #4CAF50; }
+			.group-status.partial { background: #f0a500; }
+			.group-status.all-down { background: #d9534f; }
+			.label { font-weight: normal; font-size: 0.8em; background: #fff; border: 1px solid #9cc3e6; border-radius: 3px; padding: 0 4px; color: #245; text-decoration: none; }
 			.solver-row { background-color: #f9f9f9; }
 			.sortable-header { cursor: pointer; user-select: none; position: relative; padding-right: 20px; }
 			.sortable-header:hover { background-color: #e0e0e0; }
@@ -247,11 +515,55 @@ const dashboardHeader = `<html><head>
 		<script>
 			const sortState = {};
 
-			function checkEndpoint(name) {
-				fetch('/check/' + name, { method: 'POST' }).then(() => window.location.reload());
+			// checkEndpoint runs a manual check, with the session's token when
+			// the monitor requires one (API_REQUIRE_TOKEN), asking for it once.
+			function checkEndpoint(button) {
+				button.disabled = true;
+				const token = sessionStorage.getItem('adminToken');
+				const headers = token ? { 'Authorization': 'Bearer ' + token } : {};
+				fetch('/check/' + encodeURIComponent(button.dataset.endpoint), { method: 'POST', headers: headers }).then(r => {
+					if (r.status === 401 || r.status === 403) {
+						sessionStorage.removeItem('adminToken');
+						const entered = prompt('API token (trigger-check scope)');
+						button.disabled = false;
+						if (entered) {
+							sessionStorage.setItem('adminToken', entered);
+							checkEndpoint(button);
+						}
+						return;
+					}
+					if (r.status === 429) {
+						countdown(button, parseInt(r.headers.get('Retry-After'), 10) || 30);
+						return;
+					}
+					window.location.reload();
+				}).catch(() => { button.disabled = false; });
 			}
 
-			function sortTable(tableId, column) {
+			// countdown keeps a Check Now button disabled, showing the seconds
+			// left, until its endpoint may be checked by hand again.
+			function countdown(button, seconds) {
+				button.disabled = true;
+				const tick = () => {
+					if (seconds <= 0) {
+						button.disabled = false;
+						button.textContent = 'Check Now';
+						return;
+					}
+					button.textContent = 'Wait ' + seconds + 's';
+					seconds--;
+					setTimeout(tick, 1000);
+				};
+				tick();
+			}
+			document.addEventListener('DOMContentLoaded', () => {
+				document.querySelectorAll('.check-button[data-wait]').forEach(b => countdown(b, parseInt(b.dataset.wait, 10)));
+			});
+
+			// sortTable sorts solver rows within each group. fromUser marks a
+			// header click, which also records the sort in the URL so the
+			// current view stays shareable.
+			function sortTable(tableId, column, fromUser) {
 				const table = document.getElementById(tableId);
 				if (!table) return;
 				const tbody = table.querySelector('tbody');
@@ -292,9 +604,11 @@ const dashboardHeader = `<html><head>
 					group.solvers.sort((a, b) => {
 						const aVal = a.cells[column].textContent.trim();
 						const bVal = b.cells[column].textContent.trim();
-						if (aVal === 'N/A' && bVal === 'N/A') return 0;
-						if (aVal === 'N/A') return 1;
-						if (bVal === 'N/A') return -1;
+						const aNone = aVal === 'N/A' || aVal === 'not supported';
+						const bNone = bVal === 'N/A' || bVal === 'not supported';
+						if (aNone && bNone) return 0;
+						if (aNone) return 1;
+						if (bNone) return -1;
 						let aNum, bNum;
 						try { aNum = BigInt(aVal); bNum = BigInt(bVal); }
 						catch (e) { aNum = BigInt(0); bNum = BigInt(0); }
@@ -303,6 +617,18 @@ const dashboardHeader = `<html><head>
 					});
 				});
 
+				if (fromUser) {
+					const params = new URLSearchParams(window.location.search);
+					params.set('sort', column === 3 ? 'balancer' : 'market');
+					params.set('dir', state.direction);
+					history.replaceState(null, '', '?' + params.toString());
+					const form = document.querySelector('form.filters');
+					if (form) {
+						form.elements['sort'].value = params.get('sort');
+						form.elements['dir'].value = state.direction;
+					}
+				}
+
 				tbody.innerHTML = '';
 				groups.forEach(group => {
 					tbody.appendChild(group.header);
@@ -314,8 +640,11 @@ const dashboardHeader = `<html><head>
 				setTimeout(function() {
 					document.querySelectorAll('table').forEach(t => {
 						if (!t.id) return;
-						sortState[t.id] = { column: 4, direction: 'asc' };
-						sortTable(t.id, 4);
+						// sortTable flips the direction for the same column,
+						// so seed it with the opposite of the wanted one.
+						const flipped = initialSort.direction === 'asc' ? 'desc' : 'asc';
+						sortState[t.id] = { column: initialSort.column, direction: flipped };
+						sortTable(t.id, initialSort.column);
 					});
 				}, 100);
 			});
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+)
+
+// maxCachedPages bounds dashboardCache; each distinct query string (filters,
+// sort, range) is one entry.
+const maxCachedPages = 64
+
+// dashboardPages caches rendered dashboard pages for DashboardHandler.
+var dashboardPages = &dashboardCache{pages: map[string]cachedPage{}}
+
+// dashboardCache holds rendered dashboard pages by query string. A page is
+// served again while it is younger than DASHBOARD_CACHE_TTL and the
+// collector's Generation is the one it was rendered at; delivery problems
+// and pool migrations, which the generation doesn't track, are at most one
+// TTL stale.
+type dashboardCache struct {
+	mu    sync.Mutex
+	pages map[string]cachedPage
+}
+
+type cachedPage struct {
+	gen  uint64
+	at   time.Time
+	body []byte
+}
+
+// get returns the page cached for query at generation gen.
+func (c *dashboardCache) get(query string, gen uint64) ([]byte, bool) {
+	ttl := config.GetDashboardCacheTTL()
+	if ttl <= 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.pages[query]
+	if !ok || p.gen != gen || clock.Since(p.at) >= ttl {
+		return nil, false
+	}
+	return p.body, true
+}
+
+// put caches body as the page for query rendered at generation gen. When
+// full, the cache starts over rather than tracking recency.
+func (c *dashboardCache) put(query string, gen uint64, body []byte) {
+	if config.GetDashboardCacheTTL() <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pages) >= maxCachedPages {
+		clear(c.pages)
+	}
+	c.pages[query] = cachedPage{gen: gen, at: clock.Now(), body: body}
+}
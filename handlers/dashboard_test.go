@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/internal/monitor"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+func TestDashboardEmptyStates(t *testing.T) {
+	collector.SetEndpoints(nil)
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	rec := httptest.NewRecorder()
+	DashboardHandler(rec, httptest.NewRequest("GET", "/", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "No endpoints configured") || strings.Contains(body, `id="endpoints-table"`) {
+		t.Fatalf("empty store should render the no-endpoints notice, got:\n%s", body)
+	}
+
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-X", BaseName: "X", Network: "1", RouteSolver: "odos", LastStatus: "up"}})
+	rec = httptest.NewRecorder()
+	DashboardHandler(rec, httptest.NewRequest("GET", "/?solver=kyberswap", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "No endpoints match the current filters") {
+		t.Fatalf("filtered-out store should render the no-match notice, got:\n%s", body)
+	}
+}
+
+func TestHopsCell(t *testing.T) {
+	hop := collector.RouteHop{TokenIn: "0xa", TokenOut: "0xb", Venues: []collector.RouteVenue{{Pool: "0xp"}}}
+	if got := hopsCell(collector.Endpoint{ExpectedNoHops: 1}); got != "<td class='hops'>1 / &mdash;</td>" {
+		t.Fatalf("no route = %q", got)
+	}
+	oneHop := collector.RouteGraph{Paths: []collector.RoutePath{{Hops: []collector.RouteHop{hop}}}}
+	if got := hopsCell(collector.Endpoint{ExpectedNoHops: 1, Route: oneHop}); got != "<td class='hops'>1 / 1</td>" {
+		t.Fatalf("match = %q", got)
+	}
+	split := collector.RouteGraph{Paths: []collector.RoutePath{{Hops: []collector.RouteHop{hop}}, {Hops: []collector.RouteHop{hop, hop}}}}
+	got := hopsCell(collector.Endpoint{ExpectedNoHops: 1, Route: split})
+	if !strings.Contains(got, "hop-mismatch") || !strings.Contains(got, "1 / 2 <span class='checks'>(2 paths)</span>") {
+		t.Fatalf("mismatch = %q", got)
+	}
+}
+
+func TestDashboardCachesUntilEndpointsChange(t *testing.T) {
+	t.Setenv("DASHBOARD_CACHE_TTL", "1m")
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-X", BaseName: "X", Network: "1", RouteSolver: "odos", LastStatus: "up", Message: "first"}})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	render := func() string {
+		rec := httptest.NewRecorder()
+		DashboardHandler(rec, httptest.NewRequest("GET", "/?range=24h", nil))
+		return rec.Body.String()
+	}
+	first := render()
+	if !strings.Contains(first, "first") {
+		t.Fatalf("page lacks the endpoint's message:\n%s", first)
+	}
+	if render() != first {
+		t.Fatal("unchanged store should serve the cached page")
+	}
+	collector.UpdateEndpointByName("Odos-X", func(e *collector.Endpoint) { e.Message = "second" })
+	if body := render(); !strings.Contains(body, "second") {
+		t.Fatalf("a changed endpoint should re-render the page, got:\n%s", body)
+	}
+}
+
+func TestSolverRowHighlightsHigherAmount(t *testing.T) {
+	var buf strings.Builder
+	renderSolverRow(&buf, collector.Endpoint{RouteSolver: "odos", ReturnAmount: "1000000000000000000001", MarketPrice: "999999999999999999999"}, "")
+	if !strings.Contains(buf.String(), "<td class='highest-value'>1000000000000000000001") {
+		t.Fatalf("the higher Balancer quote should be highlighted:\n%s", buf.String())
+	}
+}
+
+func TestUnsupportedFeatureCells(t *testing.T) {
+	saved := monitor.GlobalRegistry
+	t.Cleanup(func() { monitor.GlobalRegistry = saved })
+	monitor.GlobalRegistry = providers.NewRegistry()
+	monitor.GlobalRegistry.Register("quoteonly", providers.ProviderConfig{Capabilities: providers.Capabilities{SourceWhitelist: true}})
+
+	e := collector.Endpoint{RouteSolver: "quoteonly", ReturnAmount: "100", ExpectedNoHops: 1}
+	var buf strings.Builder
+	renderSolverRow(&buf, e, "")
+	if got := strings.Count(buf.String(), notSupported); got != 2 {
+		t.Fatalf("market price and hops should read not supported, got %d in:\n%s", got, buf.String())
+	}
+
+	// Unregistered solvers keep the empty cells.
+	e.RouteSolver = "odos"
+	if got := hopsCell(e); got != "<td class='hops'>1 / &mdash;</td>" {
+		t.Fatalf("unknown solver hops = %q", got)
+	}
+}
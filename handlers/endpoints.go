@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/rpc/monitoringv1"
+)
+
+// endpointsPage is the /api/v1/endpoints response. Total counts every row
+// the filters match, across pages. Each row is an Endpoint in its protobuf
+// JSON form.
+type endpointsPage struct {
+	Endpoints  []json.RawMessage `json:"endpoints"`
+	Total      int               `json:"total"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+// EndpointsHandler serves /api/v1/endpoints: the monitored rows in the
+// MonitoringService Endpoint shape, ordered by ID (then name) and paged with
+// ?limit= and ?cursor=. The dashboard's filters apply (?network=, ?solver=,
+// ?q=, ?status=, ?labels=); ?discovered=include adds discovered rows and
+// ?discovered=only lists just them.
+func EndpointsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	p, err := parsePageParams(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f := parseDashboardFilter(q)
+	if f.labelsInvalid {
+		http.Error(w, "labels must be key=value pairs", http.StatusBadRequest)
+		return
+	}
+
+	var rows []*monitoringv1.Endpoint
+	discovered := q.Get("discovered")
+	switch discovered {
+	case "", "include", "only":
+	default:
+		http.Error(w, "discovered must be include or only", http.StatusBadRequest)
+		return
+	}
+	if discovered != "only" {
+		for _, e := range f.apply(collector.GetEndpointsCopy()) {
+			rows = append(rows, toRPCEndpoint(e, false))
+		}
+	}
+	if discovered != "" {
+		for _, e := range f.apply(collector.GetDiscoveredEndpointsCopy()) {
+			rows = append(rows, toRPCEndpoint(e, true))
+		}
+	}
+
+	page, next := paginate(rows, endpointKey, p)
+	res := endpointsPage{Endpoints: []json.RawMessage{}, Total: len(rows), NextCursor: next}
+	for _, e := range page {
+		b, err := protojson.Marshal(e)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.Endpoints = append(res.Endpoints, b)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// endpointKey orders rows by their stable ID; the name breaks ties between
+// rows without one.
+func endpointKey(e *monitoringv1.Endpoint) string {
+	return e.GetId() + "\x00" + e.GetName()
+}
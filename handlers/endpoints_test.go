@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+// decodedPage is an endpointsPage with the fields of its rows the tests read.
+type decodedPage struct {
+	Endpoints []struct {
+		ID         string `json:"id"`
+		Discovered bool   `json:"discovered"`
+	} `json:"endpoints"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"nextCursor"`
+}
+
+func getEndpointsPage(t *testing.T, url string) decodedPage {
+	t.Helper()
+	rec := serve(httptest.NewRequest(http.MethodGet, url, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: status %d: %s", url, rec.Code, rec.Body.String())
+	}
+	var page decodedPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+func TestEndpointsHandlerPagesInIDOrder(t *testing.T) {
+	collector.SetEndpoints([]collector.Endpoint{
+		{Name: "Odos-C", ID: "odos-1-c", Network: "1", RouteSolver: "odos", LastStatus: "up"},
+		{Name: "Odos-A", ID: "odos-1-a", Network: "1", RouteSolver: "odos", LastStatus: "down"},
+		{Name: "Kyber-B", ID: "kyberswap-42161-b", Network: "42161", RouteSolver: "kyberswap", LastStatus: "up"},
+	})
+	collector.SetDiscoveredEndpoints([]collector.Endpoint{
+		{Name: "Odos-D", ID: "odos-1-d", Network: "1", RouteSolver: "odos", LastStatus: "up"},
+	}, nil)
+	t.Cleanup(func() {
+		collector.SetEndpoints(nil)
+		collector.SetDiscoveredEndpoints(nil, nil)
+	})
+
+	var ids []string
+	url := "/api/v1/endpoints?limit=2"
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("cursor never ends")
+		}
+		page := getEndpointsPage(t, url)
+		if page.Total != 3 {
+			t.Errorf("total = %d, want 3", page.Total)
+		}
+		for _, e := range page.Endpoints {
+			ids = append(ids, e.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		url = "/api/v1/endpoints?limit=2&cursor=" + page.NextCursor
+	}
+	if want := []string{"kyberswap-42161-b", "odos-1-a", "odos-1-c"}; len(ids) != 3 || ids[0] != want[0] || ids[1] != want[1] || ids[2] != want[2] {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+
+	page := getEndpointsPage(t, "/api/v1/endpoints?network=ethereum&discovered=include")
+	if page.Total != 3 || page.Endpoints[2].ID != "odos-1-d" || !page.Endpoints[2].Discovered {
+		t.Errorf("network filter with discovered = %+v", page)
+	}
+	page = getEndpointsPage(t, "/api/v1/endpoints?discovered=only")
+	if page.Total != 1 || page.Endpoints[0].ID != "odos-1-d" {
+		t.Errorf("discovered only = %+v", page)
+	}
+	page = getEndpointsPage(t, "/api/v1/endpoints?status=down")
+	if page.Total != 1 || page.Endpoints[0].ID != "odos-1-a" {
+		t.Errorf("status filter = %+v", page)
+	}
+}
+
+func TestEndpointsHandlerRejectsBadParams(t *testing.T) {
+	for _, url := range []string{
+		"/api/v1/endpoints?limit=0",
+		"/api/v1/endpoints?limit=501",
+		"/api/v1/endpoints?cursor=not*base64",
+		"/api/v1/endpoints?discovered=yes",
+		"/api/v1/endpoints?labels=team",
+	} {
+		if rec := serve(httptest.NewRequest(http.MethodGet, url, nil)); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s: status %d, want 400", url, rec.Code)
+		}
+	}
+}
+
+func TestPaginateCursorSurvivesInserts(t *testing.T) {
+	key := func(s string) string { return s }
+	page, next := paginate([]string{"d", "b", "a", "c"}, key, pageParams{Limit: 2})
+	if len(page) != 2 || page[0] != "a" || page[1] != "b" || next == "" {
+		t.Fatalf("first page = %v, next %q", page, next)
+	}
+	p, err := parsePageParams(map[string][]string{"cursor": {next}, "limit": {"2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A row added before the cursor doesn't repeat or shift the next page.
+	page, next = paginate([]string{"d", "b", "a", "c", "aa"}, key, p)
+	if len(page) != 2 || page[0] != "c" || page[1] != "d" || next != "" {
+		t.Errorf("second page = %v, next %q", page, next)
+	}
+}
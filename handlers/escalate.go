@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/apitoken"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/monitoring/collector"
+)
+
+// maxEscalationBytes bounds an escalation request body.
+const maxEscalationBytes = 4 << 10
+
+// EscalationHandler raises a Jira ticket for a failing endpoint at
+// POST /api/v1/escalations from {"endpoint"}: 201 with the ticket, or 200
+// with the ticket the down streak already has. Requires ADMIN_TOKEN as a
+// bearer token.
+func EscalationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hasScope(r, apitoken.Admin) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	var req struct {
+		Endpoint string `json:"endpoint"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEscalationBytes)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("bad request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	name, _ := collector.ResolveEndpoint(req.Endpoint)
+	e := collector.GetEndpointByName(name)
+	if e == nil {
+		e = collector.GetDiscoveredEndpointByName(name)
+	}
+	if e == nil {
+		http.Error(w, "Endpoint not found", http.StatusNotFound)
+		return
+	}
+
+	esc, err := monitor.Escalate(*e, "")
+	switch {
+	case errors.Is(err, monitor.ErrJiraDisabled):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case errors.Is(err, monitor.ErrNotFailing):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		logs.Printf("%s[JIRA]%s %s: escalation failed: %v\n", config.ColorRed, config.ColorReset, e.Name, err)
+		http.Error(w, "escalation failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !esc.Existing {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(esc)
+}
+
+// escalateDisplay renders a failing endpoint's Jira ticket link, or a button
+// raising one; "" while it is up or Jira isn't configured.
+func escalateDisplay(e collector.Endpoint) string {
+	if !monitor.JiraEnabled() || !collector.IsDownStatus(e.LastStatus) {
+		return ""
+	}
+	if esc, ok := monitor.GetEscalation(e); ok {
+		return fmt.Sprintf(`<br><a href="%s" rel="noopener">%s</a>`, html.EscapeString(esc.URL), html.EscapeString(esc.Key))
+	}
+	return fmt.Sprintf(` <button class="note" data-endpoint="%s" onclick="escalate(this)">Escalate to Jira</button>`, html.EscapeString(endpointRef(e)))
+}
+
+// escalateScript posts an endpoint to /api/v1/escalations with the
+// session's admin token, as noteEditScript does, and reloads the page on
+// success.
+const escalateScript = `<script>
+function escalate(button) {
+	const endpoint = button.dataset.endpoint;
+	if (!confirm('Raise a Jira ticket for ' + endpoint + '?')) return;
+	let token = sessionStorage.getItem('adminToken');
+	if (!token) {
+		token = prompt('ADMIN_TOKEN');
+		if (!token) return;
+		sessionStorage.setItem('adminToken', token);
+	}
+	button.disabled = true;
+	fetch('/api/v1/escalations', {
+		method: 'POST',
+		headers: {'Authorization': 'Bearer ' + token, 'Content-Type': 'application/json'},
+		body: JSON.stringify({endpoint: endpoint}),
+	}).then(r => {
+		if (r.ok) { location.reload(); return; }
+		if (r.status === 403) sessionStorage.removeItem('adminToken');
+		return r.json().then(e => e.error, () => r.statusText).then(msg => { button.disabled = false; alert('Failed: ' + msg); });
+	}).catch(err => { button.disabled = false; alert(err); });
+}
+</script>`
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestEscalationHandler(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	collector.SetEndpoints([]collector.Endpoint{{Name: "KyberSwap-X", RouteSolver: "kyberswap", LastStatus: "up"}})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+	post := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/escalations", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		EscalationHandler(rec, req)
+		return rec
+	}
+
+	if rec := post("wrong", `{"endpoint":"KyberSwap-X"}`); rec.Code != http.StatusForbidden {
+		t.Fatalf("wrong token: status %d", rec.Code)
+	}
+	if rec := post("secret", `{"endpoint":"KyberSwap-X"}`); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without Jira: status %d", rec.Code)
+	}
+	t.Setenv("JIRA_BASE_URL", "https://jira.example.com")
+	t.Setenv("JIRA_PROJECT", "PARTNER")
+	if rec := post("secret", `{"endpoint":"Missing"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown endpoint: status %d", rec.Code)
+	}
+	if rec := post("secret", `{"endpoint":"KyberSwap-X"}`); rec.Code != http.StatusConflict {
+		t.Fatalf("up endpoint: status %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"go-monitoring/monitoring/providers"
+)
+
+// exclusionTable renders the solver page's exclusion filter section: each
+// endpoint's latest quote with Balancer V3 excluded, and the route of a
+// failing one. "" before the solver's first exclusion check.
+func exclusionTable(results []providers.ExclusionResult) string {
+	if len(results) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(`<h2>Exclusion filter check</h2><table><thead><tr><th>Endpoint</th><th>Excluded</th><th>Result</th><th>Checked</th></tr></thead><tbody>`)
+	for _, res := range results {
+		class := "status-up"
+		switch res.Status {
+		case providers.ExclusionFail:
+			class = "status-down"
+		case providers.ExclusionError:
+			class = "status-unknown"
+		}
+		result := html.EscapeString(res.Message)
+		if res.Status == providers.ExclusionFail && !res.Route.IsEmpty() {
+			result += `<pre class="route">` + html.EscapeString(res.Route.String()) + `</pre>`
+		}
+		fmt.Fprintf(&b, `<tr><td>%s</td><td>%s</td><td class="%s">%s</td><td>%s</td></tr>`,
+			html.EscapeString(res.Endpoint), html.EscapeString(strings.Join(res.Excluded, ", ")), class, result,
+			formatTimeAgo(res.CheckedAt))
+	}
+	b.WriteString(`</tbody></table>`)
+	return b.String()
+}
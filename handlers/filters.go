@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+// dashboardFilter is the dashboard view state carried in the query string so
+// any slice of the dashboard can be shared as a link, e.g.
+// /?network=arbitrum&q=GHO/USDC&solver=kyberswap&labels=team=integrations&range=24h&sort=market&dir=desc.
+// Filtering happens server-side; sort is applied by the page script.
+type dashboardFilter struct {
+	Network string // network ID or name, e.g. "42161" or "arbitrum"
+	Solver  string // route solver type or display name
+	Query   string // case-insensitive substring of BaseName / Name
+	Status  string // "up", "down" (any failing status) or an exact status
+	Labels  string // "key=value" pairs a row must all carry, e.g. "team=integrations,priority=p1"
+	Range   string // uptime window, e.g. "1h", "24h", "7d"
+	Sort    string // "balancer" or "market"
+	Dir     string // "asc" or "desc"
+
+	labels        config.Labels // Labels parsed
+	labelsInvalid bool          // Labels doesn't parse; no row matches
+}
+
+// maxFilterRange matches the collector's history capacity.
+const maxFilterRange = 7 * 24 * time.Hour
+
+func parseDashboardFilter(q url.Values) dashboardFilter {
+	f := dashboardFilter{
+		Network: strings.TrimSpace(q.Get("network")),
+		Solver:  strings.TrimSpace(q.Get("solver")),
+		Query:   strings.TrimSpace(q.Get("q")),
+		Status:  strings.ToLower(strings.TrimSpace(q.Get("status"))),
+		Labels:  strings.TrimSpace(q.Get("labels")),
+		Range:   "24h",
+		Sort:    "market",
+		Dir:     "desc",
+	}
+	if labels, err := config.ParseLabels(f.Labels); err == nil {
+		f.labels = labels
+	} else {
+		f.labelsInvalid = true
+	}
+	if _, err := parseRange(q.Get("range")); err == nil {
+		f.Range = q.Get("range")
+	}
+	if s := q.Get("sort"); s == "balancer" || s == "market" {
+		f.Sort = s
+	}
+	if d := q.Get("dir"); d == "asc" || d == "desc" {
+		f.Dir = d
+	}
+	return f
+}
+
+// parseRange accepts Go durations plus a "d" (days) suffix, capped at the
+// history window.
+func parseRange(s string) (time.Duration, error) {
+	return parseRangeUpTo(s, maxFilterRange)
+}
+
+// parseRangeUpTo is parseRange with a different cap.
+func parseRangeUpTo(s string, max time.Duration) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty range")
+	}
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, err
+		}
+	}
+	if d <= 0 || d > max {
+		return 0, fmt.Errorf("range %q out of bounds", s)
+	}
+	return d, nil
+}
+
+// RangeDuration returns the parsed uptime window.
+func (f dashboardFilter) RangeDuration() time.Duration {
+	d, err := parseRange(f.Range)
+	if err != nil {
+		return 24 * time.Hour
+	}
+	return d
+}
+
+// Active reports whether any row filter is set.
+func (f dashboardFilter) Active() bool {
+	return f.Network != "" || f.Solver != "" || f.Query != "" || f.Status != "" || f.Labels != ""
+}
+
+func (f dashboardFilter) matches(e collector.Endpoint) bool {
+	if f.Network != "" && !strings.EqualFold(f.Network, e.Network) && !strings.EqualFold(f.Network, getNetworkName(e.Network)) {
+		return false
+	}
+	if f.Solver != "" && !strings.EqualFold(f.Solver, e.RouteSolver) && !strings.EqualFold(f.Solver, e.SolverName) {
+		return false
+	}
+	if f.Query != "" {
+		q := strings.ToLower(f.Query)
+		if !strings.Contains(strings.ToLower(e.BaseName), q) && !strings.Contains(strings.ToLower(e.Name), q) {
+			return false
+		}
+	}
+	if f.labelsInvalid || !e.Labels.Matches(f.labels) {
+		return false
+	}
+	switch f.Status {
+	case "":
+	case "down":
+		if !collector.IsDownStatus(e.LastStatus) {
+			return false
+		}
+	default:
+		if e.LastStatus != f.Status {
+			return false
+		}
+	}
+	return true
+}
+
+func (f dashboardFilter) apply(eps []collector.Endpoint) []collector.Endpoint {
+	if !f.Active() {
+		return eps
+	}
+	var out []collector.Endpoint
+	for _, e := range eps {
+		if f.matches(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// sortColumn maps the sort parameter to the dashboard table column index.
+func (f dashboardFilter) sortColumn() int {
+	if f.Sort == "balancer" {
+		return 3
+	}
+	return 4
+}
+
+// renderFilterForm writes the GET form that drives dashboardFilter. Submitting
+// it rewrites the URL, so the address bar is always a shareable link.
+func renderFilterForm(w io.Writer, f dashboardFilter, eps []collector.Endpoint) {
+	networks := map[string]bool{}
+	statuses := map[string]bool{"up": true, "down": true}
+	for _, e := range eps {
+		networks[getNetworkName(e.Network)] = true
+		statuses[e.LastStatus] = true
+	}
+
+	fmt.Fprint(w, `<form class="filters" method="get" action="/">`)
+	renderFilterSelect(w, "Network", "network", f.Network, sortedKeys(networks))
+	var solvers []string
+	for _, s := range config.GetEnabledRouteSolvers() {
+		solvers = append(solvers, s.Type)
+	}
+	renderFilterSelect(w, "Solver", "solver", strings.ToLower(f.Solver), solvers)
+	renderFilterSelect(w, "Status", "status", f.Status, sortedKeys(statuses))
+	fmt.Fprintf(w, `<label>Pair / name<input type="text" name="q" value="%s" placeholder="e.g. GHO/USDC"></label>`, html.EscapeString(f.Query))
+	fmt.Fprintf(w, `<label>Labels<input type="text" name="labels" value="%s" placeholder="e.g. team=integrations"></label>`, html.EscapeString(f.Labels))
+
+	fmt.Fprint(w, `<label>Uptime range<select name="range">`)
+	ranges := []string{"1h", "6h", "24h", "3d", "7d"}
+	if !containsString(ranges, f.Range) {
+		ranges = append(ranges, f.Range)
+	}
+	for _, r := range ranges {
+		fmt.Fprintf(w, `<option value="%s"%s>last %s</option>`, html.EscapeString(r), selectedAttr(r == f.Range), html.EscapeString(r))
+	}
+	fmt.Fprint(w, `</select></label>`)
+
+	fmt.Fprintf(w, `<input type="hidden" name="sort" value="%s"><input type="hidden" name="dir" value="%s">`, f.Sort, f.Dir)
+	fmt.Fprint(w, `<button type="submit">Apply</button> <a href="/">Clear</a></form>`)
+}
+
+func renderFilterSelect(w io.Writer, label, name, selected string, options []string) {
+	fmt.Fprintf(w, `<label>%s<select name="%s"><option value="">All</option>`, label, name)
+	if selected != "" && !containsString(options, selected) {
+		options = append(options, selected)
+	}
+	for _, o := range options {
+		fmt.Fprintf(w, `<option value="%s"%s>%s</option>`, html.EscapeString(o), selectedAttr(o == selected), html.EscapeString(o))
+	}
+	fmt.Fprint(w, `</select></label>`)
+}
+
+func selectedAttr(selected bool) string {
+	if selected {
+		return " selected"
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestDashboardFilterFromDeepLink(t *testing.T) {
+	q, _ := url.ParseQuery("network=arbitrum&q=gho/usdc&solver=KyberSwap&range=7d&sort=balancer&dir=asc")
+	f := parseDashboardFilter(q)
+	if f.RangeDuration() != 7*24*time.Hour || f.sortColumn() != 3 || f.Dir != "asc" {
+		t.Fatalf("filter = %+v", f)
+	}
+
+	eps := []collector.Endpoint{
+		{Name: "KyberSwap-Arbitrum-Boosted-StableSurge(GHO/USDC)", BaseName: "Arbitrum-Boosted-StableSurge(GHO/USDC)", SolverName: "KyberSwap", RouteSolver: "kyberswap", Network: "42161"},
+		{Name: "0x-Arbitrum-Boosted-StableSurge(GHO/USDC)", BaseName: "Arbitrum-Boosted-StableSurge(GHO/USDC)", SolverName: "0x", RouteSolver: "0x", Network: "42161"},
+		{Name: "KyberSwap-Base-Boosted-StableSurge(GHO/USDC)", BaseName: "Base-Boosted-StableSurge(GHO/USDC)", SolverName: "KyberSwap", RouteSolver: "kyberswap", Network: "8453"},
+	}
+	got := f.apply(eps)
+	if len(got) != 1 || got[0].Name != eps[0].Name {
+		t.Fatalf("apply = %+v", got)
+	}
+
+	// The link alerts carry must select exactly its own row.
+	link, _ := url.Parse(eps[2].DashboardPath())
+	got = parseDashboardFilter(link.Query()).apply(eps)
+	if len(got) != 1 || got[0].Name != eps[2].Name {
+		t.Fatalf("DashboardPath %s selected %+v", eps[2].DashboardPath(), got)
+	}
+}
+
+func TestDashboardFilterLabels(t *testing.T) {
+	eps := []collector.Endpoint{
+		{Name: "a", Labels: config.Labels{"team": "integrations", "priority": "p1"}},
+		{Name: "b", Labels: config.Labels{"team": "integrations"}},
+		{Name: "c"},
+	}
+	for query, want := range map[string]int{
+		"labels=team=Integrations":             2,
+		"labels=team=integrations,priority=p1": 1,
+		"labels=partner=aave":                  0,
+		"labels=team":                          0, // invalid selector matches nothing
+	} {
+		q, _ := url.ParseQuery(query)
+		if got := parseDashboardFilter(q).apply(eps); len(got) != want {
+			t.Errorf("%s matched %d rows, want %d", query, len(got), want)
+		}
+	}
+}
+
+func TestDashboardFilterDefaultsAndBadRange(t *testing.T) {
+	q, _ := url.ParseQuery("range=30d&sort=bogus&status=DOWN")
+	f := parseDashboardFilter(q)
+	if f.Range != "24h" || f.Sort != "market" || f.Dir != "desc" {
+		t.Fatalf("defaults not applied: %+v", f)
+	}
+	if !f.matches(collector.Endpoint{LastStatus: "error"}) || f.matches(collector.Endpoint{LastStatus: "up"}) {
+		t.Fatal("status=down should match any failing status")
+	}
+}
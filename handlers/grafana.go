@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+)
+
+// maxGrafanaBytes bounds a Grafana request body; queries name a few targets.
+const maxGrafanaBytes = 64 << 10
+
+// Grafana targets are "metric:endpoint", the endpoint by ID or name, e.g.
+// "uptime:odos-42161-3f2a9c1b7e4d".
+const (
+	grafanaQuote  = "quote"  // average Balancer-only quote, in whole tokenOut
+	grafanaSpread = "spread" // average bps by which the market price beats it
+	grafanaUptime = "uptime" // percent of up checks among up and down ones
+	grafanaChecks = "checks" // checks run
+)
+
+var grafanaMetrics = []string{grafanaQuote, grafanaSpread, grafanaUptime, grafanaChecks}
+
+// grafanaRange is the dashboard time range Grafana sends with queries and
+// annotation requests.
+type grafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// GrafanaHandler serves the JSON datasource contract (grafana-simple-json-
+// datasource, and Infinity's JSON backend) under /grafana/, so Grafana can
+// chart the history store without a plugin of ours: GET /grafana/ answers
+// the datasource test, POST /grafana/search lists targets, POST
+// /grafana/query returns their series and POST /grafana/annotations marks
+// incidents (down streaks).
+func GrafanaHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/grafana/" {
+		w.Write([]byte("OK"))
+		return
+	}
+	body := http.MaxBytesReader(w, r.Body, maxGrafanaBytes)
+	var res interface{}
+	var err error
+	switch r.URL.Path {
+	case "/grafana/search":
+		var req struct {
+			Target string `json:"target"`
+		}
+		if err = json.NewDecoder(body).Decode(&req); err == nil {
+			res = grafanaSearch(req.Target)
+		}
+	case "/grafana/query":
+		var req grafanaQueryRequest
+		if err = json.NewDecoder(body).Decode(&req); err == nil {
+			res, err = grafanaQuery(req)
+		}
+	case "/grafana/annotations":
+		var req grafanaAnnotationRequest
+		if err = json.NewDecoder(body).Decode(&req); err == nil {
+			res, err = grafanaAnnotations(req)
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// grafanaSearch lists every endpoint's targets containing term, case
+// insensitively, sorted.
+func grafanaSearch(term string) []string {
+	term = strings.ToLower(strings.TrimSpace(term))
+	out := []string{}
+	for _, e := range append(collector.GetEndpointsCopy(), collector.GetDiscoveredEndpointsCopy()...) {
+		ref := e.ID
+		if ref == "" {
+			ref = e.Name
+		}
+		for _, m := range grafanaMetrics {
+			t := m + ":" + ref
+			if term == "" || strings.Contains(strings.ToLower(t), term) || strings.Contains(strings.ToLower(e.Name), term) {
+				out = append(out, t)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+type grafanaQueryRequest struct {
+	Range      grafanaRange `json:"range"`
+	IntervalMs int64        `json:"intervalMs"`
+	Targets    []struct {
+		Target string `json:"target"`
+		RefID  string `json:"refId"`
+		Type   string `json:"type"` // "timeserie" (default) or "table"
+	} `json:"targets"`
+}
+
+// grafanaSeries is a "timeserie" result: [value, unix ms] pairs.
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type grafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// grafanaTable is a "table" result: one [unix ms, value] row per point.
+type grafanaTable struct {
+	Type    string          `json:"type"`
+	Columns []grafanaColumn `json:"columns"`
+	Rows    [][2]float64    `json:"rows"`
+}
+
+// grafanaQuery returns each target's points over the range, bucketed at
+// the panel's interval rounded up to whole hours (the history store's
+// aggregate resolution). Empty buckets are left out, so gaps show as gaps.
+func grafanaQuery(req grafanaQueryRequest) ([]interface{}, error) {
+	from, to, err := req.Range.bounds()
+	if err != nil {
+		return nil, err
+	}
+	hours := max(1, (req.IntervalMs+int64(time.Hour/time.Millisecond)-1)/int64(time.Hour/time.Millisecond))
+	bucket := time.Duration(hours) * time.Hour
+
+	out := []interface{}{}
+	for _, t := range req.Targets {
+		metric, ref, ok := strings.Cut(t.Target, ":")
+		if !ok || !isGrafanaMetric(metric) {
+			return nil, fmt.Errorf("target %q is not metric:endpoint with metric one of %s", t.Target, strings.Join(grafanaMetrics, ", "))
+		}
+		name, ok := collector.ResolveEndpoint(ref)
+		if !ok {
+			return nil, fmt.Errorf("endpoint %q not found", ref)
+		}
+		points, err := endpointSeries(name, from.Truncate(bucket), bucket)
+		if err != nil {
+			return nil, fmt.Errorf("query %s: %w", t.Target, err)
+		}
+		decimals := 0
+		if e := endpointByName(name); e != nil {
+			decimals = e.TokenOutDecimals
+		}
+
+		var rows [][2]float64
+		for _, p := range points {
+			if p.Start.After(to) {
+				break
+			}
+			if v, ok := grafanaValue(metric, p, decimals); ok {
+				rows = append(rows, [2]float64{v, float64(p.Start.UnixMilli())})
+			}
+		}
+		if t.Type == "table" {
+			tbl := grafanaTable{Type: "table", Columns: []grafanaColumn{{"Time", "time"}, {t.Target, "number"}}, Rows: [][2]float64{}}
+			for _, r := range rows {
+				tbl.Rows = append(tbl.Rows, [2]float64{r[1], r[0]})
+			}
+			out = append(out, tbl)
+			continue
+		}
+		if rows == nil {
+			rows = [][2]float64{}
+		}
+		out = append(out, grafanaSeries{Target: t.Target, Datapoints: rows})
+	}
+	return out, nil
+}
+
+func isGrafanaMetric(m string) bool {
+	for _, g := range grafanaMetrics {
+		if m == g {
+			return true
+		}
+	}
+	return false
+}
+
+// grafanaValue is a point's value for metric; ok is false when the bucket
+// has none (no quotes, or no up or down checks).
+func grafanaValue(metric string, p store.SeriesPoint, decimals int) (float64, bool) {
+	switch metric {
+	case grafanaQuote:
+		return p.Avg / math.Pow10(decimals), p.Quotes > 0
+	case grafanaSpread:
+		return p.SpreadAvg, p.Spreads > 0
+	case grafanaUptime:
+		if p.Up+p.Down == 0 {
+			return 0, false
+		}
+		return 100 * float64(p.Up) / float64(p.Up+p.Down), true
+	default:
+		return float64(p.Checks), p.Checks > 0
+	}
+}
+
+// endpointByName returns the base or discovered row with the given name.
+func endpointByName(name string) *collector.Endpoint {
+	if e := collector.GetEndpointByName(name); e != nil {
+		return e
+	}
+	return collector.GetDiscoveredEndpointByName(name)
+}
+
+type grafanaAnnotationRequest struct {
+	Range      grafanaRange `json:"range"`
+	Annotation struct {
+		Name  string `json:"name"`
+		Query string `json:"query"`
+	} `json:"annotation"`
+}
+
+// grafanaAnnotation is one incident as a region annotation.
+type grafanaAnnotation struct {
+	Annotation interface{} `json:"annotation"`
+	Time       int64       `json:"time"`
+	TimeEnd    int64       `json:"timeEnd"`
+	IsRegion   bool        `json:"isRegion"`
+	Title      string      `json:"title"`
+	Text       string      `json:"text"`
+	Tags       []string    `json:"tags"`
+}
+
+// Annotation kinds a Grafana annotation query can pick with kind=, besides
+// store.AnnotationDeploy and store.AnnotationConfig.
+const grafanaIncident = "incident"
+
+// grafanaAnnotations returns, oldest first, the incidents overlapping the
+// range, deploys, and config edits in the range, for the endpoints the
+// annotation's query picks: one endpoint by ID or name, else the
+// dashboard's filters as a query string (e.g. "solver=odos&network=base"),
+// where kind= limits the kinds (e.g. "kind=deploy,config"); empty is
+// everything on every base endpoint. Incidents are regions; ongoing ones
+// end at the range's end.
+func grafanaAnnotations(req grafanaAnnotationRequest) ([]grafanaAnnotation, error) {
+	from, to, err := req.Range.bounds()
+	if err != nil {
+		return nil, err
+	}
+	var endpoints []collector.Endpoint
+	kinds := map[string]bool{grafanaIncident: true, store.AnnotationDeploy: true, store.AnnotationConfig: true}
+	var baseNames map[string]bool // nil: every BaseEndpoint's edits
+	query := strings.TrimSpace(req.Annotation.Query)
+	if name, ok := collector.ResolveEndpoint(query); query != "" && ok {
+		if e := endpointByName(name); e != nil {
+			endpoints = []collector.Endpoint{*e}
+			baseNames = map[string]bool{e.BaseName: true}
+		}
+	} else {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return nil, fmt.Errorf("annotation query: %w", err)
+		}
+		f := parseDashboardFilter(values)
+		if f.labelsInvalid {
+			return nil, fmt.Errorf("annotation query: labels must be key=value pairs")
+		}
+		endpoints = f.apply(collector.GetEndpointsCopy())
+		if f.Active() {
+			baseNames = map[string]bool{}
+			for _, e := range endpoints {
+				baseNames[e.BaseName] = true
+			}
+		}
+		if k := values.Get("kind"); k != "" {
+			kinds = map[string]bool{}
+			for _, kind := range strings.Split(k, ",") {
+				kinds[strings.TrimSpace(kind)] = true
+			}
+		}
+	}
+
+	out := []grafanaAnnotation{}
+	if kinds[store.AnnotationDeploy] || kinds[store.AnnotationConfig] {
+		changes, err := changeAnnotations(from, baseNames)
+		if err != nil {
+			return nil, fmt.Errorf("annotations: %w", err)
+		}
+		for _, a := range changes {
+			if !kinds[a.Kind] || a.At.After(to) {
+				continue
+			}
+			title, tags := "Deployed "+a.Text, []string{a.Kind}
+			if a.Kind == store.AnnotationConfig {
+				title, tags = a.BaseName+" config", []string{a.Kind, a.BaseName}
+			}
+			out = append(out, grafanaAnnotation{
+				Annotation: req.Annotation,
+				Time:       a.At.UnixMilli(),
+				TimeEnd:    a.At.UnixMilli(),
+				Title:      title,
+				Text:       a.Text,
+				Tags:       tags,
+			})
+		}
+	}
+	if !kinds[grafanaIncident] {
+		endpoints = nil
+	}
+	for _, e := range endpoints {
+		// Start a window back so a streak already open at from is seen
+		// opening.
+		records, err := endpointHistory(e.Name, from.Add(-maxFilterRange))
+		if err != nil {
+			return nil, fmt.Errorf("history of %s: %w", e.Name, err)
+		}
+		for _, inc := range downStreaks(e.Name, records) {
+			end := inc.ResolvedAt
+			if end.IsZero() {
+				end = to
+			}
+			if end.Before(from) || inc.StartedAt.After(to) {
+				continue
+			}
+			title := e.Name + " " + inc.Status
+			if inc.ResolvedAt.IsZero() {
+				title += " (ongoing)"
+			}
+			out = append(out, grafanaAnnotation{
+				Annotation: req.Annotation,
+				Time:       inc.StartedAt.UnixMilli(),
+				TimeEnd:    end.UnixMilli(),
+				IsRegion:   true,
+				Title:      title,
+				Text:       inc.Message,
+				Tags:       []string{e.RouteSolver, config.NetworkName(e.Network), inc.Status},
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time < out[j].Time })
+	return out, nil
+}
+
+// downStreaks returns the incidents in an endpoint's checks, oldest first:
+// each run of down statuses, from its first check to the first check that
+// isn't down. ResolvedAt is zero for a run still open at the last check.
+func downStreaks(name string, records []collector.CheckRecord) []store.Incident {
+	var out []store.Incident
+	open := false
+	for _, r := range records {
+		down := collector.IsDownStatus(r.Status)
+		switch {
+		case down && !open:
+			out = append(out, store.Incident{Endpoint: name, Status: r.Status, Message: r.Message, StartedAt: r.At})
+			open = true
+		case !down && open:
+			out[len(out)-1].ResolvedAt = r.At
+			open = false
+		}
+	}
+	return out
+}
+
+// bounds checks the range is set, ordered and at most maxSeriesRange long.
+func (r grafanaRange) bounds() (from, to time.Time, err error) {
+	if r.From.IsZero() || r.To.IsZero() || !r.From.Before(r.To) {
+		return r.From, r.To, fmt.Errorf("range needs from before to")
+	}
+	if r.To.Sub(r.From) > maxSeriesRange {
+		return r.From, r.To, fmt.Errorf("range longer than %d days", maxSeriesRange/(24*time.Hour))
+	}
+	return r.From, r.To, nil
+}
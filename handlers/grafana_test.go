@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/monitor"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+)
+
+func postGrafana(t *testing.T, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return serve(req)
+}
+
+func TestGrafanaDatasource(t *testing.T) {
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-GHO/USDC", ID: "odos-8453-abc", BaseName: "GHO/USDC",
+		Network: "8453", RouteSolver: "odos", TokenOutDecimals: 6}})
+	collector.SetHistory("Odos-GHO/USDC", []collector.CheckRecord{
+		{At: hour.Add(5 * time.Minute), Status: "up", ReturnAmount: "1000000", MarketPrice: "1001000"},
+		{At: hour.Add(35 * time.Minute), Status: "up", ReturnAmount: "3000000", MarketPrice: "3003000"},
+		{At: hour.Add(65 * time.Minute), Status: "down", Message: "HTTP 500"},
+		{At: hour.Add(95 * time.Minute), Status: "up", ReturnAmount: "2000000"},
+		{At: hour.Add(125 * time.Minute), Status: "down", Message: "timeout"},
+	})
+	t.Cleanup(func() {
+		collector.SetEndpoints(nil)
+		collector.SetHistory("Odos-GHO/USDC", nil)
+	})
+
+	if rec := serve(httptest.NewRequest(http.MethodGet, "/grafana/", nil)); rec.Code != http.StatusOK {
+		t.Fatalf("datasource test status %d", rec.Code)
+	}
+
+	rec := postGrafana(t, "/grafana/search", `{"target":"uptime"}`)
+	var targets []string
+	if err := json.Unmarshal(rec.Body.Bytes(), &targets); err != nil || len(targets) != 1 || targets[0] != "uptime:odos-8453-abc" {
+		t.Fatalf("search = %s (%v)", rec.Body.String(), err)
+	}
+
+	rng := `"range":{"from":"` + hour.Format(time.RFC3339) + `","to":"` + hour.Add(3*time.Hour).Format(time.RFC3339) + `"}`
+	rec = postGrafana(t, "/grafana/query", `{`+rng+`,"intervalMs":60000,"targets":[{"target":"quote:odos-8453-abc","refId":"A"},{"target":"uptime:Odos-GHO/USDC","refId":"B"}]}`)
+	var series []grafanaSeries
+	if err := json.Unmarshal(rec.Body.Bytes(), &series); err != nil || len(series) != 2 {
+		t.Fatalf("query = %s (%v)", rec.Body.String(), err)
+	}
+	ms := float64(hour.UnixMilli())
+	quote, uptime := series[0].Datapoints, series[1].Datapoints
+	if len(quote) != 2 || quote[0] != [2]float64{2, ms} || quote[1][0] != 2 {
+		t.Errorf("quote datapoints = %v, want 2 USDC in each hour with quotes", quote)
+	}
+	if len(uptime) != 3 || uptime[0][0] != 100 || uptime[1][0] != 50 || uptime[2][0] != 0 {
+		t.Errorf("uptime datapoints = %v, want 100, 50, 0", uptime)
+	}
+
+	rec = postGrafana(t, "/grafana/annotations", `{`+rng+`,"annotation":{"name":"incidents","query":"solver=odos&kind=incident"}}`)
+	var anns []grafanaAnnotation
+	if err := json.Unmarshal(rec.Body.Bytes(), &anns); err != nil || len(anns) != 2 {
+		t.Fatalf("annotations = %s (%v)", rec.Body.String(), err)
+	}
+	if anns[0].Time != hour.Add(65*time.Minute).UnixMilli() || anns[0].TimeEnd != hour.Add(95*time.Minute).UnixMilli() ||
+		anns[0].Text != "HTTP 500" || !anns[0].IsRegion {
+		t.Errorf("first incident = %+v", anns[0])
+	}
+	if !strings.HasSuffix(anns[1].Title, "(ongoing)") || anns[1].TimeEnd != hour.Add(3*time.Hour).UnixMilli() {
+		t.Errorf("open incident = %+v, want it to run to the range's end", anns[1])
+	}
+}
+
+func TestGrafanaRejectsBadQueries(t *testing.T) {
+	for _, body := range []string{
+		`{"range":{"from":"2026-01-02T00:00:00Z","to":"2026-01-01T00:00:00Z"},"targets":[]}`,
+		`{"range":{"from":"2026-01-01T00:00:00Z","to":"2026-01-02T00:00:00Z"},"targets":[{"target":"latency:x"}]}`,
+		`{"range":{"from":"2026-01-01T00:00:00Z","to":"2026-01-02T00:00:00Z"},"targets":[{"target":"uptime:missing"}]}`,
+	} {
+		if rec := postGrafana(t, "/grafana/query", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("query %s: status %d, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestGrafanaChangeAnnotations(t *testing.T) {
+	monitor.SetStore(store.NewFileStore(filepath.Join(t.TempDir(), "state.json")))
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-GHO/USDC", BaseName: "GHO/USDC", Network: "8453", RouteSolver: "odos"}})
+	t.Cleanup(func() {
+		monitor.SetStore(nil)
+		collector.SetEndpoints(nil)
+	})
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	monitor.RecordAnnotation(store.Annotation{At: at, Kind: store.AnnotationDeploy, Text: "v1.0.0"})
+	monitor.RecordAnnotation(store.Annotation{At: at.Add(time.Hour), Kind: store.AnnotationConfig, BaseName: "GHO/USDC", Text: "edited by import"})
+	monitor.RecordAnnotation(store.Annotation{At: at.Add(time.Hour), Kind: store.AnnotationConfig, BaseName: "WETH/USDC", Text: "added by import"})
+
+	rng := `"range":{"from":"2026-05-01T00:00:00Z","to":"2026-05-02T00:00:00Z"}`
+	rec := postGrafana(t, "/grafana/annotations", `{`+rng+`,"annotation":{"query":"network=base"}}`)
+	var anns []grafanaAnnotation
+	if err := json.Unmarshal(rec.Body.Bytes(), &anns); err != nil || len(anns) != 2 {
+		t.Fatalf("annotations = %s (%v)", rec.Body.String(), err)
+	}
+	if anns[0].Title != "Deployed v1.0.0" || anns[0].IsRegion || anns[1].Title != "GHO/USDC config" || anns[1].Text != "edited by import" {
+		t.Errorf("annotations = %+v", anns)
+	}
+
+	rec = postGrafana(t, "/grafana/annotations", `{`+rng+`,"annotation":{"query":"kind=deploy"}}`)
+	if err := json.Unmarshal(rec.Body.Bytes(), &anns); err != nil || len(anns) != 1 {
+		t.Errorf("deploys only = %s (%v)", rec.Body.String(), err)
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/apitoken"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/importer"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+)
+
+// maxImportBytes bounds an import body; a spreadsheet of a few thousand pools
+// is well under this.
+const maxImportBytes = 4 << 20
+
+// importResult is the JSON response of EndpointsImportHandler.
+type importResult struct {
+	Rows      int                 `json:"rows"`
+	DryRun    bool                `json:"dry_run,omitempty"`
+	Suggested int                 `json:"suggested,omitempty"` // empty swap_amounts filled by swapsize
+	Added     int                 `json:"added"`               // solver rows added to the running monitor
+	Updated   int                 `json:"updated"`             // solver rows replaced, keeping their results
+	Persisted bool                `json:"persisted"`
+	Error     string              `json:"error,omitempty"`
+	Errors    []importer.RowError `json:"errors,omitempty"`
+}
+
+// EndpointsImportHandler upserts a batch of BaseEndpoints at
+// /api/v1/endpoints/import. The body is CSV (Content-Type text/csv or
+// ?format=csv) or a JSON array; see package importer for the columns. The
+// whole batch is sized and validated first (importer.Prepare) and nothing is
+// applied if any row fails. ?dry_run=1 validates only. Requires ADMIN_TOKEN as a bearer token; with
+// ENDPOINTS_FILE set the batch is also saved there for the next start.
+func EndpointsImportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hasScope(r, apitoken.Admin) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = importer.FormatFor(r.Header.Get("Content-Type"))
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	rows, err := importer.Parse(http.MaxBytesReader(w, r.Body, maxImportBytes), format)
+	if err != nil {
+		writeImportError(w, len(rows), err)
+		return
+	}
+	var dryRun bool
+	switch r.URL.Query().Get("dry_run") {
+	case "true", "1", "yes", "on":
+		dryRun = true
+	}
+	res, status := runImport(rows, dryRun)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+// runImport sizes and validates rows (importer.Prepare), then unless dryRun
+// saves them to ENDPOINTS_FILE and applies them to the running monitor. It
+// returns the importResult and its HTTP status. Nothing is applied if any
+// row fails.
+func runImport(rows []importer.Row, dryRun bool) (importResult, int) {
+	suggested, err := importer.Prepare(rows)
+	if err != nil {
+		return importError(len(rows), err), http.StatusBadRequest
+	}
+
+	res := importResult{Rows: len(rows), Suggested: suggested}
+	if dryRun {
+		res.DryRun = true
+		return res, http.StatusOK
+	}
+
+	if path := config.GetEndpointsFile(); path != "" {
+		if _, _, err := importer.UpsertFile(path, rows); err != nil {
+			res.Error = "save " + path + ": " + err.Error()
+			return res, http.StatusInternalServerError
+		}
+		res.Persisted = true
+	}
+	before := map[string]collector.Endpoint{}
+	for _, e := range collector.GetEndpointsCopy() {
+		before[e.BaseName] = e
+	}
+	res.Added, res.Updated = importer.Apply(rows)
+	now := clock.Now()
+	for _, r := range rows {
+		old, ok := before[r.Name]
+		monitor.RecordAnnotation(store.Annotation{At: now, Kind: store.AnnotationConfig, BaseName: r.Name, Text: importChange(old, ok, r)})
+	}
+	logs.Printf("%s[IMPORT]%s %d endpoints imported: %d solver rows added, %d updated\n", config.ColorGreen, config.ColorReset, len(rows), res.Added, res.Updated)
+	return res, http.StatusOK
+}
+
+// importChange describes an imported row for its config annotation: added,
+// or which of the fields that move quotes it changed, e.g. "edited by
+// import: swap_amount 1000000 → 5000000".
+func importChange(old collector.Endpoint, existed bool, r importer.Row) string {
+	if !existed {
+		return "added by import"
+	}
+	var changes []string
+	for _, f := range []struct{ name, from, to string }{
+		{"expected_pool", old.ExpectedPool, r.ExpectedPool},
+		{"token_in", old.TokenIn, r.TokenIn},
+		{"token_out", old.TokenOut, r.TokenOut},
+		{"swap_amount", old.SwapAmount, r.SwapAmount},
+		{"expected_no_hops", strconv.Itoa(old.ExpectedNoHops), strconv.Itoa(r.ExpectedNoHops)},
+		{"schedule", old.Schedule, r.Schedule},
+	} {
+		if !strings.EqualFold(f.from, f.to) {
+			changes = append(changes, fmt.Sprintf("%s %s → %s", f.name, f.from, f.to))
+		}
+	}
+	if len(changes) == 0 {
+		return "edited by import"
+	}
+	return "edited by import: " + strings.Join(changes, ", ")
+}
+
+// importError is the result of a failed batch, listing the invalid rows of
+// a *importer.ValidationError.
+func importError(rows int, err error) importResult {
+	res := importResult{Rows: rows, Error: err.Error()}
+	var verr *importer.ValidationError
+	if errors.As(err, &verr) {
+		res.Error = fmt.Sprintf("%d invalid rows; nothing imported", len(verr.Rows))
+		res.Errors = verr.Rows
+	}
+	return res
+}
+
+// writeImportError writes a failed batch as 400.
+func writeImportError(w http.ResponseWriter, rows int, err error) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(importError(rows, err))
+}
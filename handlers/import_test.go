@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-monitoring/internal/importer"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestEndpointsImportHandler(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	t.Setenv("ENDPOINTS_FILE", path)
+	collector.SetEndpoints(nil)
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	csv := "name,network,token_in,token_out,token_in_decimals,token_out_decimals,expected_pool,swap_amount,expected_no_hops\n" +
+		"Base-Stable(GHO/USDC),8453,0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913,0x6Bb7a212910682DCFdbd5BCBb3e28FB4E8da10Ee,6,18,0x7ab124ec4029316c2a42f713828ddf2a192b36db,100000000000,1\n"
+	post := func(query, token, body string) (*httptest.ResponseRecorder, importResult) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/endpoints/import"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		EndpointsImportHandler(rec, req)
+		var res importResult
+		json.Unmarshal(rec.Body.Bytes(), &res)
+		return rec, res
+	}
+
+	if rec, _ := post("", "wrong", csv); rec.Code != http.StatusForbidden {
+		t.Fatalf("wrong token: status %d", rec.Code)
+	}
+	if rec, res := post("", "secret", strings.Replace(csv, ",8453,", ",0,", 1)); rec.Code != http.StatusBadRequest || len(res.Errors) != 1 {
+		t.Fatalf("invalid batch: status %d, %+v", rec.Code, res)
+	}
+	if rec, res := post("?dry_run=1", "secret", csv); rec.Code != http.StatusOK || !res.DryRun || len(collector.GetEndpointsCopy()) != 0 {
+		t.Fatalf("dry run: status %d, %+v", rec.Code, res)
+	}
+
+	rec, res := post("", "secret", csv)
+	if rec.Code != http.StatusOK || !res.Persisted || res.Added == 0 || res.Added != len(collector.GetEndpointsCopy()) {
+		t.Fatalf("import: status %d, %+v", rec.Code, res)
+	}
+	rows, err := importer.LoadFile(path)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("endpoints file = %+v, %v", rows, err)
+	}
+
+	_, res = post("", "secret", csv)
+	if res.Added != 0 || res.Updated != len(collector.GetEndpointsCopy()) {
+		t.Fatalf("re-import: %+v", res)
+	}
+}
+
+func TestImportChange(t *testing.T) {
+	old := collector.Endpoint{ExpectedPool: "0xABC", TokenIn: "0x1", TokenOut: "0x2", SwapAmount: "1000", ExpectedNoHops: 1}
+	row := importer.Row{ExpectedPool: "0xabc", TokenIn: "0x1", TokenOut: "0x2", SwapAmount: "5000", ExpectedNoHops: 2}
+	for _, tc := range []struct {
+		existed bool
+		row     importer.Row
+		want    string
+	}{
+		{false, row, "added by import"},
+		{true, row, "edited by import: swap_amount 1000 → 5000, expected_no_hops 1 → 2"},
+		{true, importer.Row{ExpectedPool: "0xabc", TokenIn: "0x1", TokenOut: "0x2", SwapAmount: "1000", ExpectedNoHops: 1}, "edited by import"},
+	} {
+		if got := importChange(old, tc.existed, tc.row); got != tc.want {
+			t.Errorf("importChange = %q, want %q", got, tc.want)
+		}
+	}
+}
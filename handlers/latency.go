@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/monitoring/collector"
+)
+
+// LatencyHandler serves /api/v1/latency: each enabled solver's Balancer-only
+// response times over the last day split into DNS, connect, TLS, first byte
+// and read phases. ?solver= limits it to one solver (type or name).
+func LatencyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	solvers := config.GetEnabledRouteSolvers()
+	if name := r.URL.Query().Get("solver"); name != "" {
+		solver, ok := findRouteSolver(name)
+		if !ok {
+			http.Error(w, "Solver not found", http.StatusNotFound)
+			return
+		}
+		solvers = []config.RouteSolver{solver}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(monitor.LatencyReport(solvers, clock.Now()))
+}
+
+// phaseLabels names the latency phases on the solver page.
+var phaseLabels = map[string]string{
+	collector.PhaseDNS:     "DNS",
+	collector.PhaseConnect: "Connect",
+	collector.PhaseTLS:     "TLS",
+	collector.PhaseTTFB:    "First byte",
+	collector.PhaseRead:    "Read",
+}
+
+// latencyTable renders the solver page's latency breakdown: the median and
+// 95th percentile of each phase with how many checks it was measured on.
+// "" when no check measured any.
+func latencyTable(breakdowns []monitor.LatencyBreakdown) string {
+	var b strings.Builder
+	for _, lb := range breakdowns {
+		if lb.Checks == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, `<h2>Response time breakdown (%s, %d checks)</h2><table><thead><tr><th>Phase</th><th>Median</th><th>p95</th><th>Checks</th></tr></thead><tbody>`,
+			html.EscapeString(lb.Window), lb.Checks)
+		for _, p := range lb.Phases {
+			median, p95 := "&mdash;", "&mdash;"
+			if p.Samples > 0 {
+				median, p95 = fmt.Sprintf("%dms", p.P50Ms), fmt.Sprintf("%dms", p.P95Ms)
+			}
+			fmt.Fprintf(&b, `<tr><td>%s</td><td class="num">%s</td><td class="num">%s</td><td class="num">%d</td></tr>`,
+				phaseLabels[p.Phase], median, p95, p.Samples)
+		}
+		b.WriteString(`</tbody></table>`)
+	}
+	return b.String()
+}
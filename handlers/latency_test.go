@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/internal/monitor"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestLatencyHandlerUnknownSolver(t *testing.T) {
+	rec := httptest.NewRecorder()
+	LatencyHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/latency?solver=nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", rec.Code)
+	}
+}
+
+func TestLatencyTable(t *testing.T) {
+	got := latencyTable([]monitor.LatencyBreakdown{{Window: "1d", Checks: 3, Phases: []monitor.PhaseLatency{
+		{Phase: collector.PhaseDNS},
+		{Phase: collector.PhaseTTFB, Samples: 3, P50Ms: 600, P95Ms: 2000},
+	}}})
+	for _, want := range []string{"Response time breakdown (1d, 3 checks)", "<td>DNS</td><td class=\"num\">&mdash;", "<td>First byte</td><td class=\"num\">600ms</td><td class=\"num\">2000ms</td>"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in %s", want, got)
+		}
+	}
+	if latencyTable([]monitor.LatencyBreakdown{{Window: "1d"}}) != "" {
+		t.Fatal("no measured checks rendered a table")
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-monitoring/internal/apitoken"
+)
+
+// maxPathParam bounds a path parameter; an endpoint name or ID is far
+// shorter.
+const maxPathParam = 256
+
+// Route is one handler and what Validate checks of its requests.
+type Route struct {
+	// Pattern is the http.ServeMux pattern; wildcards ({endpoint},
+	// {endpoint...}) are path parameters, read with r.PathValue.
+	Pattern string
+	Handler http.HandlerFunc
+	// Methods the route answers.
+	Methods []string
+	// ContentTypes a request body may be sent as; a request without a
+	// Content-Type is left to the handler. Empty accepts any.
+	ContentTypes []string
+	// Scope an API token needs with API_REQUIRE_TOKEN set; empty leaves the
+	// route public. Admin-only actions check their scope in the handler.
+	Scope apitoken.Scope
+	// RawBody leaves the response as the handler writes it: no error
+	// envelope, no redaction. For handlers that encode their own errors and
+	// scrub their own responses (MonitoringService's binary encodings can't
+	// be rewritten in flight).
+	RawBody bool
+}
+
+// errorBody is the JSON error envelope every validated route answers with.
+type errorBody struct {
+	Error  string `json:"error"`
+	Status int    `json:"status"`
+}
+
+var pathWildcard = regexp.MustCompile(`\{(\w+)(?:\.\.\.)?\}`)
+
+// Validate wraps a route's handler: a request with another method gets 405
+// with Allow, one without a token for the route's Scope 401 or 403, a body
+// of another content type 415, and an empty, overlong or non-printable path
+// parameter 400, before the handler runs. Errors the handler sends with
+// http.Error are rewritten into the same JSON envelope, so every route fails
+// the same way, and credentials are redacted from every response (but a
+// RawBody route's).
+func Validate(rt Route) http.HandlerFunc {
+	var params []string
+	for _, m := range pathWildcard.FindAllStringSubmatch(rt.Pattern, -1) {
+		params = append(params, m[1])
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(rt.Methods, r.Method) {
+			w.Header().Set("Allow", strings.Join(rt.Methods, ", "))
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		if rt.Scope != "" && !requireScope(w, r, rt.Scope) {
+			return
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "" && len(rt.ContentTypes) > 0 {
+			mt, _, err := mime.ParseMediaType(ct)
+			if err != nil || !slices.Contains(rt.ContentTypes, mt) {
+				writeError(w, http.StatusUnsupportedMediaType,
+					fmt.Sprintf("Content-Type %q not supported; send %s", ct, strings.Join(rt.ContentTypes, " or ")))
+				return
+			}
+		}
+		for _, p := range params {
+			if err := validPathParam(r.PathValue(p)); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s: %v", p, err))
+				return
+			}
+		}
+		if rt.RawBody {
+			rt.Handler(w, r)
+			return
+		}
+		ew := &errorEnvelope{ResponseWriter: redactWriter{w}}
+		rt.Handler(ew, r)
+		ew.finish()
+	}
+}
+
+// validPathParam accepts a non-empty parameter of printable UTF-8 up to
+// maxPathParam bytes. Slashes are allowed: endpoint names contain them.
+func validPathParam(v string) error {
+	switch {
+	case v == "":
+		return fmt.Errorf("empty")
+	case len(v) > maxPathParam:
+		return fmt.Errorf("longer than %d bytes", maxPathParam)
+	case !utf8.ValidString(v):
+		return fmt.Errorf("not UTF-8")
+	case strings.IndexFunc(v, func(c rune) bool { return !unicode.IsPrint(c) }) >= 0:
+		return fmt.Errorf("contains non-printable characters")
+	}
+	return nil
+}
+
+// writeError sends msg in the JSON error envelope.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorBody{Error: msg, Status: status})
+}
+
+// errorEnvelope holds back a plain-text error response (http.Error's) and
+// sends it as the JSON envelope when the handler returns. Other responses
+// pass through.
+type errorEnvelope struct {
+	http.ResponseWriter
+	status int // of the held-back error, 0 when none
+	msg    bytes.Buffer
+}
+
+func (e *errorEnvelope) WriteHeader(status int) {
+	if status >= 400 && strings.HasPrefix(e.Header().Get("Content-Type"), "text/plain") {
+		e.status = status
+		return
+	}
+	e.ResponseWriter.WriteHeader(status)
+}
+
+func (e *errorEnvelope) Write(b []byte) (int, error) {
+	if e.status != 0 {
+		return e.msg.Write(b)
+	}
+	return e.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (e *errorEnvelope) Unwrap() http.ResponseWriter {
+	return e.ResponseWriter
+}
+
+func (e *errorEnvelope) finish() {
+	if e.status != 0 {
+		writeError(e.ResponseWriter, e.status, strings.TrimSpace(e.msg.String()))
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/apitoken"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/rpc/monitoringv1/monitoringv1connect"
+)
+
+// serve sends req through the registered routes, as the server does.
+func serve(req *http.Request) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	Register(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestValidateErrorEnvelope(t *testing.T) {
+	withType := func(method, target, contentType string) *http.Request {
+		req := httptest.NewRequest(method, target, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", contentType)
+		return req
+	}
+	for _, tt := range []struct {
+		req    *http.Request
+		status int
+	}{
+		{httptest.NewRequest(http.MethodGet, "/check/x", nil), http.StatusMethodNotAllowed},
+		{withType(http.MethodPost, "/api/v1/escalations", "application/x-www-form-urlencoded"), http.StatusUnsupportedMediaType},
+		{httptest.NewRequest(http.MethodPost, "/check/", nil), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, "/check/a%00b", nil), http.StatusBadRequest},
+		{httptest.NewRequest(http.MethodPost, "/"+monitoringv1connect.MonitoringServiceName+"/List%00Endpoints", nil), http.StatusBadRequest},
+		// An http.Error from the handler itself comes back in the envelope.
+		{httptest.NewRequest(http.MethodGet, "/solver/missing", nil), http.StatusNotFound},
+	} {
+		rec := serve(tt.req)
+		var body errorBody
+		if rec.Code != tt.status || rec.Header().Get("Content-Type") != "application/json" ||
+			json.Unmarshal(rec.Body.Bytes(), &body) != nil || body.Status != tt.status || body.Error == "" {
+			t.Errorf("%s %s = %d %q: %s", tt.req.Method, tt.req.URL, rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+		}
+	}
+	if rec := serve(httptest.NewRequest(http.MethodGet, "/check/x", nil)); rec.Header().Get("Allow") != "POST" {
+		t.Errorf("Allow = %q", rec.Header().Get("Allow"))
+	}
+	// A JSON body with parameters passes the content type check.
+	if rec := serve(withType(http.MethodPost, "/api/v1/escalations", "application/json; charset=utf-8")); rec.Code == http.StatusUnsupportedMediaType {
+		t.Errorf("JSON body refused: %s", rec.Body)
+	}
+}
+
+func TestValidateRequiresScopedToken(t *testing.T) {
+	t.Setenv("API_REQUIRE_TOKEN", "true")
+	t.Setenv("ADMIN_TOKEN", "admin-secret")
+	reader, readSecret, _ := apitoken.New("reader", []string{string(apitoken.Read)}, time.Now())
+	apitoken.Use(func() ([]store.APIToken, error) { return []store.APIToken{reader}, nil })
+	t.Cleanup(func() { apitoken.Use(nil) })
+
+	withToken := func(method, target, token string) *http.Request {
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req
+	}
+	for _, tt := range []struct {
+		req    *http.Request
+		status int
+	}{
+		{withToken(http.MethodGet, "/api/v1/notifications", ""), http.StatusUnauthorized},
+		{withToken(http.MethodGet, "/api/v1/notifications", readSecret), http.StatusOK},
+		{withToken(http.MethodGet, "/api/v1/notifications", "admin-secret"), http.StatusOK},
+		{withToken(http.MethodPost, "/check/missing", readSecret), http.StatusForbidden},
+		{withToken(http.MethodPost, "/check/missing", "admin-secret"), http.StatusNotFound},
+		// The widget stays public.
+		{withToken(http.MethodGet, "/widget?format=json", ""), http.StatusOK},
+	} {
+		if rec := serve(tt.req); rec.Code != tt.status {
+			t.Errorf("%s %s = %d, want %d: %s", tt.req.Method, tt.req.URL, rec.Code, tt.status, rec.Body)
+		}
+	}
+}
+
+func TestValidateRedactsResponses(t *testing.T) {
+	collector.SetEndpoints([]collector.Endpoint{{
+		Name: "Odos-X", LastStatus: "down",
+		Message: `Get "https://api.example.com/quote?apikey=k-9f8e7d6c&amount=1": timeout`,
+	}})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	for _, target := range []string{"/", "/api/v1/endpoints"} {
+		rec := serve(httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d", target, rec.Code)
+		}
+		if body := rec.Body.String(); strings.Contains(body, "k-9f8e7d6c") || !strings.Contains(body, "apikey=[redacted]") {
+			t.Errorf("%s: the API key was not redacted", target)
+		}
+	}
+}
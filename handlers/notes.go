@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/apitoken"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/monitoring/collector"
+)
+
+// maxNoteBytes bounds a note body; notes are a few sentences and a link.
+const maxNoteBytes = 16 << 10
+
+// maxNoteText bounds a note's text.
+const maxNoteText = 2000
+
+// noteRequest is the body of EndpointNotesHandler.
+type noteRequest struct {
+	Endpoint   string `json:"endpoint"`
+	Text       string `json:"text"`
+	RunbookURL string `json:"runbookUrl"`
+}
+
+// EndpointNotesHandler serves the endpoint notes at /api/v1/endpoints/notes:
+// GET lists them, POST sets one endpoint's note from {"endpoint", "text",
+// "runbookUrl"} (both empty deletes it) and saves it to the store. POST
+// requires ADMIN_TOKEN as a bearer token.
+func EndpointNotesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(collector.AllNotes())
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hasScope(r, apitoken.Admin) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var req noteRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxNoteBytes)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("bad request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	req.Text, req.RunbookURL = strings.TrimSpace(req.Text), strings.TrimSpace(req.RunbookURL)
+	name, ok := collector.ResolveEndpoint(req.Endpoint)
+	if !ok {
+		http.Error(w, "Endpoint not found", http.StatusNotFound)
+		return
+	}
+	req.Endpoint = name
+	if len(req.Text) > maxNoteText {
+		http.Error(w, fmt.Sprintf("note is longer than %d bytes", maxNoteText), http.StatusBadRequest)
+		return
+	}
+	if err := validateRunbookURL(req.RunbookURL); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n := collector.Note{Endpoint: req.Endpoint, Text: req.Text, RunbookURL: req.RunbookURL, UpdatedAt: clock.Now().UTC()}
+	if err := monitor.SaveNote(n); err != nil {
+		logs.Printf("%s[STORE]%s %s: save note failed: %v\n", config.ColorRed, config.ColorReset, n.Endpoint, err)
+		http.Error(w, "note updated but not saved: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(n)
+}
+
+// validateRunbookURL accepts an empty URL or an absolute http(s) one, the
+// only kinds worth linking from an alert.
+func validateRunbookURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("runbookUrl %q is not an http(s) URL", raw)
+	}
+	return nil
+}
+
+// noteDisplay renders an endpoint's note and runbook link with an edit
+// button; just the button when it has none. The button carries the current
+// note in data attributes for noteEditScript.
+func noteDisplay(e collector.Endpoint) string {
+	n, _ := collector.GetNote(e.Name)
+	out, label := "", "Add note"
+	if n.Text != "" {
+		out += html.EscapeString(n.Text) + "<br>"
+	}
+	if n.RunbookURL != "" {
+		out += fmt.Sprintf(`<a href="%s" rel="noopener">Runbook</a><br>`, html.EscapeString(n.RunbookURL))
+	}
+	if !n.IsEmpty() {
+		label = "Edit note"
+	}
+	return out + fmt.Sprintf(`<button class="note" data-endpoint="%s" data-text="%s" data-runbook="%s" onclick="editNote(this)">%s</button>`,
+		html.EscapeString(endpointRef(e)), html.EscapeString(n.Text), html.EscapeString(n.RunbookURL), label)
+}
+
+// noteMarker flags a dashboard row whose endpoint has a note: a pencil with
+// the note as its tooltip, and the runbook link; "" without a note.
+func noteMarker(e collector.Endpoint) string {
+	n, ok := collector.GetNote(e.Name)
+	if !ok {
+		return ""
+	}
+	out := ""
+	if n.Text != "" {
+		out += fmt.Sprintf(` <span class="note-marker" title="%s">&#9998;</span>`, html.EscapeString(n.Text))
+	}
+	if n.RunbookURL != "" {
+		out += fmt.Sprintf(` <a href="%s" rel="noopener">runbook</a>`, html.EscapeString(n.RunbookURL))
+	}
+	return out
+}
+
+// noteEditScript prompts for an endpoint's note and runbook URL and posts
+// them to /api/v1/endpoints/notes with the session's admin token, reloading
+// the page on success.
+const noteEditScript = `<script>
+function editNote(button) {
+	const endpoint = button.dataset.endpoint;
+	const newText = prompt('Note for ' + endpoint + ' (empty to clear)', button.dataset.text);
+	if (newText === null) return;
+	const newRunbook = prompt('Runbook URL (empty for none)', button.dataset.runbook);
+	if (newRunbook === null) return;
+	let token = sessionStorage.getItem('adminToken');
+	if (!token) {
+		token = prompt('ADMIN_TOKEN');
+		if (!token) return;
+		sessionStorage.setItem('adminToken', token);
+	}
+	button.disabled = true;
+	fetch('/api/v1/endpoints/notes', {
+		method: 'POST',
+		headers: {'Authorization': 'Bearer ' + token, 'Content-Type': 'application/json'},
+		body: JSON.stringify({endpoint: endpoint, text: newText, runbookUrl: newRunbook}),
+	}).then(r => {
+		if (r.ok) { location.reload(); return; }
+		if (r.status === 403) sessionStorage.removeItem('adminToken');
+		return r.json().then(e => e.error, () => r.statusText).then(msg => { button.disabled = false; alert('Failed: ' + msg); });
+	}).catch(err => { button.disabled = false; alert(err); });
+}
+</script>`
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestEndpointNotesHandler(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	collector.SetEndpoints([]collector.Endpoint{{Name: "KyberSwap-X", RouteSolver: "kyberswap"}})
+	t.Cleanup(func() {
+		collector.SetEndpoints(nil)
+		collector.SetNotes(nil)
+	})
+	post := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/endpoints/notes", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		EndpointNotesHandler(rec, req)
+		return rec
+	}
+
+	const note = `{"endpoint":"KyberSwap-X","text":" needs whitelisting (#123) ","runbookUrl":"https://wiki.example.com/kyber"}`
+	if rec := post("wrong", note); rec.Code != http.StatusForbidden {
+		t.Fatalf("wrong token: status %d", rec.Code)
+	}
+	if rec := post("secret", `{"endpoint":"Missing","text":"x"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown endpoint: status %d", rec.Code)
+	}
+	if rec := post("secret", `{"endpoint":"KyberSwap-X","runbookUrl":"javascript:alert(1)"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("bad runbook URL: status %d", rec.Code)
+	}
+	if rec := post("secret", note); rec.Code != http.StatusOK {
+		t.Fatalf("set note: status %d %s", rec.Code, rec.Body)
+	}
+	if n, ok := collector.GetNote("KyberSwap-X"); !ok || n.Text != "needs whitelisting (#123)" || n.UpdatedAt.IsZero() {
+		t.Fatalf("note = %+v, %v", n, ok)
+	}
+
+	rec := httptest.NewRecorder()
+	EndpointNotesHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/endpoints/notes", nil))
+	var got []collector.Note
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || len(got) != 1 || got[0].RunbookURL != "https://wiki.example.com/kyber" {
+		t.Fatalf("GET = %s, %v", rec.Body, err)
+	}
+	if out := noteDisplay(collector.Endpoint{Name: "KyberSwap-X"}); !strings.Contains(out, `href="https://wiki.example.com/kyber"`) || !strings.Contains(out, "Edit note") {
+		t.Fatalf("noteDisplay = %s", out)
+	}
+
+	if rec := post("secret", `{"endpoint":"KyberSwap-X"}`); rec.Code != http.StatusOK {
+		t.Fatalf("clear note: status %d", rec.Code)
+	}
+	if _, ok := collector.GetNote("KyberSwap-X"); ok {
+		t.Fatal("note not cleared")
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/notify"
+)
+
+// NotificationsHandler serves /api/v1/notifications: each alert channel's
+// delivery record since start (sent, retried, failed, dropped, queued for a
+// retry) and the problems the dashboard banner shows.
+func NotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Channels []notify.ChannelStatus `json:"channels"`
+		Problems []string               `json:"problems"`
+	}{notify.DeliveryStatuses(), notify.DeliveryProblems(clock.Now())})
+}
+
+// renderDeliveryProblems warns at the top of the dashboard that alerts
+// aren't getting through, since nobody would otherwise be told.
+func renderDeliveryProblems(w io.Writer, problems []string) {
+	if len(problems) == 0 {
+		return
+	}
+	fmt.Fprint(w, `<div style="padding:12px 16px;background:#ffebee;border:1px solid #ef9a9a;border-radius:4px;color:#b71c1c;margin-bottom:12px;"><strong>Notification delivery is failing</strong> &mdash; alerts may be late or lost (<a href="/api/v1/notifications">details</a>)<ul style="margin:6px 0 0 0;">`)
+	for _, p := range problems {
+		fmt.Fprintf(w, `<li>%s</li>`, html.EscapeString(p))
+	}
+	fmt.Fprint(w, `</ul></div>`)
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDeliveryProblems(t *testing.T) {
+	var b strings.Builder
+	renderDeliveryProblems(&b, nil)
+	if b.Len() != 0 {
+		t.Fatalf("healthy banner = %q", b.String())
+	}
+	renderDeliveryProblems(&b, []string{"slack: 2 failed attempts in a row: webhook returned <502>"})
+	if got := b.String(); !strings.Contains(got, "Notification delivery is failing") || !strings.Contains(got, "<li>slack: 2 failed attempts in a row: webhook returned &lt;502&gt;</li>") {
+		t.Fatalf("banner = %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// List endpoints page with ?limit= and ?cursor=: defaultPageLimit items by
+// default, at most maxPageLimit.
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 500
+)
+
+// pageParams is a list request's ?limit= and ?cursor=, parsed.
+type pageParams struct {
+	Limit int
+	After string // the sort key of the previous page's last item; "" starts at the top
+}
+
+// parsePageParams reads ?limit= (1..maxPageLimit, default defaultPageLimit)
+// and ?cursor= (a nextCursor from a previous page).
+func parsePageParams(q url.Values) (pageParams, error) {
+	p := pageParams{Limit: defaultPageLimit}
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return p, fmt.Errorf("limit must be 1 to %d", maxPageLimit)
+		}
+		p.Limit = n
+	}
+	if s := q.Get("cursor"); s != "" {
+		after, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil || len(after) == 0 {
+			return p, fmt.Errorf("invalid cursor")
+		}
+		p.After = string(after)
+	}
+	return p, nil
+}
+
+// paginate sorts items by key, which must be unique, and returns the page
+// after p.After with the cursor of the next one ("" on the last page).
+// Cursors name a position in the ordering rather than an offset, so rows
+// added or removed between requests don't shift later pages.
+func paginate[T any](items []T, key func(T) string, p pageParams) (page []T, next string) {
+	sort.SliceStable(items, func(i, j int) bool { return key(items[i]) < key(items[j]) })
+	start := sort.Search(len(items), func(i int) bool { return key(items[i]) > p.After })
+	if p.After == "" {
+		start = 0
+	}
+	end := min(start+p.Limit, len(items))
+	page = items[start:end]
+	if end < len(items) {
+		next = base64.RawURLEncoding.EncodeToString([]byte(key(items[end-1])))
+	}
+	return page, next
+}
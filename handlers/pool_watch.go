@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/apitoken"
+	"go-monitoring/internal/importer"
+	"go-monitoring/internal/poolwatch"
+	"go-monitoring/monitoring/collector"
+)
+
+// NewPoolsHandler renders /pools/new: the pools the pool creation watch
+// (POOL_WATCH_FILE) matched, newest first, each with an "Add to monitoring"
+// button that imports it through PoolAddHandler. The button asks for
+// ADMIN_TOKEN once per browser session.
+func NewPoolsHandler(w http.ResponseWriter, r *http.Request) {
+	pools := poolwatch.Pools()
+	monitored := map[string]bool{}
+	for _, e := range collector.GetEndpointsCopy() {
+		monitored[collector.PoolKey(e.Network, e.ExpectedPool)] = true
+	}
+
+	fmt.Fprint(w, "<html><head>\n<title>New Pools &middot; API Monitor</title>\n")
+	fmt.Fprint(w, solverStyle)
+	fmt.Fprint(w, `<h1>New Pools</h1>`)
+	fmt.Fprint(w, `<div class="subhead"><a href="/">&larr; Back to monitor</a> &middot; Pools created by the watched factories that match the filters</div>`)
+
+	if config.GetPoolWatchFile() == "" {
+		fmt.Fprint(w, `<div class="placeholder">Set POOL_WATCH_FILE to watch pool factories.</div></body></html>`)
+		return
+	}
+	if len(pools) == 0 {
+		fmt.Fprint(w, `<div class="placeholder">No matching pools created since the watch started.</div></body></html>`)
+		return
+	}
+
+	fmt.Fprint(w, `<table><thead><tr><th>Seen</th><th>Network</th><th>Type</th><th>Pool</th><th>Tokens</th><th>Block</th><th></th></tr></thead><tbody>`)
+	for _, p := range pools {
+		action := fmt.Sprintf(`<button onclick="addPool(this, '%s', '%s')">Add to monitoring</button>`,
+			html.EscapeString(p.Network), html.EscapeString(p.Address))
+		if monitored[collector.PoolKey(p.Network, p.Address)] {
+			action = "Monitored"
+		}
+		fmt.Fprintf(w, `<tr><td>%s</td><td>%s</td><td>%s</td><td class="addr">%s</td><td>%s</td><td class="num">%d</td><td>%s</td></tr>`,
+			formatTimeAgo(p.SeenAt),
+			html.EscapeString(getNetworkName(p.Network)),
+			html.EscapeString(p.PoolType),
+			html.EscapeString(p.Address),
+			html.EscapeString(p.Symbols()),
+			p.Block,
+			action)
+	}
+	fmt.Fprint(w, `</tbody></table>`)
+	fmt.Fprint(w, poolAddScript)
+	fmt.Fprint(w, `</body></html>`)
+}
+
+// poolAddScript posts a pool to /api/v1/pools/add with the session's admin
+// token and shows the import result in place of the button.
+const poolAddScript = `<script>
+function addPool(button, network, pool) {
+	let token = sessionStorage.getItem('adminToken');
+	if (!token) {
+		token = prompt('ADMIN_TOKEN');
+		if (!token) return;
+		sessionStorage.setItem('adminToken', token);
+	}
+	button.disabled = true;
+	fetch('/api/v1/pools/add', {
+		method: 'POST',
+		headers: {'Authorization': 'Bearer ' + token, 'Content-Type': 'application/json'},
+		body: JSON.stringify({network: network, pool: pool}),
+	}).then(r => r.json().then(res => {
+		if (r.status === 403) sessionStorage.removeItem('adminToken');
+		const detail = (res.errors || []).map(e => e.message).join('; ');
+		button.outerHTML = r.ok ? 'Added as ' + res.name : 'Failed: ' + (res.error || r.statusText) + (detail ? ' (' + detail + ')' : '');
+	})).catch(err => { button.disabled = false; alert(err); });
+}
+</script>`
+
+// poolAddRequest is the body of PoolAddHandler.
+type poolAddRequest struct {
+	Network string `json:"network"`
+	Pool    string `json:"pool"`
+}
+
+// poolAddResult is the JSON response of PoolAddHandler: the import result
+// and the generated BaseEndpoint row.
+type poolAddResult struct {
+	importResult
+	Name     string        `json:"name,omitempty"`
+	Endpoint *importer.Row `json:"endpoint,omitempty"`
+}
+
+// PoolAddHandler imports a pool the pool creation watch matched as a
+// BaseEndpoint at /api/v1/pools/add. The body is {"network", "pool"}; the
+// row comes from poolwatch.EndpointRow and goes through the same sizing,
+// validation and persistence as EndpointsImportHandler. Requires ADMIN_TOKEN
+// as a bearer token.
+func PoolAddHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hasScope(r, apitoken.Admin) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	var req poolAddRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes)).Decode(&req); err != nil {
+		writeImportError(w, 0, fmt.Errorf("bad request body: %v", err))
+		return
+	}
+	pool, ok := poolwatch.Lookup(req.Network, req.Pool)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(importResult{Error: "pool not found among the watched new pools"})
+		return
+	}
+	row, err := poolwatch.EndpointRow(pool)
+	if err != nil {
+		writeImportError(w, 0, err)
+		return
+	}
+
+	rows := []importer.Row{row}
+	res, status := runImport(rows, false)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(poolAddResult{importResult: res, Name: row.Name, Endpoint: &rows[0]})
+}
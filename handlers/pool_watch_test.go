@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPoolAddHandlerRejects(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	post := func(token, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/pools/add", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		PoolAddHandler(rec, req)
+		return rec.Code
+	}
+
+	body := `{"network":"8453","pool":"0x00000000000000000000000000000000000000a1"}`
+	if code := post("", body); code != http.StatusForbidden {
+		t.Errorf("no token: status %d", code)
+	}
+	if code := post("secret", "{"); code != http.StatusBadRequest {
+		t.Errorf("bad body: status %d", code)
+	}
+	if code := post("secret", body); code != http.StatusNotFound {
+		t.Errorf("unwatched pool: status %d", code)
+	}
+}
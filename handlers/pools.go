@@ -8,8 +8,9 @@ import (
 	"sort"
 	"strings"
 
-	"go-monitoring/internal/collector"
+	"go-monitoring/config"
 	"go-monitoring/internal/discovery"
+	"go-monitoring/monitoring/collector"
 )
 
 // PoolsHandler renders the discovered Balancer V3 pool list at /pools.
@@ -140,7 +141,7 @@ func renderTable(w http.ResponseWriter, pools []discovery.Pool) {
 		if sorted[i].TotalLiquidityUSD != sorted[j].TotalLiquidityUSD {
 			return sorted[i].TotalLiquidityUSD > sorted[j].TotalLiquidityUSD
 		}
-		return strings.ToLower(sorted[i].Address) < strings.ToLower(sorted[j].Address)
+		return config.NormalizeAddress(sorted[i].Address) < config.NormalizeAddress(sorted[j].Address)
 	})
 
 	fmt.Fprint(w, `<table><thead><tr>`)
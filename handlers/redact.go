@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-monitoring/monitoring/redact"
+)
+
+// redactWriter passes every response through redact.Bytes, so a provider
+// error message or response body shown on a page, in the JSON APIs or in an
+// export can't leak a key. Pages and encoders write each rendered value in
+// one Write, so a credential isn't split between two.
+type redactWriter struct {
+	http.ResponseWriter
+}
+
+func (w redactWriter) Write(b []byte) (int, error) {
+	if _, err := w.ResponseWriter.Write(redact.Bytes(b)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w redactWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-monitoring/internal/apitoken"
+	"go-monitoring/internal/metrics"
+	"go-monitoring/internal/worker"
+	"go-monitoring/monitoring/rpc/monitoringv1/monitoringv1connect"
+)
+
+var (
+	getOnly  = []string{http.MethodGet}
+	postOnly = []string{http.MethodPost}
+	jsonBody = []string{"application/json"}
+)
+
+// Routes lists every page and API the server serves. The dashboard pages,
+// the widget, badges and /metrics stay public; the JSON APIs take API tokens (see
+// API_REQUIRE_TOKEN).
+func Routes() []Route {
+	return []Route{
+		{Pattern: "/", Handler: DashboardHandler, Methods: getOnly},
+		{Pattern: "/check/{endpoint...}", Handler: CheckEndpointHandler, Methods: postOnly, Scope: apitoken.TriggerCheck},
+		{Pattern: "/pools", Handler: PoolsHandler, Methods: getOnly},
+		{Pattern: "/pools/new", Handler: NewPoolsHandler, Methods: getOnly},
+		{Pattern: "/solver/{solver...}", Handler: SolverHandler, Methods: getOnly},
+		{Pattern: "/coverage", Handler: CoverageHandler, Methods: getOnly},
+		{Pattern: "/crosschain", Handler: CrossChainHandler, Methods: getOnly},
+		{Pattern: "/widget", Handler: WidgetHandler, Methods: getOnly},
+		{Pattern: "/badge/{badge}", Handler: BadgeHandler, Methods: getOnly},
+		{Pattern: "/api/v1/config", Handler: ConfigHandler, Methods: getOnly, Scope: apitoken.Read},
+		{Pattern: "/api/v1/config/export", Handler: ConfigExportHandler, Methods: getOnly, Scope: apitoken.Read},
+		{Pattern: "/api/v1/endpoints", Handler: EndpointsHandler, Methods: getOnly, Scope: apitoken.Read},
+		{Pattern: "/api/v1/endpoints/import", Handler: EndpointsImportHandler, Methods: postOnly,
+			ContentTypes: []string{"application/json", "text/csv", "text/plain"}},
+		{Pattern: "/api/v1/endpoints/notes", Handler: EndpointNotesHandler, Methods: []string{http.MethodGet, http.MethodPost}, ContentTypes: jsonBody,
+			Scope: apitoken.Read},
+		{Pattern: "/api/v1/escalations", Handler: EscalationHandler, Methods: postOnly, ContentTypes: jsonBody},
+		{Pattern: "/api/v1/latency", Handler: LatencyHandler, Methods: getOnly, Scope: apitoken.Read},
+		{Pattern: "/api/v1/coverage", Handler: CoverageSummaryHandler, Methods: getOnly, Scope: apitoken.Read},
+		{Pattern: "/api/v1/notifications", Handler: NotificationsHandler, Methods: getOnly, Scope: apitoken.Read},
+		{Pattern: "/api/v1/pools/add", Handler: PoolAddHandler, Methods: postOnly, ContentTypes: jsonBody},
+		{Pattern: "/api/v1/series", Handler: SeriesHandler, Methods: getOnly, Scope: apitoken.Read},
+		{Pattern: "/api/v1/slo", Handler: SLOHandler, Methods: getOnly, Scope: apitoken.Read},
+		{Pattern: "/grafana/{$}", Handler: GrafanaHandler, Methods: getOnly, Scope: apitoken.Read},
+		{Pattern: "/grafana/search", Handler: GrafanaHandler, Methods: postOnly, ContentTypes: jsonBody, Scope: apitoken.Read},
+		{Pattern: "/grafana/query", Handler: GrafanaHandler, Methods: postOnly, ContentTypes: jsonBody, Scope: apitoken.Read},
+		{Pattern: "/grafana/annotations", Handler: GrafanaHandler, Methods: postOnly, ContentTypes: jsonBody, Scope: apitoken.Read},
+		{Pattern: "/metrics", Handler: metrics.Handler, Methods: getOnly},
+		{Pattern: worker.ResultsPath, Handler: WorkerResultsHandler, Methods: postOnly, ContentTypes: jsonBody},
+		{Pattern: "/" + monitoringv1connect.MonitoringServiceName + "/{procedure...}", Handler: MonitoringServiceHandler, Methods: postOnly,
+			ContentTypes: rpcContentTypes, RawBody: true},
+	}
+}
+
+// Register adds Routes to mux, each behind Validate and logRequests.
+func Register(mux *http.ServeMux) {
+	for _, rt := range Routes() {
+		mux.HandleFunc(rt.Pattern, logRequests(rt, Validate(rt)))
+	}
+}
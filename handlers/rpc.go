@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/apitoken"
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+	"go-monitoring/monitoring/redact"
+	"go-monitoring/monitoring/rpc/monitoringv1"
+	"go-monitoring/monitoring/rpc/monitoringv1/monitoringv1connect"
+)
+
+// maxRPCRequestBytes bounds a request message; every request is a few fields.
+const maxRPCRequestBytes = 64 << 10
+
+// rpcContentTypes are the request encodings MonitoringService accepts: the
+// Connect protocol's unary binary and JSON, gRPC and gRPC-Web.
+var rpcContentTypes = []string{
+	"application/proto", "application/json",
+	"application/grpc", "application/grpc+proto", "application/grpc+json",
+	"application/grpc-web", "application/grpc-web+proto", "application/grpc-web+json",
+}
+
+// rpcMethods are MonitoringService's procedures, from the .proto.
+var rpcMethods = monitoringv1.File_monitoring_v1_monitoring_proto.Services().
+	ByName("MonitoringService").Methods()
+
+var monitoringServiceHandler = func() http.Handler {
+	_, h := monitoringv1connect.NewMonitoringServiceHandler(monitoringService{},
+		connect.WithInterceptors(rpcAuthorize(), rpcRedact()),
+		connect.WithReadMaxBytes(maxRPCRequestBytes))
+	return h
+}()
+
+// MonitoringServiceHandler serves MonitoringService (proto/monitoring/v1) at
+// /monitoring.v1.MonitoringService/{procedure} with the handler generated
+// into monitoringv1connect, so Connect, gRPC and gRPC-Web clients can call
+// it in either encoding. An unknown procedure gets an unimplemented error in
+// the caller's protocol rather than a plain 404.
+func MonitoringServiceHandler(w http.ResponseWriter, r *http.Request) {
+	if rpcMethods.ByName(protoreflect.Name(r.PathValue("procedure"))) == nil {
+		connect.NewErrorWriter().Write(w, r, connect.NewError(connect.CodeUnimplemented,
+			errors.New(strings.TrimPrefix(r.URL.Path, "/")+" is not implemented")))
+		return
+	}
+	monitoringServiceHandler.ServeHTTP(w, r)
+}
+
+// rpcAuthorize checks the caller's token with API_REQUIRE_TOKEN set:
+// TriggerCheck needs the trigger-check scope, the other procedures read.
+func rpcAuthorize() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if !config.GetAPIRequireToken() {
+				return next(ctx, req)
+			}
+			scope := apitoken.Read
+			if req.Spec().Procedure == monitoringv1connect.MonitoringServiceTriggerCheckProcedure {
+				scope = apitoken.TriggerCheck
+			}
+			switch tokenGrants(req.Header(), scope) {
+			case tokenUnknown:
+				return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("an API token is required"))
+			case tokenDenied:
+				return nil, connect.NewError(connect.CodePermissionDenied, errors.New("token lacks the "+string(scope)+" scope"))
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// rpcRedact scrubs credentials from every string in a response and from
+// error messages. Validate's redactWriter can't: the binary encodings would
+// be corrupted by rewriting their bytes.
+func rpcRedact() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			res, err := next(ctx, req)
+			var cerr *connect.Error
+			if errors.As(err, &cerr) {
+				return nil, connect.NewError(cerr.Code(), errors.New(redact.String(cerr.Message())))
+			} else if err != nil {
+				return nil, connect.NewError(connect.CodeInternal, errors.New(redact.String(err.Error())))
+			}
+			if m, ok := res.Any().(interface{ ProtoReflect() protoreflect.Message }); ok {
+				redactMessage(m.ProtoReflect())
+			}
+			return res, nil
+		}
+	}
+}
+
+// redactMessage runs redact.String over m's string fields, in nested
+// messages, lists and map values too.
+func redactMessage(m protoreflect.Message) {
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	for _, fd := range fields {
+		v := m.Get(fd)
+		switch {
+		case fd.IsMap():
+			redactMap(v.Map(), fd.MapValue())
+		case fd.IsList():
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				switch fd.Kind() {
+				case protoreflect.StringKind:
+					l.Set(i, protoreflect.ValueOfString(redact.String(l.Get(i).String())))
+				case protoreflect.MessageKind, protoreflect.GroupKind:
+					redactMessage(l.Get(i).Message())
+				}
+			}
+		case fd.Kind() == protoreflect.StringKind:
+			m.Set(fd, protoreflect.ValueOfString(redact.String(v.String())))
+		case fd.Kind() == protoreflect.MessageKind, fd.Kind() == protoreflect.GroupKind:
+			redactMessage(v.Message())
+		}
+	}
+}
+
+func redactMap(mv protoreflect.Map, vd protoreflect.FieldDescriptor) {
+	var keys []protoreflect.MapKey
+	mv.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	for _, k := range keys {
+		switch vd.Kind() {
+		case protoreflect.StringKind:
+			mv.Set(k, protoreflect.ValueOfString(redact.String(mv.Get(k).String())))
+		case protoreflect.MessageKind, protoreflect.GroupKind:
+			redactMessage(mv.Get(k).Message())
+		}
+	}
+}
+
+// monitoringService implements the procedures over the collector's state.
+type monitoringService struct{}
+
+// ListEndpoints applies the request's filters with the dashboard's matching
+// rules: network by ID or name, solver by type or display name, and "down"
+// for any failing status.
+func (monitoringService) ListEndpoints(_ context.Context, req *connect.Request[monitoringv1.ListEndpointsRequest]) (*connect.Response[monitoringv1.ListEndpointsResponse], error) {
+	f := dashboardFilter{
+		Network: strings.TrimSpace(req.Msg.GetNetwork()),
+		Solver:  strings.TrimSpace(req.Msg.GetRouteSolver()),
+		Status:  strings.ToLower(strings.TrimSpace(req.Msg.GetStatus())),
+	}
+	res := &monitoringv1.ListEndpointsResponse{}
+	for _, e := range f.apply(collector.GetEndpointsCopy()) {
+		res.Endpoints = append(res.Endpoints, toRPCEndpoint(e, false))
+	}
+	if req.Msg.GetIncludeDiscovered() {
+		for _, e := range f.apply(collector.GetDiscoveredEndpointsCopy()) {
+			res.Endpoints = append(res.Endpoints, toRPCEndpoint(e, true))
+		}
+	}
+	return connect.NewResponse(res), nil
+}
+
+func (monitoringService) GetEndpoint(_ context.Context, req *connect.Request[monitoringv1.GetEndpointRequest]) (*connect.Response[monitoringv1.GetEndpointResponse], error) {
+	name, err := resolveRPCName(req.Msg.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if e := collector.GetEndpointByName(name); e != nil {
+		return connect.NewResponse(&monitoringv1.GetEndpointResponse{Endpoint: toRPCEndpoint(*e, false)}), nil
+	}
+	if e := collector.GetDiscoveredEndpointByName(name); e != nil {
+		return connect.NewResponse(&monitoringv1.GetEndpointResponse{Endpoint: toRPCEndpoint(*e, true)}), nil
+	}
+	return nil, endpointNotFound(req.Msg.GetName())
+}
+
+func (monitoringService) GetHistory(_ context.Context, req *connect.Request[monitoringv1.GetHistoryRequest]) (*connect.Response[monitoringv1.GetHistoryResponse], error) {
+	name, err := resolveRPCName(req.Msg.GetName())
+	if err != nil {
+		return nil, err
+	}
+	since := clock.Now().Add(-24 * time.Hour)
+	if req.Msg.GetSince() != nil {
+		since = req.Msg.GetSince().AsTime()
+	}
+
+	records := collector.GetHistory(name)
+	res := &monitoringv1.GetHistoryResponse{Uptime: collector.SummarizeHistory(records, since).Uptime()}
+	for _, rec := range records {
+		if rec.At.Before(since) {
+			continue
+		}
+		res.Records = append(res.Records, &monitoringv1.CheckRecord{At: timestamppb.New(rec.At), Status: rec.Status, Message: rec.Message})
+	}
+	return connect.NewResponse(res), nil
+}
+
+// TriggerCheck runs the check synchronously, like the dashboard's Check now
+// button and under the same MANUAL_CHECK_INTERVAL throttle, and returns the
+// updated row and the check's result.
+func (monitoringService) TriggerCheck(_ context.Context, req *connect.Request[monitoringv1.TriggerCheckRequest]) (*connect.Response[monitoringv1.TriggerCheckResponse], error) {
+	name, err := resolveRPCName(req.Msg.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if wait := reserveManualCheck(name, clock.Now()); wait > 0 {
+		return nil, connect.NewError(connect.CodeResourceExhausted, errors.New(throttledMessage(name, wait)))
+	}
+	e, res, discovered, ok := checkEndpointNow(name)
+	if !ok {
+		return nil, endpointNotFound(req.Msg.GetName())
+	}
+	return connect.NewResponse(&monitoringv1.TriggerCheckResponse{Endpoint: toRPCEndpoint(e, discovered), Result: toRPCCheckResult(res)}), nil
+}
+
+// resolveRPCName maps a request's ID or name to the row's name.
+func resolveRPCName(ref string) (string, error) {
+	if ref == "" {
+		return "", connect.NewError(connect.CodeInvalidArgument, errors.New("name is required"))
+	}
+	name, ok := collector.ResolveEndpoint(ref)
+	if !ok {
+		return "", endpointNotFound(ref)
+	}
+	return name, nil
+}
+
+func toRPCCheckResult(r providers.CheckResult) *monitoringv1.CheckResult {
+	return &monitoringv1.CheckResult{
+		Status:       r.Status,
+		Message:      r.Message,
+		ErrorClass:   r.ErrorClass,
+		ReturnAmount: r.ReturnAmount,
+		MarketPrice:  r.MarketPrice,
+		HttpStatus:   int32(r.HTTPStatus),
+		RequestId:    r.RequestID,
+		LatencyMs:    float64(r.Latency) / float64(time.Millisecond),
+		CheckedAt:    timestampOrNil(r.CheckedAt),
+	}
+}
+
+func endpointNotFound(name string) error {
+	return connect.NewError(connect.CodeNotFound, errors.New("endpoint "+name+" not found"))
+}
+
+func toRPCEndpoint(e collector.Endpoint, discovered bool) *monitoringv1.Endpoint {
+	return &monitoringv1.Endpoint{
+		Name:            e.Name,
+		Id:              e.ID,
+		BaseName:        e.BaseName,
+		Network:         e.Network,
+		RouteSolver:     e.RouteSolver,
+		SolverName:      e.SolverName,
+		ExpectedPool:    e.ExpectedPool,
+		Discovered:      discovered,
+		Status:          e.LastStatus,
+		Message:         e.Message,
+		LastChecked:     timestampOrNil(e.LastChecked),
+		LastStateChange: timestampOrNil(e.LastStateChange),
+		FirstSeenDown:   timestampOrNil(e.FirstSeenDown),
+		ReturnAmount:    e.ReturnAmount,
+		MarketPrice:     e.MarketPrice,
+		OnChainPrice:    e.OnChainPrice,
+		Labels:          maps.Clone(e.Labels), // rpcRedact rewrites the values in place
+	}
+}
+
+// timestampOrNil maps the zero time to an unset field.
+func timestampOrNil(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
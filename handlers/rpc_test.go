@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/rpc"
+	"go-monitoring/monitoring/rpc/monitoringv1"
+	"go-monitoring/monitoring/rpc/monitoringv1/monitoringv1connect"
+)
+
+func TestMonitoringServiceRoundTrip(t *testing.T) {
+	checked := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	collector.SetEndpoints([]collector.Endpoint{
+		{Name: "Odos-GHO/USDC", BaseName: "GHO/USDC", Network: "42161", RouteSolver: "odos", LastStatus: "up", LastChecked: checked},
+		{Name: "Kyber-GHO/USDC", BaseName: "GHO/USDC", Network: "42161", RouteSolver: "kyberswap", LastStatus: "down", Message: "no route"},
+		{Name: "Odos-WETH/USDC", BaseName: "WETH/USDC", Network: "1", RouteSolver: "odos", LastStatus: "up"},
+	})
+	collector.SetHistory("Odos-GHO/USDC", []collector.CheckRecord{
+		{At: checked.Add(-48 * time.Hour), Status: "down"},
+		{At: checked.Add(-time.Hour), Status: "down"},
+		{At: checked, Status: "up"},
+	})
+	t.Cleanup(func() {
+		collector.SetEndpoints(nil)
+		collector.SetHistory("Odos-GHO/USDC", nil)
+	})
+
+	srv := httptest.NewUnstartedServer(rpcMux())
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		name string
+		opts []connect.ClientOption
+	}{
+		{"connect+proto", nil},
+		{"connect+json", []connect.ClientOption{connect.WithProtoJSON()}},
+		{"grpc", []connect.ClientOption{connect.WithGRPC()}},
+		{"grpc-web", []connect.ClientOption{connect.WithGRPCWeb()}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := rpc.NewClientWithHTTP(srv.Client(), srv.URL, tt.opts...)
+			ctx := context.Background()
+
+			list, err := c.ListEndpoints(ctx, connect.NewRequest(&monitoringv1.ListEndpointsRequest{Network: "arbitrum", Status: "down"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if eps := list.Msg.GetEndpoints(); len(eps) != 1 || eps[0].GetName() != "Kyber-GHO/USDC" {
+				t.Fatalf("ListEndpoints(arbitrum, down) = %v", eps)
+			}
+
+			got, err := c.GetEndpoint(ctx, connect.NewRequest(&monitoringv1.GetEndpointRequest{Name: "Odos-GHO/USDC"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e := got.Msg.GetEndpoint(); e.GetStatus() != "up" || !e.GetLastChecked().AsTime().Equal(checked) || e.GetFirstSeenDown() != nil {
+				t.Fatalf("GetEndpoint = %v", e)
+			}
+
+			hist, err := c.GetHistory(ctx, connect.NewRequest(&monitoringv1.GetHistoryRequest{Name: "Odos-GHO/USDC"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(hist.Msg.GetRecords()) != 2 || hist.Msg.GetUptime() != 0.5 {
+				t.Fatalf("GetHistory = %v", hist.Msg)
+			}
+
+			_, err = c.GetEndpoint(ctx, connect.NewRequest(&monitoringv1.GetEndpointRequest{Name: "missing"}))
+			if connect.CodeOf(err) != connect.CodeNotFound {
+				t.Fatalf("GetEndpoint(missing) error = %v, want not_found", err)
+			}
+			_, err = c.TriggerCheck(ctx, connect.NewRequest(&monitoringv1.TriggerCheckRequest{}))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Fatalf("TriggerCheck(no name) error = %v, want invalid_argument", err)
+			}
+		})
+	}
+}
+
+func TestMonitoringServiceRoutedAcceptsBinary(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, monitoringv1connect.MonitoringServiceListEndpointsProcedure, nil)
+	req.Header.Set("Content-Type", "application/proto")
+	if rec := serve(req); rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/proto" {
+		t.Fatalf("application/proto: %d %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+}
+
+func TestMonitoringServiceUnknownProcedure(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/"+monitoringv1connect.MonitoringServiceName+"/DeleteEverything", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	rec := serve(req)
+	var body struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if rec.Code != http.StatusNotImplemented || json.Unmarshal(rec.Body.Bytes(), &body) != nil || body.Code != "unimplemented" {
+		t.Fatalf("unknown procedure = %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestMonitoringServiceRedactsResponses(t *testing.T) {
+	collector.SetEndpoints([]collector.Endpoint{{
+		Name: "Odos-X", LastStatus: "down", Labels: map[string]string{"url": "https://api.example.com/?apikey=k-9f8e7d6c"},
+		Message: `Get "https://api.example.com/quote?apikey=k-9f8e7d6c&amount=1": timeout`,
+	}})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+	srv := httptest.NewServer(rpcMux())
+	defer srv.Close()
+
+	res, err := rpc.NewClient(srv.URL).GetEndpoint(context.Background(), connect.NewRequest(&monitoringv1.GetEndpointRequest{Name: "Odos-X"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := res.Msg.GetEndpoint(); strings.Contains(e.GetMessage(), "k-9f8e7d6c") || strings.Contains(e.GetLabels()["url"], "k-9f8e7d6c") {
+		t.Errorf("the API key was not redacted: %v", e)
+	}
+	if got := collector.GetEndpointByName("Odos-X").Labels["url"]; !strings.Contains(got, "k-9f8e7d6c") {
+		t.Errorf("redaction rewrote the collector's labels: %q", got)
+	}
+}
+
+func TestMonitoringServiceRequiresScopedToken(t *testing.T) {
+	t.Setenv("API_REQUIRE_TOKEN", "true")
+	t.Setenv("ADMIN_TOKEN", "admin-secret")
+	srv := httptest.NewServer(rpcMux())
+	defer srv.Close()
+	ctx := context.Background()
+
+	_, err := rpc.NewClient(srv.URL).ListEndpoints(ctx, connect.NewRequest(&monitoringv1.ListEndpointsRequest{}))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("ListEndpoints without a token: %v, want unauthenticated", err)
+	}
+	if _, err := rpc.NewClient(srv.URL, rpc.WithToken("admin-secret")).ListEndpoints(ctx, connect.NewRequest(&monitoringv1.ListEndpointsRequest{})); err != nil {
+		t.Fatalf("ListEndpoints with ADMIN_TOKEN: %v", err)
+	}
+}
+
+// rpcMux serves MonitoringServiceHandler at its route, so the procedure is
+// a path value.
+func rpcMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+monitoringv1connect.MonitoringServiceName+"/{procedure...}", MonitoringServiceHandler)
+	return mux
+}
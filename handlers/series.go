@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+)
+
+// maxSeriesRange covers a year of archived hourly aggregates.
+const maxSeriesRange = 366 * 24 * time.Hour
+
+// seriesResponse is the JSON response of SeriesHandler. Annotations are
+// the deploys and the endpoint's config edits in the range, for charts to
+// overlay.
+type seriesResponse struct {
+	Endpoint    string              `json:"endpoint"`
+	Bucket      string              `json:"bucket"`
+	Since       time.Time           `json:"since"`
+	Points      []store.SeriesPoint `json:"points"`
+	Annotations []store.Annotation  `json:"annotations"`
+}
+
+// SeriesHandler serves /api/v1/series?endpoint=ID_OR_NAME&range=30d&bucket=1d: an
+// endpoint's uptime counts and min/max/avg quote per bucket, for charts,
+// with the deploys and config edits to mark on them.
+// bucket is 1h or 1d and defaults to 1h for ranges up to a week, 1d beyond.
+// With a store the series reaches back through its archived aggregates
+// (range up to a year); without one it covers the in-memory week.
+func SeriesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	ref := q.Get("endpoint")
+	if ref == "" {
+		http.Error(w, "endpoint is required", http.StatusBadRequest)
+		return
+	}
+	name, ok := collector.ResolveEndpoint(ref)
+	if !ok {
+		http.Error(w, "Endpoint not found", http.StatusNotFound)
+		return
+	}
+
+	rng := maxFilterRange
+	if s := q.Get("range"); s != "" {
+		d, err := parseRangeUpTo(s, maxSeriesRange)
+		if err != nil {
+			http.Error(w, "invalid range: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		rng = d
+	}
+	label := q.Get("bucket")
+	if label == "" {
+		label = "1h"
+		if rng > maxFilterRange {
+			label = "1d"
+		}
+	}
+	var bucket time.Duration
+	switch label {
+	case "1h":
+		bucket = time.Hour
+	case "1d":
+		bucket = 24 * time.Hour
+	default:
+		http.Error(w, "bucket must be 1h or 1d", http.StatusBadRequest)
+		return
+	}
+
+	since := clock.Now().Add(-rng)
+	points, err := endpointSeries(name, since, bucket)
+	if err != nil {
+		http.Error(w, "query series: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res := seriesResponse{Endpoint: name, Bucket: label, Since: since, Points: points, Annotations: []store.Annotation{}}
+	if e := endpointByName(name); e != nil {
+		if res.Annotations, err = changeAnnotations(since, map[string]bool{e.BaseName: true}); err != nil {
+			http.Error(w, "query annotations: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// changeAnnotations returns the deploys since a time and the config edits of
+// the given BaseEndpoints (every one when baseNames is nil), oldest first.
+func changeAnnotations(since time.Time, baseNames map[string]bool) ([]store.Annotation, error) {
+	all, err := monitor.Annotations(since)
+	if err != nil {
+		return nil, err
+	}
+	out := []store.Annotation{}
+	for _, a := range all {
+		if a.BaseName == "" || baseNames == nil || baseNames[a.BaseName] {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// endpointSeries downsamples the named endpoint's checks since a time into
+// bucket-wide points: from the store, through its archived aggregates, or
+// without one from the in-memory week.
+func endpointSeries(name string, since time.Time, bucket time.Duration) ([]store.SeriesPoint, error) {
+	if st := monitor.GetStore(); st != nil {
+		return store.QuerySeries(st, name, since, bucket)
+	}
+	return store.Downsample(nil, recentHistory(name, since), bucket), nil
+}
+
+// endpointHistory returns the named endpoint's raw checks since a time,
+// oldest first: from the store when there is one, else from memory.
+func endpointHistory(name string, since time.Time) ([]collector.CheckRecord, error) {
+	if st := monitor.GetStore(); st != nil {
+		return st.QueryHistory(name, since)
+	}
+	return recentHistory(name, since), nil
+}
+
+// recentHistory is the in-memory history of the named endpoint since a time.
+func recentHistory(name string, since time.Time) []collector.CheckRecord {
+	var recent []collector.CheckRecord
+	for _, rec := range collector.GetHistory(name) {
+		if !rec.At.Before(since) {
+			recent = append(recent, rec)
+		}
+	}
+	return recent
+}
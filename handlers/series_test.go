@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/monitor"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestSeriesHandlerFromMemory(t *testing.T) {
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-GHO/USDC", RouteSolver: "odos"}})
+	now := time.Now().UTC()
+	collector.SetHistory("Odos-GHO/USDC", []collector.CheckRecord{
+		{At: now.Add(-3 * time.Hour), Status: "up", ReturnAmount: "100"},
+		{At: now.Add(-2 * time.Hour), Status: "up", ReturnAmount: "300"},
+	})
+	t.Cleanup(func() {
+		collector.SetEndpoints(nil)
+		collector.SetHistory("Odos-GHO/USDC", nil)
+	})
+
+	rec := httptest.NewRecorder()
+	SeriesHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/series?endpoint=Odos-GHO/USDC&range=30d", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var res seriesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Bucket != "1d" || len(res.Points) == 0 {
+		t.Fatalf("series = %+v", res)
+	}
+	quotes := 0
+	for _, p := range res.Points {
+		quotes += p.Quotes
+	}
+	if quotes != 2 {
+		t.Fatalf("points = %+v, want 2 quotes", res.Points)
+	}
+
+	for query, want := range map[string]int{
+		"endpoint=missing":                   http.StatusNotFound,
+		"endpoint=Odos-GHO/USDC&bucket=5m":   http.StatusBadRequest,
+		"endpoint=Odos-GHO/USDC&range=1000d": http.StatusBadRequest,
+		"":                                   http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		SeriesHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/series?"+query, nil))
+		if rec.Code != want {
+			t.Errorf("%q: status = %d, want %d", query, rec.Code, want)
+		}
+	}
+}
+
+func TestSeriesHandlerAnnotations(t *testing.T) {
+	monitor.SetStore(store.NewFileStore(filepath.Join(t.TempDir(), "state.json")))
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-GHO/USDC", BaseName: "GHO/USDC", RouteSolver: "odos"}})
+	t.Cleanup(func() {
+		monitor.SetStore(nil)
+		collector.SetEndpoints(nil)
+	})
+	now := time.Now().UTC()
+	monitor.RecordAnnotation(store.Annotation{At: now.Add(-10 * 24 * time.Hour), Kind: store.AnnotationDeploy, Text: "v0.9.0"})
+	monitor.RecordAnnotation(store.Annotation{At: now.Add(-2 * time.Hour), Kind: store.AnnotationDeploy, Text: "v1.0.0"})
+	monitor.RecordAnnotation(store.Annotation{At: now.Add(-time.Hour), Kind: store.AnnotationConfig, BaseName: "GHO/USDC", Text: "edited by import"})
+	monitor.RecordAnnotation(store.Annotation{At: now.Add(-time.Hour), Kind: store.AnnotationConfig, BaseName: "WETH/USDC", Text: "added by import"})
+
+	rec := httptest.NewRecorder()
+	SeriesHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/series?endpoint=Odos-GHO/USDC&range=7d", nil))
+	var res seriesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Annotations) != 2 || res.Annotations[0].Text != "v1.0.0" || res.Annotations[1].BaseName != "GHO/USDC" {
+		t.Errorf("annotations = %+v, want the range's deploy and this endpoint's edit", res.Annotations)
+	}
+}
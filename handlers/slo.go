@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/monitor"
+)
+
+// SLOHandler serves /api/v1/slo: each enabled solver's availability and
+// response time SLOs over SLO_WINDOW_DAYS, with attainment, the error budget
+// left and burn rates. ?solver= limits it to one solver (type or name).
+func SLOHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	solvers := config.GetEnabledRouteSolvers()
+	if name := r.URL.Query().Get("solver"); name != "" {
+		solver, ok := findRouteSolver(name)
+		if !ok {
+			http.Error(w, "Solver not found", http.StatusNotFound)
+			return
+		}
+		solvers = []config.RouteSolver{solver}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(monitor.SLOReport(solvers, clock.Now()))
+}
+
+// sloTable renders the solver page's service level section: one row per
+// SLO with its attainment over the SLO window, the error budget left and the
+// burn rates. "" when the solver has no SLOs.
+func sloTable(statuses []monitor.SLOStatus) string {
+	if len(statuses) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<h2>Service levels (%dd)</h2><table><thead><tr><th>Objective</th><th>Attained</th><th>Error budget left</th><th>Burn rate</th></tr></thead><tbody>`,
+		config.GetSLOWindow()/(24*time.Hour))
+	for _, st := range statuses {
+		attained, class := "&mdash;", "status-unknown"
+		if st.Attained >= 0 {
+			attained = fmt.Sprintf("%.2f%% <span class='checks'>(%d)</span>", st.Attained, st.Total)
+			class = "status-up"
+			if st.Attained < st.Target {
+				class = "status-down"
+			}
+		}
+		budget := "&mdash;"
+		if st.Total > 0 {
+			budget = fmt.Sprintf("%.0f%%", 100*st.BudgetRemaining)
+		}
+		var rates []string
+		for _, br := range st.BurnRates {
+			rates = append(rates, fmt.Sprintf("%s %.1fx", br.Window, br.Rate))
+		}
+		burn := html.EscapeString(strings.Join(rates, " · "))
+		if st.Alerting != "" {
+			burn += fmt.Sprintf(` <span class="status-down">%s burn</span>`, html.EscapeString(st.Alerting))
+		}
+		fmt.Fprintf(&b, `<tr><td>%s</td><td class="num %s">%s</td><td class="num">%s</td><td>%s</td></tr>`,
+			html.EscapeString(st.Objective), class, attained, budget, burn)
+	}
+	b.WriteString(`</tbody></table>`)
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/internal/monitor"
+)
+
+func TestSLOHandlerUnknownSolver(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SLOHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/slo?solver=nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", rec.Code)
+	}
+}
+
+func TestSLOTable(t *testing.T) {
+	got := sloTable([]monitor.SLOStatus{{
+		Objective: "99% of quotes < 3s", Target: 99, Good: 95, Total: 100, Attained: 95, BudgetRemaining: -4,
+		BurnRates: []monitor.BurnRate{{Window: "1h", Rate: 5}, {Window: "6h", Rate: 6.5}}, Alerting: "fast",
+	}})
+	for _, want := range []string{"99% of quotes &lt; 3s", `class="num status-down">95.00%`, "-400%", "1h 5.0x · 6h 6.5x", "fast burn"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in %s", want, got)
+		}
+	}
+	if sloTable(nil) != "" {
+		t.Fatal("no SLOs rendered a table")
+	}
+}
@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/i18n"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/providers"
+)
+
+// featureNames lists the features caps supports and those it doesn't,
+// comma-separated and translated with t; "&mdash;" when none is supported.
+func featureNames(caps providers.Capabilities, t func(string, ...string) string) (supported, unsupported string) {
+	var yes, no []string
+	for _, f := range caps.Features() {
+		if f.Supported {
+			yes = append(yes, t("feature."+f.ID))
+		} else {
+			no = append(no, t("feature."+f.ID))
+		}
+	}
+	if len(yes) == 0 {
+		return "&mdash;", strings.Join(no, ", ")
+	}
+	return strings.Join(yes, ", "), strings.Join(no, ", ")
+}
+
+// SolverHandler renders /solver/{name}: every endpoint of one aggregator,
+// base and discovered, with uptime over the in-memory history, its SLOs and
+// the most common failure messages. Written to be linked directly to that
+// aggregator's integration team, so its labels, statuses and failure
+// summaries come from the i18n catalog in the language of ?lang= or
+// Accept-Language. {name} matches the solver type or display name,
+// case-insensitively (e.g. /solver/kyberswap).
+func SolverHandler(w http.ResponseWriter, r *http.Request) {
+	solver, ok := findRouteSolver(r.PathValue("solver"))
+	if !ok {
+		http.Error(w, "Solver not found", http.StatusNotFound)
+		return
+	}
+
+	endpoints := solverEndpoints(solver.Type)
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].BaseName < endpoints[j].BaseName
+	})
+
+	now := clock.Now()
+	day := now.Add(-24 * time.Hour)
+	week := now.Add(-7 * 24 * time.Hour)
+
+	var total24h, total7d collector.HistorySummary
+	var allRecords []collector.CheckRecord
+	upNow := 0
+	for _, e := range endpoints {
+		h := collector.GetHistory(e.Name)
+		total24h.Add(collector.SummarizeHistory(h, day))
+		total7d.Add(collector.SummarizeHistory(h, week))
+		allRecords = append(allRecords, h...)
+		if e.LastStatus == "up" {
+			upNow++
+		}
+	}
+
+	catalog := i18n.Messages()
+	l := catalog.For(catalog.Negotiate(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language")))
+	t := func(id string, args ...string) string { return html.EscapeString(l.T(id, args...)) }
+
+	fmt.Fprintf(w, "<html lang=\"%s\"><head>\n<meta charset=\"utf-8\">\n<title>%s &middot; API Monitor</title>\n", html.EscapeString(l.Lang), html.EscapeString(solver.Name))
+	fmt.Fprint(w, solverStyle)
+	fmt.Fprintf(w, `<h1>%s</h1>`, html.EscapeString(solver.Name))
+	fmt.Fprintf(w, `<div class="subhead"><a href="/">&larr; %s</a> &middot; %s &middot; %s</div>`,
+		t("page.back"), t("page.generated", "time", now.UTC().Format("2006-01-02 15:04 MST")), languageLinks(catalog, l.Lang))
+
+	fmt.Fprint(w, `<div class="summary">`)
+	fmt.Fprintf(w, `<div><span class="label">%s</span>%d</div>`, t("summary.endpoints"), len(endpoints))
+	fmt.Fprintf(w, `<div><span class="label">%s</span>%d / %d</div>`, t("summary.up_now"), upNow, len(endpoints))
+	fmt.Fprintf(w, `<div><span class="label">%s</span>%s</div>`, t("summary.uptime_24h"), formatUptime(total24h))
+	fmt.Fprintf(w, `<div><span class="label">%s</span>%s</div>`, t("summary.uptime_7d"), formatUptime(total7d))
+	fmt.Fprintf(w, `<div><span class="label">%s</span>%s</div>`, t("summary.networks"), html.EscapeString(networkNames(solver.SupportedNetworks)))
+	if caps, ok := monitor.SolverCapabilities(solver.Type); ok {
+		supported, unsupported := featureNames(caps, t)
+		fmt.Fprintf(w, `<div><span class="label">%s</span>%s</div>`, t("summary.features"), supported)
+		if unsupported != "" {
+			fmt.Fprintf(w, `<div><span class="label">%s</span>%s</div>`, t("summary.unsupported"), unsupported)
+		}
+	}
+	fmt.Fprint(w, `</div>`)
+	fmt.Fprint(w, sloTable(monitor.SLOReport([]config.RouteSolver{solver}, now)))
+	fmt.Fprint(w, latencyTable(monitor.LatencyReport([]config.RouteSolver{solver}, now)))
+	fmt.Fprint(w, exclusionTable(monitor.ExclusionResults(solver.Type)))
+
+	if len(endpoints) == 0 {
+		fmt.Fprintf(w, `<div class="placeholder">%s</div></body></html>`, t("endpoints.none"))
+		return
+	}
+
+	onChain, responses := false, false
+	for _, e := range endpoints {
+		onChain = onChain || e.OnChainRPCHost != "" || e.OnChainQueryError != ""
+		responses = responses || e.HTTPStatus != 0
+	}
+	extraHeaders := ""
+	if responses {
+		extraHeaders += "<th>" + t("column.last_response") + "</th>"
+	}
+	if onChain {
+		extraHeaders += "<th>" + t("column.on_chain") + "</th>"
+	}
+	fmt.Fprintf(w, `<h2>%s</h2><table><thead><tr><th>%s</th><th>%s</th><th>%s</th><th>%s</th><th>%s</th><th>%s</th><th>%s</th>%s<th>%s</th></tr></thead><tbody>`,
+		t("endpoints.heading"), t("column.pair"), t("column.network"), t("column.status"), t("column.uptime_24h"), t("column.uptime_7d"),
+		t("column.last_checked"), t("column.message"), extraHeaders, t("column.notes"))
+	for _, e := range endpoints {
+		h := collector.GetHistory(e.Name)
+		extraCells := ""
+		if responses {
+			extraCells += "<td>" + responseDisplay(e) + "</td>"
+		}
+		if onChain {
+			extraCells += "<td>" + onChainQueryDisplay(e) + "</td>"
+		}
+		fmt.Fprintf(w, `<tr><td>%s<br><span class="addr">pool %s</span></td><td>%s</td><td class="%s"%s>%s%s</td><td class="num">%s</td><td class="num">%s</td><td>%s</td><td>%s</td>%s<td>%s</td></tr>`,
+			html.EscapeString(e.BaseName),
+			html.EscapeString(e.ExpectedPool),
+			html.EscapeString(getNetworkName(e.Network)),
+			statusClassFor(e.LastStatus),
+			stateChangeTitle(l, e),
+			html.EscapeString(l.Status(e.LastStatus)),
+			downForDisplay(l, e),
+			formatUptime(collector.SummarizeHistory(h, day)),
+			formatUptime(collector.SummarizeHistory(h, week)),
+			formatTimeAgo(e.LastChecked),
+			messageDisplay(l, e)+routeDisplay(e),
+			extraCells,
+			noteDisplay(e)+escalateDisplay(e))
+	}
+	fmt.Fprint(w, `</tbody></table>`)
+	fmt.Fprint(w, noteEditScript)
+	fmt.Fprint(w, escalateScript)
+
+	causes := collector.FailureCauses(allRecords, week)
+	fmt.Fprintf(w, `<h2>%s</h2>`, t("causes.heading"))
+	if len(causes) == 0 {
+		fmt.Fprintf(w, `<div class="placeholder">%s</div></body></html>`, t("causes.none"))
+		return
+	}
+	if len(causes) > 10 {
+		causes = causes[:10]
+	}
+	fmt.Fprintf(w, `<table><thead><tr><th>%s</th><th>%s</th><th>%s</th><th>%s</th></tr></thead><tbody>`,
+		t("column.count"), t("column.last_seen"), t("column.message"), t("column.hint"))
+	hintEndpoint := collector.Endpoint{RouteSolver: solver.Type}
+	for _, c := range causes {
+		fmt.Fprintf(w, `<tr><td class="num">%d</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+			c.Count, formatTimeAgo(c.LastSeen), failureDisplay(l, c.Message),
+			html.EscapeString(notify.RemediationHint(&hintEndpoint, c.Message, "")))
+	}
+	fmt.Fprint(w, `</tbody></table></body></html>`)
+}
+
+// messageDisplay renders an endpoint's latest message, under the localized
+// summary of its failure class while it isn't up.
+func messageDisplay(l i18n.Localizer, e collector.Endpoint) string {
+	if e.LastStatus == "up" {
+		return html.EscapeString(e.Message)
+	}
+	return failureDisplay(l, e.Message)
+}
+
+// failureDisplay renders a failure message under the localized summary of
+// its class, e.g. "No route was returned" over the provider's error; just
+// the message when no class matches or the catalog doesn't summarize it.
+func failureDisplay(l i18n.Localizer, message string) string {
+	h, ok := notify.MatchHint(notify.Hints(), message, "")
+	if !ok || l.Failure(h.Class) == "" {
+		return html.EscapeString(message)
+	}
+	return fmt.Sprintf(`<strong>%s</strong><br><span class="addr">%s</span>`, html.EscapeString(l.Failure(h.Class)), html.EscapeString(message))
+}
+
+// languageLinks renders the catalog's languages by name, each but the
+// current one linking to the page in it.
+func languageLinks(c i18n.Catalog, current string) string {
+	var parts []string
+	for _, lang := range c.Languages() {
+		name := html.EscapeString(c.For(lang).T("language.name"))
+		if lang == current {
+			parts = append(parts, name)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf(`<a href="?lang=%s" hreflang="%s">%s</a>`, url.QueryEscape(lang), html.EscapeString(lang), name))
+	}
+	return strings.Join(parts, " ")
+}
+
+// routeDisplay renders the route a failing check's provider returned as a hop
+// diagram under its message; "" while the endpoint is up or the provider
+// returned no route.
+func routeDisplay(e collector.Endpoint) string {
+	if e.LastStatus == "up" || e.Route.IsEmpty() {
+		return ""
+	}
+	return `<pre class="route">` + html.EscapeString(e.Route.String()) + `</pre>`
+}
+
+// responseDisplay renders the latest Balancer-only response's HTTP status
+// and response time with its phases and its rate-limit and request ID headers
+// underneath, e.g. "HTTP 429 · 812ms" over "ttfb 790ms · read 22ms" and
+// "X-Request-Id: 7f3c…"; "—" when no response arrived.
+func responseDisplay(e collector.Endpoint) string {
+	if e.HTTPStatus == 0 {
+		return "—"
+	}
+	out := fmt.Sprintf("HTTP %d", e.HTTPStatus)
+	if e.ResponseTime > 0 {
+		out += fmt.Sprintf(" &middot; %dms", e.ResponseTime.Milliseconds())
+	}
+	if !e.ResponsePhases.IsZero() {
+		out += fmt.Sprintf(`<br><span class="addr">%s</span>`, html.EscapeString(e.ResponsePhases.String()))
+	}
+	names := make([]string, 0, len(e.HTTPHeaders))
+	for name := range e.HTTPHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out += fmt.Sprintf(`<br><span class="addr">%s: %s</span>`, html.EscapeString(name), html.EscapeString(e.HTTPHeaders[name]))
+	}
+	return out
+}
+
+// onChainQueryDisplay renders where an endpoint's latest on-chain query read
+// its result, e.g. "block 21500000 · eth-mainnet.example.com · 184ms" with the
+// on-chain amount or error underneath; "—" when none ran. The column is only
+// shown for solvers with on-chain queries (balancer_sor, hook-trigger rows).
+func onChainQueryDisplay(e collector.Endpoint) string {
+	if e.OnChainRPCHost == "" && e.OnChainQueryError == "" {
+		return "—"
+	}
+	var parts []string
+	if e.OnChainBlock > 0 {
+		parts = append(parts, fmt.Sprintf("block %d", e.OnChainBlock))
+	}
+	if e.OnChainRPCHost != "" {
+		parts = append(parts, html.EscapeString(e.OnChainRPCHost))
+	}
+	if e.OnChainLatency > 0 {
+		parts = append(parts, fmt.Sprintf("%dms", e.OnChainLatency.Milliseconds()))
+	}
+	out := strings.Join(parts, " &middot; ")
+	switch {
+	case e.OnChainQueryError != "":
+		out += fmt.Sprintf(`<br><span class="addr">error: %s</span>`, html.EscapeString(e.OnChainQueryError))
+	case e.OnChainPrice != "":
+		out += fmt.Sprintf(`<br><span class="addr">amount %s</span>`, html.EscapeString(e.OnChainPrice))
+	}
+	return out
+}
+
+// solverEndpoints returns a route solver type's rows, base then discovered.
+func solverEndpoints(solverType string) []collector.Endpoint {
+	var endpoints []collector.Endpoint
+	for _, e := range collector.GetEndpointsCopy() {
+		if e.RouteSolver == solverType {
+			endpoints = append(endpoints, e)
+		}
+	}
+	for _, e := range collector.GetDiscoveredEndpointsCopy() {
+		if e.RouteSolver == solverType {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
+
+// findRouteSolver looks up a configured route solver by type or display name.
+func findRouteSolver(name string) (config.RouteSolver, bool) {
+	for _, s := range config.RouteSolvers {
+		if strings.EqualFold(s.Type, name) || strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+	return config.RouteSolver{}, false
+}
+
+// statusClassFor maps a status to the dashboard's status-* CSS class.
+func statusClassFor(status string) string {
+	switch status {
+	case "up":
+		return "status-up"
+	case "down":
+		return "status-down"
+	case "disabled", collector.StatusNotApplicable:
+		return "status-disabled"
+	default:
+		return "status-unknown"
+	}
+}
+
+// formatUptime renders a summary's uptime as a percentage, or "—" when no
+// checks are in the window.
+func formatUptime(s collector.HistorySummary) string {
+	u := s.Uptime()
+	if u < 0 {
+		return "&mdash;"
+	}
+	return fmt.Sprintf("%.1f%% <span class='checks'>(%d)</span>", u*100, s.Checks)
+}
+
+// networkNames renders network IDs as a comma-separated list of names.
+func networkNames(networks []string) string {
+	names := make([]string, len(networks))
+	for i, n := range networks {
+		names[i] = getNetworkName(n)
+	}
+	return strings.Join(names, ", ")
+}
+
+// solverStyle is the rest of the <head> for /solver/{name}, after <title>.
+const solverStyle = `<style>
+	body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; margin: 20px; }
+	h1 { margin-bottom: 4px; }
+	.subhead { color: #555; margin-bottom: 16px; font-size: 0.95em; }
+	.subhead a { color: #1565c0; text-decoration: none; }
+	.summary { display: flex; gap: 24px; flex-wrap: wrap; margin-bottom: 16px; }
+	.summary div { font-size: 1.1em; }
+	.summary .label { display: block; font-size: 0.75em; color: #666; text-transform: uppercase; }
+	table { border-collapse: collapse; width: 100%; font-size: 0.93em; margin-bottom: 24px; }
+	th, td { padding: 6px 8px; text-align: left; border-bottom: 1px solid #eee; vertical-align: top; }
+	thead th { background: #f5f5f5; border-bottom: 2px solid #ddd; white-space: nowrap; }
+	.num { text-align: right; font-variant-numeric: tabular-nums; white-space: nowrap; }
+	.checks { color: #888; font-size: 0.85em; }
+	.route { font-family: ui-monospace, SFMono-Regular, monospace; font-size: 0.85em; color: #444; margin: 4px 0 0; white-space: pre-wrap; }
+	.addr { font-family: ui-monospace, SFMono-Regular, monospace; font-size: 0.85em; color: #666; }
+	.status-up { background-color: #90EE90; }
+	.status-down { background-color: #FFB6C1; }
+	.status-unknown { background-color: #FFA500; }
+	.status-disabled { background-color: #D3D3D3; }
+	.down-for { font-size: 0.85em; color: #8b0000; white-space: nowrap; }
+	.placeholder { padding: 16px; background: #fff8e1; border: 1px solid #ffe082; border-radius: 4px; color: #5d4037; }
+</style>
+</head><body>`
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/i18n"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+func TestOnChainQueryDisplay(t *testing.T) {
+	if got := onChainQueryDisplay(collector.Endpoint{}); got != "—" {
+		t.Fatalf("no query = %q", got)
+	}
+	got := onChainQueryDisplay(collector.Endpoint{
+		OnChainBlock: 21500000, OnChainRPCHost: "rpc.example.com", OnChainLatency: 184 * time.Millisecond, OnChainPrice: "123",
+	})
+	if !strings.HasPrefix(got, "block 21500000 &middot; rpc.example.com &middot; 184ms") || !strings.Contains(got, "amount 123") {
+		t.Fatalf("success = %q", got)
+	}
+	got = onChainQueryDisplay(collector.Endpoint{OnChainRPCHost: "rpc.example.com", OnChainQueryError: "eth_call failed: <revert>"})
+	if !strings.Contains(got, "error: eth_call failed: &lt;revert&gt;") {
+		t.Fatalf("failure = %q", got)
+	}
+}
+
+func TestResponseDisplay(t *testing.T) {
+	if got := responseDisplay(collector.Endpoint{}); got != "—" {
+		t.Fatalf("no response = %q", got)
+	}
+	got := responseDisplay(collector.Endpoint{HTTPStatus: 429, HTTPHeaders: map[string]string{"X-Request-Id": "<abc>", "Retry-After": "30"}})
+	want := `HTTP 429<br><span class="addr">Retry-After: 30</span><br><span class="addr">X-Request-Id: &lt;abc&gt;</span>`
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	got = responseDisplay(collector.Endpoint{HTTPStatus: 200, ResponseTime: 812 * time.Millisecond,
+		ResponsePhases: collector.LatencyPhases{TTFB: 790 * time.Millisecond, Read: 22 * time.Millisecond}})
+	if want := `HTTP 200 &middot; 812ms<br><span class="addr">ttfb 790ms · read 22ms</span>`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestRouteDisplayOnlyForFailures(t *testing.T) {
+	e := collector.Endpoint{LastStatus: "down", Route: collector.RouteGraph{Paths: []collector.RoutePath{{
+		Hops: []collector.RouteHop{{TokenIn: "0xa", TokenOut: "0xb", Venues: []collector.RouteVenue{{Exchange: "<x>"}}}},
+	}}}}
+	if got := routeDisplay(e); got != `<pre class="route">0xa → (&lt;x&gt;) → 0xb</pre>` {
+		t.Fatalf("down = %q", got)
+	}
+	e.LastStatus = "up"
+	if got := routeDisplay(e); got != "" {
+		t.Fatalf("up = %q", got)
+	}
+}
+
+func TestFailureDisplay(t *testing.T) {
+	zh := i18n.DefaultCatalog.For("zh")
+	if got := failureDisplay(zh, "no route found <x>"); got != `<strong>未返回路由</strong><br><span class="addr">no route found &lt;x&gt;</span>` {
+		t.Fatalf("classified = %q", got)
+	}
+	if got := failureDisplay(zh, "something odd"); got != "something odd" {
+		t.Fatalf("unclassified = %q", got)
+	}
+	if got := messageDisplay(zh, collector.Endpoint{LastStatus: "up", Message: "no route found"}); got != "no route found" {
+		t.Fatalf("up = %q", got)
+	}
+}
+
+func TestSolverHandlerLanguage(t *testing.T) {
+	collector.SetEndpoints([]collector.Endpoint{{Name: "k", BaseName: "k", RouteSolver: "kyberswap", Network: "1", LastStatus: "down"}})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	req := httptest.NewRequest(http.MethodGet, "/solver/kyberswap", nil)
+	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
+	rec := serve(req)
+	for _, want := range []string{`<html lang="zh">`, `<th>状态</th>`, `>故障<`, `<a href="?lang=en" hreflang="en">English</a>`} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("page lacks %s", want)
+		}
+	}
+
+	rec = serve(httptest.NewRequest(http.MethodGet, "/solver/kyberswap?lang=xx", nil))
+	if !strings.Contains(rec.Body.String(), `<th>Status</th>`) || !strings.Contains(rec.Body.String(), `>down<`) {
+		t.Errorf("unknown language did not fall back to English")
+	}
+}
+
+func TestSolverHandlerFeatures(t *testing.T) {
+	saved := monitor.GlobalRegistry
+	t.Cleanup(func() { monitor.GlobalRegistry = saved })
+	monitor.GlobalRegistry = providers.NewDefaultRegistry()
+
+	body := serve(httptest.NewRequest(http.MethodGet, "/solver/odos", nil)).Body.String()
+	for _, want := range []string{"market price, source whitelist", `<span class="label">Not supported</span>exact out, gas estimate, route`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page lacks %s", want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/collector"
+)
+
+var (
+	manualChecksMu sync.Mutex
+	// manualChecks holds when each endpoint was last checked by hand, by
+	// endpointRef, so Check Now and TriggerCheck can't hammer a provider
+	// through one row and a rename doesn't reset the interval.
+	manualChecks = map[string]time.Time{}
+)
+
+// reserveManualCheck records a manual check of the named endpoint at now and
+// returns 0, or returns how long until MANUAL_CHECK_INTERVAL allows the next
+// one without recording anything.
+func reserveManualCheck(name string, now time.Time) time.Duration {
+	key := manualCheckKey(name)
+	manualChecksMu.Lock()
+	defer manualChecksMu.Unlock()
+
+	if wait := manualCheckWaitLocked(key, now); wait > 0 {
+		return wait
+	}
+	manualChecks[key] = now
+	return 0
+}
+
+// manualCheckWait returns how long until e may be checked by hand again; 0
+// when it may be now.
+func manualCheckWait(e collector.Endpoint, now time.Time) time.Duration {
+	manualChecksMu.Lock()
+	defer manualChecksMu.Unlock()
+
+	return manualCheckWaitLocked(endpointRef(e), now)
+}
+
+// manualCheckKey returns the endpointRef of the row collector.ResolveEndpoint
+// resolved to name, or name for a row that is gone.
+func manualCheckKey(name string) string {
+	if e := collector.GetEndpointByName(name); e != nil {
+		return endpointRef(*e)
+	}
+	if e := collector.GetDiscoveredEndpointByName(name); e != nil {
+		return endpointRef(*e)
+	}
+	return name
+}
+
+func manualCheckWaitLocked(key string, now time.Time) time.Duration {
+	last, ok := manualChecks[key]
+	if !ok {
+		return 0
+	}
+	return max(config.GetManualCheckInterval()-now.Sub(last), 0)
+}
+
+// retryAfterSeconds rounds a wait up to whole seconds, for Retry-After and
+// the button countdown.
+func retryAfterSeconds(wait time.Duration) int {
+	return int(math.Ceil(wait.Seconds()))
+}
+
+// throttledMessage explains a refused manual check.
+func throttledMessage(name string, wait time.Duration) string {
+	return fmt.Sprintf("%s was checked manually less than %s ago; try again in %ds",
+		name, config.GetManualCheckInterval(), retryAfterSeconds(wait))
+}
+
+// checkButton renders an endpoint's Check Now button, counting down instead
+// while a manual check of it is throttled.
+func checkButton(e collector.Endpoint) string {
+	wait := ""
+	if s := retryAfterSeconds(manualCheckWait(e, clock.Now())); s > 0 {
+		wait = fmt.Sprintf(" data-wait='%d' disabled", s)
+	}
+	return fmt.Sprintf("<button class='check-button' data-endpoint='%s'%s onclick='checkEndpoint(this)'>Check Now</button>",
+		html.EscapeString(endpointRef(e)), wait)
+}
+
+// endpointRef is how the dashboard's buttons name an endpoint to the API:
+// its ID, which survives a rename, or its name for a row without one.
+func endpointRef(e collector.Endpoint) string {
+	if e.ID != "" {
+		return e.ID
+	}
+	return e.Name
+}
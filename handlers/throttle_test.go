@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestReserveManualCheck(t *testing.T) {
+	t.Setenv("MANUAL_CHECK_INTERVAL", "30s")
+	t.Cleanup(func() { manualChecks = map[string]time.Time{} })
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if wait := reserveManualCheck("a", now); wait != 0 {
+		t.Fatalf("first check waits %s", wait)
+	}
+	if wait := reserveManualCheck("a", now.Add(10*time.Second)); wait != 20*time.Second {
+		t.Fatalf("second check waits %s, want 20s", wait)
+	}
+	if wait := reserveManualCheck("b", now.Add(10*time.Second)); wait != 0 {
+		t.Fatalf("other endpoint waits %s", wait)
+	}
+	// A refused check doesn't restart the interval.
+	if wait := reserveManualCheck("a", now.Add(30*time.Second)); wait != 0 {
+		t.Fatalf("check after the interval waits %s", wait)
+	}
+
+	t.Setenv("MANUAL_CHECK_INTERVAL", "0")
+	if wait := reserveManualCheck("a", now.Add(31*time.Second)); wait != 0 {
+		t.Fatalf("disabled throttle waits %s", wait)
+	}
+}
+
+func TestManualCheckThrottleFollowsTheID(t *testing.T) {
+	t.Setenv("MANUAL_CHECK_INTERVAL", "1m")
+	c := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	clock.Use(c)
+	collector.SetEndpoints([]collector.Endpoint{{ID: "gho-usdc-odos", Name: "Odos-GHO/USDC", RouteSolver: "odos"}})
+	t.Cleanup(func() {
+		clock.Use(clock.Real)
+		collector.SetEndpoints(nil)
+		manualChecks = map[string]time.Time{}
+	})
+
+	// The button posts the ID; the handler reserves by the resolved name.
+	name, _ := collector.ResolveEndpoint("gho-usdc-odos")
+	reserveManualCheck(name, c.Now())
+	renamed := collector.Endpoint{ID: "gho-usdc-odos", Name: "Odos-GHO/USDC (Arbitrum)", RouteSolver: "odos"}
+	if got := checkButton(renamed); !strings.Contains(got, "data-endpoint='gho-usdc-odos' data-wait='60' disabled") {
+		t.Fatalf("button after a rename = %s", got)
+	}
+
+	collector.SetEndpoints([]collector.Endpoint{renamed})
+	c.Advance(15 * time.Second)
+	if wait := reserveManualCheck(renamed.Name, c.Now()); wait != 45*time.Second {
+		t.Fatalf("check after a rename waits %s, want 45s", wait)
+	}
+}
+
+func TestCheckEndpointHandlerThrottled(t *testing.T) {
+	t.Setenv("MANUAL_CHECK_INTERVAL", "1m")
+	c := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	clock.Use(c)
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-GHO/USDC", RouteSolver: "odos"}})
+	t.Cleanup(func() {
+		clock.Use(clock.Real)
+		collector.SetEndpoints(nil)
+		manualChecks = map[string]time.Time{}
+	})
+	reserveManualCheck("Odos-GHO/USDC", c.Now())
+	c.Advance(15 * time.Second)
+
+	rec := serve(httptest.NewRequest(http.MethodPost, "/check/Odos-GHO%2FUSDC", nil))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "45" {
+		t.Fatalf("status %d, Retry-After %q: %s", rec.Code, rec.Header().Get("Retry-After"), rec.Body)
+	}
+	if got := checkButton(collector.Endpoint{Name: "Odos-GHO/USDC"}); !strings.Contains(got, "data-wait='45' disabled") {
+		t.Fatalf("button = %s", got)
+	}
+
+	rec = serve(httptest.NewRequest(http.MethodPost, "/check/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing endpoint status %d", rec.Code)
+	}
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"fmt"
 	"time"
+
+	"go-monitoring/internal/clock"
 )
 
 // formatTimeAgo returns a human-readable relative time. Returns "Never" for the
@@ -12,7 +14,7 @@ func formatTimeAgo(t time.Time) string {
 		return "Never"
 	}
 
-	diff := time.Since(t)
+	diff := clock.Since(t)
 
 	if diff < time.Minute {
 		return "Just now"
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/i18n"
+	"go-monitoring/monitoring/collector"
+)
+
+// Widget health levels, worst last.
+const (
+	widgetUp       = "up"       // every counted row is up
+	widgetDegraded = "degraded" // some are
+	widgetDown     = "down"     // none is
+	widgetUnknown  = "unknown"  // no row counted yet (unchecked, not applicable, WIP)
+)
+
+// widgetProvider is one aggregator's rows on a network.
+type widgetProvider struct {
+	Solver string `json:"solver"` // RouteSolver.Type
+	Name   string `json:"name"`
+	Up     int    `json:"up"`
+	Total  int    `json:"total"` // rows up or down; other statuses aren't counted
+	Status string `json:"status"`
+}
+
+// widgetNetwork is a network's Balancer V3 aggregator health.
+type widgetNetwork struct {
+	Network   string           `json:"network"` // chain ID
+	Name      string           `json:"name"`
+	Up        int              `json:"up"`
+	Total     int              `json:"total"`
+	Status    string           `json:"status"`
+	Providers []widgetProvider `json:"providers"`
+}
+
+// WidgetHandler serves /widget, a read-only summary of each network's
+// Balancer V3 aggregator health from the BaseEndpoints rows, for embedding
+// in internal dashboards: a compact self-refreshing HTML page for an iframe,
+// or JSON with ?format=json (CORS-enabled for front-ends). ?network=
+// (name or chain ID) limits it to one network; the HTML is in the language
+// of ?lang= or Accept-Language, like /solver/{name}.
+func WidgetHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	networks := widgetSummary(config.GetEnabledRouteSolvers(), collector.GetEndpointsCopy())
+	if want := r.URL.Query().Get("network"); want != "" {
+		var kept []widgetNetwork
+		for _, n := range networks {
+			if strings.EqualFold(n.Network, want) || strings.EqualFold(n.Name, want) {
+				kept = append(kept, n)
+			}
+		}
+		networks = kept
+	}
+	now := clock.Now().UTC()
+	w.Header().Set("Cache-Control", "public, max-age=60")
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		json.NewEncoder(w).Encode(struct {
+			GeneratedAt time.Time       `json:"generatedAt"`
+			Networks    []widgetNetwork `json:"networks"`
+		}{now, networks})
+		return
+	}
+	catalog := i18n.Messages()
+	fmt.Fprint(w, widgetHTML(catalog.For(catalog.Negotiate(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))), networks, now))
+}
+
+// widgetSummary groups the endpoints of the solvers by network and solver,
+// networks by name and providers in solver order.
+func widgetSummary(solvers []config.RouteSolver, endpoints []collector.Endpoint) []widgetNetwork {
+	byNetwork := map[string]*widgetNetwork{}
+	var order []string
+	for _, e := range endpoints {
+		n, ok := byNetwork[e.Network]
+		if !ok {
+			n = &widgetNetwork{Network: e.Network, Name: getNetworkName(e.Network)}
+			byNetwork[e.Network] = n
+			order = append(order, e.Network)
+		}
+		up, counted := e.LastStatus == "up", e.LastStatus == "up" || collector.IsDownStatus(e.LastStatus)
+		for i, s := range solvers {
+			if s.Type != e.RouteSolver {
+				continue
+			}
+			for len(n.Providers) <= i {
+				n.Providers = append(n.Providers, widgetProvider{})
+			}
+			p := &n.Providers[i]
+			p.Solver, p.Name = s.Type, s.Name
+			if counted {
+				p.Total++
+				n.Total++
+			}
+			if up {
+				p.Up++
+				n.Up++
+			}
+		}
+	}
+
+	out := make([]widgetNetwork, 0, len(order))
+	for _, id := range order {
+		n := byNetwork[id]
+		var providers []widgetProvider
+		for _, p := range n.Providers {
+			if p.Solver != "" {
+				p.Status = widgetStatus(p.Up, p.Total)
+				providers = append(providers, p)
+			}
+		}
+		if len(providers) == 0 {
+			continue
+		}
+		n.Providers = providers
+		n.Status = widgetStatus(n.Up, n.Total)
+		out = append(out, *n)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func widgetStatus(up, total int) string {
+	switch {
+	case total == 0:
+		return widgetUnknown
+	case up == total:
+		return widgetUp
+	case up == 0:
+		return widgetDown
+	default:
+		return widgetDegraded
+	}
+}
+
+// widgetHTML renders the networks as one line each: name, rows up and a
+// chip per provider linking to its solver page. It refreshes itself every
+// five minutes and links out of the frame, to solver pages in the same
+// language.
+func widgetHTML(l i18n.Localizer, networks []widgetNetwork, now time.Time) string {
+	query := ""
+	if l.Lang != i18n.DefaultLang {
+		query = "?lang=" + url.QueryEscape(l.Lang)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<!DOCTYPE html><html lang="%s"><head><meta charset="utf-8"><meta http-equiv="refresh" content="300">
+<title>%s</title>
+`, html.EscapeString(l.Lang), html.EscapeString(l.T("widget.title")))
+	b.WriteString(`<base target="_blank">
+<style>
+	body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; margin: 8px; font-size: 13px; color: #222; }
+	.net { display: flex; align-items: center; gap: 6px; flex-wrap: wrap; margin-bottom: 6px; }
+	.name { width: 80px; font-weight: 600; }
+	.count { width: 48px; color: #666; font-variant-numeric: tabular-nums; }
+	.chip { padding: 1px 6px; border-radius: 8px; text-decoration: none; color: #222; }
+	.up { background: #90EE90; }
+	.degraded { background: #FFA500; }
+	.down { background: #FFB6C1; }
+	.unknown { background: #D3D3D3; }
+	.foot { color: #888; font-size: 11px; }
+</style></head><body>`)
+	if len(networks) == 0 {
+		fmt.Fprintf(&b, `<div class="foot">%s</div>`, html.EscapeString(l.T("widget.empty")))
+	}
+	for _, n := range networks {
+		fmt.Fprintf(&b, `<div class="net"><span class="name %s">%s</span><span class="count">%d/%d</span>`,
+			n.Status, html.EscapeString(n.Name), n.Up, n.Total)
+		for _, p := range n.Providers {
+			title := l.T("widget.provider_title", "name", p.Name, "up", strconv.Itoa(p.Up), "total", strconv.Itoa(p.Total))
+			fmt.Fprintf(&b, `<a class="chip %s" href="%s/solver/%s%s" title="%s">%s</a>`,
+				p.Status, html.EscapeString(config.GetPublicURL()), html.EscapeString(p.Solver), query,
+				html.EscapeString(title), html.EscapeString(p.Name))
+		}
+		b.WriteString(`</div>`)
+	}
+	fmt.Fprintf(&b, `<div class="foot">%s</div></body></html>`, html.EscapeString(l.T("widget.updated", "time", now.Format("2006-01-02 15:04 MST"))))
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/i18n"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestWidgetSummary(t *testing.T) {
+	solvers := []config.RouteSolver{{Type: "kyberswap", Name: "KyberSwap"}, {Type: "odos", Name: "Odos"}}
+	endpoints := []collector.Endpoint{
+		{RouteSolver: "odos", Network: "42161", LastStatus: "down"},
+		{RouteSolver: "kyberswap", Network: "42161", LastStatus: "up"},
+		{RouteSolver: "kyberswap", Network: "42161", LastStatus: "info"},
+		{RouteSolver: "kyberswap", Network: "8453", LastStatus: "up"},
+		{RouteSolver: "1inch", Network: "1", LastStatus: "up"}, // not enabled
+	}
+	got := widgetSummary(solvers, endpoints)
+	if len(got) != 2 || got[0].Name != "arbitrum" || got[1].Name != "base" {
+		t.Fatalf("networks = %+v", got)
+	}
+	arb := got[0]
+	if arb.Up != 1 || arb.Total != 2 || arb.Status != widgetDegraded || len(arb.Providers) != 2 {
+		t.Fatalf("arbitrum = %+v", arb)
+	}
+	if p := arb.Providers[0]; p.Solver != "kyberswap" || p.Total != 1 || p.Status != widgetUp {
+		t.Fatalf("kyberswap = %+v", p)
+	}
+	if p := arb.Providers[1]; p.Solver != "odos" || p.Status != widgetDown {
+		t.Fatalf("odos = %+v", p)
+	}
+}
+
+func TestWidgetHandlerJSON(t *testing.T) {
+	collector.SetEndpoints([]collector.Endpoint{
+		{Name: "a", RouteSolver: "kyberswap", Network: "42161", LastStatus: "up"},
+		{Name: "b", RouteSolver: "kyberswap", Network: "8453", LastStatus: "down"},
+	})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	rec := httptest.NewRecorder()
+	WidgetHandler(rec, httptest.NewRequest(http.MethodGet, "/widget?format=json&network=base", nil))
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Fatalf("headers = %v", rec.Header())
+	}
+	var got struct {
+		Networks []widgetNetwork `json:"networks"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || len(got.Networks) != 1 || got.Networks[0].Status != widgetDown {
+		t.Fatalf("body = %s (%v)", rec.Body, err)
+	}
+}
+
+func TestWidgetHTMLLanguage(t *testing.T) {
+	networks := []widgetNetwork{{Network: "1", Name: "ethereum", Up: 1, Total: 2, Status: widgetDegraded,
+		Providers: []widgetProvider{{Solver: "odos", Name: "Odos", Up: 1, Total: 2, Status: widgetDegraded}}}}
+	got := widgetHTML(i18n.DefaultCatalog.For("es"), networks, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	for _, want := range []string{`<html lang="es">`, `href="/solver/odos?lang=es" title="Odos: 1/2 activos"`, `Actualizado 2026-03-01 12:00 UTC`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("widget lacks %s:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/worker"
+)
+
+// maxWorkerReportBytes bounds a report body; a few hundred endpoint results
+// are well under this.
+const maxWorkerReportBytes = 4 << 20
+
+// WorkerResultsHandler accepts regional check results from workers at
+// /internal/v1/results. Requests must carry WORKER_TOKEN as a bearer token;
+// with no token configured, reports are refused.
+func WorkerResultsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hasBearerToken(r.Header, config.GetWorkerToken()) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var rep worker.Report
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWorkerReportBytes)).Decode(&rep); err != nil {
+		http.Error(w, "Invalid report: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if rep.Region == "" {
+		http.Error(w, "Invalid report: missing region", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"accepted":%d}`, worker.Accept(rep))
+}
+
+// hasBearerToken reports whether h carries token as its bearer token,
+// compared in constant time. An empty token matches nothing.
+func hasBearerToken(h http.Header, token string) bool {
+	got := h.Get("Authorization")
+	return token != "" && subtle.ConstantTimeCompare([]byte(got), []byte("Bearer "+token)) == 1
+}
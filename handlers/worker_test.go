@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/worker"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestWorkerReportRoundTrip(t *testing.T) {
+	t.Setenv("WORKER_TOKEN", "secret")
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-X", LastStatus: "up"}})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	srv := httptest.NewServer(http.HandlerFunc(WorkerResultsHandler))
+	defer srv.Close()
+
+	at := time.Now().UTC().Truncate(time.Second)
+	rep := worker.Report{Region: "sin", Results: []worker.Result{
+		{Name: "Odos-X", Status: "down", Message: "403 Forbidden", CheckedAt: at},
+		{Name: "Unknown-Y", Status: "up"},
+	}}
+	if err := worker.NewClient(srv.URL, "wrong", "sin").Send(rep); err == nil {
+		t.Fatal("report with the wrong token was accepted")
+	}
+	if err := worker.NewClient(srv.URL, "secret", "sin").Send(rep); err != nil {
+		t.Fatal(err)
+	}
+
+	got := collector.GetRegionResults("Odos-X")
+	if len(got) != 1 || got[0].Region != "sin" || got[0].Status != "down" || !got[0].CheckedAt.Equal(at) {
+		t.Fatalf("region results = %+v", got)
+	}
+	if len(collector.GetRegionResults("Unknown-Y")) != 0 {
+		t.Fatal("result for an unconfigured endpoint was recorded")
+	}
+	if s := regionsDisplay(collector.Endpoint{Name: "Odos-X", LastStatus: "up"}); !strings.Contains(s, "diverge") || !strings.Contains(s, "sin down") {
+		t.Fatalf("regionsDisplay = %q", s)
+	}
+}
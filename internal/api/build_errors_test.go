@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+type failingURLBuilder struct{ err error }
+
+func (b failingURLBuilder) BuildURL(*collector.Endpoint, check.RequestOptions) (string, error) {
+	return "", b.err
+}
+
+func TestBuildErrorStatus(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("error getting ignore list: %w", fmt.Errorf("%w: %s", check.ErrNetworkNotApplicable, "43114")), collector.StatusNotApplicable},
+		{fmt.Errorf("no Balancer V3 venue: %w", check.ErrBuildURLUnsupported), "unsupported"},
+		{errors.New("bad amount"), "error"},
+	}
+	for _, c := range cases {
+		e := &collector.Endpoint{Name: "Barter-Avalanche-X", Network: "43114", LastStatus: "unknown"}
+		before := notify.AlertsRaised()
+		NewAPIClient().CheckAPI(e, nil, failingURLBuilder{c.err}, nil, false, check.RequestOptions{})
+		if e.LastStatus != c.want {
+			t.Errorf("%v: status = %q, want %q", c.err, e.LastStatus, c.want)
+		}
+		if alerted := notify.AlertsRaised() != before; alerted != (c.want == "error") {
+			t.Errorf("%v: alerted = %v", c.err, alerted)
+		}
+	}
+}
@@ -5,54 +5,29 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/collector"
-	"go-monitoring/notifications"
+	"go-monitoring/internal/boosted"
+	"go-monitoring/internal/chaos"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/rules"
+	"go-monitoring/internal/shared"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
 )
 
-// RequestOptions contains configuration for API requests
-type RequestOptions struct {
-	IsBalancerSourceOnly bool
-	CustomHeaders        map[string]string
-}
-
-// APIResponse represents a generic API response
-type APIResponse struct {
-	StatusCode int
-	Body       []byte
-	Headers    http.Header
-}
-
-// ResponseHandler defines how to process API responses
-type ResponseHandler interface {
-	HandleResponse(response *APIResponse, endpoint *collector.Endpoint) error
-	HandleResponseForMarketPrice(response *APIResponse, endpoint *collector.Endpoint) error
-	GetIgnoreList(network string) (string, error)
-}
-
-// CustomResponseHandler allows for custom response handling without ignore list
-type CustomResponseHandler interface {
-	HandleResponse(response *APIResponse, endpoint *collector.Endpoint) error
-}
-
-// URLBuilder defines how to build URLs for different providers
-type URLBuilder interface {
-	BuildURL(endpoint *collector.Endpoint, options RequestOptions) (string, error)
-}
-
-// RequestBodyBuilder defines how to build JSON request bodies for POST requests
-type RequestBodyBuilder interface {
-	BuildRequestBody(endpoint *collector.Endpoint, options RequestOptions) ([]byte, error)
-}
-
 // APIClient handles HTTP requests and provides common functionality
 type APIClient struct {
 	client *http.Client
+
+	mu            sync.Mutex
+	solverClients map[string]*http.Client // see clientFor
 }
 
 // NewAPIClient creates a new API client with default configuration
@@ -72,12 +47,16 @@ func NewAPIClient() *APIClient {
 }
 
 // MakeRequest performs an HTTP request and handles common error scenarios
-func (c *APIClient) MakeRequest(endpoint *collector.Endpoint, baseURL string, options RequestOptions) (*APIResponse, error) {
+func (c *APIClient) MakeRequest(endpoint *collector.Endpoint, baseURL string, options check.RequestOptions) (*check.APIResponse, error) {
 	return c.MakeGETRequest(endpoint, baseURL, options)
 }
 
 // MakeGETRequest performs a GET HTTP request
-func (c *APIClient) MakeGETRequest(endpoint *collector.Endpoint, baseURL string, options RequestOptions) (*APIResponse, error) {
+func (c *APIClient) MakeGETRequest(endpoint *collector.Endpoint, baseURL string, options check.RequestOptions) (*check.APIResponse, error) {
+	// Space requests to this provider across every instance sharing the
+	// rate limiter (RATE_LIMIT_<SOLVER>).
+	shared.Wait("provider:"+endpoint.RouteSolver, config.GetRouteSolverRateLimit(endpoint.RouteSolver))
+
 	// Update endpoint timestamp
 	endpoint.LastChecked = time.Now()
 
@@ -88,35 +67,49 @@ func (c *APIClient) MakeGETRequest(endpoint *collector.Endpoint, baseURL string,
 		return nil, fmt.Errorf("error creating request: %v", err)
 	}
 
-	// Add custom headers
-	for key, value := range options.CustomHeaders {
-		req.Header.Add(key, value)
-	}
+	// Add custom headers and the standard Accept / Accept-Encoding
+	setRequestHeaders(req, options.CustomHeaders)
+	req, trace := traceRequest(req)
 
 	// Send request
-	resp, err := c.client.Do(req)
+	sent := time.Now()
+	resp, err := chaos.Active().Do(c.clientFor(endpoint.RouteSolver), req, endpoint.RouteSolver)
 	if err != nil {
 		c.handleError(endpoint, "down", fmt.Sprintf("Error sending request: %v", err))
 		return nil, fmt.Errorf("error sending request: %v", err)
 	}
 	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Read response body, decompressed
+	body, err := readBody(resp)
 	if err != nil {
 		c.handleError(endpoint, "down", fmt.Sprintf("Error reading response: %v", err))
 		return nil, fmt.Errorf("error reading response: %v", err)
 	}
+	done := time.Now()
 
-	return &APIResponse{
+	return &check.APIResponse{
 		StatusCode: resp.StatusCode,
 		Body:       body,
 		Headers:    resp.Header,
+		Duration:   done.Sub(sent),
+		Phases:     trace.phases(done),
 	}, nil
 }
 
 // MakePOSTRequest performs a POST HTTP request with JSON body
-func (c *APIClient) MakePOSTRequest(endpoint *collector.Endpoint, baseURL string, requestBody []byte, options RequestOptions) (*APIResponse, error) {
+func (c *APIClient) MakePOSTRequest(endpoint *collector.Endpoint, baseURL string, requestBody []byte, options check.RequestOptions) (*check.APIResponse, error) {
+	if options.Batch != nil {
+		if response, ok := options.Batch.Response(baseURL, requestBody); ok {
+			endpoint.LastChecked = time.Now()
+			return response, nil
+		}
+	}
+
+	// Space requests to this provider across every instance sharing the
+	// rate limiter (RATE_LIMIT_<SOLVER>).
+	shared.Wait("provider:"+endpoint.RouteSolver, config.GetRouteSolverRateLimit(endpoint.RouteSolver))
+
 	// Update endpoint timestamp
 	endpoint.LastChecked = time.Now()
 
@@ -127,125 +120,174 @@ func (c *APIClient) MakePOSTRequest(endpoint *collector.Endpoint, baseURL string
 		return nil, fmt.Errorf("error creating request: %v", err)
 	}
 
-	// Add custom headers
-	for key, value := range options.CustomHeaders {
-		req.Header.Add(key, value)
-	}
+	// Add custom headers and the standard Accept / Accept-Encoding
+	setRequestHeaders(req, options.CustomHeaders)
+	req, trace := traceRequest(req)
 
 	// Send request
-	resp, err := c.client.Do(req)
+	sent := time.Now()
+	resp, err := c.Send(req, endpoint.RouteSolver, options.FallbackURLs)
 	if err != nil {
 		c.handleError(endpoint, "down", fmt.Sprintf("Error sending request: %v", err))
 		return nil, fmt.Errorf("error sending request: %v", err)
 	}
 	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Read response body, decompressed
+	body, err := readBody(resp)
 	if err != nil {
 		c.handleError(endpoint, "down", fmt.Sprintf("Error reading response: %v", err))
 		return nil, fmt.Errorf("error reading response: %v", err)
 	}
+	done := time.Now()
 
-	return &APIResponse{
+	return &check.APIResponse{
 		StatusCode: resp.StatusCode,
 		Body:       body,
 		Headers:    resp.Header,
+		Duration:   done.Sub(sent),
+		Phases:     trace.phases(done),
 	}, nil
 }
 
-// CheckAPI performs a complete API check using the provided handler and URL builder
-func (c *APIClient) CheckAPI(endpoint *collector.Endpoint, handler ResponseHandler, urlBuilder URLBuilder, requestBodyBuilder RequestBodyBuilder, usePOST bool, options RequestOptions) {
+// CheckAPI performs a complete API check using the provided handler and URL
+// builder, writes the outcome to endpoint and returns it.
+func (c *APIClient) CheckAPI(endpoint *collector.Endpoint, handler check.ResponseHandler, urlBuilder check.URLBuilder, requestBodyBuilder check.RequestBodyBuilder, usePOST bool, options check.RequestOptions) check.Result {
+	var body []byte
+	if response := c.checkAPI(endpoint, handler, urlBuilder, requestBodyBuilder, usePOST, options); response != nil {
+		body = response.Body
+	}
+	return check.ResultOf(endpoint, body)
+}
+
+// checkAPI runs CheckAPI's check and returns the response it handled, nil
+// when none arrived.
+func (c *APIClient) checkAPI(endpoint *collector.Endpoint, handler check.ResponseHandler, urlBuilder check.URLBuilder, requestBodyBuilder check.RequestBodyBuilder, usePOST bool, options check.RequestOptions) *check.APIResponse {
 	// Update endpoint timestamp
 	endpoint.LastChecked = time.Now()
-
-	var response *APIResponse
+	endpoint.RouteSources = nil
+	endpoint.RouteTokens = nil
+	endpoint.Route = collector.RouteGraph{}
+	endpoint.HasPriceImpact = false
+	endpoint.Spender = ""
+	endpoint.HTTPStatus = 0
+	endpoint.HTTPHeaders = nil
+	endpoint.ResponseTime = 0
+	endpoint.ResponsePhases = collector.LatencyPhases{}
+
+	var response *check.APIResponse
 
 	if usePOST && requestBodyBuilder != nil {
 		// Build the request body for POST request
 		requestBody, err := requestBodyBuilder.BuildRequestBody(endpoint, options)
 		if err != nil {
-			c.handleError(endpoint, "error", fmt.Sprintf("Error building request body: %v", err))
-			return
+			c.handleBuildError(endpoint, "request body", err)
+			return nil
 		}
 
 		// Build the URL using the provider-specific builder
 		fullURL, err := urlBuilder.BuildURL(endpoint, options)
 		if err != nil {
-			if errors.Is(err, ErrBuildURLUnsupported) {
-				c.handleError(endpoint, "unsupported", err.Error())
-			} else {
-				c.handleError(endpoint, "error", fmt.Sprintf("Error building URL: %v", err))
-			}
-			return
+			c.handleBuildError(endpoint, "URL", err)
+			return nil
+		}
+		logs.Println("URL: ", fullURL)
+
+		if config.GetDryRunEnabled() {
+			c.finishDryRun(endpoint, "POST", fullURL, requestBody)
+			return nil
 		}
-		fmt.Println("URL: ", fullURL)
 
 		// Make the POST request
 		response, err = c.MakePOSTRequest(endpoint, fullURL, requestBody, options)
 		if err != nil {
 			// Error already handled in MakePOSTRequest
-			return
+			return nil
 		}
 	} else {
 		// Build the URL using the provider-specific builder
 		fullURL, err := urlBuilder.BuildURL(endpoint, options)
 		if err != nil {
-			if errors.Is(err, ErrBuildURLUnsupported) {
-				c.handleError(endpoint, "unsupported", err.Error())
-			} else {
-				c.handleError(endpoint, "error", fmt.Sprintf("Error building URL: %v", err))
-			}
-			return
+			c.handleBuildError(endpoint, "URL", err)
+			return nil
+		}
+		logs.Println("URL: ", fullURL)
+
+		if config.GetDryRunEnabled() {
+			c.finishDryRun(endpoint, "GET", fullURL, nil)
+			return nil
 		}
-		fmt.Println("URL: ", fullURL)
 
 		// Make the GET request
 		response, err = c.MakeGETRequest(endpoint, fullURL, options)
 		if err != nil {
 			// Error already handled in MakeGETRequest
-			return
+			return nil
 		}
 	}
+	endpoint.HTTPStatus = response.StatusCode
+	endpoint.HTTPHeaders = collector.DiagnosticHeaders(response.Headers)
+	endpoint.ResponseTime = response.Duration
+	endpoint.ResponsePhases = response.Phases
+
+	// An HTML page from the provider's CDN / WAF is not the API's answer
+	if msg, ok := edgeErrorMessage(response); ok {
+		c.handleError(endpoint, "down", msg)
+		return response
+	}
 
 	// Handle the response using the provided handler
 	if err := handler.HandleResponse(response, endpoint); err != nil {
 		c.handleError(endpoint, "down", fmt.Sprintf("Error handling response: %v", err))
-		return
+		return response
+	}
+
+	// Config-declared assertions on the parsed response
+	if failures := rules.Evaluate(endpoint); len(failures) > 0 {
+		c.handleError(endpoint, "down", "Rule failed: "+strings.Join(failures, "; "))
+		return response
+	}
+
+	// Boosted pools must be reached through their wrapped tokens
+	if msg := boosted.Check(endpoint); msg != "" {
+		c.handleError(endpoint, "down", "Boosted path: "+msg)
+		return response
 	}
 
 	// Success
 	endpoint.LastStatus = "up"
 	endpoint.Message = "Ok"
-	fmt.Printf("%s[SUCCESS]%s %s: API is %s%s%s\n", config.ColorGreen, config.ColorReset, endpoint.Name, config.ColorGreen, endpoint.LastStatus, config.ColorReset)
+	logs.Printf("%s[SUCCESS]%s %s: API is %s%s%s\n", config.ColorGreen, config.ColorReset, endpoint.Name, config.ColorGreen, endpoint.LastStatus, config.ColorReset)
+	return response
 }
 
 // CheckAPIForMarketPrice performs a complete API check for market price using the provided handler and URL builder
-func (c *APIClient) CheckAPIForMarketPrice(endpoint *collector.Endpoint, handler ResponseHandler, urlBuilder URLBuilder, requestBodyBuilder RequestBodyBuilder, usePOST bool, options RequestOptions) {
+func (c *APIClient) CheckAPIForMarketPrice(endpoint *collector.Endpoint, handler check.ResponseHandler, urlBuilder check.URLBuilder, requestBodyBuilder check.RequestBodyBuilder, usePOST bool, options check.RequestOptions) {
 	// Update endpoint timestamp
 	endpoint.LastChecked = time.Now()
 
-	var response *APIResponse
+	var response *check.APIResponse
 
 	if usePOST && requestBodyBuilder != nil {
 		// Build the request body for POST request
 		requestBody, err := requestBodyBuilder.BuildRequestBody(endpoint, options)
 		if err != nil {
-			c.handleError(endpoint, "error", fmt.Sprintf("Error building request body: %v", err))
+			c.handleBuildError(endpoint, "request body", err)
 			return
 		}
 
 		// Build the URL using the provider-specific builder
 		fullURL, err := urlBuilder.BuildURL(endpoint, options)
 		if err != nil {
-			if errors.Is(err, ErrBuildURLUnsupported) {
-				c.handleError(endpoint, "unsupported", err.Error())
-			} else {
-				c.handleError(endpoint, "error", fmt.Sprintf("Error building URL: %v", err))
-			}
+			c.handleBuildError(endpoint, "URL", err)
+			return
+		}
+		logs.Println("Market Price URL: ", fullURL)
+
+		if config.GetDryRunEnabled() {
+			c.finishDryRun(endpoint, "POST", fullURL, requestBody)
 			return
 		}
-		fmt.Println("Market Price URL: ", fullURL)
 
 		// Make the POST request
 		response, err = c.MakePOSTRequest(endpoint, fullURL, requestBody, options)
@@ -257,14 +299,15 @@ func (c *APIClient) CheckAPIForMarketPrice(endpoint *collector.Endpoint, handler
 		// Build the URL using the provider-specific builder
 		fullURL, err := urlBuilder.BuildURL(endpoint, options)
 		if err != nil {
-			if errors.Is(err, ErrBuildURLUnsupported) {
-				c.handleError(endpoint, "unsupported", err.Error())
-			} else {
-				c.handleError(endpoint, "error", fmt.Sprintf("Error building URL: %v", err))
-			}
+			c.handleBuildError(endpoint, "URL", err)
+			return
+		}
+		logs.Println("Market Price URL: ", fullURL)
+
+		if config.GetDryRunEnabled() {
+			c.finishDryRun(endpoint, "GET", fullURL, nil)
 			return
 		}
-		fmt.Println("Market Price URL: ", fullURL)
 
 		// Make the GET request
 		response, err = c.MakeGETRequest(endpoint, fullURL, options)
@@ -274,6 +317,12 @@ func (c *APIClient) CheckAPIForMarketPrice(endpoint *collector.Endpoint, handler
 		}
 	}
 
+	// An HTML page from the provider's CDN / WAF is not the API's answer
+	if msg, ok := edgeErrorMessage(response); ok {
+		c.handleError(endpoint, "down", msg)
+		return
+	}
+
 	// Handle the response using the provided handler for market price
 	if err := handler.HandleResponseForMarketPrice(response, endpoint); err != nil {
 		c.handleError(endpoint, "down", fmt.Sprintf("Error handling market price response: %v", err))
@@ -281,19 +330,80 @@ func (c *APIClient) CheckAPIForMarketPrice(endpoint *collector.Endpoint, handler
 	}
 
 	// Success - don't update LastStatus or Message for market price calls
-	fmt.Printf("%s[MARKET PRICE]%s %s: Market price retrieved successfully\n", config.ColorGreen, config.ColorReset, endpoint.Name)
+	logs.Printf("%s[MARKET PRICE]%s %s: Market price retrieved successfully\n", config.ColorGreen, config.ColorReset, endpoint.Name)
+}
+
+// Fetch builds and sends endpoint's request and returns the response without
+// handling it: unlike CheckAPI it leaves the endpoint untouched and sends no
+// alert, so the caller decides what a failure means. A CDN / WAF page is an
+// error.
+func (c *APIClient) Fetch(endpoint collector.Endpoint, urlBuilder check.URLBuilder, requestBodyBuilder check.RequestBodyBuilder, usePOST bool, options check.RequestOptions) (*check.APIResponse, error) {
+	fullURL, err := urlBuilder.BuildURL(&endpoint, options)
+	if err != nil {
+		return nil, fmt.Errorf("error building URL: %w", err)
+	}
+	method, body := "GET", []byte(nil)
+	if usePOST && requestBodyBuilder != nil {
+		method = "POST"
+		if body, err = requestBodyBuilder.BuildRequestBody(&endpoint, options); err != nil {
+			return nil, fmt.Errorf("error building request body: %w", err)
+		}
+	}
+
+	shared.Wait("provider:"+endpoint.RouteSolver, config.GetRouteSolverRateLimit(endpoint.RouteSolver))
+	req, err := http.NewRequest(method, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	setRequestHeaders(req, options.CustomHeaders)
+	req, trace := traceRequest(req)
+
+	sent := time.Now()
+	resp, err := chaos.Active().Do(c.clientFor(endpoint.RouteSolver), req, endpoint.RouteSolver)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := readBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+	done := time.Now()
+	response := &check.APIResponse{StatusCode: resp.StatusCode, Body: data, Headers: resp.Header, Duration: done.Sub(sent), Phases: trace.phases(done)}
+	if msg, ok := edgeErrorMessage(response); ok {
+		return nil, errors.New(msg)
+	}
+	return response, nil
+}
+
+// handleBuildError records a failure to build the request: a network the
+// solver doesn't serve is not-applicable and a mode it can't satisfy is
+// unsupported (neither alerts); anything else is an error.
+func (c *APIClient) handleBuildError(endpoint *collector.Endpoint, what string, err error) {
+	switch {
+	case errors.Is(err, check.ErrNetworkNotApplicable):
+		c.handleError(endpoint, collector.StatusNotApplicable, err.Error())
+	case errors.Is(err, check.ErrBuildURLUnsupported):
+		c.handleError(endpoint, "unsupported", err.Error())
+	default:
+		c.handleError(endpoint, "error", fmt.Sprintf("Error building %s: %v", what, err))
+	}
 }
 
 // handleError updates endpoint status and sends notifications for errors
 func (c *APIClient) handleError(endpoint *collector.Endpoint, status, message string) {
 	endpoint.LastStatus = status
 	endpoint.Message = message
-	if status == "unsupported" {
-		fmt.Printf("%s[UNSUPPORTED]%s %s: %s\n", config.ColorCyan, config.ColorReset, endpoint.Name, message)
+	switch status {
+	case "unsupported":
+		logs.Printf("%s[UNSUPPORTED]%s %s: %s\n", config.ColorCyan, config.ColorReset, endpoint.Name, message)
+		return
+	case collector.StatusNotApplicable:
+		logs.Printf("%s[NOT APPLICABLE]%s %s: %s\n", config.ColorCyan, config.ColorReset, endpoint.Name, message)
 		return
 	}
-	fmt.Printf("%s[ERROR]%s %s: %s\n", config.ColorRed, config.ColorReset, endpoint.Name, message)
-	notifications.SendEmail(fmt.Sprintf("[%s] %s", endpoint.Name, message))
+	logs.Printf("%s[ERROR]%s %s: %s\n", config.ColorRed, config.ColorReset, endpoint.Name, message)
+	notify.SendEndpointAlert(endpoint, message, "")
 }
 
 // ValidateAPIKey checks if a required API key is present
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+)
+
+// dialContext returns the transport DialContext for a route solver's dial
+// options: it dials opts.Network (forcing an IP family), resolves through
+// opts.Resolver and connects to the overridden address of a host in
+// opts.Hosts without resolving it. TLS still sends SNI for the request's
+// host.
+func dialContext(opts config.DialOptions) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	d := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if opts.Resolver != "" {
+		server := opts.Resolver
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		d.Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return (&net.Dialer{Timeout: 5 * time.Second}).DialContext(ctx, network, server)
+			},
+		}
+	}
+	return func(ctx context.Context, _, addr string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if ip, ok := opts.Hosts[strings.ToLower(host)]; ok {
+				addr = net.JoinHostPort(ip, port)
+			}
+		}
+		return d.DialContext(ctx, opts.Network, addr)
+	}
+}
+
+// clientFor returns the HTTP client for requests to a route solver: the
+// shared one unless the solver has dial options (IP_FAMILY_<SOLVER>,
+// DNS_RESOLVER_<SOLVER>, HOST_OVERRIDES_<SOLVER>), in which case a client
+// with its own transport, built on first use.
+func (c *APIClient) clientFor(routeSolver string) *http.Client {
+	opts := config.GetDialOptions(routeSolver)
+	if opts.IsDefault() {
+		return c.client
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.solverClients[routeSolver]; ok {
+		return client
+	}
+	tr := c.client.Transport.(*http.Transport).Clone()
+	tr.DialContext = dialContext(opts)
+	client := &http.Client{Transport: tr, Timeout: c.client.Timeout}
+	if c.solverClients == nil {
+		c.solverClients = map[string]*http.Client{}
+	}
+	c.solverClients[routeSolver] = client
+	return client
+}
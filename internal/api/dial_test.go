@@ -0,0 +1,45 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientForHostOverride(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer srv.Close()
+	_, port, _ := strings.Cut(strings.TrimPrefix(srv.URL, "http://"), ":")
+	url := "http://quotes.example.invalid:" + port + "/"
+
+	c := NewAPIClient()
+	if c.clientFor("kyberswap") != c.client {
+		t.Fatal("no dial options built a separate client")
+	}
+
+	t.Setenv("HOST_OVERRIDES_KYBERSWAP", "Quotes.Example.Invalid=127.0.0.1, bad-pair")
+	client := c.clientFor("kyberswap")
+	if client == c.client || c.clientFor("kyberswap") != client {
+		t.Fatal("dial options client not built once")
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// The request still names the overridden host.
+	if string(body) != "quotes.example.invalid:"+port {
+		t.Fatalf("host = %q", body)
+	}
+
+	t.Setenv("IP_FAMILY_ODOS", "ipv6")
+	t.Setenv("HOST_OVERRIDES_ODOS", "quotes.example.invalid=127.0.0.1")
+	if _, err := c.clientFor("odos").Get(url); err == nil {
+		t.Fatal("IPv6-only client reached an IPv4 address")
+	}
+}
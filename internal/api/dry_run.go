@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+)
+
+// finishDryRun validates a built request and records the outcome on the
+// endpoint instead of sending it. Used when DRY_RUN is enabled.
+func (c *APIClient) finishDryRun(endpoint *collector.Endpoint, method, fullURL string, body []byte) {
+	if err := validateDryRunRequest(fullURL, body); err != nil {
+		c.handleError(endpoint, "error", fmt.Sprintf("Dry run: invalid %s request: %v", method, err))
+		return
+	}
+
+	if body != nil {
+		logs.Printf("%s[DRY RUN]%s %s: %s %s\n  body: %s\n", config.ColorYellow, config.ColorReset, endpoint.Name, method, fullURL, body)
+	} else {
+		logs.Printf("%s[DRY RUN]%s %s: %s %s\n", config.ColorYellow, config.ColorReset, endpoint.Name, method, fullURL)
+	}
+	endpoint.LastStatus = "info"
+	endpoint.Message = fmt.Sprintf("Dry run: %s request built, not sent", method)
+}
+
+// validateDryRunRequest checks that the URL is absolute http(s) and that a
+// POST body, when present, is well-formed JSON.
+func validateDryRunRequest(fullURL string, body []byte) error {
+	u, err := url.Parse(fullURL)
+	if err != nil {
+		return fmt.Errorf("URL does not parse: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("URL scheme %q is not http(s)", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("URL has no host")
+	}
+	if body != nil && !json.Valid(body) {
+		return fmt.Errorf("request body is not valid JSON")
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package api
+
+import "testing"
+
+func TestValidateDryRunRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		body    []byte
+		wantErr bool
+	}{
+		{"get ok", "https://api.0x.org/swap/permit2/quote?chainId=1", nil, false},
+		{"post ok", "https://api.odos.xyz/sor/quote/v2", []byte(`{"chainId":1}`), false},
+		{"relative url", "/swap/quote", nil, true},
+		{"bad scheme", "ftp://example.com/x", nil, true},
+		{"bad body", "https://example.com", []byte(`{"chainId":`), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDryRunRequest(tt.url, tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"go-monitoring/monitoring/check"
+)
+
+// edgeSnippetLen bounds the text of an error page kept in the message.
+const edgeSnippetLen = 160
+
+// edgeErrorMessage recognises a non-JSON response, typically an HTML page
+// from a CDN or WAF in front of the provider (Cloudflare challenge, 502 page)
+// rather than from its API. Handlers would fail on it with "invalid character
+// '<'"; instead the check is reported as a provider edge error with the
+// status code and a short text snippet, and the page itself is not alerted.
+func edgeErrorMessage(response *check.APIResponse) (string, bool) {
+	body := strings.TrimSpace(string(response.Body))
+	if body != "" && strings.ContainsRune("{[\"", rune(body[0])) {
+		return "", false
+	}
+	contentType := response.Headers.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(contentType)
+	}
+	isHTML := strings.Contains(mediaType, "html") || strings.HasPrefix(body, "<")
+	if !isHTML && (mediaType == "" || strings.Contains(mediaType, "json")) {
+		return "", false
+	}
+	if mediaType == "" {
+		mediaType = "text/html"
+	}
+	msg := fmt.Sprintf("Provider edge error: HTTP %d %s response", response.StatusCode, mediaType)
+	if snippet := pageSnippet(body); snippet != "" {
+		msg += ": " + snippet
+	}
+	return msg, true
+}
+
+// pageSnippet returns an error page's <title>, or else its text with tags,
+// scripts and styles removed, whitespace collapsed and cut to
+// edgeSnippetLen.
+func pageSnippet(page string) string {
+	lower := strings.ToLower(page)
+	text := ""
+	if i := strings.Index(lower, "<title"); i >= 0 {
+		if j := strings.Index(lower[i:], ">"); j >= 0 {
+			start := i + j + 1
+			if end := strings.Index(lower[start:], "</title>"); end >= 0 {
+				text = page[start : start+end]
+			}
+		}
+	}
+	if strings.TrimSpace(text) == "" {
+		text = stripTags(page)
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > edgeSnippetLen {
+		text = string(r[:edgeSnippetLen]) + "…"
+	}
+	return text
+}
+
+// stripTags drops markup and the contents of script and style elements.
+func stripTags(page string) string {
+	var b strings.Builder
+	lower := strings.ToLower(page)
+	for i := 0; i < len(page); {
+		if page[i] != '<' {
+			b.WriteByte(page[i])
+			i++
+			continue
+		}
+		for _, skip := range []string{"script", "style"} {
+			if strings.HasPrefix(lower[i+1:], skip) {
+				if end := strings.Index(lower[i:], "</"+skip); end >= 0 {
+					i += end
+				}
+				break
+			}
+		}
+		end := strings.IndexByte(page[i:], '>')
+		if end < 0 {
+			break
+		}
+		i += end + 1
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/check"
+)
+
+func TestEdgeErrorMessage(t *testing.T) {
+	cloudflare := `<!DOCTYPE html><html><head><title>Attention Required! | Cloudflare</title>
+<style>body{color:red}</style></head><body><h1>Sorry, you have been blocked</h1></body></html>`
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		want        string // "" for not an edge error
+	}{
+		{"json", 200, "application/json", `{"price":"1"}`, ""},
+		{"json without content type", 200, "", `[1,2]`, ""},
+		{"json error under text/plain", 500, "text/plain", ` {"error":"x"}`, ""},
+		{"cloudflare", 403, "text/html; charset=UTF-8", cloudflare, "Provider edge error: HTTP 403 text/html response: Attention Required! | Cloudflare"},
+		{"html without content type", 502, "", "<html><body>\n  502 Bad   Gateway\n</body></html>", "Provider edge error: HTTP 502 text/html response: 502 Bad Gateway"},
+		{"plain text", 503, "text/plain", "upstream connect error", "Provider edge error: HTTP 503 text/plain response: upstream connect error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &check.APIResponse{StatusCode: tt.status, Body: []byte(tt.body), Headers: http.Header{}}
+			if tt.contentType != "" {
+				resp.Headers.Set("Content-Type", tt.contentType)
+			}
+			got, ok := edgeErrorMessage(resp)
+			if ok != (tt.want != "") || got != tt.want {
+				t.Fatalf("edgeErrorMessage = %q, %v; want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageSnippetStripsAndTruncates(t *testing.T) {
+	page := "<html><script>var x = '<b>';</script><p>" + strings.Repeat("blocked ", 40) + "</p></html>"
+	got := pageSnippet(page)
+	if strings.Contains(got, "var x") || !strings.HasPrefix(got, "blocked blocked") || !strings.HasSuffix(got, "…") {
+		t.Fatalf("snippet = %q", got)
+	}
+	if n := len([]rune(got)); n != edgeSnippetLen+1 {
+		t.Fatalf("snippet has %d runes", n)
+	}
+}
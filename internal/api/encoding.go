@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go-monitoring/config"
+)
+
+// acceptEncoding is what the client advertises and decodes itself. Setting
+// Accept-Encoding turns off net/http's transparent gzip, so every encoding
+// listed here must be handled by decodeBody.
+const acceptEncoding = "gzip, deflate"
+
+// setRequestHeaders applies the provider's custom headers and the standard
+// Accept / Accept-Encoding / User-Agent. A custom Accept or User-Agent wins;
+// Accept-Encoding is always ours, since the client can only decode what it
+// advertises.
+func setRequestHeaders(req *http.Request, custom map[string]string) {
+	for key, value := range custom {
+		req.Header.Add(key, value)
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", config.GetUserAgent())
+	}
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+}
+
+// readBody reads a response body, decompressing it according to
+// Content-Encoding. A gzip body served without the header (some CDNs strip
+// it) is recognised by its magic bytes.
+func readBody(resp *http.Response) ([]byte, error) {
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" && bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+		encoding = "gzip"
+	}
+	return decodeBody(raw, encoding)
+}
+
+// decodeBody decompresses raw per a Content-Encoding value.
+func decodeBody(raw []byte, encoding string) ([]byte, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+	switch encoding {
+	case "", "identity":
+		return raw, nil
+	case "gzip", "x-gzip":
+		r, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("gzip body: %v", err)
+		}
+		defer r.Close()
+		return readDecoded(r, "gzip")
+	case "deflate":
+		// "deflate" is meant to be zlib-wrapped, but some servers send a
+		// raw DEFLATE stream.
+		if r, err := zlib.NewReader(bytes.NewReader(raw)); err == nil {
+			defer r.Close()
+			return readDecoded(r, "deflate")
+		}
+		r := flate.NewReader(bytes.NewReader(raw))
+		defer r.Close()
+		return readDecoded(r, "deflate")
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", encoding)
+	}
+}
+
+func readDecoded(r io.Reader, encoding string) ([]byte, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s body: %v", encoding, err)
+	}
+	return body, nil
+}
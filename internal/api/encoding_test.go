@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+func compress(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	case "flate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+	w.Write(body)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestMakeGETRequestDecompresses(t *testing.T) {
+	const want = `{"price":"1"}`
+	tests := []struct {
+		name, header, codec string
+	}{
+		{"gzip", "gzip", "gzip"},
+		{"zlib deflate", "deflate", "zlib"},
+		{"raw deflate", "deflate", "flate"},
+		{"gzip without header", "", "gzip"},
+		{"identity", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Accept-Encoding"); got != acceptEncoding {
+					t.Errorf("Accept-Encoding = %q", got)
+				}
+				if got := r.Header.Get("Accept"); got != "application/json" {
+					t.Errorf("Accept = %q", got)
+				}
+				if got := r.Header.Get("User-Agent"); !strings.HasPrefix(got, "go-monitoring/") {
+					t.Errorf("User-Agent = %q", got)
+				}
+				body := []byte(want)
+				if tt.codec != "" {
+					body = compress(t, tt.codec, body)
+				}
+				if tt.header != "" {
+					w.Header().Set("Content-Encoding", tt.header)
+				}
+				w.Write(body)
+			}))
+			defer srv.Close()
+
+			e := &collector.Endpoint{Name: "x"}
+			resp, err := NewAPIClient().MakeGETRequest(e, srv.URL, check.RequestOptions{
+				CustomHeaders: map[string]string{"Accept-Encoding": "br"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(resp.Body) != want {
+				t.Fatalf("body = %q", resp.Body)
+			}
+		})
+	}
+}
+
+func TestDecodeBodyRejectsUnknownEncoding(t *testing.T) {
+	if _, err := decodeBody([]byte("x"), "br"); err == nil {
+		t.Fatal("want an error for br")
+	}
+}
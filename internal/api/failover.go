@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/chaos"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/metrics"
+)
+
+var servedBy = metrics.NewGauge("provider_api_environment",
+	"1 for the URL that served a provider's last response, 0 for its other configured URLs (providers with fallback URLs only).", "solver", "url")
+
+// Send sends req to a route solver's provider. When the provider can't be
+// reached or answers 429 or 5xx, req is resent to each of fallbacks in turn;
+// the last attempt's response or error is returned. With fallbacks, the
+// provider_api_environment gauge records which URL answered.
+func (c *APIClient) Send(req *http.Request, solver string, fallbacks []string) (*http.Response, error) {
+	urls := append([]string{req.URL.String()}, fallbacks...)
+	for i := 0; ; i++ {
+		resp, err := chaos.Active().Do(c.clientFor(solver), req, solver)
+		if i == len(urls)-1 || (err == nil && !failsOver(resp.StatusCode)) {
+			if err == nil && len(fallbacks) > 0 {
+				for j, u := range urls {
+					servedBy.Set(boolGauge(j == i), solver, u)
+				}
+			}
+			return resp, err
+		}
+		reason := fmt.Sprint(err)
+		if err == nil {
+			reason = resp.Status
+			resp.Body.Close()
+		}
+		logs.Printf("%s[FAILOVER]%s %s: %s failed (%s), trying %s\n", config.ColorYellow, config.ColorReset, solver, urls[i], reason, urls[i+1])
+		if req, err = retarget(req, urls[i+1]); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// failsOver reports whether a response status sends the request to the next
+// URL: rate limited or a server error.
+func failsOver(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retarget returns a copy of req sent to rawURL, with its body rewound.
+func retarget(req *http.Request, rawURL string) (*http.Request, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fallback URL %q: %v", rawURL, err)
+	}
+	next := req.Clone(req.Context())
+	next.URL, next.Host = u, ""
+	if req.GetBody != nil {
+		if next.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return next, nil
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/internal/metrics"
+)
+
+func TestSendFailsOver(t *testing.T) {
+	var bodies []string
+	handler := func(status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			w.WriteHeader(status)
+		}
+	}
+	primary := httptest.NewServer(handler(http.StatusServiceUnavailable))
+	defer primary.Close()
+	canary := httptest.NewServer(handler(http.StatusTooManyRequests))
+	defer canary.Close()
+	backup := httptest.NewServer(handler(http.StatusOK))
+	defer backup.Close()
+
+	req, _ := http.NewRequest("POST", primary.URL, bytes.NewBufferString(`{"query":"{}"}`))
+	resp, err := NewAPIClient().Send(req, "failover_test", []string{canary.URL, backup.URL})
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Send = %v, %v", resp, err)
+	}
+	resp.Body.Close()
+	if len(bodies) != 3 || bodies[2] != `{"query":"{}"}` {
+		t.Errorf("bodies sent = %q", bodies)
+	}
+	var out strings.Builder
+	metrics.WriteText(&out)
+	for url, want := range map[string]string{primary.URL: "0", canary.URL: "0", backup.URL: "1"} {
+		if line := `provider_api_environment{solver="failover_test",url="` + url + `"} ` + want; !strings.Contains(out.String(), line) {
+			t.Errorf("metrics missing %s", line)
+		}
+	}
+
+	// The last URL's answer is returned, whatever it is.
+	req, _ = http.NewRequest("POST", primary.URL, bytes.NewBufferString("{}"))
+	if resp, err := NewAPIClient().Send(req, "failover_test", []string{canary.URL}); err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Send = %v, %v", resp, err)
+	}
+
+	// Without fallbacks the primary's answer stands.
+	req, _ = http.NewRequest("POST", primary.URL, bytes.NewBufferString("{}"))
+	if resp, err := NewAPIClient().Send(req, "failover_test", nil); err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Send = %v, %v", resp, err)
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+type fixedURLBuilder struct{ url string }
+
+func (b fixedURLBuilder) BuildURL(*collector.Endpoint, check.RequestOptions) (string, error) {
+	return b.url, nil
+}
+
+// statusHandler fails every response that isn't a 200.
+type statusHandler struct{}
+
+func (statusHandler) HandleResponse(r *check.APIResponse, e *collector.Endpoint) error {
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", r.StatusCode, r.Body)
+	}
+	e.ReturnAmount = "1000"
+	return nil
+}
+
+func (statusHandler) HandleResponseForMarketPrice(*check.APIResponse, *collector.Endpoint) error {
+	return nil
+}
+
+func (statusHandler) GetIgnoreList(string) (string, error) { return "", nil }
+
+func TestCheckAPIReturnsResult(t *testing.T) {
+	status := http.StatusTooManyRequests
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error":"too many requests"}`))
+	}))
+	defer srv.Close()
+
+	e := &collector.Endpoint{Name: "Test-X", RouteSolver: "test"}
+	res := NewAPIClient().CheckAPI(e, statusHandler{}, fixedURLBuilder{srv.URL}, nil, false, check.RequestOptions{})
+	if res.Status != "down" || res.Status != e.LastStatus || res.Message != e.Message || !res.Failed() {
+		t.Fatalf("result = %+v, endpoint status %q", res, e.LastStatus)
+	}
+	if res.ErrorClass != "rate_limited" || res.HTTPStatus != status || res.RequestID != "req-1" {
+		t.Errorf("class %q, status %d, request ID %q", res.ErrorClass, res.HTTPStatus, res.RequestID)
+	}
+	if string(res.Body) != `{"error":"too many requests"}` || res.Latency <= 0 || res.CheckedAt.IsZero() {
+		t.Errorf("body %q, latency %v, checked %v", res.Body, res.Latency, res.CheckedAt)
+	}
+
+	status = http.StatusOK
+	res = NewAPIClient().CheckAPI(e, statusHandler{}, fixedURLBuilder{srv.URL}, nil, false, check.RequestOptions{})
+	if res.Status != "up" || res.ErrorClass != "" || res.ReturnAmount != "1000" {
+		t.Errorf("up result = %+v", res)
+	}
+}
+
+func TestCheckAPIResultWithoutResponse(t *testing.T) {
+	e := &collector.Endpoint{Name: "Test-X"}
+	res := NewAPIClient().CheckAPI(e, nil, failingURLBuilder{fmt.Errorf("bad amount")}, nil, false, check.RequestOptions{})
+	if res.Status != "error" || res.Body != nil || res.HTTPStatus != 0 {
+		t.Fatalf("result = %+v", res)
+	}
+}
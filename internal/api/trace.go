@@ -0,0 +1,67 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+// phaseTrace records when each phase of a request started and ended. The
+// transport calls the hooks from its own goroutines, hence the lock.
+type phaseTrace struct {
+	mu                  sync.Mutex
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	wroteRequest        time.Time
+	firstByte           time.Time
+}
+
+// traceRequest returns req with a trace recording its latency phases.
+func traceRequest(req *http.Request) (*http.Request, *phaseTrace) {
+	pt := &phaseTrace{}
+	mark := func(at *time.Time) {
+		pt.mu.Lock()
+		defer pt.mu.Unlock()
+		// Keep the first: a dual-stack dial reports a connect per address.
+		if at.IsZero() {
+			*at = time.Now()
+		}
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { mark(&pt.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { mark(&pt.dnsDone) },
+		ConnectStart:         func(string, string) { mark(&pt.connStart) },
+		ConnectDone:          func(string, string, error) { mark(&pt.connDone) },
+		TLSHandshakeStart:    func() { mark(&pt.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { mark(&pt.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { mark(&pt.wroteRequest) },
+		GotFirstResponseByte: func() { mark(&pt.firstByte) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), pt
+}
+
+// phases returns the measured phases of a request whose body was read by
+// done. A phase that didn't happen (DNS and dialing on a reused connection,
+// TLS over plain HTTP) is zero.
+func (pt *phaseTrace) phases(done time.Time) collector.LatencyPhases {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	span := func(start, end time.Time) time.Duration {
+		if start.IsZero() || end.Before(start) {
+			return 0
+		}
+		return end.Sub(start)
+	}
+	return collector.LatencyPhases{
+		DNS:     span(pt.dnsStart, pt.dnsDone),
+		Connect: span(pt.connStart, pt.connDone),
+		TLS:     span(pt.tlsStart, pt.tlsDone),
+		TTFB:    span(pt.wroteRequest, pt.firstByte),
+		Read:    span(pt.firstByte, done),
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTraceRequestPhases(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+	c := NewAPIClient()
+
+	get := func() (*http.Response, *phaseTrace) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, trace := traceRequest(req)
+		resp, err := c.client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp, trace
+	}
+
+	_, trace := get()
+	first := trace.phases(time.Now())
+	// The server is an IP address, so there is no lookup.
+	if first.DNS != 0 || first.Connect <= 0 || first.TLS <= 0 || first.TTFB < 50*time.Millisecond || first.Read < 0 {
+		t.Fatalf("first request phases = %+v", first)
+	}
+
+	_, trace = get()
+	reused := trace.phases(time.Now())
+	if reused.Connect != 0 || reused.TLS != 0 || reused.TTFB < 50*time.Millisecond {
+		t.Fatalf("reused connection phases = %+v", reused)
+	}
+}
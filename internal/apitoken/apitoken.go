@@ -0,0 +1,140 @@
+// Package apitoken issues and checks API tokens for automation (cron jobs,
+// CI): bearer tokens with scopes, stored only as hashes (store.APIToken) and
+// managed with `go-monitoring token`. ADMIN_TOKEN keeps working and grants
+// every scope.
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/internal/store"
+)
+
+// Scope is what a token may do. Each scope includes the ones before it.
+type Scope string
+
+const (
+	// Read allows the JSON and RPC read APIs.
+	Read Scope = "read"
+	// TriggerCheck also allows manual checks (/check/, TriggerCheck).
+	TriggerCheck Scope = "trigger-check"
+	// Admin also allows imports, note edits and escalations.
+	Admin Scope = "admin"
+)
+
+// scopes is every scope, weakest first.
+var scopes = []Scope{Read, TriggerCheck, Admin}
+
+// prefix marks a secret as one of ours, e.g. for secret scanners.
+const prefix = "gmt_"
+
+// refreshInterval is how long loaded tokens are trusted before the store is
+// read again, so tokens created or revoked by the CLI take effect on a
+// running server.
+const refreshInterval = time.Minute
+
+// ParseScopes parses a comma-separated scope list.
+func ParseScopes(s string) ([]string, error) {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(strings.ToLower(part))
+		if part == "" {
+			continue
+		}
+		if !slices.Contains(scopes, Scope(part)) {
+			return nil, fmt.Errorf("unknown scope %q (want read, trigger-check or admin)", part)
+		}
+		if !slices.Contains(out, part) {
+			out = append(out, part)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no scopes")
+	}
+	return out, nil
+}
+
+// New returns a token named name with the scopes, and its secret, which is
+// not stored.
+func New(name string, scopes []string, now time.Time) (store.APIToken, string, error) {
+	id := make([]byte, 4)
+	key := make([]byte, 24)
+	if _, err := rand.Read(id); err != nil {
+		return store.APIToken{}, "", err
+	}
+	if _, err := rand.Read(key); err != nil {
+		return store.APIToken{}, "", err
+	}
+	t := store.APIToken{ID: hex.EncodeToString(id), Name: name, Scopes: scopes, CreatedAt: now.UTC()}
+	secret := prefix + t.ID + "_" + base64.RawURLEncoding.EncodeToString(key)
+	t.Hash = Hash(secret)
+	return t, secret, nil
+}
+
+// Hash returns the stored form of a secret.
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Allows reports whether t grants scope: one of its scopes is scope or a
+// stronger one.
+func Allows(t store.APIToken, scope Scope) bool {
+	need := slices.Index(scopes, scope)
+	for _, s := range t.Scopes {
+		if i := slices.Index(scopes, Scope(s)); i >= 0 && i >= need {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	mu       sync.Mutex
+	source   func() ([]store.APIToken, error)
+	tokens   []store.APIToken
+	loadedAt time.Time
+)
+
+// Use makes Lookup check tokens from load, typically the result store's
+// LoadAPITokens. Nil (the default) means no API tokens.
+func Use(load func() ([]store.APIToken, error)) {
+	mu.Lock()
+	defer mu.Unlock()
+	source, tokens, loadedAt = load, nil, time.Time{}
+}
+
+// Lookup returns the token whose secret is secret. Tokens are reloaded at
+// most every refreshInterval; when a reload fails, the last ones are kept.
+func Lookup(secret string) (store.APIToken, bool) {
+	if !strings.HasPrefix(secret, prefix) {
+		return store.APIToken{}, false
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if source == nil {
+		return store.APIToken{}, false
+	}
+	if now := time.Now(); now.Sub(loadedAt) >= refreshInterval {
+		if loaded, err := source(); err == nil {
+			tokens = loaded
+		}
+		loadedAt = now
+	}
+	hash := Hash(secret)
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Hash), []byte(hash)) == 1 {
+			return t, true
+		}
+	}
+	return store.APIToken{}, false
+}
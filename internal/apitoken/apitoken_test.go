@@ -0,0 +1,42 @@
+package apitoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/store"
+)
+
+func TestTokenLookupAndScopes(t *testing.T) {
+	scopes, err := ParseScopes(" Trigger-Check, read ,read")
+	if err != nil || len(scopes) != 2 {
+		t.Fatalf("ParseScopes = %v, %v", scopes, err)
+	}
+	if _, err := ParseScopes("write"); err == nil {
+		t.Error("unknown scope parsed")
+	}
+
+	tok, secret, err := New("ci", []string{string(TriggerCheck)}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(secret, "gmt_"+tok.ID+"_") || tok.Hash == secret || tok.Hash != Hash(secret) {
+		t.Fatalf("token %+v, secret %s", tok, secret)
+	}
+	if !Allows(tok, Read) || !Allows(tok, TriggerCheck) || Allows(tok, Admin) {
+		t.Errorf("trigger-check scopes = read %v, trigger-check %v, admin %v", Allows(tok, Read), Allows(tok, TriggerCheck), Allows(tok, Admin))
+	}
+
+	Use(func() ([]store.APIToken, error) { return []store.APIToken{tok}, nil })
+	t.Cleanup(func() { Use(nil) })
+	if got, ok := Lookup(secret); !ok || got.ID != tok.ID {
+		t.Errorf("Lookup(secret) = %+v, %v", got, ok)
+	}
+	if _, ok := Lookup(secret + "x"); ok {
+		t.Error("a wrong secret was found")
+	}
+	if _, ok := Lookup(tok.Hash); ok {
+		t.Error("the stored hash works as a secret")
+	}
+}
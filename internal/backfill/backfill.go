@@ -0,0 +1,176 @@
+// Package backfill imports the alert emails sent before the monitor had a
+// store (an mbox or JSON export of the inbox) into its history and
+// incidents, so uptime charts and incident lists reach back past the first
+// persisted check.
+//
+// Each "[Endpoint] message" line of an email is an alert: a down check at
+// the email's date. Consecutive alerts for an endpoint form one incident,
+// started when the first alert's "(down for …)" suffix says the streak
+// began and resolved at the last alert, the latest time the archive shows
+// it down.
+package backfill
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+)
+
+// Email is one archived alert email.
+type Email struct {
+	Date    time.Time
+	Subject string
+	Text    string // the body as plain text
+}
+
+// Alert is one endpoint's failure reported by an email.
+type Alert struct {
+	At       time.Time
+	Endpoint string // Endpoint.Name when the alert was sent
+	Message  string
+	// DownFor is how long the endpoint had been down when the alert was
+	// sent, from its "(down for 2h 5m)" suffix; 0 without one.
+	DownFor time.Duration
+}
+
+// Start is when the alert's down streak began.
+func (a Alert) Start() time.Time {
+	return a.At.Add(-a.DownFor)
+}
+
+// alertLine matches an alert line: an optional severity, the endpoint name
+// in brackets, the message and an optional down-for suffix, as
+// notify.FormatEndpointAlert writes them.
+var alertLine = regexp.MustCompile(`^(?:\[(?:CRITICAL|WARNING|INFO)\]\s+)?\[([^\]]+)\]\s+(.+?)(?:\s+\(down for ([^)]+)\))?$`)
+
+// Alerts returns the alerts in emails, oldest first and without duplicates
+// (an email exported from several inboxes). known filters endpoint names;
+// nil accepts any, including group headers ("[GHO/USDC] 3/7 solvers
+// down"), so callers normally pass the configured rows' names. Skipped
+// counts the alert lines of unknown names, by name.
+func Alerts(emails []Email, known func(name string) bool) (alerts []Alert, skipped map[string]int) {
+	skipped = map[string]int{}
+	seen := map[string]bool{}
+	for _, e := range emails {
+		for _, line := range strings.Split(e.Text, "\n") {
+			m := alertLine.FindStringSubmatch(strings.TrimSpace(line))
+			if m == nil {
+				continue
+			}
+			name := strings.TrimSpace(m[1])
+			if isSeverity(name) {
+				continue
+			}
+			if known != nil && !known(name) {
+				skipped[name]++
+				continue
+			}
+			a := Alert{At: e.Date, Endpoint: name, Message: strings.TrimSpace(m[2]), DownFor: parseDownFor(m[3])}
+			key := a.Endpoint + "\x00" + a.At.UTC().Format(time.RFC3339Nano) + "\x00" + a.Message
+			if !seen[key] {
+				seen[key] = true
+				alerts = append(alerts, a)
+			}
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].At.Before(alerts[j].At) })
+	return alerts, skipped
+}
+
+func isSeverity(s string) bool {
+	switch s {
+	case "CRITICAL", "WARNING", "INFO":
+		return true
+	}
+	return false
+}
+
+// parseDownFor reads collector.FormatDuration's output: "2d 4h", "3h 12m",
+// "45m" or "<1m" (0). Unreadable text is 0.
+func parseDownFor(s string) time.Duration {
+	var d time.Duration
+	for _, f := range strings.Fields(s) {
+		if len(f) < 2 {
+			return 0
+		}
+		n, err := strconv.Atoi(f[:len(f)-1])
+		if err != nil {
+			return 0
+		}
+		switch f[len(f)-1] {
+		case 'd':
+			d += time.Duration(n) * 24 * time.Hour
+		case 'h':
+			d += time.Duration(n) * time.Hour
+		case 'm':
+			d += time.Duration(n) * time.Minute
+		default:
+			return 0
+		}
+	}
+	return d
+}
+
+// Incidents groups each endpoint's alerts into down streaks: an alert
+// whose streak began within gap of the previous alert continues its
+// incident. alerts must be oldest first, as Alerts returns them.
+func Incidents(alerts []Alert, gap time.Duration) []store.Incident {
+	var out []store.Incident
+	open := map[string]int{} // endpoint → index in out of its latest incident
+	for _, a := range alerts {
+		if i, ok := open[a.Endpoint]; ok && !a.Start().After(out[i].ResolvedAt.Add(gap)) {
+			out[i].ResolvedAt = a.At
+			continue
+		}
+		open[a.Endpoint] = len(out)
+		out = append(out, store.Incident{Endpoint: a.Endpoint, Status: "down", Message: a.Message, StartedAt: a.Start(), ResolvedAt: a.At})
+	}
+	return out
+}
+
+// Summary counts what Apply wrote.
+type Summary struct {
+	Endpoints int
+	Checks    int
+	Incidents int
+}
+
+// Apply writes alerts to st as down checks in each endpoint's history
+// (times already recorded are skipped, so a rerun adds nothing) and their
+// incidents (upserted by endpoint and start).
+func Apply(st store.Store, alerts []Alert, gap time.Duration) (Summary, error) {
+	byEndpoint := map[string][]collector.CheckRecord{}
+	var names []string
+	for _, a := range alerts {
+		if _, ok := byEndpoint[a.Endpoint]; !ok {
+			names = append(names, a.Endpoint)
+		}
+		byEndpoint[a.Endpoint] = append(byEndpoint[a.Endpoint], collector.CheckRecord{At: a.At, Status: "down", Message: a.Message})
+	}
+	var sum Summary
+	for _, name := range names {
+		if err := st.ImportHistory(name, byEndpoint[name]); err != nil {
+			return sum, fmt.Errorf("%s: %w", name, err)
+		}
+		sum.Endpoints++
+		sum.Checks += len(byEndpoint[name])
+	}
+	for _, inc := range Incidents(alerts, gap) {
+		if err := st.SaveIncident(inc); err != nil {
+			return sum, fmt.Errorf("%s incident at %s: %w", inc.Endpoint, inc.StartedAt.Format(time.RFC3339), err)
+		}
+		sum.Incidents++
+	}
+	if f, ok := st.(store.Flusher); ok {
+		if err := f.Flush(); err != nil {
+			return sum, err
+		}
+	}
+	return sum, nil
+}
@@ -0,0 +1,165 @@
+package backfill
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/store"
+)
+
+const testMbox = `From resend@example.com Mon Mar  2 10:00:00 2026
+From: Monitor <alerts@example.com>
+Subject: Aggregator Monitor
+Date: Mon, 02 Mar 2026 10:00:00 +0000
+Content-Type: text/html; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+<p>[CRITICAL] [Odos-GHO/USDC] no route found (down for 2h 5m)<br>Hint: =
+check the pool is indexed</p>
+
+From resend@example.com Mon Mar  2 11:00:00 2026
+From: Monitor <alerts@example.com>
+Subject: =?utf-8?q?Aggregator_Monitor?=
+Date: Mon, 02 Mar 2026 11:00:00 +0000
+MIME-Version: 1.0
+Content-Type: multipart/alternative; boundary="b1"
+
+--b1
+Content-Type: text/plain; charset=utf-8
+
+[WARNING] [GHO/USDC] 2/7 solvers down (down for 3h)
+
+[Odos-GHO/USDC] no route found (down for 3h 5m)
+[Paraswap-GHO/USDC] timeout
+>From here the response body follows
+--b1
+Content-Type: text/html; charset=utf-8
+
+<p>ignored</p>
+--b1--
+`
+
+func TestParseMbox(t *testing.T) {
+	emails, err := ParseMbox(strings.NewReader(testMbox))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 2 {
+		t.Fatalf("parsed %d emails, want 2", len(emails))
+	}
+	if !emails[0].Date.Equal(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)) || emails[1].Subject != "Aggregator Monitor" {
+		t.Fatalf("emails = %+v", emails)
+	}
+	if !strings.Contains(emails[0].Text, "(down for 2h 5m)\nHint: check the pool is indexed") {
+		t.Fatalf("html body = %q", emails[0].Text)
+	}
+	if strings.Contains(emails[1].Text, "ignored") || !strings.Contains(emails[1].Text, "\nFrom here") {
+		t.Fatalf("multipart body = %q, want the text/plain part unescaped", emails[1].Text)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	in := `[
+		{"date": "2026-03-02T10:00:00Z", "subject": "Aggregator Monitor", "text": "[Odos-X] down"},
+		{"date": "Mon, 02 Mar 2026 11:00:00 +0000", "html": "<p>[Odos-X] still &amp; down</p>"}
+	]`
+	emails, err := ParseJSON(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 2 || emails[0].Text != "[Odos-X] down" || !strings.Contains(emails[1].Text, "[Odos-X] still & down") {
+		t.Fatalf("emails = %+v", emails)
+	}
+	if _, err := ParseJSON(strings.NewReader(`[{"date": "yesterday"}]`)); err == nil {
+		t.Fatal("bad date parsed")
+	}
+}
+
+func TestAlerts(t *testing.T) {
+	emails, err := ParseMbox(strings.NewReader(testMbox))
+	if err != nil {
+		t.Fatal(err)
+	}
+	known := func(name string) bool { return strings.Contains(name, "-") }
+	alerts, skipped := Alerts(append(emails, emails[0]), known)
+
+	if len(alerts) != 3 {
+		t.Fatalf("alerts = %+v, want 3 (the repeated email dropped)", alerts)
+	}
+	a := alerts[0]
+	if a.Endpoint != "Odos-GHO/USDC" || a.Message != "no route found" || a.DownFor != 2*time.Hour+5*time.Minute {
+		t.Fatalf("first alert = %+v", a)
+	}
+	if alerts[2].Endpoint != "Paraswap-GHO/USDC" || alerts[2].DownFor != 0 {
+		t.Fatalf("third alert = %+v", alerts[2])
+	}
+	if skipped["GHO/USDC"] != 1 {
+		t.Fatalf("skipped = %v, want the group header", skipped)
+	}
+}
+
+func TestParseDownFor(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"2d 4h":  52 * time.Hour,
+		"3h 12m": 3*time.Hour + 12*time.Minute,
+		"45m":    45 * time.Minute,
+		"<1m":    0,
+		"":       0,
+	} {
+		if got := parseDownFor(in); got != want {
+			t.Errorf("parseDownFor(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestIncidents(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	alerts := []Alert{
+		{At: t0, Endpoint: "A", Message: "no route", DownFor: time.Hour},
+		{At: t0.Add(time.Hour), Endpoint: "B", Message: "timeout"},
+		{At: t0.Add(2 * time.Hour), Endpoint: "A", Message: "no route", DownFor: 3 * time.Hour},
+		{At: t0.Add(24 * time.Hour), Endpoint: "A", Message: "500"},
+	}
+	got := Incidents(alerts, 6*time.Hour)
+	if len(got) != 3 {
+		t.Fatalf("incidents = %+v, want 3", got)
+	}
+	if got[0].Endpoint != "A" || !got[0].StartedAt.Equal(t0.Add(-time.Hour)) || !got[0].ResolvedAt.Equal(t0.Add(2*time.Hour)) {
+		t.Fatalf("first incident = %+v", got[0])
+	}
+	if got[2].Message != "500" || !got[2].StartedAt.Equal(t0.Add(24*time.Hour)) {
+		t.Fatalf("last incident = %+v", got[2])
+	}
+}
+
+func TestApplyIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	st := store.NewFileStore(path)
+	t0 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	alerts := []Alert{
+		{At: t0, Endpoint: "A", Message: "no route", DownFor: time.Hour},
+		{At: t0.Add(time.Hour), Endpoint: "A", Message: "no route"},
+	}
+	for range 2 {
+		sum, err := Apply(st, alerts, 6*time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sum != (Summary{Endpoints: 1, Checks: 2, Incidents: 1}) {
+			t.Fatalf("summary = %+v", sum)
+		}
+	}
+
+	snap, err := store.NewFileStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.History["A"]) != 2 || len(snap.Incidents) != 1 {
+		t.Fatalf("history = %+v, incidents = %+v", snap.History["A"], snap.Incidents)
+	}
+	if inc := snap.Incidents[0]; !inc.StartedAt.Equal(t0.Add(-time.Hour)) || !inc.ResolvedAt.Equal(t0.Add(time.Hour)) {
+		t.Fatalf("incident = %+v", inc)
+	}
+}
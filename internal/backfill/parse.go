@@ -0,0 +1,236 @@
+package backfill
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FormatFor picks the archive format from a file name: "json" for .json,
+// otherwise "mbox".
+func FormatFor(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return "json"
+	}
+	return "mbox"
+}
+
+// Parse reads an archive in format ("mbox" or "json").
+func Parse(r io.Reader, format string) ([]Email, error) {
+	switch format {
+	case "mbox":
+		return ParseMbox(r)
+	case "json":
+		return ParseJSON(r)
+	}
+	return nil, fmt.Errorf("unknown format %q (want mbox or json)", format)
+}
+
+// ParseMbox reads an mbox export (as Gmail Takeout and Thunderbird write
+// it): messages start at "From " lines, and body lines escaped as ">From "
+// are restored. Multipart messages yield their text/plain part, or their
+// HTML part as text.
+func ParseMbox(r io.Reader) ([]Email, error) {
+	var (
+		emails []Email
+		msg    bytes.Buffer
+		n      int
+	)
+	flush := func() error {
+		if n == 0 {
+			return nil
+		}
+		e, err := parseMessage(msg.Bytes())
+		if err != nil {
+			return fmt.Errorf("message %d: %w", n, err)
+		}
+		emails = append(emails, e)
+		msg.Reset()
+		return nil
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "From ") {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			n++
+			continue
+		}
+		if n == 0 {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("not an mbox: first line is not a \"From \" separator")
+		}
+		if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			line = line[1:]
+		}
+		msg.WriteString(line)
+		msg.WriteString("\r\n")
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// parseMessage reads one RFC 5322 message.
+func parseMessage(raw []byte) (Email, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return Email{}, err
+	}
+	date, err := m.Header.Date()
+	if err != nil {
+		return Email{}, fmt.Errorf("date: %w", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		subject = m.Header.Get("Subject")
+	}
+	text, err := bodyText(m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), m.Body)
+	if err != nil {
+		return Email{}, fmt.Errorf("body: %w", err)
+	}
+	return Email{Date: date, Subject: subject, Text: text}, nil
+}
+
+// bodyText decodes a message or part body to plain text, preferring a
+// multipart's text/plain alternative over its HTML one.
+func bodyText(contentType, encoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, &lineJoiner{r: body})
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		var plain, htmlText string
+		for {
+			p, err := mr.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+			t, err := bodyText(p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"), p)
+			if err != nil {
+				return "", err
+			}
+			pt, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+			switch {
+			case pt == "text/html" && htmlText == "":
+				htmlText = t
+			case plain == "" && (pt == "text/plain" || strings.HasPrefix(pt, "multipart/")):
+				plain = t
+			}
+		}
+		if plain != "" {
+			return plain, nil
+		}
+		return htmlText, nil
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	if mediaType == "text/html" {
+		return htmlToText(string(b)), nil
+	}
+	return strings.ReplaceAll(string(b), "\r\n", "\n"), nil
+}
+
+// lineJoiner drops the CR and LF between base64 lines, which
+// encoding/base64 rejects.
+type lineJoiner struct{ r io.Reader }
+
+func (l *lineJoiner) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	j := 0
+	for _, c := range p[:n] {
+		if c != '\r' && c != '\n' {
+			p[j] = c
+			j++
+		}
+	}
+	return j, err
+}
+
+var (
+	htmlBreak = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+	htmlTag   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// htmlToText flattens an HTML body to lines: breaks and closing paragraphs
+// become newlines, other tags are dropped and entities unescaped. The
+// alert emails wrap the text in <p> with <br> between lines.
+func htmlToText(s string) string {
+	s = strings.NewReplacer("\r\n", " ", "\n", " ").Replace(s)
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	return html.UnescapeString(s)
+}
+
+// jsonEmail is one message of a JSON export. Exports name the body field
+// differently; the first non-empty of Text, Body and HTML is used.
+type jsonEmail struct {
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	Body    string `json:"body"`
+	HTML    string `json:"html"`
+}
+
+// ParseJSON reads a JSON export: an array of {"date", "subject", "text"}
+// objects ("body" or "html" in place of "text" also work). Dates are RFC
+// 3339 or RFC 5322.
+func ParseJSON(r io.Reader) ([]Email, error) {
+	var raw []jsonEmail
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	emails := make([]Email, 0, len(raw))
+	for i, j := range raw {
+		date, err := time.Parse(time.RFC3339, j.Date)
+		if err != nil {
+			if date, err = mail.ParseDate(j.Date); err != nil {
+				return nil, fmt.Errorf("message %d: date %q: want RFC 3339 or RFC 5322", i+1, j.Date)
+			}
+		}
+		text := j.Text
+		if text == "" {
+			text = j.Body
+		}
+		if text == "" {
+			text = htmlToText(j.HTML)
+		}
+		emails = append(emails, Email{Date: date, Subject: j.Subject, Text: text})
+	}
+	return emails, nil
+}
@@ -0,0 +1,106 @@
+// Package boosted checks routes through ERC4626 boosted pools. A boosted pool
+// registers wrapped tokens (e.g. waUSDC), so a swap between the underlying
+// tokens must wrap and unwrap through the Vault buffers:
+//
+//	USDC → waUSDC (buffer) → waGHO (pool) → GHO (buffer)
+//
+// An integration that routes through the underlying tokens instead misses
+// the buffers; Check catches that from the route tokens a provider reports.
+package boosted
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+// WrappedTokensFunc returns a pool's underlying → wrapped token map, keyed by
+// lower-case underlying address; ok is false when the pool is unknown.
+type WrappedTokensFunc func(network, pool string) (wrappedBy map[string]string, ok bool)
+
+var (
+	lookupMu sync.RWMutex
+	lookup   WrappedTokensFunc
+)
+
+// SetWrappedTokens registers the pool token source (the Balancer API pool
+// metadata cache). Without one, Check passes every route.
+func SetWrappedTokens(fn WrappedTokensFunc) {
+	lookupMu.Lock()
+	defer lookupMu.Unlock()
+	lookup = fn
+}
+
+func getWrappedTokens() WrappedTokensFunc {
+	lookupMu.RLock()
+	defer lookupMu.RUnlock()
+	return lookup
+}
+
+// ExpectedRoute returns the token order a swap from tokenIn to tokenOut
+// through a pool with the given wrappers must follow: each side that is an
+// ERC4626 underlying is followed (or preceded) by its wrapped token.
+func ExpectedRoute(tokenIn, tokenOut string, wrappedBy map[string]string) []string {
+	route := []string{tokenIn}
+	if w, ok := wrappedBy[config.NormalizeAddress(tokenIn)]; ok {
+		route = append(route, w)
+	}
+	if w, ok := wrappedBy[config.NormalizeAddress(tokenOut)]; ok {
+		route = append(route, w)
+	}
+	return append(route, tokenOut)
+}
+
+// Check validates the endpoint's RouteTokens against ExpectedRoute for its
+// ExpectedPool and returns a failure message, or "" when the route is fine
+// or cannot be judged: the provider reports no intermediate tokens, the pool
+// is unknown, or neither endpoint token needs wrapping. When the route also
+// marks buffer steps (Balancer SOR), the wrap and unwrap steps must be
+// buffers.
+func Check(e *collector.Endpoint) string {
+	if len(e.RouteTokens) <= 2 || e.ExpectedPool == "" {
+		return ""
+	}
+	fn := getWrappedTokens()
+	if fn == nil {
+		return ""
+	}
+	wrappedBy, ok := fn(e.Network, e.ExpectedPool)
+	if !ok {
+		return ""
+	}
+	want := ExpectedRoute(e.TokenIn, e.TokenOut, wrappedBy)
+	if len(want) == 2 {
+		return ""
+	}
+
+	if !sameTokens(e.RouteTokens, want) {
+		return fmt.Sprintf("route %s, want %s via the pool's wrapped tokens", strings.Join(e.RouteTokens, " → "), strings.Join(want, " → "))
+	}
+	if buffers := e.Route.Buffers(); len(buffers) == len(want)-1 {
+		_, wrapIn := wrappedBy[config.NormalizeAddress(e.TokenIn)]
+		_, wrapOut := wrappedBy[config.NormalizeAddress(e.TokenOut)]
+		if wrapIn && !buffers[0] {
+			return fmt.Sprintf("wrap %s → %s is not a buffer step", want[0], want[1])
+		}
+		if last := len(want) - 2; wrapOut && !buffers[last] {
+			return fmt.Sprintf("unwrap %s → %s is not a buffer step", want[last], want[last+1])
+		}
+	}
+	return ""
+}
+
+func sameTokens(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if !config.SameAddress(got[i], want[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package boosted
+
+import (
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+const (
+	usdc   = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	gho    = "0x6Bb7a212910682DCFdbd5BCBb3e28FB4E8da10Ee"
+	waUSDC = "0xC768c589647798a6EE01A91FdE98EF2ed046DBD6"
+	waGHO  = "0x88b1Cd4b430D95b406E382C3cDBaE54697a0286E"
+	weth   = "0x4200000000000000000000000000000000000006"
+	pool   = "0x7ab124ec4029316c2a42f713828ddf2a192b36db"
+)
+
+func withWrappedTokens(t *testing.T) {
+	t.Helper()
+	SetWrappedTokens(func(network, p string) (map[string]string, bool) {
+		if p != pool {
+			return nil, false
+		}
+		return map[string]string{strings.ToLower(usdc): waUSDC, strings.ToLower(gho): waGHO}, true
+	})
+	t.Cleanup(func() { SetWrappedTokens(nil) })
+}
+
+func TestCheckBoostedRoutes(t *testing.T) {
+	withWrappedTokens(t)
+
+	tests := []struct {
+		name     string
+		route    []string
+		isBuffer []bool
+		pool     string
+		want     string // substring of the failure; "" means pass
+	}{
+		{"wrapped path", []string{usdc, strings.ToLower(waUSDC), waGHO, gho}, nil, pool, ""},
+		{"sor buffers", []string{usdc, waUSDC, waGHO, gho}, []bool{true, false, true}, pool, ""},
+		{"two tokens cannot be judged", []string{usdc, gho}, nil, pool, ""},
+		{"unknown pool", []string{usdc, weth, gho}, nil, "0xother", ""},
+		{"via underlying", []string{usdc, weth, gho}, nil, pool, "via the pool's wrapped tokens"},
+		{"wrong order", []string{usdc, waGHO, waUSDC, gho}, nil, pool, "via the pool's wrapped tokens"},
+		{"wrap not buffered", []string{usdc, waUSDC, waGHO, gho}, []bool{false, false, true}, pool, "wrap " + usdc},
+		{"unwrap not buffered", []string{usdc, waUSDC, waGHO, gho}, []bool{true, false, false}, pool, "unwrap " + waGHO},
+	}
+	for _, tt := range tests {
+		e := &collector.Endpoint{Network: "8453", TokenIn: usdc, TokenOut: gho, ExpectedPool: tt.pool, RouteTokens: tt.route, Route: sorRoute(tt.route, tt.isBuffer)}
+		got := Check(e)
+		if tt.want == "" && got != "" || tt.want != "" && !strings.Contains(got, tt.want) {
+			t.Errorf("%s: Check = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// sorRoute builds a Balancer SOR style route through tokens whose steps are
+// marked as buffers or not; an empty route when isBuffer is nil.
+func sorRoute(tokens []string, isBuffer []bool) collector.RouteGraph {
+	if isBuffer == nil {
+		return collector.RouteGraph{}
+	}
+	var path collector.RoutePath
+	for i, buffer := range isBuffer {
+		path.Hops = append(path.Hops, collector.RouteHop{TokenIn: tokens[i], TokenOut: tokens[i+1], Venues: []collector.RouteVenue{{Buffer: buffer}}})
+	}
+	return collector.RouteGraph{Paths: []collector.RoutePath{path}, MarksBuffers: true}
+}
+
+func TestExpectedRouteWrappedEndpoint(t *testing.T) {
+	wrappedBy := map[string]string{strings.ToLower(usdc): waUSDC}
+	// Trading the registered tokens needs no wrapping.
+	if got := ExpectedRoute(waUSDC, waGHO, wrappedBy); len(got) != 2 {
+		t.Fatalf("ExpectedRoute(wrapped) = %v", got)
+	}
+	if got := ExpectedRoute(usdc, waGHO, wrappedBy); strings.Join(got, ",") != usdc+","+waUSDC+","+waGHO {
+		t.Fatalf("ExpectedRoute(one side) = %v", got)
+	}
+}
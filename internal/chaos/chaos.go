@@ -0,0 +1,182 @@
+// Package chaos injects faults into provider requests and check scheduling
+// (CHAOS_FAULTS), to validate down rechecks, alert delivery and alerting
+// against failures on demand, in tests and on staging. It is off unless
+// configured.
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/metrics"
+)
+
+// Fault is one kind of injected failure.
+type Fault string
+
+const (
+	// FaultLatency delays a request, or a check in the scheduler, by up to
+	// the injector's MaxLatency.
+	FaultLatency Fault = "latency"
+	// FaultDrop fails a request as a dropped connection, or skips a check.
+	FaultDrop Fault = "drop"
+	// FaultMalformed answers for the provider with a 200 whose JSON body is
+	// cut off.
+	FaultMalformed Fault = "malformed"
+	// FaultError answers for the provider with an HTTP 500.
+	FaultError Fault = "500"
+)
+
+// Faults lists every fault, in the order CHAOS_FAULTS documents them.
+var Faults = []Fault{FaultLatency, FaultDrop, FaultMalformed, FaultError}
+
+// ErrDropped is the error of a request failed by FaultDrop.
+var ErrDropped = errors.New("chaos: connection dropped")
+
+var injected = metrics.NewCounter("chaos_faults_injected_total",
+	"Faults injected into provider requests (target=request) and checks (target=check).", "fault", "target", "solver")
+
+// Injector decides which provider requests and checks to fault. A nil
+// Injector injects nothing, so callers needn't check Active.
+type Injector struct {
+	Faults     []Fault
+	Rate       float64       // fraction of requests and checks faulted
+	MaxLatency time.Duration // longest FaultLatency delay
+	Solvers    []string      // route solver types faulted; empty for all
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// New returns an injector of faults drawn from rnd.
+func New(faults []Fault, rate float64, maxLatency time.Duration, solvers []string, rnd *rand.Rand) *Injector {
+	return &Injector{Faults: faults, Rate: rate, MaxLatency: maxLatency, Solvers: solvers, rnd: rnd}
+}
+
+// FromConfig returns the injector CHAOS_FAULTS, CHAOS_RATE, CHAOS_LATENCY
+// and CHAOS_SOLVERS configure, nil when CHAOS_FAULTS is empty, or an error
+// naming an unknown fault.
+func FromConfig() (*Injector, error) {
+	c := config.GetChaos()
+	if len(c.Faults) == 0 {
+		return nil, nil
+	}
+	faults := make([]Fault, 0, len(c.Faults))
+	for _, name := range c.Faults {
+		f := Fault(strings.ToLower(name))
+		if !slices.Contains(Faults, f) {
+			return nil, fmt.Errorf("CHAOS_FAULTS: unknown fault %q, want one of latency, drop, malformed, 500", name)
+		}
+		faults = append(faults, f)
+	}
+	return New(faults, c.Rate, c.MaxLatency, c.Solvers, rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0))), nil
+}
+
+// active is the injector the API client and scheduler consult.
+var active atomic.Pointer[Injector]
+
+// Use makes i the active injector; nil turns injection off.
+func Use(i *Injector) {
+	active.Store(i)
+}
+
+// Active returns the active injector, nil when injection is off.
+func Active() *Injector {
+	return active.Load()
+}
+
+// pick draws whether to fault the solver's next request or check and with
+// which of the faults allowed there.
+func (i *Injector) pick(solver string, allowed ...Fault) (Fault, bool) {
+	if i == nil || (len(i.Solvers) > 0 && !slices.Contains(i.Solvers, solver)) {
+		return "", false
+	}
+	var candidates []Fault
+	for _, f := range i.Faults {
+		if slices.Contains(allowed, f) {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.rnd.Float64() >= i.Rate {
+		return "", false
+	}
+	return candidates[i.rnd.IntN(len(candidates))], true
+}
+
+// latency draws a FaultLatency delay.
+func (i *Injector) latency() time.Duration {
+	if i.MaxLatency <= 0 {
+		return 0
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return time.Duration(i.rnd.Int64N(int64(i.MaxLatency)) + 1)
+}
+
+// Doer sends an HTTP request, as *http.Client does.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Do sends req to a route solver's provider through client, unless a fault
+// delays it, fails it as dropped or answers it instead with a 500 or
+// malformed JSON.
+func (i *Injector) Do(client Doer, req *http.Request, solver string) (*http.Response, error) {
+	f, ok := i.pick(solver, Faults...)
+	if !ok {
+		return client.Do(req)
+	}
+	injected.Inc(string(f), "request", solver)
+	logs.Printf("%s[CHAOS]%s %s: injecting %s fault into %s %s\n", config.ColorOrange, config.ColorReset, solver, f, req.Method, req.URL.Host)
+	switch f {
+	case FaultDrop:
+		return nil, ErrDropped
+	case FaultError:
+		return fakeResponse(req, http.StatusInternalServerError, `{"error":"chaos: injected provider error"}`), nil
+	case FaultMalformed:
+		return fakeResponse(req, http.StatusOK, `{"price":"1","route":[{"pool":"0x`), nil
+	}
+	time.Sleep(i.latency())
+	return client.Do(req)
+}
+
+// fakeResponse is a JSON response to req the provider didn't send.
+func fakeResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// BeforeCheck is the scheduler's hook before checking a route solver's row:
+// a latency fault returns a delay to wait first, a drop fault skips the
+// check this cycle.
+func (i *Injector) BeforeCheck(solver string) (delay time.Duration, skip bool) {
+	f, ok := i.pick(solver, FaultLatency, FaultDrop)
+	if !ok {
+		return 0, false
+	}
+	injected.Inc(string(f), "check", solver)
+	if f == FaultDrop {
+		return 0, true
+	}
+	return i.latency(), false
+}
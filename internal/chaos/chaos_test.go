@@ -0,0 +1,106 @@
+package chaos
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDoFaults(t *testing.T) {
+	sent := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+	get := func(i *Injector, solver string) (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		return i.Do(srv.Client(), req, solver)
+	}
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	// A nil injector passes every request through.
+	var off *Injector
+	if resp, err := get(off, "odos"); err != nil || resp.StatusCode != 200 || sent != 1 {
+		t.Fatalf("nil injector: %v, %v, sent %d", resp, err, sent)
+	}
+
+	if _, err := get(New([]Fault{FaultDrop}, 1, 0, nil, rnd), "odos"); !errors.Is(err, ErrDropped) || sent != 1 {
+		t.Fatalf("drop: err %v, sent %d", err, sent)
+	}
+	resp, err := get(New([]Fault{FaultError}, 1, 0, nil, rnd), "odos")
+	if err != nil || resp.StatusCode != http.StatusInternalServerError || sent != 1 {
+		t.Fatalf("500: %v, %v, sent %d", resp, err, sent)
+	}
+	resp, err = get(New([]Fault{FaultMalformed}, 1, 0, nil, rnd), "odos")
+	if err != nil || resp.StatusCode != 200 || sent != 1 {
+		t.Fatalf("malformed: %v, %v, sent %d", resp, err, sent)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if json.Valid(body) {
+		t.Fatalf("malformed body %s is valid JSON", body)
+	}
+
+	started := time.Now()
+	if resp, err := get(New([]Fault{FaultLatency}, 1, 20*time.Millisecond, nil, rnd), "odos"); err != nil || resp.StatusCode != 200 || sent != 2 {
+		t.Fatalf("latency: %v, %v, sent %d", resp, err, sent)
+	}
+	if time.Since(started) > time.Second {
+		t.Fatal("latency fault waited past MaxLatency")
+	}
+
+	// Only the listed solvers are faulted; a zero rate faults nothing.
+	if _, err := get(New([]Fault{FaultDrop}, 1, 0, []string{"kyberswap"}, rnd), "odos"); err != nil || sent != 3 {
+		t.Fatalf("unlisted solver: err %v, sent %d", err, sent)
+	}
+	if _, err := get(New([]Fault{FaultDrop}, 0, 0, nil, rnd), "odos"); err != nil || sent != 4 {
+		t.Fatalf("zero rate: err %v, sent %d", err, sent)
+	}
+}
+
+func TestBeforeCheck(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	if delay, skip := (*Injector)(nil).BeforeCheck("odos"); delay != 0 || skip {
+		t.Fatalf("nil injector: %v, %v", delay, skip)
+	}
+	if _, skip := New([]Fault{FaultDrop}, 1, 0, nil, rnd).BeforeCheck("odos"); !skip {
+		t.Fatal("drop fault didn't skip the check")
+	}
+	delay, skip := New([]Fault{FaultLatency}, 1, time.Second, nil, rnd).BeforeCheck("odos")
+	if skip || delay <= 0 || delay > time.Second {
+		t.Fatalf("latency: %v, %v", delay, skip)
+	}
+	// Response faults only apply to requests.
+	if delay, skip := New([]Fault{FaultError, FaultMalformed}, 1, time.Second, nil, rnd).BeforeCheck("odos"); delay != 0 || skip {
+		t.Fatalf("response faults: %v, %v", delay, skip)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Setenv("CHAOS_FAULTS", "")
+	if i, err := FromConfig(); i != nil || err != nil {
+		t.Fatalf("unset: %v, %v", i, err)
+	}
+
+	t.Setenv("CHAOS_FAULTS", "Drop, 500")
+	t.Setenv("CHAOS_RATE", "0.5")
+	t.Setenv("CHAOS_SOLVERS", "odos,")
+	i, err := FromConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(i.Faults) != 2 || i.Faults[0] != FaultDrop || i.Faults[1] != FaultError || i.Rate != 0.5 ||
+		i.MaxLatency != 5*time.Second || len(i.Solvers) != 1 || i.Solvers[0] != "odos" {
+		t.Fatalf("injector = %+v", i)
+	}
+
+	t.Setenv("CHAOS_FAULTS", "latency,timeout")
+	if _, err := FromConfig(); err == nil {
+		t.Fatal("unknown fault accepted")
+	}
+}
@@ -0,0 +1,252 @@
+// Package clock is the process's time source: the monitor, the collector,
+// notification throttling, the handlers, the providers and the store read
+// and wait on time through Now, Sleep, AfterFunc and NewTicker. Production
+// uses Real; tests install a Fake with Use and Advance it to run check
+// intervals, schedules, quiet hours, digests and throttles deterministically.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock reads the time and waits on it.
+type Clock interface {
+	Now() time.Time
+	// Sleep blocks for d.
+	Sleep(d time.Duration)
+	// AfterFunc calls f once d has elapsed; Real calls it in its own
+	// goroutine, Fake from Advance.
+	AfterFunc(d time.Duration, f func()) Timer
+	// NewTicker sends the time on C every d; d must be positive.
+	NewTicker(d time.Duration) *Ticker
+}
+
+// Timer is a pending AfterFunc call.
+type Timer interface {
+	// Stop prevents the call; false when it already ran or was stopped.
+	Stop() bool
+}
+
+// Ticker delivers ticks on C until stopped.
+type Ticker struct {
+	C    <-chan time.Time
+	stop func()
+}
+
+// Stop turns off the ticker; no more ticks are sent.
+func (t *Ticker) Stop() {
+	t.stop()
+}
+
+// Real is the system clock.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (realClock) NewTicker(d time.Duration) *Ticker {
+	t := time.NewTicker(d)
+	return &Ticker{C: t.C, stop: t.Stop}
+}
+
+// active is the clock Current returns, Real until Use is called.
+var active atomic.Pointer[Clock]
+
+// Use replaces the process-wide clock, e.g. with a Fake in tests. Call it
+// before starting anything that reads the time.
+func Use(c Clock) {
+	active.Store(&c)
+}
+
+// Current returns the process-wide clock.
+func Current() Clock {
+	if c := active.Load(); c != nil {
+		return *c
+	}
+	return Real
+}
+
+// Now returns the current time on the process-wide clock.
+func Now() time.Time { return Current().Now() }
+
+// Since returns the time elapsed since t on the process-wide clock.
+func Since(t time.Time) time.Duration { return Current().Now().Sub(t) }
+
+// Sleep blocks for d on the process-wide clock.
+func Sleep(d time.Duration) { Current().Sleep(d) }
+
+// AfterFunc calls f once d has elapsed on the process-wide clock.
+func AfterFunc(d time.Duration, f func()) Timer { return Current().AfterFunc(d, f) }
+
+// NewTicker ticks every d on the process-wide clock.
+func NewTicker(d time.Duration) *Ticker { return Current().NewTicker(d) }
+
+// Fake is a Clock that moves only when told to. Sleepers, timers and tickers
+// fire from Advance and Set, in time order, with Now reading each one's due
+// time while it runs; AfterFunc callbacks run synchronously, so a test sees
+// their effects as soon as Advance returns.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	seq     int // orders waiters due at the same time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	at     time.Time
+	seq    int
+	fire   func(now time.Time)
+	every  time.Duration // tickers: the period; 0 fires once
+	active bool
+}
+
+// NewFake returns a Fake reading t.
+func NewFake(t time.Time) *Fake {
+	f := &Fake{now: t}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now returns the fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Sleep blocks until Advance or Set moves the clock d past now.
+func (f *Fake) Sleep(d time.Duration) {
+	done := make(chan struct{})
+	f.add(d, 0, func(time.Time) { close(done) })
+	<-done
+}
+
+// AfterFunc calls fn once the clock has moved d past now; a d <= 0 runs it
+// on the next Advance or Set.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return &fakeTimer{f, f.add(d, 0, func(time.Time) { fn() })}
+}
+
+// NewTicker sends the fake time on C every d. Like time.Ticker it drops
+// ticks a slow reader misses.
+func (f *Fake) NewTicker(d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	c := make(chan time.Time, 1)
+	w := f.add(d, d, func(now time.Time) {
+		select {
+		case c <- now:
+		default:
+		}
+	})
+	return &Ticker{C: c, stop: func() { f.remove(w) }}
+}
+
+// Advance moves the clock forward by d, firing everything due on the way.
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the clock to t, firing everything due up to it. The clock never
+// moves backwards; an earlier t only fires what is already due.
+func (f *Fake) Set(t time.Time) {
+	for {
+		f.mu.Lock()
+		w := f.nextDueLocked(t)
+		if w == nil {
+			if t.After(f.now) {
+				f.now = t
+			}
+			f.mu.Unlock()
+			return
+		}
+		if w.at.After(f.now) {
+			f.now = w.at
+		}
+		now := f.now
+		if w.every > 0 {
+			w.at = w.at.Add(w.every)
+			f.seq++
+			w.seq = f.seq
+		} else {
+			f.removeLocked(w)
+		}
+		f.mu.Unlock()
+		w.fire(now)
+	}
+}
+
+// BlockUntil waits until n sleepers, timers and tickers are pending, so a
+// test can Advance once a goroutine under test has started waiting.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) add(d, every time.Duration, fire func(time.Time)) *fakeWaiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	w := &fakeWaiter{at: f.now.Add(d), seq: f.seq, fire: fire, every: every, active: true}
+	f.waiters = append(f.waiters, w)
+	f.cond.Broadcast()
+	return w
+}
+
+func (f *Fake) remove(w *fakeWaiter) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.removeLocked(w)
+}
+
+func (f *Fake) removeLocked(w *fakeWaiter) bool {
+	if !w.active {
+		return false
+	}
+	w.active = false
+	for i, o := range f.waiters {
+		if o == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// nextDueLocked returns the earliest waiter due at or before t.
+func (f *Fake) nextDueLocked(t time.Time) *fakeWaiter {
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		a, b := f.waiters[i], f.waiters[j]
+		if !a.at.Equal(b.at) {
+			return a.at.Before(b.at)
+		}
+		return a.seq < b.seq
+	})
+	if len(f.waiters) == 0 || f.waiters[0].at.After(t) {
+		return nil
+	}
+	return f.waiters[0]
+}
+
+type fakeTimer struct {
+	f *Fake
+	w *fakeWaiter
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.f.remove(t.w)
+}
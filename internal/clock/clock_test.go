@@ -0,0 +1,63 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestFakeAfterFuncFiresInOrderAtItsTime(t *testing.T) {
+	f := NewFake(start)
+	var fired []string
+	var at []time.Time
+	record := func(name string) func() {
+		return func() { fired = append(fired, name); at = append(at, f.Now()) }
+	}
+	f.AfterFunc(2*time.Hour, record("b"))
+	f.AfterFunc(time.Hour, record("a"))
+	stopped := f.AfterFunc(90*time.Minute, record("stopped"))
+	if !stopped.Stop() || stopped.Stop() {
+		t.Fatal("Stop should succeed once")
+	}
+
+	f.Advance(59 * time.Minute)
+	if len(fired) != 0 {
+		t.Fatalf("fired early: %v", fired)
+	}
+	f.Advance(3 * time.Hour)
+	if len(fired) != 2 || fired[0] != "a" || fired[1] != "b" {
+		t.Fatalf("fired = %v, want [a b]", fired)
+	}
+	if !at[0].Equal(start.Add(time.Hour)) || !at[1].Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("Now during callbacks = %v", at)
+	}
+	if got := f.Now(); !got.Equal(start.Add(3*time.Hour + 59*time.Minute)) {
+		t.Fatalf("Now = %v", got)
+	}
+}
+
+func TestFakeTickerAndSleep(t *testing.T) {
+	f := NewFake(start)
+	tk := f.NewTicker(time.Minute)
+	defer tk.Stop()
+
+	woke := make(chan time.Time)
+	go func() {
+		f.Sleep(90 * time.Second)
+		woke <- f.Now()
+	}()
+	f.BlockUntil(2) // the ticker and the sleeper
+
+	f.Advance(time.Minute)
+	if tick := <-tk.C; !tick.Equal(start.Add(time.Minute)) {
+		t.Fatalf("tick = %v", tick)
+	}
+	f.Advance(time.Minute)
+	if got := <-woke; got.Before(start.Add(90 * time.Second)) {
+		t.Fatalf("sleeper woke at %v", got)
+	}
+	if tick := <-tk.C; !tick.Equal(start.Add(2 * time.Minute)) {
+		t.Fatalf("second tick = %v", tick)
+	}
+}
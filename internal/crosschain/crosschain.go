@@ -0,0 +1,200 @@
+// Package crosschain compares the Balancer quotes for one token pair across
+// networks (GHO/USDC on Mainnet, Arbitrum and Base) net of what executing
+// the swap would cost in gas, for the dashboard's cross-chain view. Quotes
+// are valued in USD at the pool's token prices, so rows with different
+// swap sizes or directions still compare; gas is a per-hop estimate priced
+// at the network's gas price (providers.GasPriceFor) and its native token
+// price (discovery.NativePriceUSD).
+package crosschain
+
+import (
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/discovery"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+// The execution cost model: a swap through the Vault costs swapBaseGas plus
+// hopGas per hop of the route. Real costs vary by pool type and hooks; the
+// model only needs to rank chains whose gas prices differ by orders of
+// magnitude.
+const (
+	swapBaseGas = 60_000
+	hopGas      = 90_000
+)
+
+// PairLabel is the Labels key that names a BaseEndpoint's pair explicitly,
+// for names without a "(A/B)" part.
+const PairLabel = "pair"
+
+// Quote is one network's best Balancer quote for a pair, valued in USD.
+type Quote struct {
+	Network  string
+	BaseName string
+	Solver   string // RouteSolver of the best quote
+	Hops     int
+	InUSD    float64 // the swap amount's value
+	OutUSD   float64 // the quoted amount's value
+	GasUnits uint64
+	GasPrice providers.GasPrice
+	// GasUSD is GasUnits at GasPrice, in USD; GasKnown is false when the
+	// network's native token has no price, and GasUSD is then 0.
+	GasUSD   float64
+	GasKnown bool
+	// Rate is OutUSD / InUSD; NetRate takes gas off OutUSD first.
+	Rate    float64
+	NetRate float64
+	// BehindBps is how far NetRate trails the pair's best, in basis points
+	// of the best; 0 for the best network.
+	BehindBps float64
+}
+
+// Pair is one token pair's quotes on two or more networks, best NetRate
+// first.
+type Pair struct {
+	Name   string
+	Quotes []Quote
+}
+
+// The lookups Compare prices quotes with; swapped in tests.
+var (
+	tokenPriceUSD = func(e collector.Endpoint, token string) (float64, bool) {
+		meta, ok := discovery.CachedPoolMetadata(e.Network, e.ExpectedPool)
+		price := meta.PricesUSD[config.NormalizeAddress(token)]
+		return price, ok && price > 0
+	}
+	nativePriceUSD = discovery.NativePriceUSD
+	gasPriceFor    = providers.GasPriceFor
+)
+
+// pairInName matches the trailing "(GHO/USDC)" of a BaseEndpoint name.
+var pairInName = regexp.MustCompile(`\(([^()]+/[^()]+)\)\s*$`)
+
+// PairName returns the pair a row quotes: its PairLabel, else the "(A/B)"
+// part of its BaseName; "" when neither is set.
+func PairName(e collector.Endpoint) string {
+	if p := strings.TrimSpace(e.Labels[PairLabel]); p != "" {
+		return p
+	}
+	if m := pairInName.FindStringSubmatch(e.BaseName); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+// Compare groups the up rows of endpoints by pair, keeps each network's
+// best quote, and returns the pairs quoted on at least two networks, by
+// name. Rows whose token prices are unknown are left out: their quotes
+// can't be valued.
+func Compare(endpoints []collector.Endpoint) []Pair {
+	best := map[string]map[string]Quote{} // pair key → network → quote
+	names := map[string]string{}
+	for _, e := range endpoints {
+		name := PairName(e)
+		if name == "" || e.LastStatus != "up" {
+			continue
+		}
+		q, ok := value(e)
+		if !ok {
+			continue
+		}
+		key := strings.ToUpper(name)
+		if best[key] == nil {
+			best[key] = map[string]Quote{}
+			names[key] = name
+		}
+		if cur, seen := best[key][e.Network]; !seen || q.OutUSD > cur.OutUSD {
+			best[key][e.Network] = q
+		}
+	}
+
+	var pairs []Pair
+	for key, byNetwork := range best {
+		if len(byNetwork) < 2 {
+			continue
+		}
+		p := Pair{Name: names[key]}
+		for _, q := range byNetwork {
+			p.Quotes = append(p.Quotes, withGas(q))
+		}
+		sort.Slice(p.Quotes, func(i, j int) bool {
+			if p.Quotes[i].NetRate != p.Quotes[j].NetRate {
+				return p.Quotes[i].NetRate > p.Quotes[j].NetRate
+			}
+			return p.Quotes[i].Network < p.Quotes[j].Network
+		})
+		top := p.Quotes[0].NetRate
+		for i := range p.Quotes {
+			if top > 0 {
+				p.Quotes[i].BehindBps = (top - p.Quotes[i].NetRate) / top * 10000
+			}
+		}
+		pairs = append(pairs, p)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+	return pairs
+}
+
+// value prices a row's quote in USD; ok is false when the quote or either
+// token's price is missing.
+func value(e collector.Endpoint) (Quote, bool) {
+	in := collector.ParseAmount(e.SwapAmount)
+	out := e.Amounts().Return
+	if !in.Positive() || !out.Positive() {
+		return Quote{}, false
+	}
+	inPrice, okIn := tokenPriceUSD(e, e.TokenIn)
+	outPrice, okOut := tokenPriceUSD(e, e.TokenOut)
+	if !okIn || !okOut {
+		return Quote{}, false
+	}
+	q := Quote{
+		Network:  e.Network,
+		BaseName: e.BaseName,
+		Solver:   e.RouteSolver,
+		Hops:     hops(e),
+		InUSD:    tokens(in, e.TokenInDecimals) * inPrice,
+		OutUSD:   tokens(out, e.TokenOutDecimals) * outPrice,
+	}
+	q.GasUnits = GasUnits(q.Hops)
+	q.Rate = q.OutUSD / q.InUSD
+	return q, true
+}
+
+// withGas prices q's execution on its network and sets NetRate.
+func withGas(q Quote) Quote {
+	q.GasPrice = gasPriceFor(q.Network)
+	q.NetRate = q.Rate
+	native, ok := nativePriceUSD(q.Network)
+	if !ok {
+		return q
+	}
+	wei, _ := new(big.Float).SetInt(q.GasPrice.Cost(q.GasUnits)).Float64()
+	q.GasUSD, q.GasKnown = wei/1e18*native, true
+	q.NetRate = (q.OutUSD - q.GasUSD) / q.InUSD
+	return q
+}
+
+// GasUnits estimates the gas of a swap with the given hops.
+func GasUnits(hops int) uint64 {
+	return swapBaseGas + uint64(max(hops, 1))*hopGas
+}
+
+// hops is the last route's hop count, else the row's expected one, else 1.
+func hops(e collector.Endpoint) int {
+	if !e.Route.IsEmpty() {
+		return e.Route.HopCount()
+	}
+	return max(e.ExpectedNoHops, 1)
+}
+
+// tokens converts a base-unit amount to whole tokens.
+func tokens(a collector.Amount, decimals int) float64 {
+	return a.Float64() / math.Pow10(decimals)
+}
@@ -0,0 +1,123 @@
+package crosschain
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+// stubPrices replaces the price lookups for a test: 1 USD per token, the
+// given native prices, and gasWei on every network.
+func stubPrices(t *testing.T, native map[string]float64, gasWei map[string]int64) {
+	t.Helper()
+	oldToken, oldNative, oldGas := tokenPriceUSD, nativePriceUSD, gasPriceFor
+	t.Cleanup(func() { tokenPriceUSD, nativePriceUSD, gasPriceFor = oldToken, oldNative, oldGas })
+	tokenPriceUSD = func(e collector.Endpoint, token string) (float64, bool) {
+		return 1, token != "0xunpriced"
+	}
+	nativePriceUSD = func(network string) (float64, bool) {
+		p, ok := native[network]
+		return p, ok
+	}
+	gasPriceFor = func(network string) providers.GasPrice {
+		return providers.GasPrice{Wei: big.NewInt(gasWei[network]), Source: "test"}
+	}
+}
+
+func row(name, network, solver, out string) collector.Endpoint {
+	return collector.Endpoint{
+		BaseName: name, Network: network, RouteSolver: solver, LastStatus: "up",
+		TokenIn: "0xgho", TokenOut: "0xusdc", TokenInDecimals: 18, TokenOutDecimals: 6,
+		SwapAmount: "1000000000000000000000", ReturnAmount: out, ExpectedNoHops: 1,
+	}
+}
+
+func TestPairName(t *testing.T) {
+	for _, tc := range []struct {
+		e    collector.Endpoint
+		want string
+	}{
+		{collector.Endpoint{BaseName: "Mainnet Stable (GHO/USDC)"}, "GHO/USDC"},
+		{collector.Endpoint{BaseName: "Base Boosted ( GHO/USDC )  "}, "GHO/USDC"},
+		{collector.Endpoint{BaseName: "Arbitrum GHO pool", Labels: config.Labels{"pair": "GHO/USDC"}}, "GHO/USDC"},
+		{collector.Endpoint{BaseName: "Mainnet (v3)"}, ""},
+		{collector.Endpoint{BaseName: "Mainnet"}, ""},
+	} {
+		if got := PairName(tc.e); got != tc.want {
+			t.Errorf("PairName(%q) = %q, want %q", tc.e.BaseName, got, tc.want)
+		}
+	}
+}
+
+func TestCompareNetsOutGas(t *testing.T) {
+	// Mainnet quotes 1000.5 USDC for 1000 GHO but pays 150k gas at 20 gwei
+	// with ETH at 2000 USD (6 USD); Base quotes 999.9 and pays a fraction of
+	// a cent, so Base wins net of gas.
+	stubPrices(t, map[string]float64{"1": 2000, "8453": 2000}, map[string]int64{"1": 20e9, "8453": 1e6})
+	pairs := Compare([]collector.Endpoint{
+		row("Mainnet (GHO/USDC)", "1", "paraswap", "1000500000"),
+		row("Mainnet (GHO/USDC)", "1", "odos", "1000100000"),
+		row("Base (gho/usdc)", "8453", "odos", "999900000"),
+		row("Gnosis (GHO/USDC)", "100", "odos", "0"),            // no quote
+		row("Avalanche (WAVAX/USDC)", "43114", "odos", "10000"), // single network
+	})
+	if len(pairs) != 1 {
+		t.Fatalf("Compare = %+v, want one pair", pairs)
+	}
+	p := pairs[0]
+	if len(p.Quotes) != 2 {
+		t.Fatalf("quotes = %+v, want Mainnet and Base", p.Quotes)
+	}
+	base, mainnet := p.Quotes[0], p.Quotes[1]
+	if base.Network != "8453" || mainnet.Network != "1" {
+		t.Fatalf("order = %s, %s; want Base first net of gas", base.Network, mainnet.Network)
+	}
+	if mainnet.Solver != "paraswap" || mainnet.Hops != 1 || mainnet.GasUnits != 150_000 {
+		t.Errorf("mainnet = %+v, want paraswap's 1-hop quote at 150k gas", mainnet)
+	}
+	if !mainnet.GasKnown || math.Abs(mainnet.GasUSD-6) > 1e-9 {
+		t.Errorf("mainnet GasUSD = %v, want 6", mainnet.GasUSD)
+	}
+	if math.Abs(mainnet.Rate-1.0005) > 1e-9 || math.Abs(mainnet.NetRate-0.9945) > 1e-9 {
+		t.Errorf("mainnet Rate, NetRate = %v, %v; want 1.0005, 0.9945", mainnet.Rate, mainnet.NetRate)
+	}
+	if base.BehindBps != 0 || mainnet.BehindBps <= 0 {
+		t.Errorf("BehindBps = %v, %v; want 0 for Base and positive for Mainnet", base.BehindBps, mainnet.BehindBps)
+	}
+}
+
+func TestCompareWithoutNativePrice(t *testing.T) {
+	stubPrices(t, map[string]float64{"1": 2000}, map[string]int64{"1": 20e9, "999": 1e9})
+	pairs := Compare([]collector.Endpoint{
+		row("Mainnet (GHO/USDC)", "1", "odos", "1000000000"),
+		row("HyperEVM (GHO/USDC)", "999", "odos", "999000000"),
+	})
+	if len(pairs) != 1 || len(pairs[0].Quotes) != 2 {
+		t.Fatalf("Compare = %+v", pairs)
+	}
+	for _, q := range pairs[0].Quotes {
+		if q.Network == "999" && (q.GasKnown || q.NetRate != q.Rate) {
+			t.Errorf("HyperEVM quote = %+v, want gas unknown and NetRate = Rate", q)
+		}
+	}
+}
+
+func TestCompareSkipsUnvaluedRows(t *testing.T) {
+	stubPrices(t, map[string]float64{"1": 2000, "8453": 2000}, map[string]int64{"1": 20e9, "8453": 1e6})
+	down := row("Base (GHO/USDC)", "8453", "odos", "999000000")
+	down.LastStatus = "down"
+	unpriced := row("Base (GHO/USDC)", "8453", "paraswap", "999000000")
+	unpriced.TokenOut = "0xunpriced"
+	pairs := Compare([]collector.Endpoint{
+		row("Mainnet (GHO/USDC)", "1", "odos", "1000000000"),
+		down,
+		unpriced,
+	})
+	if len(pairs) != 0 {
+		t.Errorf("Compare = %+v, want none: Base has no valued up row", pairs)
+	}
+}
@@ -11,6 +11,7 @@ const (
 	literalUnknown  = "UNKNOWN"
 	suffixUnderlying = "underlying"
 	suffixBoosted    = "boosted"
+	suffixSurge      = "surge"
 )
 
 // slugSegment uppercases ASCII letters, keeps digits, splits other runes into
@@ -89,6 +90,8 @@ func formatDiscoveredBaseName(r TestRow) string {
 	switch {
 	case r.Variant == "underlying":
 		base += "-" + suffixUnderlying
+	case r.Variant == "surge":
+		base += "-" + suffixSurge
 	case r.Boosted:
 		base += "-" + suffixBoosted
 	}
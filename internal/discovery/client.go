@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"go-monitoring/config"
+	"go-monitoring/internal/logs"
 )
 
 // balancerAPIEndpoint is the hardcoded Balancer V3 GraphQL endpoint. No env
@@ -113,10 +114,10 @@ type graphqlRequest struct {
 	Variables map[string]interface{} `json:"variables,omitempty"`
 }
 
+// graphqlResponse is the response envelope; Data points at the caller's
+// query-specific struct.
 type graphqlResponse struct {
-	Data struct {
-		PoolGetPools []rawPool `json:"poolGetPools"`
-	} `json:"data"`
+	Data   interface{} `json:"data"`
 	Errors []struct {
 		Message string `json:"message"`
 	} `json:"errors,omitempty"`
@@ -144,7 +145,7 @@ func fetchPoolsWithRetry(chainEnum string) ([]rawPool, error) {
 		raw, err := fetchPools(chainEnum)
 		if err == nil {
 			if attempt > 1 {
-				fmt.Printf("%s[DISCOVERY]%s %s succeeded on attempt %d/%d\n",
+				logs.Printf("%s[DISCOVERY]%s %s succeeded on attempt %d/%d\n",
 					config.ColorGreen, config.ColorReset, chainEnum, attempt, maxFetchAttempts)
 			}
 			return raw, nil
@@ -154,7 +155,7 @@ func fetchPoolsWithRetry(chainEnum string) ([]rawPool, error) {
 			break
 		}
 		backoff := time.Duration(attempt*5) * time.Second
-		fmt.Printf("%s[DISCOVERY]%s %s attempt %d/%d failed: %v (retrying in %s)\n",
+		logs.Printf("%s[DISCOVERY]%s %s attempt %d/%d failed: %v (retrying in %s)\n",
 			config.ColorYellow, config.ColorReset, chainEnum, attempt, maxFetchAttempts, err, backoff)
 		time.Sleep(backoff)
 	}
@@ -164,44 +165,57 @@ func fetchPoolsWithRetry(chainEnum string) ([]rawPool, error) {
 // fetchPools queries the Balancer GraphQL API for V3 pools on the given chain
 // enum (e.g. "MAINNET") and returns the raw decoded result.
 func fetchPools(chainEnum string) ([]rawPool, error) {
-	reqBody, err := json.Marshal(graphqlRequest{
-		Query:     poolsQuery,
-		Variables: map[string]interface{}{"chainIn": []string{chainEnum}},
-	})
+	var data struct {
+		PoolGetPools []rawPool `json:"poolGetPools"`
+	}
+	err := postGraphQL(httpClient, poolsQuery, map[string]interface{}{"chainIn": []string{chainEnum}}, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data.PoolGetPools, nil
+}
+
+// postGraphQL sends one query to the Balancer GraphQL API and decodes the
+// response's data object into data. GraphQL errors are returned as errors.
+func postGraphQL(client *http.Client, query string, variables map[string]interface{}, data interface{}) error {
+	reqBody, err := json.Marshal(graphqlRequest{Query: query, Variables: variables})
 	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
+		return fmt.Errorf("marshal request: %w", err)
 	}
 
 	req, err := http.NewRequest(http.MethodPost, balancerAPIEndpoint, bytes.NewReader(reqBody))
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
+		return fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return fmt.Errorf("read response: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, truncate(string(body), 256))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, truncate(string(body), 256))
 	}
+	return decodeGraphQL(body, data)
+}
 
-	var decoded graphqlResponse
+func decodeGraphQL(body []byte, data interface{}) error {
+	decoded := graphqlResponse{Data: data}
 	if err := json.Unmarshal(body, &decoded); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+		return fmt.Errorf("decode response: %w", err)
 	}
 	if len(decoded.Errors) > 0 {
-		return nil, fmt.Errorf("graphql errors: %s", decoded.Errors[0].Message)
+		return fmt.Errorf("graphql errors: %s", decoded.Errors[0].Message)
 	}
-	return decoded.Data.PoolGetPools, nil
+	return nil
 }
 
 func truncate(s string, n int) string {
@@ -5,14 +5,14 @@ import (
 	"runtime/debug"
 	"sort"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/collector"
+	"go-monitoring/internal/logs"
 	"go-monitoring/internal/monitor"
-	"go-monitoring/notifications"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
 )
 
 // testSetRunner is invoked after each discovery refresh once the discovered
@@ -63,16 +63,17 @@ var excludedPoolAddresses = map[string]struct{}{
 	"0x83470106402ed0bc83f91bb13266d35bdb23f1b9": {},
 }
 
-// Run executes discovery immediately, then re-runs on the configured cadence.
-// Designed to be invoked as `go discovery.Run(...)` from main. Fire-and-forget:
-// the caller does not block on the first run.
+// Run executes discovery after firstRunDelay (zero: immediately), then re-runs
+// on the configured cadence. Designed to be invoked as `go discovery.Run(...)`
+// from main. Fire-and-forget: the caller does not block on the first run.
 //
 // Each runOnce is wrapped in a deferred recover so a panic anywhere in the
 // fetch / process / test-set / runner chain logs the failure, emails an
 // alert, and lets the ticker fire again. Without this guard a single panic
 // would kill the goroutine for the rest of the process lifetime and all
 // future discovery refreshes would silently stop.
-func Run(intervalHours int) {
+func Run(intervalHours int, firstRunDelay time.Duration) {
+	time.Sleep(firstRunDelay)
 	safeRunOnce()
 	ticker := time.NewTicker(time.Duration(intervalHours) * time.Hour)
 	defer ticker.Stop()
@@ -89,9 +90,9 @@ func safeRunOnce() {
 	defer func() {
 		if r := recover(); r != nil {
 			stack := debug.Stack()
-			fmt.Printf("%s[DISCOVERY PANIC]%s recovered: %v\n%s\n",
+			logs.Printf("%s[DISCOVERY PANIC]%s recovered: %v\n%s\n",
 				config.ColorRed, config.ColorReset, r, stack)
-			notifications.SendEmail(fmt.Sprintf("Discovery goroutine panicked: %v", r))
+			notify.Send(notify.SeverityCritical, fmt.Sprintf("Discovery goroutine panicked: %v", r))
 		}
 	}()
 	runOnce()
@@ -100,11 +101,12 @@ func safeRunOnce() {
 // runOnce executes one discovery cycle across all enabled networks. Each
 // network is fetched and processed independently so a single failure does not
 // poison the snapshots for other networks. After the per-network loop the
-// daily test set is rebuilt from the current cross-network snapshot and
+// BaseEndpoints' pools are checked for migrations (see checkPoolMigrations),
+// the daily test set is rebuilt from the current cross-network snapshot and
 // pushed into the collector's discovered-endpoints store; the registered
 // runner (if any) then drives provider checks against it.
 func runOnce() {
-	fmt.Printf("%s[DISCOVERY]%s starting run\n", config.ColorBlue, config.ColorReset)
+	logs.Printf("%s[DISCOVERY]%s starting run\n", config.ColorBlue, config.ColorReset)
 
 	for _, cfg := range config.DiscoveryConfigs {
 		if !cfg.Enabled {
@@ -112,12 +114,12 @@ func runOnce() {
 		}
 		chainEnum := config.BalancerAPIChain(cfg.Network)
 		if chainEnum == "" {
-			fmt.Printf("%s[DISCOVERY]%s skipping network %s: unsupported by Balancer API\n",
+			logs.Printf("%s[DISCOVERY]%s skipping network %s: unsupported by Balancer API\n",
 				config.ColorYellow, config.ColorReset, cfg.Network)
 			continue
 		}
 
-		fmt.Printf("%s[DISCOVERY]%s fetching network %s (%s)\n",
+		logs.Printf("%s[DISCOVERY]%s fetching network %s (%s)\n",
 			config.ColorBlue, config.ColorReset, cfg.Network, chainEnum)
 
 		raw, err := fetchPoolsWithRetry(chainEnum)
@@ -126,10 +128,10 @@ func runOnce() {
 			// "first run failed — nothing to keep". Otherwise the operator
 			// can't tell whether /pools should have data for this network.
 			if last := networkLastSuccessAt(cfg.Network); !last.IsZero() {
-				fmt.Printf("%s[DISCOVERY ERROR]%s network %s: %v (keeping previous snapshot from %s ago)\n",
+				logs.Printf("%s[DISCOVERY ERROR]%s network %s: %v (keeping previous snapshot from %s ago)\n",
 					config.ColorRed, config.ColorReset, cfg.Network, err, time.Since(last).Round(time.Second))
 			} else {
-				fmt.Printf("%s[DISCOVERY ERROR]%s network %s: %v (no previous snapshot; will retry on next tick)\n",
+				logs.Printf("%s[DISCOVERY ERROR]%s network %s: %v (no previous snapshot; will retry on next tick)\n",
 					config.ColorRed, config.ColorReset, cfg.Network, err)
 			}
 			continue
@@ -137,11 +139,16 @@ func runOnce() {
 
 		pools := processNetwork(cfg, raw)
 		setNetwork(cfg.Network, pools, time.Now())
+		seedPoolMetadata(cfg.Network, pools)
 
-		fmt.Printf("%s[DISCOVERY RESULT]%s network %s: %d pools (from %d raw)\n",
+		logs.Printf("%s[DISCOVERY RESULT]%s network %s: %d pools (from %d raw)\n",
 			config.ColorGreen, config.ColorReset, cfg.Network, len(pools), len(raw))
 	}
 
+	// Migration checks email; leave them to the instance running the checks.
+	if monitor.IsLeader() {
+		checkPoolMigrations()
+	}
 	rebuildTestSet()
 
 	if runner := getTestSetRunner(); runner != nil {
@@ -165,6 +172,9 @@ func rebuildTestSet() {
 	}
 
 	rows, poolKeys := Build(snapshot, config.GetDiscoveryTestPoolsPerGroup(), tradePctByNetwork)
+	if config.GetHookTriggerEnabled() {
+		rows = append(rows, SurgeRows(snapshot, rows)...)
+	}
 
 	inputs := make([]monitor.ExpandInput, 0, len(rows))
 	for _, r := range rows {
@@ -188,7 +198,7 @@ func rebuildTestSet() {
 	eps := monitor.ExpandForSolvers(inputs)
 	collector.SetDiscoveredEndpoints(eps, poolKeys)
 
-	fmt.Printf("%s[DISCOVERY TESTSET]%s %d rows × solvers = %d endpoints (%d pools selected)\n",
+	logs.Printf("%s[DISCOVERY TESTSET]%s %d rows × solvers = %d endpoints (%d pools selected)\n",
 		config.ColorBlue, config.ColorReset, len(rows), len(eps), len(poolKeys))
 }
 
@@ -196,7 +206,7 @@ func rebuildTestSet() {
 // use in deterministic, address-based BaseName keys. Inputs that are too
 // short fall back to the original string.
 func shortAddr(addr string) string {
-	a := strings.ToLower(addr)
+	a := config.NormalizeAddress(addr)
 	if len(a) >= 10 {
 		return a[:10]
 	}
@@ -213,12 +223,12 @@ func processNetwork(cfg config.DiscoveryConfig, raw []rawPool) []Pool {
 		totalUSD   float64
 		swapFee    float64
 		volume24h  float64
-		tokenAddrs []string // lowercased registered token addresses, for pair-freq
+		tokenAddrs []string // normalized registered token addresses, for pair-freq
 	}
 	survivors := make([]parsed, 0, len(raw))
 
 	for _, r := range raw {
-		if _, excluded := excludedPoolAddresses[strings.ToLower(r.Address)]; excluded {
+		if _, excluded := excludedPoolAddresses[config.NormalizeAddress(r.Address)]; excluded {
 			continue
 		}
 
@@ -240,7 +250,7 @@ func processNetwork(cfg config.DiscoveryConfig, raw []rawPool) []Pool {
 
 		tvl, err := strconv.ParseFloat(r.DynamicData.TotalLiquidity, 64)
 		if err != nil {
-			fmt.Printf("%s[DISCOVERY]%s skipping pool %s: parse totalLiquidity %q: %v\n",
+			logs.Printf("%s[DISCOVERY]%s skipping pool %s: parse totalLiquidity %q: %v\n",
 				config.ColorYellow, config.ColorReset, r.Address, r.DynamicData.TotalLiquidity, err)
 			continue
 		}
@@ -250,14 +260,14 @@ func processNetwork(cfg config.DiscoveryConfig, raw []rawPool) []Pool {
 
 		fee, err := strconv.ParseFloat(r.DynamicData.SwapFee, 64)
 		if err != nil {
-			fmt.Printf("%s[DISCOVERY]%s skipping pool %s: parse swapFee %q: %v\n",
+			logs.Printf("%s[DISCOVERY]%s skipping pool %s: parse swapFee %q: %v\n",
 				config.ColorYellow, config.ColorReset, r.Address, r.DynamicData.SwapFee, err)
 			continue
 		}
 
 		vol, err := strconv.ParseFloat(r.DynamicData.Volume24h, 64)
 		if err != nil {
-			fmt.Printf("%s[DISCOVERY]%s skipping pool %s: parse volume24h %q: %v\n",
+			logs.Printf("%s[DISCOVERY]%s skipping pool %s: parse volume24h %q: %v\n",
 				config.ColorYellow, config.ColorReset, r.Address, r.DynamicData.Volume24h, err)
 			continue
 		}
@@ -284,7 +294,7 @@ func processNetwork(cfg config.DiscoveryConfig, raw []rawPool) []Pool {
 				}
 			}
 			tokens = append(tokens, tok)
-			tokenAddrs = append(tokenAddrs, strings.ToLower(t.Address))
+			tokenAddrs = append(tokenAddrs, config.NormalizeAddress(t.Address))
 		}
 
 		hookType := ""
@@ -416,7 +426,7 @@ func medianUSD(balances []float64) float64 {
 }
 
 // pairKeys returns the deterministic keys for every C(N,2) combination of the
-// given lowercased token addresses. Each key is "lower|higher" so direction is
+// given normalized token addresses (config.NormalizeAddress). Each key is "lower|higher" so direction is
 // normalised. Pools with <2 tokens produce no pairs.
 func pairKeys(addrs []string) []string {
 	n := len(addrs)
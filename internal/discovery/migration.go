@@ -0,0 +1,272 @@
+package discovery
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// migrationTVLRatio is the liquidity ratio below which an ExpectedPool is
+// treated as migrated: its TVL is under 10% of an equivalent pool's.
+const migrationTVLRatio = 0.10
+
+// PoolMigration records that a BaseEndpoint's ExpectedPool appears to have
+// been replaced by an equivalent pool (same type and token set), e.g. after a
+// reCLAMM redeploy.
+type PoolMigration struct {
+	BaseName    string
+	Network     string
+	OldPool     string
+	NewPool     string
+	NewPoolName string
+	OldTVLUSD   float64
+	NewTVLUSD   float64
+	Reason      string // why OldPool is considered deprecated
+	DetectedAt  time.Time
+	Applied     bool // NewPool was written to the BaseEndpoints store
+}
+
+var (
+	migrationsMu sync.RWMutex
+	migrations   = map[string]PoolMigration{} // keyed by BaseName
+)
+
+// GetPoolMigrations returns the current migration suggestions, sorted by
+// BaseName.
+func GetPoolMigrations() []PoolMigration {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	out := make([]PoolMigration, 0, len(migrations))
+	for _, m := range migrations {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].BaseName < out[j].BaseName })
+	return out
+}
+
+// migrationPoolFields is the field set shared by the two migration queries;
+// it decodes into rawPool.
+const migrationPoolFields = `
+    address
+    name
+    type
+    dynamicData { isPaused isInRecoveryMode totalLiquidity }
+    poolTokens { address }`
+
+const migrationPoolQuery = `query Pool($id: String!, $chain: GqlChain!) {
+  poolGetPool(id: $id, chain: $chain) {` + migrationPoolFields + `
+  }
+}`
+
+const migrationCandidatesQuery = `query Candidates($chain: GqlChain!, $tokens: [String!]!, $types: [GqlPoolType!]!) {
+  poolGetPools(
+    where: {chainIn: [$chain], protocolVersionIn: [3], tokensIn: $tokens, poolTypeIn: $types}
+    orderBy: totalLiquidity
+    orderDirection: desc
+    first: 50
+  ) {` + migrationPoolFields + `
+  }
+}`
+
+// fetchMigrationInputsFn is swapped in tests.
+var fetchMigrationInputsFn = fetchMigrationInputs
+
+// fetchMigrationInputs returns the current state of pool and the active V3
+// pools of the same type that share its tokens.
+func fetchMigrationInputs(network, pool string) (rawPool, []rawPool, error) {
+	chainEnum := config.BalancerAPIChain(network)
+	if chainEnum == "" {
+		return rawPool{}, nil, fmt.Errorf("network %s unsupported by Balancer API", network)
+	}
+
+	var current struct {
+		PoolGetPool *rawPool `json:"poolGetPool"`
+	}
+	vars := map[string]interface{}{"id": config.NormalizeAddress(pool), "chain": chainEnum}
+	if err := postGraphQL(httpClient, migrationPoolQuery, vars, &current); err != nil {
+		return rawPool{}, nil, fmt.Errorf("pool: %w", err)
+	}
+	if current.PoolGetPool == nil {
+		return rawPool{}, nil, fmt.Errorf("pool %s not found", pool)
+	}
+	old := *current.PoolGetPool
+
+	tokens := make([]string, len(old.PoolTokens))
+	for i, t := range old.PoolTokens {
+		tokens[i] = config.NormalizeAddress(t.Address)
+	}
+	var candidates struct {
+		PoolGetPools []rawPool `json:"poolGetPools"`
+	}
+	vars = map[string]interface{}{"chain": chainEnum, "tokens": tokens, "types": []string{old.Type}}
+	if err := postGraphQL(httpClient, migrationCandidatesQuery, vars, &candidates); err != nil {
+		return rawPool{}, nil, fmt.Errorf("candidates: %w", err)
+	}
+	return old, candidates.PoolGetPools, nil
+}
+
+// findMigration picks the replacement for old among candidates: the
+// highest-TVL active pool with the same type and exactly the same token set.
+// It reports a migration only when old is paused, in recovery mode, or holds
+// less than migrationTVLRatio of the replacement's liquidity.
+func findMigration(old rawPool, candidates []rawPool) (rawPool, string, bool) {
+	oldTokens := tokenSet(old)
+	var best rawPool
+	bestTVL := 0.0
+	for _, c := range candidates {
+		if config.SameAddress(c.Address, old.Address) || c.Type != old.Type {
+			continue
+		}
+		if c.DynamicData.IsPaused || c.DynamicData.IsInRecoveryMode {
+			continue
+		}
+		if tokenSet(c) != oldTokens {
+			continue
+		}
+		if tvl := parseTVL(c); tvl > bestTVL {
+			best, bestTVL = c, tvl
+		}
+	}
+	if bestTVL < minTotalLiquidityUSD {
+		return rawPool{}, "", false
+	}
+
+	oldTVL := parseTVL(old)
+	switch {
+	case old.DynamicData.IsPaused:
+		return best, "pool is paused", true
+	case old.DynamicData.IsInRecoveryMode:
+		return best, "pool is in recovery mode", true
+	case oldTVL < bestTVL*migrationTVLRatio:
+		return best, fmt.Sprintf("liquidity moved: $%.0f vs $%.0f in the new pool", oldTVL, bestTVL), true
+	}
+	return rawPool{}, "", false
+}
+
+// tokenSet is a canonical key for a pool's registered tokens.
+func tokenSet(p rawPool) string {
+	addrs := make([]string, len(p.PoolTokens))
+	for i, t := range p.PoolTokens {
+		addrs[i] = config.NormalizeAddress(t.Address)
+	}
+	sort.Strings(addrs)
+	return strings.Join(addrs, ",")
+}
+
+func parseTVL(p rawPool) float64 {
+	tvl, _ := strconv.ParseFloat(p.DynamicData.TotalLiquidity, 64)
+	return tvl
+}
+
+// checkPoolMigrations looks for a replacement pool for every BaseEndpoint's
+// current ExpectedPool. New detections are logged and emailed; with
+// POOL_MIGRATION_AUTO_APPLY set the new address is also written to the
+// BaseEndpoints store (config.BaseEndpoints itself is not modified, so a
+// restart reverts to the configured pool until the config is updated).
+// Discovered rows need no handling: discovery re-selects pools every run.
+func checkPoolMigrations() {
+	autoApply := config.GetPoolMigrationAutoApply()
+	for _, base := range config.BaseEndpoints {
+		if base.ExpectedPool == "" {
+			continue
+		}
+		pool := currentExpectedPool(base.Name, base.ExpectedPool)
+
+		old, candidates, err := fetchMigrationInputsFn(base.Network, pool)
+		if err != nil {
+			logs.Printf("%s[POOL MIGRATION]%s %s: %v\n", config.ColorYellow, config.ColorReset, base.Name, err)
+			continue
+		}
+		next, reason, ok := findMigration(old, candidates)
+		if !ok {
+			clearMigration(base.Name, pool)
+			continue
+		}
+
+		m := PoolMigration{
+			BaseName:    base.Name,
+			Network:     base.Network,
+			OldPool:     pool,
+			NewPool:     next.Address,
+			NewPoolName: next.Name,
+			OldTVLUSD:   parseTVL(old),
+			NewTVLUSD:   parseTVL(next),
+			Reason:      reason,
+			DetectedAt:  time.Now(),
+		}
+		if autoApply {
+			applyMigration(m)
+			m.Applied = true
+		}
+		if recordMigration(m) {
+			msg := fmt.Sprintf("Pool migration detected for %s on %s: %s (%s) looks replaced by %s (%s)",
+				m.BaseName, config.NetworkName(m.Network), m.OldPool, m.Reason, m.NewPool, m.NewPoolName)
+			if m.Applied {
+				msg += "; applied to the running monitor, update config.BaseEndpoints to keep it"
+			} else {
+				msg += "; update ExpectedPool in config.BaseEndpoints or set POOL_MIGRATION_AUTO_APPLY"
+			}
+			logs.Printf("%s[POOL MIGRATION]%s %s\n", config.ColorYellow, config.ColorReset, msg)
+			notify.Send(notify.SeverityWarning, msg)
+		}
+	}
+}
+
+// currentExpectedPool returns the ExpectedPool the BaseEndpoints store is
+// checking for baseName, which differs from config after an applied
+// migration.
+func currentExpectedPool(baseName, configured string) string {
+	for _, e := range collector.GetEndpointsCopy() {
+		if e.BaseName == baseName && e.ExpectedPool != "" {
+			return e.ExpectedPool
+		}
+	}
+	return configured
+}
+
+// applyMigration points every solver row of the migrated base endpoint at the
+// new pool. The old pool's check history stays keyed by Endpoint.Name.
+func applyMigration(m PoolMigration) {
+	n := collector.UpdateEndpointsByBaseName(m.BaseName, func(e *collector.Endpoint) {
+		e.ExpectedPool = m.NewPool
+	})
+	logs.Printf("%s[POOL MIGRATION]%s %s: ExpectedPool %s -> %s on %d rows\n",
+		config.ColorGreen, config.ColorReset, m.BaseName, m.OldPool, m.NewPool, n)
+}
+
+// recordMigration stores m and reports whether it is new (not the same
+// old -> new suggestion as last run).
+func recordMigration(m PoolMigration) bool {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	prev, ok := migrations[m.BaseName]
+	if ok && config.SameAddress(prev.NewPool, m.NewPool) {
+		m.Applied = m.Applied || prev.Applied
+		m.DetectedAt = prev.DetectedAt
+		migrations[m.BaseName] = m
+		return false
+	}
+	migrations[m.BaseName] = m
+	return true
+}
+
+// clearMigration drops a stale suggestion once the checked pool is healthy,
+// keeping applied migrations (whose NewPool is now the checked pool) visible.
+func clearMigration(baseName, pool string) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	if m, ok := migrations[baseName]; ok && !(m.Applied && config.SameAddress(m.NewPool, pool)) {
+		delete(migrations, baseName)
+	}
+}
@@ -0,0 +1,121 @@
+package discovery
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func migrationPool(addr, typ, tvl string, tokens ...string) rawPool {
+	p := rawPool{Address: addr, Type: typ}
+	p.DynamicData.TotalLiquidity = tvl
+	for _, t := range tokens {
+		p.PoolTokens = append(p.PoolTokens, rawPoolToken{Address: t})
+	}
+	return p
+}
+
+func TestFindMigration(t *testing.T) {
+	old := migrationPool("0xold", "RECLAMM", "5000", "0xA", "0xB")
+
+	cases := []struct {
+		name       string
+		old        rawPool
+		candidates []rawPool
+		want       string
+	}{
+		{
+			name: "liquidity moved to redeploy",
+			old:  old,
+			candidates: []rawPool{
+				old,
+				migrationPool("0xnew", "RECLAMM", "900000", "0xb", "0xa"),
+				migrationPool("0xsmaller", "RECLAMM", "200000", "0xa", "0xb"),
+			},
+			want: "0xnew",
+		},
+		{
+			name:       "different token set ignored",
+			old:        old,
+			candidates: []rawPool{migrationPool("0xnew", "RECLAMM", "900000", "0xa", "0xb", "0xc")},
+		},
+		{
+			name:       "different type ignored",
+			old:        old,
+			candidates: []rawPool{migrationPool("0xnew", "STABLE", "900000", "0xa", "0xb")},
+		},
+		{
+			name:       "old pool still holds enough liquidity",
+			old:        migrationPool("0xold", "RECLAMM", "500000", "0xa", "0xb"),
+			candidates: []rawPool{migrationPool("0xnew", "RECLAMM", "900000", "0xa", "0xb")},
+		},
+		{
+			name: "paused old pool migrates regardless of TVL",
+			old: func() rawPool {
+				p := migrationPool("0xold", "RECLAMM", "500000", "0xa", "0xb")
+				p.DynamicData.IsPaused = true
+				return p
+			}(),
+			candidates: []rawPool{migrationPool("0xnew", "RECLAMM", "400000", "0xa", "0xb")},
+			want:       "0xnew",
+		},
+		{
+			name:       "replacement below liquidity floor ignored",
+			old:        migrationPool("0xold", "RECLAMM", "10", "0xa", "0xb"),
+			candidates: []rawPool{migrationPool("0xnew", "RECLAMM", "5000", "0xa", "0xb")},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, reason, ok := findMigration(tc.old, tc.candidates)
+			if tc.want == "" {
+				if ok {
+					t.Fatalf("unexpected migration to %s (%s)", got.Address, reason)
+				}
+				return
+			}
+			if !ok || got.Address != tc.want || reason == "" {
+				t.Fatalf("findMigration = %q, %q, %v; want %q", got.Address, reason, ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyMigrationAndRecord(t *testing.T) {
+	collector.SetEndpoints([]collector.Endpoint{
+		{Name: "X-KyberSwap", BaseName: "X", ExpectedPool: "0xold"},
+		{Name: "X-Odos", BaseName: "X", ExpectedPool: "0xold"},
+		{Name: "Y-Odos", BaseName: "Y", ExpectedPool: "0xother"},
+	})
+	t.Cleanup(func() {
+		collector.SetEndpoints(nil)
+		migrationsMu.Lock()
+		migrations = map[string]PoolMigration{}
+		migrationsMu.Unlock()
+	})
+
+	m := PoolMigration{BaseName: "X", OldPool: "0xold", NewPool: "0xnew", Applied: true}
+	applyMigration(m)
+	if got := currentExpectedPool("X", "0xold"); got != "0xnew" {
+		t.Fatalf("currentExpectedPool = %s, want 0xnew", got)
+	}
+	if got := currentExpectedPool("Y", "0xother"); got != "0xother" {
+		t.Fatalf("unrelated base endpoint changed: %s", got)
+	}
+
+	if !recordMigration(m) {
+		t.Fatal("first detection should be new")
+	}
+	if recordMigration(m) {
+		t.Fatal("repeat detection should not be new")
+	}
+	// Next run checks 0xnew and finds it healthy: the applied migration stays listed.
+	clearMigration("X", "0xnew")
+	if len(GetPoolMigrations()) != 1 {
+		t.Fatal("applied migration should remain listed")
+	}
+	clearMigration("X", "0xold")
+	if len(GetPoolMigrations()) != 0 {
+		t.Fatal("stale migration should be cleared")
+	}
+}
@@ -0,0 +1,103 @@
+package discovery
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+// wrappedNative maps a chain ID to its wrapped gas token, whose Balancer API
+// price stands in for the native token's. Networks missing here have no
+// native price.
+var wrappedNative = map[string]string{
+	"1":     "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", // WETH
+	"10":    "0x4200000000000000000000000000000000000006", // WETH
+	"100":   "0xe91d153e0b41518a2ce8dd3d7944fa863463a97d", // WXDAI
+	"999":   "0x5555555555555555555555555555555555555555", // WHYPE
+	"8453":  "0x4200000000000000000000000000000000000006", // WETH
+	"42161": "0x82af49447d8a07e3bd95bd0d56f35241523fbab1", // WETH
+	"43114": "0xb31f66aa3c1e785363f0875a1b74e27b85fd66c7", // WAVAX
+}
+
+const (
+	// nativePriceTTL is how long a native token price is served from cache;
+	// gas costs are estimates, so minutes-old prices do.
+	nativePriceTTL = 15 * time.Minute
+	// nativePriceFailureTTL is how long a failed lookup is remembered.
+	nativePriceFailureTTL = 5 * time.Minute
+)
+
+type nativePriceEntry struct {
+	price   float64
+	ok      bool
+	expires time.Time
+}
+
+var (
+	nativePriceMu    sync.Mutex
+	nativePriceCache = map[string]nativePriceEntry{}
+
+	// fetchTokenPriceFn is swapped in tests.
+	fetchTokenPriceFn = fetchTokenPrice
+)
+
+// NativePriceUSD returns the USD price of network's native gas token, from
+// the Balancer API's price of its wrapped token, querying on a cache miss.
+// ok is false for a network without a known wrapped token or when the
+// lookup fails; failures are cached briefly.
+func NativePriceUSD(network string) (float64, bool) {
+	token, known := wrappedNative[network]
+	if !known {
+		return 0, false
+	}
+	now := time.Now()
+	nativePriceMu.Lock()
+	entry, hit := nativePriceCache[network]
+	nativePriceMu.Unlock()
+	if hit && now.Before(entry.expires) {
+		return entry.price, entry.ok
+	}
+
+	price, err := fetchTokenPriceFn(network, token)
+	entry = nativePriceEntry{price: price, ok: true, expires: now.Add(nativePriceTTL)}
+	if err == nil && price <= 0 {
+		err = fmt.Errorf("no price")
+	}
+	if err != nil {
+		logs.Printf("%s[NATIVE PRICE]%s %s on %s: %v\n", config.ColorYellow, config.ColorReset, token, network, err)
+		entry = nativePriceEntry{expires: now.Add(nativePriceFailureTTL)}
+	}
+
+	nativePriceMu.Lock()
+	nativePriceCache[network] = entry
+	nativePriceMu.Unlock()
+	return entry.price, entry.ok
+}
+
+const tokenPriceQuery = `query Price($address: String!, $chain: GqlChain!) {
+  tokenGetCurrentPrice(address: $address, chain: $chain) { price }
+}`
+
+// fetchTokenPrice queries the Balancer API's current USD price of a token.
+func fetchTokenPrice(network, address string) (float64, error) {
+	chainEnum := config.BalancerAPIChain(network)
+	if chainEnum == "" {
+		return 0, fmt.Errorf("network %s unsupported by Balancer API", network)
+	}
+	var data struct {
+		TokenGetCurrentPrice *struct {
+			Price float64 `json:"price"`
+		} `json:"tokenGetCurrentPrice"`
+	}
+	vars := map[string]interface{}{"address": address, "chain": chainEnum}
+	if err := postGraphQL(poolMetadataClient, tokenPriceQuery, vars, &data); err != nil {
+		return 0, err
+	}
+	if data.TokenGetCurrentPrice == nil {
+		return 0, fmt.Errorf("token not priced")
+	}
+	return data.TokenGetCurrentPrice.Price, nil
+}
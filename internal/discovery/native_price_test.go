@@ -0,0 +1,41 @@
+package discovery
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNativePriceUSDCachesHitsAndFailures(t *testing.T) {
+	prev := fetchTokenPriceFn
+	t.Cleanup(func() { fetchTokenPriceFn = prev })
+	nativePriceMu.Lock()
+	nativePriceCache = map[string]nativePriceEntry{}
+	nativePriceMu.Unlock()
+
+	calls := map[string]int{}
+	fetchTokenPriceFn = func(network, address string) (float64, error) {
+		calls[network]++
+		if network == "42161" {
+			return 0, errors.New("token not priced")
+		}
+		if address != wrappedNative[network] {
+			t.Errorf("fetch %s on %s, want the wrapped native token", address, network)
+		}
+		return 2500, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if p, ok := NativePriceUSD("1"); !ok || p != 2500 {
+			t.Fatalf("NativePriceUSD(1) = %v, %v", p, ok)
+		}
+		if _, ok := NativePriceUSD("42161"); ok {
+			t.Fatal("expected failed lookup on 42161")
+		}
+	}
+	if _, ok := NativePriceUSD("31337"); ok {
+		t.Fatal("expected no price for a network without a wrapped native token")
+	}
+	if calls["1"] != 1 || calls["42161"] != 1 || calls["31337"] != 0 {
+		t.Errorf("fetch calls = %v, want one per known network", calls)
+	}
+}
@@ -0,0 +1,285 @@
+package discovery
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+// PoolMetadata is the display metadata for one pool, as reported by the
+// Balancer API.
+type PoolMetadata struct {
+	Address string
+	Network string
+	Name    string   // e.g. "Aave GHO/USDC Boosted StableSurge"
+	Type    string   // raw enum string, e.g. "STABLE"
+	Version int      // protocol version, e.g. 3
+	Tokens  []string // registered token symbols, in pool order
+	// WrappedBy maps an ERC4626 underlying token address (lower-case) to the
+	// registered wrapped token (e.g. USDC → waUSDC) for boosted pools.
+	WrappedBy map[string]string
+	TVLUSD    float64
+	// PricesUSD maps token addresses (lower-case, registered and underlying)
+	// to a USD price implied by the pool balances; an underlying token is
+	// priced as its wrapped token, as in discovery's swap sizing.
+	PricesUSD map[string]float64
+}
+
+// TokenSymbols joins the registered token symbols, e.g. "waGHO/waUSDC".
+func (m PoolMetadata) TokenSymbols() string {
+	return strings.Join(m.Tokens, "/")
+}
+
+const (
+	// poolMetadataTTL is how long a successful lookup is served from cache.
+	// Name / type / tokens are effectively immutable; the TTL only picks up
+	// renames in the API.
+	poolMetadataTTL = 24 * time.Hour
+	// poolMetadataFailureTTL is how long a failed lookup is remembered so a
+	// dashboard refresh doesn't re-query the API for every unknown pool.
+	poolMetadataFailureTTL = 15 * time.Minute
+)
+
+type poolMetadataEntry struct {
+	meta    PoolMetadata
+	ok      bool
+	expires time.Time
+}
+
+var (
+	poolMetadataMu    sync.Mutex
+	poolMetadataCache = map[string]poolMetadataEntry{}
+	// poolMetadataFetches shares one API call between every caller missing
+	// the same pool.
+	poolMetadataFetches singleflight.Group
+
+	// fetchPoolMetadataFn is swapped in tests.
+	fetchPoolMetadataFn = fetchPoolMetadata
+)
+
+func poolMetadataKey(network, address string) string {
+	return network + ":" + config.NormalizeAddress(address)
+}
+
+// PoolMetadataFor returns metadata for the pool at address on network,
+// querying the Balancer API on a cache miss. Failures are cached briefly and
+// reported as ok == false; callers fall back to showing the address.
+func PoolMetadataFor(network, address string) (PoolMetadata, bool) {
+	if address == "" {
+		return PoolMetadata{}, false
+	}
+	if entry, hit := cachedPoolMetadata(network, address); hit && time.Now().Before(entry.expires) {
+		return entry.meta, entry.ok
+	}
+	entry := loadPoolMetadata(network, address)
+	return entry.meta, entry.ok
+}
+
+// CachedPoolMetadata is PoolMetadataFor for render paths: it never waits on
+// the Balancer API. A miss reports ok == false and an expired entry is still
+// served, while the lookup runs in the background for the next render.
+func CachedPoolMetadata(network, address string) (PoolMetadata, bool) {
+	if address == "" {
+		return PoolMetadata{}, false
+	}
+	entry, hit := cachedPoolMetadata(network, address)
+	if !hit || !time.Now().Before(entry.expires) {
+		go loadPoolMetadata(network, address)
+	}
+	return entry.meta, entry.ok
+}
+
+func cachedPoolMetadata(network, address string) (poolMetadataEntry, bool) {
+	poolMetadataMu.Lock()
+	defer poolMetadataMu.Unlock()
+	entry, hit := poolMetadataCache[poolMetadataKey(network, address)]
+	return entry, hit
+}
+
+// loadPoolMetadata queries the API and caches the result, joining a lookup
+// of the same pool that is already in flight and skipping one that has just
+// finished.
+func loadPoolMetadata(network, address string) poolMetadataEntry {
+	key := poolMetadataKey(network, address)
+	v, _, _ := poolMetadataFetches.Do(key, func() (interface{}, error) {
+		now := time.Now()
+		if entry, hit := cachedPoolMetadata(network, address); hit && now.Before(entry.expires) {
+			return entry, nil
+		}
+		meta, err := fetchPoolMetadataFn(network, address)
+		entry := poolMetadataEntry{meta: meta, ok: err == nil, expires: now.Add(poolMetadataTTL)}
+		if err != nil {
+			logs.Printf("%s[POOL METADATA]%s %s on %s: %v\n",
+				config.ColorYellow, config.ColorReset, address, network, err)
+			entry = poolMetadataEntry{expires: now.Add(poolMetadataFailureTTL)}
+		}
+
+		poolMetadataMu.Lock()
+		poolMetadataCache[key] = entry
+		poolMetadataMu.Unlock()
+		return entry, nil
+	})
+	return v.(poolMetadataEntry)
+}
+
+// WarmPoolMetadata fills the cache for the given pools, skipping entries that
+// are already fresh. Run in the background at startup so the first dashboard
+// render doesn't wait on the API.
+func WarmPoolMetadata(pools [][2]string) {
+	for _, p := range pools {
+		PoolMetadataFor(p[0], p[1])
+	}
+}
+
+// WrappedTokensFor returns the pool's underlying → wrapped token map (see
+// PoolMetadata.WrappedBy), from the metadata cache. ok is false when the pool
+// is unknown; a non-boosted pool returns an empty map.
+func WrappedTokensFor(network, pool string) (map[string]string, bool) {
+	meta, ok := PoolMetadataFor(network, pool)
+	return meta.WrappedBy, ok
+}
+
+// seedPoolMetadata caches metadata for pools discovery already fetched, so
+// discovered rows never trigger a separate lookup.
+func seedPoolMetadata(network string, pools []Pool) {
+	expires := time.Now().Add(poolMetadataTTL)
+
+	poolMetadataMu.Lock()
+	defer poolMetadataMu.Unlock()
+	for _, p := range pools {
+		tokens := make([]string, len(p.Tokens))
+		wrappedBy := map[string]string{}
+		prices := map[string]float64{}
+		for i, t := range p.Tokens {
+			tokens[i] = t.Symbol
+			price := impliedPriceUSD(t.BalanceUSD, t.Balance)
+			addPrice(prices, t.Address, price)
+			if t.Underlying != nil {
+				wrappedBy[config.NormalizeAddress(t.Underlying.Address)] = t.Address
+				addPrice(prices, t.Underlying.Address, price)
+			}
+		}
+		poolMetadataCache[poolMetadataKey(network, p.Address)] = poolMetadataEntry{
+			meta: PoolMetadata{
+				Address:   p.Address,
+				Network:   network,
+				Name:      p.Name,
+				Type:      p.Type,
+				Version:   3,
+				Tokens:    tokens,
+				WrappedBy: wrappedBy,
+				TVLUSD:    p.TotalLiquidityUSD,
+				PricesUSD: prices,
+			},
+			ok:      true,
+			expires: expires,
+		}
+	}
+}
+
+const poolQuery = `query Pool($id: String!, $chain: GqlChain!) {
+  poolGetPool(id: $id, chain: $chain) {
+    address
+    name
+    type
+    protocolVersion
+    dynamicData { totalLiquidity }
+    poolTokens { address symbol balance balanceUSD underlyingToken { address } }
+  }
+}`
+
+// rawPoolMetadata is the poolGetPool response shape for poolQuery.
+type rawPoolMetadata struct {
+	Address         string `json:"address"`
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	ProtocolVersion int    `json:"protocolVersion"`
+	DynamicData     struct {
+		TotalLiquidity string `json:"totalLiquidity"`
+	} `json:"dynamicData"`
+	PoolTokens []struct {
+		Address         string `json:"address"`
+		Symbol          string `json:"symbol"`
+		Balance         string `json:"balance"`
+		BalanceUSD      string `json:"balanceUSD"`
+		UnderlyingToken *struct {
+			Address string `json:"address"`
+		} `json:"underlyingToken"`
+	} `json:"poolTokens"`
+}
+
+// poolMetadataClient has a short timeout: PoolMetadataFor callers such as
+// the import command wait on it.
+var poolMetadataClient = &http.Client{Timeout: 10 * time.Second}
+
+// fetchPoolMetadata queries poolGetPool for a single pool.
+func fetchPoolMetadata(network, address string) (PoolMetadata, error) {
+	chainEnum := config.BalancerAPIChain(network)
+	if chainEnum == "" {
+		return PoolMetadata{}, fmt.Errorf("network %s unsupported by Balancer API", network)
+	}
+
+	var data struct {
+		PoolGetPool *rawPoolMetadata `json:"poolGetPool"`
+	}
+	vars := map[string]interface{}{"id": config.NormalizeAddress(address), "chain": chainEnum}
+	if err := postGraphQL(poolMetadataClient, poolQuery, vars, &data); err != nil {
+		return PoolMetadata{}, err
+	}
+	return poolMetadataFromRaw(network, data.PoolGetPool)
+}
+
+func poolMetadataFromRaw(network string, p *rawPoolMetadata) (PoolMetadata, error) {
+	if p == nil {
+		return PoolMetadata{}, fmt.Errorf("pool not found")
+	}
+	tokens := make([]string, len(p.PoolTokens))
+	wrappedBy := map[string]string{}
+	prices := map[string]float64{}
+	for i, t := range p.PoolTokens {
+		tokens[i] = t.Symbol
+		balanceUSD, _ := strconv.ParseFloat(t.BalanceUSD, 64)
+		price := impliedPriceUSD(balanceUSD, t.Balance)
+		addPrice(prices, t.Address, price)
+		if t.UnderlyingToken != nil {
+			wrappedBy[config.NormalizeAddress(t.UnderlyingToken.Address)] = t.Address
+			addPrice(prices, t.UnderlyingToken.Address, price)
+		}
+	}
+	tvl, _ := strconv.ParseFloat(p.DynamicData.TotalLiquidity, 64)
+	return PoolMetadata{
+		Address:   p.Address,
+		Network:   network,
+		Name:      p.Name,
+		Type:      p.Type,
+		Version:   p.ProtocolVersion,
+		Tokens:    tokens,
+		WrappedBy: wrappedBy,
+		TVLUSD:    tvl,
+		PricesUSD: prices,
+	}, nil
+}
+
+// impliedPriceUSD is balanceUSD / balance, or 0 when either is missing.
+func impliedPriceUSD(balanceUSD float64, balance string) float64 {
+	b, err := strconv.ParseFloat(balance, 64)
+	if err != nil || b <= 0 || balanceUSD <= 0 {
+		return 0
+	}
+	return balanceUSD / b
+}
+
+// addPrice records a positive price under the lower-case address.
+func addPrice(prices map[string]float64, address string, price float64) {
+	if price > 0 && address != "" {
+		prices[config.NormalizeAddress(address)] = price
+	}
+}
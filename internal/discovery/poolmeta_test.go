@@ -0,0 +1,156 @@
+package discovery
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func withPoolMetadataFetcher(t *testing.T, fn func(network, address string) (PoolMetadata, error)) {
+	t.Helper()
+	prev := fetchPoolMetadataFn
+	fetchPoolMetadataFn = fn
+	poolMetadataMu.Lock()
+	poolMetadataCache = map[string]poolMetadataEntry{}
+	poolMetadataMu.Unlock()
+	t.Cleanup(func() { fetchPoolMetadataFn = prev })
+}
+
+func TestPoolMetadataForCachesHitsAndFailures(t *testing.T) {
+	calls := 0
+	withPoolMetadataFetcher(t, func(network, address string) (PoolMetadata, error) {
+		calls++
+		if address == "0xbad" {
+			return PoolMetadata{}, errors.New("pool not found")
+		}
+		return PoolMetadata{Address: address, Network: network, Name: "Aave GHO/USDC", Type: "STABLE", Version: 3}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		meta, ok := PoolMetadataFor("1", "0xABC")
+		if !ok || meta.Name != "Aave GHO/USDC" {
+			t.Fatalf("PoolMetadataFor = %+v, %v", meta, ok)
+		}
+	}
+	// Addresses are matched case-insensitively.
+	if _, ok := PoolMetadataFor("1", "0xabc"); !ok {
+		t.Fatal("expected cached hit for lower-cased address")
+	}
+	if calls != 1 {
+		t.Fatalf("fetch calls = %d, want 1", calls)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, ok := PoolMetadataFor("1", "0xbad"); ok {
+			t.Fatal("expected failed lookup")
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("fetch calls = %d, want failure cached after 2", calls)
+	}
+}
+
+func TestCachedPoolMetadataNeverWaitsOnFetch(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	withPoolMetadataFetcher(t, func(network, address string) (PoolMetadata, error) {
+		calls.Add(1)
+		<-release
+		return PoolMetadata{Address: address, Network: network, Name: "Aave GHO/USDC"}, nil
+	})
+
+	returned := make(chan struct{})
+	go func() {
+		defer close(returned)
+		for i := 0; i < 3; i++ {
+			if _, ok := CachedPoolMetadata("1", "0xABC"); ok {
+				t.Error("CachedPoolMetadata reported a hit before the fetch finished")
+			}
+		}
+	}()
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("CachedPoolMetadata waited on the fetch")
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if meta, ok := CachedPoolMetadata("1", "0xABC"); ok {
+			if meta.Name != "Aave GHO/USDC" {
+				t.Fatalf("CachedPoolMetadata = %+v", meta)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the background fetch never filled the cache")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("fetch calls = %d, want one shared by every miss", n)
+	}
+}
+
+func TestSeedPoolMetadataAvoidsFetch(t *testing.T) {
+	withPoolMetadataFetcher(t, func(network, address string) (PoolMetadata, error) {
+		t.Fatalf("unexpected fetch for %s", address)
+		return PoolMetadata{}, nil
+	})
+	seedPoolMetadata("8453", []Pool{{
+		Address: "0xPool",
+		Name:    "Boosted Stable",
+		Type:    "STABLE",
+		Tokens:  []PoolToken{{Symbol: "waGHO"}, {Symbol: "waUSDC"}},
+	}})
+	meta, ok := PoolMetadataFor("8453", "0xpool")
+	if !ok || meta.TokenSymbols() != "waGHO/waUSDC" || meta.Version != 3 {
+		t.Fatalf("PoolMetadataFor = %+v, %v", meta, ok)
+	}
+}
+
+// decodePoolMetadata mirrors fetchPoolMetadata's decoding of a response body.
+func decodePoolMetadata(network string, body []byte) (PoolMetadata, error) {
+	var data struct {
+		PoolGetPool *rawPoolMetadata `json:"poolGetPool"`
+	}
+	if err := decodeGraphQL(body, &data); err != nil {
+		return PoolMetadata{}, err
+	}
+	return poolMetadataFromRaw(network, data.PoolGetPool)
+}
+
+func TestDecodePoolMetadata(t *testing.T) {
+	body := []byte(`{"data":{"poolGetPool":{"address":"0x1","name":"Aave GHO/USDC Boosted StableSurge","type":"STABLE","protocolVersion":3,"poolTokens":[{"symbol":"waGHO"},{"symbol":"waUSDC"}]}}}`)
+	meta, err := decodePoolMetadata("1", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Name != "Aave GHO/USDC Boosted StableSurge" || meta.Version != 3 || meta.TokenSymbols() != "waGHO/waUSDC" {
+		t.Fatalf("meta = %+v", meta)
+	}
+
+	if len(meta.WrappedBy) != 0 {
+		t.Fatalf("WrappedBy = %v for a response without underlying tokens", meta.WrappedBy)
+	}
+
+	boosted := []byte(`{"data":{"poolGetPool":{"address":"0x2","dynamicData":{"totalLiquidity":"3000000.5"},"poolTokens":[` +
+		`{"address":"0xWaUSDC","symbol":"waUSDC","balance":"1000000","balanceUSD":"1100000","underlyingToken":{"address":"0xUSDC"}},` +
+		`{"address":"0xWstETH","symbol":"wstETH","balance":"500","balanceUSD":"1900000","underlyingToken":null}]}}}`)
+	meta, err = decodePoolMetadata("1", boosted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.WrappedBy) != 1 || meta.WrappedBy["0xusdc"] != "0xWaUSDC" {
+		t.Fatalf("WrappedBy = %v", meta.WrappedBy)
+	}
+	if meta.TVLUSD != 3000000.5 || meta.PricesUSD["0xwausdc"] != 1.1 || meta.PricesUSD["0xusdc"] != 1.1 || meta.PricesUSD["0xwsteth"] != 3800 {
+		t.Fatalf("TVLUSD = %v, PricesUSD = %v", meta.TVLUSD, meta.PricesUSD)
+	}
+
+	if _, err := decodePoolMetadata("1", []byte(`{"data":{"poolGetPool":null},"errors":[{"message":"Pool with id 0x1 does not exist"}]}`)); err == nil {
+		t.Fatal("expected error for graphql errors")
+	}
+}
@@ -0,0 +1,104 @@
+package discovery
+
+import (
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+// surgeTriggerMargin is how far past a StableSurge hook's
+// surgeThresholdPercentage a hook-triggering row pushes the pool's imbalance,
+// so USD-balance drift between discovery and the check doesn't leave the swap
+// just short of the surge fee.
+const surgeTriggerMargin = 0.05
+
+// SurgeRows returns one hook-triggering row per StableSurge pool with a
+// registered row in rows: the opposite direction, swapping in the pool's
+// overrepresented token, sized so the post-swap imbalance reaches
+// surgeThresholdPercentage + surgeTriggerMargin. Quotes for these rows include
+// the surge fee, which providers must price like the on-chain Router query
+// does. Pools that cannot be pushed past the threshold are skipped.
+func SurgeRows(pools []Pool, rows []TestRow) []TestRow {
+	byKey := map[string]Pool{}
+	for _, p := range pools {
+		byKey[collector.PoolKey(p.Network, p.Address)] = p
+	}
+	var out []TestRow
+	for _, r := range rows {
+		if r.Variant != "" || r.HookType != stableSurgeHookType {
+			continue
+		}
+		p, ok := byKey[collector.PoolKey(r.Network, r.PoolAddress)]
+		if !ok || p.SurgeThreshold <= 0 {
+			continue
+		}
+		if row := buildSurgeRow(p, r); row != nil {
+			out = append(out, *row)
+		}
+	}
+	return out
+}
+
+// buildSurgeRow reverses a registered row and sizes it to trigger the surge
+// fee. Returns nil when either token is not in the pool or no swap the
+// pool's balances allow reaches the target imbalance.
+func buildSurgeRow(p Pool, registered TestRow) *TestRow {
+	in, out := tokenIndex(p, registered.TokenOut), tokenIndex(p, registered.TokenIn)
+	if in < 0 || out < 0 {
+		return nil
+	}
+	tradeUSD := surgeTradeUSD(p.Tokens, in, out, p.SurgeThreshold+surgeTriggerMargin)
+	if tradeUSD <= 0 {
+		return nil
+	}
+	tokenIn := p.Tokens[in]
+	swap := computeSwapAmountRaw(tokenIn.BalanceUSD, tokenIn.Balance, tokenIn.Decimals, tradeUSD/tokenIn.BalanceUSD*100)
+	if swap == "" {
+		return nil
+	}
+	row := registered
+	row.TokenIn, row.TokenOut = registered.TokenOut, registered.TokenIn
+	row.TokenInSymbol, row.TokenOutSymbol = registered.TokenOutSymbol, registered.TokenInSymbol
+	row.TokenInDec, row.TokenOutDec = registered.TokenOutDec, registered.TokenInDec
+	row.SwapAmountRaw = swap
+	row.Variant = "surge"
+	row.Boosted = false
+	return &row
+}
+
+// surgeTradeUSD returns the smallest USD amount of tokens[in] that, swapped
+// for tokens[out] at par, takes the pool's imbalance (stableSurgeImbalance)
+// to at least target, or 0 when even swapping out all but 1% of tokens[out]
+// falls short.
+func surgeTradeUSD(tokens []PoolToken, in, out int, target float64) float64 {
+	imbalanceAfter := func(usd float64) float64 {
+		after := append([]PoolToken(nil), tokens...)
+		after[in].BalanceUSD += usd
+		after[out].BalanceUSD -= usd
+		return stableSurgeImbalance(after)
+	}
+	hi := tokens[out].BalanceUSD * 0.99
+	if hi <= 0 || imbalanceAfter(hi) < target {
+		return 0
+	}
+	lo := 0.0
+	for i := 0; i < 64; i++ {
+		mid := (lo + hi) / 2
+		if imbalanceAfter(mid) >= target {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi
+}
+
+// tokenIndex returns the index of the pool token with the given address, or
+// -1.
+func tokenIndex(p Pool, address string) int {
+	for i, t := range p.Tokens {
+		if config.SameAddress(t.Address, address) {
+			return i
+		}
+	}
+	return -1
+}
@@ -3,10 +3,11 @@ package discovery
 import (
 	"math"
 	"strconv"
+	"strings"
 	"testing"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/collector"
+	"go-monitoring/monitoring/collector"
 )
 
 func TestStableSurgeImbalance(t *testing.T) {
@@ -345,6 +346,48 @@ func TestBuild_SkipsSurgingPool(t *testing.T) {
 	}
 }
 
+// TestSurgeRows checks the hook-triggering row reverses the calm pool's
+// registered row and is sized to push the imbalance to threshold + margin:
+// (5500+x - (4500-x)) / 10000 = 0.65 → x = 2750 USD of USDC.
+func TestSurgeRows(t *testing.T) {
+	calm := Pool{
+		Address:    "0xcalm00000000000000000000000000000000000000",
+		Network:    "1",
+		Type:       "STABLE",
+		HookType:   "STABLE_SURGE",
+		Categories: []string{CategoryUnique},
+		Tokens: []PoolToken{
+			{Address: "0xccc", Symbol: "USDC", Decimals: 6, Balance: "5500", BalanceUSD: 5500},
+			{Address: "0xddd", Symbol: "USDT", Decimals: 6, Balance: "4500", BalanceUSD: 4500},
+		},
+		SurgeThreshold: 0.6,
+		SurgeImbalance: 0.1,
+	}
+	rows, _ := Build([]Pool{calm}, 1, map[string]float64{"1": 5})
+	surge := SurgeRows([]Pool{calm}, rows)
+	if len(surge) != 1 {
+		t.Fatalf("expected 1 surge row, got %+v", surge)
+	}
+	r := surge[0]
+	if r.Variant != "surge" || r.TokenIn != "0xccc" || r.TokenOut != "0xddd" || r.TokenInSymbol != "USDC" {
+		t.Fatalf("surge row = %+v", r)
+	}
+	amount, _ := strconv.ParseFloat(r.SwapAmountRaw, 64)
+	if math.Abs(amount-2750e6) > 1e3 {
+		t.Fatalf("SwapAmountRaw = %s, want ~2750000000", r.SwapAmountRaw)
+	}
+	if got := formatDiscoveredBaseName(r); !strings.HasSuffix(got, "-USDC-USDT-0xcalm0000-surge") {
+		t.Fatalf("BaseName = %s", got)
+	}
+
+	// Rows of pools without the hook get no surge row.
+	calm.HookType, calm.SurgeThreshold = "", 0
+	rows, _ = Build([]Pool{calm}, 1, map[string]float64{"1": 5})
+	if got := SurgeRows([]Pool{calm}, rows); len(got) != 0 {
+		t.Fatalf("expected no surge rows, got %+v", got)
+	}
+}
+
 func formatDecimal(v float64) string {
 	return strconv.FormatFloat(v, 'f', -1, 64)
 }
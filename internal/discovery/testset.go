@@ -3,9 +3,9 @@ package discovery
 import (
 	"math/big"
 	"sort"
-	"strings"
 
-	"go-monitoring/internal/collector"
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
 )
 
 // TestRow is one item in the daily test set: a single direction of swap
@@ -26,7 +26,7 @@ type TestRow struct {
 	TokenInDec      int
 	TokenOutDec     int
 	SwapAmountRaw   string // raw on-chain units (post decimal conversion)
-	Variant         string // "" for the registered row, "underlying" for the boosted underlying row
+	Variant         string // "" for the registered row, "underlying" for the boosted underlying row, "surge" for the hook-triggering row
 	Boosted         bool   // true only on the registered-token row when the pool is boosted (two rows)
 }
 
@@ -139,14 +139,14 @@ func Build(pools []Pool, perGroup int, tradePercentByNetwork map[string]float64)
 }
 
 // networkPairCount returns the C(N,2) registered-pair frequency map for the
-// surviving pools of a single network. Key is "lower|higher" of lowercased
-// addresses, matching pairKeys in discovery.go.
+// surviving pools of a single network. Key is "lower|higher" of normalized
+// addresses (config.NormalizeAddress), matching pairKeys in discovery.go.
 func networkPairCount(pools []Pool) map[string]int {
 	counts := map[string]int{}
 	for _, p := range pools {
 		addrs := make([]string, 0, len(p.Tokens))
 		for _, t := range p.Tokens {
-			addrs = append(addrs, strings.ToLower(t.Address))
+			addrs = append(addrs, config.NormalizeAddress(t.Address))
 		}
 		for _, k := range pairKeys(addrs) {
 			counts[k]++
@@ -171,7 +171,7 @@ func canonicalUniquePair(p Pool, pairCount map[string]int) ([2]PoolToken, bool)
 	for i := 0; i < len(p.Tokens); i++ {
 		for j := i + 1; j < len(p.Tokens); j++ {
 			a, b := p.Tokens[i], p.Tokens[j]
-			lo, hi := strings.ToLower(a.Address), strings.ToLower(b.Address)
+			lo, hi := config.NormalizeAddress(a.Address), config.NormalizeAddress(b.Address)
 			if lo > hi {
 				lo, hi = hi, lo
 			}
@@ -335,9 +335,9 @@ func orderBySmallestUSDBalance(a, b PoolToken) (PoolToken, PoolToken) {
 	return orderByAddress(a, b)
 }
 
-// orderByAddress returns (a, b) sorted by lowercased address (lexicographic).
+// orderByAddress returns (a, b) sorted by normalized address (lexicographic).
 func orderByAddress(a, b PoolToken) (PoolToken, PoolToken) {
-	if strings.ToLower(a.Address) <= strings.ToLower(b.Address) {
+	if config.NormalizeAddress(a.Address) <= config.NormalizeAddress(b.Address) {
 		return a, b
 	}
 	return b, a
@@ -0,0 +1,27 @@
+// Package fsutil holds the file helpers the stores, the importer and the
+// notification spool share.
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// AtomicWriteFile replaces path with data through a temp file in the same
+// directory and a rename, so a crash mid-write never leaves a truncated
+// file behind. The file is readable by its owner only.
+func AtomicWriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,431 @@
+// Package i18n is the message catalog for the partner-facing pages
+// (/solver/{name} and /widget): status labels, page chrome and a one-line
+// summary per failure class, so a provider's integration team can read the
+// page in their language. Messages are templates with {name} placeholders,
+// as in the alert hints. English is the default and the fallback for any
+// message a language lacks; operator pages and alerts stay English.
+package i18n
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+// DefaultLang is the language pages render in when the request asks for
+// none the catalog has.
+const DefaultLang = "en"
+
+// Catalog maps a language tag (e.g. "en", "zh") to its messages by ID.
+type Catalog map[string]map[string]string
+
+// DefaultCatalog is the built-in catalog. Every ID the pages use is in "en";
+// other languages may leave messages out.
+var DefaultCatalog = Catalog{
+	"en": {
+		"language.name": "English",
+
+		"page.back":      "Back to monitor",
+		"page.generated": "Generated {time}",
+
+		"summary.endpoints":   "Endpoints",
+		"summary.up_now":      "Up now",
+		"summary.uptime_24h":  "Uptime 24h",
+		"summary.uptime_7d":   "Uptime 7d",
+		"summary.networks":    "Networks",
+		"summary.features":    "Features",
+		"summary.unsupported": "Not supported",
+
+		"feature.market_price":     "market price",
+		"feature.exact_out":        "exact out",
+		"feature.source_whitelist": "source whitelist",
+		"feature.gas":              "gas estimate",
+		"feature.route":            "route",
+
+		"endpoints.heading": "Endpoints",
+		"endpoints.none":    "No endpoints are configured for this solver (it may be disabled).",
+		"causes.heading":    "Common failure causes (7d)",
+		"causes.none":       "No failures recorded.",
+
+		"column.pair":          "Pair",
+		"column.network":       "Network",
+		"column.status":        "Status",
+		"column.uptime_24h":    "Uptime 24h",
+		"column.uptime_7d":     "Uptime 7d",
+		"column.last_checked":  "Last Checked",
+		"column.message":       "Message",
+		"column.last_response": "Last response",
+		"column.on_chain":      "On-chain query",
+		"column.notes":         "Notes",
+		"column.count":         "Count",
+		"column.last_seen":     "Last Seen",
+		"column.hint":          "Hint",
+
+		"status.up":             "up",
+		"status.down":           "down",
+		"status.error":          "error",
+		"status.panic":          "panic",
+		"status.info":           "info",
+		"status.unknown":        "unknown",
+		"status.unsupported":    "unsupported",
+		"status.not-applicable": "not applicable",
+		"status.disabled":       "disabled",
+		"status.down_for":       "for {duration}",
+		"status.since":          "{status} since {time}",
+
+		"failure.edge_error":            "The API's CDN or firewall answered with an error page",
+		"failure.rate_limited":          "Requests were rate limited",
+		"failure.missing_api_key":       "The monitor has no API key configured",
+		"failure.unauthorized":          "The request was rejected as unauthorized",
+		"failure.wrong_source":          "The route does not go through Balancer V3",
+		"failure.hook_quote_mismatch":   "The quote ignores the pool's hook fee",
+		"failure.unexpected_spender":    "The quote names an unknown allowance target",
+		"failure.price_impact":          "The route has a high price impact",
+		"failure.uncompetitive":         "Balancer V3 is routed but other sources win",
+		"failure.spread_widening":       "Other sources increasingly beat the Balancer V3 quote",
+		"failure.expected_pool_missing": "The expected pool is not in the route",
+		"failure.no_route":              "No route was returned",
+		"failure.network":               "The request did not complete",
+		"failure.server_error":          "The API returned a server error",
+		"failure.response_format":       "The response format is not the expected one",
+		"failure.build":                 "The request could not be built",
+		"failure.panic":                 "The monitor failed to process the response",
+		"failure.wip":                   "A known integration gap",
+
+		"widget.title":          "Balancer V3 aggregator health",
+		"widget.empty":          "No endpoints checked yet.",
+		"widget.updated":        "Updated {time}",
+		"widget.provider_title": "{name}: {up}/{total} up",
+	},
+	"es": {
+		"language.name": "Español",
+
+		"page.back":      "Volver al monitor",
+		"page.generated": "Generado {time}",
+
+		"summary.endpoints":   "Endpoints",
+		"summary.up_now":      "Activos ahora",
+		"summary.uptime_24h":  "Disponibilidad 24h",
+		"summary.uptime_7d":   "Disponibilidad 7d",
+		"summary.networks":    "Redes",
+		"summary.features":    "Funciones",
+		"summary.unsupported": "No soportado",
+
+		"feature.market_price":     "precio de mercado",
+		"feature.exact_out":        "salida exacta",
+		"feature.source_whitelist": "lista de fuentes",
+		"feature.gas":              "estimación de gas",
+		"feature.route":            "ruta",
+
+		"endpoints.heading": "Endpoints",
+		"endpoints.none":    "No hay endpoints configurados para este agregador (puede estar desactivado).",
+		"causes.heading":    "Causas de fallo más comunes (7d)",
+		"causes.none":       "No se han registrado fallos.",
+
+		"column.pair":          "Par",
+		"column.network":       "Red",
+		"column.status":        "Estado",
+		"column.uptime_24h":    "Disponibilidad 24h",
+		"column.uptime_7d":     "Disponibilidad 7d",
+		"column.last_checked":  "Última comprobación",
+		"column.message":       "Mensaje",
+		"column.last_response": "Última respuesta",
+		"column.on_chain":      "Consulta on-chain",
+		"column.notes":         "Notas",
+		"column.count":         "Veces",
+		"column.last_seen":     "Última vez",
+		"column.hint":          "Sugerencia",
+
+		"status.up":             "activo",
+		"status.down":           "caído",
+		"status.error":          "error",
+		"status.panic":          "fallo interno",
+		"status.info":           "info",
+		"status.unknown":        "desconocido",
+		"status.unsupported":    "no soportado",
+		"status.not-applicable": "no aplica",
+		"status.disabled":       "desactivado",
+		"status.down_for":       "desde hace {duration}",
+		"status.since":          "{status} desde {time}",
+
+		"failure.edge_error":            "La CDN o el firewall de la API respondió con una página de error",
+		"failure.rate_limited":          "Las peticiones fueron limitadas por tasa",
+		"failure.missing_api_key":       "El monitor no tiene una clave de API configurada",
+		"failure.unauthorized":          "La petición fue rechazada por no estar autorizada",
+		"failure.wrong_source":          "La ruta no pasa por Balancer V3",
+		"failure.hook_quote_mismatch":   "La cotización ignora la comisión del hook del pool",
+		"failure.unexpected_spender":    "La cotización indica un destinatario de allowance desconocido",
+		"failure.price_impact":          "La ruta tiene un impacto de precio alto",
+		"failure.uncompetitive":         "Balancer V3 se enruta, pero otras fuentes ganan",
+		"failure.spread_widening":       "Otras fuentes superan cada vez más la cotización de Balancer V3",
+		"failure.expected_pool_missing": "El pool esperado no está en la ruta",
+		"failure.no_route":              "No se devolvió ninguna ruta",
+		"failure.network":               "La petición no se completó",
+		"failure.server_error":          "La API devolvió un error de servidor",
+		"failure.response_format":       "El formato de la respuesta no es el esperado",
+		"failure.build":                 "No se pudo construir la petición",
+		"failure.panic":                 "El monitor no pudo procesar la respuesta",
+		"failure.wip":                   "Una carencia de integración conocida",
+
+		"widget.title":          "Estado de los agregadores en Balancer V3",
+		"widget.empty":          "Aún no se ha comprobado ningún endpoint.",
+		"widget.updated":        "Actualizado {time}",
+		"widget.provider_title": "{name}: {up}/{total} activos",
+	},
+	"zh": {
+		"language.name": "中文",
+
+		"page.back":      "返回监控",
+		"page.generated": "生成于 {time}",
+
+		"summary.endpoints":   "端点",
+		"summary.up_now":      "当前正常",
+		"summary.uptime_24h":  "24 小时可用率",
+		"summary.uptime_7d":   "7 天可用率",
+		"summary.networks":    "网络",
+		"summary.features":    "功能",
+		"summary.unsupported": "不支持",
+
+		"feature.market_price":     "市场价格",
+		"feature.exact_out":        "精确输出",
+		"feature.source_whitelist": "流动性来源白名单",
+		"feature.gas":              "Gas 估算",
+		"feature.route":            "路由",
+
+		"endpoints.heading": "端点",
+		"endpoints.none":    "此聚合器未配置任何端点（可能已停用）。",
+		"causes.heading":    "常见失败原因（7 天）",
+		"causes.none":       "没有失败记录。",
+
+		"column.pair":          "交易对",
+		"column.network":       "网络",
+		"column.status":        "状态",
+		"column.uptime_24h":    "24 小时可用率",
+		"column.uptime_7d":     "7 天可用率",
+		"column.last_checked":  "上次检查",
+		"column.message":       "消息",
+		"column.last_response": "上次响应",
+		"column.on_chain":      "链上查询",
+		"column.notes":         "备注",
+		"column.count":         "次数",
+		"column.last_seen":     "最近出现",
+		"column.hint":          "提示",
+
+		"status.up":             "正常",
+		"status.down":           "故障",
+		"status.error":          "错误",
+		"status.panic":          "内部错误",
+		"status.info":           "信息",
+		"status.unknown":        "未知",
+		"status.unsupported":    "不支持",
+		"status.not-applicable": "不适用",
+		"status.disabled":       "已停用",
+		"status.down_for":       "已持续 {duration}",
+		"status.since":          "自 {time} 起{status}",
+
+		"failure.edge_error":            "API 的 CDN 或防火墙返回了错误页面",
+		"failure.rate_limited":          "请求被限流",
+		"failure.missing_api_key":       "监控未配置 API 密钥",
+		"failure.unauthorized":          "请求因未授权被拒绝",
+		"failure.wrong_source":          "路由未经过 Balancer V3",
+		"failure.hook_quote_mismatch":   "报价忽略了池的 hook 费用",
+		"failure.unexpected_spender":    "报价中的授权目标地址未知",
+		"failure.price_impact":          "路由的价格影响过高",
+		"failure.uncompetitive":         "已路由 Balancer V3，但其他来源报价更优",
+		"failure.spread_widening":       "其他来源相对 Balancer V3 报价的优势在扩大",
+		"failure.expected_pool_missing": "预期的池不在路由中",
+		"failure.no_route":              "未返回路由",
+		"failure.network":               "请求未完成",
+		"failure.server_error":          "API 返回了服务器错误",
+		"failure.response_format":       "响应格式与预期不符",
+		"failure.build":                 "无法构建请求",
+		"failure.panic":                 "监控处理响应时出错",
+		"failure.wip":                   "已知的集成缺口",
+
+		"widget.title":          "Balancer V3 聚合器状态",
+		"widget.empty":          "尚未检查任何端点。",
+		"widget.updated":        "更新于 {time}",
+		"widget.provider_title": "{name}：{up}/{total} 正常",
+	},
+}
+
+var (
+	messagesOnce sync.Once
+	messages     Catalog
+)
+
+// Messages returns the active catalog: DefaultCatalog with the messages of
+// the JSON file named by MESSAGES_FILE (if any) laid over it, message by
+// message, so a file can fix one translation or add a whole language.
+// Loaded once per process.
+func Messages() Catalog {
+	messagesOnce.Do(func() {
+		messages = DefaultCatalog
+		path := config.GetMessagesFile()
+		if path == "" {
+			return
+		}
+		loaded, err := loadCatalog(path)
+		if err != nil {
+			logs.Printf("%s[ERROR]%s: MESSAGES_FILE %s: %v; using built-in messages\n", config.ColorRed, config.ColorReset, path, err)
+			return
+		}
+		messages = mergeCatalogs(DefaultCatalog, loaded)
+	})
+	return messages
+}
+
+func loadCatalog(path string) (Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out Catalog
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// mergeCatalogs copies base and lays overrides over it; language tags are
+// lower-cased.
+func mergeCatalogs(base, overrides Catalog) Catalog {
+	out := make(Catalog, len(base)+len(overrides))
+	for _, c := range []Catalog{base, overrides} {
+		for lang, msgs := range c {
+			lang = strings.ToLower(lang)
+			if out[lang] == nil {
+				out[lang] = map[string]string{}
+			}
+			for id, text := range msgs {
+				out[lang][id] = text
+			}
+		}
+	}
+	return out
+}
+
+// Languages returns the catalog's language tags, DefaultLang first and the
+// rest sorted.
+func (c Catalog) Languages() []string {
+	out := make([]string, 0, len(c))
+	for lang := range c {
+		if lang != DefaultLang {
+			out = append(out, lang)
+		}
+	}
+	sort.Strings(out)
+	return append([]string{DefaultLang}, out...)
+}
+
+// Negotiate picks the language to render in: the ?lang= value if the
+// catalog has it, else the best Accept-Language entry it has, else
+// DefaultLang. Regional tags fall back to their base language, so "zh-CN"
+// matches "zh".
+func (c Catalog) Negotiate(lang, acceptLanguage string) string {
+	if l, ok := c.match(lang); ok {
+		return l
+	}
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if tag != "" && q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	for _, t := range tags {
+		if l, ok := c.match(t.tag); ok {
+			return l
+		}
+	}
+	return DefaultLang
+}
+
+// match returns the catalog language for tag, trying its base language
+// when the catalog lacks the regional one.
+func (c Catalog) match(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", false
+	}
+	if _, ok := c[tag]; ok {
+		return tag, true
+	}
+	base, _, _ := strings.Cut(tag, "-")
+	if _, ok := c[base]; ok {
+		return base, true
+	}
+	return "", false
+}
+
+// Localizer renders messages in one language.
+type Localizer struct {
+	Lang    string
+	catalog Catalog
+}
+
+// For returns a Localizer for lang over the active catalog.
+func For(lang string) Localizer {
+	return Messages().For(lang)
+}
+
+// For returns a Localizer for lang over c.
+func (c Catalog) For(lang string) Localizer {
+	return Localizer{Lang: lang, catalog: c}
+}
+
+// T renders message id with its {name} placeholders replaced from args,
+// given as name, value pairs: T("page.generated", "time", "12:00"). A
+// message missing from the language falls back to DefaultLang, then to the
+// ID itself. The result is plain text; callers escape it for HTML.
+func (l Localizer) T(id string, args ...string) string {
+	text, ok := l.lookup(id)
+	if !ok {
+		return id
+	}
+	for i := 0; i+1 < len(args); i += 2 {
+		text = strings.ReplaceAll(text, "{"+args[i]+"}", args[i+1])
+	}
+	return text
+}
+
+// Status renders a check status (e.g. "down", "not-applicable"); statuses
+// the catalog doesn't know are returned as they are.
+func (l Localizer) Status(status string) string {
+	if text, ok := l.lookup("status." + status); ok {
+		return text
+	}
+	return status
+}
+
+// Failure renders the summary of a failure class (a notify.Hint class,
+// e.g. "no_route"); "" for a class the catalog doesn't summarize.
+func (l Localizer) Failure(class string) string {
+	text, _ := l.lookup("failure." + class)
+	return text
+}
+
+func (l Localizer) lookup(id string) (string, bool) {
+	if text, ok := l.catalog[l.Lang][id]; ok {
+		return text, true
+	}
+	text, ok := l.catalog[DefaultLang][id]
+	return text, ok
+}
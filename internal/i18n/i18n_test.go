@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/notify"
+)
+
+func TestDefaultCatalogComplete(t *testing.T) {
+	en := DefaultCatalog[DefaultLang]
+	for lang, msgs := range DefaultCatalog {
+		for id := range msgs {
+			if _, ok := en[id]; !ok {
+				t.Errorf("%s message %q is not in %s", lang, id, DefaultLang)
+			}
+		}
+	}
+	for _, h := range notify.DefaultHints {
+		if _, ok := en["failure."+h.Class]; !ok {
+			t.Errorf("no summary for failure class %q", h.Class)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	for _, tc := range []struct{ lang, accept, want string }{
+		{"", "", "en"},
+		{"es", "zh-CN", "es"},
+		{"ZH-tw", "", "zh"},
+		{"fr", "fr-FR, zh-CN;q=0.8, es;q=0.9", "es"},
+		{"", "de, *;q=0.5", "en"},
+		{"", "zh;q=0, es;q=0.1", "es"},
+	} {
+		if got := DefaultCatalog.Negotiate(tc.lang, tc.accept); got != tc.want {
+			t.Errorf("Negotiate(%q, %q) = %q, want %q", tc.lang, tc.accept, got, tc.want)
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	c := mergeCatalogs(DefaultCatalog, Catalog{"DE": {"status.up": "verfügbar"}, "zh": {"status.down": "宕机"}})
+	de := c.For("de")
+	if got := de.Status("up"); got != "verfügbar" {
+		t.Fatalf("de up = %q", got)
+	}
+	// Missing messages fall back to English, then to the ID or status.
+	if got := de.T("page.generated", "time", "12:00"); got != "Generated 12:00" {
+		t.Fatalf("de generated = %q", got)
+	}
+	if got := de.T("no.such.message"); got != "no.such.message" {
+		t.Fatalf("missing = %q", got)
+	}
+	if got := de.Status("weird"); got != "weird" {
+		t.Fatalf("unknown status = %q", got)
+	}
+	if got := c.For("zh").Status("down"); got != "宕机" {
+		t.Fatalf("zh override = %q", got)
+	}
+	if got := c.For("zh").T("status.since", "status", "正常", "time", "2026-03-01"); got != "自 2026-03-01 起正常" {
+		t.Fatalf("zh since = %q", got)
+	}
+	if got := c.For("es").Failure("made_up"); got != "" {
+		t.Fatalf("unknown class = %q", got)
+	}
+	if got := c.Languages(); len(got) != 4 || got[0] != "en" || got[1] != "de" {
+		t.Fatalf("languages = %q", got)
+	}
+}
@@ -0,0 +1,441 @@
+// Package importer loads batches of BaseEndpoints from CSV or JSON, so pools
+// onboarded in a spreadsheet or by automation reach the monitor without a
+// code change. A batch is validated as a whole before anything is applied;
+// rows are upserted by their optional id, else by Name, so a row with an id
+// can be renamed.
+//
+// CSV needs a header row; column names match the JSON keys (case and
+// spaces/hyphens are ignored, so "Token In" is token_in):
+//
+//	id,name,network,token_in,token_out,token_in_decimals,token_out_decimals,expected_pool,swap_amount,expected_no_hops,labels,schedule
+//
+// JSON is an array of objects with those keys; labels is an object there and
+// "team=integrations;priority=p1" in CSV (see config.ParseLabels). Rules are
+// JSON-only. schedule (e.g. "5m" or "*/5 * * * *", see config.ParseSchedule)
+// gives the endpoint its own check cadence.
+// swap_amount may be left empty for a pool the Balancer API knows; Prepare
+// suggests one (see package swapsize).
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/fsutil"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/internal/swapsize"
+	"go-monitoring/monitoring/collector"
+)
+
+// Row is the import shape of one config.BaseEndpoint.
+type Row struct {
+	ID               string                `json:"id,omitempty"`
+	Name             string                `json:"name"`
+	Network          string                `json:"network"`
+	TokenIn          string                `json:"token_in"`
+	TokenOut         string                `json:"token_out"`
+	TokenInDecimals  int                   `json:"token_in_decimals"`
+	TokenOutDecimals int                   `json:"token_out_decimals"`
+	ExpectedPool     string                `json:"expected_pool"`
+	SwapAmount       string                `json:"swap_amount"`
+	ExpectedNoHops   int                   `json:"expected_no_hops"`
+	Rules            []config.EndpointRule `json:"rules,omitempty"`
+	Labels           config.Labels         `json:"labels,omitempty"`
+	Schedule         string                `json:"schedule,omitempty"`
+}
+
+// BaseEndpoint returns the row as configuration.
+func (r Row) BaseEndpoint() config.BaseEndpoint {
+	return config.BaseEndpoint{
+		ID:               r.ID,
+		Name:             r.Name,
+		Network:          r.Network,
+		TokenIn:          r.TokenIn,
+		TokenOut:         r.TokenOut,
+		TokenInDecimals:  r.TokenInDecimals,
+		TokenOutDecimals: r.TokenOutDecimals,
+		ExpectedPool:     r.ExpectedPool,
+		SwapAmount:       r.SwapAmount,
+		ExpectedNoHops:   r.ExpectedNoHops,
+		Rules:            r.Rules,
+		Labels:           r.Labels,
+		Schedule:         r.Schedule,
+	}
+}
+
+func rowFromBase(b config.BaseEndpoint) Row {
+	return Row{
+		ID:               b.ID,
+		Name:             b.Name,
+		Network:          b.Network,
+		TokenIn:          b.TokenIn,
+		TokenOut:         b.TokenOut,
+		TokenInDecimals:  b.TokenInDecimals,
+		TokenOutDecimals: b.TokenOutDecimals,
+		ExpectedPool:     b.ExpectedPool,
+		SwapAmount:       b.SwapAmount,
+		ExpectedNoHops:   b.ExpectedNoHops,
+		Rules:            b.Rules,
+		Labels:           b.Labels,
+		Schedule:         b.Schedule,
+	}
+}
+
+// Formats accepted by Parse.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// FormatFor picks the format from a file name or Content-Type, defaulting to
+// JSON.
+func FormatFor(nameOrContentType string) string {
+	s := strings.ToLower(nameOrContentType)
+	if strings.HasSuffix(s, ".csv") || strings.Contains(s, "text/csv") {
+		return FormatCSV
+	}
+	return FormatJSON
+}
+
+// Parse reads a batch. Syntax errors fail the whole batch; field checks are
+// left to Validate.
+func Parse(r io.Reader, format string) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSON:
+		var rows []Row
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rows); err != nil {
+			return nil, fmt.Errorf("decode JSON: %w", err)
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func parseCSV(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("decode CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("decode CSV: missing header row")
+	}
+
+	header := records[0]
+	for i, h := range header {
+		header[i] = csvColumn(h)
+		switch header[i] {
+		case "id", "name", "network", "token_in", "token_out", "token_in_decimals", "token_out_decimals",
+			"expected_pool", "swap_amount", "expected_no_hops", "labels", "schedule":
+		default:
+			return nil, fmt.Errorf("decode CSV: unknown column %q", h)
+		}
+	}
+
+	rows := make([]Row, 0, len(records)-1)
+	for n, rec := range records[1:] {
+		var row Row
+		for i, v := range rec {
+			v = strings.TrimSpace(v)
+			var err error
+			switch header[i] {
+			case "id":
+				row.ID = v
+			case "name":
+				row.Name = v
+			case "network":
+				row.Network = v
+			case "token_in":
+				row.TokenIn = v
+			case "token_out":
+				row.TokenOut = v
+			case "token_in_decimals":
+				row.TokenInDecimals, err = csvInt(v)
+			case "token_out_decimals":
+				row.TokenOutDecimals, err = csvInt(v)
+			case "expected_pool":
+				row.ExpectedPool = v
+			case "swap_amount":
+				row.SwapAmount = v
+			case "expected_no_hops":
+				row.ExpectedNoHops, err = csvInt(v)
+			case "labels":
+				row.Labels, err = config.ParseLabels(v)
+			case "schedule":
+				row.Schedule = v
+			}
+			if err != nil {
+				return nil, fmt.Errorf("decode CSV: row %d: %s: %w", n+1, header[i], err)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// csvColumn normalises a spreadsheet header, e.g. "Token In" → "token_in".
+func csvColumn(h string) string {
+	h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(h)
+}
+
+func csvInt(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
+
+// RowError is a validation failure; Row is 1-based within the batch.
+type RowError struct {
+	Row     int    `json:"row"`
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every failing row of a batch.
+type ValidationError struct {
+	Rows []RowError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Rows))
+	for i, r := range e.Rows {
+		msgs[i] = fmt.Sprintf("row %d (%s): %s", r.Row, r.Name, r.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// Validate checks every row and returns a *ValidationError listing all
+// failures, or nil. The network must be served by at least one route solver.
+func Validate(rows []Row) error {
+	networks := map[string]bool{}
+	for _, s := range config.RouteSolvers {
+		for _, n := range s.SupportedNetworks {
+			networks[n] = true
+		}
+	}
+
+	var errs []RowError
+	seen := map[string]int{}
+	seenID := map[string]int{}
+	for i, r := range rows {
+		fail := func(format string, args ...interface{}) {
+			errs = append(errs, RowError{Row: i + 1, Name: r.Name, Message: fmt.Sprintf(format, args...)})
+		}
+		if r.Name == "" {
+			fail("name is required")
+		} else if first, ok := seen[r.Name]; ok {
+			fail("duplicate name (first on row %d)", first)
+		} else {
+			seen[r.Name] = i + 1
+		}
+		if r.ID != "" {
+			if !config.ValidEndpointID(r.ID) {
+				fail("id %q must be 1-64 lowercase letters, digits, '-' or '_'", r.ID)
+			} else if first, ok := seenID[r.ID]; ok {
+				fail("duplicate id (first on row %d)", first)
+			} else {
+				seenID[r.ID] = i + 1
+			}
+		}
+		if !networks[r.Network] {
+			fail("network %q is not supported by any route solver", r.Network)
+		}
+		if !addressPattern.MatchString(r.TokenIn) {
+			fail("token_in %q is not an address", r.TokenIn)
+		}
+		if !addressPattern.MatchString(r.TokenOut) {
+			fail("token_out %q is not an address", r.TokenOut)
+		} else if config.SameAddress(r.TokenIn, r.TokenOut) {
+			fail("token_in and token_out are the same")
+		}
+		if !addressPattern.MatchString(r.ExpectedPool) {
+			fail("expected_pool %q is not an address", r.ExpectedPool)
+		}
+		if r.TokenInDecimals < 0 || r.TokenInDecimals > 36 {
+			fail("token_in_decimals %d out of range", r.TokenInDecimals)
+		}
+		if r.TokenOutDecimals < 0 || r.TokenOutDecimals > 36 {
+			fail("token_out_decimals %d out of range", r.TokenOutDecimals)
+		}
+		if amt, ok := new(big.Int).SetString(r.SwapAmount, 10); !ok || amt.Sign() <= 0 {
+			fail("swap_amount %q is not a positive integer in token_in base units", r.SwapAmount)
+		}
+		if r.ExpectedNoHops < 0 {
+			fail("expected_no_hops %d is negative", r.ExpectedNoHops)
+		}
+		if err := r.Labels.Validate(); err != nil {
+			fail("labels: %v", err)
+		}
+		if _, err := config.ParseSchedule(r.Schedule); err != nil {
+			fail("%v", err)
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Rows: errs}
+	}
+	return nil
+}
+
+// Prepare sizes and validates a batch for import: an empty swap_amount is
+// filled with swapsize.Suggest for the pool type and token_in price, and a
+// set one must pass swapsize.Check. Pools the registered source doesn't know
+// are left to Validate, which then runs over the whole batch. Returns the
+// number of amounts filled; sizing and validation failures are reported
+// together in one *ValidationError.
+func Prepare(rows []Row) (suggested int, err error) {
+	var errs []RowError
+	for i := range rows {
+		r := &rows[i]
+		if !addressPattern.MatchString(r.ExpectedPool) || !addressPattern.MatchString(r.TokenIn) ||
+			r.TokenInDecimals < 0 || r.TokenInDecimals > 36 {
+			continue
+		}
+		info, ok := swapsize.Lookup(r.Network, r.ExpectedPool)
+		if !ok {
+			continue
+		}
+		if r.SwapAmount == "" {
+			amount, err := swapsize.Suggest(info, r.TokenIn, r.TokenInDecimals)
+			if err != nil {
+				errs = append(errs, RowError{Row: i + 1, Name: r.Name, Message: "swap_amount is empty and cannot be suggested: " + err.Error()})
+				continue
+			}
+			r.SwapAmount = amount
+			suggested++
+			continue
+		}
+		if err := swapsize.Check(info, r.TokenIn, r.TokenInDecimals, r.SwapAmount); err != nil {
+			errs = append(errs, RowError{Row: i + 1, Name: r.Name, Message: err.Error()})
+		}
+	}
+
+	var verr *ValidationError
+	if errors.As(Validate(rows), &verr) {
+		errs = append(errs, verr.Rows...)
+	}
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Row < errs[j].Row })
+		return suggested, &ValidationError{Rows: errs}
+	}
+	return suggested, nil
+}
+
+// Merge upserts rows into existing by ID or, failing that, Name, keeping
+// existing's order and appending new rows. A row matched by ID renames the
+// endpoint it replaces.
+func Merge(existing []config.BaseEndpoint, rows []Row) (merged []config.BaseEndpoint, added, updated int) {
+	merged = append([]config.BaseEndpoint(nil), existing...)
+	index := make(map[string]int, len(merged))
+	byID := make(map[string]int, len(merged))
+	for i, b := range merged {
+		index[b.Name] = i
+		if b.ID != "" {
+			byID[b.ID] = i
+		}
+	}
+	for _, r := range rows {
+		i, ok := byID[r.ID]
+		if !ok || r.ID == "" {
+			i, ok = index[r.Name]
+		}
+		if ok {
+			delete(index, merged[i].Name)
+			index[r.Name] = i
+			merged[i] = r.BaseEndpoint()
+			updated++
+			continue
+		}
+		if r.ID != "" {
+			byID[r.ID] = len(merged)
+		}
+		index[r.Name] = len(merged)
+		merged = append(merged, r.BaseEndpoint())
+		added++
+	}
+	return merged, added, updated
+}
+
+// Apply upserts rows into the running monitor's BaseEndpoints store, expanded
+// across the enabled route solvers, and returns the solver-row counts. Rows
+// already present keep their results. config.BaseEndpoints is not changed,
+// so pool migration checks see imported rows from the next start (via
+// ENDPOINTS_FILE).
+func Apply(rows []Row) (added, updated int) {
+	bases := make([]config.BaseEndpoint, len(rows))
+	for i, r := range rows {
+		bases[i] = r.BaseEndpoint()
+	}
+	return collector.UpsertEndpoints(monitor.ExpandForSolvers(monitor.BaseInputs(bases)))
+}
+
+// LoadFile reads the ENDPOINTS_FILE format: a JSON batch. A missing file is
+// an empty batch.
+func LoadFile(path string) ([]Row, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := Parse(bytes.NewReader(data), FormatJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := Validate(rows); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rows, nil
+}
+
+// fileMu serialises UpsertFile's read-modify-write.
+var fileMu sync.Mutex
+
+// UpsertFile merges rows into the file at path, writing it via a temp file
+// and rename.
+func UpsertFile(path string, rows []Row) (added, updated int, err error) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	current, err := LoadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	bases := make([]config.BaseEndpoint, len(current))
+	for i, r := range current {
+		bases[i] = r.BaseEndpoint()
+	}
+	merged, added, updated := Merge(bases, rows)
+
+	out := make([]Row, len(merged))
+	for i, b := range merged {
+		out[i] = rowFromBase(b)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return 0, 0, err
+	}
+	return added, updated, fsutil.AtomicWriteFile(path, append(data, '\n'))
+}
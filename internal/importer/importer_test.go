@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/swapsize"
+)
+
+const (
+	usdc = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	gho  = "0x6Bb7a212910682DCFdbd5BCBb3e28FB4E8da10Ee"
+	pool = "0x7ab124ec4029316c2a42f713828ddf2a192b36db"
+)
+
+func TestParseCSVSpreadsheetHeaders(t *testing.T) {
+	in := "\ufeffName,Network,Token In,Token Out,Token In Decimals,Token Out Decimals,Expected Pool,Swap Amount,Expected-No-Hops\n" +
+		"Base-Stable(GHO/USDC), 8453," + usdc + "," + gho + ",6,18," + pool + ",100000000000,1\n"
+	rows, err := Parse(strings.NewReader(in), FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Row{Name: "Base-Stable(GHO/USDC)", Network: "8453", TokenIn: usdc, TokenOut: gho,
+		TokenInDecimals: 6, TokenOutDecimals: 18, ExpectedPool: pool, SwapAmount: "100000000000", ExpectedNoHops: 1}
+	if len(rows) != 1 || rows[0].Name != want.Name || rows[0].Network != want.Network || rows[0].TokenOutDecimals != 18 || rows[0].ExpectedNoHops != 1 {
+		t.Fatalf("rows = %+v, want %+v", rows, want)
+	}
+	if err := Validate(rows); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Parse(strings.NewReader("name,colour\nx,red\n"), FormatCSV); err == nil {
+		t.Fatal("unknown column accepted")
+	}
+}
+
+func TestParseCSVLabels(t *testing.T) {
+	in := "name,labels\n" +
+		"A,team=integrations; Priority=p1\n" +
+		"B,\n"
+	rows, err := Parse(strings.NewReader(in), FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rows[0].Labels.String(); got != "priority=p1,team=integrations" {
+		t.Errorf("labels = %q", got)
+	}
+	if rows[1].Labels != nil {
+		t.Errorf("empty cell = %v, want no labels", rows[1].Labels)
+	}
+	if _, err := Parse(strings.NewReader("name,labels\nA,team\n"), FormatCSV); err == nil {
+		t.Error("label without a value accepted")
+	}
+
+	bad := Row{Name: "A", Network: "8453", TokenIn: usdc, TokenOut: gho, ExpectedPool: pool, SwapAmount: "1",
+		Labels: config.Labels{"Team-Name": "x"}}
+	if err := Validate([]Row{bad}); err == nil || !strings.Contains(err.Error(), "label key") {
+		t.Errorf("Validate = %v, want a label key error", err)
+	}
+}
+
+func TestParseCSVSchedule(t *testing.T) {
+	rows, err := Parse(strings.NewReader("name,schedule\nA,*/5 * * * *\n"), FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].Schedule != "*/5 * * * *" || rows[0].BaseEndpoint().Schedule != "*/5 * * * *" {
+		t.Errorf("schedule = %q", rows[0].Schedule)
+	}
+
+	bad := Row{Name: "A", Network: "8453", TokenIn: usdc, TokenOut: gho, ExpectedPool: pool, SwapAmount: "1", Schedule: "10s"}
+	if err := Validate([]Row{bad}); err == nil || !strings.Contains(err.Error(), "schedule") {
+		t.Errorf("Validate = %v, want a schedule error", err)
+	}
+}
+
+func TestValidateReportsEveryRow(t *testing.T) {
+	good := Row{Name: "A", Network: "8453", TokenIn: usdc, TokenOut: gho, TokenInDecimals: 6, TokenOutDecimals: 18, ExpectedPool: pool, SwapAmount: "1"}
+	dup := good
+	bad := good
+	bad.Name, bad.Network, bad.TokenOut, bad.SwapAmount = "B", "999999", usdc, "1e6"
+
+	err := Validate([]Row{good, dup, bad})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate error = %v, want *ValidationError", err)
+	}
+	var msgs []string
+	for _, r := range verr.Rows {
+		msgs = append(msgs, r.Message)
+	}
+	got := strings.Join(msgs, "\n")
+	for _, want := range []string{"duplicate name (first on row 1)", `network "999999"`, "token_in and token_out are the same", `swap_amount "1e6"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("errors %q missing %q", got, want)
+		}
+	}
+	if verr.Rows[0].Row != 2 {
+		t.Errorf("first error on row %d, want 2", verr.Rows[0].Row)
+	}
+}
+
+func TestPrepareSizesSwapAmounts(t *testing.T) {
+	swapsize.SetPoolInfo(func(network, p string) (swapsize.PoolInfo, bool) {
+		if !strings.EqualFold(p, pool) {
+			return swapsize.PoolInfo{}, false
+		}
+		return swapsize.PoolInfo{Type: "STABLE", TVLUSD: 5_000_000, PricesUSD: map[string]float64{strings.ToLower(usdc): 1}}, true
+	})
+	t.Cleanup(func() { swapsize.SetPoolInfo(nil) })
+
+	base := Row{Network: "8453", TokenIn: usdc, TokenOut: gho, TokenInDecimals: 6, TokenOutDecimals: 18, ExpectedPool: pool}
+	empty, dust, unknownPool, unsized := base, base, base, base
+	empty.Name = "empty"
+	dust.Name, dust.SwapAmount = "dust", "10"
+	unknownPool.Name, unknownPool.ExpectedPool, unknownPool.SwapAmount = "unknown", "0x0000000000000000000000000000000000000001", "10"
+	unsized.Name, unsized.ExpectedPool = "unsized", unknownPool.ExpectedPool
+
+	rows := []Row{empty, dust, unknownPool, unsized}
+	suggested, err := Prepare(rows)
+	if suggested != 1 || rows[0].SwapAmount != "10000000000" {
+		t.Fatalf("Prepare suggested %d, row = %+v", suggested, rows[0])
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Rows) != 2 {
+		t.Fatalf("Prepare error = %v, want dust and unsized rows", err)
+	}
+	if verr.Rows[0].Name != "dust" || !strings.Contains(verr.Rows[0].Message, "dust") ||
+		verr.Rows[1].Name != "unsized" || !strings.Contains(verr.Rows[1].Message, "swap_amount") {
+		t.Fatalf("errors = %+v", verr.Rows)
+	}
+}
+
+func TestUpsertFileMergesByName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	a := Row{Name: "A", Network: "8453", TokenIn: usdc, TokenOut: gho, TokenInDecimals: 6, TokenOutDecimals: 18, ExpectedPool: pool, SwapAmount: "1"}
+	b := a
+	b.Name = "B"
+
+	if added, updated, err := UpsertFile(path, []Row{a, b}); err != nil || added != 2 || updated != 0 {
+		t.Fatalf("first import = %d added, %d updated, %v", added, updated, err)
+	}
+	a.SwapAmount = "2"
+	if added, updated, err := UpsertFile(path, []Row{a}); err != nil || added != 0 || updated != 1 {
+		t.Fatalf("second import = %d added, %d updated, %v", added, updated, err)
+	}
+
+	rows, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Name != "A" || rows[0].SwapAmount != "2" || rows[1].Name != "B" {
+		t.Fatalf("file rows = %+v", rows)
+	}
+
+	merged, added, updated := Merge([]config.BaseEndpoint{{Name: "B", SwapAmount: "9"}, {Name: "C"}}, rows)
+	if added != 1 || updated != 1 || len(merged) != 3 || merged[0].SwapAmount != "1" || merged[2].Name != "A" {
+		t.Fatalf("Merge = %+v (%d added, %d updated)", merged, added, updated)
+	}
+
+	if rows, err := LoadFile(filepath.Join(t.TempDir(), "missing.json")); err != nil || rows != nil {
+		t.Fatalf("LoadFile(missing) = %v, %v", rows, err)
+	}
+	os.WriteFile(path, []byte(`[{"name":"A","swap_amount":"x"}]`), 0o644)
+	if _, err := LoadFile(path); err == nil {
+		t.Fatal("invalid endpoints file loaded")
+	}
+}
+
+func TestMergeRenamesByID(t *testing.T) {
+	existing := []config.BaseEndpoint{{ID: "gho-usdc", Name: "Old name", SwapAmount: "1"}, {Name: "Other"}}
+	merged, added, updated := Merge(existing, []Row{{ID: "gho-usdc", Name: "New name", SwapAmount: "2"}, {Name: "Old name"}})
+	if added != 1 || updated != 1 || len(merged) != 3 || merged[0].Name != "New name" || merged[0].SwapAmount != "2" || merged[2].Name != "Old name" {
+		t.Fatalf("Merge = %+v (%d added, %d updated)", merged, added, updated)
+	}
+
+	bad := Row{ID: "Has Space", Name: "A", Network: "8453", TokenIn: usdc, TokenOut: gho, TokenInDecimals: 6, TokenOutDecimals: 18, ExpectedPool: pool, SwapAmount: "1"}
+	if err := Validate([]Row{bad}); err == nil || !strings.Contains(err.Error(), "id") {
+		t.Fatalf("Validate = %v, want an id error", err)
+	}
+}
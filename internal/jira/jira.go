@@ -0,0 +1,152 @@
+// Package jira creates issues through the Jira Cloud REST API (v2), for
+// escalating provider failures to the partnerships team. It knows nothing
+// about endpoints; monitor.Escalate decides what goes in a ticket.
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// requestTimeout bounds one Jira request.
+const requestTimeout = 15 * time.Second
+
+// maxErrorBody bounds the response body quoted in an error.
+const maxErrorBody = 300
+
+// Client creates issues on one Jira site as one account, authenticating
+// with the account's email and an API token.
+type Client struct {
+	baseURL string
+	email   string
+	token   string
+	client  *http.Client
+}
+
+// NewClient returns a client for the Jira site at baseURL (e.g.
+// https://example.atlassian.net).
+func NewClient(baseURL, email, token string) *Client {
+	return &Client{baseURL: baseURL, email: email, token: token, client: &http.Client{Timeout: requestTimeout}}
+}
+
+// Issue is a ticket to create. Description is in Jira wiki markup.
+type Issue struct {
+	Project     string // project key, e.g. "PARTNER"
+	Type        string // issue type name, e.g. "Task"
+	Summary     string
+	Description string
+	Labels      []string // no spaces: Jira splits labels on them
+}
+
+// Ref identifies a created or found issue.
+type Ref struct {
+	Key string `json:"key"` // e.g. "PARTNER-123"
+	URL string `json:"url"` // browse link
+}
+
+// Create creates the issue and returns its key and link.
+func (c *Client) Create(issue Issue) (Ref, error) {
+	body, err := json.Marshal(map[string]any{"fields": map[string]any{
+		"project":     map[string]string{"key": issue.Project},
+		"issuetype":   map[string]string{"name": issue.Type},
+		"summary":     issue.Summary,
+		"description": issue.Description,
+		"labels":      issue.Labels,
+	}})
+	if err != nil {
+		return Ref{}, err
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := c.do("create issue", "POST", "/rest/api/2/issue", "application/json", bytes.NewReader(body), &created); err != nil {
+		return Ref{}, err
+	}
+	return c.ref(created.Key), nil
+}
+
+// Attach uploads data as a file attached to the issue.
+func (c *Client) Attach(key, filename string, data []byte) error {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.do("attach "+filename, "POST", "/rest/api/2/issue/"+url.PathEscape(key)+"/attachments", w.FormDataContentType(), &buf, nil)
+}
+
+// FindByLabel returns the most recently created issue of the project with
+// the label; ok is false when there is none.
+func (c *Client) FindByLabel(project, label string) (ref Ref, ok bool, err error) {
+	q := url.Values{}
+	q.Set("jql", fmt.Sprintf(`project = "%s" AND labels = "%s" ORDER BY created DESC`, project, label))
+	q.Set("fields", "key")
+	q.Set("maxResults", "1")
+	var found struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}
+	if err := c.do("search", "GET", "/rest/api/2/search?"+q.Encode(), "", nil, &found); err != nil {
+		return Ref{}, false, err
+	}
+	if len(found.Issues) == 0 {
+		return Ref{}, false, nil
+	}
+	return c.ref(found.Issues[0].Key), true, nil
+}
+
+func (c *Client) ref(key string) Ref {
+	return Ref{Key: key, URL: c.baseURL + "/browse/" + key}
+}
+
+// do sends one request and decodes a 2xx JSON response into out, if not nil.
+func (c *Client) do(op, method, path, contentType string, body io.Reader, out any) error {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.email, c.token)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	// Jira refuses multipart uploads without it (XSRF check).
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira %s: %v", op, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("jira %s: reading response: %v", op, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(data) > maxErrorBody {
+			data = data[:maxErrorBody]
+		}
+		return fmt.Errorf("jira %s: HTTP %d: %s", op, resp.StatusCode, bytes.TrimSpace(data))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("jira %s: decoding response: %v", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package jira
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	var created map[string]map[string]any
+	var attached string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "ops@example.com" || pass != "tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"1","key":"PARTNER-7"}`))
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue/PARTNER-7/attachments":
+			if r.Header.Get("X-Atlassian-Token") != "no-check" {
+				http.Error(w, "XSRF check failed", http.StatusForbidden)
+				return
+			}
+			f, h, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(f)
+			attached = h.Filename + ":" + string(data)
+			w.Write([]byte(`[]`))
+		case r.URL.Path == "/rest/api/2/search":
+			if strings.Contains(r.URL.Query().Get("jql"), `labels = "known"`) {
+				w.Write([]byte(`{"issues":[{"key":"PARTNER-3"}]}`))
+				return
+			}
+			w.Write([]byte(`{"issues":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "ops@example.com", "tok")
+	ref, err := c.Create(Issue{Project: "PARTNER", Type: "Task", Summary: "s", Description: "d", Labels: []string{"go-monitoring"}})
+	if err != nil || ref.Key != "PARTNER-7" || ref.URL != srv.URL+"/browse/PARTNER-7" {
+		t.Fatalf("Create = %+v, %v", ref, err)
+	}
+	if f := created["fields"]; f["summary"] != "s" || f["project"].(map[string]any)["key"] != "PARTNER" || f["issuetype"].(map[string]any)["name"] != "Task" {
+		t.Fatalf("created fields = %v", f)
+	}
+	if err := c.Attach("PARTNER-7", "evidence.json", []byte("{}")); err != nil || attached != "evidence.json:{}" {
+		t.Fatalf("Attach: %q, %v", attached, err)
+	}
+	if ref, ok, err := c.FindByLabel("PARTNER", "known"); err != nil || !ok || ref.Key != "PARTNER-3" {
+		t.Fatalf("FindByLabel known = %+v %v %v", ref, ok, err)
+	}
+	if _, ok, err := c.FindByLabel("PARTNER", "other"); err != nil || ok {
+		t.Fatalf("FindByLabel other = %v %v", ok, err)
+	}
+
+	_, err = NewClient(srv.URL, "ops@example.com", "wrong").Create(Issue{})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 401: unauthorized") {
+		t.Fatalf("bad credentials error = %v", err)
+	}
+}
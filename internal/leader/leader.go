@@ -0,0 +1,128 @@
+// Package leader elects one instance to run the check loops when several
+// replicas share a database, so provider APIs see one monitor's traffic
+// however many machines serve the dashboard.
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+// Elector reports whether this instance currently holds leadership.
+type Elector interface {
+	IsLeader() bool
+}
+
+// lockKey is the Postgres advisory lock key for the scheduler ("gomon").
+const lockKey int64 = 0x676f6d6f6e
+
+// PostgresElector holds a session-level Postgres advisory lock on a dedicated
+// connection. Postgres releases the lock when that session ends, so a crashed
+// or partitioned leader is replaced on a follower's next attempt.
+type PostgresElector struct {
+	db     *sql.DB
+	leader atomic.Bool
+
+	mu   sync.Mutex
+	conn *sql.Conn // holds the lock while leader
+}
+
+// NewPostgresElector returns an elector using db. It is a follower until Step
+// or Run acquires the lock.
+func NewPostgresElector(db *sql.DB) *PostgresElector {
+	return &PostgresElector{db: db}
+}
+
+// IsLeader reports whether the lock was held at the last Step.
+func (e *PostgresElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run calls Step every interval until ctx is done, then releases the lock.
+func (e *PostgresElector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+			e.Step(ctx)
+		}
+	}
+}
+
+// Step tries to take the lock as a follower, or checks the lock's session is
+// still alive as leader. Transitions are logged.
+func (e *PostgresElector) Step(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	was := e.leader.Load()
+	is, err := e.stepLocked(ctx)
+	if err != nil {
+		logs.Printf("%s[LEADER]%s %v\n", config.ColorRed, config.ColorReset, err)
+		e.discardLocked()
+		is = false
+	}
+	e.leader.Store(is)
+
+	switch {
+	case is && !was:
+		logs.Printf("%s[LEADER]%s acquired scheduler lock; this instance runs the checks\n", config.ColorGreen, config.ColorReset)
+	case !is && was:
+		logs.Printf("%s[LEADER]%s lost scheduler lock; checks paused on this instance\n", config.ColorYellow, config.ColorReset)
+	}
+}
+
+func (e *PostgresElector) stepLocked(ctx context.Context) (bool, error) {
+	if e.conn == nil {
+		conn, err := e.db.Conn(ctx)
+		if err != nil {
+			return false, fmt.Errorf("connect: %w", err)
+		}
+		e.conn = conn
+	}
+	if e.leader.Load() {
+		if err := e.conn.PingContext(ctx); err != nil {
+			return false, fmt.Errorf("lock session lost: %w", err)
+		}
+		return true, nil
+	}
+	var acquired bool
+	if err := e.conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, lockKey).Scan(&acquired); err != nil {
+		return false, fmt.Errorf("try lock: %w", err)
+	}
+	return acquired, nil
+}
+
+// release unlocks and closes the lock session.
+func (e *PostgresElector) release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.conn != nil && e.leader.Load() {
+		e.conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+	}
+	e.discardLocked()
+	e.leader.Store(false)
+}
+
+// discardLocked closes the lock session rather than returning it to the
+// pool, where it would keep holding the lock. Callers hold e.mu.
+func (e *PostgresElector) discardLocked() {
+	if e.conn == nil {
+		return
+	}
+	e.conn.Raw(func(any) error { return driver.ErrBadConn })
+	e.conn.Close()
+	e.conn = nil
+}
@@ -0,0 +1,158 @@
+// Package localdev runs the monitor against fake in-process providers
+// (LOCAL_DEV), so the dashboard, notifications and history can be developed
+// without API keys or requests to real aggregators. Each fake route solver
+// quotes every BaseEndpoint with a fixed behaviour: steady, flaky or down.
+package localdev
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+)
+
+// Fake provider behaviours, the suffix of their route solver type.
+const (
+	Steady = "steady" // always quotes, in 40-150ms
+	Flaky  = "flaky"  // fails one quote in four (HTTP 500 or 429), slow one in ten
+	Down   = "down"   // always fails with HTTP 503
+)
+
+// Behaviours lists the fake providers in dashboard order.
+var Behaviours = []string{Steady, Flaky, Down}
+
+// solverPrefix starts every fake route solver type.
+const solverPrefix = "fake_"
+
+// SolverType returns the route solver type of a behaviour, e.g. fake_flaky.
+func SolverType(behaviour string) string {
+	return solverPrefix + behaviour
+}
+
+// UseFakeSolvers replaces config.RouteSolvers with one fake solver per
+// behaviour, each supporting every BaseEndpoints network, so no real
+// aggregator is checked. Call config.Load again afterwards so per-solver
+// settings (DELAY_FAKE_STEADY, SLO_LATENCY_FAKE_FLAKY, ...) cover them.
+func UseFakeSolvers() {
+	var networks []string
+	for _, b := range config.BaseEndpoints {
+		if !slices.Contains(networks, b.Network) {
+			networks = append(networks, b.Network)
+		}
+	}
+	solvers := make([]config.RouteSolver, 0, len(Behaviours))
+	for _, b := range Behaviours {
+		solvers = append(solvers, config.RouteSolver{
+			Name:              "Fake " + strings.ToUpper(b[:1]) + b[1:],
+			Type:              SolverType(b),
+			SupportedNetworks: networks,
+		})
+	}
+	config.RouteSolvers = solvers
+}
+
+// Serve starts the fake providers on a loopback port and returns their base
+// URL. They serve GET {base}/{behaviour}/quote with the query FakeURLBuilder
+// builds.
+func Serve() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	go http.Serve(ln, NewServer(rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0))))
+	return "http://" + ln.Addr().String(), nil
+}
+
+// fakeQuote is the fake providers' response body.
+type fakeQuote struct {
+	AmountOut string `json:"amountOut,omitempty"`
+	Pool      string `json:"pool,omitempty"`
+	Source    string `json:"source,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// NewServer returns the fake providers' handler, drawing failures and
+// latencies from rnd.
+func NewServer(rnd *rand.Rand) http.Handler {
+	var mu sync.Mutex // requests are served concurrently
+	intN := func(n int) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return rnd.IntN(n)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{behaviour}/quote", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", fmt.Sprintf("fake-%08x", intN(1<<31)))
+		reply := func(status int, q fakeQuote) {
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(q)
+		}
+
+		switch r.PathValue("behaviour") {
+		case Steady:
+			time.Sleep(time.Duration(40+intN(110)) * time.Millisecond)
+		case Flaky:
+			switch n := intN(20); {
+			case n < 3:
+				reply(http.StatusInternalServerError, fakeQuote{Error: "upstream timeout"})
+				return
+			case n < 5:
+				w.Header().Set("Retry-After", "30")
+				reply(http.StatusTooManyRequests, fakeQuote{Error: "rate limit exceeded"})
+				return
+			case n < 7:
+				time.Sleep(time.Duration(1500+intN(2000)) * time.Millisecond)
+			default:
+				time.Sleep(time.Duration(80+intN(400)) * time.Millisecond)
+			}
+		case Down:
+			reply(http.StatusServiceUnavailable, fakeQuote{Error: "service unavailable"})
+			return
+		default:
+			reply(http.StatusNotFound, fakeQuote{Error: "unknown fake provider"})
+			return
+		}
+
+		q := r.URL.Query()
+		amount, ok := quoteAmount(q.Get("amount"), q.Get("decimalsIn"), q.Get("decimalsOut"), q.Get("sources") != "balancer")
+		if !ok {
+			reply(http.StatusBadRequest, fakeQuote{Error: "invalid amount or decimals"})
+			return
+		}
+		reply(http.StatusOK, fakeQuote{AmountOut: amount, Pool: q.Get("pool"), Source: "BalancerV3"})
+	})
+	return mux
+}
+
+// quoteAmount converts amount between decimals at a 1:1 price less a 0.3%
+// fee; the all-sources quote beats it by 5 bps so the dashboard shows a
+// quote spread.
+func quoteAmount(amount, decimalsIn, decimalsOut string, allSources bool) (string, bool) {
+	in, ok := new(big.Int).SetString(amount, 10)
+	dIn, errIn := strconv.Atoi(decimalsIn)
+	dOut, errOut := strconv.Atoi(decimalsOut)
+	if !ok || in.Sign() <= 0 || errIn != nil || errOut != nil || dIn < 0 || dOut < 0 || dIn > 36 || dOut > 36 {
+		return "", false
+	}
+	out := new(big.Int).Mul(in, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(dOut)), nil))
+	out.Quo(out, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(dIn)), nil))
+	bps := int64(9970)
+	if allSources {
+		bps = 9975
+	}
+	out.Mul(out, big.NewInt(bps)).Quo(out, big.NewInt(10000))
+	if out.Sign() <= 0 {
+		return "", false
+	}
+	return out.String(), true
+}
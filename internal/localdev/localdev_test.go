@@ -0,0 +1,87 @@
+package localdev
+
+import (
+	"math/rand/v2"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+func fakeEndpoint(name, behaviour string) collector.Endpoint {
+	return collector.Endpoint{Name: name, BaseName: name, RouteSolver: SolverType(behaviour), Network: "8453",
+		TokenIn: "0xin", TokenOut: "0xout", TokenInDecimals: 6, TokenOutDecimals: 18, SwapAmount: "1000000", ExpectedPool: "0xpool"}
+}
+
+func TestFakeProviders(t *testing.T) {
+	t.Setenv("LOCAL_DEV", "true") // no wait before the market price call
+	srv := httptest.NewServer(NewServer(rand.New(rand.NewPCG(1, 2))))
+	defer srv.Close()
+	r := providers.NewRegistry()
+	Register(r, srv.URL)
+
+	steady := fakeEndpoint("fake-steady", Steady)
+	r.Check(&steady, nil)
+	if steady.LastStatus != "up" || steady.ReturnAmount != "997000000000000000" || steady.MarketPrice != "997500000000000000" {
+		t.Fatalf("steady = %s %q, quote %s, market %s", steady.LastStatus, steady.Message, steady.ReturnAmount, steady.MarketPrice)
+	}
+	if venues := steady.Route.Venues(); len(venues) != 1 || venues[0].Pool != "0xpool" {
+		t.Fatalf("route = %+v", steady.Route)
+	}
+	if steady.ResponsePhases.IsZero() || steady.HTTPHeaders["X-Request-Id"] == "" {
+		t.Fatalf("diagnostics = %+v %v", steady.ResponsePhases, steady.HTTPHeaders)
+	}
+
+	down := fakeEndpoint("fake-down", Down)
+	r.Check(&down, nil)
+	if down.LastStatus != "down" || !strings.Contains(down.Message, "HTTP 503: service unavailable") {
+		t.Fatalf("down = %s %q", down.LastStatus, down.Message)
+	}
+}
+
+func TestQuoteAmount(t *testing.T) {
+	for _, tt := range []struct {
+		amount, in, out string
+		all             bool
+		want            string
+	}{
+		{"1000000", "6", "18", false, "997000000000000000"},
+		{"1000000000000000000", "18", "6", true, "997500"},
+		{"0", "6", "6", false, ""},
+		{"1", "18", "6", false, ""}, // rounds to nothing
+		{"x", "6", "6", false, ""},
+	} {
+		got, ok := quoteAmount(tt.amount, tt.in, tt.out, tt.all)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("quoteAmount(%s, %s, %s) = %q, %v; want %q", tt.amount, tt.in, tt.out, got, ok, tt.want)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	endpoints := []collector.Endpoint{fakeEndpoint("seed-steady", Steady), fakeEndpoint("seed-down", Down), {Name: "seed-real", RouteSolver: "kyberswap"}}
+	collector.RecordCheck("seed-warm", collector.CheckRecord{At: now, Status: "up"})
+	endpoints = append(endpoints, fakeEndpoint("seed-warm", Flaky))
+
+	if n := Seed(endpoints, now); n != 2 {
+		t.Fatalf("seeded %d rows", n)
+	}
+	steady := collector.GetHistory("seed-steady")
+	if len(steady) != 7*24 || !steady[len(steady)-1].At.Equal(now) || collector.SummarizeHistory(steady, time.Time{}).Up != 7*24 {
+		t.Fatalf("steady history: %d records, last %v", len(steady), steady[len(steady)-1].At)
+	}
+	if spread, ok := steady[0].SpreadBps(); !ok || spread <= 0 || steady[0].ResponsePhases.Phase(collector.PhaseTTFB) <= 0 {
+		t.Fatalf("steady record = %+v", steady[0])
+	}
+	down := collector.GetHistory("seed-down")
+	if last := down[len(down)-1]; last.Status != "down" || down[0].Status != "up" {
+		t.Fatalf("down history runs from %s to %s", down[0].Status, last.Status)
+	}
+	if len(collector.GetHistory("seed-real")) != 0 || len(collector.GetHistory("seed-warm")) != 1 {
+		t.Fatal("seeded a real or warm-started row")
+	}
+}
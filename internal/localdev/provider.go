@@ -0,0 +1,99 @@
+package localdev
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+// Register registers the fake providers served at baseURL under their
+// route solver types.
+func Register(r *providers.Registry, baseURL string) {
+	for _, b := range Behaviours {
+		r.Register(SolverType(b), providers.ProviderConfig{
+			Handler:      FakeHandler{},
+			URLBuilder:   FakeURLBuilder{BaseURL: baseURL},
+			Capabilities: providers.Capabilities{MarketPrice: true, SourceWhitelist: true, Route: true},
+		})
+	}
+}
+
+// FakeURLBuilder builds the fake providers' quote URLs.
+type FakeURLBuilder struct {
+	BaseURL string
+}
+
+// BuildURL quotes the endpoint from the fake provider of its route solver.
+func (b FakeURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
+	behaviour, ok := strings.CutPrefix(endpoint.RouteSolver, solverPrefix)
+	if !ok {
+		return "", fmt.Errorf("%s is not a fake route solver", endpoint.RouteSolver)
+	}
+	params := url.Values{}
+	params.Add("tokenIn", endpoint.TokenIn)
+	params.Add("tokenOut", endpoint.TokenOut)
+	params.Add("amount", endpoint.SwapAmount)
+	params.Add("decimalsIn", strconv.Itoa(endpoint.TokenInDecimals))
+	params.Add("decimalsOut", strconv.Itoa(endpoint.TokenOutDecimals))
+	params.Add("pool", endpoint.ExpectedPool)
+	if options.IsBalancerSourceOnly {
+		params.Add("sources", "balancer")
+	}
+	return fmt.Sprintf("%s/%s/quote?%s", b.BaseURL, behaviour, params.Encode()), nil
+}
+
+// FakeHandler reads the fake providers' quotes.
+type FakeHandler struct{}
+
+// parse returns the quote in a 200 response, or an error naming the HTTP
+// status and the fake provider's error.
+func (FakeHandler) parse(response *check.APIResponse) (fakeQuote, error) {
+	var q fakeQuote
+	if err := json.Unmarshal(response.Body, &q); err != nil {
+		return q, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	if response.StatusCode != 200 {
+		return q, fmt.Errorf("HTTP %d: %s", response.StatusCode, q.Error)
+	}
+	if q.AmountOut == "" || q.AmountOut == "0" {
+		return q, errors.New("no amountOut in response")
+	}
+	return q, nil
+}
+
+// HandleResponse stores the quote and its one-hop route through the pool.
+func (h FakeHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
+	q, err := h.parse(response)
+	if err != nil {
+		return err
+	}
+	endpoint.ReturnAmount = q.AmountOut
+	endpoint.SetRoute(collector.RouteGraph{Paths: []collector.RoutePath{{Hops: []collector.RouteHop{{
+		TokenIn:  endpoint.TokenIn,
+		TokenOut: endpoint.TokenOut,
+		Venues:   []collector.RouteVenue{{Pool: q.Pool, Exchange: q.Source, Percent: 100, AmountIn: endpoint.SwapAmount, AmountOut: q.AmountOut}},
+	}}}}})
+	return nil
+}
+
+// HandleResponseForMarketPrice stores the all-sources quote.
+func (h FakeHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
+	q, err := h.parse(response)
+	if err != nil {
+		return err
+	}
+	endpoint.MarketPrice = q.AmountOut
+	return nil
+}
+
+// GetIgnoreList returns no ignore list: the fakes only quote the pool.
+func (FakeHandler) GetIgnoreList(network string) (string, error) {
+	return "", nil
+}
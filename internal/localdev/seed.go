@@ -0,0 +1,88 @@
+package localdev
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+// seedDays is how much hourly history Seed writes, the in-memory history's
+// capacity.
+const seedDays = 7
+
+// downSince is how long before the seed ends the down provider started
+// failing, so the dashboard shows an outage with a start rather than a week
+// of red.
+const downSince = 36 * time.Hour
+
+// Seed fills the history of the fake solvers' rows among endpoints with a
+// week of hourly checks ending at now, as each behaviour would have produced
+// them. Rows that already have history (a warm start) are skipped. It
+// returns the number of rows seeded.
+func Seed(endpoints []collector.Endpoint, now time.Time) int {
+	rnd := rand.New(rand.NewPCG(1, 2)) // the same week on every start
+	seeded := 0
+	for _, e := range endpoints {
+		behaviour, ok := strings.CutPrefix(e.RouteSolver, solverPrefix)
+		if !ok || len(collector.GetHistory(e.Name)) > 0 {
+			continue
+		}
+		decimalsIn, decimalsOut := strconv.Itoa(e.TokenInDecimals), strconv.Itoa(e.TokenOutDecimals)
+		quote, _ := quoteAmount(e.SwapAmount, decimalsIn, decimalsOut, false)
+		market, _ := quoteAmount(e.SwapAmount, decimalsIn, decimalsOut, true)
+		start := now.Add(-seedDays * 24 * time.Hour)
+		for at := start.Add(time.Hour); !at.After(now); at = at.Add(time.Hour) {
+			collector.RecordCheck(e.Name, seedRecord(rnd, behaviour, at, now, quote, market))
+		}
+		seeded++
+	}
+	return seeded
+}
+
+// seedRecord is one synthetic check of a behaviour at at.
+func seedRecord(rnd *rand.Rand, behaviour string, at, now time.Time, quote, market string) collector.CheckRecord {
+	up := func(latency time.Duration) collector.CheckRecord {
+		return collector.CheckRecord{At: at, Status: "up", Message: "Ok", ReturnAmount: quote, MarketPrice: market, HTTPStatus: 200,
+			ResponseTime: latency, ResponsePhases: seedPhases(rnd, latency)}
+	}
+	failed := func(status int, message string) collector.CheckRecord {
+		latency := time.Duration(30+rnd.IntN(40)) * time.Millisecond
+		return collector.CheckRecord{At: at, Status: "down", Message: "Error handling response: HTTP " + strconv.Itoa(status) + ": " + message,
+			HTTPStatus: status, ResponseTime: latency, ResponsePhases: seedPhases(rnd, latency)}
+	}
+	switch behaviour {
+	case Flaky:
+		switch n := rnd.IntN(20); {
+		case n < 3:
+			return failed(500, "upstream timeout")
+		case n < 5:
+			return failed(429, "rate limit exceeded")
+		case n < 7:
+			return up(time.Duration(1500+rnd.IntN(2000)) * time.Millisecond)
+		default:
+			return up(time.Duration(80+rnd.IntN(400)) * time.Millisecond)
+		}
+	case Down:
+		if at.After(now.Add(-downSince)) {
+			return failed(503, "service unavailable")
+		}
+	}
+	return up(time.Duration(40+rnd.IntN(110)) * time.Millisecond)
+}
+
+// seedPhases splits a synthetic response time as a fresh TLS connection
+// would: a few milliseconds each of DNS and connect, the TLS handshake, then
+// mostly waiting for the first byte.
+func seedPhases(rnd *rand.Rand, total time.Duration) collector.LatencyPhases {
+	p := collector.LatencyPhases{
+		DNS:     time.Duration(1+rnd.IntN(4)) * time.Millisecond,
+		Connect: time.Duration(1+rnd.IntN(3)) * time.Millisecond,
+		TLS:     time.Duration(3+rnd.IntN(5)) * time.Millisecond,
+		Read:    time.Duration(1+rnd.IntN(3)) * time.Millisecond,
+	}
+	p.TTFB = max(total-p.DNS-p.Connect-p.TLS-p.Read, time.Millisecond)
+	return p
+}
@@ -0,0 +1,37 @@
+// Package logs is where the monitor's console output goes. Printf and
+// Println write to Stdout; usage and errors of the subcommands go to
+// Stderr. Both pass through redact.Writer, so a key or RPC URL echoed in a
+// provider error, a request URL or a response body never reaches the logs.
+// The secrets to mask are registered once at startup (redact.SetSecrets);
+// credential-shaped values are masked before that too.
+package logs
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"go-monitoring/monitoring/redact"
+)
+
+var (
+	// Stdout is the redacted standard output.
+	Stdout io.Writer = redact.NewWriter(os.Stdout)
+	// Stderr is the redacted standard error.
+	Stderr io.Writer = redact.NewWriter(os.Stderr)
+)
+
+// Printf formats like fmt.Printf and writes to Stdout.
+func Printf(format string, a ...any) {
+	fmt.Fprintf(Stdout, format, a...)
+}
+
+// Println formats like fmt.Println and writes to Stdout.
+func Println(a ...any) {
+	fmt.Fprintln(Stdout, a...)
+}
+
+// Print formats like fmt.Print and writes to Stdout.
+func Print(a ...any) {
+	fmt.Fprint(Stdout, a...)
+}
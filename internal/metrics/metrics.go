@@ -0,0 +1,140 @@
+// Package metrics is a minimal in-process metrics registry rendered in the
+// Prometheus text exposition format at /metrics. It covers what the monitor
+// needs (labelled gauges and counters) without pulling in a client library.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// metric is one named family of labelled samples.
+type metric struct {
+	name       string
+	help       string
+	kind       string // "gauge" or "counter"
+	labelNames []string
+
+	mu      sync.Mutex
+	samples map[string]float64 // keyed by joined label values
+}
+
+// Gauge is a value that can go up and down, e.g. the last cycle's duration.
+type Gauge struct{ m *metric }
+
+// Counter is a monotonically increasing value, e.g. alerts sent.
+type Counter struct{ m *metric }
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*metric{}
+)
+
+func register(name, help, kind string, labelNames []string) *metric {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if m, ok := registry[name]; ok {
+		return m
+	}
+	m := &metric{name: name, help: help, kind: kind, labelNames: labelNames, samples: map[string]float64{}}
+	registry[name] = m
+	return m
+}
+
+// NewGauge registers (or returns the existing) gauge with the given label
+// names. Label values are passed positionally to Set.
+func NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{register(name, help, "gauge", labelNames)}
+}
+
+// NewCounter registers (or returns the existing) counter with the given
+// label names.
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{register(name, help, "counter", labelNames)}
+}
+
+// Set sets the gauge for the given label values.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.m.update(labelValues, func(float64) float64 { return v })
+}
+
+// Add increments the counter for the given label values. Negative deltas are
+// ignored.
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.m.update(labelValues, func(old float64) float64 { return old + delta })
+}
+
+// Inc increments the counter by one.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (m *metric) update(labelValues []string, fn func(float64) float64) {
+	if len(labelValues) != len(m.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", m.name, len(m.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.samples[key] = fn(m.samples[key])
+}
+
+// WriteText writes every registered metric in the Prometheus text format,
+// sorted by name and label values for stable output.
+func WriteText(w io.Writer) {
+	registryMu.Lock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	registryMu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		registryMu.Lock()
+		m := registry[name]
+		registryMu.Unlock()
+		m.write(w)
+	}
+}
+
+func (m *metric) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+	keys := make([]string, 0, len(m.samples))
+	for k := range m.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s%s %s\n", m.name, formatLabels(m.labelNames, k), strconv.FormatFloat(m.samples[k], 'g', -1, 64))
+	}
+}
+
+func formatLabels(names []string, key string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	values := strings.Split(key, "\xff")
+	parts := make([]string, len(names))
+	for i, n := range names {
+		parts[i] = fmt.Sprintf("%s=%s", n, strconv.Quote(values[i]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// Handler serves the registry at /metrics.
+func Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	WriteText(w)
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	g := NewGauge("test_cycle_duration_seconds", "Duration of the last cycle.", "cycle")
+	g.Set(12.5, "base")
+	g.Set(3, "discovered")
+	g.Set(4, "discovered")
+
+	c := NewCounter("test_alerts_total", "Alerts raised.")
+	c.Inc()
+	c.Add(2)
+	c.Add(-5) // ignored
+
+	var b strings.Builder
+	WriteText(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		"# HELP test_alerts_total Alerts raised.\n# TYPE test_alerts_total counter\ntest_alerts_total 3\n",
+		"# TYPE test_cycle_duration_seconds gauge\n",
+		`test_cycle_duration_seconds{cycle="base"} 12.5` + "\n",
+		`test_cycle_duration_seconds{cycle="discovered"} 4` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "test_alerts_total") > strings.Index(out, "test_cycle_duration_seconds") {
+		t.Error("metrics should be sorted by name")
+	}
+}
+
+func TestSameNameReturnsSameMetric(t *testing.T) {
+	NewCounter("test_shared_total", "Shared.").Inc()
+	NewCounter("test_shared_total", "Shared.").Inc()
+
+	var b strings.Builder
+	WriteText(&b)
+	if !strings.Contains(b.String(), "test_shared_total 2\n") {
+		t.Fatalf("expected shared counter at 2:\n%s", b.String())
+	}
+}
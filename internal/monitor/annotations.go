@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/store"
+)
+
+// maxMemoryAnnotations bounds the annotations kept without a store.
+const maxMemoryAnnotations = 500
+
+var (
+	annotationsMu     sync.Mutex
+	memoryAnnotations []store.Annotation
+)
+
+// RecordAnnotation saves a deploy or config change mark to the registered
+// store, or without one keeps it in memory for this process's charts.
+// Failures are logged.
+func RecordAnnotation(a store.Annotation) {
+	if st := GetStore(); st != nil {
+		if err := st.SaveAnnotation(a); err != nil {
+			logs.Printf("%s[STORE]%s save %s annotation failed: %v\n", config.ColorRed, config.ColorReset, a.Kind, err)
+		}
+		return
+	}
+	annotationsMu.Lock()
+	defer annotationsMu.Unlock()
+	memoryAnnotations = append(memoryAnnotations, a)
+	if n := len(memoryAnnotations) - maxMemoryAnnotations; n > 0 {
+		memoryAnnotations = append([]store.Annotation(nil), memoryAnnotations[n:]...)
+	}
+}
+
+// Annotations returns the annotations since a time, oldest first, from the
+// registered store or else memory.
+func Annotations(since time.Time) ([]store.Annotation, error) {
+	if st := GetStore(); st != nil {
+		return st.QueryAnnotations(since)
+	}
+	annotationsMu.Lock()
+	defer annotationsMu.Unlock()
+	var out []store.Annotation
+	for _, a := range memoryAnnotations {
+		if !a.At.Before(since) {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// RecordDeploy marks a deploy at startup when version differs from the last
+// deploy the store recorded, so restarts and instances sharing a store mark
+// each version once. Without a store every start is marked.
+func RecordDeploy(version string, now time.Time) {
+	if st := GetStore(); st != nil {
+		past, err := st.QueryAnnotations(time.Time{})
+		if err != nil {
+			logs.Printf("%s[STORE]%s read annotations failed: %v\n", config.ColorRed, config.ColorReset, err)
+			return
+		}
+		for i := len(past) - 1; i >= 0; i-- {
+			if past[i].Kind == store.AnnotationDeploy {
+				if past[i].Text == version {
+					return
+				}
+				break
+			}
+		}
+	}
+	RecordAnnotation(store.Annotation{At: now, Kind: store.AnnotationDeploy, Text: version})
+}
@@ -0,0 +1,48 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/store"
+)
+
+func TestRecordDeployOncePerVersion(t *testing.T) {
+	SetStore(store.NewFileStore(filepath.Join(t.TempDir(), "state.json")))
+	t.Cleanup(func() { SetStore(nil) })
+
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	RecordDeploy("v1.0.0", now)
+	RecordAnnotation(store.Annotation{At: now.Add(time.Minute), Kind: store.AnnotationConfig, BaseName: "GHO/USDC", Text: "added by import"})
+	RecordDeploy("v1.0.0", now.Add(time.Hour)) // restart of the same version
+	RecordDeploy("v1.1.0", now.Add(2*time.Hour))
+
+	got, err := Annotations(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deploys []string
+	for _, a := range got {
+		if a.Kind == store.AnnotationDeploy {
+			deploys = append(deploys, a.Text)
+		}
+	}
+	if len(got) != 3 || len(deploys) != 2 || deploys[0] != "v1.0.0" || deploys[1] != "v1.1.0" {
+		t.Errorf("annotations = %+v, want v1.0.0 and v1.1.0 deploys around the config edit", got)
+	}
+}
+
+func TestAnnotationsWithoutStore(t *testing.T) {
+	annotationsMu.Lock()
+	memoryAnnotations = nil
+	annotationsMu.Unlock()
+
+	now := time.Now()
+	RecordDeploy("dev", now.Add(-2*time.Hour))
+	RecordDeploy("dev", now)
+	got, _ := Annotations(now.Add(-time.Hour))
+	if len(got) != 1 || !got[0].At.Equal(now) {
+		t.Errorf("annotations = %+v, want every start marked and the older one left out", got)
+	}
+}
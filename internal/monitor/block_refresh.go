@@ -0,0 +1,125 @@
+package monitor
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+// blockRefreshRetry is how long a network waits before resubscribing after
+// its newHeads subscription fails.
+const blockRefreshRetry = 30 * time.Second
+
+// RunBlockRefresh subscribes to new blocks on every balancer_sor network
+// with a WebSocket RPC URL (<NETWORK>_WS_RPC_URL) and refreshes the on-chain
+// price of the network's endpoints on the first new block each
+// BLOCK_REFRESH_INTERVAL, so the on-chain reference stays near real time
+// between check cycles. A time rather than a block count keeps the RPC load
+// alike on 12-second and sub-second chains. Provider polling is unchanged.
+// Only the leader refreshes; dry runs don't subscribe.
+func RunBlockRefresh() {
+	if config.GetDryRunEnabled() {
+		return
+	}
+	every := config.GetBlockRefreshInterval()
+	for _, network := range blockRefreshNetworks(config.GetEnabledRouteSolvers()) {
+		logs.Printf("%s[BLOCK REFRESH]%s %s: refreshing on-chain prices every %s\n", config.ColorBlue, config.ColorReset, config.NetworkName(network), every)
+		go watchNetwork(network, config.GetWSRPCURL(network), every)
+	}
+}
+
+// blockRefreshNetworks returns the balancer_sor networks with a WebSocket
+// RPC URL.
+func blockRefreshNetworks(solvers []config.RouteSolver) []string {
+	var networks []string
+	for _, solver := range solvers {
+		if solver.Type != "balancer_sor" {
+			continue
+		}
+		for _, network := range solver.SupportedNetworks {
+			if config.GetWSRPCURL(network) != "" {
+				networks = append(networks, network)
+			}
+		}
+	}
+	return networks
+}
+
+// watchNetwork keeps a newHeads subscription open for network, resubscribing
+// after blockRefreshRetry when it fails. A refresh still running when the
+// next one is due is not overlapped; that block is skipped.
+func watchNetwork(network, wsURL string, every time.Duration) {
+	var busy atomic.Bool
+	for {
+		err := providers.WatchNewHeads(context.Background(), wsURL, every, func(block uint64) {
+			if !IsLeader() || !busy.CompareAndSwap(false, true) {
+				return
+			}
+			go func() {
+				defer busy.Store(false)
+				n := refreshOnChainPrices(network)
+				logs.Printf("%s[BLOCK REFRESH]%s %s block %d: refreshed %d on-chain prices\n", config.ColorBlue, config.ColorReset, config.NetworkName(network), block, n)
+			}()
+		})
+		logs.Printf("%s[BLOCK REFRESH]%s %s: %v; resubscribing in %s\n", config.ColorYellow, config.ColorReset, config.NetworkName(network), err, blockRefreshRetry)
+		clock.Sleep(blockRefreshRetry)
+	}
+}
+
+// refreshOnChainPrices re-reads the on-chain price of network's monitored and
+// discovered endpoints and returns how many it refreshed.
+func refreshOnChainPrices(network string) int {
+	n := 0
+	for _, e := range collector.GetEndpointsCopy() {
+		if refreshable(&e, network) && refreshEndpoint(e, collector.UpdateEndpointByName) {
+			n++
+		}
+	}
+	for _, e := range collector.GetDiscoveredEndpointsCopy() {
+		if refreshable(&e, network) && refreshEndpoint(e, collector.UpdateDiscoveredEndpointByName) {
+			n++
+		}
+	}
+	return n
+}
+
+// refreshEndpoint re-reads e's on-chain price and writes it back through
+// update, unless a check finished meanwhile (sameCheck): its price is newer
+// and may be for another swap path. Reports whether it wrote.
+func refreshEndpoint(e collector.Endpoint, update func(string, func(*collector.Endpoint)) bool) bool {
+	before := e
+	providers.RefreshOnChainPrice(&e)
+	wrote := false
+	update(e.Name, func(ep *collector.Endpoint) {
+		if sameCheck(ep, &before) {
+			copyOnChain(ep, &e)
+			wrote = true
+		}
+	})
+	return wrote
+}
+
+// sameCheck reports whether a and b hold the same check: the same
+// LastChecked and swap path.
+func sameCheck(a, b *collector.Endpoint) bool {
+	return a.LastChecked.Equal(b.LastChecked) && slices.Equal(a.SwapPathPools, b.SwapPathPools)
+}
+
+// refreshable reports whether e is a balancer_sor endpoint on network whose
+// last check found a swap path to query.
+func refreshable(e *collector.Endpoint, network string) bool {
+	return e.Network == network && e.RouteSolver == "balancer_sor" && len(e.SwapPathPools) > 0
+}
+
+// copyOnChain copies the on-chain query fields of src onto dst.
+func copyOnChain(dst, src *collector.Endpoint) {
+	dst.OnChainPrice, dst.OnChainQueryError = src.OnChainPrice, src.OnChainQueryError
+	dst.OnChainBlock, dst.OnChainRPCHost, dst.OnChainLatency = src.OnChainBlock, src.OnChainRPCHost, src.OnChainLatency
+}
@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestBlockRefreshNetworks(t *testing.T) {
+	t.Setenv("BASE_WS_RPC_URL", "wss://base.example")
+	t.Setenv("ETHEREUM_WS_RPC_URL", "")
+	solvers := []config.RouteSolver{
+		{Type: "balancer_sor", SupportedNetworks: []string{"1", "8453"}},
+		{Type: "paraswap", SupportedNetworks: []string{"8453"}},
+	}
+	if got := blockRefreshNetworks(solvers); !reflect.DeepEqual(got, []string{"8453"}) {
+		t.Fatalf("blockRefreshNetworks = %v, want [8453]", got)
+	}
+}
+
+func TestRefreshable(t *testing.T) {
+	path := []string{"0xpool"}
+	tests := []struct {
+		e    collector.Endpoint
+		want bool
+	}{
+		{collector.Endpoint{Network: "8453", RouteSolver: "balancer_sor", SwapPathPools: path}, true},
+		{collector.Endpoint{Network: "1", RouteSolver: "balancer_sor", SwapPathPools: path}, false},
+		{collector.Endpoint{Network: "8453", RouteSolver: "paraswap", SwapPathPools: path}, false},
+		{collector.Endpoint{Network: "8453", RouteSolver: "balancer_sor"}, false},
+	}
+	for _, tt := range tests {
+		if got := refreshable(&tt.e, "8453"); got != tt.want {
+			t.Errorf("refreshable(%+v) = %v, want %v", tt.e, got, tt.want)
+		}
+	}
+}
+
+func TestSameCheck(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := collector.Endpoint{LastChecked: at, SwapPathPools: []string{"0xpool"}}
+	tests := []struct {
+		now  collector.Endpoint
+		want bool
+	}{
+		{collector.Endpoint{LastChecked: at, SwapPathPools: []string{"0xpool"}, OnChainPrice: "1"}, true},
+		{collector.Endpoint{LastChecked: at.Add(time.Minute), SwapPathPools: []string{"0xpool"}}, false},
+		{collector.Endpoint{LastChecked: at, SwapPathPools: []string{"0xother"}}, false},
+	}
+	for _, tt := range tests {
+		if got := sameCheck(&tt.now, &before); got != tt.want {
+			t.Errorf("sameCheck(%+v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"go-monitoring/internal/clock"
+)
+
+func TestMonitorAPIsFollowsTheClock(t *testing.T) {
+	c := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	clock.Use(c)
+	cycles := make(chan time.Time)
+	checkAll = func() { cycles <- c.Now() }
+	t.Cleanup(func() {
+		clock.Use(clock.Real)
+		checkAll = checkAllEndpoints
+	})
+
+	go MonitorAPIs(time.Hour, 10*time.Minute)
+	c.BlockUntil(1) // the first-cycle delay
+	c.Advance(10 * time.Minute)
+	if at := <-cycles; !at.Equal(time.Date(2026, 3, 1, 12, 10, 0, 0, time.UTC)) {
+		t.Fatalf("first cycle at %v, want after the first-cycle delay", at)
+	}
+
+	c.BlockUntil(1) // the interval ticker
+	c.Advance(time.Hour)
+	if at := <-cycles; !at.Equal(time.Date(2026, 3, 1, 13, 10, 0, 0, time.UTC)) {
+		t.Fatalf("second cycle at %v, want one interval later", at)
+	}
+}
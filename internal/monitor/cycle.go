@@ -0,0 +1,163 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/metrics"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+var (
+	cycleDuration = metrics.NewGauge("monitor_cycle_duration_seconds",
+		"Wall-clock duration of the last completed check cycle.", "cycle")
+	cycleCompleted = metrics.NewGauge("monitor_cycle_last_completed_timestamp_seconds",
+		"Unix time the last check cycle completed.", "cycle")
+	cycleEndpoints = metrics.NewGauge("monitor_cycle_endpoints",
+		"Endpoints by status at the end of the last check cycle.", "cycle", "status")
+	cycleAlerts = metrics.NewCounter("monitor_cycle_alerts_total",
+		"Endpoint alerts raised during check cycles.", "cycle")
+
+	// cycleStatusesSeen holds the statuses each cycle has reported in
+	// monitor_cycle_endpoints, so one missing from a later cycle reads 0
+	// instead of keeping its last count.
+	cycleStatusesMu   sync.Mutex
+	cycleStatusesSeen = map[string]map[string]bool{}
+
+	endpointUpOnce sync.Once
+	endpointUp     *metrics.Gauge
+	endpointKeys   []string
+)
+
+// endpointUpGauge registers monitor_endpoint_up on first use, once the
+// environment is loaded: 1 when the endpoint's last check passed, 0 when it
+// failed, labelled with the endpoint, solver and network plus label_<key>
+// for each METRIC_LABEL_KEYS key (empty when the endpoint lacks it).
+func endpointUpGauge() (*metrics.Gauge, []string) {
+	endpointUpOnce.Do(func() {
+		endpointKeys = config.GetMetricLabelKeys()
+		names := []string{"endpoint", "solver", "network"}
+		for _, k := range endpointKeys {
+			names = append(names, "label_"+k)
+		}
+		endpointUp = metrics.NewGauge("monitor_endpoint_up",
+			"1 when the endpoint's last check passed, 0 when it failed.", names...)
+	})
+	return endpointUp, endpointKeys
+}
+
+// endpointMetricLabels returns e's monitor_endpoint_up label values.
+func endpointMetricLabels(e collector.Endpoint, keys []string) []string {
+	values := []string{e.Name, e.RouteSolver, e.Network}
+	for _, k := range keys {
+		values = append(values, e.Labels[k])
+	}
+	return values
+}
+
+// cycleStats accumulates one check cycle (the hourly BaseEndpoints sweep or a
+// discovered test set run) for its summary line and metrics.
+type cycleStats struct {
+	name        string // "base", "scheduled" or "discovered"
+	start       time.Time
+	alertsStart int64
+	emailsStart int64
+
+	statuses    map[string]int
+	slowest     string
+	slowestTime time.Duration
+}
+
+func startCycle(name string) *cycleStats {
+	return &cycleStats{
+		name:        name,
+		start:       clock.Now(),
+		alertsStart: notify.AlertsRaised(),
+		emailsStart: notify.EmailsSent(),
+		statuses:    map[string]int{},
+	}
+}
+
+// record adds one endpoint's check result and duration, and sets its
+// monitor_endpoint_up. The duration covers both the Balancer-only and market
+// price calls.
+func (s *cycleStats) record(e collector.Endpoint, took time.Duration) {
+	s.statuses[e.LastStatus]++
+	up, keys := endpointUpGauge()
+	switch {
+	case e.LastStatus == "up":
+		up.Set(1, endpointMetricLabels(e, keys)...)
+	case collector.IsDownStatus(e.LastStatus):
+		up.Set(0, endpointMetricLabels(e, keys)...)
+	}
+	if took > s.slowestTime {
+		s.slowestTime = took
+		s.slowest = e.Name
+	}
+}
+
+// finish logs the one-line cycle summary and updates the cycle metrics.
+func (s *cycleStats) finish() {
+	took := clock.Now().Sub(s.start)
+	alerts := notify.AlertsRaised() - s.alertsStart
+	emails := notify.EmailsSent() - s.emailsStart
+
+	logs.Printf("%s[CYCLE SUMMARY]%s %s\n", config.ColorBlue, config.ColorReset, s.summary(took, alerts, emails))
+
+	cycleDuration.Set(took.Seconds(), s.name)
+	cycleCompleted.Set(float64(clock.Now().Unix()), s.name)
+	s.setEndpointCounts()
+	cycleAlerts.Add(float64(alerts), s.name)
+}
+
+// setEndpointCounts sets monitor_cycle_endpoints for every status the cycle
+// has reported, 0 for those absent from this run.
+func (s *cycleStats) setEndpointCounts() {
+	cycleStatusesMu.Lock()
+	defer cycleStatusesMu.Unlock()
+	seen := cycleStatusesSeen[s.name]
+	if seen == nil {
+		seen = map[string]bool{}
+		cycleStatusesSeen[s.name] = seen
+	}
+	for status := range s.statuses {
+		seen[status] = true
+	}
+	for status := range seen {
+		cycleEndpoints.Set(float64(s.statuses[status]), s.name, status)
+	}
+}
+
+// summary renders the cycle as space-separated key=value pairs so one line
+// per cycle is greppable in the Fly logs, e.g.
+//
+//	cycle=base endpoints=40 up=35 down=3 unsupported=2 duration=14m2s slowest=KyberSwap-X slowest_duration=9.1s alerts=3 emails=3
+func (s *cycleStats) summary(took time.Duration, alerts, emails int64) string {
+	total := 0
+	statuses := make([]string, 0, len(s.statuses))
+	for status, n := range s.statuses {
+		total += n
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	parts := []string{fmt.Sprintf("cycle=%s", s.name), fmt.Sprintf("endpoints=%d", total)}
+	for _, status := range statuses {
+		parts = append(parts, fmt.Sprintf("%s=%d", status, s.statuses[status]))
+	}
+	parts = append(parts, fmt.Sprintf("duration=%s", took.Round(time.Second)))
+	if s.slowest != "" {
+		parts = append(parts,
+			fmt.Sprintf("slowest=%q", s.slowest),
+			fmt.Sprintf("slowest_duration=%s", s.slowestTime.Round(100*time.Millisecond)))
+	}
+	parts = append(parts, fmt.Sprintf("alerts=%d", alerts), fmt.Sprintf("emails=%d", emails))
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,52 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/metrics"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestCycleSummary(t *testing.T) {
+	s := startCycle("base")
+	s.record(collector.Endpoint{Name: "Odos-X", LastStatus: "up"}, 2*time.Second)
+	s.record(collector.Endpoint{Name: "KyberSwap-X", LastStatus: "down"}, 9100*time.Millisecond)
+	s.record(collector.Endpoint{Name: "Paraswap-X", LastStatus: "up"}, time.Second)
+
+	got := s.summary(14*time.Minute+2*time.Second, 1, 0)
+	want := `cycle=base endpoints=3 down=1 up=2 duration=14m2s slowest="KyberSwap-X" slowest_duration=9.1s alerts=1 emails=0`
+	if got != want {
+		t.Fatalf("summary =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCycleEndpointsZeroesAbsentStatuses(t *testing.T) {
+	s := startCycle("zeroing")
+	s.record(collector.Endpoint{Name: "Odos-X", LastStatus: "down"}, time.Second)
+	s.setEndpointCounts()
+	s = startCycle("zeroing")
+	s.record(collector.Endpoint{Name: "Odos-X", LastStatus: "up"}, time.Second)
+	s.setEndpointCounts()
+
+	var out strings.Builder
+	metrics.WriteText(&out)
+	for _, want := range []string{
+		`monitor_cycle_endpoints{cycle="zeroing",status="down"} 0`,
+		`monitor_cycle_endpoints{cycle="zeroing",status="up"} 1`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics lack %s:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestEndpointMetricLabels(t *testing.T) {
+	e := collector.Endpoint{Name: "Odos-X", RouteSolver: "odos", Network: "8453", Labels: config.Labels{"team": "integrations"}}
+	got := strings.Join(endpointMetricLabels(e, []string{"team", "priority"}), "|")
+	if want := "Odos-X|odos|8453|integrations|"; got != want {
+		t.Fatalf("labels = %q, want %q", got, want)
+	}
+}
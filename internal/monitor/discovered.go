@@ -1,11 +1,10 @@
 package monitor
 
 import (
-	"fmt"
-	"time"
-
 	"go-monitoring/config"
-	"go-monitoring/internal/collector"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
 )
 
 // RunDiscoveredOnce iterates the discovered-endpoints store and runs the same
@@ -18,24 +17,43 @@ import (
 func RunDiscoveredOnce() {
 	eps := collector.GetDiscoveredEndpointsCopy()
 	if len(eps) == 0 {
-		fmt.Printf("%s[DISCOVERY RUN]%s no discovered test rows to check\n",
+		logs.Printf("%s[DISCOVERY RUN]%s no discovered test rows to check\n",
+			config.ColorBlue, config.ColorReset)
+		return
+	}
+
+	if !IsLeader() {
+		logs.Printf("%s[FOLLOWER]%s another instance holds the scheduler lock; skipping discovered test rows\n",
 			config.ColorBlue, config.ColorReset)
 		return
 	}
 
-	fmt.Printf("%s[DISCOVERY RUN]%s checking %d discovered test rows\n",
+	logs.Printf("%s[DISCOVERY RUN]%s checking %d discovered test rows\n",
 		config.ColorBlue, config.ColorReset, len(eps))
 
+	stats := startCycle("discovered")
+	options := cycleCheckOptions(eps)
+	groups := newGroupCycle(collector.GetDiscoveredEndpointsCopy)
 	for _, endpoint := range eps {
 		name := endpoint.Name
+		started := clock.Now()
+		groups.enter(endpoint.BaseName)
 		safeCheck(name, func() {
 			collector.UpdateDiscoveredEndpointByName(name, func(e *collector.Endpoint) {
-				CheckAPI(e, nil) // nil triggers Balancer-only + market price calls
+				CheckAPI(e, options) // Balancer-only + market price calls
 			})
 		})
-		time.Sleep(endpoint.Delay)
+		if checked := collector.GetDiscoveredEndpointByName(name); checked != nil {
+			stats.record(*checked, clock.Now().Sub(started))
+		}
+		sleepBetweenChecks(endpoint.Delay)
 	}
+	groups.finish()
+	reportMarketPriceHits(options)
+	reportCallCacheHits()
+	stats.finish()
+	saveState()
 
-	fmt.Printf("%s[DISCOVERY RUN]%s finished checking %d rows\n",
+	logs.Printf("%s[DISCOVERY RUN]%s finished checking %d rows\n",
 		config.ColorGreen, config.ColorReset, len(eps))
 }
@@ -0,0 +1,17 @@
+package monitor
+
+import (
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+)
+
+// sleepBetweenChecks applies the per-solver delay between rows. Skipped in
+// dry-run mode since no provider requests are sent.
+func sleepBetweenChecks(d time.Duration) {
+	if config.GetDryRunEnabled() {
+		return
+	}
+	clock.Sleep(d)
+}
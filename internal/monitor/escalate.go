@@ -0,0 +1,357 @@
+package monitor
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/jira"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/redact"
+)
+
+// ErrNotFailing is returned by Escalate for an endpoint that isn't down.
+var ErrNotFailing = errors.New("endpoint is not failing")
+
+// ErrJiraDisabled is returned by Escalate when JIRA_BASE_URL or
+// JIRA_PROJECT isn't set.
+var ErrJiraDisabled = errors.New("jira escalation is not configured (JIRA_BASE_URL, JIRA_PROJECT)")
+
+// escalationHistory is how much of an endpoint's history a ticket's evidence
+// carries.
+const escalationHistory = 24 * time.Hour
+
+// Escalation is the Jira ticket raised for an endpoint's down streak.
+type Escalation struct {
+	Endpoint  string    `json:"endpoint"`
+	Key       string    `json:"key"`
+	URL       string    `json:"url"`
+	Class     string    `json:"class"`          // failure class (notify.Hint.Class)
+	Rule      string    `json:"rule,omitempty"` // the rule that raised it; empty when raised by hand
+	CreatedAt time.Time `json:"createdAt"`
+	// Existing is set when the streak already had a ticket (found by its
+	// incident label) and none was created.
+	Existing bool `json:"existing,omitempty"`
+}
+
+var (
+	escalationsMu sync.Mutex
+	// escalations holds the ticket of each endpoint's latest escalated
+	// streak, keyed by Endpoint.Name, for the solver page and so rules
+	// escalate a streak once.
+	escalations = map[string]Escalation{}
+)
+
+// JiraEnabled reports whether escalation tickets can be created.
+func JiraEnabled() bool {
+	return config.GetJiraBaseURL() != "" && config.GetJiraProject() != ""
+}
+
+// GetEscalation returns the ticket raised for the endpoint's current down
+// streak, if any.
+func GetEscalation(e collector.Endpoint) (Escalation, bool) {
+	escalationsMu.Lock()
+	defer escalationsMu.Unlock()
+	esc, ok := escalations[e.Name]
+	if !ok || !collector.IsDownStatus(e.LastStatus) || esc.CreatedAt.Before(e.FirstSeenDown) {
+		return Escalation{}, false
+	}
+	return esc, true
+}
+
+// Escalate raises a Jira ticket in JIRA_PROJECT for the endpoint's current
+// down streak, with the provider, pool, failure class and evidence (the
+// alert text and the last day of checks, plus an evidence.json
+// attachment). rule names the rule escalating it, empty for an operator. A
+// streak gets one ticket: when one already carries its incident label, that
+// ticket is returned with Existing set.
+func Escalate(e collector.Endpoint, rule string) (Escalation, error) {
+	if !JiraEnabled() {
+		return Escalation{}, ErrJiraDisabled
+	}
+	if !collector.IsDownStatus(e.LastStatus) {
+		return Escalation{}, ErrNotFailing
+	}
+	if esc, ok := GetEscalation(e); ok {
+		esc.Existing = true
+		return esc, nil
+	}
+
+	issue := ticketFor(e, collector.GetHistory(e.Name), rule, clock.Now())
+	esc := Escalation{Endpoint: e.Name, Class: failureClass(e), Rule: rule, CreatedAt: clock.Now()}
+	if config.GetDryRunEnabled() {
+		logs.Printf("%s[DRY RUN]%s: Jira ticket not created: %s\n", config.ColorYellow, config.ColorReset, issue.Summary)
+		return esc, nil
+	}
+
+	email, token := config.GetJiraCredentials()
+	client := jira.NewClient(config.GetJiraBaseURL(), email, token)
+	label := incidentLabel(e)
+	if ref, found, err := client.FindByLabel(issue.Project, label); err != nil {
+		return Escalation{}, err
+	} else if found {
+		esc.Key, esc.URL, esc.Existing = ref.Key, ref.URL, true
+		recordEscalation(esc)
+		return esc, nil
+	}
+	ref, err := client.Create(issue)
+	if err != nil {
+		return Escalation{}, err
+	}
+	esc.Key, esc.URL = ref.Key, ref.URL
+	recordEscalation(esc)
+	if err := client.Attach(ref.Key, "evidence.json", redact.Bytes(evidenceFor(e, collector.GetHistory(e.Name), clock.Now()))); err != nil {
+		// The ticket stands without it; the description has the evidence.
+		logs.Printf("%s[JIRA]%s %s: %v\n", config.ColorYellow, config.ColorReset, ref.Key, err)
+	}
+	logs.Printf("%s[JIRA]%s %s: created %s\n", config.ColorGreen, config.ColorReset, e.Name, ref.Key)
+	return esc, nil
+}
+
+func recordEscalation(esc Escalation) {
+	escalationsMu.Lock()
+	defer escalationsMu.Unlock()
+	escalations[esc.Endpoint] = esc
+}
+
+// failureClass returns the class of the endpoint's failure from the alert
+// hints table, "unclassified" when no hint matches.
+func failureClass(e collector.Endpoint) string {
+	if h, ok := notify.MatchHint(notify.Hints(), e.Message, ""); ok {
+		return h.Class
+	}
+	return "unclassified"
+}
+
+// incidentLabel identifies the endpoint's down streak across restarts and
+// instances, so a streak is escalated once.
+func incidentLabel(e collector.Endpoint) string {
+	sum := sha1.Sum([]byte(e.Name + "|" + e.FirstSeenDown.UTC().Format(time.RFC3339)))
+	return "go-monitoring-" + hex.EncodeToString(sum[:6])
+}
+
+// ticketFor builds the ticket for the endpoint's failure: labelled with the
+// provider, failure class and incident, and describing the pool, the
+// failure, the alert text and the checks since now - escalationHistory.
+func ticketFor(e collector.Endpoint, history []collector.CheckRecord, rule string, now time.Time) jira.Issue {
+	class := failureClass(e)
+	network := config.NetworkName(e.Network)
+	solver := e.SolverName
+	if solver == "" {
+		solver = e.RouteSolver
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Provider:* %s\n", solver)
+	fmt.Fprintf(&b, "*Network:* %s (%s)\n", network, e.Network)
+	fmt.Fprintf(&b, "*Pool:* %s\n", orDash(e.ExpectedPool))
+	if e.PoolType != "" {
+		fmt.Fprintf(&b, "*Pool type:* %s\n", e.PoolType)
+	}
+	fmt.Fprintf(&b, "*Swap:* %s %s → %s\n", e.SwapAmount, e.TokenIn, e.TokenOut)
+	fmt.Fprintf(&b, "*Failure class:* %s\n", class)
+	fmt.Fprintf(&b, "*Status:* %s%s\n", e.LastStatus, e.DownForSuffix())
+	if rule != "" {
+		fmt.Fprintf(&b, "*Raised by rule:* %s\n", rule)
+	}
+	b.WriteString("\nh3. Latest failure\n{noformat}\n")
+	b.WriteString(notify.FormatEndpointAlert(&e, e.Message, ""))
+	b.WriteString("\n{noformat}\n")
+	b.WriteString("\nh3. Checks in the last 24h\n||Checked (UTC)||Status||Message||\n")
+	since := now.Add(-escalationHistory)
+	for i := len(history) - 1; i >= 0; i-- {
+		r := history[i]
+		if r.At.Before(since) {
+			break
+		}
+		fmt.Fprintf(&b, "|%s|%s|%s|\n", r.At.UTC().Format("2006-01-02 15:04"), r.Status, jiraCell(r.Message))
+	}
+	b.WriteString("\nevidence.json has the endpoint's state and these checks.\n")
+
+	return jira.Issue{
+		Project:     config.GetJiraProject(),
+		Type:        config.GetJiraIssueType(),
+		Summary:     fmt.Sprintf("[%s] %s on %s: %s", solver, e.BaseName, network, class),
+		Description: redact.String(b.String()),
+		Labels:      []string{"go-monitoring", "provider-" + jiraLabel(e.RouteSolver), "class-" + jiraLabel(class), incidentLabel(e)},
+	}
+}
+
+// evidenceFor renders the evidence.json attachment: the endpoint's persisted
+// state (status, message, quotes, route) and its checks since now -
+// escalationHistory.
+func evidenceFor(e collector.Endpoint, history []collector.CheckRecord, now time.Time) []byte {
+	since := now.Add(-escalationHistory)
+	var recent []collector.CheckRecord
+	for _, r := range history {
+		if !r.At.Before(since) {
+			recent = append(recent, r)
+		}
+	}
+	data, _ := json.MarshalIndent(struct {
+		Endpoint  store.EndpointState     `json:"endpoint"`
+		Provider  string                  `json:"provider"`
+		Network   string                  `json:"network"`
+		Pool      string                  `json:"pool"`
+		TokenIn   string                  `json:"tokenIn"`
+		TokenOut  string                  `json:"tokenOut"`
+		Amount    string                  `json:"amount"`
+		RequestID string                  `json:"requestId,omitempty"`
+		Checks    []collector.CheckRecord `json:"checks"`
+	}{store.StateOf(e), e.RouteSolver, e.Network, e.ExpectedPool, e.TokenIn, e.TokenOut, e.SwapAmount, e.RequestID(), recent}, "", "  ")
+	return data
+}
+
+// jiraLabel makes s usable as a Jira label: lower case, spaces replaced.
+func jiraLabel(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), " ", "_")
+}
+
+// jiraCell keeps a message from breaking a wiki markup table row.
+func jiraCell(s string) string {
+	s = strings.NewReplacer("|", "/", "\n", " ").Replace(s)
+	if s == "" {
+		return " "
+	}
+	return s
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
+
+// EscalationRule escalates failures automatically: a row of one of Solvers
+// (every solver when empty) whose failure is one of Classes (every class
+// when empty) and whose labels carry every pair in Match, once it has been
+// down for DownFor.
+type EscalationRule struct {
+	Name    string        `json:"name"`
+	Solvers []string      `json:"solvers,omitempty"`
+	Classes []string      `json:"classes,omitempty"`
+	Match   config.Labels `json:"match,omitempty"`
+	DownFor string        `json:"downFor,omitempty"` // Go duration, e.g. "6h"; empty escalates on the first failure
+
+	downFor time.Duration
+}
+
+// matches reports whether the rule escalates the endpoint at now.
+func (r EscalationRule) matches(e collector.Endpoint, now time.Time) bool {
+	if !collector.IsDownStatus(e.LastStatus) || e.DownFor(now) < r.downFor {
+		return false
+	}
+	if len(r.Solvers) > 0 && !containsFold(r.Solvers, e.RouteSolver) {
+		return false
+	}
+	if len(r.Classes) > 0 && !containsFold(r.Classes, failureClass(e)) {
+		return false
+	}
+	return len(r.Match) == 0 || e.Labels.Matches(r.Match)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	escalationRulesOnce sync.Once
+	escalationRules     []EscalationRule
+)
+
+// EscalationRules returns the rules from the JSON file named by
+// JIRA_RULES_FILE, or none. Loaded once per process.
+func EscalationRules() []EscalationRule {
+	escalationRulesOnce.Do(func() {
+		path := config.GetJiraRulesFile()
+		if path == "" {
+			return
+		}
+		loaded, err := loadEscalationRules(path)
+		if err != nil {
+			logs.Printf("%s[ERROR]%s: JIRA_RULES_FILE %s: %v; tickets are only raised by hand\n", config.ColorRed, config.ColorReset, path, err)
+			return
+		}
+		escalationRules = loaded
+	})
+	return escalationRules
+}
+
+// loadEscalationRules reads and validates a rules file. Every rule needs a
+// name; DownFor must parse.
+func loadEscalationRules(path string) ([]EscalationRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []EscalationRule
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		r := &out[i]
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if r.DownFor != "" {
+			if r.downFor, err = time.ParseDuration(r.DownFor); err != nil || r.downFor < 0 {
+				return nil, fmt.Errorf("rule %s: invalid downFor %q", r.Name, r.DownFor)
+			}
+		}
+		if err := r.Match.Validate(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+	}
+	return out, nil
+}
+
+// escalateByRules raises a ticket for each endpoint a rule matches, first
+// rule first, and returns the escalations made. Failures are logged and
+// retried after the next cycle.
+func escalateByRules(rules []EscalationRule, endpoints []collector.Endpoint, now time.Time) []Escalation {
+	if len(rules) == 0 || !JiraEnabled() {
+		return nil
+	}
+	var out []Escalation
+	for _, e := range endpoints {
+		if _, ok := GetEscalation(e); ok {
+			continue
+		}
+		for _, r := range rules {
+			if !r.matches(e, now) {
+				continue
+			}
+			esc, err := Escalate(e, r.Name)
+			if err != nil {
+				logs.Printf("%s[JIRA]%s %s: rule %s: %v\n", config.ColorRed, config.ColorReset, e.Name, r.Name, err)
+			} else {
+				out = append(out, esc)
+			}
+			break
+		}
+	}
+	return out
+}
+
+// checkEscalations applies the escalation rules after a cycle.
+func checkEscalations(now time.Time) {
+	endpoints := append(collector.GetEndpointsCopy(), collector.GetDiscoveredEndpointsCopy()...)
+	escalateByRules(EscalationRules(), endpoints, now)
+}
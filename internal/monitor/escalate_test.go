@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func failingEndpoint(downSince time.Time) collector.Endpoint {
+	return collector.Endpoint{Name: "GHO/USDC-KyberSwap", BaseName: "GHO/USDC", SolverName: "KyberSwap", RouteSolver: "kyberswap",
+		Network: "42161", ExpectedPool: "0xpool", TokenIn: "0xgho", TokenOut: "0xusdc", SwapAmount: "1000",
+		LastStatus: "down", FirstSeenDown: downSince, Message: "Unexpected source: uniswap-v3"}
+}
+
+func TestTicketFor(t *testing.T) {
+	t.Setenv("JIRA_PROJECT", "PARTNER")
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	e := failingEndpoint(now.Add(-3 * time.Hour))
+	history := []collector.CheckRecord{
+		{At: now.Add(-30 * time.Hour), Status: "down", Message: "too old"},
+		{At: now.Add(-2 * time.Hour), Status: "down", Message: "a | b"},
+		{At: now.Add(-time.Hour), Status: "down", Message: e.Message},
+	}
+
+	issue := ticketFor(e, history, "kyber", now)
+	if issue.Project != "PARTNER" || issue.Type != "Task" || issue.Summary != "[KyberSwap] GHO/USDC on arbitrum: wrong_source" {
+		t.Fatalf("issue = %+v", issue)
+	}
+	for _, want := range []string{"*Pool:* 0xpool", "*Failure class:* wrong_source", "*Raised by rule:* kyber",
+		"[GHO/USDC-KyberSwap] Unexpected source: uniswap-v3", "|2026-03-02 10:00|down|a / b|"} {
+		if !strings.Contains(issue.Description, want) {
+			t.Errorf("description lacks %q:\n%s", want, issue.Description)
+		}
+	}
+	if strings.Contains(issue.Description, "too old") {
+		t.Errorf("description has checks older than a day")
+	}
+	if len(issue.Labels) != 4 || issue.Labels[1] != "provider-kyberswap" || issue.Labels[2] != "class-wrong_source" || issue.Labels[3] != incidentLabel(e) {
+		t.Fatalf("labels = %q", issue.Labels)
+	}
+}
+
+func TestEscalateOncePerStreak(t *testing.T) {
+	t.Cleanup(func() { escalations = map[string]Escalation{} })
+	created := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			w.Write([]byte(`{"issues":[]}`))
+		case "/rest/api/2/issue":
+			created++
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]string{"key": "PARTNER-" + string(rune('0'+created))})
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("JIRA_BASE_URL", srv.URL)
+	t.Setenv("JIRA_PROJECT", "PARTNER")
+	t.Setenv("DRY_RUN", "false")
+
+	now := time.Now()
+	e := failingEndpoint(now.Add(-7 * time.Hour))
+	rules := []EscalationRule{
+		{Name: "slow", Classes: []string{"rate_limited"}},
+		{Name: "wrong source", Solvers: []string{"KyberSwap"}, Classes: []string{"wrong_source"}, downFor: 6 * time.Hour},
+	}
+	got := escalateByRules(rules, []collector.Endpoint{e}, now)
+	if len(got) != 1 || got[0].Key != "PARTNER-1" || got[0].Rule != "wrong source" {
+		t.Fatalf("escalations = %+v", got)
+	}
+	if again := escalateByRules(rules, []collector.Endpoint{e}, now); len(again) != 0 || created != 1 {
+		t.Fatalf("escalated the streak again: %+v", again)
+	}
+	if esc, err := Escalate(e, ""); err != nil || !esc.Existing || esc.Key != "PARTNER-1" {
+		t.Fatalf("manual escalation = %+v, %v", esc, err)
+	}
+
+	// A new streak gets its own ticket.
+	e.FirstSeenDown = now.Add(time.Minute)
+	if esc, err := Escalate(e, ""); err != nil || esc.Existing || esc.Key != "PARTNER-2" {
+		t.Fatalf("new streak escalation = %+v, %v", esc, err)
+	}
+
+	e.LastStatus = "up"
+	if _, err := Escalate(e, ""); err != ErrNotFailing {
+		t.Fatalf("escalating an up endpoint: %v", err)
+	}
+}
+
+func TestLoadEscalationRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	os.WriteFile(path, []byte(`[{"name":"kyber","solvers":["kyberswap"],"downFor":"6h","match":{"team":"integrations"}}]`), 0o644)
+	rules, err := loadEscalationRules(path)
+	if err != nil || len(rules) != 1 || rules[0].downFor != 6*time.Hour {
+		t.Fatalf("rules = %+v, %v", rules, err)
+	}
+	os.WriteFile(path, []byte(`[{"name":"kyber","downFor":"soon"}]`), 0o644)
+	if _, err := loadEscalationRules(path); err == nil || !strings.Contains(err.Error(), "invalid downFor") {
+		t.Fatalf("bad downFor error = %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/providers"
+)
+
+var (
+	exclusionMu sync.Mutex
+	// exclusionResults holds each endpoint's latest exclusion check, keyed
+	// by Endpoint.Name, for the solver page.
+	exclusionResults = map[string]providers.ExclusionResult{}
+)
+
+// RunExclusionCheck runs the exclusion check against every BaseEndpoints row
+// of an enabled provider that supports it each interval, starting
+// immediately: a quote with Balancer V3 excluded must not route through
+// Balancer V3, or the provider ignores its source filters and the
+// Balancer-only checks, which rely on them, prove nothing. Only the leader
+// checks; dry runs make no requests.
+func RunExclusionCheck(interval time.Duration) {
+	for {
+		if IsLeader() && !config.GetDryRunEnabled() {
+			checkExclusions(GlobalRegistry, enabledEndpoints(collector.GetEndpointsCopy()))
+		}
+		clock.Sleep(interval)
+	}
+}
+
+// enabledEndpoints returns the endpoints whose route solver is enabled.
+func enabledEndpoints(endpoints []collector.Endpoint) []collector.Endpoint {
+	enabled := map[string]bool{}
+	for _, s := range config.GetEnabledRouteSolvers() {
+		enabled[s.Type] = true
+	}
+	var out []collector.Endpoint
+	for _, e := range endpoints {
+		if enabled[e.RouteSolver] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// checkExclusions runs the exclusion check for each endpoint and returns the
+// alerts sent: critical when an endpoint starts failing, info when it passes
+// again. A request that fails says nothing about the filters and keeps the
+// previous outcome's alert state.
+func checkExclusions(reg *providers.Registry, endpoints []collector.Endpoint) []string {
+	var alerts []string
+	for _, e := range endpoints {
+		res, ok := reg.CheckExclusion(e)
+		if !ok {
+			continue
+		}
+		if msg, ok := recordExclusion(res); ok {
+			alerts = append(alerts, msg)
+		}
+	}
+	return alerts
+}
+
+// recordExclusion stores an exclusion check result and alerts when its
+// outcome changed, returning the alert.
+func recordExclusion(res providers.ExclusionResult) (string, bool) {
+	exclusionMu.Lock()
+	prev, seen := exclusionResults[res.Endpoint]
+	if res.Status == providers.ExclusionError && seen {
+		// Keep the last verdict, with this attempt's message.
+		prev.CheckedAt, prev.Message = res.CheckedAt, res.Message
+		res = prev
+	}
+	exclusionResults[res.Endpoint] = res
+	exclusionMu.Unlock()
+
+	wasFailing := seen && prev.Status == providers.ExclusionFail
+	switch {
+	case res.Status == providers.ExclusionFail && !wasFailing:
+		msg := fmt.Sprintf("Exclusion check failed for %s: quoted with %s excluded, %s. %s is ignoring its source filter parameters, so its Balancer-only checks can't be trusted",
+			res.Endpoint, strings.Join(res.Excluded, ", "), res.Message, res.Solver)
+		logs.Printf("%s[EXCLUSION CHECK]%s %s\n", config.ColorRed, config.ColorReset, msg)
+		notify.Send(notify.SeverityCritical, msg)
+		return msg, true
+	case res.Status == providers.ExclusionPass && wasFailing:
+		msg := fmt.Sprintf("Exclusion check passes again for %s: %s", res.Endpoint, res.Message)
+		logs.Printf("%s[EXCLUSION CHECK]%s %s\n", config.ColorGreen, config.ColorReset, msg)
+		notify.Send(notify.SeverityInfo, msg)
+		return msg, true
+	case res.Status == providers.ExclusionError:
+		logs.Printf("%s[EXCLUSION CHECK]%s %s: %s\n", config.ColorYellow, config.ColorReset, res.Endpoint, res.Message)
+	default:
+		logs.Printf("%s[EXCLUSION CHECK]%s %s: %s\n", config.ColorGreen, config.ColorReset, res.Endpoint, res.Message)
+	}
+	return "", false
+}
+
+// ExclusionResults returns the latest exclusion check of each of a route
+// solver's endpoints, ordered by endpoint name.
+func ExclusionResults(routeSolver string) []providers.ExclusionResult {
+	exclusionMu.Lock()
+	defer exclusionMu.Unlock()
+
+	var out []providers.ExclusionResult
+	for _, res := range exclusionResults {
+		if res.Solver == routeSolver {
+			out = append(out, res)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Endpoint < out[j].Endpoint })
+	return out
+}
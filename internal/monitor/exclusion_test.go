@@ -0,0 +1,39 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/providers"
+)
+
+func TestRecordExclusionAlertsOncePerFailure(t *testing.T) {
+	t.Cleanup(func() { exclusionResults = map[string]providers.ExclusionResult{} })
+	res := func(status, msg string) providers.ExclusionResult {
+		return providers.ExclusionResult{Endpoint: "KyberSwap-Stable", Solver: "kyberswap", CheckedAt: time.Now(),
+			Excluded: []string{"balancer-v3-stable"}, Status: status, Message: msg}
+	}
+
+	if _, alerted := recordExclusion(res(providers.ExclusionPass, "route avoids Balancer V3")); alerted {
+		t.Fatal("alerted on a pass")
+	}
+	msg, alerted := recordExclusion(res(providers.ExclusionFail, "route goes through excluded Balancer V3 sources: (balancer-v3-stable)"))
+	if !alerted || !strings.Contains(msg, "kyberswap is ignoring its source filter parameters") {
+		t.Fatalf("failure alert = %q", msg)
+	}
+	if _, alerted := recordExclusion(res(providers.ExclusionFail, "still failing")); alerted {
+		t.Fatal("re-alerted")
+	}
+	// A failed request keeps the verdict: no recovery alert, and the next
+	// pass still resolves the failure.
+	if _, alerted := recordExclusion(res(providers.ExclusionError, "HTTP 502")); alerted {
+		t.Fatal("alerted on an error")
+	}
+	if got := ExclusionResults("kyberswap"); len(got) != 1 || got[0].Status != providers.ExclusionFail || got[0].Message != "HTTP 502" {
+		t.Fatalf("results = %+v", got)
+	}
+	if msg, alerted := recordExclusion(res(providers.ExclusionPass, "route avoids Balancer V3")); !alerted || !strings.Contains(msg, "passes again") {
+		t.Fatalf("recovery alert = %q", msg)
+	}
+}
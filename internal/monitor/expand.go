@@ -1,11 +1,14 @@
 package monitor
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/collector"
+	"go-monitoring/monitoring/collector"
 )
 
 // ExpandInput is the network-agnostic shape consumed by ExpandForSolvers.
@@ -14,6 +17,7 @@ import (
 // supports the input's network.
 type ExpandInput struct {
 	BaseName         string
+	ID               string // BaseEndpoint.ID; empty derives one, see rowID
 	Network          string
 	TokenIn          string
 	TokenOut         string
@@ -24,7 +28,34 @@ type ExpandInput struct {
 	ExpectedNoHops   int
 	PoolType         string // empty for BaseEndpoints rows
 	HookType         string // empty for BaseEndpoints rows
-	Variant          string // "" for base / registered; "underlying" for the boosted underlying row
+	Variant          string // "" for base / registered; "underlying" for the boosted underlying row; "surge" for the hook-triggering row
+	Rules            []config.EndpointRule
+	Labels           config.Labels
+	Schedule         string
+}
+
+// BaseInputs converts BaseEndpoints-shaped configuration (config.BaseEndpoints
+// or an imported batch) to ExpandForSolvers inputs.
+func BaseInputs(bases []config.BaseEndpoint) []ExpandInput {
+	out := make([]ExpandInput, 0, len(bases))
+	for _, base := range bases {
+		out = append(out, ExpandInput{
+			BaseName:         base.Name,
+			ID:               base.ID,
+			Network:          base.Network,
+			TokenIn:          base.TokenIn,
+			TokenOut:         base.TokenOut,
+			TokenInDecimals:  base.TokenInDecimals,
+			TokenOutDecimals: base.TokenOutDecimals,
+			SwapAmount:       base.SwapAmount,
+			ExpectedPool:     base.ExpectedPool,
+			ExpectedNoHops:   base.ExpectedNoHops,
+			Rules:            base.Rules,
+			Labels:           base.Labels,
+			Schedule:         base.Schedule,
+		})
+	}
+	return out
 }
 
 // ExpandForSolvers cross-joins inputs with the enabled route solvers, keeping
@@ -39,37 +70,74 @@ func ExpandForSolvers(inputs []ExpandInput) []collector.Endpoint {
 	var out []collector.Endpoint
 	for _, in := range inputs {
 		for _, solver := range enabled {
-			supported := false
-			for _, n := range solver.SupportedNetworks {
-				if n == in.Network {
-					supported = true
-					break
-				}
-			}
-			if !supported {
-				continue
+			if e, ok := ExpandForSolver(in, solver); ok {
+				out = append(out, e)
 			}
-			out = append(out, collector.Endpoint{
-				Name:             fmt.Sprintf("%s-%s", solver.Name, in.BaseName),
-				BaseName:         in.BaseName,
-				SolverName:       solver.Name,
-				RouteSolver:      solver.Type,
-				Network:          in.Network,
-				TokenIn:          in.TokenIn,
-				TokenOut:         in.TokenOut,
-				TokenInDecimals:  in.TokenInDecimals,
-				TokenOutDecimals: in.TokenOutDecimals,
-				SwapAmount:       in.SwapAmount,
-				ExpectedPool:     in.ExpectedPool,
-				ExpectedNoHops:   in.ExpectedNoHops,
-				Delay:            config.GetRouteSolverDelay(solver.Type),
-				LastStatus:       "unknown",
-				LastChecked:      time.Time{},
-				Message:          "",
-				PoolType:         in.PoolType,
-				HookType:         in.HookType,
-				Variant:          in.Variant,
-			})
+		}
+	}
+	return out
+}
+
+// ExpandForSolver returns the input's row for one route solver, enabled or
+// not; ok is false when the solver doesn't support the input's network.
+func ExpandForSolver(in ExpandInput, solver config.RouteSolver) (collector.Endpoint, bool) {
+	supported := false
+	for _, n := range solver.SupportedNetworks {
+		if n == in.Network {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return collector.Endpoint{}, false
+	}
+	return collector.Endpoint{
+		Name:             fmt.Sprintf("%s-%s", solver.Name, in.BaseName),
+		ID:               rowID(in, solver.Type),
+		BaseName:         in.BaseName,
+		SolverName:       solver.Name,
+		RouteSolver:      solver.Type,
+		Network:          in.Network,
+		TokenIn:          in.TokenIn,
+		TokenOut:         in.TokenOut,
+		TokenInDecimals:  in.TokenInDecimals,
+		TokenOutDecimals: in.TokenOutDecimals,
+		SwapAmount:       in.SwapAmount,
+		ExpectedPool:     in.ExpectedPool,
+		ExpectedNoHops:   in.ExpectedNoHops,
+		Delay:            config.GetRouteSolverDelay(solver.Type),
+		LastStatus:       "unknown",
+		LastChecked:      time.Time{},
+		Message:          "",
+		PoolType:         in.PoolType,
+		HookType:         in.HookType,
+		Variant:          in.Variant,
+		Rules:            rulesForSolver(in.Rules, solver.Type),
+		Labels:           in.Labels,
+		Schedule:         in.Schedule,
+	}, true
+}
+
+// rowID returns a solver row's ID: the solver type, the network, then the
+// input's ID or, without one, a hash of what the row checks (pool, pair,
+// amount, variant and discovered pool type). The name plays no part, so
+// renaming a row keeps its ID.
+func rowID(in ExpandInput, solverType string) string {
+	id := in.ID
+	if id == "" {
+		sum := sha256.Sum256([]byte(strings.Join([]string{config.NormalizeAddress(in.ExpectedPool),
+			config.NormalizeAddress(in.TokenIn), config.NormalizeAddress(in.TokenOut), in.SwapAmount, in.Variant, in.PoolType}, "|")))
+		id = hex.EncodeToString(sum[:6])
+	}
+	return solverType + "-" + in.Network + "-" + id
+}
+
+// rulesForSolver keeps the rules that apply to the given route solver type.
+func rulesForSolver(rules []config.EndpointRule, solverType string) []config.EndpointRule {
+	var out []config.EndpointRule
+	for _, r := range rules {
+		if r.AppliesTo(solverType) {
+			out = append(out, r)
 		}
 	}
 	return out
@@ -0,0 +1,33 @@
+package monitor
+
+import (
+	"testing"
+
+	"go-monitoring/config"
+)
+
+func TestRowIDSurvivesRename(t *testing.T) {
+	in := ExpandInput{BaseName: "GHO-USDC", Network: "8453", TokenIn: "0xA", TokenOut: "0xB", ExpectedPool: "0xC", SwapAmount: "1000"}
+	id := rowID(in, "odos")
+
+	renamed := in
+	renamed.BaseName, renamed.ExpectedPool = "GHO/USDC", "0xc"
+	if got := rowID(renamed, "odos"); got != id {
+		t.Errorf("renamed row ID = %s, want %s", got, id)
+	}
+	other := in
+	other.SwapAmount = "2000"
+	if got := rowID(other, "odos"); got == id {
+		t.Errorf("another amount has the same ID %s", got)
+	}
+	if got := rowID(in, "paraswap"); got == id {
+		t.Errorf("another solver has the same ID %s", got)
+	}
+	in.ID = "gho-usdc"
+	if got := rowID(in, "odos"); got != "odos-8453-gho-usdc" {
+		t.Errorf("configured ID = %s", got)
+	}
+	if !config.ValidEndpointID(id) {
+		t.Errorf("derived ID %s is not a valid ID", id)
+	}
+}
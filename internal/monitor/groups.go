@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// groupCycle batches a check cycle's alerts per BaseName. Solver rows of a
+// BaseName are expanded (and so checked) one after another; while they are
+// checked their alerts are held, and when the cycle moves on the group's
+// composite status is stored and its alerts go out as one notification.
+type groupCycle struct {
+	current string
+	rows    func() []collector.Endpoint // the store the cycle checks
+	quiet   bool                        // drop the held alerts instead of sending them
+}
+
+func newGroupCycle(rows func() []collector.Endpoint) *groupCycle {
+	return &groupCycle{rows: rows}
+}
+
+// enter is called before checking a row of baseName.
+func (g *groupCycle) enter(baseName string) {
+	if baseName == g.current {
+		return
+	}
+	g.finish()
+	g.current = baseName
+	notify.HoldGroup(baseName)
+}
+
+// finish closes the current group, if any.
+func (g *groupCycle) finish() {
+	if g.current == "" {
+		return
+	}
+	status := collector.ComputeGroupStatus(g.current, g.rows(), clock.Now())
+	collector.SetGroupStatus(status)
+	if g.quiet {
+		notify.DropGroup(g.current)
+	} else {
+		notify.FlushGroup(status)
+	}
+	g.current = ""
+}
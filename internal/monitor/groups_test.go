@@ -0,0 +1,29 @@
+package monitor
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestGroupCycleStoresStatusPerBaseName(t *testing.T) {
+	rows := []collector.Endpoint{
+		{Name: "a-X", BaseName: "group-test-X", LastStatus: "up"},
+		{Name: "b-X", BaseName: "group-test-X", LastStatus: "down"},
+		{Name: "a-Y", BaseName: "group-test-Y", LastStatus: "up"},
+	}
+	g := newGroupCycle(func() []collector.Endpoint { return rows })
+	for _, r := range rows {
+		g.enter(r.BaseName)
+	}
+	if _, ok := collector.GetGroupStatus("group-test-Y"); ok {
+		t.Fatal("group stored before the cycle moved past it")
+	}
+	g.finish()
+
+	x, _ := collector.GetGroupStatus("group-test-X")
+	y, _ := collector.GetGroupStatus("group-test-Y")
+	if x.Status != collector.GroupPartial || x.Failing != 1 || y.Status != collector.GroupAllUp {
+		t.Fatalf("X = %+v, Y = %+v", x, y)
+	}
+}
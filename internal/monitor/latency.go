@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"slices"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+// latencyWindow is the span LatencyReport aggregates checks over.
+const latencyWindow = 24 * time.Hour
+
+// PhaseLatency is one phase of a provider's Balancer-only responses over the
+// window, in milliseconds, over the checks where the phase happened: DNS,
+// connect and TLS only count fresh connections.
+type PhaseLatency struct {
+	Phase   string `json:"phase"` // a collector.Phase* name
+	Samples int    `json:"samples"`
+	P50Ms   int64  `json:"p50Ms"`
+	P95Ms   int64  `json:"p95Ms"`
+}
+
+// LatencyBreakdown is a provider's response time split into phases over
+// latencyWindow from the checks of its BaseEndpoints rows, to tell network
+// trouble (DNS, connect, TLS) from a slow backend (first byte).
+type LatencyBreakdown struct {
+	Solver string         `json:"solver"` // RouteSolver.Type
+	Name   string         `json:"name"`
+	Window string         `json:"window"`
+	Checks int            `json:"checks"` // checks with measured phases
+	Phases []PhaseLatency `json:"phases"` // collector.LatencyPhaseNames order
+}
+
+// LatencyReport splits the solvers' response times into phases over the
+// last day at now, from the registered store or the in-memory history.
+func LatencyReport(solvers []config.RouteSolver, now time.Time) []LatencyBreakdown {
+	return latencyBreakdowns(solvers, collector.GetEndpointsCopy(), sloHistory, latencyWindow, now)
+}
+
+// latencyBreakdowns aggregates each solver's phases over window from the
+// history of its rows among endpoints.
+func latencyBreakdowns(solvers []config.RouteSolver, endpoints []collector.Endpoint, history sloHistoryFunc, window time.Duration, now time.Time) []LatencyBreakdown {
+	var out []LatencyBreakdown
+	for _, solver := range solvers {
+		b := LatencyBreakdown{Solver: solver.Type, Name: solver.Name, Window: formatWindow(window)}
+		samples := map[string][]time.Duration{}
+		for _, e := range endpoints {
+			if e.RouteSolver != solver.Type {
+				continue
+			}
+			for _, r := range history(e.Name, now.Add(-window)) {
+				if r.At.After(now) || r.ResponsePhases.IsZero() {
+					continue
+				}
+				b.Checks++
+				for _, name := range collector.LatencyPhaseNames {
+					if d := r.ResponsePhases.Phase(name); d > 0 {
+						samples[name] = append(samples[name], d)
+					}
+				}
+			}
+		}
+		for _, name := range collector.LatencyPhaseNames {
+			p := PhaseLatency{Phase: name, Samples: len(samples[name])}
+			if p.Samples > 0 {
+				slices.Sort(samples[name])
+				p.P50Ms = nearestRank(samples[name], 50).Milliseconds()
+				p.P95Ms = nearestRank(samples[name], 95).Milliseconds()
+			}
+			b.Phases = append(b.Phases, p)
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// nearestRank returns the nearest-rank p-th percentile of sorted, which
+// must not be empty.
+func nearestRank(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
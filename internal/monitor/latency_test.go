@@ -0,0 +1,63 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestLatencyBreakdowns(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	solvers := []config.RouteSolver{{Name: "KyberSwap", Type: "kyberswap"}, {Name: "Odos", Type: "odos"}}
+	endpoints := []collector.Endpoint{
+		{Name: "KyberSwap-A", RouteSolver: "kyberswap"},
+		{Name: "KyberSwap-B", RouteSolver: "kyberswap"},
+	}
+	fresh := collector.LatencyPhases{DNS: 5 * time.Millisecond, Connect: 20 * time.Millisecond, TLS: 40 * time.Millisecond,
+		TTFB: 600 * time.Millisecond, Read: 10 * time.Millisecond}
+	reused := collector.LatencyPhases{TTFB: 2 * time.Second, Read: 10 * time.Millisecond}
+	records := map[string][]collector.CheckRecord{
+		"KyberSwap-A": {
+			{At: now.Add(-30 * time.Hour), Status: "up", ResponsePhases: reused}, // outside the window
+			{At: now.Add(-3 * time.Hour), Status: "up", ResponsePhases: fresh},
+			{At: now.Add(-2 * time.Hour), Status: "down"}, // no response
+			{At: now.Add(-time.Hour), Status: "up", ResponsePhases: reused},
+		},
+		"KyberSwap-B": {{At: now, Status: "up", ResponsePhases: fresh}},
+	}
+	history := func(name string, since time.Time) []collector.CheckRecord {
+		var out []collector.CheckRecord
+		for _, r := range records[name] {
+			if !r.At.Before(since) {
+				out = append(out, r)
+			}
+		}
+		return out
+	}
+
+	got := latencyBreakdowns(solvers, endpoints, history, latencyWindow, now)
+	if len(got) != 2 {
+		t.Fatalf("breakdowns = %+v", got)
+	}
+	kyber := got[0]
+	if kyber.Window != "1d" || kyber.Checks != 3 || len(kyber.Phases) != len(collector.LatencyPhaseNames) {
+		t.Fatalf("kyberswap = %+v", kyber)
+	}
+	want := []PhaseLatency{
+		{Phase: collector.PhaseDNS, Samples: 2, P50Ms: 5, P95Ms: 5},
+		{Phase: collector.PhaseConnect, Samples: 2, P50Ms: 20, P95Ms: 20},
+		{Phase: collector.PhaseTLS, Samples: 2, P50Ms: 40, P95Ms: 40},
+		{Phase: collector.PhaseTTFB, Samples: 3, P50Ms: 600, P95Ms: 2000},
+		{Phase: collector.PhaseRead, Samples: 3, P50Ms: 10, P95Ms: 10},
+	}
+	for i, p := range want {
+		if kyber.Phases[i] != p {
+			t.Fatalf("phase %d = %+v, want %+v", i, kyber.Phases[i], p)
+		}
+	}
+	if odos := got[1]; odos.Checks != 0 || odos.Phases[0].Samples != 0 {
+		t.Fatalf("odos = %+v", odos)
+	}
+}
@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/leader"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/store"
+)
+
+// elector decides whether this instance runs the check loops. Nil (the
+// default, a single instance) always leads.
+var elector leader.Elector
+
+// SetElector enables leader election. Call before starting the loops.
+func SetElector(e leader.Elector) {
+	elector = e
+}
+
+// IsLeader reports whether this instance should run provider checks and send
+// scheduled alerts. Followers only serve the dashboard.
+func IsLeader() bool {
+	return elector == nil || elector.IsLeader()
+}
+
+// SyncFromStore refreshes a follower's results and history from the shared
+// store every interval, so its dashboard shows the leader's checks.
+func SyncFromStore(interval time.Duration) {
+	ticker := clock.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if IsLeader() {
+			continue
+		}
+		stateStoreMu.Lock()
+		st := stateStore
+		stateStoreMu.Unlock()
+		if st == nil {
+			continue
+		}
+		snap, err := store.LoadSnapshot(st, clock.Now())
+		if err != nil {
+			logs.Printf("%s[FOLLOWER]%s sync from store failed: %v\n", config.ColorRed, config.ColorReset, err)
+			continue
+		}
+		store.Restore(snap)
+	}
+}
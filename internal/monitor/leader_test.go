@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+type staticElector bool
+
+func (s staticElector) IsLeader() bool { return bool(s) }
+
+func TestFollowerSkipsCycles(t *testing.T) {
+	SetElector(staticElector(false))
+	t.Cleanup(func() { SetElector(nil) })
+	collector.SetEndpoints([]collector.Endpoint{{Name: "X", RouteSolver: "nope", LastStatus: "unknown"}})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	checkAllEndpoints()
+	if e := collector.GetEndpointByName("X"); e.LastStatus != "unknown" || !e.LastChecked.IsZero() {
+		t.Fatalf("follower checked endpoint: %+v", e)
+	}
+}
+
+func TestIsLeaderWithoutElector(t *testing.T) {
+	SetElector(nil)
+	if !IsLeader() {
+		t.Fatal("a single instance should lead")
+	}
+	SetElector(staticElector(true))
+	t.Cleanup(func() { SetElector(nil) })
+	if !IsLeader() {
+		t.Fatal("elected instance should lead")
+	}
+}
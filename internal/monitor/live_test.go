@@ -0,0 +1,97 @@
+//go:build live
+
+package monitor
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+// Live provider suite. Excluded from the default build; run with
+//
+//	go test -tags live -run TestLiveProviders ./internal/monitor
+//
+// Each route solver quotes a tiny Balancer-only swap against the first
+// BaseEndpoint it supports, using real API keys from the environment, through
+// the same registry path the monitor uses. Solvers whose API key is not set
+// are skipped. Email alerts are forced off for the run.
+
+// liveAmountDivisor shrinks BaseEndpoint swap amounts to a tiny quote.
+const liveAmountDivisor = 1000
+
+func TestLiveProviders(t *testing.T) {
+	t.Setenv("EMAIL_NOTIFICATIONS", "false")
+	t.Setenv("DRY_RUN", "false")
+	InitializeRegistry()
+
+	for _, solver := range config.RouteSolvers {
+		solver := solver
+		t.Run(solver.Type, func(t *testing.T) {
+			providerConfig, ok := GlobalRegistry.Lookup(solver.Type)
+			if !ok {
+				t.Fatalf("solver %q is not registered", solver.Type)
+			}
+			if providerConfig.APIKeyEnvVar != "" && os.Getenv(providerConfig.APIKeyEnvVar) == "" {
+				t.Skipf("%s not set", providerConfig.APIKeyEnvVar)
+			}
+
+			endpoint, ok := liveEndpointFor(solver)
+			if !ok {
+				t.Skipf("no BaseEndpoint on a network %s supports", solver.Name)
+			}
+
+			balancerOnly := true
+			GlobalRegistry.Check(&endpoint, &providers.CheckOptions{IsBalancerSourceOnly: &balancerOnly})
+
+			switch endpoint.LastStatus {
+			case "up":
+				t.Logf("%s: up, returnAmount=%s", endpoint.Name, endpoint.ReturnAmount)
+			case "info", "unsupported":
+				t.Skipf("%s: %s (%s)", endpoint.Name, endpoint.LastStatus, endpoint.Message)
+			default:
+				t.Fatalf("%s: status %q: %s", endpoint.Name, endpoint.LastStatus, endpoint.Message)
+			}
+		})
+	}
+}
+
+// liveEndpointFor expands the first BaseEndpoint on a network the solver
+// supports, with the swap amount scaled down to a tiny quote.
+func liveEndpointFor(solver config.RouteSolver) (collector.Endpoint, bool) {
+	for _, base := range config.BaseEndpoints {
+		for _, network := range solver.SupportedNetworks {
+			if network != base.Network {
+				continue
+			}
+			amount, ok := new(big.Int).SetString(base.SwapAmount, 10)
+			if !ok {
+				continue
+			}
+			amount.Quo(amount, big.NewInt(liveAmountDivisor))
+			if amount.Sign() == 0 {
+				amount.SetInt64(1)
+			}
+			return collector.Endpoint{
+				Name:             solver.Name + "-" + base.Name,
+				BaseName:         base.Name,
+				SolverName:       solver.Name,
+				RouteSolver:      solver.Type,
+				Network:          base.Network,
+				TokenIn:          base.TokenIn,
+				TokenOut:         base.TokenOut,
+				TokenInDecimals:  base.TokenInDecimals,
+				TokenOutDecimals: base.TokenOutDecimals,
+				SwapAmount:       amount.String(),
+				ExpectedPool:     base.ExpectedPool,
+				ExpectedNoHops:   base.ExpectedNoHops,
+				LastStatus:       "unknown",
+			}, true
+		}
+	}
+	return collector.Endpoint{}, false
+}
@@ -0,0 +1,28 @@
+package monitor
+
+import (
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/rules"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// checkMarketLead alerts when one of the endpoint's MarketLeadCycles rules
+// triggers on its history, records ending with this check. Like
+// checkPriceImpact it alerts once per excursion and leaves the status alone:
+// the pool still routes, it is just no longer the best price for the pair.
+func checkMarketLead(endpoint *collector.Endpoint, records []collector.CheckRecord) {
+	failure := rules.MarketLead(endpoint, records)
+	if failure == "" {
+		endpoint.MarketLeadAlerted = false
+		return
+	}
+	if endpoint.MarketLeadAlerted {
+		return
+	}
+	endpoint.MarketLeadAlerted = true
+	message := "Rule failed: " + failure
+	logs.Printf("%s[RULE]%s %s: %s\n", config.ColorYellow, config.ColorReset, endpoint.Name, message)
+	notify.SendEndpointAlert(endpoint, message, "")
+}
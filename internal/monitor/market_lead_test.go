@@ -0,0 +1,27 @@
+package monitor
+
+import (
+	"testing"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestCheckMarketLeadAlertsOncePerExcursion(t *testing.T) {
+	e := &collector.Endpoint{Name: "x", RouteSolver: "paraswap", Rules: []config.EndpointRule{{MarketLeadCycles: 2}}}
+	lead := collector.CheckRecord{Status: "up", ReturnAmount: "990", MarketPrice: "1000"}
+	even := collector.CheckRecord{Status: "up", ReturnAmount: "1000", MarketPrice: "1000"}
+
+	checkMarketLead(e, []collector.CheckRecord{lead, lead})
+	if !e.MarketLeadAlerted {
+		t.Fatal("expected alert after 2 consecutive checks")
+	}
+	checkMarketLead(e, []collector.CheckRecord{lead, lead, lead})
+	if !e.MarketLeadAlerted {
+		t.Fatal("expected alert to stay set while the streak lasts")
+	}
+	checkMarketLead(e, []collector.CheckRecord{lead, lead, even})
+	if e.MarketLeadAlerted {
+		t.Fatal("expected alert to re-arm once Balancer is competitive again")
+	}
+}
@@ -1,47 +1,201 @@
 package monitor
 
 import (
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"go-monitoring/internal/collector"
+	"go-monitoring/config"
+	"go-monitoring/internal/chaos"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
 )
 
-// CheckAPI checks API status based on route solver
-func CheckAPI(endpoint *collector.Endpoint, options *CheckOptions) {
-	GlobalRegistry.CheckProvider(endpoint, options)
+// CheckAPI checks API status based on route solver, records any status
+// transition so the dashboard and alerts can report outage durations, and
+// appends the result to the endpoint's check history and the store. An up
+// check confirms its provider's support for the pool kind (recordSupport).
+// It returns the check's result.
+func CheckAPI(endpoint *collector.Endpoint, options *providers.CheckOptions) providers.CheckResult {
+	return checkRow(endpoint, options, true)
 }
 
-// MonitorAPIs periodically checks API status
-func MonitorAPIs(checkIntervalHours int) {
-	ticker := time.NewTicker(time.Duration(checkIntervalHours) * time.Hour)
+// checkRow is CheckAPI. A check that isn't a sample (a down row's recheck)
+// only updates the endpoint's latest state: uptime counts checks, so it is
+// kept out of the history, where it would weigh outages more than the
+// regular checks around them.
+func checkRow(endpoint *collector.Endpoint, options *providers.CheckOptions, sample bool) providers.CheckResult {
+	prevStatus, prevDownSince := endpoint.LastStatus, endpoint.FirstSeenDown
+	res := GlobalRegistry.Check(endpoint, options)
+	recordSurgeState(endpoint, clock.Now())
+	checkPriceImpact(endpoint, config.GetPriceImpactAlertBps())
+	checkBalancerRank(endpoint, config.GetBalancerRankAlertTopN())
+	checkSpender(endpoint, providers.Spenders())
+	now := clock.Now()
+	endpoint.RecordStatusChange(prevStatus, now)
+	if sample {
+		collector.RecordCheck(endpoint.Name, collector.CheckRecord{At: now, Status: endpoint.LastStatus, Message: endpoint.Message,
+			ReturnAmount: endpoint.ReturnAmount, MarketPrice: endpoint.MarketPrice, HTTPStatus: endpoint.HTTPStatus, RequestID: endpoint.RequestID(),
+			ResponseTime: endpoint.ResponseTime, ResponsePhases: endpoint.ResponsePhases, Surge: endpoint.Surge})
+	}
+	history := collector.GetHistory(endpoint.Name)
+	checkQuoteSpread(endpoint, history, config.GetQuoteSpreadAlertBps(), config.GetQuoteSpreadAlertChecks())
+	checkMarketLead(endpoint, history)
+	saveResult(endpoint, prevStatus, prevDownSince, now, sample)
+	recordSupport(endpoint, now)
+	return res
+}
+
+// cycleCheckOptions returns the options for one check cycle of endpoints:
+// both calls per endpoint, with market prices shared between endpoints
+// quoting the same provider, network, pair and amount, and Balancer SOR
+// queries batched SOR_BATCH_SIZE to a request.
+func cycleCheckOptions(endpoints []collector.Endpoint) *providers.CheckOptions {
+	return &providers.CheckOptions{
+		MarketPrices: providers.NewMarketPriceCache(),
+		SORBatch:     providers.NewSORBatch(endpoints, config.GetSORBatchSize()),
+	}
+}
+
+// reportMarketPriceHits logs how many market price calls and Balancer SOR
+// requests the cycle saved.
+func reportMarketPriceHits(options *providers.CheckOptions) {
+	if hits := options.MarketPrices.Hits(); hits > 0 {
+		logs.Printf("%s[MARKET PRICE]%s reused %d market price quotes this cycle\n", config.ColorGreen, config.ColorReset, hits)
+	}
+	if served := options.SORBatch.Served(); served > 0 {
+		logs.Printf("%s[SOR BATCH]%s answered %d Balancer SOR requests from batches this cycle\n", config.ColorGreen, config.ColorReset, served)
+	}
+}
+
+// reportCallCacheHits logs how many eth_calls were answered from the call
+// cache since the last report.
+func reportCallCacheHits() {
+	if hits := providers.TakeCallCacheHits(); hits > 0 {
+		logs.Printf("%s[ON-CHAIN QUERY]%s reused %d eth_call results this cycle\n", config.ColorGreen, config.ColorReset, hits)
+	}
+}
+
+// MonitorAPIs periodically checks API status every interval
+// (CHECK_INTERVAL_HOURS). The first cycle runs after firstCycleDelay (zero:
+// immediately), so a deploy doesn't have to burst every provider at once.
+// Rows with a Schedule are left to RunScheduled.
+func MonitorAPIs(interval, firstCycleDelay time.Duration) {
+	if firstCycleDelay > 0 {
+		logs.Printf("%s[STARTUP]%s first check cycle in %s\n", config.ColorYellow, config.ColorReset, firstCycleDelay)
+		clock.Sleep(firstCycleDelay)
+	}
+
+	ticker := clock.NewTicker(interval)
 	defer ticker.Stop()
 
-	// Perform initial checks immediately
-	checkAllEndpoints()
+	checkAll()
 
 	// Check all endpoints when ticker triggers
 	for range ticker.C {
-		checkAllEndpoints()
+		checkAll()
 	}
 }
 
-// checkAllEndpoints performs API checks for all endpoints with minimal mutex locking
+// checkAll runs one MonitorAPIs cycle; swapped in tests.
+var checkAll = checkAllEndpoints
+
+// checkAllEndpoints performs API checks for all endpoints without their own
+// Schedule, with minimal mutex locking
 func checkAllEndpoints() {
 	// Get a copy of endpoints to iterate over
-	endpoints := collector.GetEndpointsCopy()
+	all := collector.GetEndpointsCopy()
+	if len(all) == 0 {
+		logs.Printf("%s[WARN]%s no endpoints configured (all route solvers disabled or no BaseEndpoints); skipping cycle\n",
+			config.ColorYellow, config.ColorReset)
+		return
+	}
+	var endpoints []collector.Endpoint
+	for _, e := range all {
+		if rowSchedule(e) == nil {
+			endpoints = append(endpoints, e)
+		}
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+	if !IsLeader() {
+		logs.Printf("%s[FOLLOWER]%s another instance holds the scheduler lock; skipping cycle\n", config.ColorBlue, config.ColorReset)
+		return
+	}
+	baseCycleRunning.Store(true)
+	defer baseCycleRunning.Store(false)
+	checkCycle("base", endpoints, false)
+}
+
+// baseCycleRunning is set while checkAllEndpoints checks; down-endpoint
+// rechecks wait for it, as it checks every row anyway.
+var baseCycleRunning atomic.Bool
+
+// checkCycle checks BaseEndpoints rows one after another as the named cycle,
+// then persists and reports the results. A recheck cycle updates statuses
+// and group status but sends no endpoint alerts and adds nothing to the
+// history (checkRow).
+func checkCycle(name string, endpoints []collector.Endpoint, recheck bool) {
+	stats := startCycle(name)
 
 	// Do the actual API checks outside the lock. Each row is wrapped in
 	// safeCheck so a panic in one provider handler doesn't kill the sweep
 	// for the remaining rows.
+	options := cycleCheckOptions(endpoints)
+	groups := newGroupCycle(collector.GetEndpointsCopy)
+	groups.quiet = recheck
 	for _, endpoint := range endpoints {
 		name := endpoint.Name
+		delay, skip := chaos.Active().BeforeCheck(endpoint.RouteSolver)
+		if skip {
+			logs.Printf("%s[CHAOS]%s %s: check skipped this cycle\n", config.ColorOrange, config.ColorReset, name)
+			continue
+		}
+		clock.Sleep(delay)
+		started := clock.Now()
+		groups.enter(endpoint.BaseName)
 		safeCheck(name, func() {
 			collector.UpdateEndpointByName(name, func(endpoint *collector.Endpoint) {
 				// Make both calls: Balancer-only and market price
-				CheckAPI(endpoint, nil) // nil options will trigger both calls
+				checkRow(endpoint, options, !recheck)
 			})
 		})
+		if checked := collector.GetEndpointByName(name); checked != nil {
+			stats.record(*checked, clock.Now().Sub(started))
+		}
 		// Add delay between each endpoint check based on endpoint's configured delay
-		time.Sleep(endpoint.Delay)
+		sleepBetweenChecks(endpoint.Delay)
 	}
+	groups.finish()
+	reportMarketPriceHits(options)
+	reportCallCacheHits()
+	stats.finish()
+	saveState()
+	checkSLOs(clock.Now())
+	checkEscalations(clock.Now())
+	if report := getCycleReporter(); report != nil {
+		report(collector.GetEndpointsCopy())
+	}
+}
+
+var (
+	cycleReporterMu sync.Mutex
+	cycleReporter   func([]collector.Endpoint)
+)
+
+// SetCycleReporter registers a callback given the BaseEndpoints results after
+// each cycle, e.g. a regional worker's report to the central instance.
+func SetCycleReporter(fn func([]collector.Endpoint)) {
+	cycleReporterMu.Lock()
+	defer cycleReporterMu.Unlock()
+	cycleReporter = fn
+}
+
+func getCycleReporter() func([]collector.Endpoint) {
+	cycleReporterMu.Lock()
+	defer cycleReporterMu.Unlock()
+	return cycleReporter
 }
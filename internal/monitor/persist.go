@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+)
+
+var (
+	stateStoreMu sync.Mutex
+	stateStore   store.Store
+)
+
+// SetStore registers the store each check result is saved to, and moves
+// its rows when a row is renamed (collector.RenameEndpoint). Nil (the
+// default) disables persistence.
+func SetStore(s store.Store) {
+	stateStoreMu.Lock()
+	defer stateStoreMu.Unlock()
+	stateStore = s
+	if s == nil {
+		collector.SetRenameHandler(nil)
+		return
+	}
+	collector.SetRenameHandler(func(old, name string) {
+		if err := s.RenameEndpoint(old, name); err != nil {
+			logs.Printf("%s[STORE]%s %s: rename to %s failed: %v\n", config.ColorRed, config.ColorReset, old, name, err)
+		}
+	})
+}
+
+// GetStore returns the registered store, or nil when persistence is off.
+func GetStore() store.Store {
+	stateStoreMu.Lock()
+	defer stateStoreMu.Unlock()
+	return stateStore
+}
+
+// saveResult persists a completed check, as history too when it is a sample
+// (checkRow), and, when the check moved the endpoint into or out of a down
+// status, the incident. prevStatus and prevDownSince are the endpoint's
+// LastStatus / FirstSeenDown before the check. Failures are logged; the
+// in-memory state is unaffected.
+func saveResult(e *collector.Endpoint, prevStatus string, prevDownSince, now time.Time, sample bool) {
+	stateStoreMu.Lock()
+	defer stateStoreMu.Unlock()
+	if stateStore == nil {
+		return
+	}
+	// Stamp the completion time, as the in-memory history does: not every
+	// failure path (e.g. a missing API key) updates LastChecked.
+	st := store.StateOf(*e)
+	st.LastChecked = now
+	save := stateStore.SaveResult
+	if !sample {
+		save = stateStore.SaveLatest
+	}
+	if err := save(st); err != nil {
+		logs.Printf("%s[STORE]%s %s: save result failed: %v\n", config.ColorRed, config.ColorReset, e.Name, err)
+	}
+	if inc, ok := incidentFor(e, prevStatus, prevDownSince, now); ok {
+		if err := stateStore.SaveIncident(inc); err != nil {
+			logs.Printf("%s[STORE]%s %s: save incident failed: %v\n", config.ColorRed, config.ColorReset, e.Name, err)
+		}
+	}
+}
+
+// SaveNote sets an endpoint's note and saves it to the registered store, if
+// any. The in-memory note is updated even when saving fails.
+func SaveNote(n collector.Note) error {
+	collector.SetNote(n)
+	stateStoreMu.Lock()
+	defer stateStoreMu.Unlock()
+	if stateStore == nil {
+		return nil
+	}
+	return stateStore.SaveNote(n)
+}
+
+// incidentFor returns the incident to open or resolve after a check, if any.
+func incidentFor(e *collector.Endpoint, prevStatus string, prevDownSince, now time.Time) (store.Incident, bool) {
+	wasDown, isDown := collector.IsDownStatus(prevStatus), collector.IsDownStatus(e.LastStatus)
+	switch {
+	case isDown && !wasDown:
+		return store.Incident{Endpoint: e.Name, Status: e.LastStatus, Message: e.Message, StartedAt: e.FirstSeenDown}, true
+	case wasDown && !isDown && !prevDownSince.IsZero():
+		return store.Incident{Endpoint: e.Name, Status: prevStatus, StartedAt: prevDownSince, ResolvedAt: now}, true
+	}
+	return store.Incident{}, false
+}
+
+// saveState flushes a buffering store after a cycle; failures are logged and
+// the next cycle tries again.
+func saveState() {
+	stateStoreMu.Lock()
+	defer stateStoreMu.Unlock()
+	f, ok := stateStore.(store.Flusher)
+	if !ok {
+		return
+	}
+	if err := f.Flush(); err != nil {
+		logs.Printf("%s[STORE]%s save failed: %v\n", config.ColorRed, config.ColorReset, err)
+	}
+}
@@ -0,0 +1,30 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestIncidentFor(t *testing.T) {
+	now := time.Now()
+	start := now.Add(-2 * time.Hour)
+
+	e := &collector.Endpoint{Name: "X", LastStatus: "down", Message: "no route", FirstSeenDown: now}
+	inc, ok := incidentFor(e, "up", time.Time{}, now)
+	if !ok || !inc.StartedAt.Equal(now) || !inc.ResolvedAt.IsZero() || inc.Message != "no route" {
+		t.Fatalf("opening incident = %+v, %v", inc, ok)
+	}
+
+	e = &collector.Endpoint{Name: "X", LastStatus: "up"}
+	inc, ok = incidentFor(e, "error", start, now)
+	if !ok || !inc.StartedAt.Equal(start) || !inc.ResolvedAt.Equal(now) {
+		t.Fatalf("resolving incident = %+v, %v", inc, ok)
+	}
+
+	e = &collector.Endpoint{Name: "X", LastStatus: "down", FirstSeenDown: start}
+	if _, ok := incidentFor(e, "down", start, now); ok {
+		t.Fatal("continuing outage should not produce an incident")
+	}
+}
@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/sources"
+)
+
+// Balancer V3 pool types of the coverage summary, in its column order. They
+// are finer than the sources.Kind* pool kinds providers split sources by:
+// StableSurge is a Stable pool with the surge hook, and Boosted applies on
+// top of another type.
+const (
+	PoolTypeStable      = "Stable"
+	PoolTypeStableSurge = "StableSurge"
+	PoolTypeGyroE       = "GyroE"
+	PoolTypeQuantAMM    = "QuantAMM"
+	PoolTypeReCLAMM     = "reCLAMM"
+	PoolTypeWeighted    = "Weighted"
+	PoolTypeLBP         = "LBP"
+	PoolTypeBoosted     = "Boosted"
+)
+
+// PoolTypes lists the coverage summary's pool types in order.
+var PoolTypes = []string{PoolTypeStable, PoolTypeStableSurge, PoolTypeGyroE, PoolTypeQuantAMM, PoolTypeReCLAMM, PoolTypeWeighted, PoolTypeLBP, PoolTypeBoosted}
+
+// PoolTypesOf returns the summary pool types of an endpoint's pool, from the
+// Balancer API pool and hook types of discovered rows or the names of
+// BaseEndpoints rows (Arbitrum-Boosted-StableSurge(GHO/USDC) is both
+// StableSurge and Boosted). Empty for other pools, e.g. Gyro 2-CLP.
+func PoolTypesOf(e *collector.Endpoint) []string {
+	var types []string
+	surge := strings.Contains(strings.ToUpper(e.HookType), "STABLE_SURGE") || strings.Contains(e.Name, "StableSurge")
+	switch sources.KindOf(e) {
+	case sources.KindStable:
+		if surge {
+			types = append(types, PoolTypeStableSurge)
+		} else {
+			types = append(types, PoolTypeStable)
+		}
+	case sources.KindGyro:
+		if strings.Contains(strings.ToUpper(e.PoolType), "GYROE") || strings.Contains(e.Name, "GyroE") {
+			types = append(types, PoolTypeGyroE)
+		}
+	case sources.KindQuantAMM:
+		types = append(types, PoolTypeQuantAMM)
+	case sources.KindReCLAMM:
+		types = append(types, PoolTypeReCLAMM)
+	case sources.KindWeighted:
+		types = append(types, PoolTypeWeighted)
+	case sources.KindLBP:
+		types = append(types, PoolTypeLBP)
+	}
+	// Discovered boosted pools have a -boosted row and an -underlying one.
+	if strings.Contains(strings.ToLower(e.BaseName+" "+e.Name), "boosted") || e.Variant == "underlying" {
+		types = append(types, PoolTypeBoosted)
+	}
+	return types
+}
+
+// PoolTypeCoverage is where a provider quotes one pool type: the networks
+// (chain IDs) where a row of that type passes its latest check, and those
+// where rows are checked but none passes. Both are sorted and never nil.
+type PoolTypeCoverage struct {
+	PoolType string   `json:"poolType"`
+	Passing  []string `json:"passing"`
+	Failing  []string `json:"failing"`
+}
+
+// SolverCoverage is a provider's row of the coverage summary, one entry per
+// pool type in PoolTypes order.
+type SolverCoverage struct {
+	Solver    string             `json:"solver"`
+	Name      string             `json:"name"`
+	PoolTypes []PoolTypeCoverage `json:"poolTypes"`
+}
+
+// CoverageSummary builds each solver's pool type coverage from the latest
+// checks of endpoints. Rows not checked yet, or whose status is neither up
+// nor down (WIP, n/a), count for neither list.
+func CoverageSummary(solvers []config.RouteSolver, endpoints []collector.Endpoint) []SolverCoverage {
+	out := make([]SolverCoverage, 0, len(solvers))
+	for _, s := range solvers {
+		passing := map[string]map[string]bool{}
+		failing := map[string]map[string]bool{}
+		for i := range endpoints {
+			e := &endpoints[i]
+			if e.RouteSolver != s.Type {
+				continue
+			}
+			var into map[string]map[string]bool
+			switch {
+			case e.LastStatus == "up":
+				into = passing
+			case collector.IsDownStatus(e.LastStatus):
+				into = failing
+			default:
+				continue
+			}
+			for _, t := range PoolTypesOf(e) {
+				if into[t] == nil {
+					into[t] = map[string]bool{}
+				}
+				into[t][e.Network] = true
+			}
+		}
+		sc := SolverCoverage{Solver: s.Type, Name: s.Name, PoolTypes: make([]PoolTypeCoverage, 0, len(PoolTypes))}
+		for _, t := range PoolTypes {
+			c := PoolTypeCoverage{PoolType: t, Passing: sortedKeys(passing[t]), Failing: []string{}}
+			for _, n := range sortedKeys(failing[t]) {
+				if !slices.Contains(c.Passing, n) {
+					c.Failing = append(c.Failing, n)
+				}
+			}
+			sc.PoolTypes = append(sc.PoolTypes, c)
+		}
+		out = append(out, sc)
+	}
+	return out
+}
+
+// sortedKeys returns a set's members sorted, empty rather than nil.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
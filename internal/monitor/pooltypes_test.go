@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestPoolTypesOf(t *testing.T) {
+	for _, tc := range []struct {
+		e    collector.Endpoint
+		want []string
+	}{
+		{collector.Endpoint{Name: "Arbitrum-Boosted-StableSurge(GHO/USDC)"}, []string{PoolTypeStableSurge, PoolTypeBoosted}},
+		{collector.Endpoint{Name: "Gnosis-Boosted-Stable(WETH/wstETH)"}, []string{PoolTypeStable, PoolTypeBoosted}},
+		{collector.Endpoint{Name: "Avax-Boosted-GyroE(BTC.b/wAVAX)"}, []string{PoolTypeGyroE, PoolTypeBoosted}},
+		{collector.Endpoint{Name: "Base-reCLAMM(WETH/USDC)"}, []string{PoolTypeReCLAMM}},
+		{collector.Endpoint{Name: "Weighted(BAL/WETH)"}, []string{PoolTypeWeighted}},
+		{collector.Endpoint{PoolType: "LIQUIDITY_BOOTSTRAPPING"}, []string{PoolTypeLBP}},
+		{collector.Endpoint{BaseName: "base-stable-stable_surge-gho-usdc-0x1234", PoolType: "STABLE", HookType: "STABLE_SURGE"}, []string{PoolTypeStableSurge}},
+		{collector.Endpoint{BaseName: "base-gyroe-nohook-weth-usdc-0x1234-underlying", PoolType: "GYROE", Variant: "underlying"}, []string{PoolTypeGyroE, PoolTypeBoosted}},
+		{collector.Endpoint{PoolType: "GYRO"}, nil}, // 2-CLP
+		{collector.Endpoint{PoolType: "QUANT_AMM_WEIGHTED"}, []string{PoolTypeQuantAMM}},
+	} {
+		if got := PoolTypesOf(&tc.e); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("PoolTypesOf(%+v) = %v, want %v", tc.e, got, tc.want)
+		}
+	}
+}
+
+func TestCoverageSummary(t *testing.T) {
+	solvers := []config.RouteSolver{{Type: "kyberswap", Name: "KyberSwap"}}
+	endpoints := []collector.Endpoint{
+		{Name: "KyberSwap-Base-Boosted-StableSurge(GHO/USDC)", RouteSolver: "kyberswap", Network: "8453", LastStatus: "up"},
+		{Name: "KyberSwap-Arbitrum-Boosted-StableSurge(GHO/USDC)", RouteSolver: "kyberswap", Network: "42161", LastStatus: "down"},
+		{Name: "KyberSwap-Arbitrum-Boosted-Stable(WETH/WSTETH)", RouteSolver: "kyberswap", Network: "42161", LastStatus: "up"},
+		{Name: "KyberSwap-Avax-Boosted-GyroE(BTC.b/wAVAX)", RouteSolver: "kyberswap", Network: "43114", LastStatus: "info"},
+		{Name: "Odos-Base-Boosted-StableSurge(GHO/USDC)", RouteSolver: "odos", Network: "8453", LastStatus: "up"},
+	}
+	got := CoverageSummary(solvers, endpoints)
+	if len(got) != 1 || got[0].Solver != "kyberswap" || got[0].Name != "KyberSwap" || len(got[0].PoolTypes) != len(PoolTypes) {
+		t.Fatalf("summary = %+v", got)
+	}
+	byType := map[string]PoolTypeCoverage{}
+	for _, c := range got[0].PoolTypes {
+		byType[c.PoolType] = c
+	}
+	for typ, want := range map[string]PoolTypeCoverage{
+		PoolTypeStable:      {Passing: []string{"42161"}, Failing: []string{}},
+		PoolTypeStableSurge: {Passing: []string{"8453"}, Failing: []string{"42161"}},
+		// The Boosted Stable row passes on Arbitrum, so the failing
+		// StableSurge row there doesn't make Boosted fail.
+		PoolTypeBoosted: {Passing: []string{"42161", "8453"}, Failing: []string{}},
+		// A WIP row counts for neither.
+		PoolTypeGyroE: {Passing: []string{}, Failing: []string{}},
+	} {
+		c := byType[typ]
+		if !reflect.DeepEqual(c.Passing, want.Passing) || !reflect.DeepEqual(c.Failing, want.Failing) {
+			t.Errorf("%s = %+v, want passing %v failing %v", typ, c, want.Passing, want.Failing)
+		}
+	}
+}
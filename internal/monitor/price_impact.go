@@ -0,0 +1,33 @@
+package monitor
+
+import (
+	"fmt"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// checkPriceImpact alerts when a healthy endpoint's provider-reported price
+// impact for the configured swap size exceeds thresholdBps. The alert fires
+// once when the impact crosses the threshold and re-arms when it drops back
+// below; the endpoint's status is not changed. A thresholdBps of 0 disables
+// the check. Rules with MaxPriceImpactBps cover endpoints that should fail
+// outright instead.
+func checkPriceImpact(endpoint *collector.Endpoint, thresholdBps float64) {
+	if thresholdBps <= 0 || endpoint.LastStatus != "up" || !endpoint.HasPriceImpact {
+		return
+	}
+	if endpoint.PriceImpactBps <= thresholdBps {
+		endpoint.PriceImpactAlerted = false
+		return
+	}
+	if endpoint.PriceImpactAlerted {
+		return
+	}
+	endpoint.PriceImpactAlerted = true
+	message := fmt.Sprintf("Price impact %.1f bps exceeds %.0f bps threshold for swap amount %s", endpoint.PriceImpactBps, thresholdBps, endpoint.SwapAmount)
+	logs.Printf("%s[PRICE IMPACT]%s %s: %s\n", config.ColorYellow, config.ColorReset, endpoint.Name, message)
+	notify.SendEndpointAlert(endpoint, message, "")
+}
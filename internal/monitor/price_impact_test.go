@@ -0,0 +1,40 @@
+package monitor
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestCheckPriceImpactAlertsOncePerExcursion(t *testing.T) {
+	e := &collector.Endpoint{Name: "x", LastStatus: "up", HasPriceImpact: true, PriceImpactBps: 150}
+
+	checkPriceImpact(e, 100)
+	if !e.PriceImpactAlerted {
+		t.Fatal("expected alert above threshold")
+	}
+
+	e.PriceImpactBps = 40
+	checkPriceImpact(e, 100)
+	if e.PriceImpactAlerted {
+		t.Fatal("expected alert to re-arm below threshold")
+	}
+
+	// Unknown impact, failing checks and a disabled threshold leave the state alone.
+	e.PriceImpactBps = 500
+	for _, tc := range []struct {
+		status    string
+		known     bool
+		threshold float64
+	}{
+		{"up", false, 100},
+		{"down", true, 100},
+		{"up", true, 0},
+	} {
+		e.LastStatus, e.HasPriceImpact = tc.status, tc.known
+		checkPriceImpact(e, tc.threshold)
+		if e.PriceImpactAlerted {
+			t.Fatalf("unexpected alert for %+v", tc)
+		}
+	}
+}
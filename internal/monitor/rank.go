@@ -0,0 +1,37 @@
+package monitor
+
+import (
+	"fmt"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// checkBalancerRank alerts when a healthy endpoint's Balancer V3 quote drops
+// out of the top topN per-source quotes of the market price response, even
+// though the Balancer-only check passed. Like checkPriceImpact it alerts once
+// per excursion, leaves the status alone, and skips providers without
+// per-source quotes. A topN of 0 disables the check.
+func checkBalancerRank(endpoint *collector.Endpoint, topN int) {
+	if topN <= 0 || endpoint.LastStatus != "up" || endpoint.RankedSources == 0 {
+		return
+	}
+	if endpoint.BalancerRank > 0 && endpoint.BalancerRank <= topN {
+		endpoint.RankAlerted = false
+		return
+	}
+	if endpoint.RankAlerted {
+		return
+	}
+	endpoint.RankAlerted = true
+	var message string
+	if endpoint.BalancerRank == 0 {
+		message = fmt.Sprintf("Balancer V3 is out of the top %d: no Balancer V3 quote among %d sources in the market price response", topN, endpoint.RankedSources)
+	} else {
+		message = fmt.Sprintf("Balancer V3 is out of the top %d: ranks %d of %d sources in the market price response", topN, endpoint.BalancerRank, endpoint.RankedSources)
+	}
+	logs.Printf("%s[RANK]%s %s: %s\n", config.ColorYellow, config.ColorReset, endpoint.Name, message)
+	notify.SendEndpointAlert(endpoint, message, "")
+}
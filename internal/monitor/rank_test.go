@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestCheckBalancerRankAlertsOncePerExcursion(t *testing.T) {
+	e := &collector.Endpoint{Name: "x", LastStatus: "up", BalancerRank: 5, RankedSources: 8}
+
+	checkBalancerRank(e, 3)
+	if !e.RankAlerted {
+		t.Fatal("expected alert outside the top 3")
+	}
+
+	e.BalancerRank = 2
+	checkBalancerRank(e, 3)
+	if e.RankAlerted {
+		t.Fatal("expected alert to re-arm inside the top 3")
+	}
+
+	e.BalancerRank = 0 // Balancer V3 did not quote
+	checkBalancerRank(e, 3)
+	if !e.RankAlerted {
+		t.Fatal("expected alert when Balancer V3 is missing")
+	}
+
+	// Unranked providers, failing checks and a disabled alert leave the state alone.
+	for _, tc := range []struct {
+		status string
+		ranked int
+		topN   int
+	}{
+		{"up", 0, 3},
+		{"down", 8, 3},
+		{"up", 8, 0},
+	} {
+		e.RankAlerted = false
+		e.LastStatus, e.RankedSources, e.BalancerRank = tc.status, tc.ranked, 7
+		checkBalancerRank(e, tc.topN)
+		if e.RankAlerted {
+			t.Fatalf("unexpected alert for %+v", tc)
+		}
+	}
+}
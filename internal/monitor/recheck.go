@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"sort"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+// recheckPolicy is how down rows are rechecked between regular cycles.
+type recheckPolicy struct {
+	first   time.Duration // wait after the failing check; 0 disables rechecks
+	max     time.Duration // backoff cap
+	maxRows int           // rows rechecked per pass, oldest check first
+	regular time.Duration // CHECK_INTERVAL_HOURS; rechecks back off no further
+}
+
+func recheckPolicyFromConfig() recheckPolicy {
+	return recheckPolicy{
+		first:   config.GetDownRecheckInterval(),
+		max:     config.GetDownRecheckMaxInterval(),
+		maxRows: config.GetDownRecheckMaxRows(),
+		regular: config.GetCheckInterval(),
+	}
+}
+
+// rechecker picks down rows to check again before their next regular cycle,
+// so the dashboard shows a recovery within minutes. The wait after each
+// failed recheck doubles from first up to max; once it reaches the regular
+// interval the row is left to the regular cycle. Rows with their own
+// Schedule are never rechecked. A recheck updates the row's latest state but
+// is not a history sample (checkRow).
+type rechecker struct {
+	attempts map[string]int // failed rechecks of the current outage, by row name
+}
+
+func newRechecker() *rechecker {
+	return &rechecker{attempts: map[string]int{}}
+}
+
+// wait returns how long after its last check a row is rechecked after n
+// failed rechecks, and false once that is no sooner than the regular cycle.
+func (p recheckPolicy) wait(n int) (time.Duration, bool) {
+	d := p.first
+	for i := 0; i < n && d < p.max; i++ {
+		d *= 2
+	}
+	if d > p.max {
+		d = p.max
+	}
+	return d, d < p.regular
+}
+
+// due returns the rows to recheck at now, at most p.maxRows of them, the
+// longest unchecked first, and counts the attempt for each.
+func (r *rechecker) due(rows []collector.Endpoint, now time.Time, p recheckPolicy) []collector.Endpoint {
+	down := map[string]bool{}
+	var out []collector.Endpoint
+	for _, e := range rows {
+		if !collector.IsDownStatus(e.LastStatus) || e.LastChecked.IsZero() || rowSchedule(e) != nil {
+			continue
+		}
+		down[e.Name] = true
+		if p.first <= 0 {
+			continue
+		}
+		if wait, ok := p.wait(r.attempts[e.Name]); ok && !now.Before(e.LastChecked.Add(wait)) {
+			out = append(out, e)
+		}
+	}
+	// Recovered (or removed) rows start a later outage from the first wait.
+	for name := range r.attempts {
+		if !down[name] {
+			delete(r.attempts, name)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].LastChecked.Before(out[j].LastChecked) })
+	if len(out) > p.maxRows {
+		out = out[:p.maxRows]
+	}
+	// Keep each BaseName's rows together so its group alerts stay grouped.
+	sort.SliceStable(out, func(i, j int) bool { return out[i].BaseName < out[j].BaseName })
+	for _, e := range out {
+		r.attempts[e.Name]++
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+func TestRecheckPolicyWait(t *testing.T) {
+	p := recheckPolicy{first: 5 * time.Minute, max: 30 * time.Minute, regular: time.Hour}
+	for n, want := range []time.Duration{5 * time.Minute, 10 * time.Minute, 20 * time.Minute, 30 * time.Minute, 30 * time.Minute} {
+		if got, ok := p.wait(n); got != want || !ok {
+			t.Errorf("wait(%d) = %v, %t, want %v", n, got, ok, want)
+		}
+	}
+	p.regular = 15 * time.Minute
+	if _, ok := p.wait(2); ok {
+		t.Error("recheck after 20m kept with a 15m regular interval")
+	}
+}
+
+func TestRecheckerDue(t *testing.T) {
+	now := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	rows := []collector.Endpoint{
+		{Name: "A-X", BaseName: "X", LastStatus: "down", LastChecked: now.Add(-6 * time.Minute)},
+		{Name: "B-X", BaseName: "X", LastStatus: "up", LastChecked: now.Add(-time.Hour)},
+		{Name: "A-Y", BaseName: "Y", LastStatus: "error", LastChecked: now.Add(-2 * time.Minute)},
+		{Name: "A-Z", BaseName: "Z", LastStatus: "down", LastChecked: now.Add(-7 * time.Minute), Schedule: "5m"},
+		{Name: "A-W", BaseName: "W", LastStatus: "down", LastChecked: now.Add(-9 * time.Minute)},
+	}
+	p := recheckPolicy{first: 5 * time.Minute, max: 30 * time.Minute, maxRows: 10, regular: time.Hour}
+	r := newRechecker()
+
+	due := r.due(rows, now, p)
+	if got := names(due); len(got) != 2 || got[0] != "A-W" || got[1] != "A-X" {
+		t.Fatalf("due = %v, want A-W and A-X", got)
+	}
+
+	// Still down 6 minutes after the recheck: the wait has doubled to 10m.
+	rows[0].LastChecked, rows[4].LastChecked = now, now
+	if due := r.due(rows, now.Add(6*time.Minute), p); len(names(due)) != 1 || due[0].Name != "A-Y" {
+		t.Errorf("due = %v, want only A-Y", names(due))
+	}
+
+	// Recovery resets the backoff.
+	rows[0].LastStatus = "up"
+	r.due(rows, now.Add(7*time.Minute), p)
+	if _, ok := r.attempts["A-X"]; ok {
+		t.Error("recovered row kept its attempts")
+	}
+
+	p.maxRows = 1
+	r = newRechecker()
+	rows[0].LastStatus = "down"
+	if due := r.due(rows, now.Add(20*time.Minute), p); len(due) != 1 || due[0].Name != "A-Y" {
+		t.Errorf("capped due = %v, want the longest unchecked row A-Y", names(due))
+	}
+
+	p.first = 0
+	if due := newRechecker().due(rows, now.Add(time.Hour), p); len(due) != 0 {
+		t.Errorf("disabled rechecks due %v", names(due))
+	}
+}
+
+type failingURLBuilder struct{}
+
+func (failingURLBuilder) BuildURL(*collector.Endpoint, check.RequestOptions) (string, error) {
+	return "", errors.New("no route")
+}
+
+// Rechecks of a down row update its latest state but not its history, so
+// the uptime of a week of regular checks is what it was.
+func TestRechecksLeaveUptimeUnchanged(t *testing.T) {
+	const name = "Recheck-X"
+	saved := GlobalRegistry
+	GlobalRegistry = providers.NewRegistry()
+	GlobalRegistry.Register("recheck-test", providers.ProviderConfig{URLBuilder: failingURLBuilder{}})
+	st := store.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	SetStore(st)
+	now := time.Now()
+	collector.SetEndpoints([]collector.Endpoint{{Name: name, BaseName: "X", RouteSolver: "recheck-test", LastStatus: "down", LastChecked: now}})
+	collector.SetHistory(name, []collector.CheckRecord{{At: now.Add(-time.Hour), Status: "up"}, {At: now, Status: "down"}})
+	t.Cleanup(func() {
+		GlobalRegistry = saved
+		SetStore(nil)
+		collector.SetEndpoints(nil)
+		collector.SetHistory(name, nil)
+	})
+	week := now.Add(-7 * 24 * time.Hour)
+
+	for range 3 {
+		checkCycle("recheck", collector.GetEndpointsCopy(), true)
+	}
+	if u := collector.SummarizeHistory(collector.GetHistory(name), week).Uptime(); u != 0.5 {
+		t.Fatalf("uptime after rechecks = %v, want 0.5", u)
+	}
+	if e := collector.GetEndpointByName(name); e.LastStatus != "error" || !e.LastChecked.After(now) {
+		t.Fatalf("recheck didn't update the latest state: %+v", e)
+	}
+	if recs, _ := st.QueryHistory(name, week); len(recs) != 0 {
+		t.Fatalf("rechecks stored %d history records", len(recs))
+	}
+	if latest, _ := st.LoadLatest(); len(latest) != 1 || latest[0].LastStatus != "error" {
+		t.Fatalf("stored latest = %+v", latest)
+	}
+
+	checkCycle("base", collector.GetEndpointsCopy(), false)
+	if n := len(collector.GetHistory(name)); n != 3 {
+		t.Fatalf("history has %d records after a regular check, want 3", n)
+	}
+}
@@ -0,0 +1,21 @@
+package monitor
+
+import "go-monitoring/monitoring/providers"
+
+// GlobalRegistry is the provider registry the monitor loops check through.
+var GlobalRegistry *providers.Registry
+
+// SolverCapabilities returns what GlobalRegistry's provider for a route
+// solver type supports; ok is false for an unregistered one or before
+// InitializeRegistry.
+func SolverCapabilities(routeSolver string) (caps providers.Capabilities, ok bool) {
+	if GlobalRegistry == nil {
+		return providers.Capabilities{}, false
+	}
+	return GlobalRegistry.Capabilities(routeSolver)
+}
+
+// InitializeRegistry initializes the global provider registry
+func InitializeRegistry() {
+	GlobalRegistry = providers.NewDefaultRegistry()
+}
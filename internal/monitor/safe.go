@@ -5,7 +5,9 @@ import (
 	"runtime/debug"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/collector"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
 )
 
 // safeCheck runs a single per-endpoint check with panic recovery so a bug in
@@ -20,7 +22,7 @@ func safeCheck(name string, fn func()) {
 	defer func() {
 		if r := recover(); r != nil {
 			stack := debug.Stack()
-			fmt.Printf("%s[CHECK PANIC]%s %s: %v\n%s\n",
+			logs.Printf("%s[CHECK PANIC]%s %s: %v\n%s\n",
 				config.ColorRed, config.ColorReset, name, r, stack)
 			collector.UpdateEndpointByName(name, recordPanic(r))
 			collector.UpdateDiscoveredEndpointByName(name, recordPanic(r))
@@ -35,7 +37,11 @@ func safeCheck(name string, fn func()) {
 // no-op depending on which sweep the panic happened in.
 func recordPanic(r any) func(*collector.Endpoint) {
 	return func(e *collector.Endpoint) {
+		prevStatus := e.LastStatus
 		e.LastStatus = "panic"
 		e.Message = fmt.Sprintf("provider handler panicked: %v", r)
+		now := clock.Now()
+		e.RecordStatusChange(prevStatus, now)
+		collector.RecordCheck(e.Name, collector.CheckRecord{At: now, Status: e.LastStatus, Message: e.Message})
 	}
 }
@@ -4,7 +4,7 @@ import (
 	"strings"
 	"testing"
 
-	"go-monitoring/internal/collector"
+	"go-monitoring/monitoring/collector"
 )
 
 // TestSafeCheck_RecoversAndRecordsStatus verifies the two contracts of
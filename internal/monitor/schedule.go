@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+)
+
+// scheduleTick bounds how long RunScheduled sleeps, so a group imported with
+// a Schedule is picked up within a minute.
+const scheduleTick = time.Minute
+
+// rowSchedule returns the row's parsed Schedule, nil when it has none. An
+// invalid one (imports reject it; a compiled-in typo can slip through) is
+// treated as none, so the row still follows CHECK_INTERVAL_HOURS.
+func rowSchedule(e collector.Endpoint) config.Schedule {
+	s, err := config.ParseSchedule(e.Schedule)
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+// scheduler tracks when each scheduled group is next due. A group is a
+// BaseName's solver rows; changing its Schedule starts it afresh.
+type scheduler struct {
+	next    map[string]time.Time
+	invalid map[string]bool // schedules already reported as invalid
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{next: map[string]time.Time{}, invalid: map[string]bool{}}
+}
+
+// due returns the rows whose group is due at now, and when to look again. A
+// group first seen is due at its schedule's first time after now.
+func (s *scheduler) due(rows []collector.Endpoint, now time.Time) ([]collector.Endpoint, time.Time) {
+	wake := now.Add(scheduleTick)
+	seen := map[string]bool{}
+	fire := map[string]bool{}
+	var out []collector.Endpoint
+	for _, e := range rows {
+		if e.Schedule == "" {
+			continue
+		}
+		key := e.BaseName + "\x00" + e.Schedule
+		if !seen[key] {
+			seen[key] = true
+			sched, err := config.ParseSchedule(e.Schedule)
+			if err != nil {
+				if !s.invalid[key] {
+					s.invalid[key] = true
+					logs.Printf("%s[SCHEDULE]%s %s: %v; checking it every CHECK_INTERVAL_HOURS instead\n", config.ColorRed, config.ColorReset, e.BaseName, err)
+				}
+				continue
+			}
+			next, ok := s.next[key]
+			if !ok {
+				next = sched.Next(now)
+			}
+			if !next.After(now) {
+				fire[key] = true
+				next = sched.Next(now)
+			}
+			s.next[key] = next
+			if next.Before(wake) {
+				wake = next
+			}
+		}
+		if fire[key] {
+			out = append(out, e)
+		}
+	}
+	for key := range s.next {
+		if !seen[key] {
+			delete(s.next, key)
+		}
+	}
+	return out, wake
+}
+
+// RunScheduled checks each BaseEndpoints group that has its own Schedule
+// (e.g. "*/5 * * * *" for a high-priority pool) when it is due, as a
+// "scheduled" cycle. Groups due together are checked in one cycle; a cycle
+// that overruns a group's next time runs it as soon as it ends. Down rows are
+// rechecked between cycles (see rechecker). Only the leader checks.
+func RunScheduled(firstCycleDelay time.Duration) {
+	if firstCycleDelay > 0 {
+		clock.Sleep(firstCycleDelay)
+	}
+	s, r := newScheduler(), newRechecker()
+	for {
+		rows, now := collector.GetEndpointsCopy(), clock.Now()
+		due, wake := s.due(rows, now)
+		if len(due) > 0 && IsLeader() {
+			checkCycle("scheduled", due, false)
+		}
+		if recheck := r.due(rows, now, recheckPolicyFromConfig()); len(recheck) > 0 && IsLeader() && !baseCycleRunning.Load() {
+			checkCycle("recheck", recheck, true)
+		}
+		clock.Sleep(wake.Sub(clock.Now()))
+	}
+}
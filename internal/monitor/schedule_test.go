@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestSchedulerDue(t *testing.T) {
+	rows := []collector.Endpoint{
+		{Name: "A-Fast", BaseName: "Fast", Schedule: "5m"},
+		{Name: "B-Fast", BaseName: "Fast", Schedule: "5m"},
+		{Name: "A-Slow", BaseName: "Slow", Schedule: "0 * * * *"},
+		{Name: "A-Hourly", BaseName: "Hourly"},
+		{Name: "A-Bad", BaseName: "Bad", Schedule: "soon"},
+	}
+	start := time.Date(2025, 3, 14, 10, 7, 0, 0, time.UTC)
+	s := newScheduler()
+
+	due, wake := s.due(rows, start)
+	if len(due) != 0 {
+		t.Fatalf("first pass due %d rows, want none", len(due))
+	}
+	if want := start.Add(time.Minute); !wake.Equal(want) {
+		t.Errorf("wake = %s, want %s (tick)", wake, want)
+	}
+
+	due, wake = s.due(rows, start.Add(5*time.Minute))
+	if len(due) != 2 || due[0].Name != "A-Fast" || due[1].Name != "B-Fast" {
+		t.Fatalf("due = %v, want the Fast group", names(due))
+	}
+	if want := start.Add(6 * time.Minute); !wake.Equal(want) {
+		t.Errorf("wake = %s, want %s", wake, want)
+	}
+
+	due, _ = s.due(rows, time.Date(2025, 3, 14, 11, 0, 0, 0, time.UTC))
+	if got := names(due); len(got) != 3 || got[2] != "A-Slow" {
+		t.Errorf("due at 11:00 = %v, want Fast and Slow", got)
+	}
+
+	// A group removed (or whose schedule changed) is forgotten.
+	s.due(rows[:2], time.Date(2025, 3, 14, 11, 1, 0, 0, time.UTC))
+	if _, ok := s.next["Slow\x000 * * * *"]; ok {
+		t.Error("removed group still tracked")
+	}
+}
+
+func TestRowSchedule(t *testing.T) {
+	if rowSchedule(collector.Endpoint{}) != nil || rowSchedule(collector.Endpoint{Schedule: "soon"}) != nil {
+		t.Error("rows without a valid schedule are scheduled")
+	}
+	if rowSchedule(collector.Endpoint{Schedule: "*/5 * * * *"}) == nil {
+		t.Error("cron schedule not parsed")
+	}
+}
+
+func names(rows []collector.Endpoint) []string {
+	out := make([]string, len(rows))
+	for i, e := range rows {
+		out[i] = e.Name
+	}
+	return out
+}
@@ -0,0 +1,294 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// SLO kinds.
+const (
+	SLOAvailability = "availability"  // checks up
+	SLOResponseTime = "response_time" // Balancer-only quotes faster than a threshold
+)
+
+// burnPolicy alerts when a provider burns its error budget at least rate
+// times faster than the SLO allows over both the long window and the short
+// one: the long window keeps a single failed check from paging, the short
+// one stops the alert soon after the provider recovers. Windows suit the
+// hourly check cadence rather than the usual 1h / 5m.
+type burnPolicy struct {
+	name        string
+	long, short time.Duration
+	rate        float64
+	severity    notify.Severity
+}
+
+// burnPolicies are checked in order, most severe first. At rate 6 a 30-day
+// budget lasts five days; at rate 1 it runs out with the window.
+var burnPolicies = []burnPolicy{
+	{name: "fast", long: 6 * time.Hour, short: time.Hour, rate: 6, severity: notify.SeverityCritical},
+	{name: "slow", long: 3 * 24 * time.Hour, short: 6 * time.Hour, rate: 1, severity: notify.SeverityWarning},
+}
+
+// burnWindows are the windows SLOStatus reports burn rates over, shortest
+// first.
+var burnWindows = []time.Duration{time.Hour, 6 * time.Hour, 3 * 24 * time.Hour}
+
+// BurnRate is how many times faster than the SLO allows a provider burned
+// its error budget over a window: 1 uses it up exactly at the end of the SLO
+// window. Zero when no checks fall in the window.
+type BurnRate struct {
+	Window string  `json:"window"` // e.g. "6h", "3d"
+	Good   int     `json:"good"`
+	Total  int     `json:"total"`
+	Rate   float64 `json:"rate"`
+}
+
+// SLOStatus is one provider SLO measured over the SLO window
+// (SLO_WINDOW_DAYS) from the stored results of its BaseEndpoints rows.
+type SLOStatus struct {
+	Solver    string  `json:"solver"` // RouteSolver.Type
+	Name      string  `json:"name"`
+	SLO       string  `json:"slo"`       // SLOAvailability or SLOResponseTime
+	Objective string  `json:"objective"` // e.g. "99% of quotes < 3s"
+	Target    float64 `json:"target"`    // percent of good checks
+	Good      int     `json:"good"`
+	Total     int     `json:"total"`
+	// Attained is the percent of good checks over the window, -1 when no
+	// checks fall in it. BudgetRemaining is the fraction of the window's
+	// error budget left: 1 untouched, negative once overspent.
+	Attained        float64    `json:"attained"`
+	BudgetRemaining float64    `json:"budgetRemaining"`
+	BurnRates       []BurnRate `json:"burnRates"`
+	// Alerting names the most severe burn policy the burn rates trip
+	// ("fast", "slow"), empty when none does.
+	Alerting string `json:"alerting,omitempty"`
+}
+
+// sloHistoryFunc returns an endpoint's checks since a time, oldest first.
+type sloHistoryFunc func(name string, since time.Time) []collector.CheckRecord
+
+// SLOReport measures the solvers' SLOs at now from the registered store, or
+// the in-memory history (one week) without one.
+func SLOReport(solvers []config.RouteSolver, now time.Time) []SLOStatus {
+	return sloStatuses(solvers, collector.GetEndpointsCopy(), sloHistory, config.GetSLOWindow(), now)
+}
+
+// sloHistory reads an endpoint's checks from the store, falling back to the
+// in-memory history when there is none or it fails.
+func sloHistory(name string, since time.Time) []collector.CheckRecord {
+	if st := GetStore(); st != nil {
+		recs, err := st.QueryHistory(name, since)
+		if err == nil {
+			return recs
+		}
+		logs.Printf("%s[SLO]%s %s: query history failed: %v\n", config.ColorRed, config.ColorReset, name, err)
+	}
+	var out []collector.CheckRecord
+	for _, r := range collector.GetHistory(name) {
+		if !r.At.Before(since) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// sloStatuses measures each solver's enabled SLOs over window from the
+// history of its rows among endpoints.
+func sloStatuses(solvers []config.RouteSolver, endpoints []collector.Endpoint, history sloHistoryFunc, window time.Duration, now time.Time) []SLOStatus {
+	span := window
+	for _, w := range burnWindows {
+		span = max(span, w)
+	}
+	var out []SLOStatus
+	for _, solver := range solvers {
+		var records []collector.CheckRecord
+		for _, e := range endpoints {
+			if e.RouteSolver == solver.Type {
+				records = append(records, history(e.Name, now.Add(-span))...)
+			}
+		}
+		if target := config.GetSLOAvailability(solver.Type); target > 0 && target < 100 {
+			out = append(out, measureSLO(solver, SLOAvailability, fmt.Sprintf("%g%% of checks up", target), target,
+				records, availabilityGood, window, now))
+		}
+		if threshold, target := config.GetSLOLatency(solver.Type); threshold > 0 && target > 0 && target < 100 {
+			good := func(r collector.CheckRecord) (bool, bool) {
+				return r.ResponseTime < threshold, r.ResponseTime > 0
+			}
+			out = append(out, measureSLO(solver, SLOResponseTime, fmt.Sprintf("%g%% of quotes < %s", target, threshold), target,
+				records, good, window, now))
+		}
+	}
+	return out
+}
+
+// availabilityGood classifies a check for the availability SLO: up is good,
+// a down status bad, anything else (unsupported, not-applicable, info) not
+// counted, as in collector.SummarizeHistory.
+func availabilityGood(r collector.CheckRecord) (good, counted bool) {
+	return r.Status == "up", r.Status == "up" || collector.IsDownStatus(r.Status)
+}
+
+// measureSLO counts the good and counted records over the SLO window and
+// each burn window, windows ending at now and excluding their start.
+func measureSLO(solver config.RouteSolver, slo, objective string, target float64, records []collector.CheckRecord,
+	classify func(collector.CheckRecord) (good, counted bool), window time.Duration, now time.Time) SLOStatus {
+	budget := 1 - target/100
+	count := func(since time.Time) (good, total int) {
+		for _, r := range records {
+			if !r.At.After(since) || r.At.After(now) {
+				continue
+			}
+			if ok, counted := classify(r); counted {
+				total++
+				if ok {
+					good++
+				}
+			}
+		}
+		return good, total
+	}
+
+	st := SLOStatus{Solver: solver.Type, Name: solver.Name, SLO: slo, Objective: objective, Target: target, Attained: -1, BudgetRemaining: 1}
+	st.Good, st.Total = count(now.Add(-window))
+	if st.Total > 0 {
+		bad := float64(st.Total-st.Good) / float64(st.Total)
+		st.Attained = 100 * (1 - bad)
+		st.BudgetRemaining = 1 - bad/budget
+	}
+	for _, w := range burnWindows {
+		br := BurnRate{Window: formatWindow(w)}
+		br.Good, br.Total = count(now.Add(-w))
+		if br.Total > 0 {
+			br.Rate = float64(br.Total-br.Good) / float64(br.Total) / budget
+		}
+		st.BurnRates = append(st.BurnRates, br)
+	}
+	if p, ok := st.firing(); ok {
+		st.Alerting = p.name
+	}
+	return st
+}
+
+// burnRate returns the status's burn rate over window, 0 when it isn't one
+// of burnWindows.
+func (s SLOStatus) burnRate(window time.Duration) float64 {
+	for _, br := range s.BurnRates {
+		if br.Window == formatWindow(window) {
+			return br.Rate
+		}
+	}
+	return 0
+}
+
+// firing returns the most severe burn policy the status trips, if any.
+func (s SLOStatus) firing() (burnPolicy, bool) {
+	for _, p := range burnPolicies {
+		if s.burnRate(p.long) >= p.rate && s.burnRate(p.short) >= p.rate {
+			return p, true
+		}
+	}
+	return burnPolicy{}, false
+}
+
+var (
+	sloAlertsMu sync.Mutex
+	// sloAlerting holds the burn policy firing per "solver|slo", so each
+	// excursion alerts once, again only when it escalates, and once more
+	// when it ends.
+	sloAlerting = map[string]string{}
+)
+
+// checkSLOs measures the SLOs after a cycle and alerts on burn rates.
+func checkSLOs(now time.Time) {
+	alertSLOs(SLOReport(config.GetEnabledRouteSolvers(), now), config.GetSLOWindow())
+}
+
+// alertSLOs sends an alert for each SLO whose burn policy started firing or
+// escalated, and an info alert for each that stopped. It returns the
+// messages sent.
+func alertSLOs(statuses []SLOStatus, window time.Duration) []string {
+	sloAlertsMu.Lock()
+	defer sloAlertsMu.Unlock()
+
+	var alerts []string
+	for _, st := range statuses {
+		key := st.Solver + "|" + st.SLO
+		prev := sloAlerting[key]
+		p, ok := st.firing()
+		if !ok {
+			if prev != "" {
+				delete(sloAlerting, key)
+				msg := fmt.Sprintf("%s %s SLO (%s) is no longer burning its error budget too fast; %s of the %s budget remains",
+					st.Name, sloLabel(st.SLO), st.Objective, formatBudget(st.BudgetRemaining), formatWindow(window))
+				logs.Printf("%s[SLO]%s %s\n", config.ColorGreen, config.ColorReset, msg)
+				notify.Send(notify.SeverityInfo, msg)
+				alerts = append(alerts, msg)
+			}
+			continue
+		}
+		sloAlerting[key] = p.name
+		if prev == p.name || (prev != "" && policySeverity(prev) >= p.severity) {
+			continue
+		}
+		msg := fmt.Sprintf("%s %s SLO (%s) is burning its error budget %.1fx too fast over %s (%.1fx over %s); %s of the %s budget remains",
+			st.Name, sloLabel(st.SLO), st.Objective, st.burnRate(p.long), formatWindow(p.long), st.burnRate(p.short), formatWindow(p.short),
+			formatBudget(st.BudgetRemaining), formatWindow(window))
+		if lasts, ok := budgetLasts(st, p, window); ok {
+			msg += fmt.Sprintf(", gone in %s at this rate", collector.FormatDuration(lasts))
+		}
+		logs.Printf("%s[SLO]%s %s\n", config.ColorRed, config.ColorReset, msg)
+		notify.Send(p.severity, msg)
+		alerts = append(alerts, msg)
+	}
+	return alerts
+}
+
+// budgetLasts estimates how long the remaining budget lasts at the policy's
+// long-window burn rate.
+func budgetLasts(st SLOStatus, p burnPolicy, window time.Duration) (time.Duration, bool) {
+	rate := st.burnRate(p.long)
+	if rate <= 0 || st.BudgetRemaining <= 0 {
+		return 0, false
+	}
+	return time.Duration(st.BudgetRemaining / rate * float64(window)).Round(time.Hour), true
+}
+
+func policySeverity(name string) notify.Severity {
+	for _, p := range burnPolicies {
+		if p.name == name {
+			return p.severity
+		}
+	}
+	return notify.SeverityInfo
+}
+
+func sloLabel(slo string) string {
+	return strings.ReplaceAll(slo, "_", " ")
+}
+
+// formatBudget renders a remaining budget fraction as a percentage, none
+// once overspent.
+func formatBudget(remaining float64) string {
+	if remaining <= 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%.0f%%", math.Floor(100*remaining))
+}
+
+// formatWindow renders a window as whole days or hours, e.g. "3d", "6h".
+func formatWindow(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return fmt.Sprintf("%dh", d/time.Hour)
+}
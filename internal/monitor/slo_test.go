@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestSLOStatuses(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	solvers := []config.RouteSolver{{Name: "KyberSwap", Type: "kyberswap"}}
+	endpoints := []collector.Endpoint{
+		{Name: "KyberSwap-A", RouteSolver: "kyberswap"},
+		{Name: "KyberSwap-B", RouteSolver: "kyberswap"},
+		{Name: "Odos-A", RouteSolver: "odos"},
+	}
+	// 200 hourly checks per row: the last two hours of A failed, and one
+	// check of B in the last six hours took 5s.
+	records := map[string][]collector.CheckRecord{}
+	for i := 0; i < 200; i++ {
+		at := now.Add(-time.Duration(i) * time.Hour)
+		a := collector.CheckRecord{At: at, Status: "up", ResponseTime: time.Second}
+		if i < 2 {
+			a = collector.CheckRecord{At: at, Status: "down"}
+		}
+		b := collector.CheckRecord{At: at, Status: "up", ResponseTime: 500 * time.Millisecond}
+		if i == 3 {
+			b.ResponseTime = 5 * time.Second
+		}
+		records["KyberSwap-A"] = append([]collector.CheckRecord{a}, records["KyberSwap-A"]...)
+		records["KyberSwap-B"] = append([]collector.CheckRecord{b}, records["KyberSwap-B"]...)
+	}
+	records["Odos-A"] = []collector.CheckRecord{{At: now, Status: "down"}}
+	history := func(name string, since time.Time) []collector.CheckRecord {
+		var out []collector.CheckRecord
+		for _, r := range records[name] {
+			if !r.At.Before(since) {
+				out = append(out, r)
+			}
+		}
+		return out
+	}
+
+	got := sloStatuses(solvers, endpoints, history, 7*24*time.Hour, now)
+	if len(got) != 2 || got[0].SLO != SLOAvailability || got[1].SLO != SLOResponseTime {
+		t.Fatalf("statuses = %+v", got)
+	}
+
+	avail := got[0]
+	if avail.Objective != "99.5% of checks up" || avail.Total != 2*168 || avail.Good != 2*168-2 {
+		t.Fatalf("availability = %+v", avail)
+	}
+	// 6h: 2 bad of 12 checks = 16.7% errors against a 0.5% budget.
+	if r := avail.burnRate(6 * time.Hour); r < 33 || r > 34 {
+		t.Fatalf("6h burn rate = %v", r)
+	}
+	if avail.Alerting != "fast" {
+		t.Fatalf("availability alerting = %q", avail.Alerting)
+	}
+
+	latency := got[1]
+	// Down checks have no response time and are not counted.
+	if latency.Objective != "99% of quotes < 3s" || latency.Total != 2*168-2 || latency.Good != latency.Total-1 {
+		t.Fatalf("response time = %+v", latency)
+	}
+	// The slow quote is outside the last hour, so the fast policy's short
+	// window holds it back.
+	if latency.burnRate(time.Hour) != 0 || latency.Alerting != "" {
+		t.Fatalf("response time alerting = %q, burn rates %+v", latency.Alerting, latency.BurnRates)
+	}
+}
+
+func TestAlertSLOsOncePerExcursion(t *testing.T) {
+	t.Cleanup(func() { sloAlerting = map[string]string{} })
+	burning := SLOStatus{Solver: "odos", Name: "Odos", SLO: SLOAvailability, Objective: "99.5% of checks up", BudgetRemaining: 0.5,
+		BurnRates: []BurnRate{{Window: "1h", Rate: 10}, {Window: "6h", Rate: 8}, {Window: "3d", Rate: 2}}}
+	healthy := burning
+	healthy.BurnRates = []BurnRate{{Window: "1h"}, {Window: "6h", Rate: 0.5}, {Window: "3d", Rate: 0.8}}
+
+	alerts := alertSLOs([]SLOStatus{burning}, 30*24*time.Hour)
+	if len(alerts) != 1 || !strings.Contains(alerts[0], "8.0x too fast over 6h") || !strings.Contains(alerts[0], "50% of the 30d budget") {
+		t.Fatalf("first alerts = %q", alerts)
+	}
+	if again := alertSLOs([]SLOStatus{burning}, 30*24*time.Hour); len(again) != 0 {
+		t.Fatalf("re-alerted: %q", again)
+	}
+	if resolved := alertSLOs([]SLOStatus{healthy}, 30*24*time.Hour); len(resolved) != 1 || !strings.Contains(resolved[0], "no longer burning") {
+		t.Fatalf("resolved alerts = %q", resolved)
+	}
+}
@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/providers"
+	"go-monitoring/monitoring/sources"
+)
+
+var (
+	newSourcesMu sync.Mutex
+	// newSourcesSeen records the (solver, network, ID) triples already
+	// alerted on, so a new source is reported once per process rather than
+	// on every scan until the registry learns it.
+	newSourcesSeen = map[string]bool{}
+
+	catalogsMu sync.Mutex
+	// lastCatalogs holds each solver and network's catalog from the previous
+	// scan, keyed "solver|network". The store's copy takes precedence, so a
+	// restart or a new leader still diffs against the last scan.
+	lastCatalogs = map[string]store.SourceCatalog{}
+)
+
+// RunSourceScan scans every enabled provider's source catalog each interval,
+// starting immediately, for Balancer V3 sources the sources registry does not
+// know (e.g. a new "balancer-v3-reclamm" on KyberSwap) and alerts so the
+// registry can be updated and a check added for the new pool type, and for
+// Balancer V3 sources that dropped out of a catalog since the previous scan,
+// often the first sign an integrator disabled them, before quotes start
+// failing. Only the leader scans; dry runs make no requests.
+func RunSourceScan(interval time.Duration) {
+	for {
+		if IsLeader() && !config.GetDryRunEnabled() {
+			scanSources(GlobalRegistry, config.GetEnabledRouteSolvers(), sources.Table())
+		}
+		clock.Sleep(interval)
+	}
+}
+
+// scanSources runs one scan and returns the alert for each solver and
+// network with sources not reported before or removed since the last scan.
+func scanSources(reg *providers.Registry, solvers []config.RouteSolver, table []sources.Entry) []string {
+	var alerts []string
+	previous := previousCatalogs()
+	for _, solver := range solvers {
+		cfg, ok := reg.Lookup(solver.Type)
+		if !ok || cfg.SourceCatalog == nil {
+			continue
+		}
+		for _, network := range solver.SupportedNetworks {
+			catalog, err := cfg.SourceCatalog(network)
+			if err != nil {
+				logs.Printf("%s[SOURCE SCAN]%s %s on %s: %v\n", config.ColorYellow, config.ColorReset, solver.Name, config.NetworkName(network), err)
+				continue
+			}
+			if len(catalog) == 0 {
+				// An empty list is a provider glitch far more often than a
+				// delisting of everything; don't let it become the snapshot.
+				logs.Printf("%s[SOURCE SCAN]%s %s on %s: empty catalog\n", config.ColorYellow, config.ColorReset, solver.Name, config.NetworkName(network))
+				continue
+			}
+			if prev, ok := previous[solver.Type+"|"+network]; ok {
+				if removed := sources.Removed(prev.Sources, catalog); len(removed) > 0 {
+					msg := fmt.Sprintf("Balancer V3 source removed from %s %s catalog since %s: %s; the provider may have disabled it, expect its Balancer V3 checks to fail",
+						solver.Name, config.NetworkName(network), prev.ScannedAt.UTC().Format(time.RFC3339), strings.Join(removed, ", "))
+					logs.Printf("%s[SOURCE SCAN]%s %s\n", config.ColorRed, config.ColorReset, msg)
+					notify.Send(notify.SeverityCritical, msg)
+					alerts = append(alerts, msg)
+				}
+			}
+			saveCatalog(store.SourceCatalog{Solver: solver.Type, Network: network, Sources: catalog, ScannedAt: clock.Now().UTC()})
+
+			fresh := recordNewSources(solver.Type, network, sources.Unknown(table, solver.Type, network, catalog))
+			if len(fresh) == 0 {
+				continue
+			}
+			msg := fmt.Sprintf("New Balancer V3 source on %s %s: %s; add it to SOURCE_IDS_FILE or sources.DefaultEntries and add a check for the new pool type",
+				solver.Name, config.NetworkName(network), strings.Join(fresh, ", "))
+			logs.Printf("%s[SOURCE SCAN]%s %s\n", config.ColorYellow, config.ColorReset, msg)
+			notify.Send(notify.SeverityWarning, msg)
+			alerts = append(alerts, msg)
+		}
+	}
+	return alerts
+}
+
+// previousCatalogs returns the last scanned catalogs keyed "solver|network":
+// the in-memory ones overlaid with the store's.
+func previousCatalogs() map[string]store.SourceCatalog {
+	catalogsMu.Lock()
+	out := make(map[string]store.SourceCatalog, len(lastCatalogs))
+	for k, c := range lastCatalogs {
+		out[k] = c
+	}
+	catalogsMu.Unlock()
+
+	st := GetStore()
+	if st == nil {
+		return out
+	}
+	stored, err := st.LoadSourceCatalogs()
+	if err != nil {
+		logs.Printf("%s[STORE]%s load source catalogs failed: %v\n", config.ColorRed, config.ColorReset, err)
+		return out
+	}
+	for _, c := range stored {
+		out[c.Solver+"|"+c.Network] = c
+	}
+	return out
+}
+
+// saveCatalog records a scanned catalog as the one the next scan diffs
+// against, in memory and in the registered store, if any.
+func saveCatalog(c store.SourceCatalog) {
+	catalogsMu.Lock()
+	lastCatalogs[c.Solver+"|"+c.Network] = c
+	catalogsMu.Unlock()
+
+	if st := GetStore(); st != nil {
+		if err := st.SaveSourceCatalog(c); err != nil {
+			logs.Printf("%s[STORE]%s %s on %s: save source catalog failed: %v\n", config.ColorRed, config.ColorReset, c.Solver, config.NetworkName(c.Network), err)
+		}
+	}
+}
+
+// recordNewSources returns the IDs not seen before for solver and network,
+// marking them seen.
+func recordNewSources(solver, network string, ids []string) []string {
+	newSourcesMu.Lock()
+	defer newSourcesMu.Unlock()
+	var fresh []string
+	for _, id := range ids {
+		key := solver + "|" + network + "|" + id
+		if !newSourcesSeen[key] {
+			newSourcesSeen[key] = true
+			fresh = append(fresh, id)
+		}
+	}
+	return fresh
+}
@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/providers"
+	"go-monitoring/monitoring/sources"
+)
+
+func TestScanSourcesAlertsOncePerNewSource(t *testing.T) {
+	reg := providers.NewRegistry()
+	reg.Register("kyberswap", providers.ProviderConfig{
+		SourceCatalog: func(network string) ([]string, error) {
+			if network == "10" {
+				return nil, errors.New("unavailable")
+			}
+			return []string{"uniswap-v3", "balancer-v2-weighted", "balancer-v3-stable", "balancer-v3-lbp"}, nil
+		},
+	})
+	reg.Register("odos", providers.ProviderConfig{}) // no catalog
+	solvers := []config.RouteSolver{
+		{Name: "KyberSwap", Type: "kyberswap", SupportedNetworks: []string{"1", "10"}},
+		{Name: "Odos", Type: "odos", SupportedNetworks: []string{"1"}},
+	}
+
+	alerts := scanSources(reg, solvers, sources.DefaultEntries)
+	if len(alerts) != 1 || !strings.Contains(alerts[0], "balancer-v3-lbp") || strings.Contains(alerts[0], "balancer-v3-stable") {
+		t.Fatalf("first scan alerts = %q", alerts)
+	}
+	if again := scanSources(reg, solvers, sources.DefaultEntries); len(again) != 0 {
+		t.Fatalf("second scan re-alerted: %q", again)
+	}
+}
+
+func TestScanSourcesAlertsOnRemovedBalancerV3Source(t *testing.T) {
+	catalog := []string{"UniswapV3", "BalancerV3", "BalancerV3Stable"}
+	reg := providers.NewRegistry()
+	reg.Register("paraswap", providers.ProviderConfig{
+		SourceCatalog: func(network string) ([]string, error) { return catalog, nil },
+	})
+	solvers := []config.RouteSolver{{Name: "Paraswap", Type: "paraswap", SupportedNetworks: []string{"8453"}}}
+	removedAlerts := func() []string {
+		var out []string
+		for _, a := range scanSources(reg, solvers, sources.DefaultEntries) {
+			if strings.Contains(a, "removed") {
+				out = append(out, a)
+			}
+		}
+		return out
+	}
+
+	if alerts := removedAlerts(); len(alerts) != 0 {
+		t.Fatalf("first scan has nothing to diff against, got %q", alerts)
+	}
+	catalog = []string{"UniswapV3", "BalancerV3"}
+	if alerts := removedAlerts(); len(alerts) != 1 || !strings.Contains(alerts[0], "BalancerV3Stable") {
+		t.Fatalf("removal alerts = %q", alerts)
+	}
+	catalog = nil
+	if alerts := removedAlerts(); len(alerts) != 0 {
+		t.Fatalf("empty catalog alerted: %q", alerts)
+	}
+	catalog = []string{"BalancerV3", "SushiSwap"}
+	if alerts := removedAlerts(); len(alerts) != 0 {
+		t.Fatalf("removing a non-Balancer V3 source alerted: %q", alerts)
+	}
+}
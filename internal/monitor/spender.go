@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"fmt"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/providers"
+)
+
+// checkSpender alerts when the allowance target a provider's quote names is
+// not a known-good spender for the provider on the endpoint's network: an
+// integration approving it would fail at execution time, or worse. Like
+// checkPriceImpact it alerts once per excursion and leaves the status
+// alone. Providers that report no spender, or have no registry entries for
+// the network, are skipped.
+func checkSpender(endpoint *collector.Endpoint, table []providers.KnownSpender) {
+	if endpoint.Spender == "" {
+		return
+	}
+	known, checked := providers.CheckSpender(table, endpoint.RouteSolver, endpoint.Network, endpoint.Spender)
+	if !checked || known {
+		endpoint.SpenderAlerted = false
+		return
+	}
+	if endpoint.SpenderAlerted {
+		return
+	}
+	endpoint.SpenderAlerted = true
+	message := fmt.Sprintf("Unexpected spender %s from %s on %s: not in the known-good spender registry", endpoint.Spender, endpoint.RouteSolver, config.NetworkName(endpoint.Network))
+	logs.Printf("%s[SPENDER]%s %s: %s\n", config.ColorRed, config.ColorReset, endpoint.Name, message)
+	notify.SendEndpointAlert(endpoint, message, "")
+}
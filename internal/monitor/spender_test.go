@@ -0,0 +1,36 @@
+package monitor
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+func TestCheckSpenderAlertsOncePerExcursion(t *testing.T) {
+	table := []providers.KnownSpender{{Solver: "kyberswap", Address: "0x6131B5fae19EA4f9D964eAc0408E4408b66337b5"}}
+	e := &collector.Endpoint{Name: "x", RouteSolver: "kyberswap", Network: "8453", Spender: "0x00000000000000000000000000000000000000aa"}
+
+	checkSpender(e, table)
+	if !e.SpenderAlerted {
+		t.Fatal("expected alert for an unknown spender")
+	}
+
+	e.Spender = "0x6131b5fae19ea4f9d964eac0408e4408b66337b5"
+	checkSpender(e, table)
+	if e.SpenderAlerted {
+		t.Fatal("expected alert to re-arm on a known spender")
+	}
+
+	// No reported spender or no registry entries for the solver: no alert.
+	for _, tc := range []struct{ solver, spender string }{
+		{"kyberswap", ""},
+		{"odos", "0x00000000000000000000000000000000000000aa"},
+	} {
+		e.RouteSolver, e.Spender = tc.solver, tc.spender
+		checkSpender(e, table)
+		if e.SpenderAlerted {
+			t.Fatalf("unexpected alert for %+v", tc)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package monitor
+
+import (
+	"fmt"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// checkQuoteSpread alerts when the quote spread of a healthy endpoint (how
+// far the market price beats the Balancer-only quote, see
+// collector.QuoteSpreadBps) has widened persistently. records is the
+// endpoint's history ending with this check; the alert fires when every one of
+// its last n spreads (n = checks) exceeds the average of the n before them by
+// more than thresholdBps, i.e. competitors are increasingly beating the
+// pool. Like checkPriceImpact it alerts once per excursion and leaves the
+// status alone; checks without both quotes are skipped. A thresholdBps of 0
+// disables the check.
+func checkQuoteSpread(endpoint *collector.Endpoint, records []collector.CheckRecord, thresholdBps float64, checks int) {
+	if thresholdBps <= 0 || checks <= 0 || endpoint.LastStatus != "up" {
+		return
+	}
+	if _, ok := endpoint.SpreadBps(); !ok {
+		return
+	}
+	var spreads []float64
+	for _, r := range records {
+		if bps, ok := r.SpreadBps(); ok && r.Status == "up" {
+			spreads = append(spreads, bps)
+		}
+	}
+	if len(spreads) < 2*checks {
+		return
+	}
+	spreads = spreads[len(spreads)-2*checks:]
+	baseline, recent := mean(spreads[:checks]), spreads[checks:]
+	for _, bps := range recent {
+		if bps <= baseline+thresholdBps {
+			endpoint.SpreadAlerted = false
+			return
+		}
+	}
+	if endpoint.SpreadAlerted {
+		return
+	}
+	endpoint.SpreadAlerted = true
+	message := fmt.Sprintf("Quote spread widened: market price beat the Balancer-only quote by %.1f bps on average over the last %d checks, up from %.1f bps",
+		mean(recent), checks, baseline)
+	message += surgeNote(endpoint)
+	logs.Printf("%s[SPREAD]%s %s: %s\n", config.ColorYellow, config.ColorReset, endpoint.Name, message)
+	notify.SendEndpointAlert(endpoint, message, "")
+}
+
+// mean returns the average of values, which must not be empty.
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"strconv"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+// spreadRecords returns up records whose spreads are the given bps over a
+// market price of 10000.
+func spreadRecords(bps ...int) []collector.CheckRecord {
+	var out []collector.CheckRecord
+	for _, b := range bps {
+		out = append(out, collector.CheckRecord{Status: "up", ReturnAmount: strconv.Itoa(10000 - b), MarketPrice: "10000"})
+	}
+	return out
+}
+
+func TestCheckQuoteSpreadAlertsOncePerExcursion(t *testing.T) {
+	e := &collector.Endpoint{Name: "x", LastStatus: "up", ReturnAmount: "9960", MarketPrice: "10000"}
+
+	// Widened past baseline (5 bps) + 25 bps on each of the last 3 checks.
+	checkQuoteSpread(e, spreadRecords(5, 5, 5, 40, 35, 40), 25, 3)
+	if !e.SpreadAlerted {
+		t.Fatal("expected alert when the spread widens on every recent check")
+	}
+
+	// One recent check back near the baseline re-arms the alert.
+	checkQuoteSpread(e, spreadRecords(5, 5, 5, 40, 10, 40), 25, 3)
+	if e.SpreadAlerted {
+		t.Fatal("expected alert to re-arm when the spread narrows")
+	}
+
+	// Too little history, a failing check and a disabled alert leave the state alone.
+	e.SpreadAlerted = false
+	checkQuoteSpread(e, spreadRecords(40, 40, 40), 25, 3)
+	e.LastStatus = "down"
+	checkQuoteSpread(e, spreadRecords(5, 5, 5, 40, 40, 40), 25, 3)
+	e.LastStatus = "up"
+	checkQuoteSpread(e, spreadRecords(5, 5, 5, 40, 40, 40), 0, 3)
+	if e.SpreadAlerted {
+		t.Fatal("expected no alert")
+	}
+}
@@ -0,0 +1,160 @@
+package monitor
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/sources"
+)
+
+var (
+	supportMu sync.Mutex
+	// support holds the coverage cells this process confirmed, keyed
+	// "solver|network|kind". SupportMatrix merges in the store's, so a
+	// restart or another instance keeps the first-seen dates.
+	support = map[string]store.Support{}
+)
+
+// recordSupport confirms support at now for an up check's provider, network
+// and pool kind, in memory and in the registered store, if any. Other
+// statuses confirm nothing: support is never unconfirmed, only not
+// confirmed lately.
+func recordSupport(e *collector.Endpoint, now time.Time) {
+	if e.LastStatus != "up" {
+		return
+	}
+	cell := store.Support{Solver: e.RouteSolver, Network: e.Network, PoolKind: sources.KindOf(e), FirstSeen: now, LastConfirmed: now}
+	key := cell.Solver + "|" + cell.Network + "|" + cell.PoolKind
+	supportMu.Lock()
+	if cur, ok := support[key]; ok {
+		cell = cur.Merge(cell)
+	}
+	support[key] = cell
+	supportMu.Unlock()
+
+	if st := GetStore(); st != nil {
+		if err := st.SaveSupport(cell); err != nil {
+			logs.Printf("%s[STORE]%s %s: save support failed: %v\n", config.ColorRed, config.ColorReset, e.Name, err)
+		}
+	}
+}
+
+// SupportMatrix returns every coverage cell, this process's merged with the
+// registered store's.
+func SupportMatrix() []store.Support {
+	supportMu.Lock()
+	merged := make(map[string]store.Support, len(support))
+	for k, c := range support {
+		merged[k] = c
+	}
+	supportMu.Unlock()
+
+	if st := GetStore(); st != nil {
+		stored, err := st.LoadSupport()
+		if err != nil {
+			logs.Printf("%s[STORE]%s load support failed: %v\n", config.ColorRed, config.ColorReset, err)
+		}
+		for _, c := range stored {
+			key := c.Solver + "|" + c.Network + "|" + c.PoolKind
+			if cur, ok := merged[key]; ok {
+				c = cur.Merge(c)
+			}
+			merged[key] = c
+		}
+	}
+	out := make([]store.Support, 0, len(merged))
+	for _, c := range merged {
+		out = append(out, c)
+	}
+	return out
+}
+
+// CoverageCell is a provider, network and pool kind of the coverage page:
+// when support was first seen and last confirmed (zero when never), and what
+// the rows checking it report now.
+type CoverageCell struct {
+	Solver        string    `json:"solver"`
+	Network       string    `json:"network"`
+	PoolKind      string    `json:"poolKind"`
+	FirstSeen     time.Time `json:"firstSeen"`
+	LastConfirmed time.Time `json:"lastConfirmed"`
+	// Status is "up" when any row is up, otherwise the most telling status
+	// of the rows (down, then info such as a WIP integration, then the
+	// rest); empty when no row checks the cell any more.
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Rows    int    `json:"rows"`
+}
+
+// Coverage builds the coverage cells of the solvers from the rows checking
+// them and the support matrix, ordered as solvers, then by pool kind and
+// network.
+func Coverage(solvers []config.RouteSolver, endpoints []collector.Endpoint, matrix []store.Support) []CoverageCell {
+	order := map[string]int{}
+	for i, s := range solvers {
+		order[s.Type] = i
+	}
+	cells := map[string]*CoverageCell{}
+	cell := func(solver, network, kind string) *CoverageCell {
+		key := solver + "|" + network + "|" + kind
+		if cells[key] == nil {
+			cells[key] = &CoverageCell{Solver: solver, Network: network, PoolKind: kind}
+		}
+		return cells[key]
+	}
+	for _, c := range matrix {
+		if _, ok := order[c.Solver]; ok {
+			cc := cell(c.Solver, c.Network, c.PoolKind)
+			cc.FirstSeen, cc.LastConfirmed = c.FirstSeen, c.LastConfirmed
+		}
+	}
+	for i := range endpoints {
+		e := &endpoints[i]
+		if _, ok := order[e.RouteSolver]; !ok {
+			continue
+		}
+		cc := cell(e.RouteSolver, e.Network, sources.KindOf(e))
+		cc.Rows++
+		if cc.Rows == 1 || coverageRank(e.LastStatus) < coverageRank(cc.Status) {
+			cc.Status, cc.Message = e.LastStatus, e.Message
+		}
+	}
+
+	out := make([]CoverageCell, 0, len(cells))
+	for _, c := range cells {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Solver != b.Solver {
+			return order[a.Solver] < order[b.Solver]
+		}
+		if a.PoolKind != b.PoolKind {
+			return a.PoolKind < b.PoolKind
+		}
+		return a.Network < b.Network
+	})
+	return out
+}
+
+// coverageRank orders statuses by how much they tell about a cell, lowest
+// first.
+func coverageRank(status string) int {
+	switch {
+	case status == "up":
+		return 0
+	case collector.IsDownStatus(status):
+		return 1
+	case status == "info":
+		return 2
+	case status == collector.StatusNotApplicable:
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/store"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestRecordSupportKeepsFirstSeen(t *testing.T) {
+	t.Cleanup(func() { support = map[string]store.Support{} })
+	first := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	e := collector.Endpoint{Name: "KyberSwap-Stable", RouteSolver: "kyberswap", Network: "1", LastStatus: "up"}
+	recordSupport(&e, first)
+	e.LastStatus = "down"
+	recordSupport(&e, first.Add(time.Hour))
+	e.LastStatus = "up"
+	recordSupport(&e, first.Add(2*time.Hour))
+
+	got := SupportMatrix()
+	if len(got) != 1 || got[0].PoolKind != "stable" || !got[0].FirstSeen.Equal(first) || !got[0].LastConfirmed.Equal(first.Add(2*time.Hour)) {
+		t.Fatalf("matrix = %+v", got)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	solvers := []config.RouteSolver{{Type: "kyberswap"}, {Type: "1inch"}}
+	endpoints := []collector.Endpoint{
+		{Name: "KyberSwap-Stable", RouteSolver: "kyberswap", Network: "1", LastStatus: "down"},
+		{Name: "KyberSwap-Stable-2", RouteSolver: "kyberswap", Network: "1", LastStatus: "up"},
+		{Name: "1inch-GyroE", RouteSolver: "1inch", Network: "1", LastStatus: "info", Message: "1inch GyroE integration WIP"},
+		{Name: "Odos-Stable", RouteSolver: "odos", Network: "1", LastStatus: "up"}, // disabled
+	}
+	matrix := []store.Support{
+		{Solver: "kyberswap", Network: "1", PoolKind: "stable", FirstSeen: at, LastConfirmed: at},
+		{Solver: "kyberswap", Network: "8453", PoolKind: "gyro", FirstSeen: at, LastConfirmed: at}, // no row checks it now
+	}
+
+	got := Coverage(solvers, endpoints, matrix)
+	if len(got) != 3 {
+		t.Fatalf("cells = %+v", got)
+	}
+	if c := got[0]; c.PoolKind != "gyro" || c.Network != "8453" || c.Rows != 0 || c.Status != "" {
+		t.Fatalf("unchecked cell = %+v", c)
+	}
+	if c := got[1]; c.PoolKind != "stable" || c.Rows != 2 || c.Status != "up" || !c.FirstSeen.Equal(at) {
+		t.Fatalf("supported cell = %+v", c)
+	}
+	if c := got[2]; c.Solver != "1inch" || c.PoolKind != "gyro" || c.Status != "info" || c.Message != "1inch GyroE integration WIP" || !c.FirstSeen.IsZero() {
+		t.Fatalf("WIP cell = %+v", c)
+	}
+}
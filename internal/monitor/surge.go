@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+// surgeStateTTL is how long a pool's surge state read serves further checks:
+// a cycle's solver rows of one pool share a read, the next cycle reads again.
+const surgeStateTTL = time.Minute
+
+// surgeRead is a pool's cached surge state read.
+type surgeRead struct {
+	at    time.Time
+	state collector.SurgeState
+	err   error
+}
+
+var (
+	surgeMu sync.Mutex
+	// surgeReads are the latest reads by collector.PoolKey.
+	surgeReads = map[string]surgeRead{}
+	// querySurgeState reads a pool's state; replaced in tests.
+	querySurgeState = providers.QuerySurgeState
+)
+
+// recordSurgeState sets a StableSurge endpoint's Surge from an on-chain read
+// of its pool at now, shared with the pool's other rows for surgeStateTTL,
+// and logs it when the read was fresh. Other endpoints, and endpoints without
+// an RPC URL for their network, get a zero state. Skipped in dry-run and
+// local dev.
+func recordSurgeState(endpoint *collector.Endpoint, now time.Time) {
+	endpoint.Surge = collector.SurgeState{}
+	if endpoint.ExpectedPool == "" || !slices.Contains(PoolTypesOf(endpoint), PoolTypeStableSurge) ||
+		config.GetRPCURL(endpoint.Network) == "" || config.GetDryRunEnabled() || config.GetLocalDevEnabled() {
+		return
+	}
+	key := collector.PoolKey(endpoint.Network, endpoint.ExpectedPool)
+	surgeMu.Lock()
+	read, ok := surgeReads[key]
+	surgeMu.Unlock()
+	if !ok || now.Sub(read.at) >= surgeStateTTL {
+		state, err := querySurgeState(endpoint.Network, endpoint.ExpectedPool)
+		read = surgeRead{at: now, state: state, err: err}
+		surgeMu.Lock()
+		surgeReads[key] = read
+		surgeMu.Unlock()
+		pool := config.NetworkName(endpoint.Network) + " " + config.NormalizeAddress(endpoint.ExpectedPool)
+		switch {
+		case err != nil:
+			logs.Printf("%s[SURGE]%s %s: surge state read failed: %v\n", config.ColorYellow, config.ColorReset, pool, err)
+		case state.Surging():
+			logs.Printf("%s[SURGE]%s %s: surging, %s\n", config.ColorOrange, config.ColorReset, pool, state)
+		default:
+			logs.Printf("%s[SURGE]%s %s: %s\n", config.ColorCyan, config.ColorReset, pool, state)
+		}
+	}
+	endpoint.Surge = read.state
+}
+
+// surgeNote is appended to quote anomaly alerts of a surging pool, so the
+// anomaly isn't blamed on the aggregator; "" otherwise.
+func surgeNote(endpoint *collector.Endpoint) string {
+	if !endpoint.Surge.Surging() {
+		return ""
+	}
+	return "; the pool is surging, " + endpoint.Surge.String()
+}
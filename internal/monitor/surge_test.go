@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/providers"
+)
+
+func TestRecordSurgeStateSharesReads(t *testing.T) {
+	reads := 0
+	querySurgeState = func(network, pool string) (collector.SurgeState, error) {
+		reads++
+		if pool == "0xbad" {
+			return collector.SurgeState{}, errors.New("execution reverted")
+		}
+		return collector.SurgeState{Block: uint64(reads), StaticFee: 0.001, MaxFee: 0.05, Threshold: 0.3, Imbalance: 0.4, Fee: 0.008}, nil
+	}
+	t.Cleanup(func() {
+		surgeReads = map[string]surgeRead{}
+		querySurgeState = providers.QuerySurgeState
+	})
+	t.Setenv("BASE_RPC_URL", "http://node")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	kyber := collector.Endpoint{Name: "KyberSwap-Base-Boosted-StableSurge(GHO/USDC)", Network: "8453", ExpectedPool: "0xPool"}
+	odos := collector.Endpoint{Name: "Odos-Base-Boosted-StableSurge(GHO/USDC)", Network: "8453", ExpectedPool: "0xpool"}
+	recordSurgeState(&kyber, now)
+	recordSurgeState(&odos, now.Add(10*time.Second))
+	if reads != 1 || kyber.Surge.Block != 1 || odos.Surge != kyber.Surge || !odos.Surge.Surging() {
+		t.Fatalf("one pool's rows: %d reads, %+v / %+v", reads, kyber.Surge, odos.Surge)
+	}
+	// The next cycle reads again.
+	recordSurgeState(&kyber, now.Add(surgeStateTTL))
+	if reads != 2 || kyber.Surge.Block != 2 {
+		t.Fatalf("next cycle: %d reads, %+v", reads, kyber.Surge)
+	}
+
+	// Other pools, a failed read and a network without an RPC URL leave it zero.
+	stable := collector.Endpoint{Name: "Base-Boosted-Stable(WETH/wstETH)", Network: "8453", ExpectedPool: "0xother", Surge: kyber.Surge}
+	failed := collector.Endpoint{Name: "Base-StableSurge(USDC/USDT)", Network: "8453", ExpectedPool: "0xbad", Surge: kyber.Surge}
+	noRPC := collector.Endpoint{Name: "Gnosis-StableSurge(USDC/USDT)", Network: "100", ExpectedPool: "0xpool", Surge: kyber.Surge}
+	for _, e := range []*collector.Endpoint{&stable, &failed, &noRPC} {
+		recordSurgeState(e, now)
+		if !e.Surge.IsZero() {
+			t.Errorf("%s: surge = %+v", e.Name, e.Surge)
+		}
+	}
+	if reads != 3 {
+		t.Fatalf("reads = %d, want only the failed pool's", reads)
+	}
+}
+
+func TestSurgeNote(t *testing.T) {
+	e := &collector.Endpoint{Surge: collector.SurgeState{Block: 7, StaticFee: 0.001, MaxFee: 0.05, Threshold: 0.3, Imbalance: 0.5, Fee: 0.015}}
+	if got := surgeNote(e); got != "; the pool is surging, surge fee 1.50% (static 0.10%, imbalance 50.0% > threshold 30.0%) at block 7" {
+		t.Fatalf("note = %q", got)
+	}
+	e.Surge.Imbalance = 0.2
+	if got := surgeNote(e); got != "" {
+		t.Fatalf("below threshold note = %q", got)
+	}
+}
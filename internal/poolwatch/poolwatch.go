@@ -0,0 +1,285 @@
+// Package poolwatch watches Balancer V3 pool factories for new pools. The
+// factories and the filters a new pool must match (network, pool type, token
+// set) come from POOL_WATCH_FILE:
+//
+//	{
+//	  "factories": [{"network": "8453", "address": "0x…", "poolType": "StableSurge"}],
+//	  "filters":   [{"network": "8453", "poolType": "StableSurge", "tokens": ["USDC"]}]
+//	}
+//
+// A pool matching any filter (every pool when there are none) is announced
+// and listed on /pools/new, where "Add to monitoring" imports it as a
+// BaseEndpoint (EndpointRow).
+package poolwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/importer"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/monitor"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/providers"
+)
+
+// maxPools bounds how many matched pools are kept for /pools/new.
+const maxPools = 100
+
+// Factory is a pool factory to watch. PoolType names the pools it creates
+// (e.g. "Stable", "StableSurge") for filters and endpoint names.
+type Factory struct {
+	Network  string `json:"network"`
+	Address  string `json:"address"`
+	PoolType string `json:"poolType"`
+}
+
+// Filter selects new pools. Empty fields match anything; Tokens must all be
+// among the pool's tokens, each given as an address or a symbol (case
+// insensitive).
+type Filter struct {
+	Network  string   `json:"network,omitempty"`
+	PoolType string   `json:"poolType,omitempty"`
+	Tokens   []string `json:"tokens,omitempty"`
+}
+
+// Settings is the POOL_WATCH_FILE content.
+type Settings struct {
+	Factories []Factory `json:"factories"`
+	Filters   []Filter  `json:"filters,omitempty"`
+}
+
+// Pool is a new pool that matched the filters.
+type Pool struct {
+	Network  string
+	Address  string
+	Factory  string
+	PoolType string
+	Tokens   []providers.TokenInfo
+	Block    uint64
+	SeenAt   time.Time
+}
+
+// Symbols joins the pool's token symbols, e.g. "GHO/USDC".
+func (p Pool) Symbols() string {
+	symbols := make([]string, len(p.Tokens))
+	for i, t := range p.Tokens {
+		symbols[i] = t.Symbol
+	}
+	return strings.Join(symbols, "/")
+}
+
+// Matches reports whether p passes the filter.
+func (f Filter) Matches(p Pool) bool {
+	if f.Network != "" && f.Network != p.Network {
+		return false
+	}
+	if f.PoolType != "" && !strings.EqualFold(f.PoolType, p.PoolType) {
+		return false
+	}
+	for _, want := range f.Tokens {
+		found := false
+		for _, t := range p.Tokens {
+			if config.SameAddress(want, t.Address) || strings.EqualFold(want, t.Symbol) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether p passes any filter, or there are none.
+func (s Settings) Matches(p Pool) bool {
+	if len(s.Filters) == 0 {
+		return true
+	}
+	for _, f := range s.Filters {
+		if f.Matches(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads and validates POOL_WATCH_FILE.
+func Load(path string) (Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Settings{}, err
+	}
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Settings{}, err
+	}
+	if len(s.Factories) == 0 {
+		return Settings{}, fmt.Errorf("no factories")
+	}
+	for i, f := range s.Factories {
+		if f.Network == "" || !common.IsHexAddress(f.Address) {
+			return Settings{}, fmt.Errorf("factory %d: network and a valid address are required", i+1)
+		}
+	}
+	return s, nil
+}
+
+var (
+	poolsMu sync.Mutex
+	pools   []Pool // newest first
+
+	// Swapped in tests.
+	poolsCreatedFn = providers.PoolsCreated
+	poolTokensFn   = providers.PoolTokens
+)
+
+// Pools returns the matched pools, newest first.
+func Pools() []Pool {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	return append([]Pool(nil), pools...)
+}
+
+// Lookup returns the matched pool at address on network.
+func Lookup(network, address string) (Pool, bool) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	for _, p := range pools {
+		if p.Network == network && config.SameAddress(p.Address, address) {
+			return p, true
+		}
+	}
+	return Pool{}, false
+}
+
+func addPool(p Pool) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	pools = append([]Pool{p}, pools...)
+	if len(pools) > maxPools {
+		pools = pools[:maxPools]
+	}
+}
+
+// Run polls the factories in POOL_WATCH_FILE every interval for new pools.
+// Only the leader polls and dry runs make no requests. Each network starts
+// from its head at the first poll, so pools created while no instance was
+// polling are not reported.
+func Run(interval time.Duration) {
+	path := config.GetPoolWatchFile()
+	if path == "" {
+		return
+	}
+	settings, err := Load(path)
+	if err != nil {
+		logs.Printf("%s[ERROR]%s: POOL_WATCH_FILE %s: %v; pool creation watch disabled\n", config.ColorRed, config.ColorReset, path, err)
+		return
+	}
+	logs.Printf("%s[POOL WATCH]%s watching %d factories with %d filters\n", config.ColorBlue, config.ColorReset, len(settings.Factories), len(settings.Filters))
+	cursors := map[string]uint64{}
+	for {
+		if monitor.IsLeader() && !config.GetDryRunEnabled() {
+			poll(settings, cursors, time.Now())
+		}
+		time.Sleep(interval)
+	}
+}
+
+// poll scans each network's factories once from its cursor, advancing it,
+// and returns the pools that matched.
+func poll(settings Settings, cursors map[string]uint64, now time.Time) []Pool {
+	byNetwork := map[string][]Factory{}
+	var networks []string
+	for _, f := range settings.Factories {
+		if _, ok := byNetwork[f.Network]; !ok {
+			networks = append(networks, f.Network)
+		}
+		byNetwork[f.Network] = append(byNetwork[f.Network], f)
+	}
+
+	var matched []Pool
+	for _, network := range networks {
+		factories := byNetwork[network]
+		addresses := make([]string, len(factories))
+		for i, f := range factories {
+			addresses[i] = f.Address
+		}
+		created, last, err := poolsCreatedFn(network, addresses, cursors[network])
+		if err != nil {
+			logs.Printf("%s[POOL WATCH]%s %s: %v\n", config.ColorYellow, config.ColorReset, config.NetworkName(network), err)
+			continue
+		}
+		cursors[network] = last
+		for _, c := range created {
+			tokens, err := poolTokensFn(network, c.Pool)
+			if err != nil {
+				logs.Printf("%s[POOL WATCH]%s %s pool %s: %v\n", config.ColorYellow, config.ColorReset, config.NetworkName(network), c.Pool, err)
+				continue
+			}
+			p := Pool{Network: network, Address: c.Pool, Factory: c.Factory, PoolType: factoryType(factories, c.Factory), Tokens: tokens, Block: c.Block, SeenAt: now}
+			if !settings.Matches(p) {
+				continue
+			}
+			addPool(p)
+			announce(p)
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+func factoryType(factories []Factory, address string) string {
+	for _, f := range factories {
+		if config.SameAddress(f.Address, address) {
+			return f.PoolType
+		}
+	}
+	return ""
+}
+
+// announce sends the new-pool notice with a link to /pools/new.
+func announce(p Pool) {
+	link := "/pools/new"
+	if base := config.GetPublicURL(); base != "" {
+		link = base + link
+	}
+	msg := fmt.Sprintf("New %s pool on %s: %s (%s) at block %d; add it to monitoring at %s",
+		p.PoolType, config.NetworkName(p.Network), p.Address, p.Symbols(), p.Block, link)
+	logs.Printf("%s[POOL WATCH]%s %s\n", config.ColorGreen, config.ColorReset, msg)
+	notify.Send(notify.SeverityInfo, msg)
+}
+
+// EndpointRow returns the BaseEndpoint import row for p: a direct swap from
+// its first token to its second through the pool, named like the hand-written
+// BaseEndpoints (e.g. "Base-StableSurge(GHO/USDC)"). The swap amount is left
+// for importer.Prepare to size.
+func EndpointRow(p Pool) (importer.Row, error) {
+	if len(p.Tokens) < 2 {
+		return importer.Row{}, fmt.Errorf("pool %s has %d tokens; need two", p.Address, len(p.Tokens))
+	}
+	in, out := p.Tokens[0], p.Tokens[1]
+	network := config.NetworkName(p.Network)
+	name := strings.ToUpper(network[:1]) + network[1:]
+	if p.PoolType != "" {
+		name += "-" + p.PoolType
+	}
+	return importer.Row{
+		Name:             fmt.Sprintf("%s(%s/%s)", name, in.Symbol, out.Symbol),
+		Network:          p.Network,
+		TokenIn:          in.Address,
+		TokenOut:         out.Address,
+		TokenInDecimals:  in.Decimals,
+		TokenOutDecimals: out.Decimals,
+		ExpectedPool:     p.Address,
+		ExpectedNoHops:   1,
+	}, nil
+}
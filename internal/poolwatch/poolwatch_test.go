@@ -0,0 +1,131 @@
+package poolwatch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/providers"
+)
+
+var (
+	gho  = providers.TokenInfo{Address: "0x6Bb7a212910682DCFdbd5BCBb3e28FB4E8da10Ee", Symbol: "GHO", Decimals: 18}
+	usdc = providers.TokenInfo{Address: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", Symbol: "USDC", Decimals: 6}
+)
+
+func TestFilterMatches(t *testing.T) {
+	p := Pool{Network: "8453", PoolType: "StableSurge", Tokens: []providers.TokenInfo{gho, usdc}}
+	for _, tc := range []struct {
+		filter Filter
+		want   bool
+	}{
+		{Filter{}, true},
+		{Filter{Network: "8453", PoolType: "stablesurge"}, true},
+		{Filter{Network: "1"}, false},
+		{Filter{PoolType: "Weighted"}, false},
+		{Filter{Tokens: []string{"usdc", gho.Address}}, true},
+		{Filter{Tokens: []string{" 6bb7a212910682dcfdbd5bcbb3e28fb4e8da10ee"}}, true},
+		{Filter{Tokens: []string{"USDC", "WETH"}}, false},
+	} {
+		if got := tc.filter.Matches(p); got != tc.want {
+			t.Errorf("%+v.Matches = %v, want %v", tc.filter, got, tc.want)
+		}
+	}
+
+	if !(Settings{}).Matches(p) {
+		t.Error("no filters should match every pool")
+	}
+	s := Settings{Filters: []Filter{{Network: "1"}, {Tokens: []string{"GHO"}}}}
+	if !s.Matches(p) {
+		t.Error("expected a match on the second filter")
+	}
+}
+
+func TestLoadValidates(t *testing.T) {
+	dir := t.TempDir()
+	for name, tc := range map[string]struct {
+		body    string
+		wantErr bool
+	}{
+		"ok":          {`{"factories":[{"network":"8453","address":"0x0000000000000000000000000000000000000001","poolType":"Stable"}]}`, false},
+		"no factory":  {`{"factories":[]}`, true},
+		"bad address": {`{"factories":[{"network":"8453","address":"0x12"}]}`, true},
+		"no network":  {`{"factories":[{"address":"0x0000000000000000000000000000000000000001"}]}`, true},
+	} {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(tc.body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); (err != nil) != tc.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestPollAddsMatchingPools(t *testing.T) {
+	origCreated, origTokens := poolsCreatedFn, poolTokensFn
+	t.Cleanup(func() {
+		poolsCreatedFn, poolTokensFn = origCreated, origTokens
+		pools = nil
+	})
+	pools = nil
+
+	factory := "0x0000000000000000000000000000000000000f01"
+	poolsCreatedFn = func(network string, factories []string, from uint64) ([]providers.CreatedPool, uint64, error) {
+		if from == 0 {
+			return nil, 100, nil
+		}
+		return []providers.CreatedPool{
+			{Pool: "0x00000000000000000000000000000000000000a1", Factory: factory, Block: 101},
+			{Pool: "0x00000000000000000000000000000000000000a2", Factory: factory, Block: 102},
+		}, 150, nil
+	}
+	poolTokensFn = func(network, pool string) ([]providers.TokenInfo, error) {
+		if pool == "0x00000000000000000000000000000000000000a1" {
+			return []providers.TokenInfo{gho, usdc}, nil
+		}
+		return []providers.TokenInfo{{Address: "0x01", Symbol: "WETH"}, usdc}, nil
+	}
+
+	settings := Settings{
+		Factories: []Factory{{Network: "8453", Address: factory, PoolType: "StableSurge"}},
+		Filters:   []Filter{{Tokens: []string{"GHO"}}},
+	}
+	cursors := map[string]uint64{}
+	now := time.Now()
+
+	if got := poll(settings, cursors, now); len(got) != 0 || cursors["8453"] != 100 {
+		t.Fatalf("first poll: matched %d, cursor %d; want 0 and the head", len(got), cursors["8453"])
+	}
+	got := poll(settings, cursors, now)
+	if len(got) != 1 || got[0].Address != "0x00000000000000000000000000000000000000a1" || got[0].PoolType != "StableSurge" || got[0].Block != 101 {
+		t.Fatalf("second poll matched %+v", got)
+	}
+	if cursors["8453"] != 150 {
+		t.Errorf("cursor = %d, want 150", cursors["8453"])
+	}
+	if _, ok := Lookup("8453", "0x00000000000000000000000000000000000000A1"); !ok {
+		t.Error("matched pool not found by Lookup")
+	}
+	if _, ok := Lookup("8453", "0x00000000000000000000000000000000000000a2"); ok {
+		t.Error("filtered-out pool should not be listed")
+	}
+}
+
+func TestEndpointRow(t *testing.T) {
+	p := Pool{Network: "8453", Address: "0x00000000000000000000000000000000000000a1", PoolType: "StableSurge", Tokens: []providers.TokenInfo{gho, usdc}}
+	row, err := EndpointRow(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row.Name != "Base-StableSurge(GHO/USDC)" || row.TokenIn != gho.Address || row.TokenOut != usdc.Address ||
+		row.TokenInDecimals != 18 || row.TokenOutDecimals != 6 || row.ExpectedPool != p.Address || row.ExpectedNoHops != 1 || row.SwapAmount != "" {
+		t.Errorf("row = %+v", row)
+	}
+
+	p.Tokens = p.Tokens[:1]
+	if _, err := EndpointRow(p); err == nil {
+		t.Error("expected an error for a single-token pool")
+	}
+}
@@ -0,0 +1,106 @@
+// Package rules evaluates config-declared endpoint assertions
+// (config.EndpointRule) against a parsed provider response, so bespoke
+// per-endpoint requirements don't need new Go validation code in a handler.
+package rules
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+// Evaluate checks every rule attached to the endpoint and returns one message
+// per failed assertion; nil means all rules passed. It reads the fields a
+// handler fills in while parsing: ReturnAmount, RouteSources and the price
+// impact.
+func Evaluate(e *collector.Endpoint) []string {
+	var failures []string
+	for _, r := range e.Rules {
+		if !r.AppliesTo(e.RouteSolver) {
+			continue
+		}
+		failures = append(failures, evaluateRule(r, e)...)
+	}
+	return failures
+}
+
+func evaluateRule(r config.EndpointRule, e *collector.Endpoint) []string {
+	var failures []string
+
+	if r.MinReturnAmount != "" {
+		min, ok := new(big.Int).SetString(r.MinReturnAmount, 10)
+		got, gotOK := new(big.Int).SetString(e.ReturnAmount, 10)
+		switch {
+		case !ok:
+			failures = append(failures, fmt.Sprintf("minReturnAmount: invalid rule value %q", r.MinReturnAmount))
+		case !gotOK:
+			failures = append(failures, fmt.Sprintf("minReturnAmount: return amount %q is not an integer", e.ReturnAmount))
+		case got.Cmp(min) < 0:
+			failures = append(failures, fmt.Sprintf("minReturnAmount: got %s, want >= %s", got, min))
+		}
+	}
+
+	if r.MaxPriceImpactBps > 0 && e.HasPriceImpact && e.PriceImpactBps > r.MaxPriceImpactBps {
+		failures = append(failures, fmt.Sprintf("maxPriceImpactBps: got %.1f, want <= %.1f", e.PriceImpactBps, r.MaxPriceImpactBps))
+	}
+
+	if e.RouteSources != nil {
+		for _, want := range r.RequiredSources {
+			if !anySourceMatches(e.RouteSources, want) {
+				failures = append(failures, fmt.Sprintf("requiredSources: %s not in route %v", want, e.RouteSources))
+			}
+		}
+		for _, banned := range r.ForbiddenSources {
+			if anySourceMatches(e.RouteSources, banned) {
+				failures = append(failures, fmt.Sprintf("forbiddenSources: %s found in route %v", banned, e.RouteSources))
+			}
+		}
+	}
+
+	return failures
+}
+
+// MarketLead checks the MarketLeadCycles rules attached to the endpoint
+// against its check history, records oldest first and ending with the current
+// check, and returns the failure message, or "" when no rule triggers. The
+// streak counts the latest consecutive up checks whose market price beat the
+// Balancer-only quote; any other check ends it.
+func MarketLead(e *collector.Endpoint, records []collector.CheckRecord) string {
+	cycles := 0
+	for _, r := range e.Rules {
+		if r.MarketLeadCycles > 0 && r.AppliesTo(e.RouteSolver) && (cycles == 0 || r.MarketLeadCycles < cycles) {
+			cycles = r.MarketLeadCycles
+		}
+	}
+	if cycles == 0 {
+		return ""
+	}
+	streak, latest := 0, 0.0
+	for i := len(records) - 1; i >= 0; i-- {
+		bps, ok := records[i].SpreadBps()
+		if records[i].Status != "up" || !ok || bps <= 0 {
+			break
+		}
+		if streak == 0 {
+			latest = bps
+		}
+		streak++
+	}
+	if streak < cycles {
+		return ""
+	}
+	return fmt.Sprintf("marketLeadCycles: market price beat the Balancer-only quote on %d consecutive checks (limit %d), by %.1f bps on the latest", streak, cycles, latest)
+}
+
+func anySourceMatches(sources []string, pattern string) bool {
+	p := strings.ToLower(pattern)
+	for _, s := range sources {
+		if strings.Contains(strings.ToLower(s), p) {
+			return true
+		}
+	}
+	return false
+}
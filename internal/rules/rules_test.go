@@ -0,0 +1,93 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestEvaluate(t *testing.T) {
+	base := collector.Endpoint{
+		RouteSolver:    "kyberswap",
+		ReturnAmount:   "1000",
+		RouteSources:   []string{"balancer-v3-stable", "balancer-v3-stable"},
+		PriceImpactBps: 12,
+		HasPriceImpact: true,
+	}
+
+	cases := []struct {
+		name  string
+		rule  config.EndpointRule
+		edit  func(*collector.Endpoint)
+		fails []string // substrings, one per expected failure
+	}{
+		{name: "min return met", rule: config.EndpointRule{MinReturnAmount: "1000"}},
+		{name: "min return missed", rule: config.EndpointRule{MinReturnAmount: "1001"}, fails: []string{"minReturnAmount: got 1000"}},
+		{name: "price impact over", rule: config.EndpointRule{MaxPriceImpactBps: 10}, fails: []string{"maxPriceImpactBps"}},
+		{
+			name: "price impact unknown is skipped",
+			rule: config.EndpointRule{MaxPriceImpactBps: 10},
+			edit: func(e *collector.Endpoint) { e.HasPriceImpact = false },
+		},
+		{name: "required source matches by substring", rule: config.EndpointRule{RequiredSources: []string{"Balancer-V3"}}},
+		{name: "required source missing", rule: config.EndpointRule{RequiredSources: []string{"curve"}}, fails: []string{"requiredSources: curve"}},
+		{name: "forbidden source present", rule: config.EndpointRule{ForbiddenSources: []string{"stable"}}, fails: []string{"forbiddenSources: stable"}},
+		{
+			name: "sources unknown are skipped",
+			rule: config.EndpointRule{RequiredSources: []string{"curve"}},
+			edit: func(e *collector.Endpoint) { e.RouteSources = nil },
+		},
+		{name: "other solver's rule ignored", rule: config.EndpointRule{Solvers: []string{"odos"}, MinReturnAmount: "5000"}},
+		{
+			name:  "multiple failures",
+			rule:  config.EndpointRule{Solvers: []string{"KyberSwap"}, MinReturnAmount: "5000", ForbiddenSources: []string{"balancer"}},
+			fails: []string{"minReturnAmount", "forbiddenSources"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := base
+			if tc.edit != nil {
+				tc.edit(&e)
+			}
+			e.Rules = []config.EndpointRule{tc.rule}
+			got := Evaluate(&e)
+			if len(got) != len(tc.fails) {
+				t.Fatalf("Evaluate = %q, want %d failures", got, len(tc.fails))
+			}
+			for i, want := range tc.fails {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("failure %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestMarketLead(t *testing.T) {
+	lead := collector.CheckRecord{Status: "up", ReturnAmount: "990", MarketPrice: "1000"}
+	even := collector.CheckRecord{Status: "up", ReturnAmount: "1000", MarketPrice: "1000"}
+	down := collector.CheckRecord{Status: "down", ReturnAmount: "900", MarketPrice: "1000"}
+	e := &collector.Endpoint{RouteSolver: "paraswap", Rules: []config.EndpointRule{{MarketLeadCycles: 3}}}
+
+	got := MarketLead(e, []collector.CheckRecord{even, lead, lead, lead})
+	if !strings.Contains(got, "on 3 consecutive checks (limit 3), by 100.0 bps") {
+		t.Fatalf("MarketLead = %q", got)
+	}
+	for _, records := range [][]collector.CheckRecord{
+		{lead, lead},
+		{lead, even, lead, lead},
+		{lead, down, lead, lead},
+	} {
+		if got := MarketLead(e, records); got != "" {
+			t.Errorf("MarketLead(%v) = %q, want no trigger", records, got)
+		}
+	}
+
+	e.Rules = []config.EndpointRule{{Solvers: []string{"odos"}, MarketLeadCycles: 1}}
+	if got := MarketLead(e, []collector.CheckRecord{lead}); got != "" {
+		t.Errorf("other solver's rule triggered: %q", got)
+	}
+}
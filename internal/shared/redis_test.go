@@ -0,0 +1,117 @@
+package shared
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves PING / GET / SET [NX] PX / PTTL from a map, ignoring
+// expiry beyond what PTTL reports.
+func fakeRedis(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	data := map[string]string{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					args, err := readCommand(r)
+					if err != nil {
+						return
+					}
+					conn.Write([]byte(fakeReply(data, args)))
+				}
+			}()
+		}
+	}()
+	return "redis://" + ln.Addr().String()
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		v, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(v, "\r\n")
+	}
+	return args, nil
+}
+
+func fakeReply(data map[string]string, args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		v, ok := data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return "$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"
+	case "SET":
+		if len(args) > 3 && args[3] == "NX" {
+			if _, ok := data[args[1]]; ok {
+				return "$-1\r\n"
+			}
+		}
+		data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "PTTL":
+		return ":40\r\n"
+	}
+	return "-ERR unknown command\r\n"
+}
+
+func TestRedisBackend(t *testing.T) {
+	r, err := NewRedis(fakeRedis(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, ok, err := r.Get("missing"); ok || err != nil {
+		t.Fatalf("Get(missing) = %v, %v", ok, err)
+	}
+	if err := r.Set("openocean:dexlist:eth", "7,12", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := r.Get("openocean:dexlist:eth"); v != "7,12" || !ok || err != nil {
+		t.Fatalf("Get = %q, %v, %v", v, ok, err)
+	}
+
+	if ok, _, err := r.Reserve("ratelimit:odos", time.Second); !ok || err != nil {
+		t.Fatalf("first Reserve = %v, %v", ok, err)
+	}
+	ok, remaining, err := r.Reserve("ratelimit:odos", time.Second)
+	if ok || err != nil || remaining != 40*time.Millisecond {
+		t.Fatalf("second Reserve = %v, %v, %v", ok, remaining, err)
+	}
+}
+
+func TestNewRedisRejectsScheme(t *testing.T) {
+	if _, err := NewRedis("http://localhost:6379"); err == nil {
+		t.Fatal("http URL accepted")
+	}
+}
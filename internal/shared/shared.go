@@ -0,0 +1,144 @@
+// Package shared holds state that replicas and regional workers must agree
+// on: a key/value cache for provider metadata (e.g. OpenOcean's dexList) and
+// per-provider request spacing. In-memory by default; Redis when REDIS_URL
+// is set, so every instance draws on one provider quota.
+package shared
+
+import (
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+// Backend stores expiring string values.
+type Backend interface {
+	// Get returns the value for key, with ok == false when missing or expired.
+	Get(key string) (value string, ok bool, err error)
+	// Set stores value for ttl.
+	Set(key, value string, ttl time.Duration) error
+	// Reserve stores key for ttl only if it is not set. When it is, Reserve
+	// returns false and the time until it expires.
+	Reserve(key string, ttl time.Duration) (ok bool, remaining time.Duration, err error)
+}
+
+var (
+	backendMu sync.RWMutex
+	backend   Backend = NewMemory()
+)
+
+// Use replaces the process-wide backend. Call before starting the loops.
+func Use(b Backend) {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backend = b
+}
+
+func current() Backend {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend
+}
+
+// GetOrLoad returns the cached value for key, calling load on a miss and
+// caching its result for ttl. Backend errors are logged and treated as a
+// miss, so a Redis outage degrades to uncached lookups.
+func GetOrLoad(key string, ttl time.Duration, load func() (string, error)) (string, error) {
+	b := current()
+	v, ok, err := b.Get(key)
+	if err != nil {
+		logs.Printf("%s[SHARED]%s get %s: %v\n", config.ColorYellow, config.ColorReset, key, err)
+	}
+	if ok {
+		return v, nil
+	}
+	v, err = load()
+	if err != nil {
+		return "", err
+	}
+	if err := b.Set(key, v, ttl); err != nil {
+		logs.Printf("%s[SHARED]%s set %s: %v\n", config.ColorYellow, config.ColorReset, key, err)
+	}
+	return v, nil
+}
+
+// Set caches value for key for ttl, replacing what is there, e.g. a
+// fallback that should expire sooner than GetOrLoad's results. Backend
+// errors are logged.
+func Set(key, value string, ttl time.Duration) {
+	if err := current().Set(key, value, ttl); err != nil {
+		logs.Printf("%s[SHARED]%s set %s: %v\n", config.ColorYellow, config.ColorReset, key, err)
+	}
+}
+
+// Wait blocks until no request for key has started within interval, across
+// every instance sharing the backend, then claims the slot. A zero interval
+// returns immediately; backend errors let the request through.
+func Wait(key string, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	b := current()
+	for {
+		ok, remaining, err := b.Reserve("ratelimit:"+key, interval)
+		if err != nil {
+			logs.Printf("%s[SHARED]%s rate limit %s: %v\n", config.ColorYellow, config.ColorReset, key, err)
+			return
+		}
+		if ok {
+			return
+		}
+		if remaining <= 0 {
+			remaining = 10 * time.Millisecond
+		}
+		time.Sleep(remaining)
+	}
+}
+
+// Memory is the single-instance Backend.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   string
+	expires time.Time
+}
+
+// NewMemory returns an empty in-memory backend.
+func NewMemory() *Memory {
+	return &Memory{entries: map[string]memoryEntry{}}
+}
+
+// Get implements Backend.
+func (m *Memory) Get(key string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok || !time.Now().Before(e.expires) {
+		return "", false, nil
+	}
+	return e.value, true, nil
+}
+
+// Set implements Backend.
+func (m *Memory) Set(key, value string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = memoryEntry{value: value, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+// Reserve implements Backend.
+func (m *Memory) Reserve(key string, ttl time.Duration) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if e, ok := m.entries[key]; ok && now.Before(e.expires) {
+		return false, e.expires.Sub(now), nil
+	}
+	m.entries[key] = memoryEntry{value: "1", expires: now.Add(ttl)}
+	return true, 0, nil
+}
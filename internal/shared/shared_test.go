@@ -0,0 +1,54 @@
+package shared
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetOrLoadCachesAndSkipsErrors(t *testing.T) {
+	Use(NewMemory())
+	t.Cleanup(func() { Use(NewMemory()) })
+
+	calls := 0
+	load := func() (string, error) { calls++; return "7,12", nil }
+	for i := 0; i < 2; i++ {
+		if v, err := GetOrLoad("k", time.Minute, load); err != nil || v != "7,12" {
+			t.Fatalf("GetOrLoad = %q, %v", v, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("load called %d times, want 1", calls)
+	}
+
+	if _, err := GetOrLoad("bad", time.Minute, func() (string, error) { return "", errors.New("boom") }); err == nil {
+		t.Fatal("load error not returned")
+	}
+	if _, ok, _ := NewMemory().Get("bad"); ok {
+		t.Fatal("failed load was cached")
+	}
+
+	Set("bad", "0", time.Minute)
+	if v, err := GetOrLoad("bad", time.Minute, load); err != nil || v != "0" {
+		t.Fatalf("GetOrLoad after Set = %q, %v", v, err)
+	}
+}
+
+func TestWaitSpacesRequests(t *testing.T) {
+	Use(NewMemory())
+	t.Cleanup(func() { Use(NewMemory()) })
+
+	start := time.Now()
+	Wait("provider:odos", 50*time.Millisecond)
+	Wait("provider:odos", 50*time.Millisecond)
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Fatalf("second request started after %v, want >= 50ms", d)
+	}
+
+	start = time.Now()
+	Wait("provider:kyberswap", 0)
+	Wait("provider:kyberswap", 0)
+	if d := time.Since(start); d > 20*time.Millisecond {
+		t.Fatalf("zero interval waited %v", d)
+	}
+}
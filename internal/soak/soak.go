@@ -0,0 +1,198 @@
+// Package soak runs one provider endpoint repeatedly for a set time and
+// summarizes how it behaved: latency distribution, failure classes and rate
+// limiting. `go-monitoring soak` uses it to pick the default DELAY_<SOLVER>
+// of a newly added route solver.
+package soak
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rateLimitedClass is the alert hint class of HTTP 429 style failures.
+const rateLimitedClass = "rate_limited"
+
+// Sample is one check.
+type Sample struct {
+	At      time.Time
+	Latency time.Duration
+	Status  string // the endpoint's LastStatus after the check
+	Message string
+	Class   string // alert hint class of a failure, "other" when none matches; empty when up
+}
+
+// Run calls check every interval (measured from the end of the previous
+// check, like DELAY_<SOLVER>) until duration has passed or ctx is done, and
+// returns the samples.
+func Run(ctx context.Context, duration, interval time.Duration, check func() Sample) []Sample {
+	var samples []Sample
+	deadline := time.Now().Add(duration)
+	for {
+		samples = append(samples, check())
+		if !time.Now().Add(interval).Before(deadline) {
+			return samples
+		}
+		select {
+		case <-ctx.Done():
+			return samples
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Latency is a latency distribution.
+type Latency struct {
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+	Mean time.Duration
+}
+
+// FailureClass counts the failures of one class.
+type FailureClass struct {
+	Class   string
+	Count   int
+	Example string // the first message of the class
+}
+
+// RateLimit describes the rate-limited failures.
+type RateLimit struct {
+	Count int
+	// FirstCheck is the 1-based check that was first rate limited, 0 when
+	// none was.
+	FirstCheck int
+	// FirstAfter is how long after the start that check ran.
+	FirstAfter time.Duration
+	// LongestStreak is the most consecutive rate-limited checks.
+	LongestStreak int
+}
+
+// Report summarizes a soak.
+type Report struct {
+	Solver         string
+	Endpoint       string
+	Interval       time.Duration
+	Elapsed        time.Duration
+	Checks         int
+	Up             int
+	Statuses       map[string]int
+	Latency        Latency
+	Failures       []FailureClass
+	RateLimit      RateLimit
+	SuggestedDelay time.Duration
+	Advice         string
+}
+
+// Summarize builds the report of samples taken interval apart.
+func Summarize(solver, endpoint string, interval time.Duration, samples []Sample) Report {
+	r := Report{Solver: solver, Endpoint: endpoint, Interval: interval, Checks: len(samples), Statuses: map[string]int{}}
+	if len(samples) == 0 {
+		r.Advice = "no checks ran"
+		return r
+	}
+	r.Elapsed = samples[len(samples)-1].At.Add(samples[len(samples)-1].Latency).Sub(samples[0].At)
+
+	latencies := make([]time.Duration, len(samples))
+	var total time.Duration
+	classes := map[string]*FailureClass{}
+	var order []string
+	streak := 0
+	for i, s := range samples {
+		latencies[i] = s.Latency
+		total += s.Latency
+		r.Statuses[s.Status]++
+		if s.Status == "up" {
+			r.Up++
+		}
+		if s.Class != "" {
+			fc, ok := classes[s.Class]
+			if !ok {
+				fc = &FailureClass{Class: s.Class, Example: s.Message}
+				classes[s.Class] = fc
+				order = append(order, s.Class)
+			}
+			fc.Count++
+		}
+		if s.Class != rateLimitedClass {
+			streak = 0
+			continue
+		}
+		r.RateLimit.Count++
+		if r.RateLimit.FirstCheck == 0 {
+			r.RateLimit.FirstCheck = i + 1
+			r.RateLimit.FirstAfter = s.At.Sub(samples[0].At)
+		}
+		if streak++; streak > r.RateLimit.LongestStreak {
+			r.RateLimit.LongestStreak = streak
+		}
+	}
+	for _, c := range order {
+		r.Failures = append(r.Failures, *classes[c])
+	}
+	sort.SliceStable(r.Failures, func(i, j int) bool { return r.Failures[i].Count > r.Failures[j].Count })
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	r.Latency = Latency{
+		P50:  percentile(latencies, 50),
+		P90:  percentile(latencies, 90),
+		P99:  percentile(latencies, 99),
+		Max:  latencies[len(latencies)-1],
+		Mean: total / time.Duration(len(latencies)),
+	}
+
+	if r.RateLimit.Count == 0 {
+		r.SuggestedDelay = interval
+		r.Advice = fmt.Sprintf("no rate limiting with %s between checks; DELAY of %s is safe", interval, ceilSeconds(interval))
+	} else {
+		r.SuggestedDelay = 2 * interval
+		r.Advice = fmt.Sprintf("rate limited from check %d; soak again with -interval %s before choosing a DELAY", r.RateLimit.FirstCheck, ceilSeconds(2*interval))
+	}
+	return r
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// ceilSeconds rounds d up to whole seconds, the unit of DELAY_<SOLVER>.
+func ceilSeconds(d time.Duration) time.Duration {
+	return (d + time.Second - 1).Truncate(time.Second)
+}
+
+// Write renders the report as text.
+func (r Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "Soak of %s on %s: %d checks every %s over %s\n", r.Solver, r.Endpoint, r.Checks, r.Interval, r.Elapsed.Round(time.Second))
+	if r.Checks == 0 {
+		return
+	}
+	statuses := make([]string, 0, len(r.Statuses))
+	for s, n := range r.Statuses {
+		statuses = append(statuses, fmt.Sprintf("%s=%d", s, n))
+	}
+	sort.Strings(statuses)
+	fmt.Fprintf(w, "Up: %d/%d (%.1f%%)  statuses: %s\n", r.Up, r.Checks, 100*float64(r.Up)/float64(r.Checks), strings.Join(statuses, " "))
+	fmt.Fprintf(w, "Latency: p50=%s p90=%s p99=%s max=%s mean=%s\n",
+		r.Latency.P50.Round(time.Millisecond), r.Latency.P90.Round(time.Millisecond), r.Latency.P99.Round(time.Millisecond),
+		r.Latency.Max.Round(time.Millisecond), r.Latency.Mean.Round(time.Millisecond))
+	if len(r.Failures) > 0 {
+		fmt.Fprintln(w, "Failures:")
+		for _, f := range r.Failures {
+			fmt.Fprintf(w, "  %-22s %4d  e.g. %s\n", f.Class, f.Count, f.Example)
+		}
+	}
+	if r.RateLimit.Count > 0 {
+		fmt.Fprintf(w, "Rate limited: %d checks, first at check %d (%s in), longest streak %d\n",
+			r.RateLimit.Count, r.RateLimit.FirstCheck, r.RateLimit.FirstAfter.Round(time.Second), r.RateLimit.LongestStreak)
+	}
+	fmt.Fprintf(w, "Suggested delay: %s (%s)\n", ceilSeconds(r.SuggestedDelay), r.Advice)
+}
@@ -0,0 +1,72 @@
+package soak
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	var samples []Sample
+	add := func(latencyMs int, status, class, msg string) {
+		samples = append(samples, Sample{
+			At: start.Add(time.Duration(len(samples)) * 10 * time.Second), Latency: time.Duration(latencyMs) * time.Millisecond,
+			Status: status, Class: class, Message: msg,
+		})
+	}
+	for i := 1; i <= 6; i++ {
+		add(100*i, "up", "", "")
+	}
+	add(50, "down", "rate_limited", "HTTP 429")
+	add(50, "down", "rate_limited", "HTTP 429 again")
+	add(900, "up", "", "")
+	add(2000, "error", "network", "timeout")
+
+	r := Summarize("kyberswap", "KyberSwap-X", 5*time.Second, samples)
+	if r.Checks != 10 || r.Up != 7 || r.Statuses["down"] != 2 || r.Statuses["error"] != 1 {
+		t.Errorf("counts = %d checks, %d up, %v", r.Checks, r.Up, r.Statuses)
+	}
+	if r.Latency.P50 != 300*time.Millisecond || r.Latency.P90 != 900*time.Millisecond || r.Latency.Max != 2*time.Second {
+		t.Errorf("latency = %+v", r.Latency)
+	}
+	if len(r.Failures) != 2 || r.Failures[0].Class != "rate_limited" || r.Failures[0].Count != 2 || r.Failures[0].Example != "HTTP 429" {
+		t.Errorf("failures = %+v", r.Failures)
+	}
+	if r.RateLimit.Count != 2 || r.RateLimit.FirstCheck != 7 || r.RateLimit.FirstAfter != time.Minute || r.RateLimit.LongestStreak != 2 {
+		t.Errorf("rate limit = %+v", r.RateLimit)
+	}
+	if r.SuggestedDelay != 10*time.Second {
+		t.Errorf("suggested delay = %v, want 10s after rate limiting", r.SuggestedDelay)
+	}
+
+	var b strings.Builder
+	r.Write(&b)
+	for _, want := range []string{"10 checks every 5s", "Up: 7/10", "p50=300ms", "rate_limited", "first at check 7", "Suggested delay: 10s"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestSummarizeNoRateLimit(t *testing.T) {
+	r := Summarize("odos", "Odos-X", 1500*time.Millisecond, []Sample{{Status: "up", Latency: time.Second}})
+	if r.RateLimit.Count != 0 || r.SuggestedDelay != 1500*time.Millisecond || !strings.Contains(r.Advice, "DELAY of 2s is safe") {
+		t.Errorf("report = %+v", r)
+	}
+}
+
+func TestRunStopsAtDurationOrCancel(t *testing.T) {
+	n := 0
+	samples := Run(context.Background(), 25*time.Millisecond, 10*time.Millisecond, func() Sample { n++; return Sample{Status: "up"} })
+	if len(samples) != n || n < 2 || n > 3 {
+		t.Errorf("ran %d checks, want 2-3", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if samples := Run(ctx, time.Hour, time.Hour, func() Sample { return Sample{} }); len(samples) != 1 {
+		t.Errorf("cancelled soak ran %d checks, want 1", len(samples))
+	}
+}
@@ -0,0 +1,297 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+// The Store contract, run against every backend. Postgres runs when
+// TEST_DATABASE_URL names a database the tests may empty.
+
+func TestFileStoreContract(t *testing.T) {
+	testStoreContract(t, func(t *testing.T) Store {
+		return NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	})
+}
+
+func TestSQLiteStoreContract(t *testing.T) {
+	testStoreContract(t, func(t *testing.T) Store {
+		s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "monitor.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
+
+func TestPostgresStoreContract(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	testStoreContract(t, func(t *testing.T) Store {
+		s, err := NewPostgresStore(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		if _, err := s.db.Exec(`TRUNCATE endpoint_latest, check_results, check_results_hourly, endpoint_notes,
+source_catalogs, support, api_tokens, annotations, incidents`); err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
+
+// contractTime is a check time every backend stores exactly (Postgres keeps
+// microseconds).
+var contractTime = time.Date(2026, 3, 4, 10, 20, 30, 123456000, time.UTC)
+
+func testStoreContract(t *testing.T, open func(t *testing.T) Store) {
+	t.Run("Results", func(t *testing.T) {
+		s := open(t)
+		first := EndpointState{Name: "Odos-X", ID: "odos-1-x", LastStatus: "down", Message: "no route",
+			LastChecked: contractTime, LastStateChange: contractTime, FirstSeenDown: contractTime}
+		second := EndpointState{Name: "Odos-X", ID: "odos-1-x", LastStatus: "up", Message: "Ok", ReturnAmount: "99", MarketPrice: "100",
+			LastChecked: contractTime.Add(time.Minute), LastStateChange: contractTime.Add(time.Minute), ResponseTime: 250 * time.Millisecond,
+			Route: &collector.RouteGraph{Paths: []collector.RoutePath{{Percent: 100}}}}
+		recheck := second
+		recheck.LastChecked = contractTime.Add(2 * time.Minute)
+		for _, err := range []error{s.SaveResult(first), s.SaveResult(second), s.SaveLatest(recheck)} {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		latest, err := s.LoadLatest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(latest) != 1 {
+			t.Fatalf("LoadLatest = %+v, want one state", latest)
+		}
+		got := latest[0]
+		if got.ID != "odos-1-x" || got.LastStatus != "up" || got.ReturnAmount != "99" || !got.LastChecked.Equal(recheck.LastChecked) ||
+			!got.FirstSeenDown.IsZero() || got.Route == nil || len(got.Route.Paths) != 1 {
+			t.Fatalf("LoadLatest = %+v", got)
+		}
+
+		recs, err := s.QueryHistory("Odos-X", contractTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(recs) != 2 || recs[0].Status != "down" || recs[1].Status != "up" || recs[1].MarketPrice != "100" ||
+			recs[1].ResponseTime != 250*time.Millisecond || !recs[1].At.Equal(second.LastChecked) {
+			t.Fatalf("QueryHistory = %+v, want the two results oldest first", recs)
+		}
+		if recs, err := s.QueryHistory("Odos-X", contractTime.Add(time.Second)); err != nil || len(recs) != 1 {
+			t.Fatalf("QueryHistory since after the first = %+v, %v", recs, err)
+		}
+	})
+
+	t.Run("ImportHistory", func(t *testing.T) {
+		s := open(t)
+		if err := s.SaveResult(EndpointState{Name: "Odos-X", LastStatus: "up", LastChecked: contractTime}); err != nil {
+			t.Fatal(err)
+		}
+		err := s.ImportHistory("Odos-X", []collector.CheckRecord{
+			{At: contractTime.Add(-time.Hour), Status: "down", Message: "backfilled"},
+			{At: contractTime, Status: "down", Message: "duplicate"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		recs, err := s.QueryHistory("Odos-X", contractTime.Add(-2*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(recs) != 2 || recs[0].Message != "backfilled" || recs[1].Status != "up" {
+			t.Fatalf("history = %+v, want the backfill before the saved result", recs)
+		}
+		latest, err := s.LoadLatest()
+		if err != nil || len(latest) != 1 || latest[0].LastStatus != "up" {
+			t.Fatalf("LoadLatest after import = %+v, %v", latest, err)
+		}
+	})
+
+	t.Run("RenameEndpoint", func(t *testing.T) {
+		s := open(t)
+		old := EndpointState{Name: "Odos-Old", ID: "odos-1-x", LastStatus: "up", LastChecked: contractTime}
+		if err := s.SaveResult(old); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SaveNote(collector.Note{Endpoint: "Odos-Old", Text: "whitelisted", UpdatedAt: contractTime}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SaveIncident(Incident{Endpoint: "Odos-Old", Status: "down", StartedAt: contractTime}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.RenameEndpoint("Odos-Old", "Odos-New"); err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := s.LoadLatest()
+		if err != nil || len(latest) != 1 || latest[0].Name != "Odos-New" {
+			t.Fatalf("LoadLatest after rename = %+v, %v", latest, err)
+		}
+		if recs, err := s.QueryHistory("Odos-New", time.Time{}); err != nil || len(recs) != 1 {
+			t.Fatalf("history of the new name = %+v, %v", recs, err)
+		}
+		if recs, err := s.QueryHistory("Odos-Old", time.Time{}); err != nil || len(recs) != 0 {
+			t.Fatalf("history of the old name = %+v, %v", recs, err)
+		}
+		notes, err := s.LoadNotes()
+		if err != nil || len(notes) != 1 || notes[0].Endpoint != "Odos-New" {
+			t.Fatalf("notes after rename = %+v, %v", notes, err)
+		}
+	})
+
+	t.Run("Notes", func(t *testing.T) {
+		s := open(t)
+		for _, n := range []collector.Note{
+			{Endpoint: "Odos-X", Text: "first", UpdatedAt: contractTime},
+			{Endpoint: "Odos-X", Text: "second", RunbookURL: "https://runbook.example/x", UpdatedAt: contractTime.Add(time.Minute)},
+			{Endpoint: "Kyber-Y", Text: "removed", UpdatedAt: contractTime},
+			{Endpoint: "Kyber-Y"},
+		} {
+			if err := s.SaveNote(n); err != nil {
+				t.Fatal(err)
+			}
+		}
+		notes, err := s.LoadNotes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(notes) != 1 || notes[0].Text != "second" || notes[0].RunbookURL == "" || !notes[0].UpdatedAt.Equal(contractTime.Add(time.Minute)) {
+			t.Fatalf("LoadNotes = %+v, want the replaced note only", notes)
+		}
+	})
+
+	t.Run("SourceCatalogs", func(t *testing.T) {
+		s := open(t)
+		for _, c := range []SourceCatalog{
+			{Solver: "odos", Network: "1", Sources: []string{"a", "b"}, ScannedAt: contractTime},
+			{Solver: "odos", Network: "1", Sources: []string{"c"}, ScannedAt: contractTime.Add(time.Hour)},
+		} {
+			if err := s.SaveSourceCatalog(c); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cats, err := s.LoadSourceCatalogs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cats) != 1 || len(cats[0].Sources) != 1 || cats[0].Sources[0] != "c" || !cats[0].ScannedAt.Equal(contractTime.Add(time.Hour)) {
+			t.Fatalf("LoadSourceCatalogs = %+v, want the replaced catalog", cats)
+		}
+	})
+
+	t.Run("Support", func(t *testing.T) {
+		s := open(t)
+		for _, c := range []Support{
+			{Solver: "odos", Network: "1", PoolKind: "stable", FirstSeen: contractTime, LastConfirmed: contractTime},
+			{Solver: "odos", Network: "1", PoolKind: "stable", FirstSeen: contractTime.Add(time.Hour), LastConfirmed: contractTime.Add(time.Hour)},
+			{Solver: "odos", Network: "1", PoolKind: "stable", FirstSeen: contractTime.Add(-time.Hour), LastConfirmed: contractTime.Add(-time.Hour)},
+		} {
+			if err := s.SaveSupport(c); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cells, err := s.LoadSupport()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cells) != 1 || !cells[0].FirstSeen.Equal(contractTime.Add(-time.Hour)) || !cells[0].LastConfirmed.Equal(contractTime.Add(time.Hour)) {
+			t.Fatalf("LoadSupport = %+v, want the earliest first seen and the latest confirmation", cells)
+		}
+	})
+
+	t.Run("APITokens", func(t *testing.T) {
+		s := open(t)
+		for _, tok := range []APIToken{
+			{ID: "a", Name: "ci", Hash: "h1", Scopes: []string{"read"}, CreatedAt: contractTime},
+			{ID: "b", Name: "ops", Hash: "h2", Scopes: []string{"read", "trigger-check"}, CreatedAt: contractTime.Add(time.Minute)},
+			{ID: "a", Name: "ci-renamed", Hash: "h1", Scopes: []string{"read"}, CreatedAt: contractTime},
+		} {
+			if err := s.SaveAPIToken(tok); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if ok, err := s.DeleteAPIToken("b"); err != nil || !ok {
+			t.Fatalf("DeleteAPIToken(b) = %v, %v", ok, err)
+		}
+		if ok, err := s.DeleteAPIToken("missing"); err != nil || ok {
+			t.Fatalf("DeleteAPIToken(missing) = %v, %v", ok, err)
+		}
+		toks, err := s.LoadAPITokens()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(toks) != 1 || toks[0].Name != "ci-renamed" || len(toks[0].Scopes) != 1 {
+			t.Fatalf("LoadAPITokens = %+v", toks)
+		}
+	})
+
+	t.Run("Annotations", func(t *testing.T) {
+		s := open(t)
+		for _, a := range []Annotation{
+			{At: contractTime.Add(-time.Hour), Kind: AnnotationDeploy, Text: "v1"},
+			{At: contractTime, Kind: AnnotationConfig, BaseName: "GHO/USDC", Text: "amount"},
+			{At: contractTime.Add(time.Hour), Kind: AnnotationDeploy, Text: "v2"},
+		} {
+			if err := s.SaveAnnotation(a); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := s.QueryAnnotations(contractTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].BaseName != "GHO/USDC" || got[1].Text != "v2" {
+			t.Fatalf("QueryAnnotations = %+v, want the last two oldest first", got)
+		}
+	})
+
+	t.Run("Compact", func(t *testing.T) {
+		s := open(t)
+		c, ok := s.(Compactor)
+		if !ok {
+			t.Skip("not a Compactor")
+		}
+		hour := contractTime.Truncate(time.Hour)
+		for i, st := range []EndpointState{
+			{LastStatus: "up", ReturnAmount: "100"},
+			{LastStatus: "up", ReturnAmount: "300"},
+			{LastStatus: "down"},
+		} {
+			st.Name = "Odos-X"
+			st.LastChecked = hour.Add(time.Duration(i) * time.Minute)
+			if err := s.SaveResult(st); err != nil {
+				t.Fatal(err)
+			}
+		}
+		stats, err := c.Compact(hour.Add(48*time.Hour), Retention{Raw: 24 * time.Hour, Hourly: 365 * 24 * time.Hour})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Archived != 3 {
+			t.Errorf("archived %d checks, want 3", stats.Archived)
+		}
+		if recs, err := s.QueryHistory("Odos-X", time.Time{}); err != nil || len(recs) != 0 {
+			t.Fatalf("raw history after compaction = %+v, %v", recs, err)
+		}
+		aggs, err := c.QueryHourly("Odos-X", time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(aggs) != 1 || !aggs[0].Hour.Equal(hour) || aggs[0].Checks != 3 || aggs[0].Up != 2 || aggs[0].Down != 1 ||
+			aggs[0].Quotes != 2 || aggs[0].QuoteMin != 100 || aggs[0].QuoteMax != 300 || aggs[0].QuoteSum != 400 {
+			t.Fatalf("QueryHourly = %+v", aggs)
+		}
+	})
+}
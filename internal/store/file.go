@@ -0,0 +1,521 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/fsutil"
+	"go-monitoring/monitoring/collector"
+)
+
+// FileStore keeps the snapshot in one JSON file, e.g. on a Fly volume. Results
+// and incidents are buffered in memory and written by Flush, once per check
+// cycle rather than once per check. Not shareable between instances.
+type FileStore struct {
+	path string
+
+	mu    sync.Mutex
+	snap  *Snapshot // loaded on first use
+	dirty bool
+}
+
+// NewFileStore returns a store backed by the JSON file at path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads the snapshot; a missing file is an empty snapshot.
+func (s *FileStore) Load() (Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Snapshot{}, nil
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("decode %s: %w", s.path, err)
+	}
+	return snap, nil
+}
+
+// Save writes the snapshot with fsutil.AtomicWriteFile so a crash mid-write
+// never leaves a truncated file behind.
+func (s *FileStore) Save(snap Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	return fsutil.AtomicWriteFile(s.path, data)
+}
+
+// current returns the buffered snapshot, loading the file on first use.
+// Callers hold s.mu.
+func (s *FileStore) current() (*Snapshot, error) {
+	if s.snap == nil {
+		snap, err := s.Load()
+		if err != nil {
+			return nil, err
+		}
+		if snap.History == nil {
+			snap.History = map[string][]collector.CheckRecord{}
+		}
+		s.snap = &snap
+	}
+	return s.snap, nil
+}
+
+// SaveResult buffers a result. History is bounded by Compact.
+func (s *FileStore) SaveResult(st EndpointState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return err
+	}
+	setLatest(snap, st)
+	snap.History[st.Name] = append(snap.History[st.Name], st.Record())
+	s.dirty = true
+	return nil
+}
+
+// SaveLatest buffers a latest state without a history record.
+func (s *FileStore) SaveLatest(st EndpointState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return err
+	}
+	setLatest(snap, st)
+	s.dirty = true
+	return nil
+}
+
+// setLatest replaces or adds st among snap's latest states.
+func setLatest(snap *Snapshot, st EndpointState) {
+	replaced := false
+	for i := range snap.Endpoints {
+		if snap.Endpoints[i].Name == st.Name {
+			snap.Endpoints[i] = st
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		snap.Endpoints = append(snap.Endpoints, st)
+	}
+}
+
+// LoadLatest returns the buffered latest states.
+func (s *FileStore) LoadLatest() ([]EndpointState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+	return append([]EndpointState(nil), snap.Endpoints...), nil
+}
+
+// QueryHistory returns the buffered history for name since a time.
+func (s *FileStore) QueryHistory(name string, since time.Time) ([]collector.CheckRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+	var out []collector.CheckRecord
+	for _, r := range snap.History[name] {
+		if !r.At.Before(since) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// SaveIncident buffers an incident, resolving the matching open one.
+func (s *FileStore) SaveIncident(inc Incident) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return err
+	}
+	for i := range snap.Incidents {
+		cur := &snap.Incidents[i]
+		if cur.Endpoint == inc.Endpoint && cur.StartedAt.Equal(inc.StartedAt) {
+			cur.ResolvedAt = inc.ResolvedAt
+			s.dirty = true
+			return nil
+		}
+	}
+	snap.Incidents = append(snap.Incidents, inc)
+	s.dirty = true
+	return nil
+}
+
+// ImportHistory merges the records into the endpoint's history in time
+// order and writes the file. A running server rewrites the file from its
+// own copy, so import with it stopped.
+func (s *FileStore) ImportHistory(name string, records []collector.CheckRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return err
+	}
+	h := snap.History[name]
+	seen := make(map[int64]bool, len(h))
+	for _, r := range h {
+		seen[r.At.UnixNano()] = true
+	}
+	for _, r := range records {
+		if !seen[r.At.UnixNano()] {
+			seen[r.At.UnixNano()] = true
+			h = append(h, r)
+		}
+	}
+	sort.SliceStable(h, func(i, j int) bool { return h[i].At.Before(h[j].At) })
+	snap.History[name] = h
+	s.dirty = true
+	return s.flush()
+}
+
+// RenameEndpoint files old's state, history, aggregates, incidents and note
+// under name and writes the file. Where name has its own, old's is dropped.
+func (s *FileStore) RenameEndpoint(old, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return err
+	}
+	hasState := false
+	for _, st := range snap.Endpoints {
+		if st.Name == name {
+			hasState = true
+		}
+	}
+	states := snap.Endpoints[:0]
+	for _, st := range snap.Endpoints {
+		if st.Name == old {
+			if hasState {
+				continue
+			}
+			st.Name = name
+		}
+		states = append(states, st)
+	}
+	snap.Endpoints = states
+
+	if recs, ok := snap.History[old]; ok {
+		h := append(snap.History[name], recs...)
+		sort.SliceStable(h, func(i, j int) bool { return h[i].At.Before(h[j].At) })
+		snap.History[name] = h
+		delete(snap.History, old)
+	}
+
+	hours := map[int64]bool{}
+	for _, a := range snap.Hourly {
+		if a.Endpoint == name {
+			hours[a.Hour.Unix()] = true
+		}
+	}
+	hourly := snap.Hourly[:0]
+	for _, a := range snap.Hourly {
+		if a.Endpoint == old {
+			if hours[a.Hour.Unix()] {
+				continue
+			}
+			a.Endpoint = name
+		}
+		hourly = append(hourly, a)
+	}
+	snap.Hourly = hourly
+
+	starts := map[int64]bool{}
+	for _, inc := range snap.Incidents {
+		if inc.Endpoint == name {
+			starts[inc.StartedAt.UnixNano()] = true
+		}
+	}
+	incidents := snap.Incidents[:0]
+	for _, inc := range snap.Incidents {
+		if inc.Endpoint == old {
+			if starts[inc.StartedAt.UnixNano()] {
+				continue
+			}
+			inc.Endpoint = name
+		}
+		incidents = append(incidents, inc)
+	}
+	snap.Incidents = incidents
+
+	hasNote := false
+	for _, n := range snap.Notes {
+		if n.Endpoint == name {
+			hasNote = true
+		}
+	}
+	notes := snap.Notes[:0]
+	for _, n := range snap.Notes {
+		if n.Endpoint == old {
+			if hasNote {
+				continue
+			}
+			n.Endpoint = name
+		}
+		notes = append(notes, n)
+	}
+	snap.Notes = notes
+	s.dirty = true
+	return s.flush()
+}
+
+// SaveNote updates the note and writes the file, along with any buffered
+// results.
+func (s *FileStore) SaveNote(n collector.Note) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return err
+	}
+	kept := snap.Notes[:0]
+	for _, cur := range snap.Notes {
+		if cur.Endpoint != n.Endpoint {
+			kept = append(kept, cur)
+		}
+	}
+	if !n.IsEmpty() {
+		kept = append(kept, n)
+	}
+	snap.Notes = kept
+	s.dirty = true
+	return s.flush()
+}
+
+// LoadNotes returns the stored notes.
+func (s *FileStore) LoadNotes() ([]collector.Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+	return append([]collector.Note(nil), snap.Notes...), nil
+}
+
+// SaveSourceCatalog replaces the solver and network's catalog and writes the
+// file, along with any buffered results.
+func (s *FileStore) SaveSourceCatalog(c SourceCatalog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return err
+	}
+	kept := snap.Catalogs[:0]
+	for _, cur := range snap.Catalogs {
+		if cur.Solver != c.Solver || cur.Network != c.Network {
+			kept = append(kept, cur)
+		}
+	}
+	snap.Catalogs = append(kept, c)
+	s.dirty = true
+	return s.flush()
+}
+
+// LoadSourceCatalogs returns the stored catalogs.
+func (s *FileStore) LoadSourceCatalogs() ([]SourceCatalog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+	return append([]SourceCatalog(nil), snap.Catalogs...), nil
+}
+
+// SaveAnnotation appends the annotation and writes the file, along with any
+// buffered results.
+func (s *FileStore) SaveAnnotation(a Annotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return err
+	}
+	snap.Annotations = append(snap.Annotations, a)
+	s.dirty = true
+	return s.flush()
+}
+
+// QueryAnnotations returns the stored annotations since a time, oldest
+// first.
+func (s *FileStore) QueryAnnotations(since time.Time) ([]Annotation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+	var out []Annotation
+	for _, a := range snap.Annotations {
+		if !a.At.Before(since) {
+			out = append(out, a)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out, nil
+}
+
+// SaveSupport merges the coverage cell into the buffered snapshot; Flush
+// writes it.
+func (s *FileStore) SaveSupport(c Support) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return err
+	}
+	s.dirty = true
+	for i, cur := range snap.Support {
+		if cur.Solver == c.Solver && cur.Network == c.Network && cur.PoolKind == c.PoolKind {
+			snap.Support[i] = cur.Merge(c)
+			return nil
+		}
+	}
+	snap.Support = append(snap.Support, c)
+	return nil
+}
+
+// LoadSupport returns the stored coverage cells, buffered ones included.
+func (s *FileStore) LoadSupport() ([]Support, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+	return append([]Support(nil), snap.Support...), nil
+}
+
+// Flush writes buffered changes to the file.
+func (s *FileStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush()
+}
+
+// flush writes the snapshot if dirty. Callers hold s.mu.
+func (s *FileStore) flush() error {
+	if !s.dirty {
+		return nil
+	}
+	s.snap.SavedAt = clock.Now()
+	if err := s.Save(*s.snap); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// Compact rolls history older than r.Raw up into hourly aggregates, drops
+// aggregates and resolved incidents older than r.Hourly, and writes the file.
+func (s *FileStore) Compact(now time.Time, r Retention) (CompactStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return CompactStats{}, err
+	}
+	rawCutoff, hourlyCutoff := r.cutoffs(now)
+	var stats CompactStats
+
+	type key struct {
+		name string
+		hour int64
+	}
+	index := make(map[key]int, len(snap.Hourly))
+	for i, a := range snap.Hourly {
+		index[key{a.Endpoint, a.Hour.Unix()}] = i
+	}
+	for name, h := range snap.History {
+		n := 0
+		for n < len(h) && h[n].At.Before(rawCutoff) {
+			hour := h[n].At.UTC().Truncate(time.Hour)
+			k := key{name, hour.Unix()}
+			i, ok := index[k]
+			if !ok {
+				i = len(snap.Hourly)
+				index[k] = i
+				snap.Hourly = append(snap.Hourly, HourlyAggregate{Endpoint: name, Hour: hour})
+			}
+			snap.Hourly[i].add(h[n])
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		stats.Archived += n
+		if n == len(h) {
+			delete(snap.History, name)
+		} else {
+			snap.History[name] = append([]collector.CheckRecord(nil), h[n:]...)
+		}
+	}
+
+	keptHourly := snap.Hourly[:0]
+	for _, a := range snap.Hourly {
+		if a.Hour.Before(hourlyCutoff) {
+			stats.AggregatesPruned++
+			continue
+		}
+		keptHourly = append(keptHourly, a)
+	}
+	snap.Hourly = keptHourly
+
+	keptIncidents := snap.Incidents[:0]
+	for _, inc := range snap.Incidents {
+		if !inc.ResolvedAt.IsZero() && inc.ResolvedAt.Before(hourlyCutoff) {
+			stats.IncidentsPruned++
+			continue
+		}
+		keptIncidents = append(keptIncidents, inc)
+	}
+	snap.Incidents = keptIncidents
+
+	if stats != (CompactStats{}) {
+		s.dirty = true
+	}
+	return stats, s.flush()
+}
+
+// QueryHourly returns the archived aggregates for name since a time.
+func (s *FileStore) QueryHourly(name string, since time.Time) ([]HourlyAggregate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+	var out []HourlyAggregate
+	for _, a := range snap.Hourly {
+		if a.Endpoint == name && !a.Hour.Before(since) {
+			out = append(out, a)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Hour.Before(out[j].Hour) })
+	return out, nil
+}
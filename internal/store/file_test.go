@@ -0,0 +1,459 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	s := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+
+	empty, err := s.Load()
+	if err != nil || len(empty.Endpoints) != 0 {
+		t.Fatalf("Load on missing file = %+v, %v", empty, err)
+	}
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := Snapshot{
+		SavedAt:   at,
+		Endpoints: []EndpointState{{Name: "Odos-X", LastStatus: "down", Message: "no route", FirstSeenDown: at}},
+		History:   map[string][]collector.CheckRecord{"Odos-X": {{At: at, Status: "down", Message: "no route"}}},
+	}
+	if err := s.Save(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.SavedAt.Equal(at) || len(got.Endpoints) != 1 || got.Endpoints[0] != want.Endpoints[0] ||
+		len(got.History["Odos-X"]) != 1 {
+		t.Fatalf("Load = %+v, want %+v", got, want)
+	}
+}
+
+func TestRestoreSkipsUnknownEndpoints(t *testing.T) {
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-X", LastStatus: "unknown", SwapAmount: "100"}})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	n := Restore(Snapshot{Endpoints: []EndpointState{
+		{Name: "Odos-X", LastStatus: "up", Message: "Ok", ReturnAmount: "99"},
+		{Name: "Removed-Y", LastStatus: "down"},
+	}})
+	if n != 1 {
+		t.Fatalf("restored %d, want 1", n)
+	}
+	e := collector.GetEndpointByName("Odos-X")
+	if e.LastStatus != "up" || e.ReturnAmount != "99" || e.SwapAmount != "100" {
+		t.Fatalf("endpoint = %+v", e)
+	}
+}
+
+func TestRestoreFindsRenamedEndpointByID(t *testing.T) {
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-New", ID: "odos-1-abc", LastStatus: "unknown"}})
+	var renames []string
+	collector.SetRenameHandler(func(old, name string) { renames = append(renames, old+" → "+name) })
+	t.Cleanup(func() {
+		collector.SetRenameHandler(nil)
+		collector.SetEndpoints(nil)
+		collector.SetHistory("Odos-New", nil)
+		collector.SetNotes(nil)
+	})
+
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	n := Restore(Snapshot{
+		Endpoints: []EndpointState{{Name: "Odos-Old", ID: "odos-1-abc", LastStatus: "up", LastChecked: at}},
+		History:   map[string][]collector.CheckRecord{"Odos-Old": {{At: at, Status: "up"}}},
+		Notes:     []collector.Note{{Endpoint: "Odos-Old", Text: "whitelisted in #12"}},
+	})
+	if n != 1 {
+		t.Fatalf("restored %d, want 1", n)
+	}
+	if e := collector.GetEndpointByName("Odos-New"); e.LastStatus != "up" {
+		t.Fatalf("endpoint = %+v", e)
+	}
+	if h := collector.GetHistory("Odos-New"); len(h) != 1 {
+		t.Fatalf("history = %+v", h)
+	}
+	if h := collector.GetHistory("Odos-Old"); len(h) != 0 {
+		t.Fatalf("history kept under the old name: %+v", h)
+	}
+	if note, ok := collector.GetNote("Odos-New"); !ok || note.Text != "whitelisted in #12" {
+		t.Fatalf("note = %+v, %v", note, ok)
+	}
+	if len(renames) != 1 || renames[0] != "Odos-Old → Odos-New" {
+		t.Fatalf("store renames = %q", renames)
+	}
+}
+
+func TestFileStoreRenameEndpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewFileStore(path)
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	old := now.Add(-40 * 24 * time.Hour).Truncate(time.Hour)
+	for _, st := range []EndpointState{
+		{Name: "Odos-Old", LastStatus: "up", LastChecked: old},
+		{Name: "Odos-Old", LastStatus: "down", LastChecked: now.Add(-time.Hour), FirstSeenDown: now.Add(-time.Hour)},
+	} {
+		if err := s.SaveResult(st); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Compact(now, Retention{Raw: 30 * 24 * time.Hour, Hourly: 365 * 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveIncident(Incident{Endpoint: "Odos-Old", Status: "down", StartedAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveNote(collector.Note{Endpoint: "Odos-Old", Text: "whitelisted in #12"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.RenameEndpoint("Odos-Old", "Odos-New"); err != nil {
+		t.Fatal(err)
+	}
+
+	// RenameEndpoint writes the file; a fresh store sees every row moved.
+	snap, err := NewFileStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Endpoints) != 1 || snap.Endpoints[0].Name != "Odos-New" {
+		t.Fatalf("endpoints = %+v", snap.Endpoints)
+	}
+	if len(snap.History["Odos-New"]) != 1 || len(snap.History["Odos-Old"]) != 0 {
+		t.Fatalf("history = %+v", snap.History)
+	}
+	if len(snap.Hourly) != 1 || snap.Hourly[0].Endpoint != "Odos-New" {
+		t.Fatalf("hourly = %+v", snap.Hourly)
+	}
+	if len(snap.Incidents) != 1 || snap.Incidents[0].Endpoint != "Odos-New" {
+		t.Fatalf("incidents = %+v", snap.Incidents)
+	}
+	if len(snap.Notes) != 1 || snap.Notes[0].Endpoint != "Odos-New" {
+		t.Fatalf("notes = %+v", snap.Notes)
+	}
+}
+
+func TestFileStoreResultsAndIncidents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewFileStore(path)
+
+	t0 := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	t1 := t0.Add(30 * time.Minute)
+	for _, st := range []EndpointState{
+		{Name: "Odos-X", LastStatus: "down", Message: "no route", LastChecked: t0, FirstSeenDown: t0},
+		{Name: "Odos-X", LastStatus: "up", Message: "Ok", LastChecked: t1},
+	} {
+		if err := s.SaveResult(st); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SaveIncident(Incident{Endpoint: "Odos-X", Status: "down", StartedAt: t0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveIncident(Incident{Endpoint: "Odos-X", StartedAt: t0, ResolvedAt: t1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh store reads what the first one flushed.
+	snap, err := LoadSnapshot(NewFileStore(path), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Endpoints) != 1 || snap.Endpoints[0].LastStatus != "up" || !snap.SavedAt.Equal(t1) {
+		t.Fatalf("endpoints = %+v, savedAt %v", snap.Endpoints, snap.SavedAt)
+	}
+	if h := snap.History["Odos-X"]; len(h) != 2 || h[0].Status != "down" || h[1].Status != "up" {
+		t.Fatalf("history = %+v", h)
+	}
+
+	raw, err := NewFileStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(raw.Incidents) != 1 || raw.Incidents[0].Status != "down" || !raw.Incidents[0].ResolvedAt.Equal(t1) {
+		t.Fatalf("incidents = %+v", raw.Incidents)
+	}
+}
+
+func TestFileStoreQueryHistorySince(t *testing.T) {
+	s := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	now := time.Now()
+	for _, at := range []time.Time{now.Add(-3 * time.Hour), now.Add(-2 * time.Hour), now.Add(-time.Hour)} {
+		if err := s.SaveResult(EndpointState{Name: "A", LastStatus: "up", LastChecked: at}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := s.QueryHistory("A", now.Add(-2*time.Hour))
+	if err != nil || len(got) != 2 {
+		t.Fatalf("QueryHistory = %d records, %v; want 2", len(got), err)
+	}
+}
+
+func TestFileStoreImportHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewFileStore(path)
+	now := time.Now().Truncate(time.Second)
+	if err := s.SaveResult(EndpointState{Name: "A", LastStatus: "up", LastChecked: now}); err != nil {
+		t.Fatal(err)
+	}
+	past := []collector.CheckRecord{
+		{At: now.Add(-2 * time.Hour), Status: "down", Message: "no route"},
+		{At: now.Add(-time.Hour), Status: "down", Message: "no route"},
+		{At: now, Status: "down", Message: "already recorded"},
+	}
+	if err := s.ImportHistory("A", past); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ImportHistory("A", past); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewFileStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := got.History["A"]
+	if len(h) != 3 || !h[0].At.Equal(now.Add(-2*time.Hour)) || h[2].Status != "up" {
+		t.Fatalf("history = %+v, want the two imports then the saved check", h)
+	}
+	if len(got.Endpoints) != 1 || got.Endpoints[0].LastStatus != "up" {
+		t.Fatalf("endpoints = %+v, want latest state unchanged", got.Endpoints)
+	}
+}
+
+func TestFileStoreCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewFileStore(path)
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	r := Retention{Raw: 30 * 24 * time.Hour, Hourly: 365 * 24 * time.Hour}
+
+	old := now.Add(-40 * 24 * time.Hour).Truncate(time.Hour)
+	for _, st := range []EndpointState{
+		{Name: "A", LastStatus: "up", LastChecked: old.Add(5 * time.Minute)},
+		{Name: "A", LastStatus: "error", LastChecked: old.Add(20 * time.Minute)},
+		{Name: "A", LastStatus: "skipped", LastChecked: old.Add(40 * time.Minute)},
+		{Name: "A", LastStatus: "up", LastChecked: now.Add(-time.Hour)},
+		{Name: "B", LastStatus: "down", LastChecked: old},
+	} {
+		if err := s.SaveResult(st); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, inc := range []Incident{
+		{Endpoint: "A", StartedAt: old, ResolvedAt: old.Add(time.Hour)},
+		{Endpoint: "B", StartedAt: now.Add(-400 * 24 * time.Hour), ResolvedAt: now.Add(-380 * 24 * time.Hour)},
+		{Endpoint: "B", StartedAt: now.Add(-400 * 24 * time.Hour).Add(time.Minute)},
+	} {
+		if err := s.SaveIncident(inc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := s.Compact(now, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (CompactStats{Archived: 4, IncidentsPruned: 1}) {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	// Compaction writes the file; a fresh store sees the result.
+	fresh := NewFileStore(path)
+	if h, _ := fresh.QueryHistory("A", time.Time{}); len(h) != 1 || h[0].Status != "up" {
+		t.Fatalf("raw history for A = %+v", h)
+	}
+	if h, _ := fresh.QueryHistory("B", time.Time{}); len(h) != 0 {
+		t.Fatalf("raw history for B = %+v", h)
+	}
+	agg, err := fresh.QueryHourly("A", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agg) != 1 || agg[0] != (HourlyAggregate{Endpoint: "A", Hour: old, Checks: 2, Up: 1, Down: 1}) {
+		t.Fatalf("hourly for A = %+v", agg)
+	}
+	snap, err := fresh.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Incidents) != 2 {
+		t.Fatalf("incidents = %+v, want the recent resolved and the open one", snap.Incidents)
+	}
+
+	// Over a year on, the remaining check is archived and every aggregate
+	// (including the new one) and the resolved incident are pruned.
+	stats, err = s.Compact(now.Add(400*24*time.Hour), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (CompactStats{Archived: 1, AggregatesPruned: 3, IncidentsPruned: 1}) {
+		t.Fatalf("second compaction stats = %+v", stats)
+	}
+}
+
+func TestFileStoreKeepsRoute(t *testing.T) {
+	s := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	e := collector.Endpoint{Name: "Kyber-X", LastStatus: "down"}
+	e.SetRoute(collector.RouteGraph{Paths: []collector.RoutePath{{Hops: []collector.RouteHop{
+		{TokenIn: "0xa", TokenOut: "0xb", Venues: []collector.RouteVenue{{Pool: "0xpool", Exchange: "curve", AmountIn: "100", AmountOut: "99"}}},
+	}}}})
+	if err := s.SaveResult(StateOf(e)); err != nil {
+		t.Fatal(err)
+	}
+	if st := StateOf(collector.Endpoint{Name: "Odos-X"}); st.Route != nil {
+		t.Fatalf("StateOf without a route = %+v", st.Route)
+	}
+
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := NewFileStore(s.path).LoadLatest()
+	if err != nil || len(latest) != 1 {
+		t.Fatalf("LoadLatest = %+v, %v", latest, err)
+	}
+	var restored collector.Endpoint
+	latest[0].Apply(&restored)
+	if got, want := restored.Route.String(), e.Route.String(); got != want {
+		t.Fatalf("route = %q, want %q", got, want)
+	}
+	if v := restored.Route.Venues(); len(v) != 1 || v[0].AmountOut != "99" {
+		t.Fatalf("venues = %+v", v)
+	}
+}
+
+func TestFileStoreNotes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewFileStore(path)
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, n := range []collector.Note{
+		{Endpoint: "Kyber-X", Text: "whitelisting ticket #123", UpdatedAt: at},
+		{Endpoint: "Odos-X", RunbookURL: "https://wiki.example.com/odos", UpdatedAt: at},
+		{Endpoint: "Kyber-X", Text: "whitelisted", UpdatedAt: at},
+		{Endpoint: "Odos-X"},
+	} {
+		if err := s.SaveNote(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Notes are written right away, without a Flush.
+	snap, err := LoadSnapshot(NewFileStore(path), at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Notes) != 1 || snap.Notes[0].Text != "whitelisted" {
+		t.Fatalf("notes = %+v", snap.Notes)
+	}
+	t.Cleanup(func() { collector.SetNotes(nil) })
+	Restore(snap)
+	if n, ok := collector.GetNote("Kyber-X"); !ok || n.Text != "whitelisted" {
+		t.Fatalf("restored note = %+v, %v", n, ok)
+	}
+}
+
+func TestFileStoreSourceCatalogs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewFileStore(path)
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, c := range []SourceCatalog{
+		{Solver: "paraswap", Network: "1", Sources: []string{"BalancerV3", "UniswapV3"}, ScannedAt: at},
+		{Solver: "paraswap", Network: "8453", Sources: []string{"BalancerV3"}, ScannedAt: at},
+		{Solver: "paraswap", Network: "1", Sources: []string{"UniswapV3"}, ScannedAt: at.Add(time.Hour)},
+	} {
+		if err := s.SaveSourceCatalog(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := NewFileStore(path).LoadSourceCatalogs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].Network != "1" || len(got[1].Sources) != 1 || !got[1].ScannedAt.Equal(at.Add(time.Hour)) {
+		t.Fatalf("catalogs = %+v", got)
+	}
+}
+
+func TestFileStoreSupport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewFileStore(path)
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, c := range []Support{
+		{Solver: "kyberswap", Network: "1", PoolKind: "stable", FirstSeen: at, LastConfirmed: at},
+		{Solver: "kyberswap", Network: "1", PoolKind: "gyro", FirstSeen: at, LastConfirmed: at},
+		{Solver: "kyberswap", Network: "1", PoolKind: "stable", FirstSeen: at.Add(2 * time.Hour), LastConfirmed: at.Add(2 * time.Hour)},
+	} {
+		if err := s.SaveSupport(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewFileStore(path).LoadSupport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || !got[0].FirstSeen.Equal(at) || !got[0].LastConfirmed.Equal(at.Add(2*time.Hour)) {
+		t.Fatalf("support = %+v", got)
+	}
+}
+
+func TestFileStoreAnnotations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewFileStore(path)
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, a := range []Annotation{
+		{At: at.Add(time.Hour), Kind: AnnotationConfig, BaseName: "GHO/USDC", Text: "edited by import"},
+		{At: at, Kind: AnnotationDeploy, Text: "v1.2.0"},
+		{At: at.Add(-time.Hour), Kind: AnnotationDeploy, Text: "v1.1.0"},
+	} {
+		if err := s.SaveAnnotation(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Saved right away, and read back oldest first.
+	got, err := NewFileStore(path).QueryAnnotations(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Text != "v1.2.0" || got[1].BaseName != "GHO/USDC" {
+		t.Fatalf("annotations = %+v", got)
+	}
+}
+
+func TestFileStoreAPITokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewFileStore(path)
+	tok := APIToken{ID: "ab12", Name: "ci", Hash: "h", Scopes: []string{"read"}}
+	if err := s.SaveAPIToken(tok); err != nil {
+		t.Fatal(err)
+	}
+	// A second process (the token CLI) sees it, and a flush of the snapshot
+	// doesn't drop it.
+	s.SaveResult(EndpointState{Name: "x", LastStatus: "up"})
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := NewFileStore(path).LoadAPITokens()
+	if err != nil || len(tokens) != 1 || tokens[0].Name != "ci" {
+		t.Fatalf("tokens = %+v, %v", tokens, err)
+	}
+	if ok, err := s.DeleteAPIToken("ab12"); !ok || err != nil {
+		t.Fatalf("delete = %v, %v", ok, err)
+	}
+	if ok, _ := s.DeleteAPIToken("ab12"); ok {
+		t.Error("deleted twice")
+	}
+}
@@ -0,0 +1,631 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+
+	_ "github.com/lib/pq" // registers the "postgres" driver
+)
+
+// PostgresStore keeps results and incidents in Postgres, so several monitor
+// instances (e.g. Fly machines sharing a managed database) see one state.
+// Writes go straight to the database; there is nothing to flush.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// postgresSchema is applied on open. Statements are idempotent.
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS endpoint_latest (
+	name              TEXT PRIMARY KEY,
+	last_status       TEXT NOT NULL,
+	message           TEXT NOT NULL,
+	last_checked      TIMESTAMPTZ NOT NULL,
+	last_state_change TIMESTAMPTZ,
+	first_seen_down   TIMESTAMPTZ,
+	return_amount     TEXT NOT NULL,
+	market_price      TEXT NOT NULL,
+	on_chain_price    TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS check_results (
+	id         BIGSERIAL PRIMARY KEY,
+	name       TEXT NOT NULL,
+	checked_at TIMESTAMPTZ NOT NULL,
+	status     TEXT NOT NULL,
+	message    TEXT NOT NULL
+);
+ALTER TABLE endpoint_latest ADD COLUMN IF NOT EXISTS route JSONB;
+ALTER TABLE endpoint_latest ADD COLUMN IF NOT EXISTS endpoint_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE check_results ADD COLUMN IF NOT EXISTS return_amount TEXT NOT NULL DEFAULT '';
+ALTER TABLE check_results ADD COLUMN IF NOT EXISTS market_price TEXT NOT NULL DEFAULT '';
+ALTER TABLE check_results ADD COLUMN IF NOT EXISTS response_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE check_results ADD COLUMN IF NOT EXISTS response_phases JSONB;
+ALTER TABLE check_results ADD COLUMN IF NOT EXISTS surge JSONB;
+CREATE INDEX IF NOT EXISTS check_results_name_checked_at ON check_results (name, checked_at);
+CREATE TABLE IF NOT EXISTS check_results_hourly (
+	name   TEXT NOT NULL,
+	hour   TIMESTAMPTZ NOT NULL,
+	checks    INTEGER NOT NULL,
+	up        INTEGER NOT NULL,
+	down      INTEGER NOT NULL,
+	quotes    INTEGER NOT NULL,
+	quote_min DOUBLE PRECISION,
+	quote_max DOUBLE PRECISION,
+	quote_sum DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (name, hour)
+);
+ALTER TABLE check_results_hourly ADD COLUMN IF NOT EXISTS spreads INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE check_results_hourly ADD COLUMN IF NOT EXISTS spread_min DOUBLE PRECISION;
+ALTER TABLE check_results_hourly ADD COLUMN IF NOT EXISTS spread_max DOUBLE PRECISION;
+ALTER TABLE check_results_hourly ADD COLUMN IF NOT EXISTS spread_sum DOUBLE PRECISION NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS endpoint_notes (
+	name        TEXT PRIMARY KEY,
+	text        TEXT NOT NULL,
+	runbook_url TEXT NOT NULL,
+	updated_at  TIMESTAMPTZ NOT NULL
+);
+CREATE TABLE IF NOT EXISTS source_catalogs (
+	solver     TEXT NOT NULL,
+	network    TEXT NOT NULL,
+	sources    JSONB NOT NULL,
+	scanned_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (solver, network)
+);
+CREATE TABLE IF NOT EXISTS support (
+	solver         TEXT NOT NULL,
+	network        TEXT NOT NULL,
+	pool_kind      TEXT NOT NULL,
+	first_seen     TIMESTAMPTZ NOT NULL,
+	last_confirmed TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (solver, network, pool_kind)
+);
+CREATE TABLE IF NOT EXISTS api_tokens (
+	id         TEXT PRIMARY KEY,
+	name       TEXT NOT NULL,
+	hash       TEXT NOT NULL UNIQUE,
+	scopes     JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE TABLE IF NOT EXISTS annotations (
+	id        BIGSERIAL PRIMARY KEY,
+	at        TIMESTAMPTZ NOT NULL,
+	kind      TEXT NOT NULL,
+	base_name TEXT NOT NULL,
+	text      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS annotations_at ON annotations (at);
+CREATE TABLE IF NOT EXISTS incidents (
+	endpoint    TEXT NOT NULL,
+	started_at  TIMESTAMPTZ NOT NULL,
+	resolved_at TIMESTAMPTZ,
+	status      TEXT NOT NULL,
+	message     TEXT NOT NULL,
+	PRIMARY KEY (endpoint, started_at)
+);`
+
+// NewPostgresStore connects to the database at dsn (a postgres:// URL, as
+// set in DATABASE_URL by Fly) and creates the tables if needed.
+func NewPostgresStore(dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	if _, err := db.Exec(postgresSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+// DB returns the connection pool, shared with leader election.
+func (s *PostgresStore) DB() *sql.DB {
+	return s.db
+}
+
+// SaveResult upserts the latest state and appends a history row in one
+// transaction.
+func (s *PostgresStore) SaveResult(st EndpointState) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveLatest(tx, st); err != nil {
+		return err
+	}
+	var phases []byte
+	if !st.ResponsePhases.IsZero() {
+		if phases, err = json.Marshal(st.ResponsePhases); err != nil {
+			return fmt.Errorf("encode response phases: %w", err)
+		}
+	}
+	var surge []byte
+	if !st.Surge.IsZero() {
+		if surge, err = json.Marshal(st.Surge); err != nil {
+			return fmt.Errorf("encode surge state: %w", err)
+		}
+	}
+	_, err = tx.Exec(`
+INSERT INTO check_results (name, checked_at, status, message, return_amount, market_price, response_ms, response_phases, surge)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		st.Name, st.LastChecked, st.LastStatus, st.Message, st.ReturnAmount, st.MarketPrice, st.ResponseTime.Milliseconds(), nullJSON(phases), nullJSON(surge))
+	if err != nil {
+		return fmt.Errorf("save history: %w", err)
+	}
+	return tx.Commit()
+}
+
+// SaveLatest upserts the latest state alone.
+func (s *PostgresStore) SaveLatest(st EndpointState) error {
+	return saveLatest(s.db, st)
+}
+
+// execer is a *sql.DB or *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// saveLatest upserts st into endpoint_latest.
+func saveLatest(db execer, st EndpointState) error {
+	var route []byte
+	if st.Route != nil {
+		var err error
+		if route, err = json.Marshal(st.Route); err != nil {
+			return fmt.Errorf("encode route: %w", err)
+		}
+	}
+	_, err := db.Exec(`
+INSERT INTO endpoint_latest (name, last_status, message, last_checked, last_state_change, first_seen_down, return_amount, market_price, on_chain_price, route, endpoint_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (name) DO UPDATE SET
+	last_status = EXCLUDED.last_status,
+	message = EXCLUDED.message,
+	last_checked = EXCLUDED.last_checked,
+	last_state_change = EXCLUDED.last_state_change,
+	first_seen_down = EXCLUDED.first_seen_down,
+	return_amount = EXCLUDED.return_amount,
+	market_price = EXCLUDED.market_price,
+	on_chain_price = EXCLUDED.on_chain_price,
+	route = EXCLUDED.route,
+	endpoint_id = EXCLUDED.endpoint_id`,
+		st.Name, st.LastStatus, st.Message, st.LastChecked, nullTime(st.LastStateChange), nullTime(st.FirstSeenDown),
+		st.ReturnAmount, st.MarketPrice, st.OnChainPrice, nullJSON(route), st.ID)
+	if err != nil {
+		return fmt.Errorf("save latest: %w", err)
+	}
+	return nil
+}
+
+// LoadLatest returns every endpoint's latest state, ordered by name.
+func (s *PostgresStore) LoadLatest() ([]EndpointState, error) {
+	rows, err := s.db.Query(`
+SELECT name, last_status, message, last_checked, last_state_change, first_seen_down, return_amount, market_price, on_chain_price, route, endpoint_id
+FROM endpoint_latest ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []EndpointState
+	for rows.Next() {
+		var st EndpointState
+		var stateChange, firstDown sql.NullTime
+		var route []byte
+		if err := rows.Scan(&st.Name, &st.LastStatus, &st.Message, &st.LastChecked, &stateChange, &firstDown,
+			&st.ReturnAmount, &st.MarketPrice, &st.OnChainPrice, &route, &st.ID); err != nil {
+			return nil, err
+		}
+		if len(route) > 0 {
+			st.Route = &collector.RouteGraph{}
+			if err := json.Unmarshal(route, st.Route); err != nil {
+				return nil, fmt.Errorf("decode route of %s: %w", st.Name, err)
+			}
+		}
+		st.LastStateChange = stateChange.Time
+		st.FirstSeenDown = firstDown.Time
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+// QueryHistory returns the named endpoint's checks since a time, oldest first.
+func (s *PostgresStore) QueryHistory(name string, since time.Time) ([]collector.CheckRecord, error) {
+	rows, err := s.db.Query(`
+SELECT checked_at, status, message, return_amount, market_price, response_ms, response_phases, surge FROM check_results
+WHERE name = $1 AND checked_at >= $2 ORDER BY checked_at`, name, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []collector.CheckRecord
+	for rows.Next() {
+		var r collector.CheckRecord
+		var ms int64
+		var phases, surge []byte
+		if err := rows.Scan(&r.At, &r.Status, &r.Message, &r.ReturnAmount, &r.MarketPrice, &ms, &phases, &surge); err != nil {
+			return nil, err
+		}
+		r.ResponseTime = time.Duration(ms) * time.Millisecond
+		if len(phases) > 0 {
+			if err := json.Unmarshal(phases, &r.ResponsePhases); err != nil {
+				return nil, fmt.Errorf("decode response phases of %s: %w", name, err)
+			}
+		}
+		if len(surge) > 0 {
+			if err := json.Unmarshal(surge, &r.Surge); err != nil {
+				return nil, fmt.Errorf("decode surge state of %s: %w", name, err)
+			}
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// ImportHistory inserts the records in one transaction, skipping times the
+// endpoint already has a check at.
+func (s *PostgresStore) ImportHistory(name string, records []collector.CheckRecord) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, r := range records {
+		if _, err := tx.Exec(`
+INSERT INTO check_results (name, checked_at, status, message)
+SELECT $1, $2, $3, $4
+WHERE NOT EXISTS (SELECT 1 FROM check_results WHERE name = $1 AND checked_at = $2)`,
+			name, r.At, r.Status, r.Message); err != nil {
+			return fmt.Errorf("import history: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveIncident inserts an incident or resolves the stored one.
+func (s *PostgresStore) SaveIncident(inc Incident) error {
+	_, err := s.db.Exec(`
+INSERT INTO incidents (endpoint, started_at, resolved_at, status, message)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (endpoint, started_at) DO UPDATE SET resolved_at = EXCLUDED.resolved_at`,
+		inc.Endpoint, inc.StartedAt, nullTime(inc.ResolvedAt), inc.Status, inc.Message)
+	return err
+}
+
+// renameStatements move old's ($1) rows to name ($2). Keyed tables skip rows
+// name has already, and the rows left under old are deleted after.
+var renameStatements = []struct{ what, query string }{
+	{"results", `UPDATE check_results SET name = $2 WHERE name = $1`},
+	{"aggregates", `UPDATE check_results_hourly o SET name = $2 WHERE name = $1
+AND NOT EXISTS (SELECT 1 FROM check_results_hourly n WHERE n.name = $2 AND n.hour = o.hour)`},
+	{"incidents", `UPDATE incidents o SET endpoint = $2 WHERE endpoint = $1
+AND NOT EXISTS (SELECT 1 FROM incidents n WHERE n.endpoint = $2 AND n.started_at = o.started_at)`},
+	{"note", `UPDATE endpoint_notes SET name = $2 WHERE name = $1
+AND NOT EXISTS (SELECT 1 FROM endpoint_notes WHERE name = $2)`},
+	{"latest", `UPDATE endpoint_latest SET name = $2 WHERE name = $1
+AND NOT EXISTS (SELECT 1 FROM endpoint_latest WHERE name = $2)`},
+	{"leftover aggregates", `DELETE FROM check_results_hourly WHERE name = $1`},
+	{"leftover incidents", `DELETE FROM incidents WHERE endpoint = $1`},
+	{"leftover note", `DELETE FROM endpoint_notes WHERE name = $1`},
+	{"leftover latest", `DELETE FROM endpoint_latest WHERE name = $1`},
+}
+
+// RenameEndpoint moves old's rows to name in one transaction.
+func (s *PostgresStore) RenameEndpoint(old, name string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, st := range renameStatements {
+		if _, err := tx.Exec(st.query, old, name); err != nil {
+			return fmt.Errorf("rename %s: %w", st.what, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveNote upserts the endpoint's note, or deletes it when empty.
+func (s *PostgresStore) SaveNote(n collector.Note) error {
+	if n.IsEmpty() {
+		_, err := s.db.Exec(`DELETE FROM endpoint_notes WHERE name = $1`, n.Endpoint)
+		return err
+	}
+	_, err := s.db.Exec(`
+INSERT INTO endpoint_notes (name, text, runbook_url, updated_at) VALUES ($1, $2, $3, $4)
+ON CONFLICT (name) DO UPDATE SET
+	text = EXCLUDED.text,
+	runbook_url = EXCLUDED.runbook_url,
+	updated_at = EXCLUDED.updated_at`,
+		n.Endpoint, n.Text, n.RunbookURL, n.UpdatedAt)
+	return err
+}
+
+// LoadNotes returns every note, ordered by endpoint name.
+func (s *PostgresStore) LoadNotes() ([]collector.Note, error) {
+	rows, err := s.db.Query(`SELECT name, text, runbook_url, updated_at FROM endpoint_notes ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []collector.Note
+	for rows.Next() {
+		var n collector.Note
+		if err := rows.Scan(&n.Endpoint, &n.Text, &n.RunbookURL, &n.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+// SaveSourceCatalog upserts the solver and network's catalog.
+func (s *PostgresStore) SaveSourceCatalog(c SourceCatalog) error {
+	ids, err := json.Marshal(c.Sources)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+INSERT INTO source_catalogs (solver, network, sources, scanned_at) VALUES ($1, $2, $3, $4)
+ON CONFLICT (solver, network) DO UPDATE SET
+	sources = EXCLUDED.sources,
+	scanned_at = EXCLUDED.scanned_at`,
+		c.Solver, c.Network, ids, c.ScannedAt)
+	return err
+}
+
+// LoadSourceCatalogs returns every catalog, ordered by solver and network.
+func (s *PostgresStore) LoadSourceCatalogs() ([]SourceCatalog, error) {
+	rows, err := s.db.Query(`SELECT solver, network, sources, scanned_at FROM source_catalogs ORDER BY solver, network`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SourceCatalog
+	for rows.Next() {
+		var c SourceCatalog
+		var ids []byte
+		if err := rows.Scan(&c.Solver, &c.Network, &ids, &c.ScannedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(ids, &c.Sources); err != nil {
+			return nil, fmt.Errorf("catalog %s/%s: %w", c.Solver, c.Network, err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// SaveAnnotation inserts an annotation.
+func (s *PostgresStore) SaveAnnotation(a Annotation) error {
+	_, err := s.db.Exec(`INSERT INTO annotations (at, kind, base_name, text) VALUES ($1, $2, $3, $4)`,
+		a.At, a.Kind, a.BaseName, a.Text)
+	return err
+}
+
+// QueryAnnotations returns the annotations since a time, oldest first.
+func (s *PostgresStore) QueryAnnotations(since time.Time) ([]Annotation, error) {
+	rows, err := s.db.Query(`SELECT at, kind, base_name, text FROM annotations WHERE at >= $1 ORDER BY at, id`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Annotation
+	for rows.Next() {
+		var a Annotation
+		if err := rows.Scan(&a.At, &a.Kind, &a.BaseName, &a.Text); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// SaveSupport upserts the coverage cell, keeping the earlier first_seen and
+// the later last_confirmed.
+func (s *PostgresStore) SaveSupport(c Support) error {
+	_, err := s.db.Exec(`
+INSERT INTO support (solver, network, pool_kind, first_seen, last_confirmed) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (solver, network, pool_kind) DO UPDATE SET
+	first_seen = LEAST(support.first_seen, EXCLUDED.first_seen),
+	last_confirmed = GREATEST(support.last_confirmed, EXCLUDED.last_confirmed)`,
+		c.Solver, c.Network, c.PoolKind, c.FirstSeen, c.LastConfirmed)
+	return err
+}
+
+// LoadSupport returns every coverage cell, ordered by solver, network and
+// pool kind.
+func (s *PostgresStore) LoadSupport() ([]Support, error) {
+	rows, err := s.db.Query(`SELECT solver, network, pool_kind, first_seen, last_confirmed FROM support ORDER BY solver, network, pool_kind`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Support
+	for rows.Next() {
+		var c Support
+		if err := rows.Scan(&c.Solver, &c.Network, &c.PoolKind, &c.FirstSeen, &c.LastConfirmed); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Compact rolls check_results older than r.Raw up into check_results_hourly
+// and deletes them, then deletes aggregates and resolved incidents older than
+// r.Hourly, in one transaction. Hours are bucketed in UTC; the down statuses
+// match collector.IsDownStatus and quotes and spreads are taken from up
+// checks, as in HourlyAggregate.add.
+func (s *PostgresStore) Compact(now time.Time, r Retention) (CompactStats, error) {
+	rawCutoff, hourlyCutoff := r.cutoffs(now)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return CompactStats{}, err
+	}
+	defer tx.Rollback()
+
+	var stats CompactStats
+	_, err = tx.Exec(`
+INSERT INTO check_results_hourly (name, hour, checks, up, down, quotes, quote_min, quote_max, quote_sum, spreads, spread_min, spread_max, spread_sum)
+SELECT name, hour,
+	count(*) FILTER (WHERE status IN ('up', 'down', 'error', 'panic')),
+	count(*) FILTER (WHERE status = 'up'),
+	count(*) FILTER (WHERE status IN ('down', 'error', 'panic')),
+	count(quote), min(quote), max(quote), coalesce(sum(quote), 0),
+	count(spread), min(spread), max(spread), coalesce(sum(spread), 0)
+FROM (
+	SELECT name, status, hour, quote,
+		CASE WHEN quote > 0 AND market > 0 THEN (market - quote) / market * 10000 END AS spread
+	FROM (
+		SELECT name, status,
+			date_trunc('hour', checked_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS hour,
+			CASE WHEN status = 'up' AND return_amount ~ '^[0-9]+(\.[0-9]+)?$' THEN return_amount::double precision END AS quote,
+			CASE WHEN status = 'up' AND market_price ~ '^[0-9]+(\.[0-9]+)?$' THEN market_price::double precision END AS market
+		FROM check_results WHERE checked_at < $1
+	) r
+) c
+GROUP BY name, hour
+ON CONFLICT (name, hour) DO UPDATE SET
+	checks = check_results_hourly.checks + EXCLUDED.checks,
+	up = check_results_hourly.up + EXCLUDED.up,
+	down = check_results_hourly.down + EXCLUDED.down,
+	quotes = check_results_hourly.quotes + EXCLUDED.quotes,
+	quote_min = LEAST(check_results_hourly.quote_min, EXCLUDED.quote_min),
+	quote_max = GREATEST(check_results_hourly.quote_max, EXCLUDED.quote_max),
+	quote_sum = check_results_hourly.quote_sum + EXCLUDED.quote_sum,
+	spreads = check_results_hourly.spreads + EXCLUDED.spreads,
+	spread_min = LEAST(check_results_hourly.spread_min, EXCLUDED.spread_min),
+	spread_max = GREATEST(check_results_hourly.spread_max, EXCLUDED.spread_max),
+	spread_sum = check_results_hourly.spread_sum + EXCLUDED.spread_sum`, rawCutoff)
+	if err != nil {
+		return CompactStats{}, fmt.Errorf("archive checks: %w", err)
+	}
+	if stats.Archived, err = execCount(tx, `DELETE FROM check_results WHERE checked_at < $1`, rawCutoff); err != nil {
+		return CompactStats{}, fmt.Errorf("delete archived checks: %w", err)
+	}
+	if stats.AggregatesPruned, err = execCount(tx, `DELETE FROM check_results_hourly WHERE hour < $1`, hourlyCutoff); err != nil {
+		return CompactStats{}, fmt.Errorf("prune aggregates: %w", err)
+	}
+	if stats.IncidentsPruned, err = execCount(tx, `DELETE FROM incidents WHERE resolved_at < $1`, hourlyCutoff); err != nil {
+		return CompactStats{}, fmt.Errorf("prune incidents: %w", err)
+	}
+	return stats, tx.Commit()
+}
+
+// QueryHourly returns the named endpoint's hourly aggregates since a time,
+// oldest first.
+func (s *PostgresStore) QueryHourly(name string, since time.Time) ([]HourlyAggregate, error) {
+	rows, err := s.db.Query(`
+SELECT hour, checks, up, down, quotes, quote_min, quote_max, quote_sum, spreads, spread_min, spread_max, spread_sum FROM check_results_hourly
+WHERE name = $1 AND hour >= $2 ORDER BY hour`, name, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HourlyAggregate
+	for rows.Next() {
+		a := HourlyAggregate{Endpoint: name}
+		var quoteMin, quoteMax, spreadMin, spreadMax sql.NullFloat64
+		if err := rows.Scan(&a.Hour, &a.Checks, &a.Up, &a.Down, &a.Quotes, &quoteMin, &quoteMax, &a.QuoteSum,
+			&a.Spreads, &spreadMin, &spreadMax, &a.SpreadSum); err != nil {
+			return nil, err
+		}
+		a.QuoteMin, a.QuoteMax = quoteMin.Float64, quoteMax.Float64
+		a.SpreadMin, a.SpreadMax = spreadMin.Float64, spreadMax.Float64
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// execCount runs a statement and returns the rows it affected.
+func execCount(tx *sql.Tx, query string, args ...interface{}) (int, error) {
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// Close closes the connection pool.
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+// nullTime maps the zero time to NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// nullJSON stores an empty document as NULL.
+func nullJSON(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
+
+// SaveAPIToken upserts the token.
+func (s *PostgresStore) SaveAPIToken(t APIToken) error {
+	scopes, err := json.Marshal(t.Scopes)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+INSERT INTO api_tokens (id, name, hash, scopes, created_at) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE SET
+	name = EXCLUDED.name,
+	hash = EXCLUDED.hash,
+	scopes = EXCLUDED.scopes,
+	created_at = EXCLUDED.created_at`,
+		t.ID, t.Name, t.Hash, scopes, t.CreatedAt)
+	return err
+}
+
+// DeleteAPIToken deletes the token with the ID.
+func (s *PostgresStore) DeleteAPIToken(id string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM api_tokens WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// LoadAPITokens returns every token, oldest first.
+func (s *PostgresStore) LoadAPITokens() ([]APIToken, error) {
+	rows, err := s.db.Query(`SELECT id, name, hash, scopes, created_at FROM api_tokens ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []APIToken
+	for rows.Next() {
+		var t APIToken
+		var scopes []byte
+		if err := rows.Scan(&t.ID, &t.Name, &t.Hash, &scopes, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(scopes, &t.Scopes); err != nil {
+			return nil, fmt.Errorf("decode scopes of token %s: %w", t.ID, err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
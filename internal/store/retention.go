@@ -0,0 +1,134 @@
+package store
+
+import (
+	"math"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+)
+
+// Retention is how long each tier of stored history is kept. Raw checks older
+// than Raw are rolled up into hourly aggregates and deleted; aggregates older
+// than Hourly, and incidents resolved before then, are deleted.
+type Retention struct {
+	Raw    time.Duration
+	Hourly time.Duration
+}
+
+// cutoffs returns the raw and hourly cutoffs at now, on hour boundaries so an
+// hour is never split between raw rows and its aggregate.
+func (r Retention) cutoffs(now time.Time) (raw, hourly time.Time) {
+	return now.Add(-r.Raw).Truncate(time.Hour), now.Add(-r.Hourly).Truncate(time.Hour)
+}
+
+// HourlyAggregate counts one endpoint's checks in the hour starting at Hour,
+// the way collector.SummarizeHistory counts them, and summarises the quotes
+// (ReturnAmount, token_out base units) and quote spreads (bps, see
+// collector.QuoteSpreadBps) of its up checks.
+type HourlyAggregate struct {
+	Endpoint  string    `json:"endpoint"`
+	Hour      time.Time `json:"hour"`
+	Checks    int       `json:"checks"`
+	Up        int       `json:"up"`
+	Down      int       `json:"down"`
+	Quotes    int       `json:"quotes,omitempty"`
+	QuoteMin  float64   `json:"quoteMin,omitempty"`
+	QuoteMax  float64   `json:"quoteMax,omitempty"`
+	QuoteSum  float64   `json:"quoteSum,omitempty"`
+	Spreads   int       `json:"spreads,omitempty"`
+	SpreadMin float64   `json:"spreadMin,omitempty"`
+	SpreadMax float64   `json:"spreadMax,omitempty"`
+	SpreadSum float64   `json:"spreadSum,omitempty"`
+}
+
+// add counts one check record.
+func (a *HourlyAggregate) add(r collector.CheckRecord) {
+	switch {
+	case r.Status == "up":
+		o := HourlyAggregate{Checks: 1, Up: 1}
+		if q, ok := chartQuote(r.Quote()); ok {
+			o.Quotes, o.QuoteMin, o.QuoteMax, o.QuoteSum = 1, q, q, q
+		}
+		if bps, ok := r.SpreadBps(); ok {
+			o.Spreads, o.SpreadMin, o.SpreadMax, o.SpreadSum = 1, bps, bps, bps
+		}
+		a.merge(o)
+	case collector.IsDownStatus(r.Status):
+		a.merge(HourlyAggregate{Checks: 1, Down: 1})
+	}
+}
+
+// merge adds o's counts and quote and spread summaries to a.
+func (a *HourlyAggregate) merge(o HourlyAggregate) {
+	a.Checks += o.Checks
+	a.Up += o.Up
+	a.Down += o.Down
+	if o.Quotes > 0 {
+		if a.Quotes == 0 || o.QuoteMin < a.QuoteMin {
+			a.QuoteMin = o.QuoteMin
+		}
+		if a.Quotes == 0 || o.QuoteMax > a.QuoteMax {
+			a.QuoteMax = o.QuoteMax
+		}
+		a.Quotes += o.Quotes
+		a.QuoteSum += o.QuoteSum
+	}
+	if o.Spreads > 0 {
+		if a.Spreads == 0 || o.SpreadMin < a.SpreadMin {
+			a.SpreadMin = o.SpreadMin
+		}
+		if a.Spreads == 0 || o.SpreadMax > a.SpreadMax {
+			a.SpreadMax = o.SpreadMax
+		}
+		a.Spreads += o.Spreads
+		a.SpreadSum += o.SpreadSum
+	}
+}
+
+// chartQuote returns a ReturnAmount as a float for charting; empty, zero and
+// malformed amounts are not quotes.
+func chartQuote(a collector.Amount) (float64, bool) {
+	q := a.Float64()
+	if !a.Positive() || math.IsInf(q, 0) {
+		return 0, false
+	}
+	return q, true
+}
+
+// CompactStats reports what one compaction removed.
+type CompactStats struct {
+	Archived         int // raw checks rolled up into hourly aggregates
+	AggregatesPruned int
+	IncidentsPruned  int
+}
+
+// Compactor is implemented by stores that archive and prune their history so
+// it stays bounded (e.g. on a small Fly volume).
+type Compactor interface {
+	// Compact applies r at now.
+	Compact(now time.Time, r Retention) (CompactStats, error)
+	// QueryHourly returns the named endpoint's hourly aggregates since a
+	// time, oldest first.
+	QueryHourly(name string, since time.Time) ([]HourlyAggregate, error)
+}
+
+// RunCompaction compacts c every interval, starting immediately. Passes where
+// shouldRun reports false (a follower; the leader compacts the shared store)
+// are skipped.
+func RunCompaction(c Compactor, r Retention, interval time.Duration, shouldRun func() bool) {
+	for {
+		if shouldRun() {
+			stats, err := c.Compact(clock.Now(), r)
+			if err != nil {
+				logs.Printf("%s[STORE]%s compaction failed: %v\n", config.ColorRed, config.ColorReset, err)
+			} else if stats != (CompactStats{}) {
+				logs.Printf("%s[STORE]%s compacted history: %d checks archived, %d hourly aggregates and %d incidents pruned\n",
+					config.ColorGreen, config.ColorReset, stats.Archived, stats.AggregatesPruned, stats.IncidentsPruned)
+			}
+		}
+		clock.Sleep(interval)
+	}
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+// SeriesPoint is one bucket of a downsampled endpoint series, for charts that
+// span more checks than are worth sending to a browser. Min, Max and Avg
+// summarise the quotes (ReturnAmount, token_out base units) of up checks and
+// are zero when Quotes is; SpreadMin, SpreadMax and SpreadAvg likewise
+// summarise their quote spreads (bps by which the market price beat the
+// Balancer-only quote, see collector.QuoteSpreadBps).
+type SeriesPoint struct {
+	Start     time.Time `json:"start"`
+	Checks    int       `json:"checks"`
+	Up        int       `json:"up"`
+	Down      int       `json:"down"`
+	Quotes    int       `json:"quotes"`
+	Min       float64   `json:"min,omitempty"`
+	Max       float64   `json:"max,omitempty"`
+	Avg       float64   `json:"avg,omitempty"`
+	Spreads   int       `json:"spreads"`
+	SpreadMin float64   `json:"spreadMin,omitempty"`
+	SpreadMax float64   `json:"spreadMax,omitempty"`
+	SpreadAvg float64   `json:"spreadAvg,omitempty"`
+}
+
+// Downsample buckets archived hourly aggregates and raw records of one
+// endpoint into points bucket wide, aligned to UTC midnight, oldest first.
+// bucket must be a whole number of hours. Empty buckets are omitted.
+func Downsample(hourly []HourlyAggregate, records []collector.CheckRecord, bucket time.Duration) []SeriesPoint {
+	buckets := map[int64]*HourlyAggregate{}
+	at := func(t time.Time) *HourlyAggregate {
+		start := t.UTC().Truncate(bucket)
+		b, ok := buckets[start.Unix()]
+		if !ok {
+			b = &HourlyAggregate{Hour: start}
+			buckets[start.Unix()] = b
+		}
+		return b
+	}
+	for _, a := range hourly {
+		at(a.Hour).merge(a)
+	}
+	for _, r := range records {
+		at(r.At).add(r)
+	}
+
+	out := make([]SeriesPoint, 0, len(buckets))
+	for _, b := range buckets {
+		p := SeriesPoint{Start: b.Hour, Checks: b.Checks, Up: b.Up, Down: b.Down, Quotes: b.Quotes, Spreads: b.Spreads}
+		if b.Quotes > 0 {
+			p.Min, p.Max, p.Avg = b.QuoteMin, b.QuoteMax, b.QuoteSum/float64(b.Quotes)
+		}
+		if b.Spreads > 0 {
+			p.SpreadMin, p.SpreadMax, p.SpreadAvg = b.SpreadMin, b.SpreadMax, b.SpreadSum/float64(b.Spreads)
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
+
+// QuerySeries returns the named endpoint's series since a time from st: the
+// raw history, plus the archived hourly aggregates when st is a Compactor.
+func QuerySeries(st Store, name string, since time.Time, bucket time.Duration) ([]SeriesPoint, error) {
+	if bucket < time.Hour || bucket%time.Hour != 0 {
+		return nil, fmt.Errorf("bucket %s is not a whole number of hours", bucket)
+	}
+	records, err := st.QueryHistory(name, since)
+	if err != nil {
+		return nil, err
+	}
+	var hourly []HourlyAggregate
+	if c, ok := st.(Compactor); ok {
+		if hourly, err = c.QueryHourly(name, since); err != nil {
+			return nil, err
+		}
+	}
+	return Downsample(hourly, records, bucket), nil
+}
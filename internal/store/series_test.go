@@ -0,0 +1,85 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestDownsampleMergesAggregatesAndRecords(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	hourly := []HourlyAggregate{
+		{Endpoint: "A", Hour: day.Add(2 * time.Hour), Checks: 2, Up: 2, Quotes: 2, QuoteMin: 90, QuoteMax: 110, QuoteSum: 200},
+		{Endpoint: "A", Hour: day.Add(3 * time.Hour), Checks: 1, Down: 1},
+	}
+	records := []collector.CheckRecord{
+		{At: day.Add(20 * time.Hour), Status: "up", ReturnAmount: "130"},
+		{At: day.Add(21 * time.Hour), Status: "up", ReturnAmount: ""},
+		{At: day.Add(22 * time.Hour), Status: "down", ReturnAmount: "1"},
+		{At: day.Add(30 * time.Hour), Status: "up", ReturnAmount: "80"},
+		{At: day.Add(31 * time.Hour), Status: "skipped"},
+	}
+
+	got := Downsample(hourly, records, 24*time.Hour)
+	want := []SeriesPoint{
+		{Start: day, Checks: 6, Up: 4, Down: 2, Quotes: 3, Min: 90, Max: 130, Avg: 110},
+		{Start: day.Add(24 * time.Hour), Checks: 1, Up: 1, Quotes: 1, Min: 80, Max: 80, Avg: 80},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Downsample = %+v", got)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || got[i].Checks != want[i].Checks || got[i].Up != want[i].Up ||
+			got[i].Down != want[i].Down || got[i].Quotes != want[i].Quotes ||
+			got[i].Min != want[i].Min || got[i].Max != want[i].Max || got[i].Avg != want[i].Avg {
+			t.Fatalf("point %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if hours := Downsample(hourly, nil, time.Hour); len(hours) != 2 || hours[0].Avg != 100 {
+		t.Fatalf("hourly Downsample = %+v", hours)
+	}
+}
+
+func TestDownsampleSpreads(t *testing.T) {
+	hour := time.Date(2026, 3, 1, 5, 0, 0, 0, time.UTC)
+	hourly := []HourlyAggregate{{Hour: hour, Checks: 1, Up: 1, Spreads: 1, SpreadMin: -10, SpreadMax: -10, SpreadSum: -10}}
+	records := []collector.CheckRecord{
+		{At: hour.Add(10 * time.Minute), Status: "up", ReturnAmount: "990", MarketPrice: "1000"},
+		{At: hour.Add(20 * time.Minute), Status: "up", ReturnAmount: "990"},
+		{At: hour.Add(30 * time.Minute), Status: "down", ReturnAmount: "900", MarketPrice: "1000"},
+	}
+	got := Downsample(hourly, records, time.Hour)
+	if len(got) != 1 || got[0].Spreads != 2 || got[0].SpreadMin != -10 || got[0].SpreadMax != 100 || got[0].SpreadAvg != 45 {
+		t.Fatalf("Downsample = %+v", got)
+	}
+}
+
+func TestQuerySeriesSpansArchive(t *testing.T) {
+	s := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	now := time.Now().UTC()
+	for _, st := range []EndpointState{
+		{Name: "A", LastStatus: "up", ReturnAmount: "100", LastChecked: now.Add(-60 * 24 * time.Hour)},
+		{Name: "A", LastStatus: "up", ReturnAmount: "200", LastChecked: now.Add(-time.Hour)},
+	} {
+		if err := s.SaveResult(st); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Compact(now, Retention{Raw: 30 * 24 * time.Hour, Hourly: 365 * 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := QuerySeries(s, "A", now.Add(-90*24*time.Hour), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[0].Avg != 100 || points[1].Avg != 200 {
+		t.Fatalf("QuerySeries = %+v", points)
+	}
+	if _, err := QuerySeries(s, "A", now, 90*time.Minute); err == nil {
+		t.Fatal("QuerySeries accepted a 90m bucket")
+	}
+}
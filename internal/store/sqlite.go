@@ -0,0 +1,661 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+
+	_ "github.com/mattn/go-sqlite3" // registers the "sqlite3" driver
+)
+
+// SQLiteStore keeps results and incidents in a SQLite database file, e.g. on
+// a Fly volume: the same tables as PostgresStore for a single instance that
+// doesn't need a database server. Writes go straight to the file; there is
+// nothing to flush.
+//
+// Times are stored as text in UTC, so comparing them compares the instants.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// sqliteSchema is applied on open. Statements are idempotent.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS endpoint_latest (
+	name              TEXT PRIMARY KEY,
+	endpoint_id       TEXT NOT NULL DEFAULT '',
+	last_status       TEXT NOT NULL,
+	message           TEXT NOT NULL,
+	last_checked      DATETIME NOT NULL,
+	last_state_change DATETIME,
+	first_seen_down   DATETIME,
+	return_amount     TEXT NOT NULL,
+	market_price      TEXT NOT NULL,
+	on_chain_price    TEXT NOT NULL,
+	route             TEXT
+);
+CREATE TABLE IF NOT EXISTS check_results (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	name            TEXT NOT NULL,
+	checked_at      DATETIME NOT NULL,
+	status          TEXT NOT NULL,
+	message         TEXT NOT NULL,
+	return_amount   TEXT NOT NULL DEFAULT '',
+	market_price    TEXT NOT NULL DEFAULT '',
+	response_ms     INTEGER NOT NULL DEFAULT 0,
+	response_phases TEXT,
+	surge           TEXT
+);
+CREATE INDEX IF NOT EXISTS check_results_name_checked_at ON check_results (name, checked_at);
+CREATE TABLE IF NOT EXISTS check_results_hourly (
+	name       TEXT NOT NULL,
+	hour       DATETIME NOT NULL,
+	checks     INTEGER NOT NULL,
+	up         INTEGER NOT NULL,
+	down       INTEGER NOT NULL,
+	quotes     INTEGER NOT NULL,
+	quote_min  REAL,
+	quote_max  REAL,
+	quote_sum  REAL NOT NULL,
+	spreads    INTEGER NOT NULL DEFAULT 0,
+	spread_min REAL,
+	spread_max REAL,
+	spread_sum REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY (name, hour)
+);
+CREATE TABLE IF NOT EXISTS endpoint_notes (
+	name        TEXT PRIMARY KEY,
+	text        TEXT NOT NULL,
+	runbook_url TEXT NOT NULL,
+	updated_at  DATETIME NOT NULL
+);
+CREATE TABLE IF NOT EXISTS source_catalogs (
+	solver     TEXT NOT NULL,
+	network    TEXT NOT NULL,
+	sources    TEXT NOT NULL,
+	scanned_at DATETIME NOT NULL,
+	PRIMARY KEY (solver, network)
+);
+CREATE TABLE IF NOT EXISTS support (
+	solver         TEXT NOT NULL,
+	network        TEXT NOT NULL,
+	pool_kind      TEXT NOT NULL,
+	first_seen     DATETIME NOT NULL,
+	last_confirmed DATETIME NOT NULL,
+	PRIMARY KEY (solver, network, pool_kind)
+);
+CREATE TABLE IF NOT EXISTS api_tokens (
+	id         TEXT PRIMARY KEY,
+	name       TEXT NOT NULL,
+	hash       TEXT NOT NULL UNIQUE,
+	scopes     TEXT NOT NULL,
+	created_at DATETIME NOT NULL
+);
+CREATE TABLE IF NOT EXISTS annotations (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	at        DATETIME NOT NULL,
+	kind      TEXT NOT NULL,
+	base_name TEXT NOT NULL,
+	text      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS annotations_at ON annotations (at);
+CREATE TABLE IF NOT EXISTS incidents (
+	endpoint    TEXT NOT NULL,
+	started_at  DATETIME NOT NULL,
+	resolved_at DATETIME,
+	status      TEXT NOT NULL,
+	message     TEXT NOT NULL,
+	PRIMARY KEY (endpoint, started_at)
+);`
+
+// NewSQLiteStore opens (creating if needed) the database file at path and
+// creates the tables if needed. The token CLI may open the file while the
+// server has it open; writers wait for each other's locks.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	dsn := "file:" + path + "?" + url.Values{
+		"_busy_timeout": {"5000"},
+		"_journal_mode": {"WAL"},
+		"_loc":          {"UTC"},
+	}.Encode()
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// One connection serialises this process's writers, which SQLite would
+	// otherwise answer with "database is locked".
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// SaveResult upserts the latest state and appends a history row in one
+// transaction.
+func (s *SQLiteStore) SaveResult(st EndpointState) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveLatestSQLite(tx, st); err != nil {
+		return err
+	}
+	var phases []byte
+	if !st.ResponsePhases.IsZero() {
+		if phases, err = json.Marshal(st.ResponsePhases); err != nil {
+			return fmt.Errorf("encode response phases: %w", err)
+		}
+	}
+	var surge []byte
+	if !st.Surge.IsZero() {
+		if surge, err = json.Marshal(st.Surge); err != nil {
+			return fmt.Errorf("encode surge state: %w", err)
+		}
+	}
+	_, err = tx.Exec(`
+INSERT INTO check_results (name, checked_at, status, message, return_amount, market_price, response_ms, response_phases, surge)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9)`,
+		st.Name, st.LastChecked.UTC(), st.LastStatus, st.Message, st.ReturnAmount, st.MarketPrice, st.ResponseTime.Milliseconds(), nullJSON(phases), nullJSON(surge))
+	if err != nil {
+		return fmt.Errorf("save history: %w", err)
+	}
+	return tx.Commit()
+}
+
+// SaveLatest upserts the latest state alone.
+func (s *SQLiteStore) SaveLatest(st EndpointState) error {
+	return saveLatestSQLite(s.db, st)
+}
+
+// saveLatestSQLite upserts st into endpoint_latest.
+func saveLatestSQLite(db execer, st EndpointState) error {
+	var route []byte
+	if st.Route != nil {
+		var err error
+		if route, err = json.Marshal(st.Route); err != nil {
+			return fmt.Errorf("encode route: %w", err)
+		}
+	}
+	_, err := db.Exec(`
+INSERT INTO endpoint_latest (name, last_status, message, last_checked, last_state_change, first_seen_down, return_amount, market_price, on_chain_price, route, endpoint_id)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11)
+ON CONFLICT (name) DO UPDATE SET
+	last_status = excluded.last_status,
+	message = excluded.message,
+	last_checked = excluded.last_checked,
+	last_state_change = excluded.last_state_change,
+	first_seen_down = excluded.first_seen_down,
+	return_amount = excluded.return_amount,
+	market_price = excluded.market_price,
+	on_chain_price = excluded.on_chain_price,
+	route = excluded.route,
+	endpoint_id = excluded.endpoint_id`,
+		st.Name, st.LastStatus, st.Message, st.LastChecked.UTC(), nullUTC(st.LastStateChange), nullUTC(st.FirstSeenDown),
+		st.ReturnAmount, st.MarketPrice, st.OnChainPrice, nullJSON(route), st.ID)
+	if err != nil {
+		return fmt.Errorf("save latest: %w", err)
+	}
+	return nil
+}
+
+// LoadLatest returns every endpoint's latest state, ordered by name.
+func (s *SQLiteStore) LoadLatest() ([]EndpointState, error) {
+	rows, err := s.db.Query(`
+SELECT name, last_status, message, last_checked, last_state_change, first_seen_down, return_amount, market_price, on_chain_price, route, endpoint_id
+FROM endpoint_latest ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []EndpointState
+	for rows.Next() {
+		var st EndpointState
+		var stateChange, firstDown sql.NullTime
+		var route sql.NullString
+		if err := rows.Scan(&st.Name, &st.LastStatus, &st.Message, &st.LastChecked, &stateChange, &firstDown,
+			&st.ReturnAmount, &st.MarketPrice, &st.OnChainPrice, &route, &st.ID); err != nil {
+			return nil, err
+		}
+		if route.String != "" {
+			st.Route = &collector.RouteGraph{}
+			if err := json.Unmarshal([]byte(route.String), st.Route); err != nil {
+				return nil, fmt.Errorf("decode route of %s: %w", st.Name, err)
+			}
+		}
+		st.LastStateChange = stateChange.Time
+		st.FirstSeenDown = firstDown.Time
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+// QueryHistory returns the named endpoint's checks since a time, oldest first.
+func (s *SQLiteStore) QueryHistory(name string, since time.Time) ([]collector.CheckRecord, error) {
+	rows, err := s.db.Query(`
+SELECT checked_at, status, message, return_amount, market_price, response_ms, response_phases, surge FROM check_results
+WHERE name = ?1 AND checked_at >= ?2 ORDER BY checked_at, id`, name, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []collector.CheckRecord
+	for rows.Next() {
+		var r collector.CheckRecord
+		var ms int64
+		var phases, surge sql.NullString
+		if err := rows.Scan(&r.At, &r.Status, &r.Message, &r.ReturnAmount, &r.MarketPrice, &ms, &phases, &surge); err != nil {
+			return nil, err
+		}
+		r.ResponseTime = time.Duration(ms) * time.Millisecond
+		if phases.String != "" {
+			if err := json.Unmarshal([]byte(phases.String), &r.ResponsePhases); err != nil {
+				return nil, fmt.Errorf("decode response phases of %s: %w", name, err)
+			}
+		}
+		if surge.String != "" {
+			if err := json.Unmarshal([]byte(surge.String), &r.Surge); err != nil {
+				return nil, fmt.Errorf("decode surge state of %s: %w", name, err)
+			}
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// ImportHistory inserts the records in one transaction, skipping times the
+// endpoint already has a check at.
+func (s *SQLiteStore) ImportHistory(name string, records []collector.CheckRecord) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, r := range records {
+		if _, err := tx.Exec(`
+INSERT INTO check_results (name, checked_at, status, message)
+SELECT ?1, ?2, ?3, ?4
+WHERE NOT EXISTS (SELECT 1 FROM check_results WHERE name = ?1 AND checked_at = ?2)`,
+			name, r.At.UTC(), r.Status, r.Message); err != nil {
+			return fmt.Errorf("import history: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveIncident inserts an incident or resolves the stored one.
+func (s *SQLiteStore) SaveIncident(inc Incident) error {
+	_, err := s.db.Exec(`
+INSERT INTO incidents (endpoint, started_at, resolved_at, status, message)
+VALUES (?1, ?2, ?3, ?4, ?5)
+ON CONFLICT (endpoint, started_at) DO UPDATE SET resolved_at = excluded.resolved_at`,
+		inc.Endpoint, inc.StartedAt.UTC(), nullUTC(inc.ResolvedAt), inc.Status, inc.Message)
+	return err
+}
+
+// sqliteRenameStatements are renameStatements in SQLite's dialect.
+var sqliteRenameStatements = []struct{ what, query string }{
+	{"results", `UPDATE check_results SET name = ?2 WHERE name = ?1`},
+	{"aggregates", `UPDATE check_results_hourly AS o SET name = ?2 WHERE name = ?1
+AND NOT EXISTS (SELECT 1 FROM check_results_hourly n WHERE n.name = ?2 AND n.hour = o.hour)`},
+	{"incidents", `UPDATE incidents AS o SET endpoint = ?2 WHERE endpoint = ?1
+AND NOT EXISTS (SELECT 1 FROM incidents n WHERE n.endpoint = ?2 AND n.started_at = o.started_at)`},
+	{"note", `UPDATE endpoint_notes SET name = ?2 WHERE name = ?1
+AND NOT EXISTS (SELECT 1 FROM endpoint_notes WHERE name = ?2)`},
+	{"latest", `UPDATE endpoint_latest SET name = ?2 WHERE name = ?1
+AND NOT EXISTS (SELECT 1 FROM endpoint_latest WHERE name = ?2)`},
+	{"leftover aggregates", `DELETE FROM check_results_hourly WHERE name = ?1`},
+	{"leftover incidents", `DELETE FROM incidents WHERE endpoint = ?1`},
+	{"leftover note", `DELETE FROM endpoint_notes WHERE name = ?1`},
+	{"leftover latest", `DELETE FROM endpoint_latest WHERE name = ?1`},
+}
+
+// RenameEndpoint moves old's rows to name in one transaction.
+func (s *SQLiteStore) RenameEndpoint(old, name string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, st := range sqliteRenameStatements {
+		if _, err := tx.Exec(st.query, old, name); err != nil {
+			return fmt.Errorf("rename %s: %w", st.what, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveNote upserts the endpoint's note, or deletes it when empty.
+func (s *SQLiteStore) SaveNote(n collector.Note) error {
+	if n.IsEmpty() {
+		_, err := s.db.Exec(`DELETE FROM endpoint_notes WHERE name = ?1`, n.Endpoint)
+		return err
+	}
+	_, err := s.db.Exec(`
+INSERT INTO endpoint_notes (name, text, runbook_url, updated_at) VALUES (?1, ?2, ?3, ?4)
+ON CONFLICT (name) DO UPDATE SET
+	text = excluded.text,
+	runbook_url = excluded.runbook_url,
+	updated_at = excluded.updated_at`,
+		n.Endpoint, n.Text, n.RunbookURL, n.UpdatedAt.UTC())
+	return err
+}
+
+// LoadNotes returns every note, ordered by endpoint name.
+func (s *SQLiteStore) LoadNotes() ([]collector.Note, error) {
+	rows, err := s.db.Query(`SELECT name, text, runbook_url, updated_at FROM endpoint_notes ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []collector.Note
+	for rows.Next() {
+		var n collector.Note
+		if err := rows.Scan(&n.Endpoint, &n.Text, &n.RunbookURL, &n.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+// SaveSourceCatalog upserts the solver and network's catalog.
+func (s *SQLiteStore) SaveSourceCatalog(c SourceCatalog) error {
+	ids, err := json.Marshal(c.Sources)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+INSERT INTO source_catalogs (solver, network, sources, scanned_at) VALUES (?1, ?2, ?3, ?4)
+ON CONFLICT (solver, network) DO UPDATE SET
+	sources = excluded.sources,
+	scanned_at = excluded.scanned_at`,
+		c.Solver, c.Network, string(ids), c.ScannedAt.UTC())
+	return err
+}
+
+// LoadSourceCatalogs returns every catalog, ordered by solver and network.
+func (s *SQLiteStore) LoadSourceCatalogs() ([]SourceCatalog, error) {
+	rows, err := s.db.Query(`SELECT solver, network, sources, scanned_at FROM source_catalogs ORDER BY solver, network`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SourceCatalog
+	for rows.Next() {
+		var c SourceCatalog
+		var ids string
+		if err := rows.Scan(&c.Solver, &c.Network, &ids, &c.ScannedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(ids), &c.Sources); err != nil {
+			return nil, fmt.Errorf("catalog %s/%s: %w", c.Solver, c.Network, err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// SaveAnnotation inserts an annotation.
+func (s *SQLiteStore) SaveAnnotation(a Annotation) error {
+	_, err := s.db.Exec(`INSERT INTO annotations (at, kind, base_name, text) VALUES (?1, ?2, ?3, ?4)`,
+		a.At.UTC(), a.Kind, a.BaseName, a.Text)
+	return err
+}
+
+// QueryAnnotations returns the annotations since a time, oldest first.
+func (s *SQLiteStore) QueryAnnotations(since time.Time) ([]Annotation, error) {
+	rows, err := s.db.Query(`SELECT at, kind, base_name, text FROM annotations WHERE at >= ?1 ORDER BY at, id`, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Annotation
+	for rows.Next() {
+		var a Annotation
+		if err := rows.Scan(&a.At, &a.Kind, &a.BaseName, &a.Text); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// SaveSupport upserts the coverage cell, keeping the earlier first_seen and
+// the later last_confirmed.
+func (s *SQLiteStore) SaveSupport(c Support) error {
+	_, err := s.db.Exec(`
+INSERT INTO support (solver, network, pool_kind, first_seen, last_confirmed) VALUES (?1, ?2, ?3, ?4, ?5)
+ON CONFLICT (solver, network, pool_kind) DO UPDATE SET
+	first_seen = min(support.first_seen, excluded.first_seen),
+	last_confirmed = max(support.last_confirmed, excluded.last_confirmed)`,
+		c.Solver, c.Network, c.PoolKind, c.FirstSeen.UTC(), c.LastConfirmed.UTC())
+	return err
+}
+
+// LoadSupport returns every coverage cell, ordered by solver, network and
+// pool kind.
+func (s *SQLiteStore) LoadSupport() ([]Support, error) {
+	rows, err := s.db.Query(`SELECT solver, network, pool_kind, first_seen, last_confirmed FROM support ORDER BY solver, network, pool_kind`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Support
+	for rows.Next() {
+		var c Support
+		if err := rows.Scan(&c.Solver, &c.Network, &c.PoolKind, &c.FirstSeen, &c.LastConfirmed); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Compact rolls check_results older than r.Raw up into check_results_hourly
+// and deletes them, then deletes aggregates and resolved incidents older than
+// r.Hourly, in one transaction. SQLite can't parse the quote amounts the way
+// Postgres does, so the checks are counted here with HourlyAggregate.add, as
+// FileStore counts them.
+func (s *SQLiteStore) Compact(now time.Time, r Retention) (CompactStats, error) {
+	rawCutoff, hourlyCutoff := r.cutoffs(now)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return CompactStats{}, err
+	}
+	defer tx.Rollback()
+
+	aggs, err := hourlyAggregatesBefore(tx, rawCutoff.UTC())
+	if err != nil {
+		return CompactStats{}, fmt.Errorf("archive checks: %w", err)
+	}
+	for _, a := range aggs {
+		if err := mergeHourlySQLite(tx, a); err != nil {
+			return CompactStats{}, fmt.Errorf("archive checks: %w", err)
+		}
+	}
+	var stats CompactStats
+	if stats.Archived, err = execCount(tx, `DELETE FROM check_results WHERE checked_at < ?1`, rawCutoff.UTC()); err != nil {
+		return CompactStats{}, fmt.Errorf("delete archived checks: %w", err)
+	}
+	if stats.AggregatesPruned, err = execCount(tx, `DELETE FROM check_results_hourly WHERE hour < ?1`, hourlyCutoff.UTC()); err != nil {
+		return CompactStats{}, fmt.Errorf("prune aggregates: %w", err)
+	}
+	if stats.IncidentsPruned, err = execCount(tx, `DELETE FROM incidents WHERE resolved_at < ?1`, hourlyCutoff.UTC()); err != nil {
+		return CompactStats{}, fmt.Errorf("prune incidents: %w", err)
+	}
+	return stats, tx.Commit()
+}
+
+// hourlyAggregatesBefore counts the checks before cutoff into one aggregate
+// per endpoint and UTC hour.
+func hourlyAggregatesBefore(tx *sql.Tx, cutoff time.Time) ([]HourlyAggregate, error) {
+	rows, err := tx.Query(`SELECT name, checked_at, status, return_amount, market_price FROM check_results WHERE checked_at < ?1`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type key struct {
+		name string
+		hour time.Time
+	}
+	byHour := map[key]*HourlyAggregate{}
+	var order []key
+	for rows.Next() {
+		var name string
+		var r collector.CheckRecord
+		if err := rows.Scan(&name, &r.At, &r.Status, &r.ReturnAmount, &r.MarketPrice); err != nil {
+			return nil, err
+		}
+		k := key{name, r.At.UTC().Truncate(time.Hour)}
+		a := byHour[k]
+		if a == nil {
+			a = &HourlyAggregate{Endpoint: name, Hour: k.hour}
+			byHour[k] = a
+			order = append(order, k)
+		}
+		a.add(r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]HourlyAggregate, 0, len(order))
+	for _, k := range order {
+		out = append(out, *byHour[k])
+	}
+	return out, nil
+}
+
+// mergeHourlySQLite adds a to the stored aggregate of its endpoint and hour.
+func mergeHourlySQLite(tx *sql.Tx, a HourlyAggregate) error {
+	stored, err := scanHourly(tx.QueryRow(`
+SELECT hour, checks, up, down, quotes, quote_min, quote_max, quote_sum, spreads, spread_min, spread_max, spread_sum FROM check_results_hourly
+WHERE name = ?1 AND hour = ?2`, a.Endpoint, a.Hour), a.Endpoint)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return err
+	default:
+		stored.merge(a)
+		a = stored
+	}
+	_, err = tx.Exec(`
+INSERT OR REPLACE INTO check_results_hourly (name, hour, checks, up, down, quotes, quote_min, quote_max, quote_sum, spreads, spread_min, spread_max, spread_sum)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13)`,
+		a.Endpoint, a.Hour, a.Checks, a.Up, a.Down,
+		a.Quotes, nullFloat(a.QuoteMin, a.Quotes), nullFloat(a.QuoteMax, a.Quotes), a.QuoteSum,
+		a.Spreads, nullFloat(a.SpreadMin, a.Spreads), nullFloat(a.SpreadMax, a.Spreads), a.SpreadSum)
+	return err
+}
+
+// QueryHourly returns the named endpoint's hourly aggregates since a time,
+// oldest first.
+func (s *SQLiteStore) QueryHourly(name string, since time.Time) ([]HourlyAggregate, error) {
+	rows, err := s.db.Query(`
+SELECT hour, checks, up, down, quotes, quote_min, quote_max, quote_sum, spreads, spread_min, spread_max, spread_sum FROM check_results_hourly
+WHERE name = ?1 AND hour >= ?2 ORDER BY hour`, name, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HourlyAggregate
+	for rows.Next() {
+		a, err := scanHourly(rows, name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// scanHourly reads a check_results_hourly row selected as in QueryHourly.
+func scanHourly(row interface{ Scan(...any) error }, name string) (HourlyAggregate, error) {
+	a := HourlyAggregate{Endpoint: name}
+	var quoteMin, quoteMax, spreadMin, spreadMax sql.NullFloat64
+	if err := row.Scan(&a.Hour, &a.Checks, &a.Up, &a.Down, &a.Quotes, &quoteMin, &quoteMax, &a.QuoteSum,
+		&a.Spreads, &spreadMin, &spreadMax, &a.SpreadSum); err != nil {
+		return HourlyAggregate{}, err
+	}
+	a.QuoteMin, a.QuoteMax = quoteMin.Float64, quoteMax.Float64
+	a.SpreadMin, a.SpreadMax = spreadMin.Float64, spreadMax.Float64
+	return a, nil
+}
+
+// SaveAPIToken upserts the token.
+func (s *SQLiteStore) SaveAPIToken(t APIToken) error {
+	scopes, err := json.Marshal(t.Scopes)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+INSERT INTO api_tokens (id, name, hash, scopes, created_at) VALUES (?1, ?2, ?3, ?4, ?5)
+ON CONFLICT (id) DO UPDATE SET
+	name = excluded.name,
+	hash = excluded.hash,
+	scopes = excluded.scopes,
+	created_at = excluded.created_at`,
+		t.ID, t.Name, t.Hash, string(scopes), t.CreatedAt.UTC())
+	return err
+}
+
+// DeleteAPIToken deletes the token with the ID.
+func (s *SQLiteStore) DeleteAPIToken(id string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM api_tokens WHERE id = ?1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// LoadAPITokens returns every token, oldest first.
+func (s *SQLiteStore) LoadAPITokens() ([]APIToken, error) {
+	rows, err := s.db.Query(`SELECT id, name, hash, scopes, created_at FROM api_tokens ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []APIToken
+	for rows.Next() {
+		var t APIToken
+		var scopes string
+		if err := rows.Scan(&t.ID, &t.Name, &t.Hash, &scopes, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(scopes), &t.Scopes); err != nil {
+			return nil, fmt.Errorf("decode scopes of token %s: %w", t.ID, err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// nullUTC maps the zero time to NULL and others to UTC.
+func nullUTC(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
+
+// nullFloat stores the min or max of no values as NULL.
+func nullFloat(v float64, n int) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: v, Valid: n > 0}
+}
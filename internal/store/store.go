@@ -0,0 +1,317 @@
+// Package store persists the monitor's check results across restarts so a
+// deploy can warm-start endpoint statuses and uptime history instead of
+// starting from "unknown".
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+// Snapshot is the persisted monitor state: the latest endpoint results, the
+// per-endpoint check history (base and discovered, keyed by Endpoint.Name),
+// incidents and the operators' endpoint notes. It is FileStore's file format
+// and what Restore applies; Hourly holds FileStore's archived history,
+// Catalogs the source scan's last catalogs, Support the coverage matrix and
+// Annotations the deploy and config change marks; none is restored.
+type Snapshot struct {
+	SavedAt     time.Time                          `json:"savedAt"`
+	Endpoints   []EndpointState                    `json:"endpoints"`
+	History     map[string][]collector.CheckRecord `json:"history"`
+	Incidents   []Incident                         `json:"incidents,omitempty"`
+	Hourly      []HourlyAggregate                  `json:"hourly,omitempty"`
+	Notes       []collector.Note                   `json:"notes,omitempty"`
+	Catalogs    []SourceCatalog                    `json:"catalogs,omitempty"`
+	Support     []Support                          `json:"support,omitempty"`
+	Annotations []Annotation                       `json:"annotations,omitempty"`
+}
+
+// EndpointState is the result portion of a collector.Endpoint. Configuration
+// fields (tokens, pool, amount, rules) always come from config, so a
+// persisted snapshot can't override a config change.
+type EndpointState struct {
+	Name            string    `json:"name"`
+	ID              string    `json:"id,omitempty"` // Endpoint.ID, for restoring a renamed row
+	LastStatus      string    `json:"lastStatus"`
+	Message         string    `json:"message"`
+	LastChecked     time.Time `json:"lastChecked"`
+	LastStateChange time.Time `json:"lastStateChange"`
+	FirstSeenDown   time.Time `json:"firstSeenDown"`
+	ReturnAmount    string    `json:"returnAmount"`
+	MarketPrice     string    `json:"marketPrice"`
+	OnChainPrice    string    `json:"onChainPrice"`
+	// ResponseTime is the Balancer-only response's, 0 when none arrived.
+	ResponseTime time.Duration `json:"responseTime,omitempty"`
+	// ResponsePhases splits ResponseTime, zero when none arrived.
+	ResponsePhases collector.LatencyPhases `json:"responsePhases,omitzero"`
+	// Surge is a StableSurge pool's fee state at the check, zero otherwise.
+	Surge collector.SurgeState `json:"surge,omitzero"`
+	// Route is the latest returned route, nil when the check returned none.
+	Route *collector.RouteGraph `json:"route,omitempty"`
+}
+
+// Incident is one down streak of an endpoint, opened when it enters a down
+// status and resolved when it leaves it. ResolvedAt is zero while ongoing.
+type Incident struct {
+	Endpoint   string    `json:"endpoint"`
+	Status     string    `json:"status"`  // status that opened the incident
+	Message    string    `json:"message"` // failure message at that point
+	StartedAt  time.Time `json:"startedAt"`
+	ResolvedAt time.Time `json:"resolvedAt"`
+}
+
+// Annotation kinds.
+const (
+	AnnotationDeploy = "deploy" // a new version started
+	AnnotationConfig = "config" // a BaseEndpoint was added or edited at runtime
+)
+
+// Annotation marks one of our own changes on the history charts, so a quote
+// regression can be lined up with the deploy or endpoint edit before it.
+type Annotation struct {
+	At   time.Time `json:"at"`
+	Kind string    `json:"kind"` // AnnotationDeploy or AnnotationConfig
+	// BaseName is the edited BaseEndpoint; empty for a deploy, which
+	// concerns every row.
+	BaseName string `json:"baseName,omitempty"`
+	// Text is the version for a deploy, what changed for a config edit.
+	Text string `json:"text"`
+}
+
+// SourceCatalog is the source list a provider published for a network at
+// the last source scan, kept so the next scan can tell which sources were
+// dropped.
+type SourceCatalog struct {
+	Solver    string    `json:"solver"` // RouteSolver.Type
+	Network   string    `json:"network"`
+	Sources   []string  `json:"sources"`
+	ScannedAt time.Time `json:"scannedAt"`
+}
+
+// Support is a cell of the coverage matrix: when a provider was first seen
+// quoting a pool kind on a network, and when it last did. An up check is
+// what confirms it.
+type Support struct {
+	Solver        string    `json:"solver"` // RouteSolver.Type
+	Network       string    `json:"network"`
+	PoolKind      string    `json:"poolKind"` // sources.Kind*, empty when unclassified
+	FirstSeen     time.Time `json:"firstSeen"`
+	LastConfirmed time.Time `json:"lastConfirmed"`
+}
+
+// Merge returns s widened to also cover o's timestamps: the earlier
+// FirstSeen and the later LastConfirmed.
+func (s Support) Merge(o Support) Support {
+	if s.FirstSeen.IsZero() || !o.FirstSeen.IsZero() && o.FirstSeen.Before(s.FirstSeen) {
+		s.FirstSeen = o.FirstSeen
+	}
+	if o.LastConfirmed.After(s.LastConfirmed) {
+		s.LastConfirmed = o.LastConfirmed
+	}
+	return s
+}
+
+// APIToken is an API token for automation, stored by the SHA-256 of its
+// secret (see package apitoken); the secret itself is shown once, at
+// creation.
+type APIToken struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`   // hex SHA-256 of the secret
+	Scopes    []string  `json:"scopes"` // apitoken.Scope values
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Store persists check results and incidents: FileStore, SQLiteStore or
+// PostgresStore. Results are saved as each check completes; a store shared
+// between instances (Postgres) lets a new or standby instance start from the
+// latest state of every endpoint.
+type Store interface {
+	// SaveResult records a completed check: it becomes the endpoint's latest
+	// state and is appended to its history.
+	SaveResult(EndpointState) error
+	// SaveLatest records a check that isn't a history sample (a down row's
+	// recheck): it becomes the endpoint's latest state only.
+	SaveLatest(EndpointState) error
+	// LoadLatest returns the latest state of every endpoint with a saved
+	// result, base and discovered.
+	LoadLatest() ([]EndpointState, error)
+	// QueryHistory returns the named endpoint's checks since a time, oldest
+	// first.
+	QueryHistory(name string, since time.Time) ([]collector.CheckRecord, error)
+	// ImportHistory adds past checks to the named endpoint's history,
+	// skipping any at a time already recorded, without changing its latest
+	// state; for backfills.
+	ImportHistory(name string, records []collector.CheckRecord) error
+	// RenameEndpoint files what is kept under old (latest state, history,
+	// hourly aggregates, incidents, note) under name, when a row keeps its
+	// ID under a new name. Where name already has a row of its own (a state,
+	// an hour, an incident start, a note), that one is kept.
+	RenameEndpoint(old, name string) error
+	// SaveIncident inserts an incident, or sets ResolvedAt on the stored one
+	// with the same Endpoint and StartedAt.
+	SaveIncident(Incident) error
+	// SaveNote stores an endpoint's note, replacing the previous one, or
+	// deletes it when the note is empty. Notes are edited rarely and saved
+	// right away, not buffered until the next flush.
+	SaveNote(collector.Note) error
+	// LoadNotes returns every stored note.
+	LoadNotes() ([]collector.Note, error)
+	// SaveSourceCatalog stores a solver and network's catalog, replacing the
+	// previous one. Like notes, catalogs are saved right away.
+	SaveSourceCatalog(SourceCatalog) error
+	// LoadSourceCatalogs returns every stored catalog.
+	LoadSourceCatalogs() ([]SourceCatalog, error)
+	// SaveSupport merges a coverage cell into the stored one with the same
+	// Solver, Network and PoolKind (see Support.Merge). Saved with the
+	// results, so buffered like them.
+	SaveSupport(Support) error
+	// LoadSupport returns every stored coverage cell.
+	LoadSupport() ([]Support, error)
+	// SaveAPIToken stores a token, replacing the one with the same ID. Saved
+	// right away, and visible to other processes sharing the store (the
+	// token CLI and a running server).
+	SaveAPIToken(APIToken) error
+	// DeleteAPIToken removes the token with the ID, reporting whether there
+	// was one.
+	DeleteAPIToken(id string) (bool, error)
+	// LoadAPITokens returns every stored token, read fresh from the store.
+	LoadAPITokens() ([]APIToken, error)
+	// SaveAnnotation appends an annotation. Saved right away, like notes.
+	SaveAnnotation(Annotation) error
+	// QueryAnnotations returns the annotations since a time, oldest first.
+	QueryAnnotations(since time.Time) ([]Annotation, error)
+}
+
+// Flusher is implemented by stores that buffer writes; Flush is called after
+// every check cycle.
+type Flusher interface {
+	Flush() error
+}
+
+// HistoryRetention is how much history LoadSnapshot reads back, matching the
+// collector's one-week window. Stores keep raw checks for longer; see
+// Retention.
+const HistoryRetention = 7 * 24 * time.Hour
+
+// StateOf extracts the persisted fields of an endpoint.
+func StateOf(e collector.Endpoint) EndpointState {
+	st := EndpointState{
+		Name:            e.Name,
+		ID:              e.ID,
+		LastStatus:      e.LastStatus,
+		Message:         e.Message,
+		LastChecked:     e.LastChecked,
+		LastStateChange: e.LastStateChange,
+		FirstSeenDown:   e.FirstSeenDown,
+		ReturnAmount:    e.ReturnAmount,
+		MarketPrice:     e.MarketPrice,
+		OnChainPrice:    e.OnChainPrice,
+		ResponseTime:    e.ResponseTime,
+		ResponsePhases:  e.ResponsePhases,
+		Surge:           e.Surge,
+	}
+	if !e.Route.IsEmpty() {
+		route := e.Route
+		st.Route = &route
+	}
+	return st
+}
+
+// Apply copies the persisted fields onto an endpoint.
+func (s EndpointState) Apply(e *collector.Endpoint) {
+	e.LastStatus = s.LastStatus
+	e.Message = s.Message
+	e.LastChecked = s.LastChecked
+	e.LastStateChange = s.LastStateChange
+	e.FirstSeenDown = s.FirstSeenDown
+	e.ReturnAmount = s.ReturnAmount
+	e.MarketPrice = s.MarketPrice
+	e.OnChainPrice = s.OnChainPrice
+	e.ResponseTime = s.ResponseTime
+	e.ResponsePhases = s.ResponsePhases
+	e.Surge = s.Surge
+	e.Route = collector.RouteGraph{}
+	if s.Route != nil {
+		e.Route = *s.Route
+	}
+}
+
+// Record is the history record for a saved result.
+func (s EndpointState) Record() collector.CheckRecord {
+	return collector.CheckRecord{At: s.LastChecked, Status: s.LastStatus, Message: s.Message, ReturnAmount: s.ReturnAmount, MarketPrice: s.MarketPrice, ResponseTime: s.ResponseTime,
+		ResponsePhases: s.ResponsePhases, Surge: s.Surge}
+}
+
+// LoadSnapshot reads the latest states, each endpoint's history since
+// now - HistoryRetention and the notes into a Snapshot for Restore.
+func LoadSnapshot(st Store, now time.Time) (Snapshot, error) {
+	latest, err := st.LoadLatest()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	notes, err := st.LoadNotes()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("notes: %w", err)
+	}
+	snap := Snapshot{Endpoints: latest, History: make(map[string][]collector.CheckRecord, len(latest)), Notes: notes}
+	since := now.Add(-HistoryRetention)
+	for _, s := range latest {
+		if s.LastChecked.After(snap.SavedAt) {
+			snap.SavedAt = s.LastChecked
+		}
+		recs, err := st.QueryHistory(s.Name, since)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("history for %s: %w", s.Name, err)
+		}
+		if len(recs) > 0 {
+			snap.History[s.Name] = recs
+		}
+	}
+	return snap, nil
+}
+
+// Restore applies a snapshot to the BaseEndpoints and discovered stores and
+// the history, and replaces the notes. A state applies to the row of its
+// name or, when that is gone, to the row of its ID, which is then renamed
+// (collector.RenameEndpoint): it takes the state's history and note, and the
+// rename handler moves what the store keeps. So renaming a row keeps its
+// results; a state whose ID row has a state of its own under its new name is
+// skipped. Endpoints in neither store (no longer in config, or not yet
+// discovered) are skipped. Returns the number of endpoints restored.
+func Restore(snap Snapshot) int {
+	saved := make(map[string]bool, len(snap.Endpoints))
+	for _, s := range snap.Endpoints {
+		saved[s.Name] = true
+	}
+	renamed := map[string]string{}
+	restored := 0
+	for _, s := range snap.Endpoints {
+		if collector.UpdateEndpointByName(s.Name, s.Apply) || collector.UpdateDiscoveredEndpointByName(s.Name, s.Apply) {
+			restored++
+			continue
+		}
+		if s.ID == "" {
+			continue
+		}
+		name, ok := collector.ResolveEndpoint(s.ID)
+		if !ok || saved[name] {
+			continue
+		}
+		if collector.UpdateEndpointByName(name, s.Apply) || collector.UpdateDiscoveredEndpointByName(name, s.Apply) {
+			saved[name] = true
+			renamed[s.Name] = name
+			restored++
+		}
+	}
+	for name, recs := range snap.History {
+		collector.SetHistory(name, recs)
+	}
+	collector.SetNotes(snap.Notes)
+	for old, name := range renamed {
+		collector.RenameEndpoint(old, name)
+	}
+	return restored
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"go-monitoring/internal/fsutil"
+)
+
+// tokensPath is the file FileStore keeps API tokens in, next to the
+// snapshot: the snapshot is buffered in memory and rewritten by a running
+// server, which would drop tokens the CLI added meanwhile.
+func (s *FileStore) tokensPath() string {
+	return strings.TrimSuffix(s.path, ".json") + ".tokens.json"
+}
+
+// SaveAPIToken replaces or adds the token and writes the tokens file.
+func (s *FileStore) SaveAPIToken(t APIToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.readTokens()
+	if err != nil {
+		return err
+	}
+	kept := tokens[:0]
+	for _, cur := range tokens {
+		if cur.ID != t.ID {
+			kept = append(kept, cur)
+		}
+	}
+	return s.writeTokens(append(kept, t))
+}
+
+// DeleteAPIToken removes the token and writes the tokens file.
+func (s *FileStore) DeleteAPIToken(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.readTokens()
+	if err != nil {
+		return false, err
+	}
+	kept := tokens[:0]
+	for _, cur := range tokens {
+		if cur.ID != id {
+			kept = append(kept, cur)
+		}
+	}
+	if len(kept) == len(tokens) {
+		return false, nil
+	}
+	return true, s.writeTokens(kept)
+}
+
+// LoadAPITokens reads the tokens file; a missing file has no tokens.
+func (s *FileStore) LoadAPITokens() ([]APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readTokens()
+}
+
+// readTokens reads the tokens file. Callers hold s.mu.
+func (s *FileStore) readTokens() ([]APIToken, error) {
+	data, err := os.ReadFile(s.tokensPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tokens []APIToken
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", s.tokensPath(), err)
+	}
+	return tokens, nil
+}
+
+// writeTokens writes the tokens file, readable by its owner only, with
+// fsutil.AtomicWriteFile. Callers hold s.mu.
+func (s *FileStore) writeTokens(tokens []APIToken) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	return fsutil.AtomicWriteFile(s.tokensPath(), data)
+}
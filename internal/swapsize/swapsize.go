@@ -0,0 +1,137 @@
+// Package swapsize suggests and sanity-checks an endpoint's SwapAmount from
+// its pool type and the token_in USD price, so endpoints created by import
+// are sized like the hand-written ones: a notional the pool can carry
+// without the quote being dominated by price impact, and never dust.
+package swapsize
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-monitoring/config"
+)
+
+// PoolInfo is what sizing needs to know about a pool.
+type PoolInfo struct {
+	Type      string             // Balancer API enum, e.g. "STABLE"
+	TVLUSD    float64            // 0 when unknown
+	PricesUSD map[string]float64 // lower-case token address → USD price
+}
+
+// PoolInfoFunc looks a pool up; ok is false when it is unknown.
+type PoolInfoFunc func(network, pool string) (info PoolInfo, ok bool)
+
+var (
+	lookupMu sync.RWMutex
+	lookup   PoolInfoFunc
+)
+
+// SetPoolInfo registers the pool source (the Balancer API pool metadata
+// cache). Without one, Lookup reports every pool unknown.
+func SetPoolInfo(fn PoolInfoFunc) {
+	lookupMu.Lock()
+	defer lookupMu.Unlock()
+	lookup = fn
+}
+
+// Lookup returns the registered source's info for a pool.
+func Lookup(network, pool string) (PoolInfo, bool) {
+	lookupMu.RLock()
+	fn := lookup
+	lookupMu.RUnlock()
+	if fn == nil {
+		return PoolInfo{}, false
+	}
+	return fn(network, pool)
+}
+
+const (
+	// stableNotionalUSD sizes swaps through stable pools, which are deep
+	// around the peg.
+	stableNotionalUSD = 10_000
+	// defaultNotionalUSD sizes swaps through every other pool type.
+	defaultNotionalUSD = 1_000
+	// suggestTVLShare caps a suggestion at this share of the pool's TVL.
+	suggestTVLShare = 0.01
+
+	// MinNotionalUSD is the smallest swap Check accepts; below it gas and
+	// rounding dominate the quote.
+	MinNotionalUSD = 1
+	// MaxNotionalUSD is the largest swap Check accepts in any pool.
+	MaxNotionalUSD = 1_000_000
+	// MaxTVLShare is the largest share of the pool's TVL Check accepts.
+	MaxTVLShare = 0.25
+)
+
+// NotionalUSD returns the suggested swap size for a pool type.
+func NotionalUSD(poolType string) float64 {
+	switch strings.ToUpper(poolType) {
+	case "STABLE", "COMPOSABLE_STABLE", "META_STABLE", "GYROE":
+		return stableNotionalUSD
+	default:
+		return defaultNotionalUSD
+	}
+}
+
+// Suggest returns a SwapAmount in tokenIn base units worth NotionalUSD for
+// the pool type, capped at 1% of TVL and rounded to two significant figures
+// (e.g. 10000 USDC rather than 10002.04).
+func Suggest(info PoolInfo, tokenIn string, decimals int) (string, error) {
+	price, ok := info.PricesUSD[config.NormalizeAddress(tokenIn)]
+	if !ok || price <= 0 {
+		return "", fmt.Errorf("no USD price for token_in")
+	}
+	usd := NotionalUSD(info.Type)
+	if info.TVLUSD > 0 && usd > info.TVLUSD*suggestTVLShare {
+		usd = info.TVLUSD * suggestTVLShare
+	}
+	if usd < MinNotionalUSD {
+		return "", fmt.Errorf("pool TVL $%.0f is too small to size a swap", info.TVLUSD)
+	}
+	human := strconv.FormatFloat(usd/price, 'g', 2, 64)
+
+	// Scale exactly so 3.3e-04 WETH is 330000000000000 wei, not ...999.
+	amount, ok := new(big.Rat).SetString(human)
+	if !ok {
+		return "", fmt.Errorf("amount %s out of range", human)
+	}
+	amount.Mul(amount, new(big.Rat).SetInt(pow10(decimals)))
+	raw := new(big.Int).Quo(amount.Num(), amount.Denom())
+	if raw.Sign() <= 0 {
+		return "", fmt.Errorf("amount rounds to zero at %d decimals", decimals)
+	}
+	return raw.String(), nil
+}
+
+// Check rejects a SwapAmount whose notional is dust (under MinNotionalUSD)
+// or absurdly large (over MaxNotionalUSD or MaxTVLShare of the pool). It
+// passes when the token_in price is unknown.
+func Check(info PoolInfo, tokenIn string, decimals int, amountRaw string) error {
+	price, ok := info.PricesUSD[config.NormalizeAddress(tokenIn)]
+	if !ok || price <= 0 {
+		return nil
+	}
+	raw, ok := new(big.Float).SetString(amountRaw)
+	if !ok {
+		return fmt.Errorf("swap_amount %q is not a number", amountRaw)
+	}
+	human, _ := new(big.Float).Quo(raw, new(big.Float).SetInt(pow10(decimals))).Float64()
+	usd := human * price
+
+	switch {
+	case usd < MinNotionalUSD:
+		return fmt.Errorf("swap_amount is dust: $%.4f notional", usd)
+	case usd > MaxNotionalUSD:
+		return fmt.Errorf("swap_amount is $%.0f notional, over the $%d limit", usd, MaxNotionalUSD)
+	case info.TVLUSD > 0 && usd > info.TVLUSD*MaxTVLShare:
+		return fmt.Errorf("swap_amount is $%.0f notional, over %.0f%% of the pool's $%.0f TVL", usd, MaxTVLShare*100, info.TVLUSD)
+	}
+	return nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,54 @@
+package swapsize
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	usdc = "0xaf88d065e77c8cC2239327C5EDb3A432268e5831"
+	weth = "0x82aF49447D8a07e3bd95BD0d56f35241523fBab1"
+)
+
+func TestSuggest(t *testing.T) {
+	prices := map[string]float64{strings.ToLower(usdc): 0.9998, strings.ToLower(weth): 3000}
+	cases := []struct {
+		name    string
+		info    PoolInfo
+		token   string
+		dec     int
+		want    string
+		wantErr bool
+	}{
+		{"stable pool, $10k of USDC", PoolInfo{Type: "STABLE", PricesUSD: prices}, usdc, 6, "10000000000", false},
+		{"weighted pool, $1k of WETH", PoolInfo{Type: "WEIGHTED", PricesUSD: prices}, weth, 18, "330000000000000000", false},
+		{"capped at 1% of TVL", PoolInfo{Type: "STABLE", TVLUSD: 50_000, PricesUSD: prices}, usdc, 6, "500000000", false},
+		{"pool too small", PoolInfo{Type: "STABLE", TVLUSD: 50, PricesUSD: prices}, usdc, 6, "", true},
+		{"unknown price", PoolInfo{Type: "STABLE"}, usdc, 6, "", true},
+	}
+	for _, c := range cases {
+		got, err := Suggest(c.info, c.token, c.dec)
+		if got != c.want || (err != nil) != c.wantErr {
+			t.Errorf("%s: Suggest = %q, %v; want %q", c.name, got, err, c.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	info := PoolInfo{Type: "STABLE", TVLUSD: 2_000_000, PricesUSD: map[string]float64{strings.ToLower(usdc): 1}}
+	cases := map[string]string{
+		"10000000000":   "",            // $10k
+		"100":           "dust",        // $0.0001
+		"2000000000000": "over the",    // $2M
+		"600000000000":  "of the pool", // $600k of a $2M pool
+	}
+	for amount, want := range cases {
+		err := Check(info, usdc, 6, amount)
+		if want == "" && err != nil || want != "" && (err == nil || !strings.Contains(err.Error(), want)) {
+			t.Errorf("Check(%s) = %v, want %q", amount, err, want)
+		}
+	}
+	if err := Check(info, weth, 18, "1"); err != nil {
+		t.Errorf("Check with unknown price = %v, want nil", err)
+	}
+}
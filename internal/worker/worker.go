@@ -0,0 +1,178 @@
+// Package worker lets monitor instances in other regions run the
+// BaseEndpoints checks and report results to a central instance, which shows
+// them per region to surface geo-blocking or region-specific routing.
+package worker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// ResultsPath is the central instance's endpoint workers report to.
+const ResultsPath = "/internal/v1/results"
+
+// Result is one endpoint's check result as reported by a worker.
+type Result struct {
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	Message      string    `json:"message"`
+	ReturnAmount string    `json:"returnAmount"`
+	CheckedAt    time.Time `json:"checkedAt"`
+}
+
+// Report is a worker's results for one check cycle.
+type Report struct {
+	Region  string   `json:"region"`
+	Results []Result `json:"results"`
+}
+
+// Client posts reports to the central instance.
+type Client struct {
+	url    string
+	token  string
+	region string
+	http   *http.Client
+}
+
+// NewClient returns a client reporting as region to the instance at
+// collectorURL (e.g. http://go-monitoring.internal:8080), authenticated with
+// token.
+func NewClient(collectorURL, token, region string) *Client {
+	return &Client{
+		url:    collectorURL + ResultsPath,
+		token:  token,
+		region: region,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Send posts one report.
+func (c *Client) Send(rep Report) error {
+	body, err := json.Marshal(rep)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// ReportCycle sends the results of a completed cycle. Failures are logged;
+// the next cycle reports again.
+func (c *Client) ReportCycle(eps []collector.Endpoint) {
+	rep := Report{Region: c.region, Results: make([]Result, len(eps))}
+	for i, e := range eps {
+		rep.Results[i] = Result{
+			Name:         e.Name,
+			Status:       e.LastStatus,
+			Message:      e.Message,
+			ReturnAmount: e.ReturnAmount,
+			CheckedAt:    e.LastChecked,
+		}
+	}
+	if err := c.Send(rep); err != nil {
+		logs.Printf("%s[WORKER]%s report to %s failed: %v\n", config.ColorRed, config.ColorReset, c.url, err)
+		return
+	}
+	logs.Printf("%s[WORKER]%s reported %d results as %s\n", config.ColorGreen, config.ColorReset, len(eps), c.region)
+}
+
+// divergedReports is how many reports in a row a region must disagree with
+// the local result before Accept alerts, so a cycle that ran either side of
+// a status change doesn't.
+const divergedReports = 2
+
+var (
+	divergedMu sync.Mutex
+	// diverged counts the consecutive diverging reports per endpoint and
+	// region ("name\x00region").
+	diverged = map[string]int{}
+)
+
+// Accept records a worker's report on the central instance and logs
+// endpoints where the region disagrees with the local result on whether the
+// endpoint is down. A disagreement that lasts divergedReports reports is
+// alerted as a warning, and its end as info; workers send no alerts of their
+// own. Results for endpoints not configured here are skipped. Returns the
+// number recorded.
+func Accept(rep Report) int {
+	accepted := 0
+	for _, r := range rep.Results {
+		local := collector.GetEndpointByName(r.Name)
+		if local == nil {
+			continue
+		}
+		collector.RecordRegionResult(r.Name, collector.RegionResult{
+			Region:       rep.Region,
+			Status:       r.Status,
+			Message:      r.Message,
+			ReturnAmount: r.ReturnAmount,
+			CheckedAt:    r.CheckedAt,
+		})
+		accepted++
+		diverges := Diverges(local.LastStatus, r.Status)
+		if diverges {
+			logs.Printf("%s[REGION]%s %s: %s reports %s (%s), local status %s\n",
+				config.ColorYellow, config.ColorReset, r.Name, rep.Region, r.Status, r.Message, local.LastStatus)
+		}
+		alertDivergence(rep.Region, r, local.LastStatus, diverges)
+	}
+	return accepted
+}
+
+// alertDivergence tracks one result's run of diverging reports and alerts
+// when it reaches divergedReports or ends after having done so.
+func alertDivergence(region string, r Result, local string, diverges bool) {
+	key := r.Name + "\x00" + region
+	divergedMu.Lock()
+	n := diverged[key]
+	if diverges {
+		diverged[key] = n + 1
+	} else {
+		delete(diverged, key)
+	}
+	divergedMu.Unlock()
+
+	switch {
+	case diverges && n+1 == divergedReports:
+		notify.Send(notify.SeverityWarning, fmt.Sprintf("[%s] region %s reports %s (%s) while this instance reports %s",
+			r.Name, region, r.Status, r.Message, local))
+	case !diverges && n >= divergedReports:
+		notify.Send(notify.SeverityInfo, fmt.Sprintf("[%s] region %s agrees again: %s", r.Name, region, r.Status))
+	}
+}
+
+// Diverges reports whether a regional status disagrees with the local one
+// about the endpoint being down. Statuses that are not results (unknown,
+// info, unsupported, not-applicable) never diverge.
+func Diverges(local, regional string) bool {
+	if !isResult(local) || !isResult(regional) {
+		return false
+	}
+	return collector.IsDownStatus(local) != collector.IsDownStatus(regional)
+}
+
+func isResult(status string) bool {
+	return status == "up" || collector.IsDownStatus(status)
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestDiverges(t *testing.T) {
+	cases := []struct {
+		local, regional string
+		want            bool
+	}{
+		{"up", "down", true},
+		{"error", "up", true},
+		{"down", "error", false},
+		{"up", "up", false},
+		{"unknown", "down", false},
+		{"up", "info", false},
+	}
+	for _, c := range cases {
+		if got := Diverges(c.local, c.regional); got != c.want {
+			t.Errorf("Diverges(%q, %q) = %v, want %v", c.local, c.regional, got, c.want)
+		}
+	}
+}
+
+func TestAcceptAlertsOnLastingDivergence(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg struct{ Text string }
+		json.NewDecoder(r.Body).Decode(&msg)
+		got = append(got, msg.Text)
+	}))
+	defer srv.Close()
+	t.Setenv("SLACK_WEBHOOK_URL", srv.URL)
+	collector.SetEndpoints([]collector.Endpoint{{Name: "Odos-X", LastStatus: "up"}})
+	t.Cleanup(func() { collector.SetEndpoints(nil) })
+
+	down := Report{Region: "sin", Results: []Result{{Name: "Odos-X", Status: "down", Message: "403 Forbidden"}}}
+	Accept(down)
+	if len(got) != 0 {
+		t.Fatalf("alerted on the first diverging report: %q", got)
+	}
+	Accept(down)
+	Accept(down)
+	if len(got) != 1 || !strings.HasPrefix(got[0], "[WARNING] [Odos-X] region sin reports down") {
+		t.Fatalf("Slack messages = %q, want one warning", got)
+	}
+	Accept(Report{Region: "sin", Results: []Result{{Name: "Odos-X", Status: "up"}}})
+	if len(got) != 2 || !strings.HasPrefix(got[1], "[INFO] [Odos-X] region sin agrees again") {
+		t.Fatalf("Slack messages = %q, want the recovery", got)
+	}
+}
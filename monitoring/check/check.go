@@ -0,0 +1,71 @@
+// Package check holds the types a provider check is built from and
+// returns: the request options, the raw response, the interfaces a provider
+// implements (URL and request body builders, response handler), the errors
+// builders return and the Result of a check. They live outside internal/ so tools embedding package
+// providers can implement and register their own providers.
+package check
+
+import (
+	"net/http"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+// RequestOptions contains configuration for API requests
+type RequestOptions struct {
+	IsBalancerSourceOnly bool
+	CustomHeaders        map[string]string
+	// ExcludeSources are source IDs the quote must not route through, for
+	// the exclusion check. Only builders of providers registered with
+	// ExclusionCheck send them.
+	ExcludeSources []string
+	// FallbackURLs are alternate URLs a POST is resent to, in order, when
+	// the built URL fails; see the client's Send.
+	FallbackURLs []string
+	// Batch, when set, may answer a POST from a request already sent for
+	// several endpoints together; see Batcher.
+	Batch Batcher
+}
+
+// Batcher answers requests from responses fetched in batches: a provider
+// that takes several queries in one request fetches a request's answer
+// together with those of the requests expected next.
+type Batcher interface {
+	// Response returns the response to a POST of body to url, or false when
+	// the request isn't batched and must be sent alone.
+	Response(url string, body []byte) (*APIResponse, bool)
+}
+
+// APIResponse represents a generic API response
+type APIResponse struct {
+	StatusCode int
+	Body       []byte
+	Headers    http.Header
+	// Duration is the time from sending the request to reading the whole
+	// body, excluding any rate-limit wait. Phases splits it.
+	Duration time.Duration
+	Phases   collector.LatencyPhases
+}
+
+// ResponseHandler defines how to process API responses
+type ResponseHandler interface {
+	HandleResponse(response *APIResponse, endpoint *collector.Endpoint) error
+	HandleResponseForMarketPrice(response *APIResponse, endpoint *collector.Endpoint) error
+	GetIgnoreList(network string) (string, error)
+}
+
+// CustomResponseHandler allows for custom response handling without ignore list
+type CustomResponseHandler interface {
+	HandleResponse(response *APIResponse, endpoint *collector.Endpoint) error
+}
+
+// URLBuilder defines how to build URLs for different providers
+type URLBuilder interface {
+	BuildURL(endpoint *collector.Endpoint, options RequestOptions) (string, error)
+}
+
+// RequestBodyBuilder defines how to build JSON request bodies for POST requests
+type RequestBodyBuilder interface {
+	BuildRequestBody(endpoint *collector.Endpoint, options RequestOptions) ([]byte, error)
+}
@@ -0,0 +1,13 @@
+package check
+
+import "errors"
+
+// ErrBuildURLUnsupported is returned by URL builders when the requested
+// mode cannot be satisfied (for example Balancer-only routing where the
+// provider exposes no matching venues for that chain).
+var ErrBuildURLUnsupported = errors.New("unsupported")
+
+// ErrNetworkNotApplicable is returned by URL and request body builders when
+// the solver doesn't serve the endpoint's network at all (e.g. Barter on
+// Avalanche). The endpoint is marked collector.StatusNotApplicable.
+var ErrNetworkNotApplicable = errors.New("solver does not support network")
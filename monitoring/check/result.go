@@ -0,0 +1,64 @@
+package check
+
+import (
+	"time"
+
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// Result is the outcome of one check, returned so callers (the manual check
+// API, CLIs, tests) can use it without reading the endpoint back. The
+// check still writes the same state to the endpoint, which the monitor
+// persists and the dashboard renders.
+type Result struct {
+	Endpoint  string // Endpoint.Name
+	CheckedAt time.Time
+	Status    string // a collector.IsKnownStatus value
+	Message   string
+	// ErrorClass is the alert hints class of a failure (notify.MatchHint),
+	// e.g. "rate_limited" or "wrong_source"; empty when the check didn't
+	// fail or no class matches.
+	ErrorClass   string
+	ReturnAmount string // quote in token_out base units; empty when none
+	MarketPrice  string // all-sources quote, same units; empty when none
+	// HTTPStatus, RequestID, Latency and Phases describe the Balancer-only
+	// response; zero when none arrived.
+	HTTPStatus int
+	RequestID  string
+	Latency    time.Duration
+	Phases     collector.LatencyPhases
+	// Body is the Balancer-only response body as received, shared with the
+	// handler that parsed it: read it, don't modify it. Nil when none
+	// arrived.
+	Body []byte
+}
+
+// Failed reports whether the check found an outage (collector.IsDownStatus).
+func (r Result) Failed() bool {
+	return collector.IsDownStatus(r.Status)
+}
+
+// ResultOf reads the result of the check just written to endpoint; body is
+// the response body it parsed, nil when none arrived.
+func ResultOf(endpoint *collector.Endpoint, body []byte) Result {
+	r := Result{
+		Endpoint:     endpoint.Name,
+		CheckedAt:    endpoint.LastChecked,
+		Status:       endpoint.LastStatus,
+		Message:      endpoint.Message,
+		ReturnAmount: endpoint.ReturnAmount,
+		MarketPrice:  endpoint.MarketPrice,
+		HTTPStatus:   endpoint.HTTPStatus,
+		RequestID:    endpoint.RequestID(),
+		Latency:      endpoint.ResponseTime,
+		Phases:       endpoint.ResponsePhases,
+		Body:         body,
+	}
+	if r.Failed() {
+		if h, ok := notify.MatchHint(notify.Hints(), r.Message, string(body)); ok {
+			r.ErrorClass = h.Class
+		}
+	}
+	return r
+}
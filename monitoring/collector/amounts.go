@@ -0,0 +1,156 @@
+package collector
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Amount is a quote amount in token base units: the string the provider
+// reported, kept verbatim, and its value parsed once when the result was
+// stored. The zero Amount is "no amount".
+type Amount struct {
+	Raw   string   // as reported; empty when none
+	value *big.Rat // nil unless Raw is a number
+}
+
+// ParseAmount parses a reported amount. Integers, decimals and exponent
+// forms parse; empty, "N/A" and malformed amounts keep Raw but are not Valid.
+func ParseAmount(raw string) Amount {
+	a := Amount{Raw: raw}
+	if s := strings.TrimSpace(raw); s != "" && s != "N/A" {
+		if v, ok := new(big.Rat).SetString(s); ok {
+			a.value = v
+		}
+	}
+	return a
+}
+
+// Valid reports whether the amount parsed as a number.
+func (a Amount) Valid() bool {
+	return a.value != nil
+}
+
+// Positive reports whether the amount is a number above zero.
+func (a Amount) Positive() bool {
+	return a.value != nil && a.value.Sign() > 0
+}
+
+// Cmp compares a and b numerically (-1, 0, +1), an invalid amount counting
+// as zero, so sorting and highlighting stay well-defined.
+func (a Amount) Cmp(b Amount) int {
+	return a.rat().Cmp(b.rat())
+}
+
+func (a Amount) rat() *big.Rat {
+	if a.value == nil {
+		return new(big.Rat)
+	}
+	return a.value
+}
+
+// Float64 returns the amount as the nearest float64; 0 when invalid.
+func (a Amount) Float64() float64 {
+	f, _ := a.rat().Float64()
+	return f
+}
+
+// SpreadBps returns how far market exceeds a, in basis points of market:
+// positive when market is the better quote. ok is false unless both are
+// positive.
+func (a Amount) SpreadBps(market Amount) (bps float64, ok bool) {
+	if !a.Positive() || !market.Positive() {
+		return 0, false
+	}
+	spread := new(big.Rat).Sub(market.value, a.value)
+	spread.Quo(spread, market.value).Mul(spread, big.NewRat(10000, 1))
+	bps, _ = spread.Float64()
+	return bps, true
+}
+
+// DeviationPct returns |a - ref| as a percentage of ref. ok is false unless
+// both are positive.
+func (a Amount) DeviationPct(ref Amount) (pct float64, ok bool) {
+	if !a.Positive() || !ref.Positive() {
+		return 0, false
+	}
+	diff := new(big.Rat).Sub(a.value, ref.value)
+	diff.Abs(diff).Quo(diff, ref.value).Mul(diff, big.NewRat(100, 1))
+	pct, _ = diff.Float64()
+	return pct, true
+}
+
+// String returns Raw, or "N/A" when there is no amount.
+func (a Amount) String() string {
+	if a.Raw == "" {
+		return "N/A"
+	}
+	return a.Raw
+}
+
+// Format returns the amount in whole tokens of the given decimals, with up
+// to six fractional digits and trailing zeros dropped (e.g. "1234.5"); "N/A"
+// when invalid.
+func (a Amount) Format(decimals int) string {
+	if a.value == nil {
+		return "N/A"
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(decimals, 0))), nil)
+	s := new(big.Rat).Quo(a.value, new(big.Rat).SetInt(scale)).FloatString(6)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Amounts is an endpoint's quotes parsed once per change: the store
+// refreshes them on every write, and Endpoint.Amounts recomputes them for
+// rows built elsewhere.
+type Amounts struct {
+	Return  Amount // ReturnAmount
+	Market  Amount // MarketPrice
+	OnChain Amount // OnChainPrice
+	// Price is what Return is compared against on the dashboard: OnChain for
+	// balancer_sor rows whose on-chain query succeeded, else Market.
+	Price Amount
+	from  amountsSource
+}
+
+// amountsSource is what Amounts is computed from, to tell a stale one.
+type amountsSource struct {
+	routeSolver, returnAmount, marketPrice, onChainPrice, onChainQueryError string
+}
+
+func (e *Endpoint) amountsSource() amountsSource {
+	return amountsSource{e.RouteSolver, e.ReturnAmount, e.MarketPrice, e.OnChainPrice, e.OnChainQueryError}
+}
+
+// Amounts returns e's parsed quotes, from the copy the store keeps current
+// when e came from it.
+func (e *Endpoint) Amounts() Amounts {
+	src := e.amountsSource()
+	if e.amountsSet && e.amounts.from == src {
+		return e.amounts
+	}
+	a := Amounts{
+		Return:  ParseAmount(e.ReturnAmount),
+		Market:  ParseAmount(e.MarketPrice),
+		OnChain: ParseAmount(e.OnChainPrice),
+		from:    src,
+	}
+	a.Price = a.Market
+	if e.RouteSolver == "balancer_sor" && e.OnChainPrice != "" && e.OnChainQueryError == "" {
+		a.Price = a.OnChain
+	}
+	return a
+}
+
+// SpreadBps returns the latest quote spread: how far MarketPrice beats
+// ReturnAmount, see Amount.SpreadBps.
+func (e *Endpoint) SpreadBps() (float64, bool) {
+	a := e.Amounts()
+	return a.Return.SpreadBps(a.Market)
+}
+
+// refreshAmounts stores e's Amounts on e; the store calls it after every
+// write so copies handed to readers carry them.
+func (e *Endpoint) refreshAmounts() {
+	e.amounts, e.amountsSet = e.Amounts(), true
+}
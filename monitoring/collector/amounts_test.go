@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	for _, tt := range []struct {
+		raw   string
+		valid bool
+		str   string
+	}{
+		{"", false, "N/A"},
+		{"N/A", false, "N/A"},
+		{"abc", false, "abc"},
+		{"0042", true, "0042"},
+		{"1.5", true, "1.5"},
+		{"1e21", true, "1e21"},
+	} {
+		a := ParseAmount(tt.raw)
+		if a.Valid() != tt.valid || a.String() != tt.str {
+			t.Errorf("ParseAmount(%q) = valid %v, %q; want %v, %q", tt.raw, a.Valid(), a.String(), tt.valid, tt.str)
+		}
+	}
+}
+
+func TestAmountCmpOrdersNumerically(t *testing.T) {
+	raws := []string{"999", "1000000000000000000000001", "N/A", "1000000000000000000000000", "01000"}
+	amounts := make([]Amount, len(raws))
+	for i, r := range raws {
+		amounts[i] = ParseAmount(r)
+	}
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i].Cmp(amounts[j]) < 0 })
+	want := []string{"N/A", "999", "01000", "1000000000000000000000000", "1000000000000000000000001"}
+	for i := range want {
+		if amounts[i].String() != want[i] {
+			t.Fatalf("sorted = %v, want %v", amounts, want)
+		}
+	}
+}
+
+func TestAmountSpreadAndDeviation(t *testing.T) {
+	quote, market := ParseAmount("990"), ParseAmount("1000")
+	if bps, ok := quote.SpreadBps(market); !ok || bps != 100 {
+		t.Fatalf("SpreadBps = %v, %v; want 100", bps, ok)
+	}
+	if pct, ok := quote.DeviationPct(market); !ok || pct != 1 {
+		t.Fatalf("DeviationPct = %v, %v; want 1", pct, ok)
+	}
+	if _, ok := quote.SpreadBps(ParseAmount("0")); ok {
+		t.Fatal("a zero market price has no spread")
+	}
+}
+
+func TestAmountFormat(t *testing.T) {
+	for _, tt := range []struct {
+		raw      string
+		decimals int
+		want     string
+	}{
+		{"1234500000000000000000", 18, "1234.5"},
+		{"1000000", 6, "1"},
+		{"1", 18, "0"},
+		{"42", 0, "42"},
+		{"", 18, "N/A"},
+	} {
+		if got := ParseAmount(tt.raw).Format(tt.decimals); got != tt.want {
+			t.Errorf("Format(%q, %d) = %q, want %q", tt.raw, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointAmountsPrice(t *testing.T) {
+	e := Endpoint{RouteSolver: "balancer_sor", ReturnAmount: "1010", MarketPrice: "5", OnChainPrice: "1000"}
+	if a := e.Amounts(); a.Price.Raw != "1000" {
+		t.Fatalf("balancer_sor should compare against the on-chain price, got %+v", a.Price)
+	}
+	e.OnChainQueryError = "reverted"
+	if a := e.Amounts(); a.Price.Raw != "5" {
+		t.Fatalf("failed on-chain query should compare against the market price, got %+v", a.Price)
+	}
+}
+
+func TestStoreRefreshesAmountsOnWrite(t *testing.T) {
+	SetEndpoints([]Endpoint{{Name: "A", ReturnAmount: "10"}})
+	t.Cleanup(func() { SetEndpoints(nil) })
+	gen := Generation()
+
+	UpdateEndpointByName("A", func(e *Endpoint) { e.ReturnAmount = "20" })
+	if Generation() == gen {
+		t.Fatal("a write should advance Generation")
+	}
+	e := GetEndpointsCopy()[0]
+	if !e.amountsSet || e.amounts.Return.Raw != "20" {
+		t.Fatalf("stored amounts = %+v, want Return 20", e.amounts)
+	}
+}
+
+func TestRecordCheckParsesAmounts(t *testing.T) {
+	t.Cleanup(func() { SetHistory("amounts-test", nil) })
+	RecordCheck("amounts-test", CheckRecord{Status: "up", ReturnAmount: "990", MarketPrice: "1000"})
+	recs := GetHistory("amounts-test")
+	if !recs[0].quote.Valid() || !recs[0].market.Valid() {
+		t.Fatalf("stored record amounts not parsed: %+v", recs[0])
+	}
+	if bps, ok := recs[0].SpreadBps(); !ok || bps != 100 {
+		t.Fatalf("SpreadBps = %v, %v; want 100", bps, ok)
+	}
+}
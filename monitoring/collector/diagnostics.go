@@ -0,0 +1,68 @@
+package collector
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// maxHeaderValue bounds a kept header value; trace headers can be long.
+const maxHeaderValue = 200
+
+// requestIDHeaders are the name fragments of request / trace ID headers,
+// most specific first: what provider support teams ask for.
+var requestIDHeaders = []string{"request-id", "requestid", "correlation-id", "trace-id", "traceid", "cf-ray", "x-amz-cf-id"}
+
+// isDiagnosticHeader reports whether a response header is worth keeping on
+// the endpoint: rate-limit state and request / trace IDs.
+func isDiagnosticHeader(name string) bool {
+	lower := strings.ToLower(name)
+	if strings.Contains(lower, "ratelimit") || strings.Contains(lower, "rate-limit") || lower == "retry-after" {
+		return true
+	}
+	for _, id := range requestIDHeaders {
+		if strings.Contains(lower, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// DiagnosticHeaders picks the rate-limit and request ID headers out of a
+// provider response, keyed by canonical header name; nil when there are none.
+func DiagnosticHeaders(h http.Header) map[string]string {
+	var out map[string]string
+	for name, values := range h {
+		if !isDiagnosticHeader(name) || len(values) == 0 {
+			continue
+		}
+		v := strings.Join(values, ", ")
+		if len(v) > maxHeaderValue {
+			v = v[:maxHeaderValue] + "…"
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[http.CanonicalHeaderKey(name)] = v
+	}
+	return out
+}
+
+// RequestID returns the provider's request ID from the latest response's
+// headers (e.g. X-Request-Id, falling back to trace IDs and Cf-Ray), empty
+// when it sent none.
+func (e *Endpoint) RequestID() string {
+	names := make([]string, 0, len(e.HTTPHeaders))
+	for name := range e.HTTPHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, id := range requestIDHeaders {
+		for _, name := range names {
+			if strings.Contains(strings.ToLower(name), id) {
+				return e.HTTPHeaders[name]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package collector
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Request-Id", "req-1")
+	h.Set("X-RateLimit-Remaining", "0")
+	h.Set("Retry-After", "30")
+	h.Set("Cf-Ray", "8a1b-AMS")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Amzn-Trace-Id", strings.Repeat("x", 300))
+	got := DiagnosticHeaders(h)
+	if len(got) != 5 || got["X-Request-Id"] != "req-1" || got["X-Ratelimit-Remaining"] != "0" || got["Retry-After"] != "30" {
+		t.Fatalf("headers = %v", got)
+	}
+	if v := got["X-Amzn-Trace-Id"]; len(v) > maxHeaderValue+len("…") {
+		t.Errorf("trace header kept %d bytes", len(v))
+	}
+	if DiagnosticHeaders(http.Header{"Content-Type": {"text/html"}}) != nil {
+		t.Error("headers without diagnostics should give nil")
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	e := Endpoint{HTTPHeaders: map[string]string{"Cf-Ray": "ray", "X-Trace-Id": "trace", "X-Request-Id": "req"}}
+	if got := e.RequestID(); got != "req" {
+		t.Errorf("RequestID = %q, want req", got)
+	}
+	delete(e.HTTPHeaders, "X-Request-Id")
+	if got := e.RequestID(); got != "trace" {
+		t.Errorf("without a request ID = %q, want trace", got)
+	}
+	if got := (&Endpoint{}).RequestID(); got != "" {
+		t.Errorf("no headers = %q", got)
+	}
+}
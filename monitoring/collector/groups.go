@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+)
+
+// Group statuses: every checked solver row of a BaseName up, some failing, or
+// all failing. Rows that are not up or down (unsupported, not-applicable,
+// info, unknown) don't count; a group with none of either is GroupUnknown.
+const (
+	GroupAllUp   = "all-up"
+	GroupPartial = "partial"
+	GroupAllDown = "all-down"
+	GroupUnknown = "unknown"
+)
+
+// GroupStatus is the composite status of the solver rows sharing a BaseName.
+type GroupStatus struct {
+	BaseName string
+	Network  string
+	Status   string
+	Up       int // rows with status "up"
+	Failing  int // rows with a down status (IsDownStatus)
+	Total    int // Up + Failing
+	Updated  time.Time
+}
+
+// ComputeGroupStatus summarises the rows of eps whose BaseName is baseName.
+func ComputeGroupStatus(baseName string, eps []Endpoint, now time.Time) GroupStatus {
+	g := GroupStatus{BaseName: baseName, Updated: now}
+	for _, e := range eps {
+		if e.BaseName != baseName {
+			continue
+		}
+		g.Network = e.Network
+		switch {
+		case e.LastStatus == "up":
+			g.Up++
+		case IsDownStatus(e.LastStatus):
+			g.Failing++
+		}
+	}
+	g.Total = g.Up + g.Failing
+	switch {
+	case g.Total == 0:
+		g.Status = GroupUnknown
+	case g.Failing == 0:
+		g.Status = GroupAllUp
+	case g.Up == 0:
+		g.Status = GroupAllDown
+	default:
+		g.Status = GroupPartial
+	}
+	return g
+}
+
+// Summary renders the group for a notification, e.g.
+// "Base-Stable(GHO/USDC) on base: 3 of 6 solvers failing".
+func (g GroupStatus) Summary() string {
+	return fmt.Sprintf("%s on %s: %d of %d solvers failing", g.BaseName, config.NetworkName(g.Network), g.Failing, g.Total)
+}
+
+// DashboardPath returns a dashboard deep link narrowed to the group's rows.
+func (g GroupStatus) DashboardPath() string {
+	v := url.Values{}
+	v.Set("network", config.NetworkName(g.Network))
+	v.Set("q", g.BaseName)
+	v.Set("range", "24h")
+	return "/?" + v.Encode()
+}
+
+var (
+	groupsMu sync.Mutex
+	groups   = map[string]GroupStatus{}
+)
+
+// SetGroupStatus stores g as the latest status of its BaseName.
+func SetGroupStatus(g GroupStatus) {
+	groupsMu.Lock()
+	defer groupsMu.Unlock()
+	defer generation.Add(1)
+	groups[g.BaseName] = g
+}
+
+// GetGroupStatus returns the latest stored status of baseName.
+func GetGroupStatus(baseName string) (GroupStatus, bool) {
+	groupsMu.Lock()
+	defer groupsMu.Unlock()
+	g, ok := groups[baseName]
+	return g, ok
+}
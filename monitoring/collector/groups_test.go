@@ -0,0 +1,36 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeGroupStatus(t *testing.T) {
+	row := func(base, status string) Endpoint {
+		return Endpoint{BaseName: base, Network: "1", LastStatus: status}
+	}
+	for _, tc := range []struct {
+		statuses           []string
+		want               string
+		up, failing, total int
+	}{
+		{[]string{"up", "up", StatusNotApplicable}, GroupAllUp, 2, 0, 2},
+		{[]string{"up", "down", "error", "unsupported"}, GroupPartial, 1, 2, 3},
+		{[]string{"down", "panic"}, GroupAllDown, 0, 2, 2},
+		{[]string{"unknown"}, GroupUnknown, 0, 0, 0},
+	} {
+		eps := []Endpoint{row("other", "down")}
+		for _, s := range tc.statuses {
+			eps = append(eps, row("GHO/USDC", s))
+		}
+		g := ComputeGroupStatus("GHO/USDC", eps, time.Now())
+		if g.Status != tc.want || g.Up != tc.up || g.Failing != tc.failing || g.Total != tc.total {
+			t.Errorf("%v: got %+v, want %s %d/%d/%d", tc.statuses, g, tc.want, tc.up, tc.failing, tc.total)
+		}
+	}
+
+	g := ComputeGroupStatus("GHO/USDC", []Endpoint{row("GHO/USDC", "up"), row("GHO/USDC", "down")}, time.Now())
+	if got, want := g.Summary(), "GHO/USDC on ethereum: 1 of 2 solvers failing"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+}
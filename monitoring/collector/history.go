@@ -0,0 +1,234 @@
+package collector
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// CheckRecord is one completed check kept in the in-memory history.
+type CheckRecord struct {
+	At           time.Time
+	Status       string
+	Message      string
+	ReturnAmount string        // quote in token_out base units; empty when none
+	MarketPrice  string        // all-sources quote, same units; empty when none
+	HTTPStatus   int           // the Balancer-only response's status; 0 when none arrived
+	RequestID    string        // the provider's request ID header, if it sent one
+	ResponseTime time.Duration // the Balancer-only response's; 0 when none arrived
+	// ResponsePhases splits ResponseTime; zero when none arrived.
+	ResponsePhases LatencyPhases
+	// Surge is the StableSurge pool's fee state at the check; zero for other
+	// pools.
+	Surge SurgeState
+	// ReturnAmount and MarketPrice parsed when the record was stored; read
+	// with Quote and Market.
+	quote, market Amount
+}
+
+// parseAmounts stores the record's parsed amounts on it.
+func (r *CheckRecord) parseAmounts() {
+	r.quote, r.market = ParseAmount(r.ReturnAmount), ParseAmount(r.MarketPrice)
+}
+
+// Quote returns ReturnAmount parsed.
+func (r CheckRecord) Quote() Amount {
+	if r.quote.Raw != r.ReturnAmount {
+		return ParseAmount(r.ReturnAmount)
+	}
+	return r.quote
+}
+
+// Market returns MarketPrice parsed.
+func (r CheckRecord) Market() Amount {
+	if r.market.Raw != r.MarketPrice {
+		return ParseAmount(r.MarketPrice)
+	}
+	return r.market
+}
+
+// SpreadBps returns the record's quote spread; see QuoteSpreadBps.
+func (r CheckRecord) SpreadBps() (float64, bool) {
+	return r.Quote().SpreadBps(r.Market())
+}
+
+// QuoteSpreadBps returns how far the all-sources market price exceeds the
+// Balancer-only quote, in basis points of the market price: positive when
+// other sources beat the pool, negative when the pool beats them. ok is false
+// unless both amounts are positive numbers.
+func QuoteSpreadBps(returnAmount, marketPrice string) (float64, bool) {
+	return ParseAmount(returnAmount).SpreadBps(ParseAmount(marketPrice))
+}
+
+// historyWindow bounds the per-endpoint history by age: records a week or
+// more older than the newest are dropped, however often the row is checked
+// (CHECK_INTERVAL_HOURS or its group's Schedule).
+const historyWindow = 7 * 24 * time.Hour
+
+// historyCapacity caps the records kept within historyWindow: one a minute,
+// the finest Schedule, so only a runaway caller ever reaches it.
+const historyCapacity = 7 * 24 * 60
+
+var (
+	history   = map[string][]CheckRecord{}
+	historyMu sync.Mutex
+)
+
+// RecordCheck appends a check result to the named endpoint's history,
+// dropping records historyWindow or more older than it (trimHistory). Keyed
+// by Endpoint.Name so base and discovered rows share one history store.
+func RecordCheck(name string, rec CheckRecord) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	defer generation.Add(1)
+
+	rec.parseAmounts()
+	history[name] = trimHistory(append(history[name], rec))
+}
+
+// trimHistory drops the records of h, oldest first, that are historyWindow
+// or more older than its newest, then any beyond historyCapacity.
+func trimHistory(h []CheckRecord) []CheckRecord {
+	if len(h) == 0 {
+		return h
+	}
+	cutoff := h[len(h)-1].At.Add(-historyWindow)
+	i := 0
+	for i < len(h) && !h[i].At.After(cutoff) {
+		i++
+	}
+	if n := len(h) - historyCapacity; i < n {
+		i = n
+	}
+	return h[i:]
+}
+
+// GetHistory returns a copy of the named endpoint's history, oldest first.
+func GetHistory(name string) []CheckRecord {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	result := make([]CheckRecord, len(history[name]))
+	copy(result, history[name])
+	return result
+}
+
+// AllHistory returns a copy of every endpoint's history, for persistence.
+func AllHistory() map[string][]CheckRecord {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	out := make(map[string][]CheckRecord, len(history))
+	for name, h := range history {
+		out[name] = append([]CheckRecord(nil), h...)
+	}
+	return out
+}
+
+// SetHistory replaces the named endpoint's history, e.g. when warm-starting
+// from a persisted snapshot. Records outside historyWindow are dropped as in
+// RecordCheck.
+func SetHistory(name string, recs []CheckRecord) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	defer generation.Add(1)
+
+	h := append([]CheckRecord(nil), trimHistory(recs)...)
+	for i := range h {
+		h[i].parseAmounts()
+	}
+	history[name] = h
+}
+
+// moveHistory files the history of old under name, e.g. when a row is
+// renamed.
+func moveHistory(old, name string) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	if recs, ok := history[old]; ok {
+		history[name] = recs
+		delete(history, old)
+	}
+}
+
+// HistorySummary aggregates check records over a window.
+type HistorySummary struct {
+	Checks int // up + down checks; info / unsupported / not-applicable / unknown are not counted
+	Up     int
+	Down   int
+}
+
+// Uptime returns the fraction of counted checks that were up, or -1 when no
+// checks fall in the window.
+func (s HistorySummary) Uptime() float64 {
+	if s.Checks == 0 {
+		return -1
+	}
+	return float64(s.Up) / float64(s.Checks)
+}
+
+// Add merges another summary into s.
+func (s *HistorySummary) Add(o HistorySummary) {
+	s.Checks += o.Checks
+	s.Up += o.Up
+	s.Down += o.Down
+}
+
+// SummarizeHistory counts up and down checks at or after since.
+func SummarizeHistory(records []CheckRecord, since time.Time) HistorySummary {
+	var s HistorySummary
+	for _, r := range records {
+		if r.At.Before(since) {
+			continue
+		}
+		switch {
+		case r.Status == "up":
+			s.Up++
+			s.Checks++
+		case IsDownStatus(r.Status):
+			s.Down++
+			s.Checks++
+		}
+	}
+	return s
+}
+
+// FailureCause is one distinct failure message and how often it occurred.
+type FailureCause struct {
+	Message  string
+	Count    int
+	LastSeen time.Time
+}
+
+// FailureCauses groups down checks at or after since by message, most
+// frequent first (ties broken by most recent).
+func FailureCauses(records []CheckRecord, since time.Time) []FailureCause {
+	byMessage := map[string]*FailureCause{}
+	for _, r := range records {
+		if r.At.Before(since) || !IsDownStatus(r.Status) {
+			continue
+		}
+		c, ok := byMessage[r.Message]
+		if !ok {
+			c = &FailureCause{Message: r.Message}
+			byMessage[r.Message] = c
+		}
+		c.Count++
+		if r.At.After(c.LastSeen) {
+			c.LastSeen = r.At
+		}
+	}
+
+	out := make([]FailureCause, 0, len(byMessage))
+	for _, c := range byMessage {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].LastSeen.After(out[j].LastSeen)
+	})
+	return out
+}
@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRecordCheckCapsHistory(t *testing.T) {
+	const name = "history-cap-test"
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7*24+10; i++ {
+		RecordCheck(name, CheckRecord{At: t0.Add(time.Duration(i) * time.Hour), Status: "up"})
+	}
+	h := GetHistory(name)
+	if len(h) != 7*24 {
+		t.Fatalf("len = %d, want %d", len(h), 7*24)
+	}
+	if want := t0.Add(10 * time.Hour); !h[0].At.Equal(want) {
+		t.Fatalf("oldest = %v, want %v", h[0].At, want)
+	}
+}
+
+// A row checked every 15 minutes keeps the whole week, not 168 records.
+func TestHistoryIsBoundedByTime(t *testing.T) {
+	const name = "history-window-test"
+	t.Cleanup(func() { SetHistory(name, nil) })
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 8*24*4; i++ {
+		RecordCheck(name, CheckRecord{At: t0.Add(time.Duration(i) * 15 * time.Minute), Status: "up"})
+	}
+	h := GetHistory(name)
+	if len(h) != 7*24*4 {
+		t.Fatalf("len = %d, want a week of 15m checks (%d)", len(h), 7*24*4)
+	}
+	if newest := h[len(h)-1].At; newest.Sub(h[0].At) >= historyWindow {
+		t.Fatalf("history spans %v", newest.Sub(h[0].At))
+	}
+	if s := SummarizeHistory(h, h[len(h)-1].At.Add(-historyWindow)); s.Checks != 7*24*4 {
+		t.Fatalf("week summary counts %d checks", s.Checks)
+	}
+}
+
+func TestSummarizeAndFailureCauses(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var records []CheckRecord
+	add := func(hour int, status, msg string) {
+		records = append(records, CheckRecord{At: t0.Add(time.Duration(hour) * time.Hour), Status: status, Message: msg})
+	}
+	add(0, "down", "old failure")
+	add(1, "up", "Ok")
+	add(2, "down", "timeout")
+	add(3, "error", "timeout")
+	add(4, "info", "WIP")
+	add(5, "down", "wrong pool")
+	for i := 6; i < 10; i++ {
+		add(i, "up", "Ok")
+	}
+
+	s := SummarizeHistory(records, t0.Add(time.Hour))
+	if s.Checks != 8 || s.Up != 5 || s.Down != 3 {
+		t.Fatalf("summary = %+v", s)
+	}
+	if got := fmt.Sprintf("%.3f", s.Uptime()); got != "0.625" {
+		t.Fatalf("uptime = %s", got)
+	}
+	if (HistorySummary{}).Uptime() != -1 {
+		t.Fatal("empty summary should report -1")
+	}
+
+	causes := FailureCauses(records, t0.Add(time.Hour))
+	if len(causes) != 2 || causes[0].Message != "timeout" || causes[0].Count != 2 || causes[1].Message != "wrong pool" {
+		t.Fatalf("causes = %+v", causes)
+	}
+}
+
+func TestQuoteSpreadBps(t *testing.T) {
+	tests := []struct {
+		quote, market string
+		want          float64
+		ok            bool
+	}{
+		{"990", "1000", 100, true},
+		{"1000", "1000", 0, true},
+		{"1010", "1000", -100, true},
+		{"", "1000", 0, false},
+		{"1000", "0", 0, false},
+		{"abc", "1000", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := QuoteSpreadBps(tt.quote, tt.market)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("QuoteSpreadBps(%q, %q) = %v, %v; want %v, %v", tt.quote, tt.market, got, ok, tt.want, tt.ok)
+		}
+	}
+}
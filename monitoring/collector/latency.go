@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LatencyPhases splits a response time into the phases of the request, as
+// reported by net/http/httptrace, to tell a slow network from a slow provider
+// backend. DNS, Connect and TLS are zero when the request reused a pooled
+// connection.
+type LatencyPhases struct {
+	DNS     time.Duration `json:"dns,omitempty"`
+	Connect time.Duration `json:"connect,omitempty"`
+	TLS     time.Duration `json:"tls,omitempty"`
+	// TTFB is from the request being written to the first response byte:
+	// the provider's processing time plus one round trip.
+	TTFB time.Duration `json:"ttfb,omitempty"`
+	// Read is from the first response byte to the end of the body.
+	Read time.Duration `json:"read,omitempty"`
+}
+
+// Latency phase names, in request order.
+const (
+	PhaseDNS     = "dns"
+	PhaseConnect = "connect"
+	PhaseTLS     = "tls"
+	PhaseTTFB    = "ttfb"
+	PhaseRead    = "read"
+)
+
+// LatencyPhaseNames lists the phases in request order.
+var LatencyPhaseNames = []string{PhaseDNS, PhaseConnect, PhaseTLS, PhaseTTFB, PhaseRead}
+
+// IsZero reports whether no phase was measured.
+func (p LatencyPhases) IsZero() bool {
+	return p == LatencyPhases{}
+}
+
+// Phase returns the named phase's duration, 0 for an unknown name.
+func (p LatencyPhases) Phase(name string) time.Duration {
+	switch name {
+	case PhaseDNS:
+		return p.DNS
+	case PhaseConnect:
+		return p.Connect
+	case PhaseTLS:
+		return p.TLS
+	case PhaseTTFB:
+		return p.TTFB
+	case PhaseRead:
+		return p.Read
+	}
+	return 0
+}
+
+// String renders the measured phases in request order, e.g.
+// "dns 4ms · connect 21ms · tls 43ms · ttfb 690ms · read 12ms"; a reused
+// connection shows only ttfb and read. "" when no phase was measured.
+func (p LatencyPhases) String() string {
+	var parts []string
+	for _, name := range LatencyPhaseNames {
+		if d := p.Phase(name); d > 0 {
+			parts = append(parts, fmt.Sprintf("%s %dms", name, d.Milliseconds()))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,20 @@
+package collector
+
+import (
+	"net/url"
+
+	"go-monitoring/config"
+)
+
+// DashboardPath returns a dashboard deep link narrowed to this endpoint's
+// pair, network and solver over the last 24h, e.g.
+// /?network=arbitrum&q=Arbitrum-Boosted-StableSurge(GHO/USDC)&range=24h&solver=kyberswap.
+// The query parameters are the ones the dashboard filter form reads.
+func (e *Endpoint) DashboardPath() string {
+	v := url.Values{}
+	v.Set("network", config.NetworkName(e.Network))
+	v.Set("q", e.BaseName)
+	v.Set("solver", e.RouteSolver)
+	v.Set("range", "24h")
+	return "/?" + v.Encode()
+}
@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Note is what operators know about an endpoint that the checks can't tell
+// them, e.g. "Kyber needs a source whitelisting ticket (#123)", and the
+// runbook to follow when it fails. Edited on /solver/{name}, shown next to
+// the status, and kept by the store. Keyed by Endpoint.Name like the history,
+// and moved with it when the row is renamed (RenameEndpoint).
+type Note struct {
+	Endpoint   string    `json:"endpoint"`
+	Text       string    `json:"text,omitempty"`
+	RunbookURL string    `json:"runbookUrl,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// IsEmpty reports whether the note says nothing; setting an empty note
+// deletes it.
+func (n Note) IsEmpty() bool {
+	return n.Text == "" && n.RunbookURL == ""
+}
+
+var (
+	notes   = map[string]Note{}
+	notesMu sync.Mutex
+)
+
+// SetNote stores the note for its endpoint, replacing any previous one, or
+// deletes it when the note is empty.
+func SetNote(n Note) {
+	notesMu.Lock()
+	defer notesMu.Unlock()
+	defer generation.Add(1)
+
+	if n.IsEmpty() {
+		delete(notes, n.Endpoint)
+		return
+	}
+	notes[n.Endpoint] = n
+}
+
+// SetNotes replaces every note, e.g. when warm-starting from a persisted
+// snapshot.
+func SetNotes(ns []Note) {
+	notesMu.Lock()
+	defer notesMu.Unlock()
+	defer generation.Add(1)
+
+	notes = make(map[string]Note, len(ns))
+	for _, n := range ns {
+		if !n.IsEmpty() {
+			notes[n.Endpoint] = n
+		}
+	}
+}
+
+// moveNote files the note of old under name, unless name has one.
+func moveNote(old, name string) {
+	notesMu.Lock()
+	defer notesMu.Unlock()
+	defer generation.Add(1)
+
+	n, ok := notes[old]
+	if !ok {
+		return
+	}
+	delete(notes, old)
+	if _, taken := notes[name]; !taken {
+		n.Endpoint = name
+		notes[name] = n
+	}
+}
+
+// GetNote returns the named endpoint's note; ok is false when it has none.
+func GetNote(name string) (n Note, ok bool) {
+	notesMu.Lock()
+	defer notesMu.Unlock()
+
+	n, ok = notes[name]
+	return n, ok
+}
+
+// AllNotes returns every note, ordered by endpoint name.
+func AllNotes() []Note {
+	notesMu.Lock()
+	defer notesMu.Unlock()
+
+	out := make([]Note, 0, len(notes))
+	for _, n := range notes {
+		out = append(out, n)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Endpoint < out[j].Endpoint })
+	return out
+}
@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// RegionResult is the latest result a regional check worker reported for one
+// endpoint.
+type RegionResult struct {
+	Region       string
+	Status       string
+	Message      string
+	ReturnAmount string
+	CheckedAt    time.Time
+}
+
+var (
+	regionResults   = map[string]map[string]RegionResult{} // endpoint name -> region -> result
+	regionResultsMu sync.Mutex
+)
+
+// RecordRegionResult stores a worker's result for the named endpoint,
+// replacing that region's previous one.
+func RecordRegionResult(name string, r RegionResult) {
+	regionResultsMu.Lock()
+	defer regionResultsMu.Unlock()
+	defer generation.Add(1)
+
+	byRegion := regionResults[name]
+	if byRegion == nil {
+		byRegion = map[string]RegionResult{}
+		regionResults[name] = byRegion
+	}
+	byRegion[r.Region] = r
+}
+
+// GetRegionResults returns the named endpoint's regional results, sorted by
+// region.
+func GetRegionResults(name string) []RegionResult {
+	regionResultsMu.Lock()
+	defer regionResultsMu.Unlock()
+
+	out := make([]RegionResult, 0, len(regionResults[name]))
+	for _, r := range regionResults[name] {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Region < out[j].Region })
+	return out
+}
@@ -0,0 +1,220 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-monitoring/config"
+)
+
+// RouteGraph is the route a provider returned, in one shape for every
+// provider: parallel paths (a split across routes), each a sequence of hops
+// from token to token, each hop through one or more venues (a pool on an
+// exchange). Handlers build it from their response and store it with
+// Endpoint.SetRoute before validating it, then check sources, pools and hops
+// against the graph rather than their response, so a failure such as
+// "expected pool not found in route" can show the route that was returned.
+// The store persists it with the endpoint's latest state.
+type RouteGraph struct {
+	Paths []RoutePath `json:"paths,omitempty"`
+	// MarksBuffers is set when the provider reports which steps are
+	// buffers (Balancer SOR); otherwise RouteVenue.Buffer is always false.
+	MarksBuffers bool `json:"marksBuffers,omitempty"`
+}
+
+// RoutePath is one path of a route.
+type RoutePath struct {
+	Percent float64    `json:"percent,omitempty"` // share of the amount; 0 when the provider doesn't say
+	Hops    []RouteHop `json:"hops"`
+}
+
+// RouteHop swaps TokenIn for TokenOut through its venues.
+type RouteHop struct {
+	TokenIn  string       `json:"tokenIn,omitempty"` // empty when the provider doesn't say
+	TokenOut string       `json:"tokenOut,omitempty"`
+	Venues   []RouteVenue `json:"venues"`
+}
+
+// RouteVenue is where (part of) a hop is swapped.
+type RouteVenue struct {
+	Pool     string  `json:"pool,omitempty"`     // pool address; empty when the provider only names the exchange
+	Exchange string  `json:"exchange,omitempty"` // provider's source name, e.g. "BalancerV3"; empty for Balancer SOR
+	Percent  float64 `json:"percent,omitempty"`
+	Buffer   bool    `json:"buffer,omitempty"` // an ERC4626 buffer wrap or unwrap (Balancer SOR)
+	// Amounts swapped in base units, empty when the provider doesn't say.
+	AmountIn  string `json:"amountIn,omitempty"`
+	AmountOut string `json:"amountOut,omitempty"`
+}
+
+// SetRoute stores the route on the endpoint along with the views rules and
+// the boosted path check read: RouteSources and RouteTokens.
+func (e *Endpoint) SetRoute(g RouteGraph) {
+	e.Route = g
+	e.RouteSources = g.Sources()
+	e.RouteTokens = g.Tokens()
+}
+
+// IsEmpty reports whether the graph has no hops.
+func (g RouteGraph) IsEmpty() bool {
+	for _, p := range g.Paths {
+		if len(p.Hops) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Venues returns every venue of the route, path by path and hop by hop.
+func (g RouteGraph) Venues() []RouteVenue {
+	var out []RouteVenue
+	for _, p := range g.Paths {
+		for _, h := range p.Hops {
+			out = append(out, h.Venues...)
+		}
+	}
+	return out
+}
+
+// Sources returns the exchange of every venue in route order, nil when the
+// provider names none.
+func (g RouteGraph) Sources() []string {
+	var out []string
+	for _, v := range g.Venues() {
+		if v.Exchange != "" {
+			out = append(out, v.Exchange)
+		}
+	}
+	return out
+}
+
+// HasPool reports whether a venue of the route swaps through pool, ignoring
+// address case (config.SameAddress).
+func (g RouteGraph) HasPool(pool string) bool {
+	for _, v := range g.Venues() {
+		if v.Pool != "" && config.SameAddress(v.Pool, pool) {
+			return true
+		}
+	}
+	return false
+}
+
+// HopCount returns the number of hops of the longest path.
+func (g RouteGraph) HopCount() int {
+	n := 0
+	for _, p := range g.Paths {
+		if len(p.Hops) > n {
+			n = len(p.Hops)
+		}
+	}
+	return n
+}
+
+// Tokens returns the tokens along a single-path route, TokenIn first; nil
+// when the route splits across paths or a hop's token is unknown.
+func (g RouteGraph) Tokens() []string {
+	if len(g.Paths) != 1 || len(g.Paths[0].Hops) == 0 {
+		return nil
+	}
+	hops := g.Paths[0].Hops
+	if hops[0].TokenIn == "" {
+		return nil
+	}
+	tokens := []string{hops[0].TokenIn}
+	for _, h := range hops {
+		if h.TokenOut == "" {
+			return nil
+		}
+		tokens = append(tokens, h.TokenOut)
+	}
+	return tokens
+}
+
+// Buffers reports, for each hop of a single-path route, whether it is a
+// buffer step; nil when the route splits across paths or the provider
+// doesn't mark buffers.
+func (g RouteGraph) Buffers() []bool {
+	if !g.MarksBuffers || len(g.Paths) != 1 || len(g.Paths[0].Hops) == 0 {
+		return nil
+	}
+	out := make([]bool, len(g.Paths[0].Hops))
+	for i, h := range g.Paths[0].Hops {
+		for _, v := range h.Venues {
+			if v.Buffer {
+				out[i] = true
+			}
+		}
+	}
+	return out
+}
+
+// String renders the route one path per line as a hop diagram, e.g.
+//
+//	[60%] 0xc02a…6cc2 → 0x85b2…3f0a(BalancerV3) → 0xa0b8…eb48
+//
+// Split hops list their venues with their shares: "→ pool(A) 70% + (B) 30% →".
+func (g RouteGraph) String() string {
+	var lines []string
+	for _, p := range g.Paths {
+		if len(p.Hops) == 0 {
+			continue
+		}
+		var b strings.Builder
+		if p.Percent > 0 {
+			fmt.Fprintf(&b, "[%s] ", formatPercent(p.Percent))
+		}
+		b.WriteString(routeToken(p.Hops[0].TokenIn))
+		for _, h := range p.Hops {
+			venues := make([]string, len(h.Venues))
+			for i, v := range h.Venues {
+				venues[i] = v.String()
+				if len(h.Venues) > 1 && v.Percent > 0 {
+					venues[i] += " " + formatPercent(v.Percent)
+				}
+			}
+			if len(venues) == 0 {
+				venues = []string{"?"}
+			}
+			fmt.Fprintf(&b, " → %s → %s", strings.Join(venues, " + "), routeToken(h.TokenOut))
+		}
+		lines = append(lines, b.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String renders the venue as "pool(exchange)", "(exchange)" or "pool";
+// buffers are labelled "(buffer)".
+func (v RouteVenue) String() string {
+	label := v.Exchange
+	if v.Buffer {
+		label = "buffer"
+	}
+	switch {
+	case label == "":
+		return ShortAddress(v.Pool)
+	case v.Pool == "":
+		return "(" + label + ")"
+	default:
+		return ShortAddress(v.Pool) + "(" + label + ")"
+	}
+}
+
+func routeToken(address string) string {
+	if address == "" {
+		return "?"
+	}
+	return ShortAddress(address)
+}
+
+// ShortAddress abbreviates a hex address to its first and last four digits,
+// e.g. 0xc02a…6cc2. Other strings are returned unchanged.
+func ShortAddress(s string) string {
+	if len(s) <= 14 || !strings.HasPrefix(s, "0x") {
+		return s
+	}
+	return s[:6] + "…" + s[len(s)-4:]
+}
+
+func formatPercent(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,60 @@
+package collector
+
+import "testing"
+
+func TestRouteGraphString(t *testing.T) {
+	const (
+		weth = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+		usdc = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+		pool = "0x85b2b559bc2d21104c4defdd6efca8a20343361d"
+	)
+	g := RouteGraph{Paths: []RoutePath{
+		{Percent: 60, Hops: []RouteHop{{TokenIn: weth, TokenOut: usdc, Venues: []RouteVenue{{Pool: pool, Exchange: "BalancerV3"}}}}},
+		{Percent: 40, Hops: []RouteHop{
+			{TokenIn: weth, TokenOut: "0xdai", Venues: []RouteVenue{{Exchange: "UniswapV3", Percent: 70}, {Exchange: "CurveV1", Percent: 30}}},
+			{TokenOut: usdc},
+		}},
+	}}
+	want := "[60%] 0xc02a…6cc2 → 0x85b2…361d(BalancerV3) → 0xa0b8…eb48\n" +
+		"[40%] 0xc02a…6cc2 → (UniswapV3) 70% + (CurveV1) 30% → 0xdai → ? → 0xa0b8…eb48"
+	if got := g.String(); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+	if g.IsEmpty() || !(RouteGraph{Paths: []RoutePath{{}}}).IsEmpty() {
+		t.Error("IsEmpty")
+	}
+}
+
+func TestRouteGraphViews(t *testing.T) {
+	single := RouteGraph{Paths: []RoutePath{{Hops: []RouteHop{
+		{TokenIn: "0xa", TokenOut: "0xb", Venues: []RouteVenue{{Pool: "0xPool", Exchange: "BalancerV3"}}},
+		{TokenIn: "0xb", TokenOut: "0xc", Venues: []RouteVenue{{Exchange: "UniswapV3"}, {Pool: "0xother"}}},
+	}}}}
+	if got := single.Tokens(); len(got) != 3 || got[0] != "0xa" || got[2] != "0xc" {
+		t.Errorf("Tokens = %v", got)
+	}
+	if got := single.Sources(); len(got) != 2 || got[1] != "UniswapV3" {
+		t.Errorf("Sources = %v", got)
+	}
+	if !single.HasPool("0xpool") || single.HasPool("") || single.HasPool("0xmissing") {
+		t.Error("HasPool")
+	}
+	if single.HopCount() != 2 || single.Buffers() != nil {
+		t.Errorf("HopCount = %d, Buffers = %v", single.HopCount(), single.Buffers())
+	}
+
+	split := RouteGraph{Paths: append(single.Paths, RoutePath{Hops: []RouteHop{{TokenIn: "0xa", TokenOut: "0xc"}}})}
+	if split.Tokens() != nil || split.HopCount() != 2 {
+		t.Errorf("split: Tokens = %v, HopCount = %d", split.Tokens(), split.HopCount())
+	}
+	unknown := RouteGraph{Paths: []RoutePath{{Hops: []RouteHop{{TokenOut: "0xb"}}}}}
+	if unknown.Tokens() != nil {
+		t.Errorf("unknown TokenIn: Tokens = %v", unknown.Tokens())
+	}
+
+	var e Endpoint
+	e.SetRoute(single)
+	if len(e.RouteSources) != 2 || len(e.RouteTokens) != 3 || e.Route.HopCount() != 2 {
+		t.Errorf("SetRoute: %v %v", e.RouteSources, e.RouteTokens)
+	}
+}
@@ -0,0 +1,447 @@
+// Package collector holds the monitored endpoints and their results: the
+// Endpoint type, the in-memory BaseEndpoints and discovered stores, and the
+// per-endpoint check history. Importable on its own; embedders that only
+// check endpoints need just the Endpoint type.
+package collector
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-monitoring/config"
+)
+
+// Endpoint represents a monitored API endpoint
+type Endpoint struct {
+	Name              string
+	ID                string // stable, URL-safe identity; Name is for display and may change
+	BaseName          string
+	SolverName        string
+	RouteSolver       string
+	Network           string
+	TokenIn           string
+	TokenOut          string
+	TokenInDecimals   int
+	TokenOutDecimals  int
+	SwapAmount        string
+	ExpectedPool      string
+	ExpectedNoHops    int
+	Delay             time.Duration
+	LastStatus        string
+	LastChecked       time.Time
+	LastStateChange   time.Time // when LastStatus last changed value
+	FirstSeenDown     time.Time // start of the current down streak; zero while not down
+	Message           string
+	ReturnAmount      string
+	MarketPrice       string
+	OnChainPrice      string
+	OnChainQueryError string // Error message if on-chain query failed
+	// The latest on-chain query: the block it read (0 when none was pinned),
+	// the RPC endpoint's hostname and the eth_call latency. Set on failure
+	// too, to debug discrepancies between providers and on-chain results.
+	OnChainBlock   uint64
+	OnChainRPCHost string
+	OnChainLatency time.Duration
+	// The latest Balancer-only response's HTTP status (0 when no response
+	// arrived) and its rate-limit and request ID headers (see
+	// DiagnosticHeaders), for provider support requests.
+	HTTPStatus  int
+	HTTPHeaders map[string]string
+	// ResponseTime is how long the latest Balancer-only response took to
+	// arrive, 0 when none arrived. Response time SLOs are measured on it.
+	// ResponsePhases splits it into DNS, connect, TLS, first byte and read.
+	ResponseTime   time.Duration
+	ResponsePhases LatencyPhases
+	// Surge is a StableSurge pool's fee state read with the latest check;
+	// zero for other pools or when it couldn't be read.
+	Surge            SurgeState
+	SwapPathPools    []string
+	SwapPathTokenOut []string
+	SwapPathIsBuffer []bool
+	// Parsed from the latest Balancer-only response for rules evaluation.
+	// Route is the returned route as hops; SetRoute derives RouteSources
+	// (nil when the provider doesn't list sources) and RouteTokens
+	// (addresses along a single-path route, TokenIn first; nil when it
+	// doesn't report them) from it. HasPriceImpact is false when the
+	// provider doesn't report price impact.
+	RouteSources   []string
+	RouteTokens    []string
+	Route          RouteGraph
+	PriceImpactBps float64
+	HasPriceImpact bool
+	// PriceImpactAlerted is set while the price impact is above the alert
+	// threshold, so the alert is sent once per excursion.
+	PriceImpactAlerted bool
+	// Spender is the allowance target (the address a user would approve)
+	// from the latest Balancer-only response, empty when the provider
+	// doesn't report one. SpenderAlerted is set while it is missing from the
+	// known-good spender registry.
+	Spender        string
+	SpenderAlerted bool
+	// From the latest market price response of providers that quote each
+	// source separately: BalancerRank is the 1-based rank of the best
+	// Balancer V3 quote among RankedSources quotes, 0 when Balancer V3 did
+	// not quote. RankedSources is 0 when the provider lists no per-source
+	// quotes. RankAlerted is set while Balancer V3 is outside the alert's
+	// top N.
+	BalancerRank  int
+	RankedSources int
+	RankAlerted   bool
+	// SpreadAlerted is set while the quote spread (market price over the
+	// Balancer-only quote) stays widened, so the alert is sent once per
+	// excursion.
+	SpreadAlerted bool
+	// MarketLeadAlerted is set while a MarketLeadCycles rule is triggered.
+	MarketLeadAlerted bool
+	Rules             []config.EndpointRule // rules applying to this route solver
+	Labels            config.Labels         // the BaseEndpoint's tags, shared by its solver rows
+	Schedule          string                // the BaseEndpoint's own check cadence; empty follows CHECK_INTERVAL_HOURS
+	// Discovered-only metadata. Empty for BaseEndpoints rows.
+	PoolType string // Balancer API pool type enum (e.g. "STABLE", "GYROE")
+	HookType string // Balancer API hook type, empty when no hook
+	Variant  string // "" for base / registered; "underlying" for the boosted underlying row; "surge" for the hook-triggering row
+
+	// ReturnAmount, MarketPrice and OnChainPrice parsed, kept current by the
+	// store; read with Amounts.
+	amounts    Amounts
+	amountsSet bool
+}
+
+var (
+	endpoints []Endpoint
+	mu        sync.Mutex
+)
+
+// generation counts writes to the stores; see Generation.
+var generation atomic.Uint64
+
+// Generation changes whenever a row, its history, note, group status or
+// regional results may have changed, so readers can cache views derived
+// from them (the dashboard does).
+func Generation() uint64 {
+	return generation.Load()
+}
+
+// changed refreshes the derived fields of rows a write may have changed
+// and advances Generation. Callers hold the rows' lock.
+func changed(eps []Endpoint) {
+	for i := range eps {
+		eps[i].refreshAmounts()
+	}
+	generation.Add(1)
+}
+
+// WithEndpointsLock provides thread-safe access for writers (API checker functions)
+func WithEndpointsLock(fn func([]Endpoint)) {
+	mu.Lock()
+	defer mu.Unlock()
+	fn(endpoints)
+	changed(endpoints)
+}
+
+// GetEndpointsCopy provides thread-safe access for readers (dashboard handler)
+func GetEndpointsCopy() []Endpoint {
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Return a copy to avoid race conditions
+	result := make([]Endpoint, len(endpoints))
+	copy(result, endpoints)
+	return result
+}
+
+// SetEndpoints initializes the endpoints slice
+func SetEndpoints(eps []Endpoint) {
+	mu.Lock()
+	defer mu.Unlock()
+	endpoints = eps
+	changed(endpoints)
+}
+
+// GetEndpointByName returns a copy of a specific endpoint by name
+func GetEndpointByName(name string) *Endpoint {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i := range endpoints {
+		if endpoints[i].Name == name {
+			// Return a copy to avoid race conditions
+			result := endpoints[i]
+			return &result
+		}
+	}
+	return nil
+}
+
+// ResolveEndpoint returns the name of the BaseEndpoints or discovered row
+// whose ID or, failing that, Name is ref; base rows first. URLs and the APIs
+// take either, so links keep working when a row is renamed.
+func ResolveEndpoint(ref string) (string, bool) {
+	if ref == "" {
+		return "", false
+	}
+	mu.Lock()
+	base := resolveIn(endpoints, ref)
+	mu.Unlock()
+	if base != "" {
+		return base, true
+	}
+	discoveredMu.Lock()
+	defer discoveredMu.Unlock()
+	name := resolveIn(discoveredEndpoints, ref)
+	return name, name != ""
+}
+
+// resolveIn returns the name of the row of eps whose ID, or else Name, is ref.
+func resolveIn(eps []Endpoint, ref string) string {
+	for _, e := range eps {
+		if e.ID == ref {
+			return e.Name
+		}
+	}
+	for _, e := range eps {
+		if e.Name == ref {
+			return e.Name
+		}
+	}
+	return ""
+}
+
+// UpdateEndpointByName updates a specific endpoint by name
+func UpdateEndpointByName(name string, fn func(*Endpoint)) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i := range endpoints {
+		if endpoints[i].Name == name {
+			fn(&endpoints[i])
+			changed(endpoints[i : i+1])
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateEndpointsByBaseName applies fn to every solver row of a base endpoint
+// and returns how many rows were updated.
+func UpdateEndpointsByBaseName(baseName string, fn func(*Endpoint)) int {
+	mu.Lock()
+	defer mu.Unlock()
+
+	n := 0
+	for i := range endpoints {
+		if endpoints[i].BaseName == baseName {
+			fn(&endpoints[i])
+			changed(endpoints[i : i+1])
+			n++
+		}
+	}
+	return n
+}
+
+// UpsertEndpoints adds rows to the BaseEndpoints store, replacing rows with
+// the same ID or, failing that, Name. Replaced rows keep their result fields
+// and history, so re-importing an unchanged endpoint does not reset its
+// status and renaming one keeps it.
+func UpsertEndpoints(eps []Endpoint) (added, updated int) {
+	mu.Lock()
+	index := make(map[string]int, len(endpoints))
+	byID := make(map[string]int, len(endpoints))
+	for i, e := range endpoints {
+		index[e.Name] = i
+		if e.ID != "" {
+			byID[e.ID] = i
+		}
+	}
+	renamed := map[string]string{}
+	for _, e := range eps {
+		i, ok := byID[e.ID]
+		if !ok || e.ID == "" {
+			i, ok = index[e.Name]
+		}
+		if ok {
+			if old := endpoints[i].Name; old != e.Name {
+				renamed[old] = e.Name
+				delete(index, old)
+				index[e.Name] = i
+			}
+			carryResults(&e, endpoints[i])
+			endpoints[i] = e
+			updated++
+			continue
+		}
+		if e.LastStatus == "" {
+			e.LastStatus = "unknown"
+		}
+		index[e.Name] = len(endpoints)
+		if e.ID != "" {
+			byID[e.ID] = len(endpoints)
+		}
+		endpoints = append(endpoints, e)
+		added++
+	}
+	changed(endpoints)
+	mu.Unlock()
+
+	for old, name := range renamed {
+		RenameEndpoint(old, name)
+	}
+	return added, updated
+}
+
+var (
+	renameMu      sync.Mutex
+	renameHandler func(old, name string)
+)
+
+// SetRenameHandler registers fn to be called by RenameEndpoint, e.g. to move
+// what a store keeps under the old name. Nil removes it.
+func SetRenameHandler(fn func(old, name string)) {
+	renameMu.Lock()
+	defer renameMu.Unlock()
+	renameHandler = fn
+}
+
+// RenameEndpoint files the history and note of old under name, then calls
+// the rename handler. Called when a row keeps its ID under a new name, on
+// import or when a snapshot is restored.
+func RenameEndpoint(old, name string) {
+	moveHistory(old, name)
+	moveNote(old, name)
+	renameMu.Lock()
+	fn := renameHandler
+	renameMu.Unlock()
+	if fn != nil {
+		fn(old, name)
+	}
+}
+
+// carryResults copies the result fields of a prior row into e.
+func carryResults(e *Endpoint, p Endpoint) {
+	e.LastStatus = p.LastStatus
+	e.LastChecked = p.LastChecked
+	e.LastStateChange = p.LastStateChange
+	e.FirstSeenDown = p.FirstSeenDown
+	e.Message = p.Message
+	e.ReturnAmount = p.ReturnAmount
+	e.MarketPrice = p.MarketPrice
+	e.BalancerRank = p.BalancerRank
+	e.RankedSources = p.RankedSources
+	e.OnChainPrice = p.OnChainPrice
+	e.OnChainQueryError = p.OnChainQueryError
+	e.OnChainBlock = p.OnChainBlock
+	e.OnChainRPCHost = p.OnChainRPCHost
+	e.OnChainLatency = p.OnChainLatency
+	e.ResponseTime = p.ResponseTime
+	e.ResponsePhases = p.ResponsePhases
+	e.Surge = p.Surge
+	e.SwapPathPools = p.SwapPathPools
+	e.SwapPathTokenOut = p.SwapPathTokenOut
+	e.SwapPathIsBuffer = p.SwapPathIsBuffer
+}
+
+// ----------------------------------------------------------------------------
+// Discovered-endpoints store
+//
+// Lives alongside the BaseEndpoints store but with its own mutex so the hourly
+// price-check loop and the daily discovery loop never block each other. Fully
+// replaced on each discovery cycle, with per-row result carry-over for keys
+// that survive.
+// ----------------------------------------------------------------------------
+
+var (
+	discoveredEndpoints []Endpoint
+	discoveredMu        sync.Mutex
+	inTestSet           = map[string]struct{}{}
+)
+
+// SetDiscoveredEndpoints replaces the discovered store. Surviving rows
+// (matched by Endpoint.Name) keep their prior result fields so the dashboard
+// keeps showing yesterday's verdict until this cycle's run overwrites it.
+// poolKeys is the (network, poolAddress) set used by IsPoolInTestSet.
+func SetDiscoveredEndpoints(eps []Endpoint, poolKeys map[string]struct{}) {
+	discoveredMu.Lock()
+	defer discoveredMu.Unlock()
+
+	prior := make(map[string]Endpoint, len(discoveredEndpoints))
+	for _, e := range discoveredEndpoints {
+		prior[e.Name] = e
+	}
+
+	merged := make([]Endpoint, len(eps))
+	for i, e := range eps {
+		if p, ok := prior[e.Name]; ok {
+			carryResults(&e, p)
+		} else if e.LastStatus == "" {
+			e.LastStatus = "unknown"
+		}
+		merged[i] = e
+	}
+	discoveredEndpoints = merged
+	changed(discoveredEndpoints)
+
+	if poolKeys == nil {
+		inTestSet = map[string]struct{}{}
+	} else {
+		inTestSet = poolKeys
+	}
+}
+
+// GetDiscoveredEndpointsCopy returns a copy of the discovered endpoints slice.
+func GetDiscoveredEndpointsCopy() []Endpoint {
+	discoveredMu.Lock()
+	defer discoveredMu.Unlock()
+	result := make([]Endpoint, len(discoveredEndpoints))
+	copy(result, discoveredEndpoints)
+	return result
+}
+
+// GetDiscoveredEndpointByName returns a copy of a specific discovered
+// endpoint by name.
+func GetDiscoveredEndpointByName(name string) *Endpoint {
+	discoveredMu.Lock()
+	defer discoveredMu.Unlock()
+
+	for i := range discoveredEndpoints {
+		if discoveredEndpoints[i].Name == name {
+			result := discoveredEndpoints[i]
+			return &result
+		}
+	}
+	return nil
+}
+
+// UpdateDiscoveredEndpointByName mirrors UpdateEndpointByName for the
+// discovered store.
+func UpdateDiscoveredEndpointByName(name string, fn func(*Endpoint)) bool {
+	discoveredMu.Lock()
+	defer discoveredMu.Unlock()
+
+	for i := range discoveredEndpoints {
+		if discoveredEndpoints[i].Name == name {
+			fn(&discoveredEndpoints[i])
+			changed(discoveredEndpoints[i : i+1])
+			return true
+		}
+	}
+	return false
+}
+
+// IsPoolInTestSet reports whether the given pool (by network + address)
+// landed in the current test set. Used by /pools to render the "In test set"
+// badge without recomputing selection.
+func IsPoolInTestSet(network, poolAddress string) bool {
+	key := PoolKey(network, poolAddress)
+	discoveredMu.Lock()
+	defer discoveredMu.Unlock()
+	_, ok := inTestSet[key]
+	return ok
+}
+
+// PoolKey is the canonical key shape for the in-test-set lookup. Exposed so
+// callers building the set use the same casing rules as the lookup.
+func PoolKey(network, poolAddress string) string {
+	return strings.ToLower(network) + "|" + config.NormalizeAddress(poolAddress)
+}
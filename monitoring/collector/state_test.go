@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveEndpoint(t *testing.T) {
+	SetEndpoints([]Endpoint{{Name: "Odos-A", ID: "odos-1-a"}, {Name: "odos-1-b", ID: "odos-1-c"}})
+	SetDiscoveredEndpoints([]Endpoint{{Name: "Odos-D", ID: "odos-1-d"}}, nil)
+	t.Cleanup(func() {
+		SetEndpoints(nil)
+		SetDiscoveredEndpoints(nil, nil)
+	})
+
+	for ref, want := range map[string]string{
+		"odos-1-a": "Odos-A",
+		"Odos-A":   "Odos-A",
+		"odos-1-c": "odos-1-b",
+		"odos-1-d": "Odos-D",
+		"Odos-D":   "Odos-D",
+	} {
+		if got, ok := ResolveEndpoint(ref); !ok || got != want {
+			t.Errorf("ResolveEndpoint(%q) = %q, %v; want %q", ref, got, ok, want)
+		}
+	}
+	if _, ok := ResolveEndpoint("missing"); ok {
+		t.Error("an unknown ref resolved")
+	}
+}
+
+func TestUpsertEndpointsRenamesByID(t *testing.T) {
+	SetEndpoints([]Endpoint{{Name: "Odos-Old", ID: "odos-1-a", LastStatus: "up"}})
+	RecordCheck("Odos-Old", CheckRecord{At: time.Now(), Status: "up"})
+	SetNote(Note{Endpoint: "Odos-Old", Text: "whitelisted in #12"})
+	var renames []string
+	SetRenameHandler(func(old, name string) { renames = append(renames, old+" → "+name) })
+	t.Cleanup(func() {
+		SetRenameHandler(nil)
+		SetEndpoints(nil)
+		SetHistory("Odos-New", nil)
+		SetNotes(nil)
+	})
+
+	added, updated := UpsertEndpoints([]Endpoint{{Name: "Odos-New", ID: "odos-1-a"}})
+	if added != 0 || updated != 1 {
+		t.Fatalf("upsert = %d added, %d updated", added, updated)
+	}
+	if e := GetEndpointByName("Odos-New"); e == nil || e.LastStatus != "up" {
+		t.Fatalf("renamed row = %+v", e)
+	}
+	if len(GetHistory("Odos-New")) != 1 || len(GetHistory("Odos-Old")) != 0 {
+		t.Fatal("history did not follow the rename")
+	}
+	if n, ok := GetNote("Odos-New"); !ok || n.Endpoint != "Odos-New" || n.Text != "whitelisted in #12" {
+		t.Fatalf("note = %+v, %v", n, ok)
+	}
+	if _, ok := GetNote("Odos-Old"); ok {
+		t.Fatal("note kept under the old name")
+	}
+	if len(renames) != 1 || renames[0] != "Odos-Old → Odos-New" {
+		t.Fatalf("rename handler calls = %q", renames)
+	}
+}
@@ -0,0 +1,42 @@
+package collector
+
+import "fmt"
+
+// SurgeState is a StableSurge pool's fee state read on-chain around a
+// check, so a quote anomaly can be told apart from a pool charging the
+// surge fee. Fees, the threshold and the imbalance are fractions (0.003 is
+// 0.3%).
+type SurgeState struct {
+	Block     uint64  `json:"block"`
+	StaticFee float64 `json:"staticFee"`
+	MaxFee    float64 `json:"maxFee"`
+	Threshold float64 `json:"threshold"`
+	// Imbalance is the pool's live balances' total deviation from their
+	// median over their sum, as the hook measures it.
+	Imbalance float64 `json:"imbalance"`
+	// Fee is what a swap adding to the imbalance pays at the current state:
+	// StaticFee below Threshold, rising linearly to MaxFee at full imbalance.
+	Fee float64 `json:"fee"`
+}
+
+// IsZero reports whether no state was read.
+func (s SurgeState) IsZero() bool {
+	return s == SurgeState{}
+}
+
+// Surging reports whether the pool's imbalance is past the surge threshold,
+// so imbalancing swaps pay more than the static fee.
+func (s SurgeState) Surging() bool {
+	return !s.IsZero() && s.Imbalance > s.Threshold
+}
+
+// String summarizes the state, e.g. "surge fee 0.62% (static 0.10%,
+// imbalance 41.0% > threshold 30.0%) at block 123".
+func (s SurgeState) String() string {
+	cmp := "≤"
+	if s.Surging() {
+		cmp = ">"
+	}
+	return fmt.Sprintf("surge fee %.2f%% (static %.2f%%, imbalance %.1f%% %s threshold %.1f%%) at block %d",
+		s.Fee*100, s.StaticFee*100, s.Imbalance*100, cmp, s.Threshold*100, s.Block)
+}
@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"fmt"
+	"time"
+
+	"go-monitoring/internal/clock"
+)
+
+// StatusNotApplicable marks an endpoint whose solver doesn't serve its
+// network at all. It is permanent rather than a failure: excluded from uptime
+// and never alerted.
+const StatusNotApplicable = "not-applicable"
+
+// IsDownStatus reports whether a LastStatus value counts as an outage for
+// transition tracking. "unsupported", StatusNotApplicable, "info" and
+// "unknown" are not failures of the provider, so they neither start nor
+// extend a down streak.
+func IsDownStatus(status string) bool {
+	switch status {
+	case "down", "error", "panic":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsKnownStatus reports whether status is one a check may write to
+// LastStatus. The dashboard, uptime and alerting only understand these.
+func IsKnownStatus(status string) bool {
+	switch status {
+	case "unknown", "up", "down", "error", "panic", "info", "unsupported", StatusNotApplicable:
+		return true
+	default:
+		return false
+	}
+}
+
+// RecordStatusChange updates LastStateChange / FirstSeenDown after a check has
+// written LastStatus. prevStatus is the status the endpoint had before the
+// check ran. FirstSeenDown marks the start of the current down streak and is
+// cleared as soon as the endpoint leaves a down status.
+func (e *Endpoint) RecordStatusChange(prevStatus string, at time.Time) {
+	if e.LastStatus != prevStatus {
+		e.LastStateChange = at
+	}
+	switch {
+	case !IsDownStatus(e.LastStatus):
+		e.FirstSeenDown = time.Time{}
+	case !IsDownStatus(prevStatus) || e.FirstSeenDown.IsZero():
+		e.FirstSeenDown = at
+	}
+}
+
+// DownFor returns how long the endpoint has been in its current down streak,
+// or zero when it is not down.
+func (e *Endpoint) DownFor(now time.Time) time.Duration {
+	if e.FirstSeenDown.IsZero() || !IsDownStatus(e.LastStatus) {
+		return 0
+	}
+	return now.Sub(e.FirstSeenDown)
+}
+
+// DownForSuffix returns " (down for 3h 12m)" while an existing down streak is
+// ongoing, or "" otherwise. Appended to alert messages so repeat failures
+// carry their outage duration; the first failure of a streak has no suffix
+// because FirstSeenDown is only set once the check completes.
+func (e *Endpoint) DownForSuffix() string {
+	d := e.DownFor(clock.Now())
+	if d <= 0 {
+		return ""
+	}
+	return " (down for " + FormatDuration(d) + ")"
+}
+
+// FormatDuration renders a duration compactly with its two most significant
+// units: "2d 4h", "3h 12m", "45m". Anything under a minute is "<1m".
+func FormatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordStatusChange(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	e := Endpoint{LastStatus: "unknown"}
+
+	e.LastStatus = "up"
+	e.RecordStatusChange("unknown", t0)
+	if !e.LastStateChange.Equal(t0) || !e.FirstSeenDown.IsZero() {
+		t.Fatalf("after up: LastStateChange=%v FirstSeenDown=%v", e.LastStateChange, e.FirstSeenDown)
+	}
+
+	t1 := t0.Add(time.Hour)
+	e.LastStatus = "down"
+	e.RecordStatusChange("up", t1)
+	if !e.FirstSeenDown.Equal(t1) || !e.LastStateChange.Equal(t1) {
+		t.Fatalf("after down: LastStateChange=%v FirstSeenDown=%v", e.LastStateChange, e.FirstSeenDown)
+	}
+
+	// Moving between two down statuses changes LastStateChange but keeps the
+	// streak start.
+	t2 := t1.Add(time.Hour)
+	e.LastStatus = "error"
+	e.RecordStatusChange("down", t2)
+	if !e.FirstSeenDown.Equal(t1) || !e.LastStateChange.Equal(t2) {
+		t.Fatalf("after error: LastStateChange=%v FirstSeenDown=%v", e.LastStateChange, e.FirstSeenDown)
+	}
+	if got := e.DownFor(t2.Add(12 * time.Minute)); got != time.Hour+12*time.Minute {
+		t.Fatalf("DownFor=%v", got)
+	}
+
+	t3 := t2.Add(time.Hour)
+	e.LastStatus = "up"
+	e.RecordStatusChange("error", t3)
+	if !e.FirstSeenDown.IsZero() || e.DownFor(t3) != 0 {
+		t.Fatalf("after recovery: FirstSeenDown=%v", e.FirstSeenDown)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{30 * time.Second, "<1m"},
+		{45 * time.Minute, "45m"},
+		{3*time.Hour + 12*time.Minute, "3h 12m"},
+		{52 * time.Hour, "2d 4h"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.in); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package notify sends the monitor's alerts: endpoint failures with
+// remediation hints, and plain notices (startup, pool migrations), by email
+// via Resend (when EMAIL_NOTIFICATIONS is enabled) and to a Slack webhook
+// (when SLACK_WEBHOOK_URL is set). Every alert has a Severity, and each
+// channel only receives alerts at or above its configured minimum.
+package notify
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+var (
+	alertsRaised atomic.Int64
+	emailsSent   atomic.Int64
+)
+
+// AlertsRaised returns how many endpoint alerts have been raised since start,
+// whether or not email sending is enabled.
+func AlertsRaised() int64 { return alertsRaised.Load() }
+
+// EmailsSent returns how many emails Resend has accepted since start.
+func EmailsSent() int64 { return emailsSent.Load() }
+
+// SendEndpointAlert sends a check failure for one endpoint at the severity of
+// its error class (AlertSeverity), to the default channels and the alert
+// routes matching the endpoint's labels, or held for its group (HoldGroup).
+// Shared by the generic API client and every provider handler so alert
+// content stays uniform.
+func SendEndpointAlert(endpoint *collector.Endpoint, message, responseBody string) {
+	alertsRaised.Add(1)
+	sev, text := AlertSeverity(message, responseBody), FormatEndpointAlert(endpoint, message, responseBody)
+	if holdInGroup(endpoint, sev, text) {
+		return
+	}
+	send(sev, text, endpoint.Labels)
+}
+
+// FormatEndpointAlert builds the alert text: endpoint name, message, how long
+// the endpoint has been down, a remediation hint for the error class, the
+// endpoint's runbook link when its note has one, the provider's request ID
+// when it sent one, the returned route as a hop
+// diagram, a dashboard deep link when PUBLIC_URL is set, and the provider
+// response body when there is one.
+func FormatEndpointAlert(endpoint *collector.Endpoint, message, responseBody string) string {
+	text := fmt.Sprintf("[%s] %s%s", endpoint.Name, message, endpoint.DownForSuffix())
+	if hint := RemediationHint(endpoint, message, responseBody); hint != "" {
+		text += "\nHint: " + hint
+	}
+	if note, ok := collector.GetNote(endpoint.Name); ok && note.RunbookURL != "" {
+		text += "\nRunbook: " + note.RunbookURL
+	}
+	if id := endpoint.RequestID(); id != "" {
+		text += "\nRequest ID: " + id
+	}
+	if !endpoint.Route.IsEmpty() {
+		text += "\nRoute:\n" + endpoint.Route.String()
+	}
+	if base := config.GetPublicURL(); base != "" {
+		text += "\nDashboard: " + base + endpoint.DashboardPath()
+	}
+	if responseBody != "" {
+		text += "\nResponse body:\n" + responseBody
+	}
+	return text
+}
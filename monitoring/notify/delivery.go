@@ -0,0 +1,432 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/fsutil"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/redact"
+)
+
+// Retry policy for notifications a channel failed to take: the wait doubles
+// from retryBaseDelay up to retryMaxDelay, and a notification is dropped
+// after maxDeliveryAttempts (about two hours of retries).
+const (
+	maxDeliveryAttempts = 8
+	retryBaseDelay      = 30 * time.Second
+	retryMaxDelay       = 30 * time.Minute
+)
+
+// maxQueuedInMemory bounds the in-memory retry queue; beyond it entries
+// spill to NOTIFY_SPOOL_FILE, or the oldest is dropped without one.
+// maxSpooled bounds the spool the same way.
+const (
+	maxQueuedInMemory = 100
+	maxSpooled        = 10000
+)
+
+// dropWindow is how long a dropped notification keeps the delivery
+// pipeline reported as unhealthy.
+const dropWindow = 24 * time.Hour
+
+// errNotSent is returned by a channel that is switched off (email disabled,
+// dry-run): nothing to retry or count.
+var errNotSent = errors.New("not sent")
+
+// permanentError is a delivery failure a retry can't fix, e.g. a missing API
+// key or a deleted webhook. It is counted and the notification dropped.
+type permanentError struct{ error }
+
+func (e permanentError) Unwrap() error { return e.error }
+
+// queuedDelivery is a notification waiting for a retry on one channel.
+// Channels are looked up by name when retried, so the spool holds no
+// webhook URLs or recipients.
+type queuedDelivery struct {
+	Channel     string    `json:"channel"`
+	Message     string    `json:"message"`
+	Attempts    int       `json:"attempts"`
+	FirstFailed time.Time `json:"firstFailed"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	LastError   string    `json:"lastError"`
+}
+
+// ChannelStatus is one channel's delivery record since start.
+type ChannelStatus struct {
+	Channel  string `json:"channel"`
+	Sent     int64  `json:"sent"`     // delivered, first time or on a retry
+	Retried  int64  `json:"retried"`  // of those, delivered on a retry
+	Failures int64  `json:"failures"` // failed attempts
+	Dropped  int64  `json:"dropped"`  // given up on
+	Queued   int    `json:"queued"`   // waiting for a retry, in memory or spooled
+	// ConsecutiveFailures counts the failed attempts since the last success.
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	LastSent            time.Time `json:"lastSent,omitempty"`
+	LastError           string    `json:"lastError,omitempty"`
+	LastErrorAt         time.Time `json:"lastErrorAt,omitempty"`
+	LastDroppedAt       time.Time `json:"lastDroppedAt,omitempty"`
+}
+
+var (
+	deliveryMu sync.Mutex
+	queue      []queuedDelivery
+	statuses   = map[string]*ChannelStatus{}
+)
+
+// deliver sends message on ch and records the outcome; a failure that a
+// retry may fix queues it for RetryDeliveries.
+func deliver(ch channel, message string) {
+	// Scrubbed before the first attempt so the retry queue and spool file
+	// never hold a credential either.
+	message = redact.String(message)
+	err := ch.send(message)
+	if errors.Is(err, errNotSent) {
+		return
+	}
+	now := clock.Now()
+
+	deliveryMu.Lock()
+	defer deliveryMu.Unlock()
+	st := statusLocked(ch.name)
+	if err == nil {
+		st.recordSent(now, false)
+		return
+	}
+	st.recordFailure(now, err)
+	logs.Printf("%s[NOTIFY]%s %s: delivery failed: %v\n", config.ColorRed, config.ColorReset, ch.name, err)
+	if errors.As(err, &permanentError{}) {
+		st.recordDropped(now)
+		return
+	}
+	enqueueLocked(queuedDelivery{
+		Channel: ch.name, Message: message, Attempts: 1,
+		FirstFailed: now, NextAttempt: now.Add(retryDelay(1)), LastError: err.Error(),
+	}, now)
+}
+
+// retryDelay is the wait after a notification's attempts-th failure.
+func retryDelay(attempts int) time.Duration {
+	d := retryBaseDelay
+	for i := 1; i < attempts && d < retryMaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, retryMaxDelay)
+}
+
+// enqueueLocked queues d in memory, spilling it to the spool when memory is
+// full. Without a spool, or when it fails, the oldest entry is dropped.
+func enqueueLocked(d queuedDelivery, now time.Time) {
+	if len(queue) < maxQueuedInMemory {
+		queue = append(queue, d)
+		return
+	}
+	if path := config.GetNotifySpoolFile(); path != "" {
+		err := spill(path, []queuedDelivery{d})
+		if err == nil {
+			return
+		}
+		logs.Printf("%s[NOTIFY]%s spool %s: %v; dropping the oldest queued notification\n", config.ColorRed, config.ColorReset, path, err)
+	}
+	dropLocked(queue[0], now)
+	queue = append(queue[1:], d)
+}
+
+func dropLocked(d queuedDelivery, now time.Time) {
+	statusLocked(d.Channel).recordDropped(now)
+	logs.Printf("%s[NOTIFY]%s %s: dropped a notification after %d attempts (%s): %.80q\n",
+		config.ColorRed, config.ColorReset, d.Channel, d.Attempts, d.LastError, d.Message)
+}
+
+// RunDeliveryRetries retries queued notifications every interval. It runs
+// for the life of the process.
+func RunDeliveryRetries(interval time.Duration) {
+	ticker := clock.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		RetryDeliveries(clock.Now())
+	}
+}
+
+// RetryDeliveries sends the queued notifications due at now, oldest first,
+// then refills the in-memory queue from the spool. It returns how many were
+// delivered.
+func RetryDeliveries(now time.Time) int {
+	deliveryMu.Lock()
+	var due, waiting []queuedDelivery
+	for _, d := range queue {
+		if d.NextAttempt.After(now) {
+			waiting = append(waiting, d)
+		} else {
+			due = append(due, d)
+		}
+	}
+	queue = waiting
+	deliveryMu.Unlock()
+
+	delivered := 0
+	for _, d := range due {
+		ch, ok := channelByName(d.Channel)
+		var err error
+		if !ok {
+			err = permanentError{errors.New("channel no longer configured")}
+		} else {
+			err = ch.send(d.Message)
+		}
+
+		deliveryMu.Lock()
+		st := statusLocked(d.Channel)
+		switch {
+		case err == nil:
+			st.recordSent(now, true)
+			delivered++
+		case errors.Is(err, errNotSent):
+			// Switched off since; nothing left to deliver.
+		default:
+			st.recordFailure(now, err)
+			d.Attempts++
+			d.LastError = err.Error()
+			if d.Attempts >= maxDeliveryAttempts || errors.As(err, &permanentError{}) {
+				dropLocked(d, now)
+				break
+			}
+			d.NextAttempt = now.Add(retryDelay(d.Attempts))
+			queue = append(queue, d)
+		}
+		deliveryMu.Unlock()
+	}
+
+	deliveryMu.Lock()
+	defer deliveryMu.Unlock()
+	refillLocked()
+	return delivered
+}
+
+// refillLocked moves spooled entries into the in-memory queue while it has
+// room.
+func refillLocked() {
+	path := config.GetNotifySpoolFile()
+	room := maxQueuedInMemory - len(queue)
+	if path == "" || room <= 0 {
+		return
+	}
+	taken, err := takeSpooled(path, room)
+	if err != nil {
+		logs.Printf("%s[NOTIFY]%s spool %s: %v\n", config.ColorRed, config.ColorReset, path, err)
+		return
+	}
+	queue = append(queue, taken...)
+}
+
+// SpillDeliveries writes the in-memory retry queue to NOTIFY_SPOOL_FILE, so
+// a restart retries it; called at shutdown. A no-op without a spool file.
+func SpillDeliveries() {
+	path := config.GetNotifySpoolFile()
+	if path == "" {
+		return
+	}
+	deliveryMu.Lock()
+	defer deliveryMu.Unlock()
+	if len(queue) == 0 {
+		return
+	}
+	if err := spill(path, queue); err != nil {
+		logs.Printf("%s[NOTIFY]%s spool %s: %v; %d queued notifications lost\n", config.ColorRed, config.ColorReset, path, err, len(queue))
+		return
+	}
+	queue = nil
+}
+
+// channelByName returns the configured channel named name: a default one or
+// any alert route's.
+func channelByName(name string) (channel, bool) {
+	all := channels(nil)
+	for _, r := range Routes() {
+		all = append(all, r.channels()...)
+	}
+	for _, ch := range all {
+		if ch.name == name {
+			return ch, true
+		}
+	}
+	return channel{}, false
+}
+
+// DeliveryStatuses returns each channel's delivery record, by name, with
+// the notifications it has queued in memory and in the spool.
+func DeliveryStatuses() []ChannelStatus {
+	deliveryMu.Lock()
+	defer deliveryMu.Unlock()
+
+	queued := queue
+	if path := config.GetNotifySpoolFile(); path != "" {
+		spooled, err := readSpool(path)
+		if err != nil {
+			logs.Printf("%s[NOTIFY]%s spool %s: %v\n", config.ColorRed, config.ColorReset, path, err)
+		}
+		queued = append(append([]queuedDelivery{}, queue...), spooled...)
+	}
+	byName := make(map[string]*ChannelStatus, len(statuses))
+	for name, st := range statuses {
+		c := *st
+		byName[name] = &c
+	}
+	for _, d := range queued {
+		c, ok := byName[d.Channel]
+		if !ok {
+			// Spooled by an earlier process.
+			c = &ChannelStatus{Channel: d.Channel}
+			byName[d.Channel] = c
+		}
+		c.Queued++
+	}
+	out := make([]ChannelStatus, 0, len(byName))
+	for _, c := range byName {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Channel < out[j].Channel })
+	return out
+}
+
+// DeliveryProblems describes what is wrong with notification delivery at
+// now, one line per channel whose last attempt failed or that dropped a
+// notification within a day; nil when notifications are getting through.
+func DeliveryProblems(now time.Time) []string {
+	var out []string
+	for _, st := range DeliveryStatuses() {
+		var msg string
+		if st.ConsecutiveFailures > 0 {
+			msg = fmt.Sprintf("%s: %d failed attempts in a row, last at %s: %s", st.Channel, st.ConsecutiveFailures,
+				st.LastErrorAt.UTC().Format("2006-01-02 15:04 MST"), st.LastError)
+		}
+		if !st.LastDroppedAt.IsZero() && now.Sub(st.LastDroppedAt) < dropWindow {
+			if msg == "" {
+				msg = st.Channel + ": "
+			} else {
+				msg += "; "
+			}
+			msg += fmt.Sprintf("%d notifications dropped, last at %s", st.Dropped, st.LastDroppedAt.UTC().Format("2006-01-02 15:04 MST"))
+		}
+		if msg == "" {
+			continue
+		}
+		if st.Queued > 0 {
+			msg += fmt.Sprintf(" (%d queued for retry)", st.Queued)
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
+func statusLocked(name string) *ChannelStatus {
+	st, ok := statuses[name]
+	if !ok {
+		st = &ChannelStatus{Channel: name}
+		statuses[name] = st
+	}
+	return st
+}
+
+func (st *ChannelStatus) recordSent(now time.Time, retried bool) {
+	st.Sent++
+	if retried {
+		st.Retried++
+	}
+	st.ConsecutiveFailures = 0
+	st.LastSent = now
+}
+
+func (st *ChannelStatus) recordFailure(now time.Time, err error) {
+	st.Failures++
+	st.ConsecutiveFailures++
+	st.LastError = err.Error()
+	st.LastErrorAt = now
+}
+
+func (st *ChannelStatus) recordDropped(now time.Time) {
+	st.Dropped++
+	st.LastDroppedAt = now
+}
+
+// spill appends entries to the spool file, dropping its oldest beyond
+// maxSpooled. Callers hold deliveryMu.
+func spill(path string, entries []queuedDelivery) error {
+	existing, err := readSpool(path)
+	if err != nil {
+		return err
+	}
+	all := append(existing, entries...)
+	if n := len(all) - maxSpooled; n > 0 {
+		now := clock.Now()
+		for _, d := range all[:n] {
+			statusLocked(d.Channel).recordDropped(now)
+		}
+		all = all[n:]
+	}
+	return writeSpool(path, all)
+}
+
+// takeSpooled removes and returns the spool's oldest n entries.
+func takeSpooled(path string, n int) ([]queuedDelivery, error) {
+	all, err := readSpool(path)
+	if err != nil || len(all) == 0 {
+		return nil, err
+	}
+	n = min(n, len(all))
+	if err := writeSpool(path, all[n:]); err != nil {
+		return nil, err
+	}
+	return all[:n], nil
+}
+
+// readSpool reads the spool's entries, oldest first; none when it doesn't
+// exist. Unreadable lines are skipped.
+func readSpool(path string) ([]queuedDelivery, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []queuedDelivery
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for sc.Scan() {
+		var d queuedDelivery
+		if json.Unmarshal(sc.Bytes(), &d) == nil && d.Channel != "" {
+			out = append(out, d)
+		}
+	}
+	return out, sc.Err()
+}
+
+// writeSpool replaces the spool with entries through fsutil.AtomicWriteFile,
+// and removes it when there are none.
+func writeSpool(path string, entries []queuedDelivery) error {
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	for _, d := range entries {
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	}
+	return fsutil.AtomicWriteFile(path, b.Bytes())
+}
@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// resetDeliveries clears the retry queue and delivery statuses before and
+// after a test, and lifts the storm cap.
+func resetDeliveries(t *testing.T) {
+	t.Setenv("ALERT_STORM_MAX", "0")
+	reset := func() {
+		deliveryMu.Lock()
+		queue, statuses = nil, map[string]*ChannelStatus{}
+		deliveryMu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+// flakyWebhook is a fake Slack webhook answering status until it is set to
+// 200.
+func flakyWebhook(t *testing.T, status *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func slackStatus() ChannelStatus {
+	for _, st := range DeliveryStatuses() {
+		if st.Channel == "slack" {
+			return st
+		}
+	}
+	return ChannelStatus{}
+}
+
+func TestDeliveryRetriesUntilSent(t *testing.T) {
+	resetDeliveries(t)
+	var status atomic.Int32
+	status.Store(http.StatusBadGateway)
+	t.Setenv("SLACK_WEBHOOK_URL", flakyWebhook(t, &status).URL)
+	t.Setenv("NOTIFY_SPOOL_FILE", "")
+
+	start := time.Now()
+	Send(SeverityCritical, "pool drained")
+	if st := slackStatus(); st.Failures != 1 || st.Queued != 1 || st.Sent != 0 {
+		t.Fatalf("after failure = %+v", st)
+	}
+	problems := DeliveryProblems(start)
+	if len(problems) != 1 || !strings.Contains(problems[0], "slack: 1 failed attempts in a row") || !strings.Contains(problems[0], "502") {
+		t.Fatalf("problems = %q", problems)
+	}
+
+	if n := RetryDeliveries(start); n != 0 {
+		t.Fatalf("retried %d before the backoff", n)
+	}
+	// Fails again, then waits twice as long.
+	RetryDeliveries(start.Add(retryBaseDelay + time.Second))
+	if n := RetryDeliveries(start.Add(2 * retryBaseDelay)); n != 0 {
+		t.Fatalf("retried %d before the doubled backoff", n)
+	}
+	status.Store(http.StatusOK)
+	if n := RetryDeliveries(start.Add(4 * retryBaseDelay)); n != 1 {
+		t.Fatalf("delivered %d, want 1", n)
+	}
+	if st := slackStatus(); st.Sent != 1 || st.Retried != 1 || st.Failures != 2 || st.Queued != 0 || st.ConsecutiveFailures != 0 {
+		t.Fatalf("after retry = %+v", st)
+	}
+	if problems := DeliveryProblems(start.Add(time.Hour)); problems != nil {
+		t.Fatalf("healthy problems = %q", problems)
+	}
+}
+
+func TestDeliveryDropsPermanentFailures(t *testing.T) {
+	resetDeliveries(t)
+	var status atomic.Int32
+	status.Store(http.StatusNotFound)
+	t.Setenv("SLACK_WEBHOOK_URL", flakyWebhook(t, &status).URL)
+
+	Send(SeverityCritical, "pool drained")
+	st := slackStatus()
+	if st.Dropped != 1 || st.Queued != 0 {
+		t.Fatalf("status = %+v", st)
+	}
+	problems := DeliveryProblems(st.LastDroppedAt.Add(time.Hour))
+	if len(problems) != 1 || !strings.Contains(problems[0], "1 notifications dropped") {
+		t.Fatalf("problems = %q", problems)
+	}
+	if problems := DeliveryProblems(st.LastDroppedAt.Add(dropWindow + time.Hour)); len(problems) != 1 || strings.Contains(problems[0], "dropped") {
+		t.Fatalf("problems after the drop window = %q", problems)
+	}
+}
+
+func TestDeliverySpillsToSpool(t *testing.T) {
+	resetDeliveries(t)
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	t.Setenv("SLACK_WEBHOOK_URL", flakyWebhook(t, &status).URL)
+	spool := filepath.Join(t.TempDir(), "spool.jsonl")
+	t.Setenv("NOTIFY_SPOOL_FILE", spool)
+
+	for i := 0; i < maxQueuedInMemory+2; i++ {
+		Send(SeverityCritical, "alert")
+	}
+	if st := slackStatus(); st.Queued != maxQueuedInMemory+2 || st.Dropped != 0 {
+		t.Fatalf("status = %+v", st)
+	}
+	if spooled, err := readSpool(spool); err != nil || len(spooled) != 2 {
+		t.Fatalf("spool = %d entries (%v)", len(spooled), err)
+	}
+
+	SpillDeliveries()
+	deliveryMu.Lock()
+	inMemory := len(queue)
+	deliveryMu.Unlock()
+	if spooled, _ := readSpool(spool); inMemory != 0 || len(spooled) != maxQueuedInMemory+2 {
+		t.Fatalf("after shutdown spill: %d in memory, %d spooled", inMemory, len(spooled))
+	}
+
+	// The next pass (e.g. after a restart) delivers the spooled ones as
+	// they come back into memory.
+	status.Store(http.StatusOK)
+	RetryDeliveries(time.Now().Add(time.Hour))
+	if n := RetryDeliveries(time.Now().Add(time.Hour)); n != maxQueuedInMemory {
+		t.Fatalf("delivered %d, want %d", n, maxQueuedInMemory)
+	}
+	RetryDeliveries(time.Now().Add(time.Hour))
+	if st := slackStatus(); st.Queued != 0 || st.Sent != maxQueuedInMemory+2 {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 6: 16 * time.Minute, 7: retryMaxDelay, 70: retryMaxDelay} {
+		if got := retryDelay(attempts); got != want {
+			t.Errorf("retryDelay(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestDeliveryRedactsCredentials(t *testing.T) {
+	resetDeliveries(t)
+	var body atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body.Store(string(b))
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("SLACK_WEBHOOK_URL", srv.URL)
+
+	Send(SeverityCritical, `[Odos-X] Get "https://api.example.com/quote?apikey=k-9f8e7d6c": timeout`)
+	got, _ := body.Load().(string)
+	if strings.Contains(got, "k-9f8e7d6c") || !strings.Contains(got, "apikey=[redacted]") {
+		t.Fatalf("slack body = %s, want the key redacted", got)
+	}
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+// groupedAlert is an endpoint alert held until its group is flushed.
+type groupedAlert struct {
+	sev    Severity
+	text   string
+	labels config.Labels
+}
+
+var (
+	groupMu    sync.Mutex
+	heldGroups map[string][]groupedAlert // by BaseName, for groups between HoldGroup and FlushGroup
+)
+
+// HoldGroup makes endpoint alerts for rows with this BaseName wait for
+// FlushGroup, so a check cycle sends one notification per pair instead of one
+// per solver.
+func HoldGroup(baseName string) {
+	groupMu.Lock()
+	defer groupMu.Unlock()
+	if heldGroups == nil {
+		heldGroups = map[string][]groupedAlert{}
+	}
+	if _, ok := heldGroups[baseName]; !ok {
+		heldGroups[baseName] = []groupedAlert{}
+	}
+}
+
+// FlushGroup sends the alerts held for g's BaseName and stops holding them. A
+// single alert goes out as is; several become one notification headed by
+// the group summary (e.g. "… 3 of 6 solvers failing") at the highest of their
+// severities.
+func FlushGroup(g collector.GroupStatus) {
+	groupMu.Lock()
+	pending := heldGroups[g.BaseName]
+	delete(heldGroups, g.BaseName)
+	groupMu.Unlock()
+
+	switch len(pending) {
+	case 0:
+		return
+	case 1:
+		send(pending[0].sev, pending[0].text, pending[0].labels)
+		return
+	}
+	sev := SeverityInfo
+	texts := make([]string, len(pending))
+	for i, a := range pending {
+		if a.sev > sev {
+			sev = a.sev
+		}
+		texts[i] = a.text
+	}
+	text := fmt.Sprintf("[%s] %s (%s)", g.BaseName, g.Summary(), g.Status)
+	if base := config.GetPublicURL(); base != "" {
+		text += "\nDashboard: " + base + g.DashboardPath()
+	}
+	send(sev, text+"\n\n"+strings.Join(texts, "\n\n"), pending[0].labels)
+}
+
+// DropGroup discards the alerts held for baseName and stops holding them,
+// returning how many were dropped. Down-endpoint rechecks use it: the regular
+// cycle still alerts, the recheck only refreshes the dashboard.
+func DropGroup(baseName string) int {
+	groupMu.Lock()
+	defer groupMu.Unlock()
+	n := len(heldGroups[baseName])
+	delete(heldGroups, baseName)
+	return n
+}
+
+// holdInGroup queues an endpoint alert when its BaseName is held.
+func holdInGroup(endpoint *collector.Endpoint, sev Severity, text string) bool {
+	groupMu.Lock()
+	defer groupMu.Unlock()
+	pending, ok := heldGroups[endpoint.BaseName]
+	if !ok {
+		return false
+	}
+	heldGroups[endpoint.BaseName] = append(pending, groupedAlert{sev: sev, text: text, labels: endpoint.Labels})
+	return true
+}
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestFlushGroupSendsOneNotification(t *testing.T) {
+	srv, got := slackRecorder(t)
+	t.Setenv("SLACK_WEBHOOK_URL", srv.URL)
+
+	a := &collector.Endpoint{Name: "Odos-X", BaseName: "X", RouteSolver: "odos"}
+	b := &collector.Endpoint{Name: "KyberSwap-X", BaseName: "X", RouteSolver: "kyberswap"}
+	other := &collector.Endpoint{Name: "Odos-Y", BaseName: "Y", RouteSolver: "odos"}
+
+	HoldGroup("X")
+	SendEndpointAlert(a, "rate limit exceeded", "")
+	SendEndpointAlert(b, "expected balancerv3 source", "")
+	SendEndpointAlert(other, "rate limit exceeded", "") // not held
+	if sent := got(); len(sent) != 1 || !strings.Contains(sent[0], "[Odos-Y]") {
+		t.Fatalf("sent while held = %q, want only the ungrouped alert", sent)
+	}
+
+	FlushGroup(collector.GroupStatus{BaseName: "X", Network: "8453", Status: collector.GroupPartial, Up: 4, Failing: 2, Total: 6})
+	sent := got()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d notifications, want 2", len(sent))
+	}
+	msg := sent[1]
+	if !strings.HasPrefix(msg, "[CRITICAL] [X] X on base: 2 of 6 solvers failing (partial)") ||
+		!strings.Contains(msg, "[Odos-X]") || !strings.Contains(msg, "[KyberSwap-X]") {
+		t.Fatalf("group notification = %q", msg)
+	}
+
+	// A group with one alert sends it unchanged; after the flush alerts go
+	// out directly again.
+	HoldGroup("X")
+	SendEndpointAlert(a, "rate limit exceeded", "")
+	FlushGroup(collector.GroupStatus{BaseName: "X", Status: collector.GroupPartial})
+	SendEndpointAlert(a, "rate limit exceeded", "")
+	sent = got()
+	if len(sent) != 4 || !strings.HasPrefix(sent[2], "[WARNING] [Odos-X]") || !strings.HasPrefix(sent[3], "[WARNING] [Odos-X]") {
+		t.Fatalf("single alerts = %q", sent[2:])
+	}
+}
@@ -0,0 +1,265 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+)
+
+// Hint maps an error class to a remediation hint. A failure matches when its
+// message or provider response body contains any of Patterns
+// (case-insensitive); a pattern ending in a digit only matches where no digit
+// follows, so "http 429" doesn't match "http 4290". Text may use {solver}
+// (upper-cased route solver, as in DELAY_<SOLVER>) and {pool} (expected pool
+// address). Severity ("info", "warning" or "critical") sets the alert
+// severity of the class; empty means critical.
+type Hint struct {
+	Class    string   `json:"class"`
+	Patterns []string `json:"patterns"`
+	Text     string   `json:"hint"`
+	Severity string   `json:"severity,omitempty"`
+}
+
+// DefaultHints is the built-in hints table, checked in order; the first match
+// wins, so narrower classes come first and the critical route classes come
+// before the transport ones. Status codes are matched only in context
+// ("code: 429", "HTTP 429", "status":429), never as bare digits, which
+// amounts and addresses contain.
+var DefaultHints = []Hint{
+	{
+		Class:    "edge_error",
+		Patterns: []string{"provider edge error"},
+		Text:     "the provider's CDN / WAF answered with an error page instead of the API → usually transient; if it persists, check their status page or whether our egress IP is blocked",
+		Severity: "warning",
+	},
+	{
+		Class: "wrong_source",
+		Patterns: []string{
+			"expected balancerv3", "expected balancer_v3", "expected only balancerv3",
+			"expected protocol containing balancer_v3", "unexpected source", "expected source",
+			"does not use balancer v3", "no balancerv3 source",
+		},
+		Text:     "wrong source found → check provider's source whitelist/id mapping for this chain (ignore list, includedSources, protocol name)",
+		Severity: "critical",
+	},
+	{
+		Class:    "hook_quote_mismatch",
+		Patterns: []string{"hook quote mismatch"},
+		Text:     "the provider's quote for a hook-triggering swap differs from the Router query → it likely ignores the hook's dynamic fee (StableSurge surge fee); report it with the pool address",
+		Severity: "critical",
+	},
+	{
+		Class:    "unexpected_spender",
+		Patterns: []string{"unexpected spender"},
+		Text:     "the provider's quote names an allowance target outside the known-good registry → verify the address on a block explorer; if it is a legitimate new router or Permit2 deployment add it to SPENDERS_FILE, otherwise report it to the provider",
+		Severity: "critical",
+	},
+	{
+		Class:    "expected_pool_missing",
+		Patterns: []string{"expected pool"},
+		Text:     "expected pool {pool} not in route → check the provider still indexes it and it still has liquidity (it may have migrated)",
+		Severity: "critical",
+	},
+	{
+		Class:    "rate_limited",
+		Patterns: append(statusPatterns(429), "rate limit", "too many requests"),
+		Text:     "rate limited → raise DELAY_{solver} or check the API key's plan limits",
+		Severity: "warning",
+	},
+	{
+		Class:    "missing_api_key",
+		Patterns: []string{"environment variable not set"},
+		Text:     "API key env var missing → set it in .env / fly secrets and redeploy",
+		Severity: "warning",
+	},
+	{
+		Class:    "unauthorized",
+		Patterns: append(append(statusPatterns(401), statusPatterns(403)...), "unauthorized", "forbidden", "invalid api key"),
+		Text:     "request rejected as unauthorized → check or rotate the {solver} API key",
+		Severity: "warning",
+	},
+	{
+		Class:    "price_impact",
+		Patterns: []string{"price impact"},
+		Text:     "high price impact for the configured swap size → check the pool's liquidity, or lower SwapAmount if TVL has dropped",
+		Severity: "warning",
+	},
+	{
+		Class:    "uncompetitive",
+		Patterns: []string{"out of the top", "marketleadcycles"},
+		Text:     "Balancer V3 routes fine but loses to other sources → compare the pool's price and fees with the winning source; it may need liquidity or a rebalance",
+		Severity: "warning",
+	},
+	{
+		Class:    "spread_widening",
+		Patterns: []string{"quote spread widened"},
+		Text:     "other sources increasingly beat the Balancer-only quote → chart the spread in /api/v1/series and compare the pool's price and fees with the market; it may need liquidity or a rebalance",
+		Severity: "warning",
+	},
+	{
+		Class:    "no_route",
+		Patterns: []string{"no route", "noroutefound", "no best route", "no paths found", "insufficient liquidity", "no swaps found"},
+		Text:     "no route returned → check pool liquidity vs swap amount, or whether the provider de-indexed the pool",
+		Severity: "critical",
+	},
+	{
+		Class:    "network",
+		Patterns: []string{"error sending request", "timeout", "deadline exceeded", "connection refused", "no such host", "error reading response"},
+		Text:     "request did not complete → provider may be down; check their status page, then Check Now",
+		Severity: "warning",
+	},
+	{
+		Class:    "server_error",
+		Patterns: append(statusPatterns(500, 501, 502, 503, 504, 520, 521, 522, 523, 524), "internal server error", "bad gateway", "service unavailable"),
+		Text:     "provider returned 5xx → usually transient on their side; escalate if it persists",
+		Severity: "warning",
+	},
+	{
+		Class:    "response_format",
+		Patterns: []string{"error parsing json", "failed to parse", "graphql error"},
+		Text:     "response format changed → update the provider handler's response types",
+		Severity: "warning",
+	},
+	{
+		Class:    "build",
+		Patterns: []string{"error building url", "error building request body"},
+		Text:     "request could not be built → check endpoint config (network support, pool type mapping) and the URL/body builder",
+		Severity: "warning",
+	},
+	{
+		Class:    "panic",
+		Patterns: []string{"panicked"},
+		Text:     "handler panicked → see the stack trace in the logs and fix the provider handler",
+		Severity: "warning",
+	},
+	{
+		Class:    "wip",
+		Patterns: []string{"integration wip", "support wip", "quantamm not supported"},
+		Text:     "known integration gap (see isWIPCase) → no action until the provider ships support",
+		Severity: "info",
+	},
+}
+
+// statusPatterns returns the ways a message or body states an HTTP status:
+// "status code: 429", "(code 429)", "HTTP 429", "status 429" and the JSON
+// "status" / "statusCode" / "code" fields.
+func statusPatterns(codes ...int) []string {
+	var out []string
+	for _, c := range codes {
+		out = append(out,
+			fmt.Sprintf("code: %d", c), fmt.Sprintf("code %d", c),
+			fmt.Sprintf("http %d", c), fmt.Sprintf("status %d", c),
+			fmt.Sprintf(`"status":%d`, c), fmt.Sprintf(`"statuscode":%d`, c), fmt.Sprintf(`"code":%d`, c),
+		)
+	}
+	return out
+}
+
+var (
+	hintsOnce sync.Once
+	hints     []Hint
+)
+
+// Hints returns the active hints table: entries from the JSON file named by
+// ALERT_HINTS_FILE (if any) followed by DefaultHints. A file entry replaces
+// the default with the same Class. Loaded once per process.
+func Hints() []Hint {
+	hintsOnce.Do(func() {
+		hints = DefaultHints
+		path := config.GetAlertHintsFile()
+		if path == "" {
+			return
+		}
+		loaded, err := loadHints(path)
+		if err != nil {
+			logs.Printf("%s[ERROR]%s: ALERT_HINTS_FILE %s: %v; using built-in hints\n", config.ColorRed, config.ColorReset, path, err)
+			return
+		}
+		hints = mergeHints(loaded, DefaultHints)
+	})
+	return hints
+}
+
+func loadHints(path string) ([]Hint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []Hint
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// mergeHints puts overrides first and drops defaults they replace by Class.
+func mergeHints(overrides, defaults []Hint) []Hint {
+	replaced := make(map[string]bool, len(overrides))
+	for _, h := range overrides {
+		replaced[h.Class] = true
+	}
+	out := append([]Hint{}, overrides...)
+	for _, h := range defaults {
+		if !replaced[h.Class] {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// MatchHint returns the first hint in table whose patterns occur in the
+// message or response body.
+func MatchHint(table []Hint, message, responseBody string) (Hint, bool) {
+	text := strings.ToLower(message + "\n" + responseBody)
+	for _, h := range table {
+		for _, p := range h.Patterns {
+			if p != "" && containsPattern(text, strings.ToLower(p)) {
+				return h, true
+			}
+		}
+	}
+	return Hint{}, false
+}
+
+// containsPattern reports whether p occurs in text; when p ends in a digit,
+// only where no digit follows.
+func containsPattern(text, p string) bool {
+	if !isDigit(p[len(p)-1]) {
+		return strings.Contains(text, p)
+	}
+	for i := 0; ; {
+		j := strings.Index(text[i:], p)
+		if j < 0 {
+			return false
+		}
+		end := i + j + len(p)
+		if end == len(text) || !isDigit(text[end]) {
+			return true
+		}
+		i += j + 1
+	}
+}
+
+func isDigit(b byte) bool { return '0' <= b && b <= '9' }
+
+// RemediationHint returns the rendered hint for an endpoint failure, or "".
+func RemediationHint(endpoint *collector.Endpoint, message, responseBody string) string {
+	h, ok := MatchHint(Hints(), message, responseBody)
+	if !ok {
+		return ""
+	}
+	text := h.Text
+	if endpoint.ExpectedPool == "" {
+		text = strings.ReplaceAll(text, " {pool}", "")
+	}
+	return strings.NewReplacer(
+		"{solver}", strings.ToUpper(endpoint.RouteSolver),
+		"{pool}", endpoint.ExpectedPool,
+	).Replace(text)
+}
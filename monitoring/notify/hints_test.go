@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestMatchHintClasses(t *testing.T) {
+	tests := []struct {
+		message, body, want string
+	}{
+		{"Found source Uniswap_V3, expected Balancer_V3", "", "wrong_source"},
+		{"unexpected source found in route: curve. Expected: balancer-v3-stable", "", "wrong_source"},
+		{"Expected pool 0xabc not found in route", "", "expected_pool_missing"},
+		{"Error parsing JSON: invalid character", `{"message":"Too Many Requests"}`, "rate_limited"},
+		{"ZEROX_API_KEY environment variable not set", "", "missing_api_key"},
+		{"Error sending request: context deadline exceeded", "", "network"},
+		{"No Routes Found", "", "no_route"},
+		{"provider handler panicked: boom", "", "panic"},
+		{"Provider edge error: HTTP 403 text/html response: Just a moment...", "", "edge_error"},
+		{"Quote spread widened: market price beat the Balancer-only quote by 40.0 bps on average over the last 6 checks, up from 5.0 bps", "", "spread_widening"},
+		{"Hook quote mismatch: provider quoted 10100, Router query 10000 (100.0 bps apart, tolerance 10 bps)", "", "hook_quote_mismatch"},
+		{"Unexpected spender 0x1234 from kyberswap on base: not in the known-good spender registry", "", "unexpected_spender"},
+		{"Rule failed: marketLeadCycles: market price beat the Balancer-only quote on 3 consecutive checks (limit 3), by 12.0 bps on the latest", "", "uncompetitive"},
+	}
+	for _, tt := range tests {
+		h, ok := MatchHint(DefaultHints, tt.message, tt.body)
+		if !ok || h.Class != tt.want {
+			t.Errorf("MatchHint(%q) = %q, %v; want %q", tt.message, h.Class, ok, tt.want)
+		}
+	}
+	if _, ok := MatchHint(DefaultHints, "Ok", ""); ok {
+		t.Error("expected no hint for a healthy message")
+	}
+}
+
+// Status codes inside amounts and addresses must not turn a critical route
+// failure into a rate limit or auth warning.
+func TestMatchHintIgnoresDigitsInAmounts(t *testing.T) {
+	tests := []struct {
+		message, body, want string
+	}{
+		{"Found DEX UniswapV3, expected BalancerV3", `{"outAmount":"998742912"}`, "wrong_source"},
+		{"expected pool 0x4031c2f5e4015e3c3b0ba41f0b2e0ad6e1b3d1f7 not found in route", `{"pool":"0x4031c2f5"}`, "expected_pool_missing"},
+		{"Hook quote mismatch: provider quoted 1000429113, Router query 1000401403 (0.3 bps apart, tolerance 0.1 bps)", "", "hook_quote_mismatch"},
+		{"No Routes Found", `{"amountIn":"4290000","gas":"403117"}`, "no_route"},
+		{"Error parsing JSON: invalid character", `{"statusCode":4290}`, "response_format"},
+		{"unexpected status code: 429", "", "rate_limited"},
+		{"HTTP 403: forbidden", "", "unauthorized"},
+		{"Error parsing JSON: invalid character", `{"statusCode":401,"error":"x"}`, "unauthorized"},
+		{"unexpected status code: 502", "", "server_error"},
+	}
+	for _, tt := range tests {
+		h, ok := MatchHint(DefaultHints, tt.message, tt.body)
+		if !ok || h.Class != tt.want {
+			t.Errorf("MatchHint(%q, %q) = %q, %v; want %q", tt.message, tt.body, h.Class, ok, tt.want)
+		}
+	}
+}
+
+func TestMergeHintsOverridesByClass(t *testing.T) {
+	merged := mergeHints([]Hint{{Class: "rate_limited", Patterns: []string{"slow down"}, Text: "custom"}}, DefaultHints)
+	if len(merged) != len(DefaultHints) || merged[0].Text != "custom" {
+		t.Fatalf("merged = %+v", merged[:2])
+	}
+	if _, ok := MatchHint(merged, "429", ""); ok {
+		// 429 was only a pattern of the replaced default; other classes must not claim it.
+		t.Fatal("overridden class patterns should be gone")
+	}
+}
+
+func TestFormatEndpointAlertIncludesHint(t *testing.T) {
+	e := &collector.Endpoint{Name: "KyberSwap-X", RouteSolver: "kyberswap", ExpectedPool: "0xpool"}
+	got := FormatEndpointAlert(e, "rate limit exceeded", "")
+	if !strings.Contains(got, "Hint: rate limited → raise DELAY_KYBERSWAP") {
+		t.Fatalf("alert = %q", got)
+	}
+}
+
+func TestFormatEndpointAlertIncludesRequestID(t *testing.T) {
+	e := &collector.Endpoint{Name: "KyberSwap-X", RouteSolver: "kyberswap", HTTPStatus: 500, HTTPHeaders: map[string]string{"X-Request-Id": "req-7"}}
+	if got := FormatEndpointAlert(e, "boom", ""); !strings.Contains(got, "\nRequest ID: req-7") {
+		t.Fatalf("alert = %q", got)
+	}
+}
+
+func TestFormatEndpointAlertIncludesRoute(t *testing.T) {
+	e := &collector.Endpoint{Name: "KyberSwap-X", RouteSolver: "kyberswap", Route: collector.RouteGraph{Paths: []collector.RoutePath{{
+		Hops: []collector.RouteHop{{TokenIn: "0xa", TokenOut: "0xb", Venues: []collector.RouteVenue{{Pool: "0xother", Exchange: "curve"}}}},
+	}}}}
+	if got := FormatEndpointAlert(e, "expected pool 0xpool not found in route", ""); !strings.Contains(got, "\nRoute:\n0xa → 0xother(curve) → 0xb") {
+		t.Fatalf("alert = %q", got)
+	}
+}
+
+func TestFormatEndpointAlertIncludesRunbook(t *testing.T) {
+	collector.SetNote(collector.Note{Endpoint: "KyberSwap-X", Text: "needs whitelisting", RunbookURL: "https://wiki.example.com/kyber"})
+	t.Cleanup(func() { collector.SetNotes(nil) })
+
+	e := &collector.Endpoint{Name: "KyberSwap-X", RouteSolver: "kyberswap"}
+	if got := FormatEndpointAlert(e, "no route", ""); !strings.Contains(got, "\nRunbook: https://wiki.example.com/kyber") {
+		t.Fatalf("alert = %q", got)
+	}
+	if got := FormatEndpointAlert(&collector.Endpoint{Name: "Odos-X"}, "no route", ""); strings.Contains(got, "Runbook") {
+		t.Fatalf("alert without a note = %q", got)
+	}
+}
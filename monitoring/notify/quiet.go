@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+)
+
+// QuietHours is a daily window, in a time zone, during which non-critical
+// alerts are held and sent as one digest when the window ends. Start and End
+// are minutes after midnight; a window with End before Start spans midnight.
+type QuietHours struct {
+	Start, End int
+	Location   *time.Location
+}
+
+// ParseQuietHours reads a window such as "22:00-07:00" in the IANA zone tz
+// (UTC when empty).
+func ParseQuietHours(window, tz string) (QuietHours, error) {
+	q := QuietHours{Location: time.UTC}
+	if tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return QuietHours{}, fmt.Errorf("time zone: %w", err)
+		}
+		q.Location = loc
+	}
+	from, to, ok := strings.Cut(window, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("window %q: want HH:MM-HH:MM", window)
+	}
+	var err error
+	if q.Start, err = parseClock(from); err != nil {
+		return QuietHours{}, err
+	}
+	if q.End, err = parseClock(to); err != nil {
+		return QuietHours{}, err
+	}
+	if q.Start == q.End {
+		return QuietHours{}, fmt.Errorf("window %q is empty", window)
+	}
+	return q, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("time %q: want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether t falls inside the window.
+func (q QuietHours) Contains(t time.Time) bool {
+	t = t.In(q.Location)
+	m := t.Hour()*60 + t.Minute()
+	if q.Start < q.End {
+		return m >= q.Start && m < q.End
+	}
+	return m >= q.Start || m < q.End
+}
+
+// NextEnd returns the first end of the window after t.
+func (q QuietHours) NextEnd(t time.Time) time.Time {
+	t = t.In(q.Location)
+	end := time.Date(t.Year(), t.Month(), t.Day(), q.End/60, q.End%60, 0, 0, q.Location)
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// heldAlert is a notice waiting for the digest.
+type heldAlert struct {
+	sev    Severity
+	text   string
+	labels config.Labels // the endpoint's, for alert routes
+}
+
+var (
+	quietMu     sync.Mutex
+	held        []heldAlert
+	digestTimer clock.Timer
+)
+
+// activeQuietHours returns the configured window, or false when QUIET_HOURS
+// is unset or invalid (reported, then ignored).
+func activeQuietHours() (QuietHours, bool) {
+	window := config.GetQuietHours()
+	if window == "" {
+		return QuietHours{}, false
+	}
+	q, err := ParseQuietHours(window, config.GetQuietHoursTimezone())
+	if err != nil {
+		logs.Printf("%s[ERROR]%s: QUIET_HOURS ignored: %v\n", config.ColorRed, config.ColorReset, err)
+		return QuietHours{}, false
+	}
+	return q, true
+}
+
+// holdForDigest queues text when inside quiet hours and schedules the digest
+// for the end of the window. Held alerts are in memory only; a restart
+// during quiet hours drops them.
+func holdForDigest(sev Severity, text string, labels config.Labels) bool {
+	q, ok := activeQuietHours()
+	if !ok {
+		return false
+	}
+	t := clock.Now()
+	if !q.Contains(t) {
+		return false
+	}
+
+	quietMu.Lock()
+	defer quietMu.Unlock()
+	held = append(held, heldAlert{sev: sev, text: text, labels: labels})
+	if digestTimer == nil {
+		digestTimer = clock.AfterFunc(q.NextEnd(t).Sub(t), SendDigest)
+	}
+	return true
+}
+
+// SendDigest sends the alerts held during quiet hours, one message per
+// channel (alert routes included) holding only the alerts that would have
+// reached it.
+func SendDigest() {
+	quietMu.Lock()
+	pending := held
+	held = nil
+	if digestTimer != nil {
+		digestTimer.Stop()
+		digestTimer = nil
+	}
+	quietMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	deliverPerChannel(pending, func(alerts []heldAlert) string {
+		texts := make([]string, len(alerts))
+		for i, a := range alerts {
+			texts[i] = a.text
+		}
+		return fmt.Sprintf("Quiet hours digest: %d alerts\n\n%s", len(texts), strings.Join(texts, "\n\n"))
+	})
+}
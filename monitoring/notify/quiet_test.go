@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/clock"
+)
+
+// fakeClock installs a clock.Fake reading at for the test.
+func fakeClock(t *testing.T, at time.Time) *clock.Fake {
+	t.Helper()
+	c := clock.NewFake(at)
+	clock.Use(c)
+	t.Cleanup(func() { clock.Use(clock.Real) })
+	return c
+}
+
+func TestQuietHoursAcrossMidnight(t *testing.T) {
+	q, err := ParseQuietHours("22:00-07:00", "America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ny := q.Location
+	for _, tt := range []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2025, 3, 1, 23, 30, 0, 0, ny), true},
+		{time.Date(2025, 3, 2, 6, 59, 0, 0, ny), true},
+		{time.Date(2025, 3, 2, 7, 0, 0, 0, ny), false},
+		{time.Date(2025, 3, 2, 12, 0, 0, 0, ny), false},
+		{time.Date(2025, 3, 2, 3, 30, 0, 0, time.UTC), true}, // 22:30 in New York
+	} {
+		if got := q.Contains(tt.at); got != tt.want {
+			t.Errorf("Contains(%s) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+	if end := q.NextEnd(time.Date(2025, 3, 1, 23, 30, 0, 0, ny)); !end.Equal(time.Date(2025, 3, 2, 7, 0, 0, 0, ny)) {
+		t.Errorf("NextEnd = %s", end)
+	}
+
+	for _, bad := range []string{"22:00", "22:00-22:00", "25:00-07:00"} {
+		if _, err := ParseQuietHours(bad, ""); err == nil {
+			t.Errorf("ParseQuietHours(%q) accepted", bad)
+		}
+	}
+}
+
+func TestQuietHoursHoldNonCriticalForDigest(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg struct{ Text string }
+		json.NewDecoder(r.Body).Decode(&msg)
+		got = append(got, msg.Text)
+	}))
+	defer srv.Close()
+	t.Setenv("SLACK_WEBHOOK_URL", srv.URL)
+	t.Setenv("QUIET_HOURS", "22:00-07:00")
+	c := fakeClock(t, time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC))
+	t.Cleanup(func() { SendDigest() })
+
+	Send(SeverityWarning, "rate limited")
+	Send(SeverityCritical, "wrong source")
+	Send(SeverityInfo, "Service starting")
+	if len(got) != 1 || got[0] != "[CRITICAL] wrong source" {
+		t.Fatalf("sent during quiet hours = %q, want only the critical", got)
+	}
+
+	// The digest goes out when the window ends.
+	c.Advance(7*time.Hour + 59*time.Minute)
+	if len(got) != 1 {
+		t.Fatalf("digest sent before the window ended: %q", got[1:])
+	}
+	c.Advance(time.Minute)
+	if len(got) != 2 || !strings.HasPrefix(got[1], "Quiet hours digest: 2 alerts") ||
+		!strings.Contains(got[1], "[WARNING] rate limited") || !strings.Contains(got[1], "[INFO] Service starting") {
+		t.Fatalf("digest = %q", got[1:])
+	}
+	SendDigest()
+	if len(got) != 2 {
+		t.Fatal("empty digest was sent")
+	}
+}
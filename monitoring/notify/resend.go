@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+
+	"github.com/resend/resend-go/v2"
+)
+
+// defaultEmailRecipients receive every email alert at or above
+// EMAIL_MIN_SEVERITY; alert routes add their own recipients.
+var defaultEmailRecipients = []string{"john@balancerlabs.dev"}
+
+// SendEmail emails message to the default recipients via Resend, once;
+// alerts go through the retry queue instead.
+func SendEmail(message string) {
+	if err := sendEmailTo(defaultEmailRecipients, message); err != nil && !errors.Is(err, errNotSent) {
+		logs.Println("Error sending email:", err)
+	}
+}
+
+// sendEmailTo emails message to the given recipients via Resend when
+// EMAIL_NOTIFICATIONS is enabled; errNotSent when it isn't (or in dry-run).
+// A missing API key is a permanent failure, not worth retrying.
+func sendEmailTo(to []string, message string) error {
+	if config.GetDryRunEnabled() {
+		logs.Printf("%s[DRY RUN]%s: Email not sent: %s\n", config.ColorYellow, config.ColorReset, message)
+		return errNotSent
+	}
+
+	// Check if email sending is enabled
+	if !config.GetEmailNotificationsEnabled() {
+		logs.Printf("%s[INFO]%s: Email sending is disabled\n", config.ColorYellow, config.ColorReset)
+		return errNotSent
+	}
+
+	apiKey := config.GetResendAPIKey()
+	if apiKey == "" {
+		return permanentError{errors.New("RESEND_API_KEY environment variable not set")}
+	}
+
+	// Set global HTTP transport to skip certificate verification
+	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: true,
+	}
+
+	client := resend.NewClient(apiKey)
+
+	params := &resend.SendEmailRequest{
+		From:    "onboarding@resend.dev",
+		To:      to,
+		Subject: "Aggregator Monitor",
+		Html:    "<p>" + message + "</p>",
+	}
+
+	sent, err := client.Emails.Send(params)
+	if err != nil {
+		return err
+	}
+	emailsSent.Add(1)
+	logs.Println("Email sent successfully:", sent)
+	return nil
+}
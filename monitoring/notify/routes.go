@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+// Route sends alerts about endpoints whose labels carry every pair in Match
+// (e.g. {"team": "integrations"}) to extra destinations: a Slack webhook,
+// email recipients, or both. Routes add to the default channels rather than
+// replace them; MinSeverity (empty means every alert) applies to the route's
+// own destinations. Plain notices carry no labels and are never routed.
+type Route struct {
+	Name            string        `json:"name"`
+	Match           config.Labels `json:"match"`
+	SlackWebhookURL string        `json:"slackWebhookUrl,omitempty"`
+	Email           []string      `json:"email,omitempty"`
+	MinSeverity     string        `json:"minSeverity,omitempty"`
+}
+
+var (
+	routesOnce sync.Once
+	routes     []Route
+)
+
+// Routes returns the alert routes from the JSON file named by
+// ALERT_ROUTES_FILE, or none. Loaded once per process.
+func Routes() []Route {
+	routesOnce.Do(func() {
+		path := config.GetAlertRoutesFile()
+		if path == "" {
+			return
+		}
+		loaded, err := loadRoutes(path)
+		if err != nil {
+			logs.Printf("%s[ERROR]%s: ALERT_ROUTES_FILE %s: %v; alerts go to the default channels only\n", config.ColorRed, config.ColorReset, path, err)
+			return
+		}
+		routes = loaded
+	})
+	return routes
+}
+
+// loadRoutes reads and validates a routes file. Every route needs a name, a
+// non-empty label selector and at least one destination.
+func loadRoutes(path string) ([]Route, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []Route
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	for i, r := range out {
+		switch {
+		case r.Name == "":
+			return nil, fmt.Errorf("route %d: name is required", i+1)
+		case len(r.Match) == 0:
+			return nil, fmt.Errorf("route %s: match is required", r.Name)
+		case r.SlackWebhookURL == "" && len(r.Email) == 0:
+			return nil, fmt.Errorf("route %s: slackWebhookUrl or email is required", r.Name)
+		}
+		if err := r.Match.Validate(); err != nil {
+			return nil, fmt.Errorf("route %s: %w", r.Name, err)
+		}
+		if r.MinSeverity != "" {
+			if _, ok := ParseSeverity(r.MinSeverity); !ok {
+				return nil, fmt.Errorf("route %s: unknown minSeverity %q", r.Name, r.MinSeverity)
+			}
+		}
+	}
+	return out, nil
+}
+
+// routeChannels returns the destinations of every route in table matching
+// labels.
+func routeChannels(table []Route, labels config.Labels) []channel {
+	var out []channel
+	for _, r := range table {
+		if len(r.Match) == 0 || !labels.Matches(r.Match) {
+			continue
+		}
+		out = append(out, r.channels()...)
+	}
+	return out
+}
+
+// channels returns the route's destinations.
+func (r Route) channels() []channel {
+	var out []channel
+	floor := minSeverity("route "+r.Name, r.MinSeverity)
+	if url := r.SlackWebhookURL; url != "" {
+		out = append(out, channel{
+			name: "route " + r.Name + " slack",
+			min:  floor,
+			send: func(message string) error { return postSlack(url, message) },
+		})
+	}
+	if to := r.Email; len(to) > 0 {
+		out = append(out, channel{
+			name: "route " + r.Name + " email",
+			min:  floor,
+			send: func(message string) error { return sendEmailTo(to, message) },
+		})
+	}
+	return out
+}
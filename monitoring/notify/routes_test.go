@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestLoadRoutesValidates(t *testing.T) {
+	dir := t.TempDir()
+	for name, tc := range map[string]struct {
+		body    string
+		wantErr bool
+	}{
+		"ok":             {`[{"name":"integrations","match":{"team":"integrations"},"slackWebhookUrl":"https://hooks.example/x","minSeverity":"warning"}]`, false},
+		"no match":       {`[{"name":"all","slackWebhookUrl":"https://hooks.example/x"}]`, true},
+		"no destination": {`[{"name":"x","match":{"team":"integrations"}}]`, true},
+		"bad key":        {`[{"name":"x","match":{"Team":"integrations"},"email":["a@example.com"]}]`, true},
+		"bad severity":   {`[{"name":"x","match":{"team":"a"},"email":["a@example.com"],"minSeverity":"loud"}]`, true},
+	} {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(tc.body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadRoutes(path); (err != nil) != tc.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", name, err, tc.wantErr)
+		}
+	}
+}
+
+// slackRecorder is a fake Slack webhook collecting posted texts.
+func slackRecorder(t *testing.T) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg struct{ Text string }
+		json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		got = append(got, msg.Text)
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), got...)
+	}
+}
+
+func TestEndpointAlertsFollowLabelRoutes(t *testing.T) {
+	main, mainGot := slackRecorder(t)
+	team, teamGot := slackRecorder(t)
+	t.Setenv("SLACK_WEBHOOK_URL", main.URL)
+	t.Setenv("QUIET_HOURS", "22:00-07:00")
+	routesOnce.Do(func() {})
+	routes = []Route{{Name: "integrations", Match: config.Labels{"team": "integrations"}, SlackWebhookURL: team.URL, MinSeverity: "critical"}}
+	c := fakeClock(t, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(func() {
+		routes = nil
+		SendDigest()
+	})
+
+	labelled := &collector.Endpoint{Name: "Odos-X", RouteSolver: "odos", Labels: config.Labels{"team": "integrations"}}
+	other := &collector.Endpoint{Name: "Odos-Y", RouteSolver: "odos", Labels: config.Labels{"team": "core"}}
+	SendEndpointAlert(labelled, "expected balancerv3 source", "")
+	SendEndpointAlert(other, "expected balancerv3 source", "")
+	SendEndpointAlert(labelled, "rate limit exceeded", "") // warning: below the route's minimum
+	Send(SeverityCritical, "Discovery goroutine panicked")
+
+	if got := mainGot(); len(got) != 4 {
+		t.Fatalf("default channel got %d alerts, want 4: %q", len(got), got)
+	}
+	got := teamGot()
+	if len(got) != 1 || !strings.Contains(got[0], "[Odos-X]") {
+		t.Fatalf("route got %q, want only the critical Odos-X alert", got)
+	}
+
+	// Held alerts reach the route's channel in the digest too.
+	routes[0].MinSeverity = ""
+	c.Set(time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC))
+	SendEndpointAlert(labelled, "rate limit exceeded", "")
+	SendEndpointAlert(other, "rate limit exceeded", "")
+	SendDigest()
+	if got := mainGot(); len(got) != 5 || !strings.HasPrefix(got[4], "Quiet hours digest: 2 alerts") {
+		t.Fatalf("default digest = %q", got[4:])
+	}
+	if got := teamGot(); len(got) != 2 || !strings.HasPrefix(got[1], "Quiet hours digest: 1 alerts") || !strings.Contains(got[1], "[Odos-X]") {
+		t.Fatalf("route digest = %q", got[1:])
+	}
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+// Severity ranks an alert. Each channel sends alerts at or above its minimum
+// (EMAIL_MIN_SEVERITY, SLACK_MIN_SEVERITY).
+type Severity int
+
+// Severities, lowest first.
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "critical"
+	}
+}
+
+// ParseSeverity reads "info", "warning" or "critical", case-insensitively.
+func ParseSeverity(s string) (Severity, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "info":
+		return SeverityInfo, true
+	case "warning", "warn":
+		return SeverityWarning, true
+	case "critical":
+		return SeverityCritical, true
+	default:
+		return SeverityCritical, false
+	}
+}
+
+// AlertSeverity classifies an endpoint failure by its hint class (see
+// Hint.Severity): e.g. wrong source is critical, rate limiting a warning and
+// a known integration gap info. Failures no hint matches are critical.
+func AlertSeverity(message, responseBody string) Severity {
+	h, ok := MatchHint(Hints(), message, responseBody)
+	if !ok || h.Severity == "" {
+		return SeverityCritical
+	}
+	sev, _ := ParseSeverity(h.Severity)
+	return sev
+}
+
+// minSeverity parses a channel's minimum from its env value. Unset means
+// every alert; an unknown value is reported and treated as info so nothing
+// is silently dropped.
+func minSeverity(channel, value string) Severity {
+	if value == "" {
+		return SeverityInfo
+	}
+	sev, ok := ParseSeverity(value)
+	if !ok {
+		logs.Printf("%s[ERROR]%s: invalid %s minimum severity %q; sending every alert\n", config.ColorRed, config.ColorReset, channel, value)
+		return SeverityInfo
+	}
+	return sev
+}
+
+// channel is one alert destination and the lowest severity it receives.
+// name identifies it when grouping the quiet hours digest, in delivery
+// statuses, and when a queued retry looks it up again (channelByName).
+type channel struct {
+	name string
+	min  Severity
+	send func(message string) error
+}
+
+// channels returns the destinations for an alert about an endpoint with the
+// given labels (nil for plain notices): the default channels, then those of
+// every matching alert route. Email is always listed; sendEmailTo itself
+// honours EMAIL_NOTIFICATIONS.
+func channels(labels config.Labels) []channel {
+	out := []channel{{
+		name: "email",
+		min:  minSeverity("email", config.GetEmailMinSeverity()),
+		send: func(message string) error { return sendEmailTo(defaultEmailRecipients, message) },
+	}}
+	if url := config.GetSlackWebhookURL(); url != "" {
+		out = append(out, channel{
+			name: "slack",
+			min:  minSeverity("Slack", config.GetSlackMinSeverity()),
+			send: func(message string) error { return postSlack(url, message) },
+		})
+	}
+	if len(labels) > 0 {
+		out = append(out, routeChannels(Routes(), labels)...)
+	}
+	return out
+}
+
+// Send delivers a notice to every default channel whose minimum severity it
+// meets, queueing it for a retry where that fails (deliver). Below critical,
+// notices raised during quiet hours are held for the digest sent when they
+// end; beyond ALERT_STORM_MAX per window, notices are held for one rollup.
+func Send(sev Severity, message string) {
+	send(sev, message, nil)
+}
+
+// muted drops every alert and notice (SetMuted).
+var muted atomic.Bool
+
+// SetMuted turns every channel off, e.g. on a regional worker, whose results
+// the central instance alerts on. Muted alerts still count in AlertsRaised.
+func SetMuted(m bool) {
+	muted.Store(m)
+}
+
+// send is Send for an alert about an endpoint with the given labels, which
+// also goes to the matching alert routes.
+func send(sev Severity, message string, labels config.Labels) {
+	if muted.Load() {
+		return
+	}
+	text := fmt.Sprintf("[%s] %s", strings.ToUpper(sev.String()), message)
+	if sev < SeverityCritical && holdForDigest(sev, text, labels) {
+		return
+	}
+	if holdForStorm(sev, text, labels) {
+		return
+	}
+	for _, ch := range channels(labels) {
+		if sev >= ch.min {
+			deliver(ch, text)
+		}
+	}
+}
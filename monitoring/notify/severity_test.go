@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAlertSeverity(t *testing.T) {
+	tests := []struct {
+		message string
+		want    Severity
+	}{
+		{"Found source Uniswap_V3, expected Balancer_V3", SeverityCritical},
+		{"Status code: 429 Too Many Requests", SeverityWarning},
+		{"Odos QuantAMM integration WIP", SeverityInfo},
+		{"Boosted path: route A → B → C, want A → wA → wC → C", SeverityCritical},
+		{"Found DEX UniswapV3, expected BalancerV3 (quoted 1000429113)", SeverityCritical},
+		{"expected pool 0x4031c2f5 not found in route", SeverityCritical},
+	}
+	for _, tt := range tests {
+		if got := AlertSeverity(tt.message, ""); got != tt.want {
+			t.Errorf("AlertSeverity(%q) = %s, want %s", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestSendRoutesByChannelMinimum(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg struct{ Text string }
+		json.NewDecoder(r.Body).Decode(&msg)
+		got = append(got, msg.Text)
+	}))
+	defer srv.Close()
+	t.Setenv("SLACK_WEBHOOK_URL", srv.URL)
+	t.Setenv("SLACK_MIN_SEVERITY", "warning")
+
+	Send(SeverityInfo, "Service starting")
+	Send(SeverityWarning, "rate limited")
+	Send(SeverityCritical, "wrong source")
+
+	if len(got) != 2 || got[0] != "[WARNING] rate limited" || !strings.HasPrefix(got[1], "[CRITICAL]") {
+		t.Fatalf("Slack messages = %q", got)
+	}
+
+	SetMuted(true)
+	t.Cleanup(func() { SetMuted(false) })
+	Send(SeverityCritical, "wrong source")
+	if len(got) != 2 {
+		t.Fatalf("muted Send delivered %q", got[2:])
+	}
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+var slackClient = &http.Client{Timeout: 10 * time.Second}
+
+// SendSlack posts message to a Slack incoming webhook (SLACK_WEBHOOK_URL),
+// once; alerts go through the retry queue instead.
+func SendSlack(webhookURL, message string) {
+	if err := postSlack(webhookURL, message); err != nil && !errors.Is(err, errNotSent) {
+		logs.Printf("%s[ERROR]%s: sending Slack message: %v\n", config.ColorRed, config.ColorReset, err)
+	}
+}
+
+// postSlack posts message to a Slack incoming webhook; errNotSent in
+// dry-run. A 4xx other than 429 means the webhook is gone or the payload is
+// refused, a permanent failure.
+func postSlack(webhookURL, message string) error {
+	if config.GetDryRunEnabled() {
+		logs.Printf("%s[DRY RUN]%s: Slack message not sent: %s\n", config.ColorYellow, config.ColorReset, message)
+		return errNotSent
+	}
+
+	body, _ := json.Marshal(map[string]string{"text": message})
+	resp, err := slackClient.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return permanentError{fmt.Errorf("webhook returned %s", resp.Status)}
+	default:
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+}
@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/collector"
+)
+
+// maxRollupLines bounds the endpoints a storm rollup lists.
+const maxRollupLines = 50
+
+var (
+	stormMu sync.Mutex
+	// stormSent holds when each notification inside the current window went
+	// out, oldest first.
+	stormSent  []time.Time
+	stormHeld  []heldAlert
+	stormTimer clock.Timer
+)
+
+// holdForStorm counts a notification against ALERT_STORM_MAX per
+// ALERT_STORM_WINDOW and, once the cap is reached, holds it for the rollup
+// sent when the oldest counted notification leaves the window. It reports
+// whether the notification was held.
+func holdForStorm(sev Severity, text string, labels config.Labels) bool {
+	limit, window := config.GetAlertStorm()
+	if limit <= 0 {
+		return false
+	}
+	t := clock.Now()
+
+	stormMu.Lock()
+	defer stormMu.Unlock()
+	pruneStormLocked(t, window)
+	if len(stormSent) < limit && len(stormHeld) == 0 {
+		stormSent = append(stormSent, t)
+		return false
+	}
+	stormHeld = append(stormHeld, heldAlert{sev: sev, text: text, labels: labels})
+	if stormTimer == nil {
+		wait := window
+		if len(stormSent) > 0 {
+			wait = stormSent[0].Add(window).Sub(t)
+		}
+		stormTimer = clock.AfterFunc(wait, SendStormRollup)
+	}
+	return true
+}
+
+// pruneStormLocked forgets notifications sent before the window ending at t.
+func pruneStormLocked(t time.Time, window time.Duration) {
+	i := 0
+	for i < len(stormSent) && !stormSent[i].After(t.Add(-window)) {
+		i++
+	}
+	stormSent = stormSent[i:]
+}
+
+// SendStormRollup sends the notifications held by the storm cap as one
+// message per channel at their highest severity, listing the affected
+// endpoints rather than every alert, and counts it against the cap.
+func SendStormRollup() {
+	stormMu.Lock()
+	pending := stormHeld
+	stormHeld = nil
+	if stormTimer != nil {
+		stormTimer.Stop()
+		stormTimer = nil
+	}
+	if len(pending) > 0 {
+		stormSent = append(stormSent, clock.Now())
+	}
+	stormMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	limit, window := config.GetAlertStorm()
+	deliverPerChannel(pending, func(alerts []heldAlert) string {
+		sev := SeverityInfo
+		for _, a := range alerts {
+			sev = max(sev, a.sev)
+		}
+		return fmt.Sprintf("[%s] Alert storm: %d more alerts after %d in %s, rolled up\n\n%s", strings.ToUpper(sev.String()),
+			len(alerts), limit, collector.FormatDuration(window), rollupLines(alerts))
+	})
+}
+
+// rollupLines lists the alerts by their first line ("[endpoint] message"),
+// most frequent first, with a count for repeats.
+func rollupLines(alerts []heldAlert) string {
+	counts := map[string]int{}
+	var order []string
+	for _, a := range alerts {
+		line, _, _ := strings.Cut(a.text, "\n")
+		if counts[line] == 0 {
+			order = append(order, line)
+		}
+		counts[line]++
+	}
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+
+	var b strings.Builder
+	for i, line := range order {
+		if i == maxRollupLines {
+			fmt.Fprintf(&b, "… and %d more\n", len(order)-maxRollupLines)
+			break
+		}
+		if n := counts[line]; n > 1 {
+			fmt.Fprintf(&b, "%s (×%d)\n", line, n)
+		} else {
+			b.WriteString(line + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// deliverPerChannel sends one message per channel (alert routes included)
+// built by format from the alerts that would have reached it, as the quiet
+// hours digest and the storm rollup do.
+func deliverPerChannel(alerts []heldAlert, format func([]heldAlert) string) {
+	type batch struct {
+		ch     channel
+		alerts []heldAlert
+	}
+	var batches []*batch
+	byName := map[string]*batch{}
+	for _, a := range alerts {
+		for _, ch := range channels(a.labels) {
+			if a.sev < ch.min {
+				continue
+			}
+			b, ok := byName[ch.name]
+			if !ok {
+				b = &batch{ch: ch}
+				byName[ch.name] = b
+				batches = append(batches, b)
+			}
+			b.alerts = append(b.alerts, a)
+		}
+	}
+	for _, b := range batches {
+		deliver(b.ch, format(b.alerts))
+	}
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestStormCapRollsUpAlerts(t *testing.T) {
+	resetDeliveries(t)
+	slack, got := slackRecorder(t)
+	t.Setenv("SLACK_WEBHOOK_URL", slack.URL)
+	t.Setenv("ALERT_STORM_MAX", "3")
+	t.Setenv("ALERT_STORM_WINDOW", "10m")
+	c := fakeClock(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	resetStorm := func() {
+		stormMu.Lock()
+		stormSent, stormHeld = nil, nil
+		stormMu.Unlock()
+	}
+	resetStorm()
+	t.Cleanup(func() {
+		SendStormRollup()
+		resetStorm()
+	})
+
+	for i := 0; i < 6; i++ {
+		e := &collector.Endpoint{Name: fmt.Sprintf("Odos-%d", i%4), RouteSolver: "odos"}
+		SendEndpointAlert(e, "no route found", "")
+	}
+	if n := len(got()); n != 3 {
+		t.Fatalf("sent %d before the rollup, want 3", n)
+	}
+
+	// The rollup goes out when the oldest counted alert leaves the window.
+	c.Advance(10 * time.Minute)
+	msgs := got()
+	if len(msgs) != 4 {
+		t.Fatalf("sent %d, want 3 and the rollup", len(msgs))
+	}
+	rollup := msgs[3]
+	if !strings.HasPrefix(rollup, "[CRITICAL] Alert storm: 3 more alerts after 3 in 10m") ||
+		!strings.Contains(rollup, "\n[CRITICAL] [Odos-3] no route found\n") || !strings.Contains(rollup, "[CRITICAL] [Odos-0] no route found") ||
+		strings.Contains(rollup, "Hint:") {
+		t.Fatalf("rollup = %q", rollup)
+	}
+
+	// The rollup counts against the cap; once the window passes alerts flow
+	// again.
+	c.Advance(time.Minute)
+	Send(SeverityWarning, "pool migrated")
+	if msgs := got(); len(msgs) != 5 || !strings.Contains(msgs[4], "pool migrated") {
+		t.Fatalf("after the window: %q", msgs[4:])
+	}
+}
+
+func TestRollupLines(t *testing.T) {
+	alerts := []heldAlert{{text: "[A] down\nHint: x"}, {text: "[B] down"}, {text: "[B] down\nRoute: y"}}
+	if got := rollupLines(alerts); got != "[B] down (×2)\n[A] down" {
+		t.Fatalf("lines = %q", got)
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/api"
-	"go-monitoring/internal/collector"
-	"go-monitoring/notifications"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/sources"
 )
 
 // ZeroXResponse represents the structure of the 0x API response
@@ -16,13 +20,22 @@ type ZeroXResponse struct {
 	BuyAmount string `json:"buyAmount,omitempty"`
 	Route     struct {
 		Fills []struct {
-			Source string `json:"source"`
+			From          string `json:"from"`
+			To            string `json:"to"`
+			Source        string `json:"source"`
+			ProportionBps string `json:"proportionBps"`
 		} `json:"fills"`
 		Tokens []struct {
 			Address string `json:"address"`
 			Symbol  string `json:"symbol"`
 		} `json:"tokens"`
 	} `json:"route"`
+	// Issues.Allowance.Spender is the address a user approves (Permit2).
+	Issues struct {
+		Allowance *struct {
+			Spender string `json:"spender"`
+		} `json:"allowance"`
+	} `json:"issues"`
 }
 
 // ZeroXHandler implements the ResponseHandler interface for 0x API
@@ -37,7 +50,7 @@ func NewZeroXHandler() *ZeroXHandler {
 }
 
 // HandleResponse processes the 0x API response and validates it according to business rules
-func (h *ZeroXHandler) HandleResponse(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *ZeroXHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result ZeroXResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -51,44 +64,54 @@ func (h *ZeroXHandler) HandleResponse(response *api.APIResponse, endpoint *colle
 		h.handleError(endpoint, "down", "No Routes Found", string(response.Body))
 		return fmt.Errorf("response contains null fills or tokens")
 	}
+	endpoint.SetRoute(result.routeGraph())
+	if result.Issues.Allowance != nil {
+		endpoint.Spender = result.Issues.Allowance.Spender
+	}
 
-	// Check if all fills are from Balancer_V3
+	// Check if all fills are from Balancer V3
+	src, err := sources.ForEndpoint("0x", endpoint)
+	if err != nil {
+		h.handleError(endpoint, "error", err.Error(), "")
+		return err
+	}
 	allBalancerV3 := true
-	for _, fill := range result.Route.Fills {
-		if fill.Source != "Balancer_V3" {
+	for _, venue := range endpoint.Route.Venues() {
+		if !src.Matches(venue.Exchange) {
 			allBalancerV3 = false
-			endpoint.Message = fmt.Sprintf("Found source %s, expected Balancer_V3", fill.Source)
+			endpoint.Message = fmt.Sprintf("Found source %s, expected %s", venue.Exchange, src)
 			prettyJSON, _ := json.MarshalIndent(result, "", "    ")
-			h.handleError(endpoint, "down", fmt.Sprintf("Found source %s, expected Balancer_V3", fill.Source), string(prettyJSON))
-			return fmt.Errorf("found source %s, expected Balancer_V3", fill.Source)
+			h.handleError(endpoint, "down", fmt.Sprintf("Found source %s, expected %s", venue.Exchange, src), string(prettyJSON))
+			return fmt.Errorf("found source %s, expected %s", venue.Exchange, src)
 		}
 	}
 
 	if !allBalancerV3 {
 		endpoint.LastStatus = "down"
-		return fmt.Errorf("not all fills are from Balancer_V3")
+		return fmt.Errorf("not all fills are from %s", src)
 	}
 
 	// Check number of hops
-	expectedTokens := endpoint.ExpectedNoHops + 1 // Number of tokens = number of hops + 1 (start and end tokens)
-	if len(result.Route.Tokens) != expectedTokens {
+	if hops := endpoint.Route.HopCount(); hops != endpoint.ExpectedNoHops {
 		endpoint.LastStatus = "down"
-		endpoint.Message = fmt.Sprintf("Expected %d tokens (hops + 2), got %d", expectedTokens, len(result.Route.Tokens))
+		endpoint.Message = fmt.Sprintf("Expected %d hops, got %d", endpoint.ExpectedNoHops, hops)
 		prettyJSON, _ := json.MarshalIndent(result, "", "    ")
-		h.handleError(endpoint, "down", fmt.Sprintf("Expected %d tokens (hops + 2), got %d", expectedTokens, len(result.Route.Tokens)), string(prettyJSON))
-		return fmt.Errorf("expected %d tokens, got %d", expectedTokens, len(result.Route.Tokens))
+		h.handleError(endpoint, "down", fmt.Sprintf("Expected %d hops, got %d", endpoint.ExpectedNoHops, hops), string(prettyJSON))
+		return fmt.Errorf("expected %d hops, got %d", endpoint.ExpectedNoHops, hops)
 	}
 
-	// Store the return amount if available
-	if result.BuyAmount != "" {
-		endpoint.ReturnAmount = result.BuyAmount
+	// Store the return amount
+	if result.BuyAmount == "" {
+		h.handleError(endpoint, "down", "no buyAmount in response", string(response.Body))
+		return fmt.Errorf("no buyAmount in response")
 	}
+	endpoint.ReturnAmount = result.BuyAmount
 
 	return nil
 }
 
 // HandleResponseForMarketPrice processes the 0x API response for market price (all sources)
-func (h *ZeroXHandler) HandleResponseForMarketPrice(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *ZeroXHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result ZeroXResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -120,7 +143,7 @@ func (h *ZeroXHandler) GetIgnoreList(network string) (string, error) {
 	case "143": // Monad
 		return "Metric,Uniswap_V2,Uniswap_V3,Uniswap_V4,SushiSwap,SushiSwap_V3,Curve,PancakeSwap_V2,PancakeSwap_V3,TraderJoe_V2.2,OctoSwap_V2,Atlantis_V4", nil
 	default:
-		return "", fmt.Errorf("unsupported network: %s", network)
+		return "", fmt.Errorf("%w: %s", check.ErrNetworkNotApplicable, network)
 	}
 }
 
@@ -128,8 +151,8 @@ func (h *ZeroXHandler) GetIgnoreList(network string) (string, error) {
 func (h *ZeroXHandler) handleError(endpoint *collector.Endpoint, status, message, responseBody string) {
 	endpoint.LastStatus = status
 	endpoint.Message = message
-	fmt.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
-	notifications.SendEmail(fmt.Sprintf("[%s] %s\nResponse body:\n%s", endpoint.Name, message, responseBody))
+	logs.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
+	notify.SendEndpointAlert(endpoint, message, responseBody)
 }
 
 // NewZeroXURLBuilder creates a new 0x URL builder
@@ -138,7 +161,7 @@ func NewZeroXURLBuilder() *ZeroXURLBuilder {
 }
 
 // BuildURL builds the complete URL for 0x API requests
-func (b *ZeroXURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.RequestOptions) (string, error) {
+func (b *ZeroXURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
 	baseURL := "https://api.0x.org/swap/permit2/price"
 
 	// Build parameters
@@ -154,12 +177,59 @@ func (b *ZeroXURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.Req
 		handler := &ZeroXHandler{}
 		ignoreList, err := handler.GetIgnoreList(endpoint.Network)
 		if err != nil {
-			return "", fmt.Errorf("error getting ignore list: %v", err)
+			return "", fmt.Errorf("error getting ignore list: %w", err)
 		}
 		if ignoreList != "" {
 			params.Add("excludedSources", ignoreList)
 		}
+	} else if len(options.ExcludeSources) > 0 {
+		params.Add("excludedSources", strings.Join(options.ExcludeSources, ","))
 	}
 
 	return fmt.Sprintf("%s?%s", baseURL, params.Encode()), nil
 }
+
+// ParseRoute returns the route of a quote response, for the exclusion check.
+func (h *ZeroXHandler) ParseRoute(body []byte) (collector.RouteGraph, error) {
+	var result ZeroXResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return collector.RouteGraph{}, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	if result.Route.Fills == nil || result.Route.Tokens == nil {
+		return collector.RouteGraph{}, fmt.Errorf("no routes found")
+	}
+	return result.routeGraph(), nil
+}
+
+// routeGraph builds the route along the token list, one hop per consecutive
+// pair with the fills between that pair as its venues. A fill between other
+// tokens (a split past an intermediate token) is a path of its own.
+func (r ZeroXResponse) routeGraph() collector.RouteGraph {
+	var along collector.RoutePath
+	for i := 0; i+1 < len(r.Route.Tokens); i++ {
+		along.Hops = append(along.Hops, collector.RouteHop{TokenIn: r.Route.Tokens[i].Address, TokenOut: r.Route.Tokens[i+1].Address})
+	}
+	var splits []collector.RoutePath
+	for _, fill := range r.Route.Fills {
+		bps, _ := strconv.ParseFloat(fill.ProportionBps, 64)
+		venue := collector.RouteVenue{Exchange: fill.Source, Percent: bps / 100}
+		hop := -1
+		for i, h := range along.Hops {
+			if config.SameAddress(h.TokenIn, fill.From) && config.SameAddress(h.TokenOut, fill.To) {
+				hop = i
+				break
+			}
+		}
+		if hop < 0 {
+			splits = append(splits, collector.RoutePath{Hops: []collector.RouteHop{{TokenIn: fill.From, TokenOut: fill.To, Venues: []collector.RouteVenue{venue}}}})
+			continue
+		}
+		along.Hops[hop].Venues = append(along.Hops[hop].Venues, venue)
+	}
+	var g collector.RouteGraph
+	if len(along.Hops) > 0 {
+		g.Paths = append(g.Paths, along)
+	}
+	g.Paths = append(g.Paths, splits...)
+	return g
+}
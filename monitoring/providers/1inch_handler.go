@@ -4,12 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"strings"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/api"
-	"go-monitoring/internal/collector"
-	"go-monitoring/notifications"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/sources"
 )
 
 // OneInchResponse represents the structure of the 1inch API response
@@ -43,7 +44,7 @@ func NewOneInchHandler() *OneInchHandler {
 }
 
 // HandleResponse processes the 1inch API response and validates it according to business rules
-func (h *OneInchHandler) HandleResponse(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *OneInchHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
 
 	// Parse the JSON response
 	var result OneInchResponse
@@ -60,6 +61,8 @@ func (h *OneInchHandler) HandleResponse(response *api.APIResponse, endpoint *col
 		return fmt.Errorf("insufficient liquidity")
 	}
 
+	endpoint.SetRoute(result.routeGraph())
+
 	// Check if protocols is null or empty
 	if result.Protocols == nil {
 		h.handleError(endpoint, "down", "1inch network support WIP", string(response.Body))
@@ -74,33 +77,40 @@ func (h *OneInchHandler) HandleResponse(response *api.APIResponse, endpoint *col
 	}
 
 	// Check all protocols are Balancer V3
-	totalPart := 0
-	for _, protocol := range result.Protocols[0][0] {
-		if !strings.Contains(protocol.Name, "BALANCER_V3") {
+	src, err := sources.ForEndpoint("1inch", endpoint)
+	if err != nil {
+		h.handleError(endpoint, "error", err.Error(), "")
+		return err
+	}
+	totalPart := 0.0
+	for _, venue := range endpoint.Route.Paths[0].Hops[0].Venues {
+		if !src.Matches(venue.Exchange) {
 			prettyJSON, _ := json.MarshalIndent(result, "", "    ")
-			h.handleError(endpoint, "down", fmt.Sprintf("found protocol %s, expected protocol containing BALANCER_V3", protocol.Name), string(prettyJSON))
-			return fmt.Errorf("found protocol %s, expected protocol containing BALANCER_V3", protocol.Name)
+			h.handleError(endpoint, "down", fmt.Sprintf("found protocol %s, expected protocol containing %s", venue.Exchange, src), string(prettyJSON))
+			return fmt.Errorf("found protocol %s, expected protocol containing %s", venue.Exchange, src)
 		}
-		totalPart += protocol.Part
+		totalPart += venue.Percent
 	}
 
 	// Verify that parts sum up to 100
 	if totalPart != 100 {
 		prettyJSON, _ := json.MarshalIndent(result, "", "    ")
-		h.handleError(endpoint, "down", fmt.Sprintf("protocol parts sum to %d, expected 100", totalPart), string(prettyJSON))
-		return fmt.Errorf("protocol parts sum to %d, expected 100", totalPart)
+		h.handleError(endpoint, "down", fmt.Sprintf("protocol parts sum to %g, expected 100", totalPart), string(prettyJSON))
+		return fmt.Errorf("protocol parts sum to %g, expected 100", totalPart)
 	}
 
-	// Store the return amount if available
-	if result.DstAmount != "" {
-		endpoint.ReturnAmount = result.DstAmount
+	// Store the return amount
+	if result.DstAmount == "" {
+		h.handleError(endpoint, "down", "no dstAmount in response", string(response.Body))
+		return fmt.Errorf("no dstAmount in response")
 	}
+	endpoint.ReturnAmount = result.DstAmount
 
 	return nil
 }
 
 // HandleResponseForMarketPrice processes the 1inch API response for market price (all sources)
-func (h *OneInchHandler) HandleResponseForMarketPrice(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *OneInchHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result OneInchResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -122,30 +132,22 @@ func (h *OneInchHandler) GetIgnoreList(network string) (string, error) {
 	return "", nil
 }
 
-// GetBalancerName returns the balancer name based on the network
+// GetBalancerName returns the balancer protocol name for the network from the
+// source ID registry; 1inch names it per chain (e.g. ARBITRUM_BALANCER_V3).
 func (h *OneInchHandler) GetBalancerName(network string) (string, error) {
-	switch network {
-	case "100": // Gnosis
-		return "GNOSIS_BALANCER_V3", nil
-	case "42161": // Arbitrum
-		return "ARBITRUM_BALANCER_V3", nil
-	case "8453": // Base
-		return "BASE_BALANCER_V3", nil
-	case "1": // Ethereum Mainnet
-		return "BALANCER_V3", nil
-	case "43114": // Avalanche
-		return "AVALANCHE_BALANCER_V3", nil
-	default:
-		return "", fmt.Errorf("unsupported network: %s", network)
+	src, ok := sources.Lookup(sources.Table(), "1inch", network, "")
+	if !ok {
+		return "", fmt.Errorf("%w: %s", check.ErrNetworkNotApplicable, network)
 	}
+	return src.String(), nil
 }
 
 // handleError updates endpoint status and sends notifications for 1inch-specific errors
 func (h *OneInchHandler) handleError(endpoint *collector.Endpoint, status, message, responseBody string) {
 	endpoint.LastStatus = status
 	endpoint.Message = message
-	fmt.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
-	notifications.SendEmail(fmt.Sprintf("[%s] %s\nResponse body:\n%s", endpoint.Name, message, responseBody))
+	logs.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
+	notify.SendEndpointAlert(endpoint, message, responseBody)
 }
 
 // NewOneInchURLBuilder creates a new 1inch URL builder
@@ -154,7 +156,7 @@ func NewOneInchURLBuilder() *OneInchURLBuilder {
 }
 
 // BuildURL builds the complete URL for 1inch API requests
-func (b *OneInchURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.RequestOptions) (string, error) {
+func (b *OneInchURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
 	baseURL := "https://api.1inch.dev/swap/v6.0/" + endpoint.Network + "/quote"
 
 	// Build parameters
@@ -171,10 +173,31 @@ func (b *OneInchURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.R
 		handler := &OneInchHandler{}
 		balancerName, err := handler.GetBalancerName(endpoint.Network)
 		if err != nil {
-			return "", fmt.Errorf("error getting 1inch balancer name: %v", err)
+			return "", fmt.Errorf("error getting 1inch balancer name: %w", err)
 		}
 		params.Add("protocols", balancerName)
 	}
 
 	return fmt.Sprintf("%s?%s", baseURL, params.Encode()), nil
 }
+
+// routeGraph builds the route from protocols, which 1inch nests as routes of
+// steps of parts.
+func (r OneInchResponse) routeGraph() collector.RouteGraph {
+	var g collector.RouteGraph
+	for _, route := range r.Protocols {
+		var path collector.RoutePath
+		for _, step := range route {
+			if len(step) == 0 {
+				continue
+			}
+			hop := collector.RouteHop{TokenIn: step[0].FromTokenAddress, TokenOut: step[0].ToTokenAddress}
+			for _, part := range step {
+				hop.Venues = append(hop.Venues, collector.RouteVenue{Exchange: part.Name, Percent: float64(part.Part)})
+			}
+			path.Hops = append(path.Hops, hop)
+		}
+		g.Paths = append(g.Paths, path)
+	}
+	return g
+}
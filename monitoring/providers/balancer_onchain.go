@@ -0,0 +1,472 @@
+package providers
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+)
+
+// Router ABI JSON for querySwapSingleTokenExactIn
+const routerABI = `[
+	{
+		"inputs": [
+			{"internalType": "address", "name": "pool", "type": "address"},
+			{"internalType": "address", "name": "tokenIn", "type": "address"},
+			{"internalType": "address", "name": "tokenOut", "type": "address"},
+			{"internalType": "uint256", "name": "exactAmountIn", "type": "uint256"},
+			{"internalType": "address", "name": "sender", "type": "address"},
+			{"internalType": "bytes", "name": "userData", "type": "bytes"}
+		],
+		"name": "querySwapSingleTokenExactIn",
+		"outputs": [
+			{"internalType": "uint256", "name": "amountOut", "type": "uint256"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// BatchRouter ABI JSON for querySwapExactIn
+const batchRouterABI = `[
+	{
+		"inputs": [
+			{
+				"components": [
+					{"internalType": "address", "name": "tokenIn", "type": "address"},
+					{
+						"components": [
+							{"internalType": "address", "name": "pool", "type": "address"},
+							{"internalType": "address", "name": "tokenOut", "type": "address"},
+							{"internalType": "bool", "name": "isBuffer", "type": "bool"}
+						],
+						"internalType": "struct BatchRouter.SwapPathStep[]",
+						"name": "steps",
+						"type": "tuple[]"
+					},
+					{"internalType": "uint256", "name": "exactAmountIn", "type": "uint256"},
+					{"internalType": "uint256", "name": "minAmountOut", "type": "uint256"}
+				],
+				"internalType": "struct BatchRouter.SwapPathExactAmountIn[]",
+				"name": "paths",
+				"type": "tuple[]"
+			},
+			{"internalType": "address", "name": "sender", "type": "address"},
+			{"internalType": "bytes", "name": "userData", "type": "bytes"}
+		],
+		"name": "querySwapExactIn",
+		"outputs": [
+			{"internalType": "uint256[]", "name": "pathAmountsOut", "type": "uint256[]"},
+			{"internalType": "address[]", "name": "tokensOut", "type": "address[]"},
+			{"internalType": "uint256[]", "name": "amountsOut", "type": "uint256[]"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+var (
+	routerABIParsed      abi.ABI
+	batchRouterABIParsed abi.ABI
+	initOnce             sync.Once
+)
+
+// initABIs initializes the parsed ABI instances
+func initABIs() error {
+	var err error
+	routerABIParsed, err = abi.JSON(strings.NewReader(routerABI))
+	if err != nil {
+		return fmt.Errorf("failed to parse Router ABI: %w", err)
+	}
+
+	batchRouterABIParsed, err = abi.JSON(strings.NewReader(batchRouterABI))
+	if err != nil {
+		return fmt.Errorf("failed to parse BatchRouter ABI: %w", err)
+	}
+
+	return nil
+}
+
+// SwapPathStep represents a single step in a swap path
+type SwapPathStep struct {
+	Pool     common.Address
+	TokenOut common.Address
+	IsBuffer bool
+}
+
+// SwapPathExactAmountIn represents a swap path with exact input amount
+type SwapPathExactAmountIn struct {
+	TokenIn       common.Address
+	Steps         []SwapPathStep
+	ExactAmountIn *big.Int
+	MinAmountOut  *big.Int
+}
+
+// OnChainQuote is an on-chain swap query result and where it came from.
+// BlockNumber, RPCHost and Latency are set even when the query fails, as far
+// as it got.
+type OnChainQuote struct {
+	Amount      string // amountOut as a raw integer string
+	BlockNumber uint64
+	RPCHost     string // hostname of the RPC URL; the path may hold an API key
+	Latency     time.Duration
+}
+
+// record copies the query's block, RPC host and latency onto endpoint.
+func (q OnChainQuote) record(endpoint *collector.Endpoint) {
+	endpoint.OnChainBlock, endpoint.OnChainRPCHost, endpoint.OnChainLatency = q.BlockNumber, q.RPCHost, q.Latency
+}
+
+// rpcHost returns the hostname of an RPC URL.
+func rpcHost(rpcURL string) string {
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// QueryOnChainPrice performs an eth_call to query the on-chain swap price.
+// For single-pool swaps, it uses Router.querySwapSingleTokenExactIn.
+// For multi-path swaps, it uses BatchRouter.querySwapExactIn.
+// The call is pinned to the head block's hash (see callAtHead); with after
+// non-zero it waits for a block after that one, to re-read a result.
+// Returns an error if the RPC URL is not configured or the call fails.
+func QueryOnChainPrice(endpoint *collector.Endpoint, after uint64) (OnChainQuote, error) {
+	rpcURL := config.GetRPCURL(endpoint.Network)
+	if rpcURL == "" {
+		return OnChainQuote{}, fmt.Errorf("no RPC URL configured for network %s", endpoint.Network)
+	}
+
+	// Check if we have path information
+	if len(endpoint.SwapPathPools) == 0 {
+		return OnChainQuote{}, fmt.Errorf("no path information available for endpoint %s", endpoint.Name)
+	}
+
+	logs.Printf("[DEBUG] On-chain query for %s:\n", endpoint.Name)
+	logs.Printf("[DEBUG]   Network: %s\n", endpoint.Network)
+	logs.Printf("[DEBUG]   RPC host: %s\n", rpcHost(rpcURL))
+	logs.Printf("[DEBUG]   Path pools: %v\n", endpoint.SwapPathPools)
+	logs.Printf("[DEBUG]   Path tokenOut: %v\n", endpoint.SwapPathTokenOut)
+	logs.Printf("[DEBUG]   Path isBuffer: %v\n", endpoint.SwapPathIsBuffer)
+	logs.Printf("[DEBUG]   TokenIn: %s\n", endpoint.TokenIn)
+	logs.Printf("[DEBUG]   TokenOut: %s\n", endpoint.TokenOut)
+	logs.Printf("[DEBUG]   SwapAmount: %s\n", endpoint.SwapAmount)
+
+	// Determine if single-pool or multi-path swap
+	if len(endpoint.SwapPathPools) == 1 {
+		logs.Printf("[DEBUG]   Detected: Single-pool swap, using Router\n")
+		quote, err := querySinglePoolSwap(rpcURL, endpoint, after)
+		quote.RPCHost = rpcHost(rpcURL)
+		return quote, err
+	}
+
+	logs.Printf("[DEBUG]   Detected: Multi-path swap (%d pools), using BatchRouter\n", len(endpoint.SwapPathPools))
+	quote, err := queryMultiPathSwap(rpcURL, endpoint, after)
+	quote.RPCHost = rpcHost(rpcURL)
+	return quote, err
+}
+
+// onChainRequeryBps is how far, in basis points, an on-chain result may be
+// from the SOR quote before it is re-read on a later block: the dashboard
+// flags a 0.5% deviation, and a quote and query that straddle a block
+// boundary or a short reorg can differ by that much on fast chains.
+const onChainRequeryBps = 50
+
+// recordOnChainPrice sets OnChainPrice (or OnChainQueryError) from the
+// on-chain query for a balancer_sor endpoint's path. A result deviating from
+// the SOR quote by more than onChainRequeryBps is re-read on a later block and
+// the later result kept, so a one-block discrepancy doesn't show as a
+// deviation.
+func recordOnChainPrice(endpoint *collector.Endpoint) {
+	logs.Printf("%s[ON-CHAIN QUERY]%s %s: Querying on-chain price\n", config.ColorCyan, config.ColorReset, endpoint.Name)
+	quote, err := QueryOnChainPrice(endpoint, 0)
+	if err == nil && deviatesBps(endpoint.ReturnAmount, quote.Amount, onChainRequeryBps) {
+		logs.Printf("%s[ON-CHAIN QUERY]%s %s: On-chain price %s at block %d deviates from the SOR quote %s; re-reading on a later block\n",
+			config.ColorYellow, config.ColorReset, endpoint.Name, quote.Amount, quote.BlockNumber, endpoint.ReturnAmount)
+		quote, err = QueryOnChainPrice(endpoint, quote.BlockNumber)
+	}
+	quote.record(endpoint)
+	if err != nil {
+		endpoint.OnChainPrice = ""
+		endpoint.OnChainQueryError = err.Error()
+		logs.Printf("%s[WARN]%s %s: On-chain query failed: %v\n", config.ColorYellow, config.ColorReset, endpoint.Name, err)
+		return
+	}
+	endpoint.OnChainPrice = quote.Amount
+	endpoint.OnChainQueryError = ""
+	logs.Printf("%s[ON-CHAIN RESULT]%s %s: On-chain price = %s (block %d)\n", config.ColorGreen, config.ColorReset, endpoint.Name, quote.Amount, quote.BlockNumber)
+}
+
+// deviatesBps reports whether quote and onChain are both amounts and differ
+// by more than bps of onChain.
+func deviatesBps(quote, onChain string, bps float64) bool {
+	spread, ok := collector.QuoteSpreadBps(quote, onChain)
+	return ok && math.Abs(spread) > bps
+}
+
+// BuildOnChainCall returns the contract address (the newest router version)
+// and calldata QueryOnChainPrice would send for the endpoint's current swap
+// path, without touching the RPC.
+// Used by dry-run mode to validate ABI encoding.
+func BuildOnChainCall(endpoint *collector.Endpoint) (string, []byte, error) {
+	if len(endpoint.SwapPathPools) == 0 {
+		return "", nil, fmt.Errorf("no path information available for endpoint %s", endpoint.Name)
+	}
+	kind, pack := KindRouter, packSinglePoolSwap
+	if len(endpoint.SwapPathPools) > 1 {
+		kind, pack = KindBatchRouter, packMultiPathSwap
+	}
+	deployments := routersFor(Routers(), endpoint.Network, kind)
+	if len(deployments) == 0 {
+		return "", nil, fmt.Errorf("no %s address known for network %s", kindLabel(kind), endpoint.Network)
+	}
+	calldata, err := pack(endpoint)
+	if err != nil {
+		return "", nil, err
+	}
+	return deployments[0].Address, calldata, nil
+}
+
+// ensureABIs parses the Router / BatchRouter ABIs on first use.
+func ensureABIs() {
+	initOnce.Do(func() {
+		if err := initABIs(); err != nil {
+			panic(fmt.Sprintf("Failed to initialize ABIs: %v", err))
+		}
+	})
+}
+
+// queryRouters runs calldata against each deployment of kind on network,
+// newest version first, and returns the newest successful quote. During a
+// migration window an older version disagreeing with it by more than
+// onChainRequeryBps is logged, and if the newest version fails an older one
+// answers. With no success the newest version's error is returned.
+func queryRouters(rpcURL, network, kind string, calldata []byte, after uint64, decode func([]byte) (*big.Int, error)) (OnChainQuote, error) {
+	deployments := routersFor(Routers(), network, kind)
+	if len(deployments) == 0 {
+		return OnChainQuote{}, fmt.Errorf("no %s address known for network %s", kindLabel(kind), network)
+	}
+	var best OnChainQuote
+	var newestErr error
+	for i, d := range deployments {
+		quote, err := queryRouter(rpcURL, d, calldata, after, decode)
+		switch {
+		case err != nil:
+			logs.Printf("[DEBUG]   %s v%d failed: %v\n", kindLabel(kind), d.Version, err)
+			if i == 0 {
+				best, newestErr = quote, err
+			}
+		case best.Amount == "":
+			if i > 0 {
+				logs.Printf("%s[ON-CHAIN QUERY]%s %s v%d failed on network %s; using v%d\n", config.ColorYellow, config.ColorReset, kindLabel(kind), deployments[0].Version, network, d.Version)
+			}
+			best = quote
+		case deviatesBps(best.Amount, quote.Amount, onChainRequeryBps):
+			logs.Printf("%s[ON-CHAIN QUERY]%s %s versions disagree on network %s: v%d returned %s (block %d), v%d %s (block %d)\n",
+				config.ColorYellow, config.ColorReset, kindLabel(kind), network, deployments[0].Version, best.Amount, best.BlockNumber, d.Version, quote.Amount, quote.BlockNumber)
+		}
+	}
+	if best.Amount == "" {
+		return best, newestErr
+	}
+	return best, nil
+}
+
+// queryRouter runs calldata against one router deployment.
+func queryRouter(rpcURL string, d RouterDeployment, calldata []byte, after uint64, decode func([]byte) (*big.Int, error)) (OnChainQuote, error) {
+	logs.Printf("[DEBUG]   %s v%d address: %s\n", kindLabel(d.Kind), d.Version, d.Address)
+	contractAddr := common.HexToAddress(d.Address)
+	call, err := callRPC(rpcURL, ethereum.CallMsg{To: &contractAddr, Data: calldata}, after)
+	quote := OnChainQuote{BlockNumber: call.BlockNumber, Latency: call.Latency}
+	if err != nil {
+		return quote, err
+	}
+
+	logs.Printf("[DEBUG]   RPC result: 0x%x\n", call.Result)
+	amountOut, err := decode(call.Result)
+	if err != nil {
+		return quote, err
+	}
+
+	logs.Printf("[DEBUG]   Decoded amountOut: %s\n", amountOut.String())
+	quote.Amount = amountOut.String()
+	return quote, nil
+}
+
+// querySinglePoolSwap performs a single-pool swap query using Router.querySwapSingleTokenExactIn
+// at the head, or the first block after `after` when it is non-zero.
+func querySinglePoolSwap(rpcURL string, endpoint *collector.Endpoint, after uint64) (OnChainQuote, error) {
+	calldata, err := packSinglePoolSwap(endpoint)
+	if err != nil {
+		return OnChainQuote{}, err
+	}
+	return queryRouters(rpcURL, endpoint.Network, KindRouter, calldata, after, decodeSinglePoolSwap)
+}
+
+// decodeSinglePoolSwap unpacks querySwapSingleTokenExactIn's amountOut.
+func decodeSinglePoolSwap(result []byte) (*big.Int, error) {
+	// Unpack result - returns a single uint256
+	unpacked, err := routerABIParsed.Unpack("querySwapSingleTokenExactIn", result)
+	if err != nil {
+		return nil, fmt.Errorf("ABI decoding failed: %w", err)
+	}
+
+	if len(unpacked) == 0 {
+		return nil, fmt.Errorf("empty result from unpack")
+	}
+
+	amountOut, ok := unpacked[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected return type: %T", unpacked[0])
+	}
+	return amountOut, nil
+}
+
+// packSinglePoolSwap encodes Router.querySwapSingleTokenExactIn for the
+// endpoint's single path pool.
+func packSinglePoolSwap(endpoint *collector.Endpoint) ([]byte, error) {
+	ensureABIs()
+
+	pool := endpoint.SwapPathPools[0]
+	senderAddr := common.HexToAddress("0x0000000000000000000000000000000000000000")
+
+	logs.Printf("[DEBUG]   Pool: %s\n", pool)
+	logs.Printf("[DEBUG]   Sender: %s\n", senderAddr.Hex())
+
+	// Convert addresses
+	poolAddr := common.HexToAddress(pool)
+	tokenInAddr := common.HexToAddress(endpoint.TokenIn)
+	tokenOutAddr := common.HexToAddress(endpoint.TokenOut)
+
+	// Convert swap amount
+	amountInt, ok := new(big.Int).SetString(endpoint.SwapAmount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid swap amount: %s", endpoint.SwapAmount)
+	}
+
+	// Pack function call
+	calldata, err := routerABIParsed.Pack("querySwapSingleTokenExactIn",
+		poolAddr,
+		tokenInAddr,
+		tokenOutAddr,
+		amountInt,
+		senderAddr,
+		[]byte{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ABI encoding failed: %w", err)
+	}
+
+	logs.Printf("[DEBUG]   Calldata length: %d bytes\n", len(calldata))
+	logs.Printf("[DEBUG]   Calldata: 0x%x\n", calldata)
+
+	return calldata, nil
+}
+
+// queryMultiPathSwap performs a multi-path swap query using BatchRouter.querySwapExactIn
+// at the head, or the first block after `after` when it is non-zero.
+func queryMultiPathSwap(rpcURL string, endpoint *collector.Endpoint, after uint64) (OnChainQuote, error) {
+	calldata, err := packMultiPathSwap(endpoint)
+	if err != nil {
+		return OnChainQuote{}, err
+	}
+	return queryRouters(rpcURL, endpoint.Network, KindBatchRouter, calldata, after, decodeMultiPathSwap)
+}
+
+// decodeMultiPathSwap unpacks querySwapExactIn's final amountOut.
+func decodeMultiPathSwap(result []byte) (*big.Int, error) {
+	// Unpack result - returns (uint256[] pathAmountsOut, address[] tokensOut, uint256[] amountsOut)
+	unpacked, err := batchRouterABIParsed.Unpack("querySwapExactIn", result)
+	if err != nil {
+		return nil, fmt.Errorf("ABI decoding failed: %w", err)
+	}
+
+	if len(unpacked) < 3 {
+		return nil, fmt.Errorf("unexpected number of return values: %d", len(unpacked))
+	}
+
+	// unpacked[0] = pathAmountsOut []*big.Int
+	// unpacked[1] = tokensOut []common.Address
+	// unpacked[2] = amountsOut []*big.Int
+	amountsOut, ok := unpacked[2].([]*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected return type for amountsOut: %T", unpacked[2])
+	}
+
+	if len(amountsOut) == 0 {
+		return nil, fmt.Errorf("empty amountsOut array")
+	}
+
+	// Return the last amountOut (final output)
+	return amountsOut[len(amountsOut)-1], nil
+}
+
+// packMultiPathSwap encodes BatchRouter.querySwapExactIn for the endpoint's
+// multi-step path.
+func packMultiPathSwap(endpoint *collector.Endpoint) ([]byte, error) {
+	ensureABIs()
+
+	// Validate path information
+	if len(endpoint.SwapPathPools) != len(endpoint.SwapPathTokenOut) {
+		return nil, fmt.Errorf("path pools length (%d) does not match tokenOut length (%d)",
+			len(endpoint.SwapPathPools), len(endpoint.SwapPathTokenOut))
+	}
+	if len(endpoint.SwapPathPools) != len(endpoint.SwapPathIsBuffer) {
+		return nil, fmt.Errorf("path pools length (%d) does not match isBuffer length (%d)",
+			len(endpoint.SwapPathPools), len(endpoint.SwapPathIsBuffer))
+	}
+
+	// Build SwapPathStep array
+	steps := make([]SwapPathStep, len(endpoint.SwapPathPools))
+	for i := 0; i < len(endpoint.SwapPathPools); i++ {
+		logs.Printf("[DEBUG]     Step %d: pool=%s, tokenOut=%s, isBuffer=%v\n",
+			i, endpoint.SwapPathPools[i], endpoint.SwapPathTokenOut[i], endpoint.SwapPathIsBuffer[i])
+
+		steps[i] = SwapPathStep{
+			Pool:     common.HexToAddress(endpoint.SwapPathPools[i]),
+			TokenOut: common.HexToAddress(endpoint.SwapPathTokenOut[i]),
+			IsBuffer: endpoint.SwapPathIsBuffer[i],
+		}
+	}
+
+	// Convert swap amount
+	amountInt, ok := new(big.Int).SetString(endpoint.SwapAmount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid swap amount: %s", endpoint.SwapAmount)
+	}
+
+	// Build SwapPathExactAmountIn struct
+	path := SwapPathExactAmountIn{
+		TokenIn:       common.HexToAddress(endpoint.TokenIn),
+		Steps:         steps,
+		ExactAmountIn: amountInt,
+		MinAmountOut:  big.NewInt(0), // 0 for queries
+	}
+
+	// Pack function call
+	calldata, err := batchRouterABIParsed.Pack("querySwapExactIn",
+		[]SwapPathExactAmountIn{path},
+		common.HexToAddress("0x0000000000000000000000000000000000000000"),
+		[]byte{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ABI encoding failed: %w", err)
+	}
+
+	logs.Printf("[DEBUG]   Calldata length: %d bytes\n", len(calldata))
+	logs.Printf("[DEBUG]   Calldata: 0x%x\n", calldata)
+
+	return calldata, nil
+}
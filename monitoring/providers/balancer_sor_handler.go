@@ -7,9 +7,10 @@ import (
 	"math/big"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/api"
-	"go-monitoring/internal/collector"
-	"go-monitoring/notifications"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
 )
 
 // BalancerSORResponse represents the structure of the Balancer SOR API response
@@ -47,7 +48,7 @@ func NewBalancerSORHandler() *BalancerSORHandler {
 }
 
 // HandleResponse processes the Balancer SOR API response and validates it according to business rules
-func (h *BalancerSORHandler) HandleResponse(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *BalancerSORHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result BalancerSORResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -82,7 +83,7 @@ func (h *BalancerSORHandler) HandleResponse(response *api.APIResponse, endpoint
 	rawReturnAmount, err := h.convertFromDecimalAmount(result.Data.SorGetSwapPaths.ReturnAmount, endpoint.TokenOutDecimals)
 	if err != nil {
 		// Log the error but don't fail the request - just use the original decimal amount
-		fmt.Printf("Warning: Could not convert return amount to raw format: %v\n", err)
+		logs.Printf("Warning: Could not convert return amount to raw format: %v\n", err)
 	} else {
 		endpoint.ReturnAmount = rawReturnAmount
 	}
@@ -93,47 +94,43 @@ func (h *BalancerSORHandler) HandleResponse(response *api.APIResponse, endpoint
 		return fmt.Errorf("no paths found in response")
 	}
 
-	path := result.Data.SorGetSwapPaths.Paths[0]
-	pools := path.Pools
-
-	// Store path information for on-chain query
-	endpoint.SwapPathPools = pools
-	endpoint.SwapPathIsBuffer = path.IsBuffer
-
-	// Extract tokenOut for each step from tokens array
-	// tokens array contains: [tokenIn, intermediate1, intermediate2, ..., tokenOut]
-	// For step i, tokenOut is tokens[i+1].address
-	if len(path.Tokens) > 0 {
-		endpoint.SwapPathTokenOut = make([]string, len(pools))
-		for i := 0; i < len(pools); i++ {
-			if i+1 < len(path.Tokens) {
-				endpoint.SwapPathTokenOut[i] = path.Tokens[i+1].Address
-			} else {
-				// Fallback: if tokens array is shorter than expected, use the last token
-				endpoint.SwapPathTokenOut[i] = path.Tokens[len(path.Tokens)-1].Address
-			}
+	endpoint.SetRoute(result.routeGraph())
+
+	// Store the first path's steps for the on-chain query: each step's pool,
+	// tokenOut and whether it is a buffer
+	path := endpoint.Route.Paths[0]
+	lastTokenOut := ""
+	for _, hop := range path.Hops {
+		if hop.TokenOut != "" {
+			lastTokenOut = hop.TokenOut
 		}
 	}
-
-	// Check that at least one of the pools matches the expected pool
-	expectedPoolFound := false
-	for _, pool := range pools {
-		if pool == endpoint.ExpectedPool {
-			expectedPoolFound = true
-			break
+	endpoint.SwapPathPools, endpoint.SwapPathTokenOut, endpoint.SwapPathIsBuffer = nil, nil, nil
+	for _, hop := range path.Hops {
+		endpoint.SwapPathPools = append(endpoint.SwapPathPools, hop.Venues[0].Pool)
+		endpoint.SwapPathIsBuffer = append(endpoint.SwapPathIsBuffer, hop.Venues[0].Buffer)
+		if lastTokenOut == "" {
+			continue
 		}
+		tokenOut := hop.TokenOut
+		if tokenOut == "" {
+			// Fallback: if tokens array is shorter than expected, use the last token
+			tokenOut = lastTokenOut
+		}
+		endpoint.SwapPathTokenOut = append(endpoint.SwapPathTokenOut, tokenOut)
 	}
 
-	if !expectedPoolFound {
-		h.handleError(endpoint, "down", fmt.Sprintf("Expected pool %s not found in pools: %v", endpoint.ExpectedPool, pools), string(response.Body))
-		return fmt.Errorf("expected pool %s not found in pools: %v", endpoint.ExpectedPool, pools)
+	// Check that at least one of the pools matches the expected pool
+	if !(collector.RouteGraph{Paths: []collector.RoutePath{path}}).HasPool(endpoint.ExpectedPool) {
+		h.handleError(endpoint, "down", fmt.Sprintf("Expected pool %s not found in pools: %v", endpoint.ExpectedPool, endpoint.SwapPathPools), string(response.Body))
+		return fmt.Errorf("expected pool %s not found in pools: %v", endpoint.ExpectedPool, endpoint.SwapPathPools)
 	}
 
 	return nil
 }
 
 // HandleResponseForMarketPrice processes the Balancer SOR API response for market price (all sources)
-func (h *BalancerSORHandler) HandleResponseForMarketPrice(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *BalancerSORHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result BalancerSORResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -147,7 +144,7 @@ func (h *BalancerSORHandler) HandleResponseForMarketPrice(response *api.APIRespo
 		rawReturnAmount, err := h.convertFromDecimalAmount(result.Data.SorGetSwapPaths.ReturnAmount, endpoint.TokenOutDecimals)
 		if err != nil {
 			// Log the error but don't fail the request - just use the original decimal amount
-			fmt.Printf("Warning: Could not convert market price amount to raw format: %v\n", err)
+			logs.Printf("Warning: Could not convert market price amount to raw format: %v\n", err)
 			endpoint.MarketPrice = result.Data.SorGetSwapPaths.ReturnAmount
 		} else {
 			endpoint.MarketPrice = rawReturnAmount
@@ -186,8 +183,8 @@ func (h *BalancerSORHandler) GetIgnoreList(network string) (string, error) {
 func (h *BalancerSORHandler) handleError(endpoint *collector.Endpoint, status, message, responseBody string) {
 	endpoint.LastStatus = status
 	endpoint.Message = message
-	fmt.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
-	notifications.SendEmail(fmt.Sprintf("[%s] %s\nResponse body:\n%s", endpoint.Name, message, responseBody))
+	logs.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
+	notify.SendEndpointAlert(endpoint, message, responseBody)
 }
 
 // NewBalancerSORURLBuilder creates a new Balancer SOR URL builder
@@ -196,9 +193,10 @@ func NewBalancerSORURLBuilder() *BalancerSORURLBuilder {
 }
 
 // BuildURL builds the complete URL for Balancer SOR API requests
-func (b *BalancerSORURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.RequestOptions) (string, error) {
-	// Balancer SOR uses a fixed GraphQL endpoint
-	return "https://api-v3.balancer.fi/", nil
+func (b *BalancerSORURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
+	// Balancer SOR uses one GraphQL endpoint, BALANCER_API_URL; failover to
+	// BALANCER_API_FALLBACK_URLS is registered on the provider
+	return config.GetBalancerAPIURL(), nil
 }
 
 // NewBalancerSORRequestBodyBuilder creates a new Balancer SOR request body builder
@@ -207,11 +205,11 @@ func NewBalancerSORRequestBodyBuilder() *BalancerSORRequestBodyBuilder {
 }
 
 // BuildRequestBody builds the GraphQL query for Balancer SOR API requests
-func (b *BalancerSORRequestBodyBuilder) BuildRequestBody(endpoint *collector.Endpoint, options api.RequestOptions) ([]byte, error) {
+func (b *BalancerSORRequestBodyBuilder) BuildRequestBody(endpoint *collector.Endpoint, options check.RequestOptions) ([]byte, error) {
 	// Convert network to Balancer chain format
 	chain, err := b.convertNetworkToChain(endpoint.Network)
 	if err != nil {
-		return nil, fmt.Errorf("error converting network to chain: %v", err)
+		return nil, fmt.Errorf("error converting network to chain: %w", err)
 	}
 
 	// Convert swap amount from raw token amount to decimal format
@@ -223,7 +221,8 @@ func (b *BalancerSORRequestBodyBuilder) BuildRequestBody(endpoint *collector.End
 	// Build the GraphQL query
 	var query string
 	if options.IsBalancerSourceOnly {
-		// When IsBalancerSourceOnly is true, add poolIds parameter
+		// When IsBalancerSourceOnly is true, add poolIds parameter; the
+		// API's pool ids are lowercase
 		query = fmt.Sprintf(`{
 			sorGetSwapPaths(
 				chain: %s
@@ -245,7 +244,7 @@ func (b *BalancerSORRequestBodyBuilder) BuildRequestBody(endpoint *collector.End
 					isBuffer
 				}
 			}
-		}`, chain, decimalAmount, endpoint.TokenIn, endpoint.TokenOut, endpoint.ExpectedPool)
+		}`, chain, decimalAmount, endpoint.TokenIn, endpoint.TokenOut, config.NormalizeAddress(endpoint.ExpectedPool))
 	} else {
 		// Default query without poolIds
 		query = fmt.Sprintf(`{
@@ -285,21 +284,27 @@ func (b *BalancerSORRequestBodyBuilder) BuildRequestBody(endpoint *collector.End
 	return jsonBody, nil
 }
 
-// convertToDecimalAmount converts a raw token amount to decimal format using the token decimals
+// convertToDecimalAmount converts a raw token amount to decimal format using the token decimals.
+// Uses integer arithmetic so amounts beyond float precision convert exactly.
 func (b *BalancerSORRequestBodyBuilder) convertToDecimalAmount(rawAmount string, decimals int) (string, error) {
 	// Parse the raw amount as a big integer
 	rawInt, ok := new(big.Int).SetString(rawAmount, 10)
 	if !ok {
 		return "", fmt.Errorf("invalid raw amount: %s", rawAmount)
 	}
+	if decimals <= 0 {
+		return rawInt.String(), nil
+	}
 
-	// Convert to decimal by dividing by 10^decimals
-	decimalValue := new(big.Float).SetInt(rawInt)
-	divisor := new(big.Float).SetFloat64(math.Pow10(decimals))
-	result := new(big.Float).Quo(decimalValue, divisor)
+	// Split into whole and fractional parts by 10^decimals
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(new(big.Int).Abs(rawInt), divisor, new(big.Int))
 
-	// Format as string with appropriate precision
-	return result.Text('f', decimals), nil
+	sign := ""
+	if rawInt.Sign() < 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%s.%0*s", sign, whole.String(), decimals, frac.String()), nil
 }
 
 // convertNetworkToChain converts network ID to Balancer chain format
@@ -324,6 +329,28 @@ func (b *BalancerSORRequestBodyBuilder) convertNetworkToChain(network string) (s
 	case "143": // Monad
 		return "MONAD", nil
 	default:
-		return "", fmt.Errorf("unsupported network: %s", network)
+		return "", fmt.Errorf("%w: %s", check.ErrNetworkNotApplicable, network)
+	}
+}
+
+// routeGraph builds the route from the swap paths: pools[i] swaps tokens[i]
+// for tokens[i+1], through an ERC4626 buffer when isBuffer[i]. Venues name
+// no exchange; the SOR only routes through Balancer.
+func (r BalancerSORResponse) routeGraph() collector.RouteGraph {
+	g := collector.RouteGraph{MarksBuffers: true}
+	for _, p := range r.Data.SorGetSwapPaths.Paths {
+		var path collector.RoutePath
+		for i, pool := range p.Pools {
+			hop := collector.RouteHop{Venues: []collector.RouteVenue{{Pool: pool, Buffer: i < len(p.IsBuffer) && p.IsBuffer[i]}}}
+			if i < len(p.Tokens) {
+				hop.TokenIn = p.Tokens[i].Address
+			}
+			if i+1 < len(p.Tokens) {
+				hop.TokenOut = p.Tokens[i+1].Address
+			}
+			path.Hops = append(path.Hops, hop)
+		}
+		g.Paths = append(g.Paths, path)
 	}
+	return g
 }
@@ -6,9 +6,11 @@ import (
 	"net/url"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/api"
-	"go-monitoring/internal/collector"
-	"go-monitoring/notifications"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/sources"
 )
 
 // BarterResponse represents the structure of the Barter API response
@@ -57,7 +59,7 @@ func NewBarterHandler() *BarterHandler {
 }
 
 // HandleResponse processes the Barter API response and validates it according to business rules
-func (h *BarterHandler) HandleResponse(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *BarterHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result BarterResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -66,6 +68,8 @@ func (h *BarterHandler) HandleResponse(response *api.APIResponse, endpoint *coll
 		return fmt.Errorf("error parsing JSON: %v", err)
 	}
 
+	endpoint.SetRoute(result.routeGraph())
+
 	// Handle "NoRouteFound" status specifically - this is a valid response indicating no route exists
 	if result.Status == "NoRouteFound" {
 		h.handleError(endpoint, "down", fmt.Sprintf("Barter API returned NoRouteFound - no route available for %s", endpoint.Name), string(response.Body))
@@ -102,48 +106,39 @@ func (h *BarterHandler) HandleResponse(response *api.APIResponse, endpoint *coll
 
 	// Check all swaps are from BalancerV3 (when filtering for Balancer sources only)
 	// For Barter, we check the metadata.type field
-	for _, route := range result.Route {
-		for _, swap := range route.Swaps {
-			swapType := swap.SwapInfo.Metadata.Type
-			if swapType != "BalancerV3" {
-				endpoint.Message = fmt.Sprintf("Found swap type %s, expected BalancerV3", swapType)
-				prettyJSON, _ := json.MarshalIndent(result, "", "    ")
-				h.handleError(endpoint, "down", fmt.Sprintf("Found swap type %s, expected BalancerV3", swapType), string(prettyJSON))
-				return fmt.Errorf("found swap type %s, expected BalancerV3", swapType)
-			}
-		}
+	src, err := sources.ForEndpoint("barter", endpoint)
+	if err != nil {
+		h.handleError(endpoint, "error", err.Error(), "")
+		return err
 	}
-
-	// Check that at least one swap has the expected pool address
-	foundExpectedPool := false
-	for _, route := range result.Route {
-		for _, swap := range route.Swaps {
-			if swap.SwapInfo.Metadata.PoolAddress == endpoint.ExpectedPool {
-				foundExpectedPool = true
-				break
-			}
-		}
-		if foundExpectedPool {
-			break
+	for _, venue := range endpoint.Route.Venues() {
+		if !src.Matches(venue.Exchange) {
+			endpoint.Message = fmt.Sprintf("Found swap type %s, expected %s", venue.Exchange, src)
+			prettyJSON, _ := json.MarshalIndent(result, "", "    ")
+			h.handleError(endpoint, "down", fmt.Sprintf("Found swap type %s, expected %s", venue.Exchange, src), string(prettyJSON))
+			return fmt.Errorf("found swap type %s, expected %s", venue.Exchange, src)
 		}
 	}
 
-	if !foundExpectedPool {
+	// Check that at least one swap has the expected pool address
+	if !endpoint.Route.HasPool(endpoint.ExpectedPool) {
 		prettyJSON, _ := json.MarshalIndent(result, "", "    ")
 		h.handleError(endpoint, "down", fmt.Sprintf("Expected pool %s not found in route", endpoint.ExpectedPool), string(prettyJSON))
 		return fmt.Errorf("expected pool %s not found in route", endpoint.ExpectedPool)
 	}
 
-	// Store the return amount if available
-	if result.OutputAmount != "" {
-		endpoint.ReturnAmount = result.OutputAmount
+	// Store the return amount
+	if result.OutputAmount == "" {
+		h.handleError(endpoint, "down", "no outputAmount in response", string(response.Body))
+		return fmt.Errorf("no outputAmount in response")
 	}
+	endpoint.ReturnAmount = result.OutputAmount
 
 	return nil
 }
 
 // HandleResponseForMarketPrice processes the Barter API response for market price (all sources)
-func (h *BarterHandler) HandleResponseForMarketPrice(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *BarterHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result BarterResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -169,8 +164,8 @@ func (h *BarterHandler) GetIgnoreList(network string) (string, error) {
 func (h *BarterHandler) handleError(endpoint *collector.Endpoint, status, message, responseBody string) {
 	endpoint.LastStatus = status
 	endpoint.Message = message
-	fmt.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
-	notifications.SendEmail(fmt.Sprintf("[%s] %s\nResponse body:\n%s", endpoint.Name, message, responseBody))
+	logs.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
+	notify.SendEndpointAlert(endpoint, message, responseBody)
 }
 
 // NewBarterURLBuilder creates a new Barter URL builder
@@ -179,7 +174,7 @@ func NewBarterURLBuilder() *BarterURLBuilder {
 }
 
 // BuildURL builds the complete URL for Barter API requests
-func (b *BarterURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.RequestOptions) (string, error) {
+func (b *BarterURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
 	// Get the base URL based on the network
 	baseURL, err := b.getBaseURL(endpoint.Network)
 	if err != nil {
@@ -203,7 +198,7 @@ func (b *BarterURLBuilder) getBaseURL(network string) (string, error) {
 	case "100": // Gnosis
 		return "https://api2.gno.barterswap.xyz/route", nil
 	default:
-		return "", fmt.Errorf("unsupported network: %s", network)
+		return "", fmt.Errorf("%w: %s", check.ErrNetworkNotApplicable, network)
 	}
 }
 
@@ -213,7 +208,7 @@ func NewBarterRequestBodyBuilder() *BarterRequestBodyBuilder {
 }
 
 // BuildRequestBody builds the JSON request body for Barter API requests
-func (rb *BarterRequestBodyBuilder) BuildRequestBody(endpoint *collector.Endpoint, options api.RequestOptions) ([]byte, error) {
+func (rb *BarterRequestBodyBuilder) BuildRequestBody(endpoint *collector.Endpoint, options check.RequestOptions) ([]byte, error) {
 	// Create the base request body
 	requestBody := map[string]interface{}{
 		"source":     endpoint.TokenIn,
@@ -225,7 +220,11 @@ func (rb *BarterRequestBodyBuilder) BuildRequestBody(endpoint *collector.Endpoin
 	// Note: Barter API doesn't support "reCLAMM" as a typeFilter, so we only use "BalancerV3".
 	// The response validation requires all swaps to be "BalancerV3" type.
 	if options.IsBalancerSourceOnly {
-		requestBody["typeFilters"] = []string{"BalancerV3"}
+		src, err := sources.ForEndpoint("barter", endpoint)
+		if err != nil {
+			return nil, err
+		}
+		requestBody["typeFilters"] = src.IDs
 	}
 
 	// Convert to JSON
@@ -236,3 +235,24 @@ func (rb *BarterRequestBodyBuilder) BuildRequestBody(endpoint *collector.Endpoin
 
 	return jsonBody, nil
 }
+
+// routeGraph builds the route: each route is a path of one-pool swaps, each
+// swapping its input amount into its target token.
+func (r BarterResponse) routeGraph() collector.RouteGraph {
+	var g collector.RouteGraph
+	for _, route := range r.Route {
+		var path collector.RoutePath
+		tokenIn := route.SourceToken
+		for _, swap := range route.Swaps {
+			meta := swap.SwapInfo.Metadata
+			path.Hops = append(path.Hops, collector.RouteHop{
+				TokenIn:  tokenIn,
+				TokenOut: swap.SwapInfo.TargetToken,
+				Venues:   []collector.RouteVenue{{Pool: meta.PoolAddress, Exchange: meta.Type, AmountIn: swap.InputAmount, AmountOut: swap.OutputAmount}},
+			})
+			tokenIn = swap.SwapInfo.TargetToken
+		}
+		g.Paths = append(g.Paths, path)
+	}
+	return g
+}
@@ -0,0 +1,327 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+// Property tests for the URL / request-body builders. Each iteration builds a
+// random but valid endpoint (addresses, amounts, decimals, a network the
+// solver lists as supported) and checks invariants that must hold for every
+// input. OpenOcean is left out: its builder fetches gas prices and the dex
+// list over the network.
+
+const propertyIterations = 300
+
+var propertyPoolTypes = []struct{ poolType, hookType string }{
+	{"STABLE", ""},
+	{"STABLE", "STABLE_SURGE"},
+	{"GYROE", ""},
+	{"WEIGHTED", ""},
+	{"WEIGHTED", "ReCLAMM"},
+	{"QUANT_AMM", ""},
+}
+
+func randomAddress(r *rand.Rand) string {
+	const hexDigits = "0123456789abcdefABCDEF"
+	b := make([]byte, 40)
+	for i := range b {
+		b[i] = hexDigits[r.Intn(len(hexDigits))]
+	}
+	return "0x" + string(b)
+}
+
+// randomAmount returns a positive integer of up to 40 digits, covering both
+// tiny dust amounts and values well beyond float64 precision.
+func randomAmount(r *rand.Rand) string {
+	n := new(big.Int).Rand(r, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(1+r.Intn(40))), nil))
+	return n.Add(n, big.NewInt(1)).String()
+}
+
+func randomEndpoint(r *rand.Rand, solverType string) collector.Endpoint {
+	var networks []string
+	for _, s := range config.RouteSolvers {
+		if s.Type == solverType {
+			networks = s.SupportedNetworks
+		}
+	}
+	pt := propertyPoolTypes[r.Intn(len(propertyPoolTypes))]
+	return collector.Endpoint{
+		Name:             fmt.Sprintf("%s-property-%d", solverType, r.Int()),
+		RouteSolver:      solverType,
+		Network:          networks[r.Intn(len(networks))],
+		TokenIn:          randomAddress(r),
+		TokenOut:         randomAddress(r),
+		TokenInDecimals:  r.Intn(37),
+		TokenOutDecimals: r.Intn(37),
+		SwapAmount:       randomAmount(r),
+		ExpectedPool:     strings.ToLower(randomAddress(r)),
+		ExpectedNoHops:   1,
+		PoolType:         pt.poolType,
+		HookType:         pt.hookType,
+	}
+}
+
+// isBalancerV3Source reports whether a provider source id names Balancer V3.
+func isBalancerV3Source(source string) bool {
+	s := strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(source))
+	return strings.Contains(s, "balancerv3")
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("URL does not parse: %q: %v", raw, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		t.Fatalf("URL is not absolute https: %q", raw)
+	}
+	return u
+}
+
+func TestPropertyGETURLBuilders(t *testing.T) {
+	builders := []struct {
+		solver      string
+		builder     check.URLBuilder
+		amountParam string
+		// filter returns the Balancer-only filter value expected in the query,
+		// or "" when the builder excludes sources instead of including them.
+		filterParam string
+		filterValue func(e *collector.Endpoint) string
+	}{
+		{"0x", NewZeroXURLBuilder(), "sellAmount", "", nil},
+		{"1inch", NewOneInchURLBuilder(), "amount", "protocols", func(e *collector.Endpoint) string {
+			name, _ := (&OneInchHandler{}).GetBalancerName(e.Network)
+			return name
+		}},
+		{"hyperbloom", NewHyperBloomURLBuilder(), "sellAmount", "includedSources", func(*collector.Endpoint) string { return "BalancerV3" }},
+		{"kyberswap", NewKyberSwapURLBuilder(), "amountIn", "includedSources", func(e *collector.Endpoint) string {
+			src, _ := kyberIncludedBalancerV3Source(e)
+			return src
+		}},
+		{"paraswap", NewParaswapURLBuilder(), "amount", "includeDEXS", func(*collector.Endpoint) string { return "BalancerV3" }},
+	}
+
+	r := rand.New(rand.NewSource(4904))
+	for _, b := range builders {
+		t.Run(b.solver, func(t *testing.T) {
+			for i := 0; i < propertyIterations; i++ {
+				e := randomEndpoint(r, b.solver)
+				for _, balancerOnly := range []bool{true, false} {
+					raw, err := b.builder.BuildURL(&e, check.RequestOptions{IsBalancerSourceOnly: balancerOnly})
+					if err != nil {
+						t.Fatalf("BuildURL(%+v, balancerOnly=%v): %v", e, balancerOnly, err)
+					}
+					q := mustParseURL(t, raw).Query()
+
+					if got := q.Get(b.amountParam); got != e.SwapAmount {
+						t.Fatalf("%s = %q, want %q", b.amountParam, got, e.SwapAmount)
+					}
+
+					for _, excluded := range strings.Split(q.Get("excludedSources"), ",") {
+						if isBalancerV3Source(excluded) {
+							t.Fatalf("excludedSources contains Balancer V3 source %q on network %s", excluded, e.Network)
+						}
+					}
+					if b.solver == "0x" && balancerOnly && q.Get("excludedSources") == "" {
+						t.Fatalf("0x Balancer-only URL has no excludedSources: %s", raw)
+					}
+					if !balancerOnly && q.Has("excludedSources") {
+						t.Fatalf("market price URL should not exclude sources: %s", raw)
+					}
+
+					if b.filterParam == "" {
+						continue
+					}
+					if balancerOnly {
+						want := b.filterValue(&e)
+						if got := q.Get(b.filterParam); got != want || !isBalancerV3Source(got) {
+							t.Fatalf("%s = %q, want Balancer V3 filter %q", b.filterParam, got, want)
+						}
+					} else if q.Has(b.filterParam) {
+						t.Fatalf("market price URL should not filter sources: %s", raw)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPropertyBarterRequestBody(t *testing.T) {
+	urlBuilder := NewBarterURLBuilder()
+	bodyBuilder := NewBarterRequestBodyBuilder()
+	r := rand.New(rand.NewSource(4904))
+	for i := 0; i < propertyIterations; i++ {
+		e := randomEndpoint(r, "barter")
+		for _, balancerOnly := range []bool{true, false} {
+			opts := check.RequestOptions{IsBalancerSourceOnly: balancerOnly}
+			raw, err := urlBuilder.BuildURL(&e, opts)
+			if err != nil {
+				t.Fatalf("BuildURL: %v", err)
+			}
+			mustParseURL(t, raw)
+
+			body, err := bodyBuilder.BuildRequestBody(&e, opts)
+			if err != nil {
+				t.Fatalf("BuildRequestBody: %v", err)
+			}
+			var decoded struct {
+				SellAmount  string   `json:"sellAmount"`
+				TypeFilters []string `json:"typeFilters"`
+			}
+			if err := json.Unmarshal(body, &decoded); err != nil {
+				t.Fatalf("body is not JSON: %v", err)
+			}
+			if decoded.SellAmount != e.SwapAmount {
+				t.Fatalf("sellAmount = %q, want %q", decoded.SellAmount, e.SwapAmount)
+			}
+			if balancerOnly {
+				if len(decoded.TypeFilters) != 1 || decoded.TypeFilters[0] != "BalancerV3" {
+					t.Fatalf("typeFilters = %v, want [BalancerV3]", decoded.TypeFilters)
+				}
+			} else if decoded.TypeFilters != nil {
+				t.Fatalf("market price body should not filter: %s", body)
+			}
+		}
+	}
+}
+
+func TestPropertyOdosRequestBody(t *testing.T) {
+	urlBuilder := &OdosURLBuilder{}
+	bodyBuilder := &OdosRequestBodyBuilder{}
+	r := rand.New(rand.NewSource(4904))
+	for i := 0; i < propertyIterations; i++ {
+		e := randomEndpoint(r, "odos")
+		for _, balancerOnly := range []bool{true, false} {
+			opts := check.RequestOptions{IsBalancerSourceOnly: balancerOnly}
+			raw, err := urlBuilder.BuildURL(&e, opts)
+			if err != nil {
+				t.Fatalf("BuildURL: %v", err)
+			}
+			mustParseURL(t, raw)
+
+			body, err := bodyBuilder.BuildRequestBody(&e, opts)
+			if err != nil {
+				t.Fatalf("BuildRequestBody: %v", err)
+			}
+			var decoded OdosQuoteRequest
+			if err := json.Unmarshal(body, &decoded); err != nil {
+				t.Fatalf("body is not JSON: %v", err)
+			}
+			if decoded.ChainID != e.Network || len(decoded.InputTokens) != 1 || decoded.InputTokens[0].Amount != e.SwapAmount {
+				t.Fatalf("body does not round-trip endpoint: %s", body)
+			}
+			if balancerOnly {
+				if len(decoded.SourceWhitelist) == 0 {
+					t.Fatalf("Balancer-only body has no sourceWhitelist: %s", body)
+				}
+				for _, s := range decoded.SourceWhitelist {
+					if !isBalancerV3Source(s) {
+						t.Fatalf("sourceWhitelist contains non Balancer V3 source %q", s)
+					}
+				}
+			} else if len(decoded.SourceWhitelist) != 0 {
+				t.Fatalf("market price body should not whitelist: %s", body)
+			}
+		}
+	}
+}
+
+var sorSwapAmountRe = regexp.MustCompile(`swapAmount: "([0-9.]+)"`)
+
+func TestPropertyBalancerSORRequestBody(t *testing.T) {
+	urlBuilder := NewBalancerSORURLBuilder()
+	bodyBuilder := NewBalancerSORRequestBodyBuilder()
+	r := rand.New(rand.NewSource(4904))
+	for i := 0; i < propertyIterations; i++ {
+		e := randomEndpoint(r, "balancer_sor")
+		for _, balancerOnly := range []bool{true, false} {
+			opts := check.RequestOptions{IsBalancerSourceOnly: balancerOnly}
+			raw, err := urlBuilder.BuildURL(&e, opts)
+			if err != nil {
+				t.Fatalf("BuildURL: %v", err)
+			}
+			mustParseURL(t, raw)
+
+			body, err := bodyBuilder.BuildRequestBody(&e, opts)
+			if err != nil {
+				t.Fatalf("BuildRequestBody: %v", err)
+			}
+			var decoded struct {
+				Query string `json:"query"`
+			}
+			if err := json.Unmarshal(body, &decoded); err != nil {
+				t.Fatalf("body is not JSON: %v", err)
+			}
+
+			m := sorSwapAmountRe.FindStringSubmatch(decoded.Query)
+			if m == nil {
+				t.Fatalf("query has no swapAmount: %s", decoded.Query)
+			}
+			if got := decimalToRaw(t, m[1], e.TokenInDecimals); got != e.SwapAmount {
+				t.Fatalf("swapAmount %q with %d decimals = %s raw, want %s", m[1], e.TokenInDecimals, got, e.SwapAmount)
+			}
+
+			poolFilter := fmt.Sprintf(`poolIds: ["%s"]`, config.NormalizeAddress(e.ExpectedPool))
+			if balancerOnly && !strings.Contains(decoded.Query, poolFilter) {
+				t.Fatalf("Balancer-only query missing %s: %s", poolFilter, decoded.Query)
+			}
+			if !balancerOnly && strings.Contains(decoded.Query, "poolIds") {
+				t.Fatalf("market price query should not pin pools: %s", decoded.Query)
+			}
+		}
+	}
+}
+
+// FuzzBalancerSORDecimalAmount checks the raw → decimal conversion used in
+// the SOR query is exact for any non-negative amount and decimals.
+func FuzzBalancerSORDecimalAmount(f *testing.F) {
+	for _, e := range config.BaseEndpoints {
+		f.Add(e.SwapAmount, uint8(e.TokenInDecimals))
+	}
+	f.Add("123456789012345678901234567890", uint8(18))
+	f.Add("1", uint8(36))
+	f.Add("0", uint8(0))
+
+	b := NewBalancerSORRequestBodyBuilder()
+	f.Fuzz(func(t *testing.T, raw string, decimals uint8) {
+		d := int(decimals % 40)
+		n, ok := new(big.Int).SetString(raw, 10)
+		if !ok || n.Sign() < 0 {
+			t.Skip()
+		}
+		got, err := b.convertToDecimalAmount(raw, d)
+		if err != nil {
+			t.Fatalf("convertToDecimalAmount(%q, %d): %v", raw, d, err)
+		}
+		if back := decimalToRaw(t, got, d); back != n.String() {
+			t.Fatalf("convertToDecimalAmount(%q, %d) = %q, round-trips to %s", raw, d, got, back)
+		}
+	})
+}
+
+// decimalToRaw scales a plain decimal string back to an integer string.
+func decimalToRaw(t *testing.T, s string, decimals int) string {
+	t.Helper()
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > decimals {
+		t.Fatalf("%q has more than %d fractional digits", s, decimals)
+	}
+	frac += strings.Repeat("0", decimals-len(frac))
+	n, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok {
+		t.Fatalf("%q is not a decimal number", s)
+	}
+	return n.String()
+}
@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// callCacheTTL bounds how long an eth_call result is kept. Results are
+	// keyed by block, so they never go stale; the TTL only bounds memory, and
+	// a later cycle queries a later block anyway.
+	callCacheTTL = 2 * time.Minute
+	// callCacheMax bounds the cached results; past it expired ones are
+	// pruned, then the whole cache if that didn't help.
+	callCacheMax = 1024
+)
+
+// callKey identifies an eth_call: the block it was pinned to, the sender
+// and contract, and the calldata's hash. The same pool, tokens and amount at
+// the same block give the same key, whichever check asks.
+type callKey struct {
+	block    uint64
+	hash     common.Hash // the block's; block numbers repeat across networks
+	from, to common.Address
+	calldata common.Hash
+}
+
+// cachedCall is a successful eth_call result and when it was read.
+type cachedCall struct {
+	result []byte
+	at     time.Time
+}
+
+var (
+	callCacheMu sync.Mutex
+	callCache   = map[callKey]cachedCall{}
+	// callCacheHits counts results served from the cache since the last
+	// TakeCallCacheHits.
+	callCacheHits atomic.Int64
+)
+
+// newCallKey keys msg's call at head.
+func newCallKey(head blockRef, msg ethereum.CallMsg) callKey {
+	k := callKey{block: uint64(head.Number), hash: head.Hash, from: msg.From, calldata: crypto.Keccak256Hash(msg.Data)}
+	if msg.To != nil {
+		k.to = *msg.To
+	}
+	return k
+}
+
+// cachedResult returns the result of an identical call at the same block,
+// if one was read within callCacheTTL.
+func cachedResult(k callKey, now time.Time) ([]byte, bool) {
+	callCacheMu.Lock()
+	defer callCacheMu.Unlock()
+	c, ok := callCache[k]
+	if !ok || now.Sub(c.at) >= callCacheTTL {
+		return nil, false
+	}
+	callCacheHits.Add(1)
+	return c.result, true
+}
+
+// storeResult caches a successful call's result.
+func storeResult(k callKey, result []byte, now time.Time) {
+	callCacheMu.Lock()
+	defer callCacheMu.Unlock()
+	if len(callCache) >= callCacheMax {
+		for key, c := range callCache {
+			if now.Sub(c.at) >= callCacheTTL {
+				delete(callCache, key)
+			}
+		}
+		if len(callCache) >= callCacheMax {
+			clear(callCache)
+		}
+	}
+	callCache[k] = cachedCall{result: result, at: now}
+}
+
+// TakeCallCacheHits returns how many eth_calls were answered from the cache
+// since the last call, and resets the count.
+func TakeCallCacheHits() int64 {
+	return callCacheHits.Swap(0)
+}
@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCallCache(t *testing.T) {
+	clear(callCache)
+	TakeCallCacheHits()
+	t.Cleanup(func() { clear(callCache) })
+
+	to := common.HexToAddress(vaultAddress)
+	msg := ethereum.CallMsg{To: &to, Data: []byte{1, 2, 3}}
+	head := blockRef{Number: 100, Hash: common.Hash{0xaa}}
+	now := time.Now()
+	key := newCallKey(head, msg)
+	if _, ok := cachedResult(key, now); ok {
+		t.Fatal("empty cache hit")
+	}
+	storeResult(key, []byte{9}, now)
+	if got, ok := cachedResult(newCallKey(head, msg), now.Add(time.Second)); !ok || !bytes.Equal(got, []byte{9}) {
+		t.Fatalf("same call = %v, %v", got, ok)
+	}
+
+	for name, k := range map[string]callKey{
+		"later block":  newCallKey(blockRef{Number: 101, Hash: common.Hash{0xbb}}, msg),
+		"other hash":   newCallKey(blockRef{Number: 100, Hash: common.Hash{0xcc}}, msg),
+		"other amount": newCallKey(head, ethereum.CallMsg{To: &to, Data: []byte{1, 2, 4}}),
+		"other target": newCallKey(head, ethereum.CallMsg{Data: msg.Data}),
+		"other sender": newCallKey(head, ethereum.CallMsg{From: to, To: &to, Data: msg.Data}),
+	} {
+		if _, ok := cachedResult(k, now); ok {
+			t.Errorf("%s hit", name)
+		}
+	}
+	if _, ok := cachedResult(key, now.Add(callCacheTTL)); ok {
+		t.Error("expired result hit")
+	}
+	if hits := TakeCallCacheHits(); hits != 1 {
+		t.Errorf("hits = %d, want 1", hits)
+	}
+	if hits := TakeCallCacheHits(); hits != 0 {
+		t.Errorf("hits after take = %d", hits)
+	}
+
+	for i := range callCacheMax {
+		storeResult(newCallKey(blockRef{Number: 200, Hash: common.Hash{0xdd}}, ethereum.CallMsg{Data: []byte{byte(i), byte(i >> 8)}}), nil, now)
+	}
+	if len(callCache) > callCacheMax {
+		t.Errorf("cache grew to %d", len(callCache))
+	}
+}
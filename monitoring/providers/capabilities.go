@@ -0,0 +1,47 @@
+package providers
+
+// Capabilities are the features a provider's API offers the checks. The
+// registry schedules only the calls a provider supports, and the dashboard
+// renders "not supported" where a feature is missing rather than an empty
+// cell. The zero value supports nothing: a provider declares what it has.
+type Capabilities struct {
+	// MarketPrice: the API quotes all sources, so the market price call
+	// runs after the Balancer-only one. Without it the call is skipped.
+	MarketPrice bool
+	// ExactOut: the API can quote a fixed output amount.
+	ExactOut bool
+	// SourceWhitelist: the API can restrict a quote to given sources, which
+	// the Balancer-only check relies on (RequestOptions.IsBalancerSourceOnly).
+	SourceWhitelist bool
+	// Gas: the response estimates the swap's gas.
+	Gas bool
+	// Route: the response lists the route, which the handler passes to
+	// Endpoint.SetRoute. Hop counts, source rules and the exclusion check
+	// need it.
+	Route bool
+}
+
+// Feature is one capability, for listing them. ID is stable, e.g.
+// "market_price", for pages to name it by.
+type Feature struct {
+	ID        string
+	Supported bool
+}
+
+// Features lists c's capabilities in a fixed order.
+func (c Capabilities) Features() []Feature {
+	return []Feature{
+		{"market_price", c.MarketPrice},
+		{"exact_out", c.ExactOut},
+		{"source_whitelist", c.SourceWhitelist},
+		{"gas", c.Gas},
+		{"route", c.Route},
+	}
+}
+
+// Capabilities returns what the provider registered for a route solver type
+// supports; ok is false when none is registered.
+func (r *Registry) Capabilities(routeSolver string) (caps Capabilities, ok bool) {
+	cfg, ok := r.providers[routeSolver]
+	return cfg.Capabilities, ok
+}
@@ -0,0 +1,110 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/shared"
+)
+
+// SourceCatalog lists the liquidity source IDs a provider offers on a
+// network, named as its routes report them. Registered on ProviderConfig for
+// providers that publish a catalog; the monitor scans it for Balancer V3
+// sources the sources registry does not know yet.
+type SourceCatalog func(network string) ([]string, error)
+
+// catalogTimeout bounds one catalog request.
+const catalogTimeout = 10 * time.Second
+
+// getCatalogJSON GETs a provider catalog into out, spaced by the provider's
+// rate limiter like its quote requests.
+func getCatalogJSON(routeSolver, rawURL string, headers map[string]string, out any) error {
+	shared.Wait("provider:"+routeSolver, config.GetRouteSolverRateLimit(routeSolver))
+
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", config.GetUserAgent())
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := (&http.Client{Timeout: catalogTimeout}).Do(req)
+	if err != nil {
+		return fmt.Errorf("error fetching source catalog: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading source catalog: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("source catalog returned status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error parsing source catalog: %v", err)
+	}
+	return nil
+}
+
+// zeroXSourceCatalog lists 0x's liquidity sources (e.g. "Balancer_V3").
+func zeroXSourceCatalog(network string) ([]string, error) {
+	apiKey := os.Getenv("ZEROX_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("ZEROX_API_KEY is not set")
+	}
+	var result struct {
+		Sources []string `json:"sources"`
+	}
+	err := getCatalogJSON("0x", "https://api.0x.org/sources?chainId="+url.QueryEscape(network),
+		map[string]string{"0x-api-key": apiKey, "0x-version": "v2"}, &result)
+	return result.Sources, err
+}
+
+// kyberSwapSourceCatalog lists KyberSwap's enabled DEX IDs (e.g.
+// "balancer-v3-stable").
+func kyberSwapSourceCatalog(network string) ([]string, error) {
+	chain := (&KyberSwapHandler{}).GetChainName(network)
+	var result struct {
+		Data struct {
+			Dexes []struct {
+				DexID string `json:"dexId"`
+			} `json:"dexes"`
+		} `json:"data"`
+	}
+	err := getCatalogJSON("kyberswap", "https://ks-setting.kyberswap.com/api/v1/dexes?isEnabled=true&pageSize=1000&chain="+url.QueryEscape(chain),
+		map[string]string{"x-client-id": "BalancerTest"}, &result)
+	ids := make([]string, 0, len(result.Data.Dexes))
+	for _, dex := range result.Data.Dexes {
+		ids = append(ids, dex.DexID)
+	}
+	return ids, err
+}
+
+// openOceanSourceCatalog lists the codes in OpenOcean's dexList (e.g.
+// "BalancerV3").
+func openOceanSourceCatalog(network string) ([]string, error) {
+	dexes, err := fetchOpenOceanDexList((&OpenOceanURLBuilder{}).getChainName(network))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(dexes))
+	for _, dex := range dexes {
+		ids = append(ids, dex.Code)
+	}
+	return ids, nil
+}
+
+// paraswapSourceCatalog lists Paraswap's DEX names (e.g. "BalancerV3").
+func paraswapSourceCatalog(network string) ([]string, error) {
+	var ids []string
+	err := getCatalogJSON("paraswap", "https://api.paraswap.io/adapters/list?namesOnly=true&network="+url.QueryEscape(network), nil, &ids)
+	return ids, err
+}
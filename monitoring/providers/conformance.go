@@ -0,0 +1,137 @@
+package providers
+
+import (
+	"fmt"
+	"testing"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+)
+
+// ConformanceCase is a provider's fixtures for Conformance.
+type ConformanceCase struct {
+	// Endpoint is the row the fixtures quote; each check gets a fresh copy.
+	Endpoint collector.Endpoint
+	// Success is a 200 body the handler must accept, storing ReturnAmount.
+	Success      string
+	ReturnAmount string
+	// WithoutAmount is Success without its quoted amount; the handler must
+	// reject it rather than report the row up with no ReturnAmount.
+	WithoutAmount string
+}
+
+// conformanceFailure is a response every handler must reject.
+type conformanceFailure struct {
+	name   string
+	status int
+	body   string
+}
+
+// conformanceFailures are what providers, CDNs and proxies send when
+// something is wrong.
+var conformanceFailures = []conformanceFailure{
+	{"malformed JSON", 200, `{"`},
+	{"empty body", 200, ``},
+	{"null", 200, `null`},
+	{"array", 200, `[]`},
+	{"empty object", 200, `{}`},
+	{"500 error", 500, `{"error":"boom"}`},
+	{"429 text", 429, `Too Many Requests`},
+}
+
+// Conformance checks that h keeps the ResponseHandler contract the API
+// client relies on:
+//
+//   - malformed JSON, an empty body, a non-200 response or one missing the
+//     route or amount fields never panics and HandleResponse returns an error;
+//   - the Success fixture is accepted and sets ReturnAmount (and its market
+//     price call sets MarketPrice);
+//   - LastStatus is only ever set to a collector.IsKnownStatus value.
+//
+// Every registered provider runs through it in conformance_test.go; embedders
+// registering their own providers can call it from their tests. Alerts the
+// handler raises are dropped.
+func Conformance(t testing.TB, h ResponseHandler, c ConformanceCase) {
+	t.Helper()
+	notify.HoldGroup(c.Endpoint.BaseName)
+	defer notify.DropGroup(c.Endpoint.BaseName)
+
+	failures := conformanceFailures
+	if c.WithoutAmount != "" {
+		failures = append(failures[:len(failures):len(failures)], conformanceFailure{"without amount", 200, c.WithoutAmount})
+	}
+	for _, f := range failures {
+		response := &check.APIResponse{StatusCode: f.status, Body: []byte(f.body)}
+
+		e := conformanceEndpoint(c)
+		err := callHandler(func() error { return h.HandleResponse(response, &e) })
+		switch {
+		case err == nil:
+			t.Errorf("%s: HandleResponse accepted it (ReturnAmount %q)", f.name, e.ReturnAmount)
+		case isPanic(err):
+			t.Errorf("%s: HandleResponse %v", f.name, err)
+		}
+		checkStatus(t, f.name+": HandleResponse", e)
+
+		e = conformanceEndpoint(c)
+		if err := callHandler(func() error { return h.HandleResponseForMarketPrice(response, &e) }); isPanic(err) {
+			t.Errorf("%s: HandleResponseForMarketPrice %v", f.name, err)
+		}
+		checkStatus(t, f.name+": HandleResponseForMarketPrice", e)
+	}
+
+	response := &check.APIResponse{StatusCode: 200, Body: []byte(c.Success)}
+	e := conformanceEndpoint(c)
+	if err := callHandler(func() error { return h.HandleResponse(response, &e) }); err != nil {
+		t.Errorf("success: HandleResponse: %v", err)
+	} else if e.ReturnAmount != c.ReturnAmount {
+		t.Errorf("success: ReturnAmount = %q, want %q", e.ReturnAmount, c.ReturnAmount)
+	}
+	checkStatus(t, "success: HandleResponse", e)
+
+	e = conformanceEndpoint(c)
+	if err := callHandler(func() error { return h.HandleResponseForMarketPrice(response, &e) }); err != nil {
+		t.Errorf("success: HandleResponseForMarketPrice: %v", err)
+	} else if e.MarketPrice == "" {
+		t.Error("success: HandleResponseForMarketPrice set no MarketPrice")
+	}
+	checkStatus(t, "success: HandleResponseForMarketPrice", e)
+}
+
+// conformanceEndpoint returns a fresh copy of the case's endpoint, as the
+// store hands it to a check.
+func conformanceEndpoint(c ConformanceCase) collector.Endpoint {
+	e := c.Endpoint
+	e.LastStatus = "unknown"
+	e.ReturnAmount = ""
+	e.MarketPrice = ""
+	return e
+}
+
+// handlerPanic is the error callHandler returns when the handler panicked.
+type handlerPanic struct{ value any }
+
+func (p handlerPanic) Error() string { return fmt.Sprintf("panicked: %v", p.value) }
+
+func isPanic(err error) bool {
+	_, ok := err.(handlerPanic)
+	return ok
+}
+
+// callHandler runs call, turning a panic into a handlerPanic error.
+func callHandler(call func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = handlerPanic{r}
+		}
+	}()
+	return call()
+}
+
+func checkStatus(t testing.TB, what string, e collector.Endpoint) {
+	t.Helper()
+	if !collector.IsKnownStatus(e.LastStatus) {
+		t.Errorf("%s: LastStatus = %q, not a known status", what, e.LastStatus)
+	}
+}
@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+// conformanceCases holds a quote fixture for every provider NewDefaultRegistry
+// registers, for the row below.
+var conformanceCases = map[string]ConformanceCase{
+	"0x": {
+		Success:       `{"buyAmount":"1000","route":{"fills":[{"from":"0xin","to":"0xout","source":"Balancer_V3"}],"tokens":[{"address":"0xin"},{"address":"0xout"}]}}`,
+		ReturnAmount:  "1000",
+		WithoutAmount: `{"route":{"fills":[{"from":"0xin","to":"0xout","source":"Balancer_V3"}],"tokens":[{"address":"0xin"},{"address":"0xout"}]}}`,
+	},
+	"paraswap": {
+		Success:       `{"priceRoute":{"destAmount":"1000","bestRoute":[{"swaps":[{"swapExchanges":[{"exchange":"BalancerV3","poolAddresses":["0xpool"]}]}]}]}}`,
+		ReturnAmount:  "1000",
+		WithoutAmount: `{"priceRoute":{"bestRoute":[{"swaps":[{"swapExchanges":[{"exchange":"BalancerV3","poolAddresses":["0xpool"]}]}]}]}}`,
+	},
+	"1inch": {
+		Success:       `{"dstAmount":"1000","protocols":[[[{"name":"BALANCER_V3","part":100}]]]}`,
+		ReturnAmount:  "1000",
+		WithoutAmount: `{"protocols":[[[{"name":"BALANCER_V3","part":100}]]]}`,
+	},
+	"hyperbloom": {
+		Success:       `{"buyAmount":"1000","price":"1","sources":[{"name":"BalancerV3","proportion":"1"}],"sellTokenAddress":"0xin","buyTokenAddress":"0xout"}`,
+		ReturnAmount:  "1000",
+		WithoutAmount: `{"price":"1","sources":[{"name":"BalancerV3","proportion":"1"}],"sellTokenAddress":"0xin","buyTokenAddress":"0xout"}`,
+	},
+	"kyberswap": {
+		Success:       `{"code":0,"data":{"routeSummary":{"amountOut":"1000","routeID":"r1","route":[[{"pool":"0xpool","tokenIn":"0xin","tokenOut":"0xout","exchange":"balancer-v3-weighted"}]]}}}`,
+		ReturnAmount:  "1000",
+		WithoutAmount: `{"code":0,"data":{"routeSummary":{"routeID":"r1","route":[[{"pool":"0xpool","tokenIn":"0xin","tokenOut":"0xout","exchange":"balancer-v3-weighted"}]]}}}`,
+	},
+	"odos": {
+		Success:       `{"outAmounts":["1000"],"outValues":[1.5]}`,
+		ReturnAmount:  "1000",
+		WithoutAmount: `{"outValues":[1.5]}`,
+	},
+	"balancer_sor": {
+		Success:       `{"data":{"sorGetSwapPaths":{"swapAmount":"1","returnAmount":"1.5","paths":[{"pools":["0xpool"],"tokens":[{"address":"0xin"},{"address":"0xout"}],"isBuffer":[false]}]}}}`,
+		ReturnAmount:  "1500000",
+		WithoutAmount: `{"data":{"sorGetSwapPaths":{"swapAmount":"1","paths":[{"pools":["0xpool"],"tokens":[{"address":"0xin"},{"address":"0xout"}],"isBuffer":[false]}]}}}`,
+	},
+	"barter": {
+		Success:       `{"status":"Normal","outputAmount":"1000","route":[{"swaps":[{"swapInfo":{"metadata":{"type":"BalancerV3","poolAddress":"0xpool"}}}]}]}`,
+		ReturnAmount:  "1000",
+		WithoutAmount: `{"status":"Normal","route":[{"swaps":[{"swapInfo":{"metadata":{"type":"BalancerV3","poolAddress":"0xpool"}}}]}]}`,
+	},
+	"openocean": {
+		Success:       `{"code":200,"data":{"outAmount":"1000","path":{"routes":[{"subRoutes":[{"dexes":[{"dex":"BalancerV3","id":"0xpool"}]}]}]}}}`,
+		ReturnAmount:  "1000",
+		WithoutAmount: `{"code":200,"data":{"path":{"routes":[{"subRoutes":[{"dexes":[{"dex":"BalancerV3","id":"0xpool"}]}]}]}}}`,
+	},
+}
+
+func TestProvidersConformance(t *testing.T) {
+	row := collector.Endpoint{
+		Name:             "WETH/USDC Weighted",
+		BaseName:         "conformance",
+		Network:          "1",
+		TokenIn:          "0xin",
+		TokenOut:         "0xout",
+		TokenOutDecimals: 6,
+		ExpectedPool:     "0xpool",
+		ExpectedNoHops:   1,
+		PoolType:         "WEIGHTED",
+	}
+	r := NewDefaultRegistry()
+	for name, provider := range r.providers {
+		c, ok := conformanceCases[name]
+		if !ok {
+			t.Errorf("%s: no conformance fixture", name)
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			c.Endpoint = row
+			c.Endpoint.RouteSolver = name
+			Conformance(t, provider.Handler, c)
+
+			// A declared Route capability matches what the handler reports.
+			e := c.Endpoint
+			provider.Handler.HandleResponse(&check.APIResponse{StatusCode: 200, Body: []byte(c.Success)}, &e)
+			if got := !e.Route.IsEmpty(); got != provider.Capabilities.Route {
+				t.Errorf("Capabilities.Route = %v, but the success fixture sets a route: %v", provider.Capabilities.Route, got)
+			}
+		})
+	}
+}
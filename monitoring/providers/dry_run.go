@@ -0,0 +1,41 @@
+package providers
+
+import (
+	"fmt"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+)
+
+// dryRunOnChainCall builds the balancer_sor on-chain query calldata without
+// calling the RPC. No API response exists in dry-run mode, so when the
+// endpoint has no swap path yet the expected pool is used as a single hop.
+func dryRunOnChainCall(endpoint *collector.Endpoint) {
+	probe := *endpoint
+	if len(probe.SwapPathPools) == 0 {
+		if probe.ExpectedPool == "" {
+			logs.Printf("%s[DRY RUN]%s %s: no path or expected pool, skipping calldata\n", config.ColorYellow, config.ColorReset, endpoint.Name)
+			return
+		}
+		probe.SwapPathPools = []string{probe.ExpectedPool}
+		probe.SwapPathTokenOut = []string{probe.TokenOut}
+		probe.SwapPathIsBuffer = []bool{false}
+	}
+
+	contract, calldata, err := BuildOnChainCall(&probe)
+	if err != nil {
+		endpoint.OnChainPrice = ""
+		endpoint.OnChainQueryError = fmt.Sprintf("Dry run: %v", err)
+		logs.Printf("%s[DRY RUN]%s %s: on-chain calldata failed: %v\n", config.ColorRed, config.ColorReset, endpoint.Name, err)
+		return
+	}
+
+	rpcState := "set"
+	if config.GetRPCURL(endpoint.Network) == "" {
+		rpcState = "missing"
+	}
+	endpoint.OnChainQueryError = ""
+	logs.Printf("%s[DRY RUN]%s %s: eth_call to %s, %d bytes calldata (RPC URL %s)\n",
+		config.ColorYellow, config.ColorReset, endpoint.Name, contract, len(calldata), rpcState)
+}
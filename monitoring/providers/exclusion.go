@@ -0,0 +1,116 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-monitoring/internal/api"
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/sources"
+)
+
+// RouteParser is implemented by handlers that can return the route of a
+// quote response without validating it or writing to an endpoint, which the
+// exclusion check needs.
+type RouteParser interface {
+	ParseRoute(body []byte) (collector.RouteGraph, error)
+}
+
+// Exclusion check outcomes.
+const (
+	ExclusionPass  = "pass"  // the route avoids Balancer V3
+	ExclusionFail  = "fail"  // the route goes through Balancer V3 anyway
+	ExclusionError = "error" // no route to judge: the request or parsing failed
+)
+
+// ExclusionResult is the outcome of one exclusion check.
+type ExclusionResult struct {
+	Endpoint  string               `json:"endpoint"`
+	Solver    string               `json:"solver"`
+	CheckedAt time.Time            `json:"checkedAt"`
+	Excluded  []string             `json:"excluded"` // source IDs sent in the exclude filter
+	Status    string               `json:"status"`   // Exclusion* constant
+	Message   string               `json:"message"`
+	Route     collector.RouteGraph `json:"route"`
+}
+
+// CheckExclusion is the negative test of the Balancer-only methodology: it
+// quotes endpoint with every Balancer V3 source of its provider excluded and
+// fails when the route still goes through Balancer V3 (a Balancer V3 source
+// or the endpoint's ExpectedPool), which means the provider ignores its
+// source filter parameters and the Balancer-only checks prove nothing. ok is
+// false when the provider isn't registered with ExclusionCheck and the Route
+// capability, or its API key isn't set. The endpoint is not modified and no alert is sent.
+func (r *Registry) CheckExclusion(endpoint collector.Endpoint) (ExclusionResult, bool) {
+	cfg, exists := r.providers[endpoint.RouteSolver]
+	if !exists || !cfg.ExclusionCheck || !cfg.Capabilities.Route {
+		return ExclusionResult{}, false
+	}
+	parser, isParser := cfg.Handler.(RouteParser)
+	if !isParser {
+		return ExclusionResult{}, false
+	}
+	var apiKey string
+	if cfg.APIKeyEnvVar != "" {
+		if apiKey = os.Getenv(cfg.APIKeyEnvVar); apiKey == "" {
+			return ExclusionResult{}, false
+		}
+	}
+
+	res := ExclusionResult{
+		Endpoint:  endpoint.Name,
+		Solver:    endpoint.RouteSolver,
+		CheckedAt: clock.Now(),
+		Excluded:  sources.AllIDs(sources.Table(), endpoint.RouteSolver, endpoint.Network),
+	}
+	if len(res.Excluded) == 0 {
+		return ExclusionResult{}, false
+	}
+	options := check.RequestOptions{
+		CustomHeaders:  requestHeaders(endpoint.RouteSolver, cfg.CustomHeaders, apiKey),
+		ExcludeSources: res.Excluded,
+	}
+	resp, err := api.NewAPIClient().Fetch(endpoint, cfg.URLBuilder, cfg.RequestBodyBuilder, cfg.UsePOST, options)
+	if err != nil {
+		res.Status, res.Message = ExclusionError, err.Error()
+		return res, true
+	}
+	if resp.StatusCode != 200 {
+		res.Status, res.Message = ExclusionError, fmt.Sprintf("HTTP %d", resp.StatusCode)
+		return res, true
+	}
+	route, err := parser.ParseRoute(resp.Body)
+	if err != nil {
+		res.Status, res.Message = ExclusionError, err.Error()
+		return res, true
+	}
+	res.Route = route
+	res.Status, res.Message = judgeExclusion(route, endpoint.ExpectedPool)
+	return res, true
+}
+
+// judgeExclusion decides whether a route quoted with Balancer V3 excluded
+// kept out of it. An empty route passes: with Balancer V3 gone there may be
+// nothing else to route through.
+func judgeExclusion(route collector.RouteGraph, expectedPool string) (status, message string) {
+	var leaked []string
+	for _, v := range route.Venues() {
+		if sources.IsBalancerV3(v.Exchange) {
+			leaked = append(leaked, v.String())
+		}
+	}
+	if len(leaked) > 0 {
+		return ExclusionFail, fmt.Sprintf("route goes through excluded Balancer V3 sources: %s", strings.Join(leaked, ", "))
+	}
+	if expectedPool != "" && route.HasPool(expectedPool) {
+		return ExclusionFail, fmt.Sprintf("route goes through the Balancer V3 pool %s", collector.ShortAddress(expectedPool))
+	}
+	if route.IsEmpty() {
+		return ExclusionPass, "no route without Balancer V3"
+	}
+	return ExclusionPass, "route avoids Balancer V3"
+}
@@ -0,0 +1,95 @@
+package providers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+// redirectURLBuilder builds the wrapped builder's query against a test
+// server.
+type redirectURLBuilder struct {
+	URLBuilder
+	server string
+}
+
+func (b redirectURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
+	raw, err := b.URLBuilder.BuildURL(endpoint, options)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	return b.server + "?" + u.RawQuery, nil
+}
+
+func TestCheckExclusion(t *testing.T) {
+	var query url.Values
+	body := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	r := NewRegistry()
+	r.Register("paraswap", ProviderConfig{
+		Handler:        NewParaswapHandler(),
+		URLBuilder:     redirectURLBuilder{NewParaswapURLBuilder(), srv.URL},
+		ExclusionCheck: true,
+		Capabilities:   Capabilities{Route: true},
+	})
+	e := collector.Endpoint{Name: "Paraswap-Stable", RouteSolver: "paraswap", Network: "1", ExpectedPool: "0xpool"}
+
+	// Ignoring the filter: the route still goes through Balancer V3.
+	body = `{"priceRoute":{"bestRoute":[{"percent":100,"swaps":[{"srcToken":"0xa","destToken":"0xb",
+		"swapExchanges":[{"exchange":"BalancerV3","percent":100,"poolAddresses":["0xpool"]}]}]}]}}`
+	res, ok := r.CheckExclusion(e)
+	if !ok || res.Status != ExclusionFail || !strings.Contains(res.Message, "BalancerV3") {
+		t.Fatalf("ignored filter: ok=%v %+v", ok, res)
+	}
+	if query.Get("excludeDEXS") != "BalancerV3" || query.Has("includeDEXS") {
+		t.Fatalf("query = %v", query)
+	}
+
+	// Honoring it.
+	body = `{"priceRoute":{"bestRoute":[{"percent":100,"swaps":[{"srcToken":"0xa","destToken":"0xb",
+		"swapExchanges":[{"exchange":"UniswapV3","percent":100,"poolAddresses":["0xuni"]}]}]}]}}`
+	if res, _ := r.CheckExclusion(e); res.Status != ExclusionPass {
+		t.Fatalf("honored filter: %+v", res)
+	}
+
+	body = `{"error":"Internal error"}`
+	if res, _ := r.CheckExclusion(e); res.Status != ExclusionError {
+		t.Fatalf("API error: %+v", res)
+	}
+
+	// Providers not registered for the check are skipped.
+	r.Register("paraswap", ProviderConfig{Handler: NewParaswapHandler(), URLBuilder: NewParaswapURLBuilder()})
+	if _, ok := r.CheckExclusion(e); ok {
+		t.Fatal("checked a provider without ExclusionCheck")
+	}
+	r.Register("paraswap", ProviderConfig{Handler: NewParaswapHandler(), URLBuilder: NewParaswapURLBuilder(), ExclusionCheck: true})
+	if _, ok := r.CheckExclusion(e); ok {
+		t.Fatal("checked a provider without the Route capability")
+	}
+}
+
+func TestJudgeExclusionExpectedPool(t *testing.T) {
+	// An unnamed exchange through the endpoint's Balancer pool still fails.
+	route := collector.RouteGraph{Paths: []collector.RoutePath{{Hops: []collector.RouteHop{{Venues: []collector.RouteVenue{{Pool: "0xPOOL"}}}}}}}
+	if status, _ := judgeExclusion(route, "0xpool"); status != ExclusionFail {
+		t.Fatalf("status = %q", status)
+	}
+	if status, msg := judgeExclusion(collector.RouteGraph{}, "0xpool"); status != ExclusionPass || msg != "no route without Balancer V3" {
+		t.Fatalf("empty route: %q %q", status, msg)
+	}
+}
@@ -0,0 +1,216 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/shared"
+)
+
+const (
+	// gasPriceTTL is how long a network's gas price is cached: long enough
+	// for a check cycle's builders on the network to share one lookup, short
+	// enough to follow gas.
+	gasPriceTTL = 5 * time.Minute
+	// gasPriceFallbackTTL is how long a network's default is served after
+	// its lookup fails, so a down node costs one timeout per window rather
+	// than one per builder.
+	gasPriceFallbackTTL = 30 * time.Second
+	// gasPriceTimeout bounds each RPC lookup.
+	gasPriceTimeout = 10 * time.Second
+	// feeHistoryBlocks and feeHistoryPercentile are the eth_feeHistory
+	// window: the median priority fee over the last few blocks.
+	feeHistoryBlocks     = 5
+	feeHistoryPercentile = 50
+)
+
+// GasPrice is a network's gas price and where it was read.
+type GasPrice struct {
+	Wei    *big.Int
+	Source string // "eth_feeHistory", "eth_gasPrice" or "default"
+}
+
+// String returns the price in wei, in decimal.
+func (g GasPrice) String() string {
+	return g.Wei.String()
+}
+
+// Cost returns what gas units cost at g, in wei, for comparing quotes net of
+// gas.
+func (g GasPrice) Cost(gas uint64) *big.Int {
+	return new(big.Int).Mul(g.Wei, new(big.Int).SetUint64(gas))
+}
+
+// gasPriceSource reads a gas price from a network's RPC node.
+type gasPriceSource struct {
+	name  string
+	fetch func(rpcURL string) (*big.Int, error)
+}
+
+// gasPriceSources are tried in order; tests replace them.
+var gasPriceSources = []gasPriceSource{
+	{"eth_feeHistory", feeHistoryGasPrice},
+	{"eth_gasPrice", rpcGasPrice},
+}
+
+// defaultGasPrices are the fallback gas prices in wei by chain ID, for a
+// network without an RPC URL or whose node fails.
+var defaultGasPrices = map[string]int64{
+	"1":      30_000_000_000, // 30 gwei
+	"10":     1_000_000,      // 0.001 gwei
+	"56":     3_000_000_000,  // 3 gwei
+	"100":    2_000_000_000,  // 2 gwei
+	"137":    30_000_000_000, // 30 gwei
+	"143":    1_000_000_000,  // 1 gwei
+	"250":    50_000_000_000, // 50 gwei
+	"324":    250_000_000,    // 0.25 gwei
+	"8453":   1_000_000,      // 0.001 gwei
+	"42161":  100_000_000,    // 0.1 gwei
+	"43114":  25_000_000_000, // 25 gwei
+	"59144":  50_000_000,     // 0.05 gwei
+	"534352": 100_000_000,    // 0.1 gwei
+}
+
+// fallbackGasPrice is the default for a network missing from
+// defaultGasPrices.
+const fallbackGasPrice = 30_000_000_000 // 30 gwei
+
+// GasPriceFor returns the network's gas price for provider builders and
+// gas-adjusted comparisons: from the network's RPC node (GetRPCURL), by
+// eth_feeHistory (next base fee plus median tip) or else eth_gasPrice,
+// cached across instances for gasPriceTTL. Without an RPC URL, in dry run,
+// or when both fail, it returns the network's default, cached only for
+// gasPriceFallbackTTL so the node is asked again soon.
+func GasPriceFor(network string) GasPrice {
+	key := "gasprice:" + network
+	v, err := shared.GetOrLoad(key, gasPriceTTL, func() (string, error) {
+		g, err := fetchGasPrice(network)
+		if err != nil {
+			return "", err
+		}
+		return g.String() + " " + g.Source, nil
+	})
+	if err == nil {
+		wei, source, _ := strings.Cut(v, " ")
+		if g, ok := new(big.Int).SetString(wei, 10); ok {
+			return GasPrice{Wei: g, Source: source}
+		}
+		err = fmt.Errorf("bad cached gas price %q", v)
+	}
+	def := defaultGasPrice(network)
+	logs.Printf("%s[WARNING]%s Gas price for network %s unavailable (%v), using default %s wei\n", config.ColorYellow, config.ColorReset, network, err, def)
+	shared.Set(key, def.String()+" "+def.Source, gasPriceFallbackTTL)
+	return def
+}
+
+// defaultGasPrice returns the network's fallback gas price.
+func defaultGasPrice(network string) GasPrice {
+	wei, ok := defaultGasPrices[network]
+	if !ok {
+		wei = fallbackGasPrice
+	}
+	return GasPrice{Wei: big.NewInt(wei), Source: "default"}
+}
+
+// fetchGasPrice asks the network's RPC node through gasPriceSources, returning
+// the first positive price.
+func fetchGasPrice(network string) (GasPrice, error) {
+	if config.GetDryRunEnabled() {
+		return GasPrice{}, errors.New("dry run")
+	}
+	rpcURL := config.GetRPCURL(network)
+	if rpcURL == "" {
+		return GasPrice{}, errors.New("no RPC URL")
+	}
+	var errs []error
+	for _, s := range gasPriceSources {
+		wei, err := s.fetch(rpcURL)
+		if err == nil && wei.Sign() <= 0 {
+			err = fmt.Errorf("non-positive price %s", wei)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		return GasPrice{Wei: wei, Source: s.name}, nil
+	}
+	return GasPrice{}, errors.Join(errs...)
+}
+
+// feeHistory is the part of an eth_feeHistory result gas pricing reads.
+type feeHistory struct {
+	// BaseFeePerGas has one more entry than the blocks asked for: the last
+	// is the next block's base fee.
+	BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+	Reward        [][]*hexutil.Big `json:"reward"`
+}
+
+// price returns the next block's base fee plus the median of the blocks'
+// priority fees at feeHistoryPercentile.
+func (h feeHistory) price() (*big.Int, error) {
+	if len(h.BaseFeePerGas) == 0 {
+		return nil, errors.New("no base fee (pre-London chain?)")
+	}
+	next := h.BaseFeePerGas[len(h.BaseFeePerGas)-1]
+	if next == nil {
+		return nil, errors.New("no base fee")
+	}
+	var tips []*big.Int
+	for _, r := range h.Reward {
+		if len(r) > 0 && r[0] != nil {
+			tips = append(tips, r[0].ToInt())
+		}
+	}
+	price := new(big.Int).Set(next.ToInt())
+	if len(tips) > 0 {
+		slices.SortFunc(tips, (*big.Int).Cmp)
+		price.Add(price, tips[len(tips)/2])
+	}
+	return price, nil
+}
+
+// feeHistoryGasPrice prices gas from the node's recent fee history.
+func feeHistoryGasPrice(rpcURL string) (*big.Int, error) {
+	var h feeHistory
+	if err := gasPriceCall(rpcURL, &h, "eth_feeHistory", hexutil.Uint64(feeHistoryBlocks), "latest", []float64{feeHistoryPercentile}); err != nil {
+		return nil, err
+	}
+	return h.price()
+}
+
+// rpcGasPrice asks the node for its suggested legacy gas price.
+func rpcGasPrice(rpcURL string) (*big.Int, error) {
+	var price hexutil.Big
+	if err := gasPriceCall(rpcURL, &price, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return price.ToInt(), nil
+}
+
+// gasPriceCall makes an RPC call on the cached client for rpcURL. A
+// connection failure drops the client and retries once, like callRPC.
+func gasPriceCall(rpcURL string, result any, method string, args ...any) error {
+	for attempt := 0; ; attempt++ {
+		client, err := getClient(rpcURL)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), gasPriceTimeout)
+		err = client.Client().CallContext(ctx, result, method, args...)
+		cancel()
+		if err == nil || attempt > 0 || !isConnectionError(err) {
+			return err
+		}
+		logs.Printf("[DEBUG]   RPC %s connection failed, reconnecting: %v\n", rpcHost(rpcURL), err)
+		dropClient(rpcURL, client)
+	}
+}
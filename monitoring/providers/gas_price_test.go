@@ -0,0 +1,95 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"go-monitoring/internal/shared"
+)
+
+// stubGasPriceSources replaces the RPC lookups for a test, counting calls.
+func stubGasPriceSources(t *testing.T, feeHistory, gasPrice func() (*big.Int, error)) *int {
+	t.Helper()
+	saved := gasPriceSources
+	t.Cleanup(func() { gasPriceSources = saved })
+	calls := 0
+	gasPriceSources = []gasPriceSource{
+		{"eth_feeHistory", func(string) (*big.Int, error) { calls++; return feeHistory() }},
+		{"eth_gasPrice", func(string) (*big.Int, error) { calls++; return gasPrice() }},
+	}
+	return &calls
+}
+
+func TestGasPriceForFallsBack(t *testing.T) {
+	defer shared.Use(shared.NewMemory())
+	shared.Use(shared.NewMemory())
+	t.Setenv("ETHEREUM_RPC_URL", "https://rpc.example")
+	failed := func() (*big.Int, error) { return nil, errors.New("boom") }
+	gwei := func() (*big.Int, error) { return big.NewInt(2_000_000_000), nil }
+
+	calls := stubGasPriceSources(t, failed, gwei)
+	if g := GasPriceFor("1"); g.String() != "2000000000" || g.Source != "eth_gasPrice" {
+		t.Fatalf("gas price = %s from %s, want eth_gasPrice's", g, g.Source)
+	}
+	// Cached for the next builder.
+	if g := GasPriceFor("1"); g.Source != "eth_gasPrice" || *calls != 2 {
+		t.Fatalf("second lookup: %s from %s after %d calls", g, g.Source, *calls)
+	}
+
+	// Both failing: the network default, cached for gasPriceFallbackTTL
+	// only.
+	backend := &ttlBackend{Memory: shared.NewMemory(), ttls: map[string]time.Duration{}}
+	shared.Use(backend)
+	calls = stubGasPriceSources(t, failed, failed)
+	if g := GasPriceFor("1"); g.String() != "30000000000" || g.Source != "default" {
+		t.Fatalf("gas price = %s from %s, want the default", g, g.Source)
+	}
+	if g := GasPriceFor("1"); g.Source != "default" || *calls != 2 {
+		t.Fatalf("second lookup: %s from %s after %d RPC calls, want the cached default", g, g.Source, *calls)
+	}
+	if ttl := backend.ttls["gasprice:1"]; ttl != gasPriceFallbackTTL {
+		t.Fatalf("default cached for %s, want %s", ttl, gasPriceFallbackTTL)
+	}
+
+	// No RPC URL: the default without asking.
+	calls = stubGasPriceSources(t, gwei, gwei)
+	if g := GasPriceFor("8453"); g.String() != "1000000" || *calls != 0 {
+		t.Fatalf("gas price = %s after %d calls", g, *calls)
+	}
+}
+
+// ttlBackend records the TTL each key was last set with.
+type ttlBackend struct {
+	*shared.Memory
+	ttls map[string]time.Duration
+}
+
+func (b *ttlBackend) Set(key, value string, ttl time.Duration) error {
+	b.ttls[key] = ttl
+	return b.Memory.Set(key, value, ttl)
+}
+
+func TestFeeHistoryPrice(t *testing.T) {
+	var h feeHistory
+	body := `{"baseFeePerGas":["0x64","0x6e","0x78"],"reward":[["0x5"],["0x1"],["0x3"]]}`
+	if err := json.Unmarshal([]byte(body), &h); err != nil {
+		t.Fatal(err)
+	}
+	// Next base fee 120 plus median tip 3.
+	if got, err := h.price(); err != nil || got.Int64() != 123 {
+		t.Fatalf("price = %v, %v", got, err)
+	}
+	if _, err := (feeHistory{}).price(); err == nil {
+		t.Fatal("empty fee history should fail")
+	}
+}
+
+func TestGasPriceCost(t *testing.T) {
+	g := GasPrice{Wei: big.NewInt(3_000_000_000)}
+	if got := g.Cost(150_000).String(); got != "450000000000000" {
+		t.Fatalf("cost = %s", got)
+	}
+}
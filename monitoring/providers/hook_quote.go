@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"fmt"
+	"math"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+)
+
+// verifyHookQuote checks a hook-triggering row ("surge" variant, see
+// discovery.SurgeRows) after a successful Balancer-only call: the swap is
+// re-quoted on-chain with Router.querySwapSingleTokenExactIn through the
+// expected pool, which applies the hook's dynamic fee, and the check fails
+// when the provider's quote is more than toleranceBps away from it. An RPC
+// failure is recorded in OnChainQueryError and leaves the status alone.
+func verifyHookQuote(endpoint *collector.Endpoint, toleranceBps float64) {
+	if endpoint.Variant != "surge" || endpoint.LastStatus != "up" || config.GetDryRunEnabled() {
+		return
+	}
+	logs.Printf("%s[HOOK QUOTE]%s %s: Querying Router through %s\n", config.ColorCyan, config.ColorReset, endpoint.Name, endpoint.ExpectedPool)
+	quote, err := queryExpectedPoolSwap(endpoint, 0)
+	if err == nil && hookQuoteMismatch(endpoint.ReturnAmount, quote.Amount, toleranceBps) != "" {
+		// Re-read on a later block before failing: the provider may have
+		// quoted a block the query didn't see.
+		quote, err = queryExpectedPoolSwap(endpoint, quote.BlockNumber)
+	}
+	quote.record(endpoint)
+	if err != nil {
+		endpoint.OnChainPrice = ""
+		endpoint.OnChainQueryError = err.Error()
+		logs.Printf("%s[WARN]%s %s: Hook quote not verified: %v\n", config.ColorYellow, config.ColorReset, endpoint.Name, err)
+		return
+	}
+	endpoint.OnChainPrice = quote.Amount
+	endpoint.OnChainQueryError = ""
+	if msg := hookQuoteMismatch(endpoint.ReturnAmount, quote.Amount, toleranceBps); msg != "" {
+		endpoint.LastStatus = "down"
+		endpoint.Message = msg
+		logs.Printf("%s[HOOK QUOTE]%s %s: %s\n", config.ColorRed, config.ColorReset, endpoint.Name, msg)
+	}
+}
+
+// queryExpectedPoolSwap quotes the endpoint's swap on-chain through its
+// expected pool alone, whatever path the provider reported.
+func queryExpectedPoolSwap(endpoint *collector.Endpoint, after uint64) (OnChainQuote, error) {
+	rpcURL := config.GetRPCURL(endpoint.Network)
+	if rpcURL == "" {
+		return OnChainQuote{}, fmt.Errorf("no RPC URL configured for network %s", endpoint.Network)
+	}
+	direct := *endpoint
+	direct.SwapPathPools = []string{endpoint.ExpectedPool}
+	quote, err := querySinglePoolSwap(rpcURL, &direct, after)
+	quote.RPCHost = rpcHost(rpcURL)
+	return quote, err
+}
+
+// hookQuoteMismatch returns the failure message when quote and the on-chain
+// amount differ by more than toleranceBps of the on-chain amount, or "".
+func hookQuoteMismatch(quote, onChain string, toleranceBps float64) string {
+	spread, ok := collector.QuoteSpreadBps(quote, onChain)
+	if !ok {
+		return fmt.Sprintf("Hook quote mismatch: cannot compare provider quote %q with Router query %q", quote, onChain)
+	}
+	if diff := math.Abs(spread); diff > toleranceBps {
+		return fmt.Sprintf("Hook quote mismatch: provider quoted %s, Router query %s (%.1f bps apart, tolerance %.0f bps)", quote, onChain, diff, toleranceBps)
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestHookQuoteMismatch(t *testing.T) {
+	if msg := hookQuoteMismatch("9995", "10000", 10); msg != "" {
+		t.Fatalf("5 bps apart should pass, got %q", msg)
+	}
+	// A provider ignoring the surge fee quotes above the Router.
+	msg := hookQuoteMismatch("10100", "10000", 10)
+	if !strings.Contains(msg, "provider quoted 10100, Router query 10000 (100.0 bps apart") {
+		t.Fatalf("msg = %q", msg)
+	}
+	if msg := hookQuoteMismatch("", "10000", 10); !strings.Contains(msg, "cannot compare") {
+		t.Fatalf("msg = %q", msg)
+	}
+}
+
+func TestVerifyHookQuoteSkipsOtherRows(t *testing.T) {
+	e := &collector.Endpoint{Name: "x", LastStatus: "up", Message: "Ok", ReturnAmount: "1"}
+	verifyHookQuote(e, 10)
+	if e.LastStatus != "up" || e.OnChainQueryError != "" {
+		t.Fatalf("non-surge row was verified: %+v", e)
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/api"
-	"go-monitoring/internal/collector"
-	"go-monitoring/notifications"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/sources"
 )
 
 // HyperBloomSource represents a source in the HyperBloom response
@@ -61,7 +64,7 @@ func NewHyperBloomHandler() *HyperBloomHandler {
 }
 
 // HandleResponse processes the HyperBloom API response and validates it according to business rules
-func (h *HyperBloomHandler) HandleResponse(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *HyperBloomHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result HyperBloomResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -84,6 +87,8 @@ func (h *HyperBloomHandler) HandleResponse(response *api.APIResponse, endpoint *
 
 	// Store the return amount
 	endpoint.ReturnAmount = result.BuyAmount
+	endpoint.PriceImpactBps, endpoint.HasPriceImpact = priceImpactPercentToBps(result.EstimatedPriceImpact)
+	endpoint.Spender = result.AllowanceTarget
 
 	// Check if we have a route ID (indicates successful route calculation)
 
@@ -106,21 +111,25 @@ func (h *HyperBloomHandler) HandleResponse(response *api.APIResponse, endpoint *
 	}
 
 	// Check that all sources with proportion > 0 are BalancerV3
+	endpoint.SetRoute(result.routeGraph())
+	src, err := sources.ForEndpoint("hyperbloom", endpoint)
+	if err != nil {
+		h.handleError(endpoint, "error", err.Error(), "")
+		return err
+	}
 	foundBalancerV3 := false
-	for _, source := range result.Sources {
-		if source.Proportion != "0" {
-			if source.Name != "BalancerV3" {
-				prettyJSON, _ := json.MarshalIndent(result, "", "    ")
-				h.handleError(endpoint, "down", fmt.Sprintf("unexpected source found: %s with proportion %s. Expected only BalancerV3", source.Name, source.Proportion), string(prettyJSON))
-				return fmt.Errorf("unexpected source found: %s with proportion %s. Expected only BalancerV3", source.Name, source.Proportion)
-			}
-			foundBalancerV3 = true
+	for _, venue := range endpoint.Route.Venues() {
+		if !src.Matches(venue.Exchange) {
+			prettyJSON, _ := json.MarshalIndent(result, "", "    ")
+			h.handleError(endpoint, "down", fmt.Sprintf("unexpected source found: %s with %g%% of the amount. Expected only %s", venue.Exchange, venue.Percent, src), string(prettyJSON))
+			return fmt.Errorf("unexpected source found: %s with %g%% of the amount. Expected only %s", venue.Exchange, venue.Percent, src)
 		}
+		foundBalancerV3 = true
 	}
 
 	if !foundBalancerV3 {
-		h.handleError(endpoint, "down", "no BalancerV3 source found with proportion > 0", string(response.Body))
-		return fmt.Errorf("no BalancerV3 source found with proportion > 0")
+		h.handleError(endpoint, "down", fmt.Sprintf("no %s source found with proportion > 0", src), string(response.Body))
+		return fmt.Errorf("no %s source found with proportion > 0", src)
 	}
 
 	// Validate token addresses match
@@ -138,7 +147,7 @@ func (h *HyperBloomHandler) HandleResponse(response *api.APIResponse, endpoint *
 }
 
 // HandleResponseForMarketPrice processes the HyperBloom API response for market price (all sources)
-func (h *HyperBloomHandler) HandleResponseForMarketPrice(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *HyperBloomHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result HyperBloomResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -164,8 +173,8 @@ func (h *HyperBloomHandler) GetIgnoreList(network string) (string, error) {
 func (h *HyperBloomHandler) handleError(endpoint *collector.Endpoint, status, message, responseBody string) {
 	endpoint.LastStatus = status
 	endpoint.Message = message
-	fmt.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
-	notifications.SendEmail(fmt.Sprintf("[%s] %s\nResponse body:\n%s", endpoint.Name, message, responseBody))
+	logs.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
+	notify.SendEndpointAlert(endpoint, message, responseBody)
 }
 
 // NewHyperBloomURLBuilder creates a new HyperBloom URL builder
@@ -174,7 +183,7 @@ func NewHyperBloomURLBuilder() *HyperBloomURLBuilder {
 }
 
 // BuildURL builds the complete URL for HyperBloom API requests
-func (b *HyperBloomURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.RequestOptions) (string, error) {
+func (b *HyperBloomURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
 	baseURL := "https://api.hyperbloom.xyz/swap/v1/price"
 
 	// Build parameters
@@ -185,8 +194,29 @@ func (b *HyperBloomURLBuilder) BuildURL(endpoint *collector.Endpoint, options ap
 
 	// Only add source filtering if we're filtering for Balancer sources only
 	if options.IsBalancerSourceOnly {
-		params.Add("includedSources", "BalancerV3")
+		src, err := sources.ForEndpoint("hyperbloom", endpoint)
+		if err != nil {
+			return "", err
+		}
+		params.Add("includedSources", src.String())
 	}
 
 	return fmt.Sprintf("%s?%s", baseURL, params.Encode()), nil
 }
+
+// routeGraph builds the route as one hop from the sell to the buy token,
+// split across the sources quoting a nonzero proportion.
+func (r HyperBloomResponse) routeGraph() collector.RouteGraph {
+	hop := collector.RouteHop{TokenIn: r.SellTokenAddress, TokenOut: r.BuyTokenAddress}
+	for _, source := range r.Sources {
+		if source.Proportion == "0" {
+			continue
+		}
+		proportion, _ := strconv.ParseFloat(source.Proportion, 64)
+		hop.Venues = append(hop.Venues, collector.RouteVenue{Exchange: source.Name, Percent: proportion * 100})
+	}
+	if len(hop.Venues) == 0 {
+		return collector.RouteGraph{}
+	}
+	return collector.RouteGraph{Paths: []collector.RoutePath{{Hops: []collector.RouteHop{hop}}}}
+}
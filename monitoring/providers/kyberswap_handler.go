@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/api"
-	"go-monitoring/internal/collector"
-	"go-monitoring/notifications"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/sources"
 )
 
 // KyberSwapRouteItem represents a single route item in the KyberSwap response
@@ -66,7 +68,7 @@ func NewKyberSwapHandler() *KyberSwapHandler {
 }
 
 // HandleResponse processes the KyberSwap API response and validates it according to business rules
-func (h *KyberSwapHandler) HandleResponse(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *KyberSwapHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
 
 	// Parse the JSON response
 	var result KyberSwapResponse
@@ -96,6 +98,8 @@ func (h *KyberSwapHandler) HandleResponse(response *api.APIResponse, endpoint *c
 
 	// Store the return amount
 	endpoint.ReturnAmount = result.Data.RouteSummary.AmountOut
+	endpoint.Spender = result.Data.RouterAddress
+	endpoint.SetRoute(result.routeGraph())
 
 	// Check if we have a route ID (indicates successful route calculation)
 	if result.Data.RouteSummary.RouteID == "" {
@@ -103,31 +107,19 @@ func (h *KyberSwapHandler) HandleResponse(response *api.APIResponse, endpoint *c
 		return fmt.Errorf("no route ID in response")
 	}
 
-	expectedSource, err := kyberIncludedBalancerV3Source(endpoint)
+	expectedSource, err := sources.ForEndpoint("kyberswap", endpoint)
 	if err != nil {
 		h.handleError(endpoint, "down", err.Error(), string(response.Body))
 		return err
 	}
 
 	// Check if route contains the expected pool and only the expected source type
-	foundExpectedPool := false
+	foundExpectedPool := endpoint.Route.HasPool(endpoint.ExpectedPool)
 	foundExpectedSource := false
-	var foundExchanges []string
-
-	for _, routeStep := range result.Data.RouteSummary.Route {
-		for _, routeItem := range routeStep {
-			// Track all exchanges for debugging
-			foundExchanges = append(foundExchanges, routeItem.Exchange)
-
-			// Check for expected pool (case-insensitive, addresses may differ in casing)
-			if strings.EqualFold(routeItem.Pool, endpoint.ExpectedPool) {
-				foundExpectedPool = true
-			}
-
-			// Check for expected source type
-			if routeItem.Exchange == expectedSource {
-				foundExpectedSource = true
-			}
+	foundExchanges := endpoint.RouteSources
+	for _, exchange := range foundExchanges {
+		if expectedSource.Matches(exchange) {
+			foundExpectedSource = true
 		}
 	}
 
@@ -147,7 +139,7 @@ func (h *KyberSwapHandler) HandleResponse(response *api.APIResponse, endpoint *c
 
 	// Validate that only the expected source type is found
 	for _, exchange := range foundExchanges {
-		if exchange != expectedSource {
+		if !expectedSource.Matches(exchange) {
 			prettyJSON, _ := json.MarshalIndent(result, "", "    ")
 			h.handleError(endpoint, "down", fmt.Sprintf("unexpected source found in route: %s. Expected: %s, All exchanges: %v", exchange, expectedSource, foundExchanges), string(prettyJSON))
 			return fmt.Errorf("unexpected source found in route: %s. Expected: %s, All exchanges: %v", exchange, expectedSource, foundExchanges)
@@ -158,7 +150,7 @@ func (h *KyberSwapHandler) HandleResponse(response *api.APIResponse, endpoint *c
 }
 
 // HandleResponseForMarketPrice processes the KyberSwap API response for market price (all sources)
-func (h *KyberSwapHandler) HandleResponseForMarketPrice(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *KyberSwapHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result KyberSwapResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -227,52 +219,21 @@ func (h *KyberSwapHandler) GetChainName(chainID string) string {
 }
 
 // kyberIncludedBalancerV3Source returns Kyber's `includedSources` slug for Balancer V3.
-// Discovered rows set PoolType / HookType from the Balancer API; base rows leave them
-// empty and rely on substrings in the expanded endpoint Name (Stable, Gyro, …).
+// Kyber splits Balancer V3 by pool type, so the slug follows sources.KindOf.
 func kyberIncludedBalancerV3Source(e *collector.Endpoint) (string, error) {
-	pt := strings.TrimSpace(e.PoolType)
-	ht := strings.TrimSpace(e.HookType)
-	if pt != "" || ht != "" {
-		combined := strings.ToUpper(pt + " " + ht)
-		switch {
-		case strings.Contains(combined, "QUANT"):
-			return "balancer-v3-quantamm", nil
-		case strings.Contains(combined, "RECLAMM"):
-			return "balancer-v3-reclamm", nil
-		case strings.Contains(combined, "GYRO"):
-			return "balancer-v3-eclp", nil
-		case strings.Contains(combined, "STABLE"):
-			return "balancer-v3-stable", nil
-		case strings.Contains(combined, "WEIGHTED"):
-			return "balancer-v3-weighted", nil
-		default:
-			return "", fmt.Errorf("unsupported pool type from PoolType=%q HookType=%q", e.PoolType, e.HookType)
-		}
-	}
-	return kyberIncludedSourcesFromEndpointName(e.Name)
-}
-
-func kyberIncludedSourcesFromEndpointName(endpointName string) (string, error) {
-	switch {
-	case strings.Contains(endpointName, "Quant"):
-		return "balancer-v3-quantamm", nil
-	case strings.Contains(endpointName, "Stable"):
-		return "balancer-v3-stable", nil
-	case strings.Contains(endpointName, "Gyro"):
-		return "balancer-v3-eclp", nil
-	case strings.Contains(endpointName, "reCLAMM"):
-		return "balancer-v3-reclamm", nil
-	default:
-		return "", fmt.Errorf("unsupported pool type")
+	src, err := sources.ForEndpoint("kyberswap", e)
+	if err != nil {
+		return "", err
 	}
+	return src.String(), nil
 }
 
 // handleError updates endpoint status and sends notifications for KyberSwap-specific errors
 func (h *KyberSwapHandler) handleError(endpoint *collector.Endpoint, status, message, responseBody string) {
 	endpoint.LastStatus = status
 	endpoint.Message = message
-	fmt.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
-	notifications.SendEmail(fmt.Sprintf("[%s] %s\nResponse body:\n%s", endpoint.Name, message, responseBody))
+	logs.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
+	notify.SendEndpointAlert(endpoint, message, responseBody)
 }
 
 // NewKyberSwapURLBuilder creates a new KyberSwap URL builder
@@ -281,7 +242,7 @@ func NewKyberSwapURLBuilder() *KyberSwapURLBuilder {
 }
 
 // BuildURL builds the complete URL for KyberSwap API requests
-func (b *KyberSwapURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.RequestOptions) (string, error) {
+func (b *KyberSwapURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
 	// Get chain name for the API endpoint
 	handler := &KyberSwapHandler{}
 	chainName := handler.GetChainName(endpoint.Network)
@@ -302,7 +263,39 @@ func (b *KyberSwapURLBuilder) BuildURL(endpoint *collector.Endpoint, options api
 			return "", fmt.Errorf("error getting included sources: %v", err)
 		}
 		params.Add("includedSources", includedSources)
+	} else if len(options.ExcludeSources) > 0 {
+		params.Add("excludedSources", strings.Join(options.ExcludeSources, ","))
 	}
 
 	return fmt.Sprintf("%s?%s", baseURL, params.Encode()), nil
 }
+
+// ParseRoute returns the route of a quote response, for the exclusion check.
+func (h *KyberSwapHandler) ParseRoute(body []byte) (collector.RouteGraph, error) {
+	var result KyberSwapResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return collector.RouteGraph{}, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	if result.Code != 0 {
+		return collector.RouteGraph{}, fmt.Errorf("kyberswap API error: %s (code: %d, requestId: %s)", result.Message, result.Code, result.RequestID)
+	}
+	return result.routeGraph(), nil
+}
+
+// routeGraph builds the route from the route summary: each inner list is a
+// path of one-pool hops, with the amounts each swaps.
+func (r KyberSwapResponse) routeGraph() collector.RouteGraph {
+	var g collector.RouteGraph
+	for _, route := range r.Data.RouteSummary.Route {
+		var path collector.RoutePath
+		for _, item := range route {
+			path.Hops = append(path.Hops, collector.RouteHop{
+				TokenIn:  item.TokenIn,
+				TokenOut: item.TokenOut,
+				Venues:   []collector.RouteVenue{{Pool: item.Pool, Exchange: item.Exchange, AmountIn: item.SwapAmount, AmountOut: item.AmountOut}},
+			})
+		}
+		g.Paths = append(g.Paths, path)
+	}
+	return g
+}
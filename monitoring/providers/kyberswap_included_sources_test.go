@@ -4,7 +4,7 @@ import (
 	"strings"
 	"testing"
 
-	"go-monitoring/internal/collector"
+	"go-monitoring/monitoring/collector"
 )
 
 func TestKyberIncludedBalancerV3Source_DiscoveredPoolType(t *testing.T) {
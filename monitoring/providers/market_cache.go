@@ -0,0 +1,75 @@
+package providers
+
+import (
+	"strings"
+	"sync"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+// MarketPriceCache memoizes market price (all sources) quotes so endpoints
+// sharing a provider, network, token pair and amount make one call. The
+// monitor uses a fresh cache per check cycle; quotes are never reused across
+// cycles.
+type MarketPriceCache struct {
+	mu     sync.Mutex
+	quotes map[string]marketQuote
+	hits   int
+}
+
+// marketQuote is what a market price call records on an endpoint.
+type marketQuote struct {
+	Price         string
+	BalancerRank  int
+	RankedSources int
+}
+
+// quoteOf returns the market price fields of e.
+func quoteOf(e *collector.Endpoint) marketQuote {
+	return marketQuote{Price: e.MarketPrice, BalancerRank: e.BalancerRank, RankedSources: e.RankedSources}
+}
+
+// apply sets the market price fields of e.
+func (q marketQuote) apply(e *collector.Endpoint) {
+	e.MarketPrice, e.BalancerRank, e.RankedSources = q.Price, q.BalancerRank, q.RankedSources
+}
+
+// NewMarketPriceCache returns an empty cache.
+func NewMarketPriceCache() *MarketPriceCache {
+	return &MarketPriceCache{quotes: map[string]marketQuote{}}
+}
+
+// marketPriceKey identifies a quote: (provider, network, tokenIn, tokenOut,
+// amount), addresses compared case-insensitively.
+func marketPriceKey(e *collector.Endpoint) string {
+	return strings.Join([]string{e.RouteSolver, e.Network, config.NormalizeAddress(e.TokenIn), config.NormalizeAddress(e.TokenOut), e.SwapAmount}, "|")
+}
+
+func (c *MarketPriceCache) get(e *collector.Endpoint) (marketQuote, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	q, ok := c.quotes[marketPriceKey(e)]
+	if ok {
+		c.hits++
+	}
+	return q, ok
+}
+
+// put records a successful quote; failed calls are retried by the next
+// endpoint with the same key.
+func (c *MarketPriceCache) put(e *collector.Endpoint, q marketQuote) {
+	if q.Price == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.quotes[marketPriceKey(e)] = q
+}
+
+// Hits returns how many market price calls the cache has saved.
+func (c *MarketPriceCache) Hits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
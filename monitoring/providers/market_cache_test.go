@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestMarketPriceCacheKey(t *testing.T) {
+	c := NewMarketPriceCache()
+	a := &collector.Endpoint{RouteSolver: "kyberswap", Network: "1", TokenIn: "0xAbC", TokenOut: "0xDef", SwapAmount: "1000"}
+	c.put(a, marketQuote{})
+	if _, ok := c.get(a); ok {
+		t.Fatal("a failed call must not be cached")
+	}
+	c.put(a, marketQuote{Price: "42", BalancerRank: 2, RankedSources: 5})
+
+	same := *a
+	same.Name, same.TokenIn = "other row", "0xabc"
+	if got, ok := c.get(&same); !ok || got.Price != "42" || got.BalancerRank != 2 {
+		t.Fatalf("same quote: got %q, %v", got, ok)
+	}
+	for _, diff := range []func(e *collector.Endpoint){
+		func(e *collector.Endpoint) { e.RouteSolver = "odos" },
+		func(e *collector.Endpoint) { e.Network = "8453" },
+		func(e *collector.Endpoint) { e.TokenIn, e.TokenOut = e.TokenOut, e.TokenIn },
+		func(e *collector.Endpoint) { e.SwapAmount = "2000" },
+	} {
+		e := *a
+		diff(&e)
+		if _, ok := c.get(&e); ok {
+			t.Errorf("unexpected hit for %+v", e)
+		}
+	}
+	if c.Hits() != 1 {
+		t.Fatalf("hits = %d", c.Hits())
+	}
+}
+
+func TestCheckMarketPriceUsesCache(t *testing.T) {
+	c := NewMarketPriceCache()
+	e := &collector.Endpoint{Name: "row", RouteSolver: "kyberswap", Network: "1", TokenIn: "a", TokenOut: "b", SwapAmount: "1"}
+	c.put(e, marketQuote{Price: "42", BalancerRank: 1, RankedSources: 3})
+	// A hit returns before any request is made (the zero ProviderConfig
+	// has no handler to call).
+	NewRegistry().checkMarketPrice(e, ProviderConfig{}, c, nil)
+	if e.MarketPrice != "42" || e.BalancerRank != 1 || e.RankedSources != 3 {
+		t.Fatalf("market fields = %q, %d of %d", e.MarketPrice, e.BalancerRank, e.RankedSources)
+	}
+}
@@ -0,0 +1,26 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestUnservedNetworkIsNotApplicable(t *testing.T) {
+	e := &collector.Endpoint{Network: "43114", SwapAmount: "1000000", TokenInDecimals: 6}
+	balancerOnly := check.RequestOptions{IsBalancerSourceOnly: true}
+
+	if _, err := (&BarterURLBuilder{}).BuildURL(e, balancerOnly); !errors.Is(err, check.ErrNetworkNotApplicable) {
+		t.Errorf("Barter on Avalanche: %v", err)
+	}
+	e.Network = "10"
+	if _, err := (&ZeroXURLBuilder{}).BuildURL(e, balancerOnly); !errors.Is(err, check.ErrNetworkNotApplicable) {
+		t.Errorf("0x on Optimism: %v", err)
+	}
+	e.Network = "424242"
+	if _, err := (&BalancerSORRequestBodyBuilder{}).BuildRequestBody(e, balancerOnly); !errors.Is(err, check.ErrNetworkNotApplicable) {
+		t.Errorf("Balancer SOR on an unknown chain: %v", err)
+	}
+}
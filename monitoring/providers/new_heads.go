@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-monitoring/internal/clock"
+	"go-monitoring/monitoring/collector"
+)
+
+// WatchNewHeads subscribes to newHeads on a WebSocket RPC URL and calls
+// onBlock with the first head and then the first head at least every after
+// the last one passed. It blocks until ctx ends or the subscription fails and
+// returns why; the caller resubscribes.
+func WatchNewHeads(ctx context.Context, wsURL string, every time.Duration, onBlock func(block uint64)) error {
+	client, err := rpc.DialContext(ctx, wsURL)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", rpcHost(wsURL), err)
+	}
+	defer client.Close()
+
+	heads := make(chan blockRef, 16)
+	sub, err := client.EthSubscribe(ctx, heads, "newHeads")
+	if err != nil {
+		return fmt.Errorf("subscribe newHeads on %s: %w", rpcHost(wsURL), err)
+	}
+	defer sub.Unsubscribe()
+
+	var last uint64
+	var lastAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return fmt.Errorf("newHeads on %s: %v", rpcHost(wsURL), err)
+		case head := <-heads:
+			block, now := uint64(head.Number), clock.Now()
+			if refreshDue(last, block, lastAt, now, every) {
+				last, lastAt = block, now
+				onBlock(block)
+			}
+		}
+	}
+}
+
+// refreshDue reports whether head, seen at now, is the first block seen
+// (last 0) or comes at least every after last, passed at lastAt. A head at
+// or below last (a reorg) waits.
+func refreshDue(last, head uint64, lastAt, now time.Time, every time.Duration) bool {
+	return last == 0 || head > last && now.Sub(lastAt) >= every
+}
+
+// RefreshOnChainPrice re-reads the on-chain price of a balancer_sor endpoint
+// with a known swap path, setting OnChainPrice (or OnChainQueryError) and the
+// query's block, RPC host and latency, as a check does. The provider is not
+// called.
+func RefreshOnChainPrice(endpoint *collector.Endpoint) {
+	recordOnChainPrice(endpoint)
+}
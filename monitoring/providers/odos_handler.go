@@ -3,9 +3,11 @@ package providers
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 
-	"go-monitoring/internal/api"
-	"go-monitoring/internal/collector"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/sources"
 )
 
 // OdosQuoteRequest represents the request body for the Odos quote endpoint
@@ -32,6 +34,7 @@ type OdosQuoteResponse struct {
 	InValues    []float64 `json:"inValues"`
 	OutValues   []float64 `json:"outValues"`
 	NetOutValue float64   `json:"netOutValue"`
+	PriceImpact *float64  `json:"priceImpact"` // percent; null when Odos can't price the tokens
 }
 
 // OdosErrorResponse represents the error response structure from the Odos API
@@ -45,7 +48,7 @@ type OdosErrorResponse struct {
 type OdosHandler struct{}
 
 // HandleResponse processes the Odos API response
-func (h *OdosHandler) HandleResponse(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *OdosHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Check status code
 	if response.StatusCode != 200 {
 		return fmt.Errorf("unexpected status code: %d", response.StatusCode)
@@ -63,15 +66,20 @@ func (h *OdosHandler) HandleResponse(response *api.APIResponse, endpoint *collec
 
 	// Extract and store the return amount
 	var odosResponse OdosQuoteResponse
-	if err := json.Unmarshal(response.Body, &odosResponse); err == nil && len(odosResponse.OutAmounts) > 0 {
-		endpoint.ReturnAmount = odosResponse.OutAmounts[0]
+	if err := json.Unmarshal(response.Body, &odosResponse); err != nil || len(odosResponse.OutAmounts) == 0 || odosResponse.OutAmounts[0] == "" {
+		return fmt.Errorf("no outAmounts in response")
+	}
+	endpoint.ReturnAmount = odosResponse.OutAmounts[0]
+	if odosResponse.PriceImpact != nil {
+		endpoint.PriceImpactBps = math.Abs(*odosResponse.PriceImpact) * 100
+		endpoint.HasPriceImpact = true
 	}
 
 	return nil
 }
 
 // HandleResponseForMarketPrice processes the Odos API response for market price (all sources)
-func (h *OdosHandler) HandleResponseForMarketPrice(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *OdosHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Check status code
 	if response.StatusCode != 200 {
 		return fmt.Errorf("unexpected status code: %d", response.StatusCode)
@@ -95,7 +103,7 @@ func (h *OdosHandler) GetIgnoreList(network string) (string, error) {
 type OdosURLBuilder struct{}
 
 // BuildURL constructs the URL for Odos API requests
-func (b *OdosURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.RequestOptions) (string, error) {
+func (b *OdosURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
 	return "https://api.odos.xyz/sor/quote/v2", nil
 }
 
@@ -103,7 +111,7 @@ func (b *OdosURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.Requ
 type OdosRequestBodyBuilder struct{}
 
 // BuildRequestBody constructs the JSON request body for Odos API requests
-func (b *OdosRequestBodyBuilder) BuildRequestBody(endpoint *collector.Endpoint, options api.RequestOptions) ([]byte, error) {
+func (b *OdosRequestBodyBuilder) BuildRequestBody(endpoint *collector.Endpoint, options check.RequestOptions) ([]byte, error) {
 	requestBody := OdosQuoteRequest{
 		ChainID: endpoint.Network,
 		InputTokens: []struct {
@@ -129,7 +137,11 @@ func (b *OdosRequestBodyBuilder) BuildRequestBody(endpoint *collector.Endpoint,
 
 	// Only add source whitelist if we're filtering for Balancer sources only
 	if options.IsBalancerSourceOnly {
-		requestBody.SourceWhitelist = []string{"Balancer V3 Gyro", "Balancer V3 Stable", "Balancer V3 Weighted", "Balancer V3 StableSurge", "Balancer V3 reCLAMM"}
+		src, err := sources.ForEndpoint("odos", endpoint)
+		if err != nil {
+			return nil, err
+		}
+		requestBody.SourceWhitelist = src.IDs
 	}
 
 	return json.Marshal(requestBody)
@@ -0,0 +1,149 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+)
+
+const (
+	// onChainCallAttempts bounds how many heads callAtHead pins before giving
+	// up when each pinned block is reorged out before the call reads it.
+	onChainCallAttempts = 3
+	// headWait bounds how long callAtHead waits for a block newer than the
+	// one a previous query read (a mainnet slot is 12s).
+	headWait = 15 * time.Second
+	// headPollInterval is how often the head is polled while waiting.
+	headPollInterval = time.Second
+)
+
+// reorgErrorPatterns are lowercased fragments of the errors nodes return for
+// an eth_call pinned to a block hash that is unknown or no longer canonical.
+var reorgErrorPatterns = []string{
+	"header not found",
+	"unknown block",
+	"block not found",
+	"not canonical",
+	"not currently canonical",
+}
+
+// pinnedCall is an eth_call result, the block it was read at and how long
+// the call took.
+type pinnedCall struct {
+	Result      []byte
+	BlockNumber uint64
+	BlockHash   common.Hash
+	Latency     time.Duration
+}
+
+// blockRef is the part of a block header callAtHead pins to. Decoding only
+// these fields keeps chains with non-standard headers (Arbitrum, HyperEVM)
+// working.
+type blockRef struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+}
+
+// callAtHead resolves the chain head and runs the eth_call against that
+// block's hash with requireCanonical set (EIP-1898), so a result is never
+// read from a fork a short reorg has just replaced; the node rejects the call
+// instead and it is retried on the new head, up to onChainCallAttempts times.
+// With after > 0 it first waits for a head above block after, to re-read a
+// result on a later block. On a failed call the block and latency of the last
+// attempt are still returned. An identical call already made at the same
+// block is answered from the call cache, with zero latency.
+func callAtHead(client *rpc.Client, msg ethereum.CallMsg, after uint64) (pinnedCall, error) {
+	var call pinnedCall
+	var lastErr error
+	for attempt := 1; attempt <= onChainCallAttempts; attempt++ {
+		head, err := headAfter(client, after)
+		if err != nil {
+			return call, err
+		}
+		key := newCallKey(head, msg)
+		if result, ok := cachedResult(key, clock.Now()); ok {
+			logs.Printf("[DEBUG]   Reused the read at block %d (%s)\n", uint64(head.Number), head.Hash.Hex())
+			return pinnedCall{Result: result, BlockNumber: uint64(head.Number), BlockHash: head.Hash}, nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		var result hexutil.Bytes
+		start := clock.Now()
+		err = client.CallContext(ctx, &result, "eth_call", callArg(msg), rpc.BlockNumberOrHashWithHash(head.Hash, true))
+		cancel()
+		call = pinnedCall{BlockNumber: uint64(head.Number), BlockHash: head.Hash, Latency: clock.Since(start)}
+		if err == nil {
+			logs.Printf("[DEBUG]   Read at block %d (%s) in %s\n", call.BlockNumber, head.Hash.Hex(), call.Latency)
+			call.Result = result
+			storeResult(key, result, clock.Now())
+			return call, nil
+		}
+		if !isReorgError(err) {
+			logs.Printf("[DEBUG]   RPC call failed: %v\n", err)
+			// Try to extract revert reason if available
+			if rpcErr, ok := err.(interface{ ErrorCode() int }); ok {
+				logs.Printf("[DEBUG]   RPC error code: %d\n", rpcErr.ErrorCode())
+			}
+			return call, fmt.Errorf("eth_call failed: %w", err)
+		}
+		logs.Printf("[DEBUG]   Block %d (%s) left the canonical chain (attempt %d/%d): %v\n",
+			uint64(head.Number), head.Hash.Hex(), attempt, onChainCallAttempts, err)
+		lastErr = err
+	}
+	return call, fmt.Errorf("eth_call failed: pinned block reorged out %d times: %w", onChainCallAttempts, lastErr)
+}
+
+// headAfter returns the latest block, waiting up to headWait for one above
+// block after when after > 0.
+func headAfter(client *rpc.Client, after uint64) (blockRef, error) {
+	deadline := clock.Now().Add(headWait)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		var head *blockRef
+		err := client.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false)
+		cancel()
+		if err != nil {
+			return blockRef{}, fmt.Errorf("get latest block: %w", err)
+		}
+		if head == nil {
+			return blockRef{}, fmt.Errorf("get latest block: no block returned")
+		}
+		if after == 0 || uint64(head.Number) > after {
+			return *head, nil
+		}
+		if clock.Now().After(deadline) {
+			return blockRef{}, fmt.Errorf("no block after %d within %s", after, headWait)
+		}
+		clock.Sleep(headPollInterval)
+	}
+}
+
+// callArg encodes the eth_call transaction object. "data" rather than
+// geth's newer "input" is accepted by every node implementation.
+func callArg(msg ethereum.CallMsg) map[string]interface{} {
+	return map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+		"data": hexutil.Bytes(msg.Data),
+	}
+}
+
+// isReorgError reports whether err is a node rejecting a call pinned to a
+// block hash that is unknown or no longer canonical.
+func isReorgError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, p := range reorgErrorPatterns {
+		if strings.Contains(msg, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIsReorgError(t *testing.T) {
+	for _, msg := range []string{
+		"header not found",
+		"unknown block",
+		"hash 0xabc is not currently canonical",
+		"block not found",
+	} {
+		if !isReorgError(errors.New(msg)) {
+			t.Errorf("isReorgError(%q) = false", msg)
+		}
+	}
+	if isReorgError(errors.New("execution reverted")) {
+		t.Error("a revert is not a reorg")
+	}
+}
+
+func TestDeviatesBps(t *testing.T) {
+	if deviatesBps("9960", "10000", 50) {
+		t.Error("40 bps should not deviate at 50")
+	}
+	if !deviatesBps("9900", "10000", 50) || !deviatesBps("10100", "10000", 50) {
+		t.Error("100 bps either way should deviate at 50")
+	}
+	if deviatesBps("", "10000", 50) {
+		t.Error("a missing quote cannot deviate")
+	}
+}
+
+func TestRPCHostDropsPath(t *testing.T) {
+	if got := rpcHost("https://eth-mainnet.g.alchemy.com/v2/secret-key"); got != "eth-mainnet.g.alchemy.com" {
+		t.Fatalf("rpcHost = %q", got)
+	}
+}
+
+func TestRefreshDue(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		last, head uint64
+		after      time.Duration
+		want       bool
+	}{
+		{0, 100, 0, true},
+		{100, 105, 30 * time.Second, false},
+		{100, 101, time.Minute, true},
+		{100, 99, 2 * time.Minute, false}, // reorged head
+	}
+	for _, tt := range tests {
+		if got := refreshDue(tt.last, tt.head, at, at.Add(tt.after), time.Minute); got != tt.want {
+			t.Errorf("refreshDue(%d, %d, +%s, 1m) = %v, want %v", tt.last, tt.head, tt.after, got, tt.want)
+		}
+	}
+}
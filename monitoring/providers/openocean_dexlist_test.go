@@ -0,0 +1,85 @@
+package providers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-monitoring/internal/shared"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/sources"
+)
+
+// failingTransport fails the test on any request.
+type failingTransport struct{ t *testing.T }
+
+func (f failingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	f.t.Errorf("unexpected request in dry run: %s", r.URL)
+	return nil, errors.New("no requests in dry run")
+}
+
+func TestOpenOceanDexListSkippedInDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	defer shared.Use(shared.NewMemory())
+	shared.Use(shared.NewMemory())
+	if _, err := shared.GetOrLoad("gasprice:1", gasPriceTTL, func() (string, error) { return "7 eth_gasPrice", nil }); err != nil {
+		t.Fatal(err)
+	}
+	saved := openOceanClient
+	openOceanClient = &http.Client{Transport: failingTransport{t}}
+	t.Cleanup(func() { openOceanClient = saved })
+
+	e := &collector.Endpoint{Network: "1", TokenIn: "0xin", TokenOut: "0xout", SwapAmount: "1"}
+	got, err := NewOpenOceanURLBuilder().BuildURL(e, check.RequestOptions{IsBalancerSourceOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "enabledDexIds="+dryRunDexIndices) {
+		t.Fatalf("URL = %s, want the placeholder DEX indices", got)
+	}
+}
+
+// dexListTransport answers every request with a dexList of two Balancer V3
+// DEXs and counts the requests.
+type dexListTransport struct{ requests int }
+
+func (d *dexListTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	d.requests++
+	body := `{"code":200,"data":[{"index":1,"code":"BalancerV3","name":"Balancer V3"},{"index":2,"code":"BalancerV3Stable","name":"Balancer V3 Stable"}]}`
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Request: r}, nil
+}
+
+func TestOpenOceanDexIndicesCachedPerSource(t *testing.T) {
+	defer shared.Use(shared.NewMemory())
+	shared.Use(shared.NewMemory())
+	transport := &dexListTransport{}
+	saved := openOceanClient
+	openOceanClient = &http.Client{Transport: transport}
+	t.Cleanup(func() { openOceanClient = saved })
+
+	b := NewOpenOceanURLBuilder()
+	weighted := sources.Entry{Solver: "openocean", PoolKind: sources.KindWeighted, IDs: []string{"BalancerV3"}}
+	stable := sources.Entry{Solver: "openocean", PoolKind: sources.KindStable, IDs: []string{"BalancerV3Stable"}}
+	for _, tt := range []struct {
+		src  sources.Entry
+		want string
+	}{
+		{weighted, "1"},
+		{stable, "2"},
+		{weighted, "1"},
+	} {
+		got, err := b.getBalancerDexIndices("base", tt.src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("indices for %v = %q, want %q", tt.src.IDs, got, tt.want)
+		}
+	}
+	if transport.requests != 2 {
+		t.Errorf("dexList fetched %d times, want once per source", transport.requests)
+	}
+}
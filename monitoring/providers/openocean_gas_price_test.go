@@ -0,0 +1,27 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+
+	"go-monitoring/internal/shared"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestOpenOceanBuildURLUsesCachedGasPrice(t *testing.T) {
+	defer shared.Use(shared.NewMemory())
+	shared.Use(shared.NewMemory())
+	if _, err := shared.GetOrLoad("gasprice:1", gasPriceTTL, func() (string, error) { return "7 eth_gasPrice", nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &collector.Endpoint{Network: "1", TokenIn: "0xin", TokenOut: "0xout", SwapAmount: "1"}
+	got, err := NewOpenOceanURLBuilder().BuildURL(e, check.RequestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "gasPriceDecimals=7") {
+		t.Fatalf("URL = %s, want the cached gas price", got)
+	}
+}
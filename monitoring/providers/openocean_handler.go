@@ -1,19 +1,22 @@
 package providers
 
 import (
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/api"
-	"go-monitoring/internal/collector"
-	"go-monitoring/notifications"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/shared"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/sources"
 )
 
 // OpenOceanDexInfo represents a single DEX entry from the /dexList endpoint
@@ -29,14 +32,6 @@ type OpenOceanDexListResponse struct {
 	Data []OpenOceanDexInfo `json:"data"`
 }
 
-// OpenOceanGasPriceResponse represents the response from the /gasPrice endpoint
-type OpenOceanGasPriceResponse struct {
-	Code int `json:"code"`
-	Data struct {
-		Standard interface{} `json:"standard"`
-	} `json:"data"`
-}
-
 // OpenOceanRouteDex represents a DEX in a route's subRoute
 type OpenOceanRouteDex struct {
 	Dex        string  `json:"dex"`
@@ -114,7 +109,7 @@ func NewOpenOceanHandler() *OpenOceanHandler {
 }
 
 // HandleResponse processes the OpenOcean API response and validates it according to business rules
-func (h *OpenOceanHandler) HandleResponse(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *OpenOceanHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result OpenOceanResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -135,6 +130,8 @@ func (h *OpenOceanHandler) HandleResponse(response *api.APIResponse, endpoint *c
 		return fmt.Errorf("no outAmount or outAmount is 0")
 	}
 
+	endpoint.SetRoute(result.routeGraph())
+
 	// Validate that routes exist
 	if len(result.Data.Path.Routes) == 0 {
 		h.handleError(endpoint, "down", "No routes found in response", string(response.Body))
@@ -142,38 +139,21 @@ func (h *OpenOceanHandler) HandleResponse(response *api.APIResponse, endpoint *c
 	}
 
 	// Validate all DEXs in route are BalancerV3
-	for _, route := range result.Data.Path.Routes {
-		for _, subRoute := range route.SubRoutes {
-			for _, dex := range subRoute.Dexes {
-				if !strings.Contains(dex.Dex, "BalancerV3") {
-					prettyJSON, _ := json.MarshalIndent(result, "", "    ")
-					h.handleError(endpoint, "down", fmt.Sprintf("Found DEX %s, expected BalancerV3", dex.Dex), string(prettyJSON))
-					return fmt.Errorf("found DEX %s, expected BalancerV3", dex.Dex)
-				}
-			}
-		}
+	src, err := sources.ForEndpoint("openocean", endpoint)
+	if err != nil {
+		h.handleError(endpoint, "error", err.Error(), "")
+		return err
 	}
-
-	// Validate that the expected pool is found in the route
-	foundExpectedPool := false
-	for _, route := range result.Data.Path.Routes {
-		for _, subRoute := range route.SubRoutes {
-			for _, dex := range subRoute.Dexes {
-				if strings.EqualFold(dex.ID, endpoint.ExpectedPool) {
-					foundExpectedPool = true
-					break
-				}
-			}
-			if foundExpectedPool {
-				break
-			}
-		}
-		if foundExpectedPool {
-			break
+	for _, venue := range endpoint.Route.Venues() {
+		if !src.Matches(venue.Exchange) {
+			prettyJSON, _ := json.MarshalIndent(result, "", "    ")
+			h.handleError(endpoint, "down", fmt.Sprintf("Found DEX %s, expected %s", venue.Exchange, src), string(prettyJSON))
+			return fmt.Errorf("found DEX %s, expected %s", venue.Exchange, src)
 		}
 	}
 
-	if !foundExpectedPool {
+	// Validate that the expected pool is found in the route
+	if !endpoint.Route.HasPool(endpoint.ExpectedPool) {
 		prettyJSON, _ := json.MarshalIndent(result, "", "    ")
 		h.handleError(endpoint, "down", fmt.Sprintf("Expected pool %s not found in route", endpoint.ExpectedPool), string(prettyJSON))
 		return fmt.Errorf("expected pool %s not found in route", endpoint.ExpectedPool)
@@ -181,12 +161,13 @@ func (h *OpenOceanHandler) HandleResponse(response *api.APIResponse, endpoint *c
 
 	// Store the return amount
 	endpoint.ReturnAmount = result.Data.OutAmount
+	endpoint.PriceImpactBps, endpoint.HasPriceImpact = priceImpactPercentToBps(result.Data.PriceImpact)
 
 	return nil
 }
 
 // HandleResponseForMarketPrice processes the OpenOcean API response for market price (all sources)
-func (h *OpenOceanHandler) HandleResponseForMarketPrice(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *OpenOceanHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result OpenOceanResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -199,6 +180,13 @@ func (h *OpenOceanHandler) HandleResponseForMarketPrice(response *api.APIRespons
 		endpoint.MarketPrice = result.Data.OutAmount
 	}
 
+	// Rank Balancer V3 among the per-DEX quotes
+	quotes := make([]sourceQuote, 0, len(result.Data.Dexes))
+	for _, dex := range result.Data.Dexes {
+		quotes = append(quotes, sourceQuote{Source: dex.DexCode, Amount: dex.SwapAmount})
+	}
+	setBalancerRank(endpoint, "openocean", quotes)
+
 	return nil
 }
 
@@ -211,8 +199,8 @@ func (h *OpenOceanHandler) GetIgnoreList(network string) (string, error) {
 func (h *OpenOceanHandler) handleError(endpoint *collector.Endpoint, status, message, responseBody string) {
 	endpoint.LastStatus = status
 	endpoint.Message = message
-	fmt.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
-	notifications.SendEmail(fmt.Sprintf("[%s] %s\nResponse body:\n%s", endpoint.Name, message, responseBody))
+	logs.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
+	notify.SendEndpointAlert(endpoint, message, responseBody)
 }
 
 // NewOpenOceanURLBuilder creates a new OpenOcean URL builder
@@ -221,16 +209,12 @@ func NewOpenOceanURLBuilder() *OpenOceanURLBuilder {
 }
 
 // BuildURL builds the complete URL for OpenOcean API requests
-func (b *OpenOceanURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.RequestOptions) (string, error) {
+func (b *OpenOceanURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
 	// Get chain name for the API endpoint
 	chainName := b.getChainName(endpoint.Network)
 
-	// Fetch gas price from OpenOcean's gasPrice endpoint, fall back to default if it fails
-	gasPrice, err := b.getGasPrice(chainName)
-	if err != nil {
-		gasPrice = b.getDefaultGasPrice(chainName)
-		fmt.Printf("%s[WARNING]%s OpenOcean: Gas price API failed for chain %s (%v), using fallback: %s\n", config.ColorYellow, config.ColorReset, chainName, err, gasPrice)
-	}
+	// The quote is priced at the network's gas price (GasPriceFor)
+	gasPrice := GasPriceFor(endpoint.Network)
 
 	// Build the base API URL
 	baseURL := fmt.Sprintf("https://open-api.openocean.finance/v4/%s/quote", chainName)
@@ -240,18 +224,22 @@ func (b *OpenOceanURLBuilder) BuildURL(endpoint *collector.Endpoint, options api
 	params.Add("inTokenAddress", endpoint.TokenIn)
 	params.Add("outTokenAddress", endpoint.TokenOut)
 	params.Add("amountDecimals", endpoint.SwapAmount)
-	params.Add("gasPriceDecimals", gasPrice)
+	params.Add("gasPriceDecimals", gasPrice.String())
 	params.Add("slippage", "1")
 
 	// Only add DEX filtering if we're filtering for Balancer sources only
 	if options.IsBalancerSourceOnly {
-		enabledDexIds, err := b.getBalancerDexIndices(chainName)
+		src, err := sources.ForEndpoint("openocean", endpoint)
 		if err != nil {
-			fmt.Printf("%s[WARNING]%s OpenOcean: Failed to fetch Balancer DEX indices for chain %s: %v\n", config.ColorYellow, config.ColorReset, chainName, err)
+			return "", err
+		}
+		enabledDexIds, err := b.getBalancerDexIndices(chainName, src)
+		if err != nil {
+			logs.Printf("%s[WARNING]%s OpenOcean: Failed to fetch Balancer DEX indices for chain %s: %v\n", config.ColorYellow, config.ColorReset, chainName, err)
 		} else if enabledDexIds != "" {
 			params.Add("enabledDexIds", enabledDexIds)
 		} else {
-			return "", fmt.Errorf("OpenOcean has no Balancer V3 in dexList for chain %q: %w", chainName, api.ErrBuildURLUnsupported)
+			return "", fmt.Errorf("OpenOcean has no Balancer V3 in dexList for chain %q: %w", chainName, check.ErrBuildURLUnsupported)
 		}
 	}
 
@@ -292,148 +280,129 @@ func (b *OpenOceanURLBuilder) getChainName(chainID string) string {
 	}
 }
 
-// getDefaultGasPrice returns a hardcoded fallback gas price (in wei) for each chain
-func (b *OpenOceanURLBuilder) getDefaultGasPrice(chainName string) string {
-	switch chainName {
-	case "eth":
-		return "30000000000" // 30 gwei
-	case "bsc":
-		return "3000000000" // 3 gwei
-	case "arbitrum":
-		return "100000000" // 0.1 gwei
-	case "polygon":
-		return "30000000000" // 30 gwei
-	case "optimism":
-		return "1000000" // 0.001 gwei
-	case "avax":
-		return "25000000000" // 25 gwei
-	case "base":
-		return "1000000" // 0.001 gwei
-	case "gnosis":
-		return "2000000000" // 2 gwei
-	case "fantom":
-		return "50000000000" // 50 gwei
-	case "zksync":
-		return "250000000" // 0.25 gwei
-	case "linea":
-		return "50000000" // 0.05 gwei
-	case "scroll":
-		return "100000000" // 0.1 gwei
-	case "monad":
-		return "1000000000" // 1 gwei
-	default:
-		return "30000000000" // 30 gwei as a safe default
+// openOceanClient makes OpenOcean's DEX list requests.
+var openOceanClient = &http.Client{Timeout: 10 * time.Second}
+
+// openOceanGet GETs an OpenOcean API URL with the monitor's User-Agent.
+func openOceanGet(rawURL string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
 	}
+	req.Header.Set("User-Agent", config.GetUserAgent())
+	return openOceanClient.Do(req)
 }
 
-// getGasPrice fetches the current gas price from OpenOcean's gasPrice endpoint
-func (b *OpenOceanURLBuilder) getGasPrice(chainName string) (string, error) {
-	gasURL := fmt.Sprintf("https://open-api.openocean.finance/v4/%s/gasPrice", chainName)
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+// openOceanDexListTTL is how long a chain's BalancerV3 DEX indices are cached.
+// The list changes only when OpenOcean integrates a new DEX.
+const openOceanDexListTTL = time.Hour
+
+// dryRunDexIndices stands in for a chain's DEX indices in dry run, so the
+// URL is built and validated without asking OpenOcean.
+const dryRunDexIndices = "0"
+
+// getBalancerDexIndices returns the indices of a chain's DEXs that src
+// matches, from the shared cache or OpenOcean's dexList; dryRunDexIndices
+// in dry run.
+func (b *OpenOceanURLBuilder) getBalancerDexIndices(chainName string, src sources.Entry) (string, error) {
+	if config.GetDryRunEnabled() {
+		return dryRunDexIndices, nil
 	}
+	return shared.GetOrLoad(dexIndicesCacheKey(chainName, src), openOceanDexListTTL, func() (string, error) {
+		return b.fetchBalancerDexIndices(chainName, src)
+	})
+}
 
-	resp, err := client.Get(gasURL)
-	if err != nil {
-		return "", fmt.Errorf("error fetching gas price: %v", err)
+// dexIndicesCacheKey keys a chain's indices by what src matches, its sorted
+// IDs and Contains, so pool kinds with different sources on one chain don't
+// share an entry.
+func dexIndicesCacheKey(chainName string, src sources.Entry) string {
+	ids := slices.Sorted(slices.Values(src.IDs))
+	key := "openocean:dexlist:" + chainName + ":" + strings.Join(ids, ",")
+	if src.Contains {
+		key += ":contains"
 	}
-	defer resp.Body.Close()
+	return key
+}
 
-	body, err := io.ReadAll(resp.Body)
+// fetchBalancerDexIndices fetches the DEX list from OpenOcean and returns BalancerV3 DEX indices
+func (b *OpenOceanURLBuilder) fetchBalancerDexIndices(chainName string, src sources.Entry) (string, error) {
+	dexes, err := fetchOpenOceanDexList(chainName)
 	if err != nil {
-		return "", fmt.Errorf("error reading gas price response: %v", err)
+		return "", err
 	}
 
-	var gasResponse OpenOceanGasPriceResponse
-	if err := json.Unmarshal(body, &gasResponse); err != nil {
-		return "", fmt.Errorf("error parsing gas price response: %v", err)
-	}
+	// Find all Balancer-related DEXs and log them
+	var allBalancerDexes []string
+	var v3Indices []string
 
-	if gasResponse.Code != 200 {
-		return "", fmt.Errorf("gas price API returned code %d", gasResponse.Code)
-	}
+	for _, dex := range dexes {
+		if strings.Contains(strings.ToLower(dex.Code), "balancer") || strings.Contains(strings.ToLower(dex.Name), "balancer") {
+			allBalancerDexes = append(allBalancerDexes, fmt.Sprintf("index=%d %s", dex.Index, dex.Code))
 
-	// The standard field can be either a number (non-EVM style) or an object (EVM style with legacyGasPrice)
-	switch v := gasResponse.Data.Standard.(type) {
-	case float64:
-		return fmt.Sprintf("%.0f", v), nil
-	case map[string]interface{}:
-		if legacyGasPrice, ok := v["legacyGasPrice"]; ok {
-			if price, ok := legacyGasPrice.(float64); ok {
-				return fmt.Sprintf("%.0f", price), nil
+			// Only include BalancerV3 DEXs for filtering
+			if src.Matches(dex.Code) {
+				v3Indices = append(v3Indices, fmt.Sprintf("%d", dex.Index))
 			}
 		}
-		return "", fmt.Errorf("could not extract legacyGasPrice from standard gas price object")
-	default:
-		return "", fmt.Errorf("unexpected gas price format: %T", v)
 	}
-}
 
-// getBalancerDexIndices fetches the DEX list from OpenOcean and returns BalancerV3 DEX indices
-func (b *OpenOceanURLBuilder) getBalancerDexIndices(chainName string) (string, error) {
-	dexURL := fmt.Sprintf("https://open-api.openocean.finance/v4/%s/dexList", chainName)
+	// Log all Balancer-related DEXs for visibility
+	if len(allBalancerDexes) > 0 {
+		logs.Printf("%s[INFO]%s OpenOcean Balancer DEXs on chain %s: %s\n", config.ColorCyan, config.ColorReset, chainName, strings.Join(allBalancerDexes, ", "))
+	} else {
+		logs.Printf("%s[WARNING]%s OpenOcean: No Balancer DEXs found on chain %s\n", config.ColorYellow, config.ColorReset, chainName)
+	}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+	// Log the filtered V3 indices
+	if len(v3Indices) > 0 {
+		logs.Printf("%s[INFO]%s OpenOcean: Using BalancerV3 DEX indices for chain %s: %s\n", config.ColorCyan, config.ColorReset, chainName, strings.Join(v3Indices, ","))
 	}
 
-	resp, err := client.Get(dexURL)
+	return strings.Join(v3Indices, ","), nil
+}
+
+// fetchOpenOceanDexList fetches a chain's DEX list from OpenOcean.
+func fetchOpenOceanDexList(chainName string) ([]OpenOceanDexInfo, error) {
+	dexURL := fmt.Sprintf("https://open-api.openocean.finance/v4/%s/dexList", chainName)
+
+	resp, err := openOceanGet(dexURL)
 	if err != nil {
-		return "", fmt.Errorf("error fetching DEX list: %v", err)
+		return nil, fmt.Errorf("error fetching DEX list: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("error reading DEX list response: %v", err)
+		return nil, fmt.Errorf("error reading DEX list response: %v", err)
 	}
 
 	var dexListResponse OpenOceanDexListResponse
 	if err := json.Unmarshal(body, &dexListResponse); err != nil {
-		return "", fmt.Errorf("error parsing DEX list response: %v", err)
+		return nil, fmt.Errorf("error parsing DEX list response: %v", err)
 	}
 
 	if dexListResponse.Code != 200 {
-		return "", fmt.Errorf("DEX list API returned code %d", dexListResponse.Code)
+		return nil, fmt.Errorf("DEX list API returned code %d", dexListResponse.Code)
 	}
 
-	// Find all Balancer-related DEXs and log them
-	var allBalancerDexes []string
-	var v3Indices []string
-
-	for _, dex := range dexListResponse.Data {
-		if strings.Contains(strings.ToLower(dex.Code), "balancer") || strings.Contains(strings.ToLower(dex.Name), "balancer") {
-			allBalancerDexes = append(allBalancerDexes, fmt.Sprintf("index=%d %s", dex.Index, dex.Code))
+	return dexListResponse.Data, nil
+}
 
-			// Only include BalancerV3 DEXs for filtering
-			if strings.Contains(dex.Code, "BalancerV3") {
-				v3Indices = append(v3Indices, fmt.Sprintf("%d", dex.Index))
+// routeGraph builds the route from the quote's path: routes of sub-routes,
+// each split across DEXs.
+func (r OpenOceanResponse) routeGraph() collector.RouteGraph {
+	var g collector.RouteGraph
+	for _, route := range r.Data.Path.Routes {
+		path := collector.RoutePath{Percent: route.Percentage}
+		for _, sub := range route.SubRoutes {
+			hop := collector.RouteHop{TokenIn: sub.From, TokenOut: sub.To}
+			for _, dex := range sub.Dexes {
+				hop.Venues = append(hop.Venues, collector.RouteVenue{Pool: dex.ID, Exchange: dex.Dex, Percent: dex.Percentage})
 			}
+			path.Hops = append(path.Hops, hop)
 		}
+		g.Paths = append(g.Paths, path)
 	}
-
-	// Log all Balancer-related DEXs for visibility
-	if len(allBalancerDexes) > 0 {
-		fmt.Printf("%s[INFO]%s OpenOcean Balancer DEXs on chain %s: %s\n", config.ColorCyan, config.ColorReset, chainName, strings.Join(allBalancerDexes, ", "))
-	} else {
-		fmt.Printf("%s[WARNING]%s OpenOcean: No Balancer DEXs found on chain %s\n", config.ColorYellow, config.ColorReset, chainName)
-	}
-
-	// Log the filtered V3 indices
-	if len(v3Indices) > 0 {
-		fmt.Printf("%s[INFO]%s OpenOcean: Using BalancerV3 DEX indices for chain %s: %s\n", config.ColorCyan, config.ColorReset, chainName, strings.Join(v3Indices, ","))
-	}
-
-	return strings.Join(v3Indices, ","), nil
+	return g
 }
@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"testing"
 
-	"go-monitoring/internal/api"
+	"go-monitoring/monitoring/check"
 )
 
 func TestOpenOceanNoBalancerV3ErrorWrapsUnsupported(t *testing.T) {
-	err := fmt.Errorf("OpenOcean has no Balancer V3 in dexList for chain %q: %w", "base", api.ErrBuildURLUnsupported)
-	if !errors.Is(err, api.ErrBuildURLUnsupported) {
+	err := fmt.Errorf("OpenOcean has no Balancer V3 in dexList for chain %q: %w", "base", check.ErrBuildURLUnsupported)
+	if !errors.Is(err, check.ErrBuildURLUnsupported) {
 		t.Fatalf("expected errors.Is(ErrBuildURLUnsupported): %v", err)
 	}
 }
@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"go-monitoring/config"
-	"go-monitoring/internal/api"
-	"go-monitoring/internal/collector"
-	"go-monitoring/notifications"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/notify"
+	"go-monitoring/monitoring/sources"
 )
 
 // ParaswapResponse represents the structure of the Paraswap API response
@@ -17,14 +19,28 @@ type ParaswapResponse struct {
 	Error      string `json:"error,omitempty"`
 	PriceRoute struct {
 		DestAmount string `json:"destAmount,omitempty"`
-		BestRoute  []struct {
-			Swaps []struct {
+		// TokenTransferProxy is the address a user approves.
+		TokenTransferProxy string `json:"tokenTransferProxy,omitempty"`
+		BestRoute          []struct {
+			Percent float64 `json:"percent"`
+			Swaps   []struct {
+				SrcToken      string `json:"srcToken"`
+				DestToken     string `json:"destToken"`
 				SwapExchanges []struct {
 					Exchange      string   `json:"exchange"`
+					Percent       float64  `json:"percent"`
+					SrcAmount     string   `json:"srcAmount"`
+					DestAmount    string   `json:"destAmount"`
 					PoolAddresses []string `json:"poolAddresses"`
 				} `json:"swapExchanges"`
 			} `json:"swaps"`
 		} `json:"bestRoute"`
+		// Others quotes each exchange alone for the full amount
+		// (otherExchangePrices=true).
+		Others []struct {
+			Exchange   string `json:"exchange"`
+			DestAmount string `json:"destAmount"`
+		} `json:"others"`
 	} `json:"priceRoute"`
 }
 
@@ -40,7 +56,7 @@ func NewParaswapHandler() *ParaswapHandler {
 }
 
 // HandleResponse processes the Paraswap API response and validates it according to business rules
-func (h *ParaswapHandler) HandleResponse(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *ParaswapHandler) HandleResponse(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Check for no routes error message
 	if string(response.Body) == `{"error":"No routes found with enough liquidity"}` {
 		h.handleError(endpoint, "down", "No routes found with enough liquidity", string(response.Body))
@@ -65,29 +81,28 @@ func (h *ParaswapHandler) HandleResponse(response *api.APIResponse, endpoint *co
 		h.handleError(endpoint, "down", "No best route found", string(response.Body))
 		return fmt.Errorf("no best route found")
 	}
+	endpoint.Spender = result.PriceRoute.TokenTransferProxy
+	endpoint.SetRoute(result.routeGraph())
 
 	// If there's an error but we have a valid route, log it but don't treat as failure
 	if result.Error != "" {
-		fmt.Printf("%s[WARNING]%s %s: API returned error but has valid route: %s\n", config.ColorYellow, config.ColorReset, endpoint.Name, result.Error)
+		logs.Printf("%s[WARNING]%s %s: API returned error but has valid route: %s\n", config.ColorYellow, config.ColorReset, endpoint.Name, result.Error)
 		endpoint.Message = fmt.Sprintf("Warning: %s (but route is valid)", result.Error)
 	}
 
 	// Check if the route uses Balancer V3 and includes the expected pool
+	src, err := sources.ForEndpoint("paraswap", endpoint)
+	if err != nil {
+		h.handleError(endpoint, "error", err.Error(), "")
+		return err
+	}
 	foundBalancerV3 := false
 	foundExpectedPool := false
-	for _, route := range result.PriceRoute.BestRoute {
-		for _, swap := range route.Swaps {
-			for _, exchange := range swap.SwapExchanges {
-				if exchange.Exchange == "BalancerV3" {
-					foundBalancerV3 = true
-
-					for _, poolAddress := range exchange.PoolAddresses {
-						if strings.EqualFold(poolAddress, endpoint.ExpectedPool) {
-							foundExpectedPool = true
-							break
-						}
-					}
-				}
+	for _, venue := range endpoint.Route.Venues() {
+		if src.Matches(venue.Exchange) {
+			foundBalancerV3 = true
+			if venue.Pool != "" && config.SameAddress(venue.Pool, endpoint.ExpectedPool) {
+				foundExpectedPool = true
 			}
 		}
 	}
@@ -106,16 +121,18 @@ func (h *ParaswapHandler) HandleResponse(response *api.APIResponse, endpoint *co
 		return fmt.Errorf("expected pool %s not found in balancerv3 route", endpoint.ExpectedPool)
 	}
 
-	// Store the return amount if available
-	if result.PriceRoute.DestAmount != "" {
-		endpoint.ReturnAmount = result.PriceRoute.DestAmount
+	// Store the return amount
+	if result.PriceRoute.DestAmount == "" {
+		h.handleError(endpoint, "down", "no destAmount in response", string(response.Body))
+		return fmt.Errorf("no destAmount in response")
 	}
+	endpoint.ReturnAmount = result.PriceRoute.DestAmount
 
 	return nil
 }
 
 // HandleResponseForMarketPrice processes the Paraswap API response for market price (all sources)
-func (h *ParaswapHandler) HandleResponseForMarketPrice(response *api.APIResponse, endpoint *collector.Endpoint) error {
+func (h *ParaswapHandler) HandleResponseForMarketPrice(response *check.APIResponse, endpoint *collector.Endpoint) error {
 	// Parse the JSON response
 	var result ParaswapResponse
 	err := json.Unmarshal(response.Body, &result)
@@ -128,6 +145,13 @@ func (h *ParaswapHandler) HandleResponseForMarketPrice(response *api.APIResponse
 		endpoint.MarketPrice = result.PriceRoute.DestAmount
 	}
 
+	// Rank Balancer V3 among the single-exchange quotes
+	quotes := make([]sourceQuote, 0, len(result.PriceRoute.Others))
+	for _, other := range result.PriceRoute.Others {
+		quotes = append(quotes, sourceQuote{Source: other.Exchange, Amount: other.DestAmount})
+	}
+	setBalancerRank(endpoint, "paraswap", quotes)
+
 	return nil
 }
 
@@ -141,8 +165,8 @@ func (h *ParaswapHandler) GetIgnoreList(network string) (string, error) {
 func (h *ParaswapHandler) handleError(endpoint *collector.Endpoint, status, message, responseBody string) {
 	endpoint.LastStatus = status
 	endpoint.Message = message
-	fmt.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
-	notifications.SendEmail(fmt.Sprintf("[%s] %s\nResponse body:\n%s", endpoint.Name, message, responseBody))
+	logs.Printf("%s[ERROR]%s %s: %s\nResponse body:\n%s\n", config.ColorRed, config.ColorReset, endpoint.Name, message, responseBody)
+	notify.SendEndpointAlert(endpoint, message, responseBody)
 }
 
 // NewParaswapURLBuilder creates a new Paraswap URL builder
@@ -151,7 +175,7 @@ func NewParaswapURLBuilder() *ParaswapURLBuilder {
 }
 
 // BuildURL builds the complete URL for Paraswap API requests
-func (b *ParaswapURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.RequestOptions) (string, error) {
+func (b *ParaswapURLBuilder) BuildURL(endpoint *collector.Endpoint, options check.RequestOptions) (string, error) {
 	baseURL := "https://api.paraswap.io/prices/"
 
 	// Build parameters
@@ -171,8 +195,55 @@ func (b *ParaswapURLBuilder) BuildURL(endpoint *collector.Endpoint, options api.
 
 	// Only add includeDEXS if we're filtering for Balancer sources only
 	if options.IsBalancerSourceOnly {
-		params.Add("includeDEXS", "BalancerV3")
+		src, err := sources.ForEndpoint("paraswap", endpoint)
+		if err != nil {
+			return "", err
+		}
+		params.Add("includeDEXS", src.String())
+	} else if len(options.ExcludeSources) > 0 {
+		params.Add("excludeDEXS", strings.Join(options.ExcludeSources, ","))
 	}
 
 	return fmt.Sprintf("%s?%s", baseURL, params.Encode()), nil
 }
+
+// ParseRoute returns the route of a quote response, for the exclusion check.
+func (h *ParaswapHandler) ParseRoute(body []byte) (collector.RouteGraph, error) {
+	var result ParaswapResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return collector.RouteGraph{}, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	if len(result.PriceRoute.BestRoute) == 0 && result.Error != "" {
+		return collector.RouteGraph{}, fmt.Errorf("API error: %s", result.Error)
+	}
+	return result.routeGraph(), nil
+}
+
+// routeGraph builds the route from bestRoute; a swap exchange quoting
+// through several pools is one venue per pool, and only a single-pool venue
+// carries the exchange's amounts.
+func (r ParaswapResponse) routeGraph() collector.RouteGraph {
+	var g collector.RouteGraph
+	for _, route := range r.PriceRoute.BestRoute {
+		path := collector.RoutePath{Percent: route.Percent}
+		for _, swap := range route.Swaps {
+			hop := collector.RouteHop{TokenIn: swap.SrcToken, TokenOut: swap.DestToken}
+			for _, exchange := range swap.SwapExchanges {
+				venue := collector.RouteVenue{Exchange: exchange.Exchange, Percent: exchange.Percent}
+				if len(exchange.PoolAddresses) <= 1 {
+					venue.AmountIn, venue.AmountOut = exchange.SrcAmount, exchange.DestAmount
+				}
+				if len(exchange.PoolAddresses) == 0 {
+					hop.Venues = append(hop.Venues, venue)
+				}
+				for _, pool := range exchange.PoolAddresses {
+					venue.Pool = pool
+					hop.Venues = append(hop.Venues, venue)
+				}
+			}
+			path.Hops = append(path.Hops, hop)
+		}
+		g.Paths = append(g.Paths, path)
+	}
+	return g
+}
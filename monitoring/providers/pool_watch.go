@@ -0,0 +1,215 @@
+package providers
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-monitoring/config"
+)
+
+// vaultAddress is the Balancer V3 Vault, deployed at the same address on
+// every network.
+const vaultAddress = "0xbA1333333333a1BA1108E8412f11850A5C319bA9"
+
+// maxLogRange bounds the blocks one eth_getLogs covers; public RPCs commonly
+// reject wider ranges. A scan that falls further behind catches up over
+// several polls.
+const maxLogRange = 2000
+
+// logTimeout bounds an eth_blockNumber or eth_getLogs request.
+const logTimeout = 30 * time.Second
+
+var (
+	// poolCreatedTopic is emitted by every Balancer V3 pool factory with the
+	// new pool as its indexed argument.
+	poolCreatedTopic = crypto.Keccak256Hash([]byte("PoolCreated(address)"))
+
+	getPoolTokensSelector = crypto.Keccak256([]byte("getPoolTokens(address)"))[:4]
+	decimalsSelector      = crypto.Keccak256([]byte("decimals()"))[:4]
+	symbolSelector        = crypto.Keccak256([]byte("symbol()"))[:4]
+)
+
+// CreatedPool is a pool a watched factory created.
+type CreatedPool struct {
+	Pool    string
+	Factory string
+	Block   uint64
+}
+
+// TokenInfo is an ERC-20's symbol and decimals.
+type TokenInfo struct {
+	Address  string
+	Symbol   string
+	Decimals int
+}
+
+// logEntry is the part of an eth_getLogs entry PoolsCreated reads.
+type logEntry struct {
+	Address     string         `json:"address"`
+	Topics      []string       `json:"topics"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Removed     bool           `json:"removed"`
+}
+
+// PoolsCreated returns the pools the factories on network created after
+// block from, and the last block scanned. A from of 0 scans nothing and
+// returns the head, the starting point of a new watch. At most maxLogRange
+// blocks are scanned per call.
+func PoolsCreated(network string, factories []string, from uint64) ([]CreatedPool, uint64, error) {
+	rpcURL := config.GetRPCURL(network)
+	if rpcURL == "" {
+		return nil, from, fmt.Errorf("no RPC URL configured for network %s", network)
+	}
+	client, err := getClient(rpcURL)
+	if err != nil {
+		return nil, from, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), logTimeout)
+	defer cancel()
+
+	var head hexutil.Uint64
+	if err := client.Client().CallContext(ctx, &head, "eth_blockNumber"); err != nil {
+		return nil, from, fmt.Errorf("eth_blockNumber: %w", err)
+	}
+	to := uint64(head)
+	if from == 0 || to <= from {
+		return nil, to, nil
+	}
+	if to-from > maxLogRange {
+		to = from + maxLogRange
+	}
+
+	var logs []logEntry
+	filter := map[string]interface{}{
+		"fromBlock": hexutil.Uint64(from + 1),
+		"toBlock":   hexutil.Uint64(to),
+		"address":   factories,
+		"topics":    []interface{}{poolCreatedTopic.Hex()},
+	}
+	if err := client.Client().CallContext(ctx, &logs, "eth_getLogs", filter); err != nil {
+		return nil, from, fmt.Errorf("eth_getLogs: %w", err)
+	}
+	return createdPools(logs), to, nil
+}
+
+// createdPools decodes PoolCreated logs, skipping removed (reorged) ones.
+func createdPools(logs []logEntry) []CreatedPool {
+	var out []CreatedPool
+	for _, l := range logs {
+		if l.Removed || len(l.Topics) < 2 || !strings.EqualFold(l.Topics[0], poolCreatedTopic.Hex()) {
+			continue
+		}
+		out = append(out, CreatedPool{
+			Pool:    common.HexToAddress(l.Topics[1]).Hex(),
+			Factory: l.Address,
+			Block:   uint64(l.BlockNumber),
+		})
+	}
+	return out
+}
+
+// PoolTokens returns the pool's registered tokens, in pool order, with their
+// symbols and decimals, read from the Vault and the tokens themselves.
+func PoolTokens(network, pool string) ([]TokenInfo, error) {
+	rpcURL := config.GetRPCURL(network)
+	if rpcURL == "" {
+		return nil, fmt.Errorf("no RPC URL configured for network %s", network)
+	}
+	poolAddr := common.HexToAddress(pool)
+	result, err := viewCall(rpcURL, vaultAddress, append(append([]byte{}, getPoolTokensSelector...), common.LeftPadBytes(poolAddr.Bytes(), 32)...))
+	if err != nil {
+		return nil, fmt.Errorf("getPoolTokens: %w", err)
+	}
+	addresses, err := decodeAddressArray(result)
+	if err != nil {
+		return nil, fmt.Errorf("getPoolTokens: %w", err)
+	}
+
+	tokens := make([]TokenInfo, 0, len(addresses))
+	for _, address := range addresses {
+		info := TokenInfo{Address: address}
+		result, err := viewCall(rpcURL, address, decimalsSelector)
+		if err != nil {
+			return nil, fmt.Errorf("decimals of %s: %w", address, err)
+		}
+		if len(result) < 32 {
+			return nil, fmt.Errorf("decimals of %s: short result (%d bytes)", address, len(result))
+		}
+		info.Decimals = int(new(big.Int).SetBytes(result[:32]).Int64())
+		if result, err := viewCall(rpcURL, address, symbolSelector); err == nil {
+			info.Symbol = decodeSymbol(result)
+		}
+		if info.Symbol == "" {
+			info.Symbol = address[:8]
+		}
+		tokens = append(tokens, info)
+	}
+	return tokens, nil
+}
+
+// viewCall runs an eth_call at the head.
+func viewCall(rpcURL, to string, data []byte) ([]byte, error) {
+	addr := common.HexToAddress(to)
+	call, err := callRPC(rpcURL, ethereum.CallMsg{To: &addr, Data: data}, 0)
+	return call.Result, err
+}
+
+// decodeAddressArray decodes an ABI-encoded address[] return value.
+func decodeAddressArray(b []byte) ([]string, error) {
+	offset, ok := abiWord(b, 0)
+	if !ok {
+		return nil, fmt.Errorf("short result (%d bytes)", len(b))
+	}
+	n, ok := abiWord(b, offset)
+	if !ok || n > uint64(len(b))/32 {
+		return nil, fmt.Errorf("bad array length")
+	}
+	out := make([]string, 0, n)
+	for i := uint64(0); i < n; i++ {
+		start := offset + 32 + i*32
+		if start+32 > uint64(len(b)) {
+			return nil, fmt.Errorf("short array")
+		}
+		out = append(out, common.HexToAddress(hex.EncodeToString(b[start+12:start+32])).Hex())
+	}
+	return out, nil
+}
+
+// decodeSymbol decodes symbol() as an ABI string, or as the bytes32 some
+// older tokens return.
+func decodeSymbol(b []byte) string {
+	if len(b) == 32 {
+		return strings.TrimRight(string(b), "\x00")
+	}
+	offset, ok := abiWord(b, 0)
+	if !ok {
+		return ""
+	}
+	n, ok := abiWord(b, offset)
+	if !ok || n > uint64(len(b)) || offset+32+n > uint64(len(b)) {
+		return ""
+	}
+	return string(b[offset+32 : offset+32+n])
+}
+
+// abiWord reads the 32-byte word at byte offset at as a uint64; ok is false
+// when it is out of range or doesn't fit.
+func abiWord(b []byte, at uint64) (uint64, bool) {
+	if at+32 > uint64(len(b)) || at+32 < at {
+		return 0, false
+	}
+	word := new(big.Int).SetBytes(b[at : at+32])
+	if !word.IsUint64() {
+		return 0, false
+	}
+	return word.Uint64(), true
+}
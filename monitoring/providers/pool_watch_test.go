@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestCreatedPoolsSkipsRemovedAndForeignLogs(t *testing.T) {
+	topic := poolCreatedTopic.Hex()
+	pool := "0x000000000000000000000000" + "00000000000000000000000000000000000000a1"
+	logs := []logEntry{
+		{Address: "0xf1", Topics: []string{topic, pool}, BlockNumber: 7},
+		{Address: "0xf1", Topics: []string{topic, pool}, BlockNumber: 8, Removed: true},
+		{Address: "0xf1", Topics: []string{"0x01", pool}, BlockNumber: 9},
+		{Address: "0xf1", Topics: []string{topic}, BlockNumber: 10},
+	}
+	got := createdPools(logs)
+	if len(got) != 1 || !strings.EqualFold(got[0].Pool, "0x00000000000000000000000000000000000000a1") || got[0].Factory != "0xf1" || got[0].Block != 7 {
+		t.Fatalf("createdPools = %+v", got)
+	}
+}
+
+func TestDecodeAddressArray(t *testing.T) {
+	b, _ := hex.DecodeString(strings.Join([]string{
+		"0000000000000000000000000000000000000000000000000000000000000020",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"0000000000000000000000006bb7a212910682dcfdbd5bcbb3e28fb4e8da10ee",
+		"000000000000000000000000833589fcd6edb6e08f4c7c32d4f71b54bda02913",
+	}, ""))
+	got, err := decodeAddressArray(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || !strings.EqualFold(got[0], "0x6Bb7a212910682DCFdbd5BCBb3e28FB4E8da10Ee") || !strings.EqualFold(got[1], "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913") {
+		t.Fatalf("decodeAddressArray = %v", got)
+	}
+
+	if _, err := decodeAddressArray(b[:96]); err == nil {
+		t.Error("expected an error for a truncated array")
+	}
+}
+
+func TestDecodeSymbol(t *testing.T) {
+	str, _ := hex.DecodeString(
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000004" +
+			"5553444300000000000000000000000000000000000000000000000000000000")
+	if got := decodeSymbol(str); got != "USDC" {
+		t.Errorf("string symbol = %q, want USDC", got)
+	}
+	b32, _ := hex.DecodeString("4d4b520000000000000000000000000000000000000000000000000000000000")
+	if got := decodeSymbol(b32); got != "MKR" {
+		t.Errorf("bytes32 symbol = %q, want MKR", got)
+	}
+	if got := decodeSymbol(nil); got != "" {
+		t.Errorf("empty result = %q, want empty", got)
+	}
+}
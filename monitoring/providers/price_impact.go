@@ -0,0 +1,22 @@
+package providers
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// priceImpactPercentToBps converts a provider-reported price impact in percent
+// (OpenOcean "-0.02%", HyperBloom "0.0153") to absolute basis points. ok is
+// false when the field is missing or not a number.
+func priceImpactPercentToBps(s string) (bps float64, ok bool) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	if s == "" {
+		return 0, false
+	}
+	pct, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(pct) || math.IsInf(pct, 0) {
+		return 0, false
+	}
+	return math.Abs(pct) * 100, true
+}
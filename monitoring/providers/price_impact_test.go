@@ -0,0 +1,23 @@
+package providers
+
+import "testing"
+
+func TestPriceImpactPercentToBps(t *testing.T) {
+	cases := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"-0.02%", 2, true},
+		{"0.0153", 1.53, true},
+		{" 1.5 % ", 150, true},
+		{"", 0, false},
+		{"n/a", 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := priceImpactPercentToBps(tc.in)
+		if ok != tc.ok || (ok && (got < tc.want-1e-9 || got > tc.want+1e-9)) {
+			t.Errorf("priceImpactPercentToBps(%q) = %v, %v; want %v, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"math/big"
+
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/sources"
+)
+
+// sourceQuote is one source's quote for the full swap, from a provider that
+// lists per-source prices (Paraswap others, OpenOcean dexes).
+type sourceQuote struct {
+	Source string
+	Amount string // token_out base units
+}
+
+// balancerRank ranks the best quote src matches among quotes, best first:
+// 1 plus the number of sources quoting strictly more. n counts the quotes
+// with a usable amount; rank is 0 when src has none of them.
+func balancerRank(quotes []sourceQuote, src sources.Entry) (rank, n int) {
+	var best *big.Int
+	amounts := make([]*big.Int, 0, len(quotes))
+	for _, q := range quotes {
+		amount, ok := new(big.Int).SetString(q.Amount, 10)
+		if !ok || amount.Sign() <= 0 {
+			continue
+		}
+		amounts = append(amounts, amount)
+		if src.Matches(q.Source) && (best == nil || amount.Cmp(best) > 0) {
+			best = amount
+		}
+	}
+	if best == nil {
+		return 0, len(amounts)
+	}
+	rank = 1
+	for _, amount := range amounts {
+		if amount.Cmp(best) > 0 {
+			rank++
+		}
+	}
+	return rank, len(amounts)
+}
+
+// setBalancerRank records the Balancer V3 rank among quotes on endpoint.
+// Endpoints without a source registry entry, and responses without
+// per-source quotes, are left unranked, clearing a previous check's rank.
+func setBalancerRank(endpoint *collector.Endpoint, solver string, quotes []sourceQuote) {
+	endpoint.BalancerRank, endpoint.RankedSources = 0, 0
+	src, err := sources.ForEndpoint(solver, endpoint)
+	if err != nil || len(quotes) == 0 {
+		return
+	}
+	endpoint.BalancerRank, endpoint.RankedSources = balancerRank(quotes, src)
+}
@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"testing"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+	"go-monitoring/monitoring/sources"
+)
+
+func TestBalancerRank(t *testing.T) {
+	src := sources.Entry{IDs: []string{"BalancerV3"}, Contains: true}
+	quotes := []sourceQuote{
+		{"UniswapV3", "1005"},
+		{"BalancerV3Stable", "1000"},
+		{"CurveV1", "1000"},
+		{"BalancerV3", "990"},
+		{"SushiSwap", "bad"},
+		{"Empty", "0"},
+	}
+	rank, n := balancerRank(quotes, src)
+	if rank != 2 || n != 4 {
+		t.Fatalf("rank = %d of %d, want 2 of 4", rank, n)
+	}
+	if rank, n := balancerRank(quotes[:1], src); rank != 0 || n != 1 {
+		t.Fatalf("without Balancer: rank = %d of %d", rank, n)
+	}
+}
+
+func TestParaswapMarketPriceRank(t *testing.T) {
+	body := `{"priceRoute":{"destAmount":"1005","others":[
+		{"exchange":"UniswapV3","destAmount":"1005"},
+		{"exchange":"BalancerV3","destAmount":"1001"},
+		{"exchange":"CurveV1","destAmount":"1003"}]}}`
+	e := &collector.Endpoint{Network: "1"}
+	if err := NewParaswapHandler().HandleResponseForMarketPrice(&check.APIResponse{Body: []byte(body)}, e); err != nil {
+		t.Fatal(err)
+	}
+	if e.MarketPrice != "1005" || e.BalancerRank != 3 || e.RankedSources != 3 {
+		t.Fatalf("got %s rank %d of %d", e.MarketPrice, e.BalancerRank, e.RankedSources)
+	}
+}
+
+func TestSetBalancerRankClearsStaleRank(t *testing.T) {
+	e := &collector.Endpoint{Network: "1", BalancerRank: 2, RankedSources: 4}
+	setBalancerRank(e, "paraswap", nil)
+	if e.BalancerRank != 0 || e.RankedSources != 0 {
+		t.Fatalf("rank %d of %d kept from the last check", e.BalancerRank, e.RankedSources)
+	}
+}
@@ -0,0 +1,478 @@
+// Package providers implements the per-aggregator checks: a response
+// handler and URL / request body builder per route solver, plus a Registry
+// that runs them. Other tools can embed the checkers without the monitor
+// loops or the HTTP dashboard:
+//
+//	r := providers.NewDefaultRegistry()
+//	e := collector.Endpoint{Name: "GHO/USDC", RouteSolver: "kyberswap", Network: "42161", ...}
+//	res := r.Check(&e, nil)
+//	logs.Println(res.Status, res.Message, res.ReturnAmount, res.Latency)
+//
+// Providers of your own implement the interfaces in package check and are
+// added with Registry.Register.
+//
+// API keys are read from the environment (see config.RouteSolvers); failures
+// are emailed through package notify when EMAIL_NOTIFICATIONS is set.
+package providers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/api"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+// The request/response interfaces a provider implements, defined in package
+// check with the APIResponse and RequestOptions their methods take, so
+// embedders can implement and Register their own providers.
+type (
+	ResponseHandler    = check.ResponseHandler
+	URLBuilder         = check.URLBuilder
+	RequestBodyBuilder = check.RequestBodyBuilder
+)
+
+// CheckResult is what Check returns: status, message, failure class,
+// amounts, latency and the raw response body (check.Result).
+type CheckResult = check.Result
+
+// ProviderConfig holds the configuration for a provider
+type ProviderConfig struct {
+	Handler            ResponseHandler
+	URLBuilder         URLBuilder
+	RequestBodyBuilder RequestBodyBuilder
+	BaseURL            string
+	APIKeyEnvVar       string
+	CustomHeaders      map[string]string
+	UsePOST            bool          // Whether to use POST request instead of GET
+	SourceCatalog      SourceCatalog // Optional: lists the provider's liquidity sources
+	// FallbackURLs, when set, returns alternate URLs a POST is resent to
+	// when the built one fails (check.RequestOptions.FallbackURLs).
+	FallbackURLs func() []string
+	// ExclusionCheck is set for providers whose URL builder honors
+	// RequestOptions.ExcludeSources and whose Handler implements
+	// RouteParser, so CheckExclusion can run against them.
+	ExclusionCheck bool
+	// Capabilities declares the features the provider's API offers; calls
+	// it doesn't support are not made.
+	Capabilities Capabilities
+}
+
+// CheckOptions provides optional configuration for provider checks
+type CheckOptions struct {
+	IsBalancerSourceOnly *bool // Optional override for Balancer source only usage
+	// MarketPrices, when set, memoizes the market price call across the
+	// endpoints checked with the same options (e.g. one monitor cycle).
+	MarketPrices *MarketPriceCache
+	// SORBatch, when set, answers Balancer SOR requests from batched
+	// requests for the endpoints checked with the same options.
+	SORBatch *SORBatch
+}
+
+// sorBatch returns o's SORBatch; nil for nil options.
+func (o *CheckOptions) sorBatch() *SORBatch {
+	if o == nil {
+		return nil
+	}
+	return o.SORBatch
+}
+
+// Registry maps route solver types to provider configs and runs checks
+// against them. It holds no other state: results are written to the endpoint
+// passed to Check, so a Registry can be used without the monitor loops or the
+// dashboard.
+type Registry struct {
+	providers map[string]ProviderConfig
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]ProviderConfig),
+	}
+}
+
+// Register registers a provider with the generic client under a route
+// solver type, replacing any existing registration.
+func (r *Registry) Register(name string, config ProviderConfig) {
+	r.providers[name] = config
+}
+
+// Lookup returns the config registered for a route solver type.
+func (r *Registry) Lookup(name string) (ProviderConfig, bool) {
+	config, ok := r.providers[name]
+	return config, ok
+}
+
+// Check quotes endpoint against the provider registered for its RouteSolver,
+// writes the result (LastStatus, Message, ReturnAmount, ...) to endpoint and
+// returns it. With nil options, or options without IsBalancerSourceOnly, it
+// makes both the Balancer-only and the market price call, as the monitor
+// does; otherwise only the Balancer-only call. The result's HTTP status,
+// latency and body are the Balancer-only call's.
+func (r *Registry) Check(endpoint *collector.Endpoint, options *CheckOptions) CheckResult {
+	// Check if provider uses new generic client
+	if providerConfig, exists := r.providers[endpoint.RouteSolver]; exists {
+		// If no specific options provided, make both calls (Balancer-only and market price)
+		if options == nil || options.IsBalancerSourceOnly == nil {
+			// First call: Balancer source only (existing behavior)
+			logs.Printf("%s[BALANCER CHECK]%s %s: Checking Balancer-only sources\n", config.ColorBlue, config.ColorReset, endpoint.Name)
+			balancerOptions := &CheckOptions{IsBalancerSourceOnly: &[]bool{true}[0], SORBatch: options.sorBatch()}
+			res := r.checkWithGenericClient(endpoint, providerConfig, balancerOptions)
+
+			// For balancer_sor, perform on-chain query after getting path information
+			if endpoint.RouteSolver == "balancer_sor" && config.GetDryRunEnabled() {
+				dryRunOnChainCall(endpoint)
+			} else if endpoint.RouteSolver == "balancer_sor" && len(endpoint.SwapPathPools) > 0 {
+				recordOnChainPrice(endpoint)
+			}
+			verifyHookQuote(endpoint, config.GetHookQuoteToleranceBps())
+
+			// Second call: Market price (all sources)
+			if !providerConfig.Capabilities.MarketPrice {
+				logs.Printf("%s[MARKET PRICE]%s %s: Not supported by %s, skipped\n", config.ColorYellow, config.ColorReset, endpoint.Name, endpoint.RouteSolver)
+				return check.ResultOf(endpoint, res.Body)
+			}
+			var cache *MarketPriceCache
+			if options != nil {
+				cache = options.MarketPrices
+			}
+			r.checkMarketPrice(endpoint, providerConfig, cache, options.sorBatch())
+			return check.ResultOf(endpoint, res.Body)
+		} else {
+			// Use provided options (for manual checks)
+			res := r.checkWithGenericClient(endpoint, providerConfig, options)
+
+			// For balancer_sor, perform on-chain query after getting path information
+			if endpoint.RouteSolver == "balancer_sor" && config.GetDryRunEnabled() {
+				dryRunOnChainCall(endpoint)
+			} else if endpoint.RouteSolver == "balancer_sor" && len(endpoint.SwapPathPools) > 0 {
+				recordOnChainPrice(endpoint)
+			}
+			if *options.IsBalancerSourceOnly {
+				verifyHookQuote(endpoint, config.GetHookQuoteToleranceBps())
+			}
+			return check.ResultOf(endpoint, res.Body)
+		}
+	}
+
+	// Provider not found
+	endpoint.LastChecked = clock.Now()
+	endpoint.LastStatus = "unsupported"
+	logs.Printf("Unsupported route solver '%s' for endpoint %s\n", endpoint.RouteSolver, endpoint.Name)
+	return check.ResultOf(endpoint, nil)
+}
+
+// checkWithGenericClient checks a provider using the new generic client and
+// returns the Balancer-only or market call's result.
+func (r *Registry) checkWithGenericClient(endpoint *collector.Endpoint, config ProviderConfig, checkOptions *CheckOptions) CheckResult {
+	// Check for WIP cases before making any requests
+	if r.isWIPCase(endpoint) {
+		r.handleWIPCase(endpoint)
+		return check.ResultOf(endpoint, nil)
+	}
+
+	client := api.NewAPIClient()
+
+	// Validate API key if required
+	var apiKey string
+	var err error
+	if config.APIKeyEnvVar != "" {
+		apiKey, err = client.ValidateAPIKey(config.APIKeyEnvVar, endpoint)
+		if err != nil {
+			return check.ResultOf(endpoint, nil) // Error already handled by ValidateAPIKey
+		}
+	}
+
+	headers := requestHeaders(endpoint.RouteSolver, config.CustomHeaders, apiKey)
+
+	// Use options if provided, otherwise default to true
+	isBalancerSourceOnly := true // Default behavior - most providers should use Balancer sources only
+	if checkOptions != nil && checkOptions.IsBalancerSourceOnly != nil {
+		isBalancerSourceOnly = *checkOptions.IsBalancerSourceOnly
+	}
+	// Configure request options
+	requestOptions := check.RequestOptions{
+		IsBalancerSourceOnly: isBalancerSourceOnly,
+		CustomHeaders:        headers,
+	}
+	if batch := checkOptions.sorBatch(); batch != nil {
+		requestOptions.Batch = batch
+	}
+	if config.FallbackURLs != nil {
+		requestOptions.FallbackURLs = config.FallbackURLs()
+	}
+
+	return client.CheckAPI(endpoint, config.Handler, config.URLBuilder, config.RequestBodyBuilder, config.UsePOST, requestOptions)
+}
+
+// requestHeaders returns the provider's custom headers plus its API key,
+// if any, in the header the provider expects.
+func requestHeaders(routeSolver string, custom map[string]string, apiKey string) map[string]string {
+	headers := make(map[string]string)
+	for key, value := range custom {
+		headers[key] = value
+	}
+	if apiKey != "" {
+		// Add API key to headers (provider-specific)
+		switch routeSolver {
+		case "0x":
+			headers["0x-api-key"] = apiKey
+			headers["0x-version"] = "v2"
+		case "1inch":
+			headers["Authorization"] = fmt.Sprintf("Bearer %s", apiKey)
+			headers["Content-Type"] = "application/json"
+		case "hyperbloom":
+			headers["api-key"] = apiKey
+		case "barter":
+			headers["Authorization"] = fmt.Sprintf("Bearer %s", apiKey)
+		}
+	}
+	return headers
+}
+
+// checkMarketPrice makes the market price (all sources) call, or takes the
+// quote from cache when another endpoint already fetched it.
+func (r *Registry) checkMarketPrice(endpoint *collector.Endpoint, providerConfig ProviderConfig, cache *MarketPriceCache, batch *SORBatch) {
+	if cache != nil && !r.isWIPCase(endpoint) {
+		if q, ok := cache.get(endpoint); ok {
+			q.apply(endpoint)
+			logs.Printf("%s[MARKET PRICE]%s %s: Market price reused from this cycle\n", config.ColorGreen, config.ColorReset, endpoint.Name)
+			return
+		}
+	}
+
+	// Add delay between calls to avoid rate limiting; the local dev fakes
+	// have no limit
+	if !config.GetDryRunEnabled() && !config.GetLocalDevEnabled() {
+		logs.Printf("%s[DELAY]%s %s: Waiting 2 seconds before market price check\n", config.ColorYellow, config.ColorReset, endpoint.Name)
+		clock.Sleep(2 * time.Second)
+	}
+
+	logs.Printf("%s[MARKET PRICE CHECK]%s %s: Checking all sources for market price\n", config.ColorCyan, config.ColorReset, endpoint.Name)
+	marketOptions := &CheckOptions{IsBalancerSourceOnly: &[]bool{false}[0], SORBatch: batch}
+	q := r.checkWithGenericClientForMarketPrice(endpoint, providerConfig, marketOptions)
+	if cache != nil {
+		cache.put(endpoint, q)
+	}
+}
+
+// checkWithGenericClientForMarketPrice checks a provider for market price (all
+// sources) and returns the quote it fetched, with an empty Price when the
+// call failed.
+func (r *Registry) checkWithGenericClientForMarketPrice(endpoint *collector.Endpoint, config ProviderConfig, checkOptions *CheckOptions) marketQuote {
+	// Check for WIP cases before making any requests
+	if r.isWIPCase(endpoint) {
+		// For WIP cases, don't make market price calls
+		return marketQuote{}
+	}
+
+	client := api.NewAPIClient()
+
+	// Validate API key if required
+	var apiKey string
+	var err error
+	if config.APIKeyEnvVar != "" {
+		apiKey, err = client.ValidateAPIKey(config.APIKeyEnvVar, endpoint)
+		if err != nil {
+			return marketQuote{} // Error already handled by ValidateAPIKey
+		}
+	}
+
+	headers := requestHeaders(endpoint.RouteSolver, config.CustomHeaders, apiKey)
+
+	// Use options if provided, otherwise default to false for market price
+	isBalancerSourceOnly := false // Default behavior for market price - use all sources
+	if checkOptions != nil && checkOptions.IsBalancerSourceOnly != nil {
+		isBalancerSourceOnly = *checkOptions.IsBalancerSourceOnly
+	}
+	// Configure request options
+	requestOptions := check.RequestOptions{
+		IsBalancerSourceOnly: isBalancerSourceOnly,
+		CustomHeaders:        headers,
+	}
+	if batch := checkOptions.sorBatch(); batch != nil {
+		requestOptions.Batch = batch
+	}
+	if config.FallbackURLs != nil {
+		requestOptions.FallbackURLs = config.FallbackURLs()
+	}
+
+	// Create a temporary endpoint copy for market price check to avoid overwriting the main endpoint data
+	tempEndpoint := *endpoint
+	marketQuote{}.apply(&tempEndpoint)
+	client.CheckAPIForMarketPrice(&tempEndpoint, config.Handler, config.URLBuilder, config.RequestBodyBuilder, config.UsePOST, requestOptions)
+
+	// Store the market price result in the original endpoint; a failed call
+	// keeps the previous one
+	q := quoteOf(&tempEndpoint)
+	if q.Price != "" {
+		q.apply(endpoint)
+	}
+	return q
+}
+
+// isWIPCase checks if the endpoint is a WIP case that should be handled
+// specially. When PoolType is set (discovered rows), the structured type is
+// the source of truth; otherwise we fall back to substring matching on the
+// endpoint name (BaseEndpoints rows encode the pool family in their name).
+func (r *Registry) isWIPCase(endpoint *collector.Endpoint) bool {
+	pt := strings.ToUpper(endpoint.PoolType)
+	switch endpoint.RouteSolver {
+	case "1inch":
+		if pt != "" {
+			return strings.Contains(pt, "GYRO") ||
+				strings.Contains(pt, "QUANT") ||
+				endpoint.Network == "43114"
+		}
+		return strings.Contains(endpoint.Name, "GyroE") ||
+			strings.Contains(endpoint.Name, "Quant") ||
+			endpoint.Network == "43114"
+	case "odos":
+		if pt != "" {
+			return strings.Contains(pt, "QUANT")
+		}
+		return strings.Contains(endpoint.Name, "Quant")
+	case "openocean":
+		if pt != "" {
+			return strings.Contains(pt, "QUANT")
+		}
+		return strings.Contains(endpoint.Name, "Quant")
+	default:
+		return false
+	}
+}
+
+// handleWIPCase handles WIP cases by setting appropriate status and message
+func (r *Registry) handleWIPCase(endpoint *collector.Endpoint) {
+	endpoint.LastChecked = clock.Now()
+
+	pt := strings.ToUpper(endpoint.PoolType)
+	hasGyro := (pt != "" && strings.Contains(pt, "GYRO")) || (pt == "" && strings.Contains(endpoint.Name, "GyroE"))
+	hasQuant := (pt != "" && strings.Contains(pt, "QUANT")) || (pt == "" && strings.Contains(endpoint.Name, "Quant"))
+
+	var message string
+	switch endpoint.RouteSolver {
+	case "1inch":
+		switch {
+		case hasGyro:
+			message = "1inch GyroE integration WIP"
+		case hasQuant:
+			message = "1inch QuantAMM integration WIP"
+		case endpoint.Network == "43114":
+			message = "1inch network support WIP"
+		}
+	case "odos":
+		message = "Odos QuantAMM integration WIP"
+	case "openocean":
+		message = "OpenOcean QuantAMM not supported"
+	}
+
+	endpoint.LastStatus = "info"
+	endpoint.Message = message
+	logs.Printf("%s[INFO]%s %s: API is %s%s%s\n", config.ColorYellow, config.ColorReset, endpoint.Name, config.ColorOrange, endpoint.LastStatus, config.ColorReset)
+}
+
+// NewDefaultRegistry returns a registry with every built-in provider
+// registered under its config.RouteSolvers type.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+
+	// Register providers using the new generic client
+	r.Register("0x", ProviderConfig{
+		Handler:        NewZeroXHandler(),
+		URLBuilder:     NewZeroXURLBuilder(),
+		APIKeyEnvVar:   "ZEROX_API_KEY",
+		SourceCatalog:  zeroXSourceCatalog,
+		ExclusionCheck: true,
+		Capabilities:   Capabilities{MarketPrice: true, SourceWhitelist: true, Route: true},
+	})
+
+	r.Register("paraswap", ProviderConfig{
+		Handler:    NewParaswapHandler(),
+		URLBuilder: NewParaswapURLBuilder(),
+		CustomHeaders: map[string]string{
+			"Content-Type": "application/json",
+		},
+		SourceCatalog:  paraswapSourceCatalog,
+		ExclusionCheck: true,
+		Capabilities:   Capabilities{MarketPrice: true, ExactOut: true, SourceWhitelist: true, Route: true},
+	})
+
+	r.Register("1inch", ProviderConfig{
+		Handler:      NewOneInchHandler(),
+		URLBuilder:   NewOneInchURLBuilder(),
+		APIKeyEnvVar: "INCH_API_KEY",
+		CustomHeaders: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Capabilities: Capabilities{MarketPrice: true, SourceWhitelist: true, Route: true},
+	})
+
+	r.Register("hyperbloom", ProviderConfig{
+		Handler:      NewHyperBloomHandler(),
+		URLBuilder:   NewHyperBloomURLBuilder(),
+		APIKeyEnvVar: "HYPERBLOOM_API_KEY",
+		Capabilities: Capabilities{MarketPrice: true, SourceWhitelist: true, Gas: true, Route: true},
+	})
+
+	r.Register("kyberswap", ProviderConfig{
+		Handler:    NewKyberSwapHandler(),
+		URLBuilder: NewKyberSwapURLBuilder(),
+		CustomHeaders: map[string]string{
+			"x-client-id": "BalancerTest",
+		},
+		SourceCatalog:  kyberSwapSourceCatalog,
+		ExclusionCheck: true,
+		Capabilities:   Capabilities{MarketPrice: true, SourceWhitelist: true, Gas: true, Route: true},
+	})
+
+	r.Register("odos", ProviderConfig{
+		Handler:            &OdosHandler{},
+		URLBuilder:         &OdosURLBuilder{},
+		RequestBodyBuilder: &OdosRequestBodyBuilder{},
+		UsePOST:            true,
+		CustomHeaders: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Capabilities: Capabilities{MarketPrice: true, SourceWhitelist: true},
+	})
+
+	r.Register("balancer_sor", ProviderConfig{
+		Handler:            NewBalancerSORHandler(),
+		URLBuilder:         NewBalancerSORURLBuilder(),
+		RequestBodyBuilder: NewBalancerSORRequestBodyBuilder(),
+		UsePOST:            true,
+		FallbackURLs:       config.GetBalancerAPIFallbackURLs,
+		CustomHeaders: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Capabilities: Capabilities{MarketPrice: true, ExactOut: true, SourceWhitelist: true, Route: true},
+	})
+
+	r.Register("barter", ProviderConfig{
+		Handler:            NewBarterHandler(),
+		URLBuilder:         NewBarterURLBuilder(),
+		RequestBodyBuilder: NewBarterRequestBodyBuilder(),
+		UsePOST:            true,
+		APIKeyEnvVar:       "BARTER_API_KEY",
+		CustomHeaders: map[string]string{
+			"Content-Type": "application/json",
+			"X-Request-Id": "123", // Default request ID, can be made dynamic if needed
+		},
+		Capabilities: Capabilities{MarketPrice: true, SourceWhitelist: true, Gas: true, Route: true},
+	})
+
+	r.Register("openocean", ProviderConfig{
+		Handler:       NewOpenOceanHandler(),
+		URLBuilder:    NewOpenOceanURLBuilder(),
+		SourceCatalog: openOceanSourceCatalog,
+		Capabilities:  Capabilities{MarketPrice: true, SourceWhitelist: true, Gas: true, Route: true},
+	})
+	return r
+}
@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestDefaultRegistryCoversRouteSolvers(t *testing.T) {
+	r := NewDefaultRegistry()
+	for _, s := range config.RouteSolvers {
+		p, ok := r.Lookup(s.Type)
+		if !ok {
+			t.Errorf("route solver %q is not registered", s.Type)
+			continue
+		}
+		if p.Handler == nil || p.URLBuilder == nil {
+			t.Errorf("route solver %q: missing handler or URL builder", s.Type)
+		}
+		if p.UsePOST && p.RequestBodyBuilder == nil {
+			t.Errorf("route solver %q: POST without a request body builder", s.Type)
+		}
+	}
+}
+
+func TestRegistryCheckUnsupportedSolver(t *testing.T) {
+	e := collector.Endpoint{Name: "test", RouteSolver: "nope"}
+	res := NewRegistry().Check(&e, nil)
+	if e.LastStatus != "unsupported" || e.LastChecked.IsZero() {
+		t.Fatalf("got status %q, checked %v", e.LastStatus, e.LastChecked)
+	}
+	if res.Status != "unsupported" || res.Endpoint != "test" || res.Failed() {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestRegistryCheckSkipsUnsupportedMarketPrice(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"error":"no route"}`))
+	}))
+	defer srv.Close()
+
+	r := NewRegistry()
+	r.Register("paraswap", ProviderConfig{
+		Handler:      NewParaswapHandler(),
+		URLBuilder:   redirectURLBuilder{NewParaswapURLBuilder(), srv.URL},
+		Capabilities: Capabilities{SourceWhitelist: true, Route: true},
+	})
+	e := collector.Endpoint{Name: "Paraswap-Stable", RouteSolver: "paraswap", Network: "1", ExpectedPool: "0xpool"}
+	res := r.Check(&e, nil)
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("made %d requests, want only the Balancer-only one", n)
+	}
+	if res.Status != e.LastStatus || res.HTTPStatus != http.StatusOK || string(res.Body) != `{"error":"no route"}` {
+		t.Fatalf("result = %+v, want the Balancer-only response", res)
+	}
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKyberSwapRouteGraph(t *testing.T) {
+	var r KyberSwapResponse
+	body := `{"data":{"routeSummary":{"route":[
+		[{"pool":"0xp1","tokenIn":"0xa","tokenOut":"0xb","exchange":"balancer-v3-stable"},{"pool":"0xp2","tokenIn":"0xb","tokenOut":"0xc","exchange":"uniswap-v3"}],
+		[{"pool":"0xp3","tokenIn":"0xa","tokenOut":"0xc","exchange":"curve"}]]}}}`
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		t.Fatal(err)
+	}
+	want := "0xa → 0xp1(balancer-v3-stable) → 0xb → 0xp2(uniswap-v3) → 0xc\n0xa → 0xp3(curve) → 0xc"
+	if got := r.routeGraph().String(); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBalancerSORRouteGraphMarksBuffers(t *testing.T) {
+	var r BalancerSORResponse
+	body := `{"data":{"sorGetSwapPaths":{"paths":[{"pools":["0xbuf","0xpool"],"isBuffer":[true,false],
+		"tokens":[{"address":"0xusdc"},{"address":"0xwusdc"},{"address":"0xwgho"}]}]}}}`
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		t.Fatal(err)
+	}
+	g := r.routeGraph()
+	want := "0xusdc → 0xbuf(buffer) → 0xwusdc → 0xpool → 0xwgho"
+	if got := g.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := g.Buffers(); len(got) != 2 || !got[0] || got[1] {
+		t.Fatalf("Buffers = %v", got)
+	}
+	// The SOR names no sources, so source rules don't apply to it.
+	if got := g.Sources(); got != nil {
+		t.Fatalf("Sources = %v", got)
+	}
+}
+
+func TestParaswapRouteGraphSplitsPools(t *testing.T) {
+	var r ParaswapResponse
+	body := `{"priceRoute":{"bestRoute":[{"percent":100,"swaps":[{"srcToken":"0xa","destToken":"0xb",
+		"swapExchanges":[{"exchange":"BalancerV3","percent":80,"poolAddresses":["0xp1"]},{"exchange":"UniswapV3","percent":20}]}]}]}}`
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		t.Fatal(err)
+	}
+	want := "[100%] 0xa → 0xp1(BalancerV3) 80% + (UniswapV3) 20% → 0xb"
+	if got := r.routeGraph().String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestZeroXRouteGraphFollowsTokens(t *testing.T) {
+	var r ZeroXResponse
+	body := `{"route":{"tokens":[{"address":"0xa"},{"address":"0xb"},{"address":"0xc"}],"fills":[
+		{"from":"0xa","to":"0xb","source":"Balancer_V3","proportionBps":"10000"},
+		{"from":"0xb","to":"0xc","source":"Balancer_V3","proportionBps":"7000"},
+		{"from":"0xb","to":"0xc","source":"Uniswap_V3","proportionBps":"3000"}]}}`
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		t.Fatal(err)
+	}
+	g := r.routeGraph()
+	want := "0xa → (Balancer_V3) → 0xb → (Balancer_V3) 70% + (Uniswap_V3) 30% → 0xc"
+	if got := g.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if g.HopCount() != 2 || len(g.Tokens()) != 3 {
+		t.Fatalf("HopCount = %d, Tokens = %v", g.HopCount(), g.Tokens())
+	}
+}
@@ -0,0 +1,142 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+// Router kinds.
+const (
+	// KindRouter exposes querySwapSingleTokenExactIn for single-pool paths.
+	KindRouter = "router"
+	// KindBatchRouter exposes querySwapExactIn for multi-step paths.
+	KindBatchRouter = "batchRouter"
+)
+
+// RouterDeployment is a Balancer V3 Router or BatchRouter on a network. When
+// Balancer deploys a new router version, add it with a higher Version and
+// keep the old entry for the migration window: both are queried, the newest
+// answer is recorded and a disagreement between them is logged. Remove the
+// old entry once providers have moved over.
+type RouterDeployment struct {
+	Network string `json:"network"`
+	Kind    string `json:"kind"`
+	Version int    `json:"version"`
+	Address string `json:"address"`
+}
+
+// DefaultRouters are the built-in deployments, version 1 throughout.
+var DefaultRouters = []RouterDeployment{
+	{Network: "1", Kind: KindRouter, Version: 1, Address: "0xAE563E3f8219521950555F5962419C8919758Ea2"},     // Mainnet
+	{Network: "42161", Kind: KindRouter, Version: 1, Address: "0xEAedc32a51c510d35ebC11088fD5fF2b47aACF2E"}, // Arbitrum
+	{Network: "10", Kind: KindRouter, Version: 1, Address: "0xe2fa4e1d17725e72dcdAfe943Ecf45dF4B9E285b"},    // Optimism
+	{Network: "8453", Kind: KindRouter, Version: 1, Address: "0x3f170631ed9821Ca51A59D996aB095162438DC10"},  // Base
+	{Network: "43114", Kind: KindRouter, Version: 1, Address: "0xF39CA6ede9BF7820a952b52f3c94af526bAB9015"}, // Avalanche
+	{Network: "100", Kind: KindRouter, Version: 1, Address: "0x4eff2d77D9fFbAeFB4b141A3e494c085b3FF4Cb5"},   // Gnosis
+	{Network: "999", Kind: KindRouter, Version: 1, Address: "0xA8920455934Da4D853faac1f94Fe7bEf72943eF1"},   // HyperEVM
+	{Network: "9745", Kind: KindRouter, Version: 1, Address: "0x9dA18982a33FD0c7051B19F0d7C76F2d5E7e017c"},  // Plasma
+
+	{Network: "1", Kind: KindBatchRouter, Version: 1, Address: "0x136f1EFcC3f8f88516B9E94110D56FDBfB1778d1"},     // Mainnet
+	{Network: "42161", Kind: KindBatchRouter, Version: 1, Address: "0xaD89051bEd8d96f045E8912aE1672c6C0bF8a85E"}, // Arbitrum
+	{Network: "10", Kind: KindBatchRouter, Version: 1, Address: "0xaD89051bEd8d96f045E8912aE1672c6C0bF8a85E"},    // Optimism
+	{Network: "8453", Kind: KindBatchRouter, Version: 1, Address: "0x85a80afee867aDf27B50BdB7b76DA70f1E853062"},  // Base
+	{Network: "43114", Kind: KindBatchRouter, Version: 1, Address: "0xc9b36096f5201ea332Db35d6D195774ea0D5988f"}, // Avalanche
+	{Network: "100", Kind: KindBatchRouter, Version: 1, Address: "0xe2fa4e1d17725e72dcdAfe943Ecf45dF4B9E285b"},   // Gnosis
+	{Network: "999", Kind: KindBatchRouter, Version: 1, Address: "0x9dd5Db2d38b50bEF682cE532bCca5DfD203915E1"},   // HyperEVM
+	{Network: "9745", Kind: KindBatchRouter, Version: 1, Address: "0x85a80afee867aDf27B50BdB7b76DA70f1E853062"},  // Plasma
+	{Network: "143", Kind: KindBatchRouter, Version: 1, Address: "0x85a80afee867aDf27B50BdB7b76DA70f1E853062"},   // Monad
+}
+
+var (
+	routersOnce sync.Once
+	routers     []RouterDeployment
+)
+
+// Routers returns the active deployments: entries from the JSON file named
+// by ROUTERS_FILE (if any), replacing a default with the same network, kind
+// and version, followed by the remaining DefaultRouters. Loaded once per
+// process.
+func Routers() []RouterDeployment {
+	routersOnce.Do(func() {
+		routers = DefaultRouters
+		path := config.GetRoutersFile()
+		if path == "" {
+			return
+		}
+		loaded, err := loadRouters(path)
+		if err != nil {
+			logs.Printf("%s[ERROR]%s: ROUTERS_FILE %s: %v; using built-in router addresses\n", config.ColorRed, config.ColorReset, path, err)
+			return
+		}
+		routers = mergeRouters(loaded, DefaultRouters)
+	})
+	return routers
+}
+
+// mergeRouters returns loaded followed by the defaults it doesn't replace.
+func mergeRouters(loaded, defaults []RouterDeployment) []RouterDeployment {
+	key := func(d RouterDeployment) string { return fmt.Sprintf("%s|%s|%d", d.Network, d.Kind, d.Version) }
+	replaced := map[string]bool{}
+	for _, d := range loaded {
+		replaced[key(d)] = true
+	}
+	out := append([]RouterDeployment(nil), loaded...)
+	for _, d := range defaults {
+		if !replaced[key(d)] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func loadRouters(path string) ([]RouterDeployment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []RouterDeployment
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	for i, d := range out {
+		if d.Network == "" || d.Version < 1 {
+			return nil, fmt.Errorf("entry %d: network and a version of at least 1 are required", i+1)
+		}
+		if d.Kind != KindRouter && d.Kind != KindBatchRouter {
+			return nil, fmt.Errorf("entry %d: kind must be %q or %q", i+1, KindRouter, KindBatchRouter)
+		}
+		if !common.IsHexAddress(d.Address) {
+			return nil, fmt.Errorf("entry %d: invalid address %q", i+1, d.Address)
+		}
+	}
+	return out, nil
+}
+
+// routersFor returns the deployments of kind on network, newest version
+// first.
+func routersFor(table []RouterDeployment, network, kind string) []RouterDeployment {
+	var out []RouterDeployment
+	for _, d := range table {
+		if d.Network == network && d.Kind == kind && d.Address != "" {
+			out = append(out, d)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Version > out[j].Version })
+	return out
+}
+
+// kindLabel names a router kind in messages.
+func kindLabel(kind string) string {
+	if kind == KindBatchRouter {
+		return "BatchRouter"
+	}
+	return "Router"
+}
@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultRoutersCoverEveryNetwork(t *testing.T) {
+	for _, network := range []string{"1", "42161", "10", "8453", "43114", "100", "999", "9745", "143"} {
+		if len(routersFor(DefaultRouters, network, KindBatchRouter)) != 1 {
+			t.Errorf("network %s: want one BatchRouter", network)
+		}
+	}
+	if got := routersFor(DefaultRouters, "143", KindRouter); len(got) != 0 {
+		t.Errorf("Monad has no Router, got %+v", got)
+	}
+}
+
+func TestRoutersForNewestFirst(t *testing.T) {
+	table := mergeRouters([]RouterDeployment{
+		{Network: "1", Kind: KindRouter, Version: 2, Address: "0x0000000000000000000000000000000000000002"},
+	}, DefaultRouters)
+	got := routersFor(table, "1", KindRouter)
+	if len(got) != 2 || got[0].Version != 2 || got[1].Version != 1 {
+		t.Fatalf("routersFor = %+v, want v2 then v1", got)
+	}
+}
+
+func TestMergeRoutersReplacesSameVersion(t *testing.T) {
+	moved := RouterDeployment{Network: "8453", Kind: KindRouter, Version: 1, Address: "0x0000000000000000000000000000000000000001"}
+	table := mergeRouters([]RouterDeployment{moved}, DefaultRouters)
+	got := routersFor(table, "8453", KindRouter)
+	if len(got) != 1 || got[0].Address != moved.Address {
+		t.Fatalf("routersFor = %+v, want only the file's entry", got)
+	}
+	if len(table) != len(DefaultRouters) {
+		t.Fatalf("merged %d entries, want %d", len(table), len(DefaultRouters))
+	}
+}
+
+func TestLoadRouters(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	os.WriteFile(good, []byte(`[{"network":"1","kind":"batchRouter","version":2,"address":"0x136f1EFcC3f8f88516B9E94110D56FDBfB1778d1"}]`), 0o644)
+	got, err := loadRouters(good)
+	if err != nil || len(got) != 1 || got[0].Kind != KindBatchRouter || got[0].Version != 2 {
+		t.Fatalf("loadRouters = %+v, %v", got, err)
+	}
+
+	for name, body := range map[string]string{
+		"kind":    `[{"network":"1","kind":"vault","version":1,"address":"0x136f1EFcC3f8f88516B9E94110D56FDBfB1778d1"}]`,
+		"version": `[{"network":"1","kind":"router","address":"0x136f1EFcC3f8f88516B9E94110D56FDBfB1778d1"}]`,
+		"address": `[{"network":"1","kind":"router","version":1,"address":"0x1234"}]`,
+	} {
+		path := filepath.Join(dir, name+".json")
+		os.WriteFile(path, []byte(body), 0o644)
+		if _, err := loadRouters(path); err == nil {
+			t.Errorf("want an error for a bad %s", name)
+		}
+	}
+}
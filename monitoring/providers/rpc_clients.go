@@ -0,0 +1,187 @@
+package providers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+)
+
+const (
+	// clientIdleTimeout is how long an RPC client may go unused before it is
+	// closed; a network only checked by discovery's test set would otherwise
+	// hold its connections between refreshes.
+	clientIdleTimeout = 30 * time.Minute
+	// clientHealthCheckAfter is how long a client may sit idle before it is
+	// pinged (eth_chainId) ahead of reuse. A failed ping reconnects.
+	clientHealthCheckAfter = 5 * time.Minute
+	// clientPingTimeout bounds the health check ping.
+	clientPingTimeout = 10 * time.Second
+)
+
+// rpcConn is a cached client and when it was last handed out.
+type rpcConn struct {
+	client   *ethclient.Client
+	lastUsed time.Time
+}
+
+var (
+	clients   = map[string]*rpcConn{}
+	clientsMu sync.Mutex
+)
+
+// getClient returns an ethclient for the given RPC URL, reusing the cached
+// client unless it has been idle past clientHealthCheckAfter and fails a
+// ping, in which case it is closed and replaced. Clients idle past
+// clientIdleTimeout are closed on the way.
+func getClient(rpcURL string) (*ethclient.Client, error) {
+	now := clock.Now()
+	clientsMu.Lock()
+	evictIdleLocked(now)
+	conn, exists := clients[rpcURL]
+	fresh := exists && now.Sub(conn.lastUsed) < clientHealthCheckAfter
+	if fresh {
+		conn.lastUsed = now
+	}
+	clientsMu.Unlock()
+
+	if fresh {
+		return conn.client, nil
+	}
+	if exists {
+		// Ping outside the lock so a slow node doesn't hold up other networks.
+		err := pingClient(conn.client)
+		if err == nil {
+			clientsMu.Lock()
+			conn.lastUsed = clock.Now()
+			clientsMu.Unlock()
+			return conn.client, nil
+		}
+		logs.Printf("[DEBUG]   RPC %s failed its health check, reconnecting: %v\n", rpcHost(rpcURL), err)
+		dropClient(rpcURL, conn.client)
+	}
+
+	client, err := dialClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	// Another check may have connected while this one dialed.
+	if conn, exists := clients[rpcURL]; exists {
+		client.Close()
+		conn.lastUsed = clock.Now()
+		return conn.client, nil
+	}
+	clients[rpcURL] = &rpcConn{client: client, lastUsed: clock.Now()}
+	return client, nil
+}
+
+// dialClient creates a client for rpcURL.
+func dialClient(rpcURL string) (*ethclient.Client, error) {
+	// Create HTTP client with proper TLS configuration for fly.io
+	// Explicitly load system certificate pool to ensure CA certificates are available
+	systemCertPool, err := x509.SystemCertPool()
+	if err != nil {
+		// If system cert pool fails, create a new empty pool
+		// This can happen in some container environments
+		systemCertPool = x509.NewCertPool()
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs: systemCertPool,
+			},
+		},
+		Timeout: 30 * time.Second,
+	}
+
+	// Create RPC client with custom HTTP client
+	rpcClient, err := rpc.DialHTTPWithClient(rpcURL, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
+	}
+
+	// Wrap RPC client with ethclient
+	return ethclient.NewClient(rpcClient), nil
+}
+
+// pingClient checks the node still answers.
+func pingClient(client *ethclient.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), clientPingTimeout)
+	defer cancel()
+	_, err := client.ChainID(ctx)
+	return err
+}
+
+// evictIdleLocked closes clients unused since before now - clientIdleTimeout.
+// The caller holds clientsMu.
+func evictIdleLocked(now time.Time) {
+	for url, conn := range clients {
+		if now.Sub(conn.lastUsed) >= clientIdleTimeout {
+			conn.client.Close()
+			delete(clients, url)
+		}
+	}
+}
+
+// dropClient closes client and removes it from the cache if it is still the
+// cached client for rpcURL, so the next getClient reconnects.
+func dropClient(rpcURL string, client *ethclient.Client) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if conn, ok := clients[rpcURL]; ok && conn.client == client {
+		delete(clients, rpcURL)
+	}
+	client.Close()
+}
+
+// callRPC runs callAtHead on the client for rpcURL. A connection failure
+// (the node unreachable or the connection cut, not an error the node
+// returned) drops the client and retries once on a fresh connection.
+func callRPC(rpcURL string, msg ethereum.CallMsg, after uint64) (pinnedCall, error) {
+	for attempt := 0; ; attempt++ {
+		client, err := getClient(rpcURL)
+		if err != nil {
+			return pinnedCall{}, err
+		}
+		call, err := callAtHead(client.Client(), msg, after)
+		if err == nil || attempt > 0 || !isConnectionError(err) {
+			return call, err
+		}
+		logs.Printf("[DEBUG]   RPC %s connection failed, reconnecting: %v\n", rpcHost(rpcURL), err)
+		dropClient(rpcURL, client)
+	}
+}
+
+// isConnectionError reports whether err is a transport failure rather than
+// a JSON-RPC error or revert from a live node.
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// CloseClients closes every cached RPC client. It is called on shutdown;
+// a later on-chain query reconnects.
+func CloseClients() {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	for url, conn := range clients {
+		conn.client.Close()
+		delete(clients, url)
+	}
+}
@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestEvictIdleClients(t *testing.T) {
+	t.Cleanup(CloseClients)
+	now := time.Now()
+	for url, lastUsed := range map[string]time.Time{
+		"http://idle":   now.Add(-clientIdleTimeout),
+		"http://active": now.Add(-time.Minute),
+	} {
+		client, err := dialClient(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients[url] = &rpcConn{client: client, lastUsed: lastUsed}
+	}
+
+	clientsMu.Lock()
+	evictIdleLocked(now)
+	clientsMu.Unlock()
+	if _, ok := clients["http://idle"]; ok {
+		t.Error("idle client was not evicted")
+	}
+	if _, ok := clients["http://active"]; !ok {
+		t.Error("active client was evicted")
+	}
+}
+
+func TestDropClientKeepsReplacement(t *testing.T) {
+	t.Cleanup(CloseClients)
+	stale, _ := dialClient("http://node")
+	current, _ := dialClient("http://node")
+	clients["http://node"] = &rpcConn{client: current, lastUsed: time.Now()}
+
+	// A check holding the old client must not evict one another check has
+	// already reconnected.
+	dropClient("http://node", stale)
+	if conn, ok := clients["http://node"]; !ok || conn.client != current {
+		t.Fatal("dropping a stale client removed its replacement")
+	}
+	dropClient("http://node", current)
+	if _, ok := clients["http://node"]; ok {
+		t.Fatal("current client was not dropped")
+	}
+}
+
+func TestCloseClients(t *testing.T) {
+	client, _ := dialClient("http://node")
+	clients["http://node"] = &rpcConn{client: client, lastUsed: time.Now()}
+	CloseClients()
+	if len(clients) != 0 {
+		t.Fatalf("%d clients left open", len(clients))
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("eth_call failed: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), true},
+		{fmt.Errorf("get latest block: %w", io.EOF), true},
+		{fmt.Errorf("eth_call failed: %w", errors.New("execution reverted")), false},
+		{errors.New("no block after 10 within 15s"), false},
+	}
+	for _, tt := range tests {
+		if got := isConnectionError(tt.err); got != tt.want {
+			t.Errorf("isConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
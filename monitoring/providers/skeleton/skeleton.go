@@ -0,0 +1,246 @@
+// Package skeleton scaffolds a new route solver (`go-monitoring new-provider`):
+// a handler with URL and request body builders in the providers package,
+// its unit tests, and the registrations a provider needs — NewDefaultRegistry,
+// config.RouteSolvers, sources.DefaultEntries and the conformance fixture.
+// The generated handler follows the common buyAmount / route response
+// layout; its TODO(new-provider) comments mark what to adapt.
+package skeleton
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// Options describes the provider to scaffold.
+type Options struct {
+	// Type is the route solver type, e.g. "acme": lowercase letters, digits
+	// and underscores, starting with a letter.
+	Type string
+	// Display is the name on the dashboard, e.g. "Acme"; Go identifiers are
+	// derived from it, so it must start with a letter. Defaults to Type
+	// capitalized.
+	Display string
+	// BaseURL is the quote endpoint.
+	BaseURL string
+	// APIKeyEnvVar, when set, is the env var holding the provider's API key.
+	APIKeyEnvVar string
+	// POST sends the quote request as a JSON body instead of query
+	// parameters.
+	POST bool
+	// Networks are the chain IDs the provider serves.
+	Networks []string
+	// SourceID is the provider's name for Balancer V3 liquidity. Defaults to
+	// "BalancerV3".
+	SourceID string
+}
+
+var validType = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// data is what the templates and snippets are rendered with.
+type data struct {
+	Options
+	Ident         string // exported Go identifier prefix, e.g. "Acme"
+	Var           string // unexported one, e.g. "acme"
+	Network       string // first of Networks, for the tests' row
+	SourceIDQuery string
+}
+
+func (o Options) data() (data, error) {
+	if !validType.MatchString(o.Type) {
+		return data{}, fmt.Errorf("provider type %q: use lowercase letters, digits and underscores, starting with a letter", o.Type)
+	}
+	if o.Display == "" {
+		o.Display = strings.ToUpper(o.Type[:1]) + o.Type[1:]
+	}
+	if o.SourceID == "" {
+		o.SourceID = "BalancerV3"
+	}
+	if u, err := url.Parse(o.BaseURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return data{}, fmt.Errorf("base URL %q: want an https:// URL", o.BaseURL)
+	}
+	if len(o.Networks) == 0 {
+		return data{}, fmt.Errorf("no networks: give the chain IDs the provider serves")
+	}
+	var ident strings.Builder
+	for _, word := range strings.FieldsFunc(o.Display, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		ident.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	d := data{Options: o, Ident: ident.String(), Network: o.Networks[0], SourceIDQuery: url.QueryEscape(o.SourceID)}
+	if d.Ident == "" || !unicode.IsLetter(rune(d.Ident[0])) || d.Ident[0] > unicode.MaxASCII {
+		return data{}, fmt.Errorf("display name %q: must start with an ASCII letter (Go identifiers are derived from it)", o.Display)
+	}
+	d.Var = strings.ToLower(d.Ident[:1]) + d.Ident[1:]
+	return d, nil
+}
+
+// Files returns the new files, by path relative to the repository root: the
+// handler and its tests.
+func Files(o Options) (map[string][]byte, error) {
+	d, err := o.data()
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Join("monitoring", "providers", o.Type+"_handler")
+	out := map[string][]byte{}
+	for name, path := range map[string]string{"handler.go.tmpl": base + ".go", "handler_test.go.tmpl": base + "_test.go"} {
+		src, err := render(name, d)
+		if err != nil {
+			return nil, err
+		}
+		out[path] = src
+	}
+	return out, nil
+}
+
+func render(name string, d data) ([]byte, error) {
+	t, err := template.ParseFS(templates, "templates/"+name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, d); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return src, nil
+}
+
+// edit inserts Snippet into an existing file, before the end of the
+// declaration starting with Opening: the first Closing after it, "\n}" (a
+// top-level closing brace) when unset.
+type edit struct {
+	Path    string
+	Opening string
+	Closing string
+	Exists  string // present when the provider is already there
+	Snippet string
+}
+
+func (o Options) edits(d data) []edit {
+	var reg strings.Builder
+	fmt.Fprintf(&reg, "\n\tr.Register(%q, ProviderConfig{\n\t\tHandler: New%sHandler(),\n\t\tURLBuilder: New%sURLBuilder(),\n", o.Type, d.Ident, d.Ident)
+	if o.POST {
+		fmt.Fprintf(&reg, "\t\tRequestBodyBuilder: New%sRequestBodyBuilder(),\n\t\tUsePOST: true,\n", d.Ident)
+		reg.WriteString("\t\tCustomHeaders: map[string]string{\n\t\t\t\"Content-Type\": \"application/json\",\n\t\t},\n")
+	}
+	if o.APIKeyEnvVar != "" {
+		fmt.Fprintf(&reg, "\t\tAPIKeyEnvVar: %q,\n", o.APIKeyEnvVar)
+	}
+	reg.WriteString("\t\t// TODO(new-provider): add ExactOut and Gas if the API offers them.\n")
+	reg.WriteString("\t\tCapabilities: Capabilities{MarketPrice: true, SourceWhitelist: true, Route: true},\n")
+	reg.WriteString("\t})")
+
+	networks := make([]string, len(d.Networks))
+	for i, n := range d.Networks {
+		networks[i] = fmt.Sprintf("%q", n)
+	}
+	route := fmt.Sprintf(`[{"source":%q,"pool":"0xpool","tokenIn":"0xin","tokenOut":"0xout"}]`, d.SourceID)
+	return []edit{
+		{
+			Path:    filepath.Join("monitoring", "providers", "registry.go"),
+			Opening: "func NewDefaultRegistry() *Registry {",
+			Closing: "\n\treturn r\n}",
+			Exists:  fmt.Sprintf("r.Register(%q,", o.Type),
+			Snippet: reg.String(),
+		},
+		{
+			Path:    filepath.Join("config", "config.go"),
+			Opening: "var RouteSolvers = []RouteSolver{",
+			Exists:  fmt.Sprintf("Type: %q,", o.Type),
+			Snippet: fmt.Sprintf("\n\t{\n\t\tName: %q,\n\t\tType: %q,\n\t\tSupportedNetworks: []string{%s},\n\t},", d.Display, o.Type, strings.Join(networks, ", ")),
+		},
+		{
+			Path:    filepath.Join("monitoring", "sources", "sources.go"),
+			Opening: "var DefaultEntries = []Entry{",
+			Exists:  fmt.Sprintf("{Solver: %q,", o.Type),
+			Snippet: fmt.Sprintf("\n\t{Solver: %q, IDs: []string{%q}},", o.Type, d.SourceID),
+		},
+		{
+			Path:    filepath.Join("monitoring", "providers", "conformance_test.go"),
+			Opening: "var conformanceCases = map[string]ConformanceCase{",
+			Exists:  fmt.Sprintf("\t%q: {", o.Type),
+			Snippet: fmt.Sprintf("\n\t%q: {\n\t\tSuccess: `{\"buyAmount\":\"1000\",\"route\":%s}`,\n\t\tReturnAmount: \"1000\",\n\t\tWithoutAmount: `{\"route\":%s}`,\n\t},", o.Type, route, route),
+		},
+	}
+}
+
+// apply returns src with e's snippet inserted, gofmt'ed.
+func (e edit) apply(src []byte) ([]byte, error) {
+	s := string(src)
+	if strings.Contains(s, e.Exists) {
+		return nil, fmt.Errorf("%s: already has %s", e.Path, strings.TrimSpace(e.Exists))
+	}
+	open := strings.Index(s, e.Opening)
+	if open < 0 {
+		return nil, fmt.Errorf("%s: %q not found", e.Path, e.Opening)
+	}
+	closing := e.Closing
+	if closing == "" {
+		closing = "\n}"
+	}
+	end := strings.Index(s[open:], closing)
+	if end < 0 {
+		return nil, fmt.Errorf("%s: end of %q not found", e.Path, e.Opening)
+	}
+	at := open + end
+	out, err := format.Source([]byte(s[:at] + e.Snippet + s[at:]))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", e.Path, err)
+	}
+	return out, nil
+}
+
+// Write scaffolds the provider in the repository at root: it creates the
+// files from Files, refusing to overwrite any, and inserts the
+// registrations. It returns the paths written, relative to root. Nothing is
+// written when the options or an edit are invalid.
+func Write(root string, o Options) ([]string, error) {
+	d, err := o.data()
+	if err != nil {
+		return nil, err
+	}
+	files, err := Files(o)
+	if err != nil {
+		return nil, err
+	}
+	for path := range files {
+		if _, err := os.Stat(filepath.Join(root, path)); err == nil {
+			return nil, fmt.Errorf("%s already exists", path)
+		}
+	}
+	for _, e := range o.edits(d) {
+		src, err := os.ReadFile(filepath.Join(root, e.Path))
+		if err != nil {
+			return nil, err
+		}
+		if files[e.Path], err = e.apply(src); err != nil {
+			return nil, err
+		}
+	}
+
+	var written []string
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		if err := os.WriteFile(filepath.Join(root, path), files[path], 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
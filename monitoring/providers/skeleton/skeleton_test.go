@@ -0,0 +1,101 @@
+package skeleton
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// repoCopy copies the files Write edits into a temporary repository root.
+func repoCopy(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, e := range (Options{Type: "x"}).edits(data{}) {
+		src, err := os.ReadFile(filepath.Join("..", "..", "..", e.Path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(e.Path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, e.Path), src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestWriteScaffoldsProvider(t *testing.T) {
+	for _, post := range []bool{false, true} {
+		root := repoCopy(t)
+		o := Options{Type: "acme_dex", Display: "Acme DEX", BaseURL: "https://api.acme.example/quote", APIKeyEnvVar: "ACME_API_KEY",
+			POST: post, Networks: []string{"1", "8453"}}
+		written, err := Write(root, o)
+		if err != nil {
+			t.Fatalf("post=%v: %v", post, err)
+		}
+		if len(written) != 6 {
+			t.Fatalf("post=%v: wrote %v, want the handler, its test and 4 registrations", post, written)
+		}
+		for _, path := range written {
+			src, err := os.ReadFile(filepath.Join(root, path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), path, src, 0); err != nil {
+				t.Errorf("post=%v: %s does not parse: %v", post, path, err)
+			}
+		}
+		read := func(path string) string {
+			b, _ := os.ReadFile(filepath.Join(root, path))
+			return string(b)
+		}
+		handler := read("monitoring/providers/acme_dex_handler.go")
+		if !strings.Contains(handler, "type AcmeDEXHandler struct{}") || strings.Contains(handler, "RequestBodyBuilder struct{}") != post {
+			t.Errorf("post=%v: handler:\n%s", post, handler)
+		}
+		reg := read("monitoring/providers/registry.go")
+		i := strings.Index(reg, `r.Register("acme_dex", ProviderConfig{`)
+		if i < 0 {
+			t.Fatalf("post=%v: registration missing", post)
+		}
+		block := reg[i : i+strings.Index(reg[i:], "})")]
+		if !strings.Contains(block, `"ACME_API_KEY"`) || strings.Contains(block, "UsePOST") != post {
+			t.Errorf("post=%v: registration:\n%s", post, block)
+		}
+		if cfg := read("config/config.go"); !strings.Contains(cfg, `Type:              "acme_dex",`) || !strings.Contains(cfg, `[]string{"1", "8453"}`) {
+			t.Error("route solver not added to config.RouteSolvers")
+		}
+		if !strings.Contains(read("monitoring/sources/sources.go"), `{Solver: "acme_dex", IDs: []string{"BalancerV3"}},`) {
+			t.Error("source entry not added")
+		}
+		if !strings.Contains(read("monitoring/providers/conformance_test.go"), `"acme_dex": {`) {
+			t.Error("conformance fixture not added")
+		}
+
+		if _, err := Write(root, o); err == nil {
+			t.Errorf("post=%v: scaffolding the same provider twice succeeded", post)
+		}
+	}
+}
+
+func TestOptionsValidation(t *testing.T) {
+	ok := Options{Type: "acme", BaseURL: "https://api.acme.example", Networks: []string{"1"}}
+	for name, o := range map[string]Options{
+		"type with capitals":     {Type: "Acme", BaseURL: ok.BaseURL, Networks: ok.Networks},
+		"plain http":             {Type: "acme", BaseURL: "http://api.acme.example", Networks: ok.Networks},
+		"no networks":            {Type: "acme", BaseURL: ok.BaseURL},
+		"display without letter": {Type: "acme", Display: "1acme", BaseURL: ok.BaseURL, Networks: ok.Networks},
+	} {
+		if _, err := Files(o); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+	d, err := ok.data()
+	if err != nil || d.Display != "Acme" || d.Ident != "Acme" || d.Var != "acme" {
+		t.Fatalf("defaults = %+v, %v", d, err)
+	}
+}
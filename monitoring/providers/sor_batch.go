@@ -0,0 +1,284 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/api"
+	"go-monitoring/internal/clock"
+	"go-monitoring/internal/logs"
+	"go-monitoring/internal/shared"
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+// sorBatchMaxAge is how long a batched response answers its request. A batch
+// runs ahead of the checks it serves; an older quote would be compared with a
+// later on-chain read.
+const sorBatchMaxAge = time.Minute
+
+// sorQuery is a request a cycle expects to POST to the Balancer SOR: its
+// body and the sorGetSwapPaths field of its query.
+type sorQuery struct {
+	body  string
+	field string
+}
+
+// sorBatched is a batched response, split out for one query.
+type sorBatched struct {
+	response *check.APIResponse
+	at       time.Time
+}
+
+// SORBatch answers a cycle's Balancer SOR requests from batched GraphQL
+// requests: the first request of a row fetches its own query together with
+// the queries of the rows checked next, each an aliased sorGetSwapPaths
+// field, up to size per request, failing over like a single request.
+// Requests it doesn't expect, and every request after a batch fails, are
+// sent alone.
+type SORBatch struct {
+	url       string
+	fallbacks []string
+	size      int
+	client    *api.APIClient
+
+	mu      sync.Mutex
+	pending []sorQuery // not yet answered, in check order
+	fetched map[string]sorBatched
+	failed  bool
+	served  int
+}
+
+// NewSORBatch returns a batch for the balancer_sor rows of endpoints, in
+// their order: each row's Balancer-only query, then its market price query.
+// Nil when size is below 2 or there is nothing to batch.
+func NewSORBatch(endpoints []collector.Endpoint, size int) *SORBatch {
+	if size < 2 {
+		return nil
+	}
+	builder := NewBalancerSORRequestBodyBuilder()
+	seen := map[string]bool{}
+	var pending []sorQuery
+	for i := range endpoints {
+		e := &endpoints[i]
+		if e.RouteSolver != "balancer_sor" {
+			continue
+		}
+		for _, balancerOnly := range []bool{true, false} {
+			body, err := builder.BuildRequestBody(e, check.RequestOptions{IsBalancerSourceOnly: balancerOnly})
+			if err != nil || seen[string(body)] {
+				continue
+			}
+			field, ok := sorQueryField(body)
+			if !ok {
+				continue
+			}
+			seen[string(body)] = true
+			pending = append(pending, sorQuery{body: string(body), field: field})
+		}
+	}
+	if len(pending) < 2 {
+		return nil
+	}
+	return &SORBatch{
+		url:       config.GetBalancerAPIURL(),
+		fallbacks: config.GetBalancerAPIFallbackURLs(),
+		size:      size,
+		client:    api.NewAPIClient(),
+		pending:   pending,
+		fetched:   map[string]sorBatched{},
+	}
+}
+
+// Response implements check.Batcher. A query fetched less than sorBatchMaxAge
+// ago is answered from its batch; otherwise it is fetched with the next
+// queries not already fetched.
+func (s *SORBatch) Response(url string, body []byte) (*check.APIResponse, bool) {
+	if s == nil || url != s.url {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := string(body)
+	i := slices.IndexFunc(s.pending, func(q sorQuery) bool { return q.body == key })
+	if i < 0 {
+		return nil, false
+	}
+	now := clock.Now()
+	if r, ok := s.fetched[key]; !ok || now.Sub(r.at) >= sorBatchMaxAge {
+		if s.failed {
+			return nil, false
+		}
+		chunk := []sorQuery{s.pending[i]}
+		for _, q := range s.pending[i+1:] {
+			if len(chunk) == s.size {
+				break
+			}
+			if r, ok := s.fetched[q.body]; !ok || now.Sub(r.at) >= sorBatchMaxAge {
+				chunk = append(chunk, q)
+			}
+		}
+		responses, err := s.fetch(chunk)
+		if err != nil {
+			s.failed = true
+			logs.Printf("%s[SOR BATCH]%s batched request failed, sending the cycle's remaining queries alone: %v\n",
+				config.ColorYellow, config.ColorReset, err)
+			return nil, false
+		}
+		for q, r := range responses {
+			s.fetched[q] = sorBatched{response: r, at: now}
+		}
+	}
+	r, ok := s.fetched[key]
+	if !ok {
+		return nil, false
+	}
+	delete(s.fetched, key)
+	s.pending = slices.Delete(s.pending, i, i+1)
+	s.served++
+	return r.response, true
+}
+
+// Served returns how many requests the batch has answered.
+func (s *SORBatch) Served() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.served
+}
+
+// fetch sends queries as one request and returns each answered query's
+// response, keyed by its body. A query the response has no field or error
+// for is left out.
+func (s *SORBatch) fetch(queries []sorQuery) (map[string]*check.APIResponse, error) {
+	shared.Wait("provider:balancer_sor", config.GetRouteSolverRateLimit("balancer_sor"))
+
+	fields := make([]string, len(queries))
+	for i, q := range queries {
+		fields[i] = q.field
+	}
+	req, err := http.NewRequest("POST", s.url, bytes.NewReader(batchSORQuery(fields)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", config.GetUserAgent())
+	sent := clock.Now()
+	resp, err := s.client.Send(req, "balancer_sor", s.fallbacks)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+	took := clock.Since(sent)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	bodies, err := splitSORBatch(len(queries), body)
+	if err != nil {
+		return nil, err
+	}
+	logs.Printf("%s[SOR BATCH]%s fetched %d queries in one request (%s)\n", config.ColorBlue, config.ColorReset, len(queries), took)
+	out := make(map[string]*check.APIResponse, len(bodies))
+	for i, b := range bodies {
+		out[queries[i].body] = &check.APIResponse{StatusCode: resp.StatusCode, Body: b, Headers: resp.Header, Duration: took}
+	}
+	return out, nil
+}
+
+// sorQueryField returns the sorGetSwapPaths field of a request body built
+// by BalancerSORRequestBodyBuilder: its query without the enclosing braces.
+func sorQueryField(body []byte) (string, bool) {
+	var request struct {
+		Query string `json:"query"`
+	}
+	if json.Unmarshal(body, &request) != nil {
+		return "", false
+	}
+	q := strings.TrimSpace(request.Query)
+	if !strings.HasPrefix(q, "{") || !strings.HasSuffix(q, "}") {
+		return "", false
+	}
+	field := strings.TrimSpace(q[1 : len(q)-1])
+	if !strings.HasPrefix(field, "sorGetSwapPaths(") {
+		return "", false
+	}
+	return field, true
+}
+
+// sorAlias names the i-th field of a batched query.
+func sorAlias(i int) string {
+	return fmt.Sprintf("q%d", i)
+}
+
+// batchSORQuery returns the request body querying fields under the aliases
+// q0, q1, ...
+func batchSORQuery(fields []string) []byte {
+	var q strings.Builder
+	q.WriteString("{\n")
+	for i, f := range fields {
+		fmt.Fprintf(&q, "%s: %s\n", sorAlias(i), f)
+	}
+	q.WriteString("}")
+	body, _ := json.Marshal(map[string]string{"query": q.String()})
+	return body
+}
+
+// splitSORBatch splits the response to a batch of n queries into the
+// response each would have had alone, by index: its sorGetSwapPaths data, or
+// the GraphQL error raised at its alias. An error not at an alias fails the
+// whole batch.
+func splitSORBatch(n int, body []byte) (map[int][]byte, error) {
+	var batch struct {
+		Data   map[string]json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+			Path    []any  `json:"path"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	errs := map[string]string{}
+	for _, e := range batch.Errors {
+		var alias string
+		if len(e.Path) > 0 {
+			alias, _ = e.Path[0].(string)
+		}
+		if alias == "" {
+			return nil, fmt.Errorf("GraphQL error: %s", e.Message)
+		}
+		if _, ok := errs[alias]; !ok {
+			errs[alias] = e.Message
+		}
+	}
+	out := map[int][]byte{}
+	for i := range n {
+		alias := sorAlias(i)
+		if msg, ok := errs[alias]; ok {
+			b, _ := json.Marshal(map[string]any{
+				"data":   map[string]any{"sorGetSwapPaths": nil},
+				"errors": []map[string]string{{"message": msg}},
+			})
+			out[i] = b
+			continue
+		}
+		if data, ok := batch.Data[alias]; ok && string(data) != "null" {
+			out[i] = slices.Concat([]byte(`{"data":{"sorGetSwapPaths":`), data, []byte(`}}`))
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,113 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/check"
+	"go-monitoring/monitoring/collector"
+)
+
+func TestSORBatchAnswersUpcomingQueries(t *testing.T) {
+	aliases := regexp.MustCompile(`(q\d+): sorGetSwapPaths`)
+	var requests []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		found := aliases.FindAllStringSubmatch(body.Query, -1)
+		requests = append(requests, len(found))
+		var data []string
+		for i, m := range found {
+			data = append(data, fmt.Sprintf(`%q:{"swapAmount":"1","returnAmount":"%d","paths":[]}`, m[1], len(requests)*100+i))
+		}
+		fmt.Fprintf(w, `{"data":{%s}}`, strings.Join(data, ","))
+	}))
+	defer srv.Close()
+
+	var endpoints []collector.Endpoint
+	for i := range 3 {
+		endpoints = append(endpoints, collector.Endpoint{RouteSolver: "balancer_sor", Network: "1", TokenIn: "0xa", TokenOut: "0xb",
+			TokenInDecimals: 18, SwapAmount: "1000", ExpectedPool: fmt.Sprintf("0xpool%d", i)})
+	}
+	endpoints = append(endpoints, collector.Endpoint{RouteSolver: "odos", Network: "1", SwapAmount: "1"})
+	b := NewSORBatch(endpoints, 3)
+	if b == nil {
+		t.Fatal("no batch")
+	}
+	// Three Balancer-only queries and one market price query, shared by
+	// the rows quoting the same pair and amount.
+	if len(b.pending) != 4 {
+		t.Fatalf("pending = %d queries", len(b.pending))
+	}
+	b.url = srv.URL
+
+	builder := NewBalancerSORRequestBodyBuilder()
+	bodyOf := func(e collector.Endpoint, balancerOnly bool) []byte {
+		body, err := builder.BuildRequestBody(&e, check.RequestOptions{IsBalancerSourceOnly: balancerOnly})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+	for _, tt := range []struct {
+		body []byte
+		want string
+	}{
+		{bodyOf(endpoints[0], true), `"returnAmount":"100"`},
+		{bodyOf(endpoints[0], false), `"returnAmount":"101"`},
+		{bodyOf(endpoints[1], true), `"returnAmount":"102"`},
+		{bodyOf(endpoints[2], true), `"returnAmount":"200"`},
+	} {
+		r, ok := b.Response(srv.URL, tt.body)
+		if !ok || !strings.Contains(string(r.Body), tt.want) || !strings.HasPrefix(string(r.Body), `{"data":{"sorGetSwapPaths":`) {
+			t.Fatalf("response = %v, %v; want %s", r, ok, tt.want)
+		}
+	}
+	if len(requests) != 2 || requests[0] != 3 || requests[1] != 1 {
+		t.Errorf("batched requests = %v, want [3 1]", requests)
+	}
+	if _, ok := b.Response(srv.URL, bodyOf(endpoints[0], true)); ok {
+		t.Error("an answered query was answered again")
+	}
+	if _, ok := b.Response("https://example.com/", bodyOf(endpoints[1], false)); ok {
+		t.Error("another URL was answered")
+	}
+	if b.Served() != 4 {
+		t.Errorf("served = %d", b.Served())
+	}
+	if NewSORBatch(endpoints, 1) != nil {
+		t.Error("size 1 batches")
+	}
+}
+
+func TestSplitSORBatch(t *testing.T) {
+	got, err := splitSORBatch(3, []byte(`{"data":{"q0":{"returnAmount":"1"},"q1":null,"q2":null},
+		"errors":[{"message":"no route","path":["q1"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got[0]) != `{"data":{"sorGetSwapPaths":{"returnAmount":"1"}}}` {
+		t.Errorf("q0 = %s", got[0])
+	}
+	var q1 BalancerSORResponse
+	if err := json.Unmarshal(got[1], &q1); err != nil || len(q1.Errors) != 1 || q1.Errors[0].Message != "no route" {
+		t.Errorf("q1 = %s", got[1])
+	}
+	if _, ok := got[2]; ok {
+		t.Errorf("q2 without data or error = %s", got[2])
+	}
+
+	if _, err := splitSORBatch(2, []byte(`{"errors":[{"message":"query too complex"}]}`)); err == nil {
+		t.Error("an error not at an alias didn't fail the batch")
+	}
+	if _, err := splitSORBatch(1, []byte(`{"data":{"q0":`)); err == nil {
+		t.Error("malformed batch split")
+	}
+}
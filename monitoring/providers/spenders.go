@@ -0,0 +1,93 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+)
+
+// KnownSpender is an allowance target a route solver's quotes may name: the
+// contract a user approves before executing the quote. Network "" matches
+// every network, for contracts deployed at the same address everywhere.
+type KnownSpender struct {
+	Solver  string `json:"solver"`
+	Network string `json:"network,omitempty"`
+	Address string `json:"address"`
+}
+
+// DefaultSpenders are the built-in known-good spenders. A solver without
+// entries is not checked.
+var DefaultSpenders = []KnownSpender{
+	{Solver: "0x", Address: "0x000000000022D473030F116dDEE9F6B43aC78BA3"},        // Permit2
+	{Solver: "kyberswap", Address: "0x6131B5fae19EA4f9D964eAc0408E4408b66337b5"}, // MetaAggregationRouterV2
+	{Solver: "paraswap", Address: "0x6A000F20005980200259B80c5102003040001068"},  // Augustus v6.2
+}
+
+var (
+	spendersOnce sync.Once
+	spenders     []KnownSpender
+)
+
+// Spenders returns the active registry: entries from the JSON file named by
+// SPENDERS_FILE (if any) followed by DefaultSpenders. Loaded once per
+// process.
+func Spenders() []KnownSpender {
+	spendersOnce.Do(func() {
+		spenders = DefaultSpenders
+		path := config.GetSpendersFile()
+		if path == "" {
+			return
+		}
+		loaded, err := loadSpenders(path)
+		if err != nil {
+			logs.Printf("%s[ERROR]%s: SPENDERS_FILE %s: %v; using built-in spenders\n", config.ColorRed, config.ColorReset, path, err)
+			return
+		}
+		spenders = append(loaded, DefaultSpenders...)
+	})
+	return spenders
+}
+
+func loadSpenders(path string) ([]KnownSpender, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []KnownSpender
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	for i, s := range out {
+		if s.Solver == "" {
+			return nil, fmt.Errorf("entry %d: solver is required", i+1)
+		}
+		if !common.IsHexAddress(s.Address) {
+			return nil, fmt.Errorf("entry %d: invalid address %q", i+1, s.Address)
+		}
+	}
+	return out, nil
+}
+
+// CheckSpender reports whether address is a known spender for solver on
+// network. checked is false when the registry has no entries for the solver
+// on that network, so an unknown address can't be told from an unlisted
+// provider.
+func CheckSpender(table []KnownSpender, solver, network, address string) (known, checked bool) {
+	for _, s := range table {
+		if !strings.EqualFold(s.Solver, solver) || (s.Network != "" && s.Network != network) {
+			continue
+		}
+		checked = true
+		if config.SameAddress(s.Address, address) {
+			return true, true
+		}
+	}
+	return false, checked
+}
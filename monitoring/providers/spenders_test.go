@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckSpender(t *testing.T) {
+	table := append([]KnownSpender{
+		{Solver: "hyperbloom", Network: "999", Address: "0x00000000000000000000000000000000000000bb"},
+	}, DefaultSpenders...)
+	tests := []struct {
+		solver, network, address string
+		known, checked           bool
+	}{
+		{"kyberswap", "8453", "0x6131b5fae19ea4f9d964eac0408e4408b66337b5", true, true},
+		{"kyberswap", "8453", "0x00000000000000000000000000000000000000aa", false, true},
+		{"0x", "1", "0x000000000022D473030F116dDEE9F6B43aC78BA3", true, true},
+		{"hyperbloom", "999", "0x00000000000000000000000000000000000000bb", true, true},
+		{"hyperbloom", "1", "0x00000000000000000000000000000000000000bb", false, false},
+		{"odos", "1", "0x00000000000000000000000000000000000000aa", false, false},
+	}
+	for _, tt := range tests {
+		known, checked := CheckSpender(table, tt.solver, tt.network, tt.address)
+		if known != tt.known || checked != tt.checked {
+			t.Errorf("CheckSpender(%s, %s, %s) = %v, %v; want %v, %v", tt.solver, tt.network, tt.address, known, checked, tt.known, tt.checked)
+		}
+	}
+}
+
+func TestLoadSpenders(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	os.WriteFile(good, []byte(`[{"solver":"hyperbloom","network":"999","address":"0x00000000000000000000000000000000000000bb"}]`), 0o644)
+	got, err := loadSpenders(good)
+	if err != nil || len(got) != 1 || got[0].Solver != "hyperbloom" {
+		t.Fatalf("loadSpenders = %+v, %v", got, err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`[{"solver":"hyperbloom","address":"0xbb"}]`), 0o644)
+	if _, err := loadSpenders(bad); err == nil {
+		t.Fatal("want an error for an invalid address")
+	}
+}
@@ -0,0 +1,163 @@
+package providers
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-monitoring/config"
+	"go-monitoring/monitoring/collector"
+)
+
+var (
+	getHooksConfigSelector             = crypto.Keccak256([]byte("getHooksConfig(address)"))[:4]
+	getStaticSwapFeePercentageSelector = crypto.Keccak256([]byte("getStaticSwapFeePercentage(address)"))[:4]
+	getCurrentLiveBalancesSelector     = crypto.Keccak256([]byte("getCurrentLiveBalances(address)"))[:4]
+	getMaxSurgeFeePercentageSelector   = crypto.Keccak256([]byte("getMaxSurgeFeePercentage(address)"))[:4]
+	getSurgeThresholdSelector          = crypto.Keccak256([]byte("getSurgeThresholdPercentage(address)"))[:4]
+)
+
+// hooksContractWord is the index of hooksContract among the words of the
+// Vault's HooksConfig: ten bools, then the address.
+const hooksContractWord = 10
+
+// QuerySurgeState reads a StableSurge pool's fee state at the head: the
+// hook from the Vault's HooksConfig, its max surge fee and threshold, and
+// the Vault's static fee and live balances, from which the imbalance and
+// current surge fee are computed as the hook would. The block is the live
+// balances' read.
+func QuerySurgeState(network, pool string) (collector.SurgeState, error) {
+	rpcURL := config.GetRPCURL(network)
+	if rpcURL == "" {
+		return collector.SurgeState{}, fmt.Errorf("no RPC URL configured for network %s", network)
+	}
+	arg := common.LeftPadBytes(common.HexToAddress(pool).Bytes(), 32)
+	call := func(to string, selector []byte) ([]byte, error) {
+		return viewCall(rpcURL, to, append(append([]byte{}, selector...), arg...))
+	}
+
+	result, err := call(vaultAddress, getHooksConfigSelector)
+	if err != nil {
+		return collector.SurgeState{}, fmt.Errorf("getHooksConfig: %w", err)
+	}
+	hook, err := hooksContract(result)
+	if err != nil {
+		return collector.SurgeState{}, fmt.Errorf("getHooksConfig: %w", err)
+	}
+
+	var s collector.SurgeState
+	for _, read := range []struct {
+		name     string
+		to       string
+		selector []byte
+		into     *float64
+	}{
+		{"getStaticSwapFeePercentage", vaultAddress, getStaticSwapFeePercentageSelector, &s.StaticFee},
+		{"getMaxSurgeFeePercentage", hook.Hex(), getMaxSurgeFeePercentageSelector, &s.MaxFee},
+		{"getSurgeThresholdPercentage", hook.Hex(), getSurgeThresholdSelector, &s.Threshold},
+	} {
+		result, err := call(read.to, read.selector)
+		if err != nil {
+			return collector.SurgeState{}, fmt.Errorf("%s: %w", read.name, err)
+		}
+		if len(result) < 32 {
+			return collector.SurgeState{}, fmt.Errorf("%s: short result (%d bytes)", read.name, len(result))
+		}
+		*read.into = fromWad(new(big.Int).SetBytes(result[:32]))
+	}
+
+	vault := common.HexToAddress(vaultAddress)
+	balances, err := callRPC(rpcURL, ethereum.CallMsg{To: &vault, Data: append(append([]byte{}, getCurrentLiveBalancesSelector...), arg...)}, 0)
+	if err != nil {
+		return collector.SurgeState{}, fmt.Errorf("getCurrentLiveBalances: %w", err)
+	}
+	live, err := decodeUintArray(balances.Result)
+	if err != nil {
+		return collector.SurgeState{}, fmt.Errorf("getCurrentLiveBalances: %w", err)
+	}
+	s.Block = balances.BlockNumber
+	s.Imbalance = surgeImbalance(live)
+	s.Fee = surgeFee(s.StaticFee, s.MaxFee, s.Threshold, s.Imbalance)
+	return s, nil
+}
+
+// hooksContract decodes the hook address of a getHooksConfig result; an
+// error when the pool has none.
+func hooksContract(result []byte) (common.Address, error) {
+	if len(result) < (hooksContractWord+1)*32 {
+		return common.Address{}, fmt.Errorf("short result (%d bytes)", len(result))
+	}
+	hook := common.HexToAddress(hex.EncodeToString(result[hooksContractWord*32+12 : (hooksContractWord+1)*32]))
+	if hook == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("pool has no hook")
+	}
+	return hook, nil
+}
+
+// fromWad converts an 18-decimal fixed point value to a float.
+func fromWad(v *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(v), big.NewFloat(1e18)).Float64()
+	return f
+}
+
+// surgeImbalance is StableSurgeMedianMath.calculateImbalance: the balances'
+// total absolute deviation from their median over their sum.
+func surgeImbalance(balances []*big.Int) float64 {
+	if len(balances) == 0 {
+		return 0
+	}
+	sorted := make([]*big.Int, len(balances))
+	copy(sorted, balances)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	mid := len(sorted) / 2
+	median := new(big.Int).Set(sorted[mid])
+	if len(sorted)%2 == 0 {
+		median.Add(median, sorted[mid-1]).Rsh(median, 1)
+	}
+	total, diff := new(big.Int), new(big.Int)
+	for _, b := range balances {
+		total.Add(total, b)
+		diff.Add(diff, new(big.Int).Abs(new(big.Int).Sub(b, median)))
+	}
+	if total.Sign() == 0 {
+		return 0
+	}
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(diff), new(big.Float).SetInt(total)).Float64()
+	return f
+}
+
+// surgeFee is the fee StableSurgeHook charges a swap leaving the pool at
+// imbalance: the static fee up to the threshold, then linear up to maxFee
+// at full imbalance. A maxFee below the static fee never surges.
+func surgeFee(staticFee, maxFee, threshold, imbalance float64) float64 {
+	if maxFee < staticFee || imbalance <= threshold || threshold >= 1 {
+		return staticFee
+	}
+	return staticFee + (maxFee-staticFee)*(imbalance-threshold)/(1-threshold)
+}
+
+// decodeUintArray decodes an ABI-encoded uint256[] return value.
+func decodeUintArray(b []byte) ([]*big.Int, error) {
+	offset, ok := abiWord(b, 0)
+	if !ok {
+		return nil, fmt.Errorf("short result (%d bytes)", len(b))
+	}
+	n, ok := abiWord(b, offset)
+	if !ok || n > uint64(len(b))/32 {
+		return nil, fmt.Errorf("bad array length")
+	}
+	out := make([]*big.Int, 0, n)
+	for i := uint64(0); i < n; i++ {
+		start := offset + 32 + i*32
+		if start+32 > uint64(len(b)) {
+			return nil, fmt.Errorf("short array")
+		}
+		out = append(out, new(big.Int).SetBytes(b[start:start+32]))
+	}
+	return out, nil
+}
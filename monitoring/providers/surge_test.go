@@ -0,0 +1,67 @@
+package providers
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSurgeImbalanceAndFee(t *testing.T) {
+	wad := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18)) }
+	if got := surgeImbalance([]*big.Int{wad(100), wad(100)}); got != 0 {
+		t.Errorf("balanced imbalance = %v", got)
+	}
+	// Median of 70 and 130 is 100: deviations 60 over a sum of 200.
+	if got := surgeImbalance([]*big.Int{wad(70), wad(130)}); math.Abs(got-0.3) > 1e-9 {
+		t.Errorf("two-token imbalance = %v", got)
+	}
+	// Median 100: deviations 50 + 0 + 50 over 300.
+	if got := surgeImbalance([]*big.Int{wad(150), wad(50), wad(100)}); math.Abs(got-1.0/3) > 1e-9 {
+		t.Errorf("three-token imbalance = %v", got)
+	}
+
+	for _, tt := range []struct {
+		static, max, threshold, imbalance, want float64
+	}{
+		{0.001, 0.05, 0.3, 0.2, 0.001},
+		{0.001, 0.05, 0.3, 0.3, 0.001},
+		{0.001, 0.05, 0.3, 0.65, 0.001 + 0.049*0.5},
+		{0.001, 0.05, 0.3, 1, 0.05},
+		{0.01, 0.005, 0.3, 0.9, 0.01}, // max below static never surges
+	} {
+		if got := surgeFee(tt.static, tt.max, tt.threshold, tt.imbalance); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("surgeFee(%v, %v, %v, %v) = %v, want %v", tt.static, tt.max, tt.threshold, tt.imbalance, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeSurgeReads(t *testing.T) {
+	word := func(v *big.Int) []byte { return common.LeftPadBytes(v.Bytes(), 32) }
+	hook := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	config := append(bytes.Repeat(word(big.NewInt(1)), hooksContractWord), word(new(big.Int).SetBytes(hook.Bytes()))...)
+	if got, err := hooksContract(config); err != nil || got != hook {
+		t.Fatalf("hooksContract = %s, %v", got.Hex(), err)
+	}
+	if _, err := hooksContract(bytes.Repeat(word(big.NewInt(0)), hooksContractWord+1)); err == nil {
+		t.Error("pool without a hook decoded")
+	}
+	if _, err := hooksContract(config[:hooksContractWord*32]); err == nil {
+		t.Error("short HooksConfig decoded")
+	}
+
+	balances := slices.Concat(word(big.NewInt(32)), word(big.NewInt(2)), word(big.NewInt(20)), word(big.NewInt(180)))
+	got, err := decodeUintArray(balances)
+	if err != nil || len(got) != 2 || got[0].Int64() != 20 || got[1].Int64() != 180 {
+		t.Fatalf("decodeUintArray = %v, %v", got, err)
+	}
+	if _, err := decodeUintArray(balances[:96]); err == nil {
+		t.Error("truncated array decoded")
+	}
+	if f := fromWad(new(big.Int).Mul(big.NewInt(3), big.NewInt(1e15))); math.Abs(f-0.003) > 1e-15 {
+		t.Errorf("fromWad = %v", f)
+	}
+}
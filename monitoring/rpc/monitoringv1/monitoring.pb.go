@@ -0,0 +1,942 @@
+// MonitoringService exposes endpoint state, check history and on-demand checks
+// over Connect (https://connectrpc.com) at
+// /monitoring.v1.MonitoringService/<Method>: the Connect, gRPC and gRPC-Web
+// protocols, each with the binary or JSON encoding. The Go messages and
+// client are generated into monitoring/rpc/monitoringv1 by `buf generate` in
+// proto/ (see buf.gen.yaml); regenerate after editing this file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: monitoring/v1/monitoring.proto
+
+package monitoringv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Endpoint struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	BaseName     string                 `protobuf:"bytes,2,opt,name=base_name,json=baseName,proto3" json:"base_name,omitempty"`
+	Network      string                 `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+	RouteSolver  string                 `protobuf:"bytes,4,opt,name=route_solver,json=routeSolver,proto3" json:"route_solver,omitempty"`
+	SolverName   string                 `protobuf:"bytes,5,opt,name=solver_name,json=solverName,proto3" json:"solver_name,omitempty"`
+	ExpectedPool string                 `protobuf:"bytes,6,opt,name=expected_pool,json=expectedPool,proto3" json:"expected_pool,omitempty"`
+	Discovered   bool                   `protobuf:"varint,7,opt,name=discovered,proto3" json:"discovered,omitempty"`
+	// "up", "down", "error", "panic", "info", "unsupported", "disabled" or "unknown".
+	Status          string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	Message         string                 `protobuf:"bytes,9,opt,name=message,proto3" json:"message,omitempty"`
+	LastChecked     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_checked,json=lastChecked,proto3" json:"last_checked,omitempty"`
+	LastStateChange *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=last_state_change,json=lastStateChange,proto3" json:"last_state_change,omitempty"`
+	// Start of the current down streak; unset when not down.
+	FirstSeenDown *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=first_seen_down,json=firstSeenDown,proto3" json:"first_seen_down,omitempty"`
+	ReturnAmount  string                 `protobuf:"bytes,13,opt,name=return_amount,json=returnAmount,proto3" json:"return_amount,omitempty"`
+	MarketPrice   string                 `protobuf:"bytes,14,opt,name=market_price,json=marketPrice,proto3" json:"market_price,omitempty"`
+	OnChainPrice  string                 `protobuf:"bytes,15,opt,name=on_chain_price,json=onChainPrice,proto3" json:"on_chain_price,omitempty"`
+	// Tags from the BaseEndpoint, e.g. team=integrations.
+	Labels map[string]string `protobuf:"bytes,16,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Stable identity of the row; name is for display and may change.
+	Id            string `protobuf:"bytes,17,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Endpoint) Reset() {
+	*x = Endpoint{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Endpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Endpoint) ProtoMessage() {}
+
+func (x *Endpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Endpoint.ProtoReflect.Descriptor instead.
+func (*Endpoint) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Endpoint) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Endpoint) GetBaseName() string {
+	if x != nil {
+		return x.BaseName
+	}
+	return ""
+}
+
+func (x *Endpoint) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Endpoint) GetRouteSolver() string {
+	if x != nil {
+		return x.RouteSolver
+	}
+	return ""
+}
+
+func (x *Endpoint) GetSolverName() string {
+	if x != nil {
+		return x.SolverName
+	}
+	return ""
+}
+
+func (x *Endpoint) GetExpectedPool() string {
+	if x != nil {
+		return x.ExpectedPool
+	}
+	return ""
+}
+
+func (x *Endpoint) GetDiscovered() bool {
+	if x != nil {
+		return x.Discovered
+	}
+	return false
+}
+
+func (x *Endpoint) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Endpoint) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Endpoint) GetLastChecked() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastChecked
+	}
+	return nil
+}
+
+func (x *Endpoint) GetLastStateChange() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastStateChange
+	}
+	return nil
+}
+
+func (x *Endpoint) GetFirstSeenDown() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeenDown
+	}
+	return nil
+}
+
+func (x *Endpoint) GetReturnAmount() string {
+	if x != nil {
+		return x.ReturnAmount
+	}
+	return ""
+}
+
+func (x *Endpoint) GetMarketPrice() string {
+	if x != nil {
+		return x.MarketPrice
+	}
+	return ""
+}
+
+func (x *Endpoint) GetOnChainPrice() string {
+	if x != nil {
+		return x.OnChainPrice
+	}
+	return ""
+}
+
+func (x *Endpoint) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Endpoint) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListEndpointsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IncludeDiscovered bool                   `protobuf:"varint,1,opt,name=include_discovered,json=includeDiscovered,proto3" json:"include_discovered,omitempty"`
+	Network           string                 `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	RouteSolver       string                 `protobuf:"bytes,3,opt,name=route_solver,json=routeSolver,proto3" json:"route_solver,omitempty"`
+	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListEndpointsRequest) Reset() {
+	*x = ListEndpointsRequest{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEndpointsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndpointsRequest) ProtoMessage() {}
+
+func (x *ListEndpointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndpointsRequest.ProtoReflect.Descriptor instead.
+func (*ListEndpointsRequest) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListEndpointsRequest) GetIncludeDiscovered() bool {
+	if x != nil {
+		return x.IncludeDiscovered
+	}
+	return false
+}
+
+func (x *ListEndpointsRequest) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *ListEndpointsRequest) GetRouteSolver() string {
+	if x != nil {
+		return x.RouteSolver
+	}
+	return ""
+}
+
+func (x *ListEndpointsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListEndpointsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Endpoints     []*Endpoint            `protobuf:"bytes,1,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEndpointsResponse) Reset() {
+	*x = ListEndpointsResponse{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEndpointsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndpointsResponse) ProtoMessage() {}
+
+func (x *ListEndpointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndpointsResponse.ProtoReflect.Descriptor instead.
+func (*ListEndpointsResponse) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListEndpointsResponse) GetEndpoints() []*Endpoint {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+type GetEndpointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// An Endpoint id or name.
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEndpointRequest) Reset() {
+	*x = GetEndpointRequest{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEndpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEndpointRequest) ProtoMessage() {}
+
+func (x *GetEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEndpointRequest.ProtoReflect.Descriptor instead.
+func (*GetEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetEndpointRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetEndpointResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Endpoint      *Endpoint              `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEndpointResponse) Reset() {
+	*x = GetEndpointResponse{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEndpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEndpointResponse) ProtoMessage() {}
+
+func (x *GetEndpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEndpointResponse.ProtoReflect.Descriptor instead.
+func (*GetEndpointResponse) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetEndpointResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type GetHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// An Endpoint id or name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Defaults to 24 hours ago.
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetHistoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type CheckRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=at,proto3" json:"at,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRecord) Reset() {
+	*x = CheckRecord{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRecord) ProtoMessage() {}
+
+func (x *CheckRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRecord.ProtoReflect.Descriptor instead.
+func (*CheckRecord) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CheckRecord) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *CheckRecord) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CheckRecord) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetHistoryResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Records []*CheckRecord         `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	// Fraction of checks since `since` that were up; -1 with no checks.
+	Uptime        float64 `protobuf:"fixed64,2,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetHistoryResponse) GetRecords() []*CheckRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *GetHistoryResponse) GetUptime() float64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+type TriggerCheckRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// An Endpoint id or name.
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerCheckRequest) Reset() {
+	*x = TriggerCheckRequest{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerCheckRequest) ProtoMessage() {}
+
+func (x *TriggerCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerCheckRequest.ProtoReflect.Descriptor instead.
+func (*TriggerCheckRequest) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TriggerCheckRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type TriggerCheckResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Endpoint *Endpoint              `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// The check just run, with its failure class and latency.
+	Result        *CheckResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerCheckResponse) Reset() {
+	*x = TriggerCheckResponse{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerCheckResponse) ProtoMessage() {}
+
+func (x *TriggerCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerCheckResponse.ProtoReflect.Descriptor instead.
+func (*TriggerCheckResponse) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TriggerCheckResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+func (x *TriggerCheckResponse) GetResult() *CheckResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// The outcome of one check.
+type CheckResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Status  string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Alert hints class of a failure, e.g. "rate_limited"; empty when the
+	// check didn't fail or no class matches.
+	ErrorClass   string `protobuf:"bytes,3,opt,name=error_class,json=errorClass,proto3" json:"error_class,omitempty"`
+	ReturnAmount string `protobuf:"bytes,4,opt,name=return_amount,json=returnAmount,proto3" json:"return_amount,omitempty"`
+	MarketPrice  string `protobuf:"bytes,5,opt,name=market_price,json=marketPrice,proto3" json:"market_price,omitempty"`
+	// Of the Balancer-only response; 0 / empty when none arrived.
+	HttpStatus    int32                  `protobuf:"varint,6,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
+	RequestId     string                 `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	LatencyMs     float64                `protobuf:"fixed64,8,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	CheckedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckResult) Reset() {
+	*x = CheckResult{}
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckResult) ProtoMessage() {}
+
+func (x *CheckResult) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoring_v1_monitoring_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckResult.ProtoReflect.Descriptor instead.
+func (*CheckResult) Descriptor() ([]byte, []int) {
+	return file_monitoring_v1_monitoring_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CheckResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CheckResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CheckResult) GetErrorClass() string {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return ""
+}
+
+func (x *CheckResult) GetReturnAmount() string {
+	if x != nil {
+		return x.ReturnAmount
+	}
+	return ""
+}
+
+func (x *CheckResult) GetMarketPrice() string {
+	if x != nil {
+		return x.MarketPrice
+	}
+	return ""
+}
+
+func (x *CheckResult) GetHttpStatus() int32 {
+	if x != nil {
+		return x.HttpStatus
+	}
+	return 0
+}
+
+func (x *CheckResult) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *CheckResult) GetLatencyMs() float64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *CheckResult) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+var File_monitoring_v1_monitoring_proto protoreflect.FileDescriptor
+
+const file_monitoring_v1_monitoring_proto_rawDesc = "" +
+	"\n" +
+	"\x1emonitoring/v1/monitoring.proto\x12\rmonitoring.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd1\x05\n" +
+	"\bEndpoint\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tbase_name\x18\x02 \x01(\tR\bbaseName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x12!\n" +
+	"\froute_solver\x18\x04 \x01(\tR\vrouteSolver\x12\x1f\n" +
+	"\vsolver_name\x18\x05 \x01(\tR\n" +
+	"solverName\x12#\n" +
+	"\rexpected_pool\x18\x06 \x01(\tR\fexpectedPool\x12\x1e\n" +
+	"\n" +
+	"discovered\x18\a \x01(\bR\n" +
+	"discovered\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\t \x01(\tR\amessage\x12=\n" +
+	"\flast_checked\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vlastChecked\x12F\n" +
+	"\x11last_state_change\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x0flastStateChange\x12B\n" +
+	"\x0ffirst_seen_down\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\rfirstSeenDown\x12#\n" +
+	"\rreturn_amount\x18\r \x01(\tR\freturnAmount\x12!\n" +
+	"\fmarket_price\x18\x0e \x01(\tR\vmarketPrice\x12$\n" +
+	"\x0eon_chain_price\x18\x0f \x01(\tR\fonChainPrice\x12;\n" +
+	"\x06labels\x18\x10 \x03(\v2#.monitoring.v1.Endpoint.LabelsEntryR\x06labels\x12\x0e\n" +
+	"\x02id\x18\x11 \x01(\tR\x02id\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9a\x01\n" +
+	"\x14ListEndpointsRequest\x12-\n" +
+	"\x12include_discovered\x18\x01 \x01(\bR\x11includeDiscovered\x12\x18\n" +
+	"\anetwork\x18\x02 \x01(\tR\anetwork\x12!\n" +
+	"\froute_solver\x18\x03 \x01(\tR\vrouteSolver\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\"N\n" +
+	"\x15ListEndpointsResponse\x125\n" +
+	"\tendpoints\x18\x01 \x03(\v2\x17.monitoring.v1.EndpointR\tendpoints\"(\n" +
+	"\x12GetEndpointRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"J\n" +
+	"\x13GetEndpointResponse\x123\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x17.monitoring.v1.EndpointR\bendpoint\"Y\n" +
+	"\x11GetHistoryRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\"k\n" +
+	"\vCheckRecord\x12*\n" +
+	"\x02at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"b\n" +
+	"\x12GetHistoryResponse\x124\n" +
+	"\arecords\x18\x01 \x03(\v2\x1a.monitoring.v1.CheckRecordR\arecords\x12\x16\n" +
+	"\x06uptime\x18\x02 \x01(\x01R\x06uptime\")\n" +
+	"\x13TriggerCheckRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x7f\n" +
+	"\x14TriggerCheckResponse\x123\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x17.monitoring.v1.EndpointR\bendpoint\x122\n" +
+	"\x06result\x18\x02 \x01(\v2\x1a.monitoring.v1.CheckResultR\x06result\"\xc2\x02\n" +
+	"\vCheckResult\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
+	"\verror_class\x18\x03 \x01(\tR\n" +
+	"errorClass\x12#\n" +
+	"\rreturn_amount\x18\x04 \x01(\tR\freturnAmount\x12!\n" +
+	"\fmarket_price\x18\x05 \x01(\tR\vmarketPrice\x12\x1f\n" +
+	"\vhttp_status\x18\x06 \x01(\x05R\n" +
+	"httpStatus\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\b \x01(\x01R\tlatencyMs\x129\n" +
+	"\n" +
+	"checked_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt2\xf1\x02\n" +
+	"\x11MonitoringService\x12Z\n" +
+	"\rListEndpoints\x12#.monitoring.v1.ListEndpointsRequest\x1a$.monitoring.v1.ListEndpointsResponse\x12T\n" +
+	"\vGetEndpoint\x12!.monitoring.v1.GetEndpointRequest\x1a\".monitoring.v1.GetEndpointResponse\x12Q\n" +
+	"\n" +
+	"GetHistory\x12 .monitoring.v1.GetHistoryRequest\x1a!.monitoring.v1.GetHistoryResponse\x12W\n" +
+	"\fTriggerCheck\x12\".monitoring.v1.TriggerCheckRequest\x1a#.monitoring.v1.TriggerCheckResponseB8Z6go-monitoring/monitoring/rpc/monitoringv1;monitoringv1b\x06proto3"
+
+var (
+	file_monitoring_v1_monitoring_proto_rawDescOnce sync.Once
+	file_monitoring_v1_monitoring_proto_rawDescData []byte
+)
+
+func file_monitoring_v1_monitoring_proto_rawDescGZIP() []byte {
+	file_monitoring_v1_monitoring_proto_rawDescOnce.Do(func() {
+		file_monitoring_v1_monitoring_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_monitoring_v1_monitoring_proto_rawDesc), len(file_monitoring_v1_monitoring_proto_rawDesc)))
+	})
+	return file_monitoring_v1_monitoring_proto_rawDescData
+}
+
+var file_monitoring_v1_monitoring_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_monitoring_v1_monitoring_proto_goTypes = []any{
+	(*Endpoint)(nil),              // 0: monitoring.v1.Endpoint
+	(*ListEndpointsRequest)(nil),  // 1: monitoring.v1.ListEndpointsRequest
+	(*ListEndpointsResponse)(nil), // 2: monitoring.v1.ListEndpointsResponse
+	(*GetEndpointRequest)(nil),    // 3: monitoring.v1.GetEndpointRequest
+	(*GetEndpointResponse)(nil),   // 4: monitoring.v1.GetEndpointResponse
+	(*GetHistoryRequest)(nil),     // 5: monitoring.v1.GetHistoryRequest
+	(*CheckRecord)(nil),           // 6: monitoring.v1.CheckRecord
+	(*GetHistoryResponse)(nil),    // 7: monitoring.v1.GetHistoryResponse
+	(*TriggerCheckRequest)(nil),   // 8: monitoring.v1.TriggerCheckRequest
+	(*TriggerCheckResponse)(nil),  // 9: monitoring.v1.TriggerCheckResponse
+	(*CheckResult)(nil),           // 10: monitoring.v1.CheckResult
+	nil,                           // 11: monitoring.v1.Endpoint.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
+}
+var file_monitoring_v1_monitoring_proto_depIdxs = []int32{
+	12, // 0: monitoring.v1.Endpoint.last_checked:type_name -> google.protobuf.Timestamp
+	12, // 1: monitoring.v1.Endpoint.last_state_change:type_name -> google.protobuf.Timestamp
+	12, // 2: monitoring.v1.Endpoint.first_seen_down:type_name -> google.protobuf.Timestamp
+	11, // 3: monitoring.v1.Endpoint.labels:type_name -> monitoring.v1.Endpoint.LabelsEntry
+	0,  // 4: monitoring.v1.ListEndpointsResponse.endpoints:type_name -> monitoring.v1.Endpoint
+	0,  // 5: monitoring.v1.GetEndpointResponse.endpoint:type_name -> monitoring.v1.Endpoint
+	12, // 6: monitoring.v1.GetHistoryRequest.since:type_name -> google.protobuf.Timestamp
+	12, // 7: monitoring.v1.CheckRecord.at:type_name -> google.protobuf.Timestamp
+	6,  // 8: monitoring.v1.GetHistoryResponse.records:type_name -> monitoring.v1.CheckRecord
+	0,  // 9: monitoring.v1.TriggerCheckResponse.endpoint:type_name -> monitoring.v1.Endpoint
+	10, // 10: monitoring.v1.TriggerCheckResponse.result:type_name -> monitoring.v1.CheckResult
+	12, // 11: monitoring.v1.CheckResult.checked_at:type_name -> google.protobuf.Timestamp
+	1,  // 12: monitoring.v1.MonitoringService.ListEndpoints:input_type -> monitoring.v1.ListEndpointsRequest
+	3,  // 13: monitoring.v1.MonitoringService.GetEndpoint:input_type -> monitoring.v1.GetEndpointRequest
+	5,  // 14: monitoring.v1.MonitoringService.GetHistory:input_type -> monitoring.v1.GetHistoryRequest
+	8,  // 15: monitoring.v1.MonitoringService.TriggerCheck:input_type -> monitoring.v1.TriggerCheckRequest
+	2,  // 16: monitoring.v1.MonitoringService.ListEndpoints:output_type -> monitoring.v1.ListEndpointsResponse
+	4,  // 17: monitoring.v1.MonitoringService.GetEndpoint:output_type -> monitoring.v1.GetEndpointResponse
+	7,  // 18: monitoring.v1.MonitoringService.GetHistory:output_type -> monitoring.v1.GetHistoryResponse
+	9,  // 19: monitoring.v1.MonitoringService.TriggerCheck:output_type -> monitoring.v1.TriggerCheckResponse
+	16, // [16:20] is the sub-list for method output_type
+	12, // [12:16] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_monitoring_v1_monitoring_proto_init() }
+func file_monitoring_v1_monitoring_proto_init() {
+	if File_monitoring_v1_monitoring_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_monitoring_v1_monitoring_proto_rawDesc), len(file_monitoring_v1_monitoring_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_monitoring_v1_monitoring_proto_goTypes,
+		DependencyIndexes: file_monitoring_v1_monitoring_proto_depIdxs,
+		MessageInfos:      file_monitoring_v1_monitoring_proto_msgTypes,
+	}.Build()
+	File_monitoring_v1_monitoring_proto = out.File
+	file_monitoring_v1_monitoring_proto_goTypes = nil
+	file_monitoring_v1_monitoring_proto_depIdxs = nil
+}
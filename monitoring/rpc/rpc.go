@@ -0,0 +1,45 @@
+// Package rpc connects other services to MonitoringService
+// (proto/monitoring/v1/monitoring.proto), so they can read endpoint state
+// instead of scraping the dashboard. The messages and the client are
+// generated into monitoringv1 and monitoringv1connect; NewClient sets up a
+// client for a monitor.
+//
+//	c := rpc.NewClient("https://go-monitoring.fly.dev", rpc.WithToken(token))
+//	res, err := c.ListEndpoints(ctx, connect.NewRequest(&monitoringv1.ListEndpointsRequest{RouteSolver: "kyberswap"}))
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"go-monitoring/monitoring/rpc/monitoringv1/monitoringv1connect"
+)
+
+// NewClient returns a MonitoringService client for the monitor at baseURL,
+// using the Connect protocol's binary encoding unless opts choose another
+// (connect.WithProtoJSON, connect.WithGRPC). TriggerCheck waits for the
+// provider calls, so the timeout is generous.
+func NewClient(baseURL string, opts ...connect.ClientOption) monitoringv1connect.MonitoringServiceClient {
+	return NewClientWithHTTP(&http.Client{Timeout: 2 * time.Minute}, baseURL, opts...)
+}
+
+// NewClientWithHTTP is NewClient over httpClient, e.g. one speaking HTTP/2
+// for connect.WithGRPC.
+func NewClientWithHTTP(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) monitoringv1connect.MonitoringServiceClient {
+	return monitoringv1connect.NewMonitoringServiceClient(httpClient, strings.TrimRight(baseURL, "/"), opts...)
+}
+
+// WithToken makes the client send token (an API token or ADMIN_TOKEN) as its
+// bearer token, for a monitor with API_REQUIRE_TOKEN set.
+func WithToken(token string) connect.ClientOption {
+	return connect.WithInterceptors(connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			req.Header().Set("Authorization", "Bearer "+token)
+			return next(ctx, req)
+		}
+	}))
+}
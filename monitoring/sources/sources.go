@@ -0,0 +1,279 @@
+// Package sources is the registry of the names each route solver uses for
+// Balancer V3 liquidity: Kyber's "balancer-v3-stable", 0x's "Balancer_V3",
+// 1inch's "ARBITRUM_BALANCER_V3" and so on. Handlers take both the include
+// filters they send and the route sources they accept from here, so a
+// provider renaming a source is a SOURCE_IDS_FILE entry rather than a code
+// change.
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"go-monitoring/config"
+	"go-monitoring/internal/logs"
+	"go-monitoring/monitoring/collector"
+)
+
+// Pool kinds, the pool type granularity at which providers split sources.
+const (
+	KindStable   = "stable"
+	KindWeighted = "weighted"
+	KindGyro     = "gyro"
+	KindReCLAMM  = "reclamm"
+	KindQuantAMM = "quantamm"
+	// KindLBP is a liquidity bootstrapping pool. Weighted math, but providers
+	// that split sources by pool type list it apart from KindWeighted, if at
+	// all: with no entry a solver's check fails as an unsupported pool type
+	// until SOURCE_IDS_FILE maps it.
+	KindLBP = "lbp"
+)
+
+// KindOf classifies an endpoint's pool. Discovered rows carry PoolType /
+// HookType from the Balancer API; BaseEndpoints leave them empty and are
+// classified by substrings of their Name (Stable, Gyro, Weighted, LBP, …).
+// Returns "" when neither identifies the pool.
+func KindOf(e *collector.Endpoint) string {
+	pt := strings.TrimSpace(e.PoolType)
+	ht := strings.TrimSpace(e.HookType)
+	if pt != "" || ht != "" {
+		combined := strings.ToUpper(pt + " " + ht)
+		switch {
+		case strings.Contains(combined, "LBP") || strings.Contains(combined, "LIQUIDITY_BOOTSTRAPPING"):
+			return KindLBP
+		case strings.Contains(combined, "QUANT"):
+			return KindQuantAMM
+		case strings.Contains(combined, "RECLAMM"):
+			return KindReCLAMM
+		case strings.Contains(combined, "GYRO"):
+			return KindGyro
+		case strings.Contains(combined, "STABLE"):
+			return KindStable
+		case strings.Contains(combined, "WEIGHTED"):
+			return KindWeighted
+		}
+		return ""
+	}
+	switch {
+	case strings.Contains(e.Name, "Quant"):
+		return KindQuantAMM
+	case strings.Contains(e.Name, "Stable"):
+		return KindStable
+	case strings.Contains(e.Name, "Gyro"):
+		return KindGyro
+	case strings.Contains(e.Name, "reCLAMM"):
+		return KindReCLAMM
+	case strings.Contains(e.Name, "LBP"):
+		return KindLBP
+	case strings.Contains(e.Name, "Weighted"):
+		return KindWeighted
+	}
+	return ""
+}
+
+// Entry maps a solver, optionally narrowed to one network and pool kind, to
+// its Balancer V3 source identifiers.
+type Entry struct {
+	Solver   string `json:"solver"`             // route solver type, e.g. "kyberswap"
+	Network  string `json:"network,omitempty"`  // chain id; empty matches every network
+	PoolKind string `json:"poolKind,omitempty"` // Kind* constant; empty matches every pool
+	// IDs are sent in include filters and accepted as route sources.
+	IDs []string `json:"ids"`
+	// Contains also accepts route sources that contain an ID, for providers
+	// that decorate source names (e.g. OpenOcean "BalancerV3Stable").
+	Contains bool `json:"contains,omitempty"`
+}
+
+// Matches reports whether a route source is one of the entry's IDs.
+func (e Entry) Matches(source string) bool {
+	for _, id := range e.IDs {
+		if source == id || e.Contains && strings.Contains(source, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// String joins the IDs with commas, as include filters take them.
+func (e Entry) String() string {
+	return strings.Join(e.IDs, ",")
+}
+
+// odosBalancerV3 are Odos's per-pool-type Balancer V3 sources; Odos takes
+// them all as one whitelist.
+var odosBalancerV3 = []string{"Balancer V3 Gyro", "Balancer V3 Stable", "Balancer V3 Weighted", "Balancer V3 StableSurge", "Balancer V3 reCLAMM"}
+
+// DefaultEntries is the built-in registry.
+var DefaultEntries = []Entry{
+	{Solver: "0x", IDs: []string{"Balancer_V3"}},
+	{Solver: "paraswap", IDs: []string{"BalancerV3"}},
+	{Solver: "hyperbloom", IDs: []string{"BalancerV3"}},
+	{Solver: "barter", IDs: []string{"BalancerV3"}},
+	{Solver: "openocean", IDs: []string{"BalancerV3"}, Contains: true},
+	{Solver: "odos", IDs: odosBalancerV3},
+
+	{Solver: "1inch", Network: "1", IDs: []string{"BALANCER_V3"}, Contains: true},
+	{Solver: "1inch", Network: "100", IDs: []string{"GNOSIS_BALANCER_V3"}, Contains: true},
+	{Solver: "1inch", Network: "8453", IDs: []string{"BASE_BALANCER_V3"}, Contains: true},
+	{Solver: "1inch", Network: "42161", IDs: []string{"ARBITRUM_BALANCER_V3"}, Contains: true},
+	{Solver: "1inch", Network: "43114", IDs: []string{"AVALANCHE_BALANCER_V3"}, Contains: true},
+
+	{Solver: "kyberswap", PoolKind: KindStable, IDs: []string{"balancer-v3-stable"}},
+	{Solver: "kyberswap", PoolKind: KindWeighted, IDs: []string{"balancer-v3-weighted"}},
+	{Solver: "kyberswap", PoolKind: KindGyro, IDs: []string{"balancer-v3-eclp"}},
+	{Solver: "kyberswap", PoolKind: KindReCLAMM, IDs: []string{"balancer-v3-reclamm"}},
+	{Solver: "kyberswap", PoolKind: KindQuantAMM, IDs: []string{"balancer-v3-quantamm"}},
+}
+
+var (
+	tableOnce sync.Once
+	table     []Entry
+)
+
+// Table returns the active registry: entries from the JSON file named by
+// SOURCE_IDS_FILE (if any) followed by DefaultEntries. Loaded once per
+// process.
+func Table() []Entry {
+	tableOnce.Do(func() {
+		table = DefaultEntries
+		path := config.GetSourceIDsFile()
+		if path == "" {
+			return
+		}
+		loaded, err := loadEntries(path)
+		if err != nil {
+			logs.Printf("%s[ERROR]%s: SOURCE_IDS_FILE %s: %v; using built-in source IDs\n", config.ColorRed, config.ColorReset, path, err)
+			return
+		}
+		table = append(loaded, DefaultEntries...)
+	})
+	return table
+}
+
+func loadEntries(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []Entry
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	for i, e := range out {
+		if e.Solver == "" || len(e.IDs) == 0 {
+			return nil, fmt.Errorf("entry %d: solver and ids are required", i+1)
+		}
+	}
+	return out, nil
+}
+
+// Lookup returns the most specific entry of table for the solver, network
+// and pool kind: a network match outranks a pool kind match, which outranks
+// a catch-all. Ties go to the earlier entry, so file overrides win.
+func Lookup(table []Entry, solver, network, kind string) (Entry, bool) {
+	best, bestScore := Entry{}, -1
+	for _, e := range table {
+		if !strings.EqualFold(e.Solver, solver) ||
+			e.Network != "" && e.Network != network ||
+			e.PoolKind != "" && e.PoolKind != kind {
+			continue
+		}
+		score := 0
+		if e.Network != "" {
+			score += 2
+		}
+		if e.PoolKind != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = e, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// IsBalancerV3 reports whether a provider's source ID names Balancer V3
+// liquidity, however the provider spells it ("Balancer_V3",
+// "balancer-v3-eclp", "Balancer V3 Stable", …).
+func IsBalancerV3(id string) bool {
+	var b strings.Builder
+	for _, r := range strings.ToLower(id) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return strings.Contains(b.String(), "balancerv3")
+}
+
+// Unknown returns the Balancer V3 IDs in a provider's catalog that no entry
+// of table for the solver and network matches, whatever its pool kind: new
+// sources the registry (and so the handlers) would reject.
+func Unknown(table []Entry, solver, network string, catalog []string) []string {
+	var out []string
+	for _, id := range catalog {
+		if !IsBalancerV3(id) {
+			continue
+		}
+		known := false
+		for _, e := range table {
+			if strings.EqualFold(e.Solver, solver) && (e.Network == "" || e.Network == network) && e.Matches(id) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// AllIDs returns the IDs of every entry of table for the solver and network,
+// whatever its pool kind, without duplicates: what a quote must exclude to
+// keep Balancer V3 out of its route entirely.
+func AllIDs(table []Entry, solver, network string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, e := range table {
+		if !strings.EqualFold(e.Solver, solver) || e.Network != "" && e.Network != network {
+			continue
+		}
+		for _, id := range e.IDs {
+			if !seen[id] {
+				seen[id] = true
+				out = append(out, id)
+			}
+		}
+	}
+	return out
+}
+
+// Removed returns the Balancer V3 IDs of a provider's previous catalog that
+// are missing from its current one, in previous-catalog order.
+func Removed(previous, current []string) []string {
+	have := make(map[string]bool, len(current))
+	for _, id := range current {
+		have[id] = true
+	}
+	var out []string
+	for _, id := range previous {
+		if IsBalancerV3(id) && !have[id] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// ForEndpoint returns the active registry's entry for a solver's check of an
+// endpoint.
+func ForEndpoint(solver string, e *collector.Endpoint) (Entry, error) {
+	kind := KindOf(e)
+	entry, ok := Lookup(Table(), solver, e.Network, kind)
+	if !ok {
+		return Entry{}, fmt.Errorf("unsupported pool type for %s on network %s (PoolType=%q HookType=%q)", solver, e.Network, e.PoolType, e.HookType)
+	}
+	return entry, nil
+}
@@ -0,0 +1,157 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-monitoring/monitoring/collector"
+)
+
+func TestLookupMostSpecificWins(t *testing.T) {
+	table := []Entry{
+		{Solver: "kyberswap", IDs: []string{"any"}},
+		{Solver: "kyberswap", PoolKind: KindStable, IDs: []string{"stable"}},
+		{Solver: "kyberswap", Network: "8453", IDs: []string{"base"}},
+		{Solver: "kyberswap", Network: "8453", PoolKind: KindStable, IDs: []string{"base-stable"}},
+	}
+	tests := []struct {
+		network, kind, want string
+	}{
+		{"1", KindWeighted, "any"},
+		{"1", KindStable, "stable"},
+		{"8453", KindWeighted, "base"},
+		{"8453", KindStable, "base-stable"},
+	}
+	for _, tt := range tests {
+		got, ok := Lookup(table, "KyberSwap", tt.network, tt.kind)
+		if !ok || got.String() != tt.want {
+			t.Errorf("Lookup(%s, %s) = %q, %v; want %q", tt.network, tt.kind, got.String(), ok, tt.want)
+		}
+	}
+	if _, ok := Lookup(table, "odos", "1", ""); ok {
+		t.Error("Lookup matched another solver's entries")
+	}
+}
+
+func TestLookupOverrideWinsTie(t *testing.T) {
+	table := append([]Entry{{Solver: "0x", IDs: []string{"BalancerV3"}}}, DefaultEntries...)
+	got, _ := Lookup(table, "0x", "1", "")
+	if got.String() != "BalancerV3" {
+		t.Fatalf("got %q, want the override", got.String())
+	}
+}
+
+func TestDefaultsNetworkAndKindGaps(t *testing.T) {
+	if _, ok := Lookup(DefaultEntries, "1inch", "146", ""); ok {
+		t.Error("1inch should have no entry on an unlisted network")
+	}
+	if _, ok := Lookup(DefaultEntries, "kyberswap", "1", ""); ok {
+		t.Error("kyberswap should need a pool kind")
+	}
+	if got, _ := Lookup(DefaultEntries, "1inch", "42161", ""); got.String() != "ARBITRUM_BALANCER_V3" {
+		t.Errorf("1inch arbitrum = %q", got.String())
+	}
+	if got, _ := Lookup(DefaultEntries, "kyberswap", "8453", KindWeighted); got.String() != "balancer-v3-weighted" {
+		t.Errorf("kyberswap weighted = %q", got.String())
+	}
+	if _, ok := Lookup(DefaultEntries, "kyberswap", "8453", KindLBP); ok {
+		t.Error("kyberswap should list no LBP source")
+	}
+	if got, _ := Lookup(DefaultEntries, "odos", "8453", KindLBP); got.String() == "" {
+		t.Error("odos whitelist should cover every pool kind")
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		e    collector.Endpoint
+		want string
+	}{
+		{collector.Endpoint{PoolType: "COMPOSABLE_STABLE"}, KindStable},
+		{collector.Endpoint{PoolType: "WEIGHTED", HookType: "ReCLAMM"}, KindReCLAMM},
+		{collector.Endpoint{PoolType: "QUANT_AMM"}, KindQuantAMM},
+		{collector.Endpoint{PoolType: "FX"}, ""},
+		{collector.Endpoint{Name: "Base-Boosted-StableSurge(GHO/USDC)"}, KindStable},
+		{collector.Endpoint{Name: "Stable-but-Gyro", PoolType: "GYROE"}, KindGyro},
+		{collector.Endpoint{Name: "Base-Weighted(BAL/WETH)"}, KindWeighted},
+		{collector.Endpoint{Name: "Base-Weighted-LBP(TKN/USDC)"}, KindLBP},
+		{collector.Endpoint{PoolType: "LIQUIDITY_BOOTSTRAPPING"}, KindLBP},
+		{collector.Endpoint{PoolType: "FIXED_LBP"}, KindLBP},
+	}
+	for _, tt := range tests {
+		if got := KindOf(&tt.e); got != tt.want {
+			t.Errorf("KindOf(%+v) = %q, want %q", tt.e, got, tt.want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	exact := Entry{IDs: []string{"BalancerV3"}}
+	if !exact.Matches("BalancerV3") || exact.Matches("BalancerV3Stable") {
+		t.Error("exact entry should match only its ID")
+	}
+	contains := Entry{IDs: []string{"BalancerV3"}, Contains: true}
+	if !contains.Matches("BalancerV3Stable") || contains.Matches("BalancerV2") {
+		t.Error("contains entry should match decorated names only")
+	}
+}
+
+func TestLoadEntries(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	os.WriteFile(good, []byte(`[{"solver":"paraswap","ids":["BalancerV3","BalancerV3Stable"]}]`), 0o644)
+	got, err := loadEntries(good)
+	if err != nil || len(got) != 1 || got[0].String() != "BalancerV3,BalancerV3Stable" {
+		t.Fatalf("loadEntries = %+v, %v", got, err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`[{"solver":"paraswap"}]`), 0o644)
+	if _, err := loadEntries(bad); err == nil {
+		t.Fatal("want an error for an entry without ids")
+	}
+}
+
+func TestUnknown(t *testing.T) {
+	catalog := []string{"Uniswap_V3", "Balancer_V2", "Balancer_V3", "Balancer_V3_LBP"}
+	got := Unknown(DefaultEntries, "0x", "1", catalog)
+	if len(got) != 1 || got[0] != "Balancer_V3_LBP" {
+		t.Fatalf("0x Unknown = %q", got)
+	}
+	// Contains entries cover decorated names.
+	if got := Unknown(DefaultEntries, "openocean", "1", []string{"BalancerV3Stable"}); len(got) != 0 {
+		t.Fatalf("openocean Unknown = %q", got)
+	}
+	// Kyber's sources are known whatever the pool kind.
+	if got := Unknown(DefaultEntries, "kyberswap", "1", []string{"balancer-v3-eclp", "balancer-v3-reclamm"}); len(got) != 0 {
+		t.Fatalf("kyberswap Unknown = %q", got)
+	}
+}
+
+func TestRemoved(t *testing.T) {
+	previous := []string{"uniswap-v3", "balancer-v3-stable", "balancer-v3-eclp", "balancer-v2"}
+	current := []string{"balancer-v3-eclp"}
+	if got := Removed(previous, current); len(got) != 1 || got[0] != "balancer-v3-stable" {
+		t.Fatalf("Removed = %q", got)
+	}
+	if got := Removed(nil, current); got != nil {
+		t.Fatalf("Removed from nothing = %q", got)
+	}
+}
+
+func TestAllIDs(t *testing.T) {
+	table := []Entry{
+		{Solver: "kyberswap", PoolKind: KindStable, IDs: []string{"balancer-v3-stable"}},
+		{Solver: "kyberswap", PoolKind: KindGyro, IDs: []string{"balancer-v3-eclp"}},
+		{Solver: "kyberswap", Network: "1", IDs: []string{"balancer-v3-stable", "balancer-v3-mainnet"}},
+		{Solver: "0x", IDs: []string{"Balancer_V3"}},
+	}
+	if got := strings.Join(AllIDs(table, "kyberswap", "1"), ","); got != "balancer-v3-stable,balancer-v3-eclp,balancer-v3-mainnet" {
+		t.Fatalf("AllIDs on 1 = %q", got)
+	}
+	if got := strings.Join(AllIDs(table, "kyberswap", "8453"), ","); got != "balancer-v3-stable,balancer-v3-eclp" {
+		t.Fatalf("AllIDs on 8453 = %q", got)
+	}
+}